@@ -0,0 +1,67 @@
+package cex
+
+import (
+	"sync"
+)
+
+// 没有批量接口的交易所, 并发逐个下单/撤单
+const batchConcurrency = 8
+
+func batchDo(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+func spotPlaceOrdersConcurrently(cex Exchanger, orders []SpotPostOrder) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	batchDo(len(orders), func(i int) {
+		o := &orders[i]
+		results[i].ClientId = o.ClientId
+		results[i].OrderId, results[i].Err = cex.SpotPlaceOrder(o.Symbol, o.ClientId,
+			o.Price, o.Amt, o.Qty, o.Side, o.TimeInForce, o.Type, o.PostOnly)
+	})
+	return results
+}
+func spotCancelOrdersConcurrently(cex Exchanger, orders []CancelOrderArg) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	batchDo(len(orders), func(i int) {
+		o := &orders[i]
+		results[i].OrderId = o.OrderId
+		results[i].ClientId = o.ClientId
+		results[i].Err = cex.SpotCancelOrder(o.Symbol, o.OrderId, o.ClientId)
+	})
+	return results
+}
+func futuresPlaceOrdersConcurrently(cex Exchanger, typ string,
+	orders []FuturesPostOrder) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	batchDo(len(orders), func(i int) {
+		o := &orders[i]
+		results[i].ClientId = o.ClientId
+		results[i].OrderId, results[i].Err = cex.FuturesPlaceOrder(typ, o.Symbol, o.ClientId,
+			o.Price, o.Qty, o.Side, o.Type, o.TimeInForce, o.PositionMode, o.TradeMode, o.ReduceOnly)
+	})
+	return results
+}
+func futuresCancelOrdersConcurrently(cex Exchanger, typ string,
+	orders []CancelOrderArg) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	batchDo(len(orders), func(i int) {
+		o := &orders[i]
+		results[i].OrderId = o.OrderId
+		results[i].ClientId = o.ClientId
+		results[i].Err = cex.FuturesCancelOrder(typ, o.Symbol, o.OrderId, o.ClientId)
+	})
+	return results
+}
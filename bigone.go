@@ -1,6 +1,7 @@
 package cex
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -67,13 +68,15 @@ func NewBigone(account, apikey, secretkey, localIP string) (*Bigone, error) {
 	}
 	cexObj := &Bigone{
 		Http: Http{
-			client: client,
+			client:  client,
+			limiter: getRateLimiter("bigone"),
 		},
-		name:      "bigone",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
-		localIP:   localIP,
+		Unsupported: Unsupported{name: "bigone"},
+		name:        "bigone",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+		localIP:     localIP,
 	}
 	return cexObj, nil
 }
@@ -89,6 +92,19 @@ func (bo *Bigone) ApiKey() string {
 func (bo *Bigone) Debug(v bool) {
 	bo.debug = v
 }
+func (bo *Bigone) withContext(ctx context.Context) Exchanger {
+	cexObj := &Bigone{
+		Http:      bo.Http.withContext(ctx),
+		name:      bo.name,
+		account:   bo.account,
+		apikey:    bo.apikey,
+		secretkey: bo.secretkey,
+		localIP:   bo.localIP,
+		debug:     bo.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (bo *Bigone) Init() error {
 	bo.spotWsPublicClosed = true
 	bo.spotWsPrivateClosed = true
@@ -134,6 +150,9 @@ func (bo *Bigone) getFuturesSymbol(symbol string) string {
 	return boFuturesSymbolMap[symbol]
 }
 
+func (bo *Bigone) apiError(httpStatus, code int, msg string) error {
+	return newApiError(bo.Name(), httpStatus, strconv.Itoa(code), msg)
+}
 func (bo *Bigone) toStdSide(side string) string {
 	if side == "BID" {
 		return "BUY"
@@ -200,3 +219,11 @@ func (bo *Bigone) toStdWithdrawStatus(c string) string {
 	}
 	return ""
 }
+func (bo *Bigone) toStdDepositStatus(c string) string {
+	if c == "CONFIRMED" || c == "COMPLETED" {
+		return "CREDITED"
+	} else if c == "FAILED" || c == "CANCELLED" {
+		return "FAILED"
+	}
+	return "PENDING"
+}
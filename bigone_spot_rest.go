@@ -15,9 +15,9 @@ func (bo *Bigone) SpotSupported() bool {
 }
 func (bo *Bigone) SpotServerTime() (int64, error) {
 	url := boSpotEndpoint + "/ping"
-	_, resp, err := bo.Get(url, boApiDeadline, nil)
+	httpCode, resp, err := bo.Get(url, boApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(bo.Name() + " net error! " + err.Error())
+		return 0, newNetError(bo.Name(), err)
 	}
 
 	recv := struct {
@@ -33,15 +33,15 @@ func (bo *Bigone) SpotServerTime() (int64, error) {
 		return 0, errors.New(bo.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return 0, errors.New(recv.Msg)
+		return 0, bo.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return recv.Data.Timestamp / 1000000, nil
 }
 func (bo *Bigone) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	url := boSpotEndpoint + "/asset_pairs"
-	_, resp, err := bo.Get(url, boApiDeadline, nil)
+	httpCode, resp, err := bo.Get(url, boApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 
 	recv := struct {
@@ -63,7 +63,7 @@ func (bo *Bigone) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 		return nil, errors.New(bo.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bo.Name() + " " + recv.Msg)
+		return nil, bo.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	all := make(map[string]*SpotExchangePairRule)
 	now := time.Now().Unix()
@@ -106,9 +106,9 @@ func (bo *Bigone) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 func (bo *Bigone) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	url := boSpotEndpoint + "/viewer/accounts"
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	type boAsset struct {
 		Symbol  string          `json:"asset_symbol"`
@@ -126,7 +126,7 @@ func (bo *Bigone) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 		return nil, errors.New(bo.Name() + " unmarshal error! " + err.Error())
 	}
 	if assetL.Code != 0 {
-		return nil, errors.New(bo.Name() + " get asset fail! " + assetL.Msg)
+		return nil, bo.apiError(httpCode, assetL.Code, assetL.Msg)
 	}
 
 	assetsMap := make(map[string]*SpotAsset)
@@ -146,9 +146,9 @@ func (bo *Bigone) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 func (bo *Bigone) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	symbolS := bo.getSpotSymbol(symbol)
 	url := boSpotEndpoint + "/asset_pairs/" + symbolS + "/ticker"
-	_, resp, err := bo.Get(url, boApiDeadline, nil)
+	httpCode, resp, err := bo.Get(url, boApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(bo.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(bo.Name(), err)
 	}
 
 	recv := struct {
@@ -171,7 +171,7 @@ func (bo *Bigone) SpotGetBBO(symbol string) (BestBidAsk, error) {
 		return BestBidAsk{}, errors.New(bo.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return BestBidAsk{}, errors.New(recv.Msg)
+		return BestBidAsk{}, bo.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return BestBidAsk{
 		Symbol:   symbol,
@@ -218,9 +218,9 @@ func (bo *Bigone) SpotPlaceOrderMultiple(orders []SpotPostOrder) error {
 		"Content-Type":  "application/json",
 		"Authorization": jwt,
 	}
-	_, resp, err := bo.Post(url, payload, boApiDeadline, header)
+	httpCode, resp, err := bo.Post(url, payload, boApiDeadline, header)
 	if err != nil {
-		return errors.New(bo.Name() + " net error! " + err.Error())
+		return newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -238,7 +238,7 @@ func (bo *Bigone) SpotPlaceOrderMultiple(orders []SpotPostOrder) error {
 		return errors.New(bo.Name() + " unmarshal fail! " + err.Error() + ", " + string(resp))
 	}
 	if ret.Code != 0 {
-		return errors.New(bo.Name() + " fail! msg=" + ret.Msg)
+		return bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return nil
@@ -272,9 +272,9 @@ func (bo *Bigone) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 		"Content-Type":  "application/json",
 		"Authorization": jwt,
 	}
-	_, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
+	httpCode, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
 	if err != nil {
-		return "", errors.New(bo.Name() + " net error! " + err.Error())
+		return "", newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -289,7 +289,7 @@ func (bo *Bigone) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 		return "", errors.New(bo.Name() + " unmarshal fail! " + err.Error() + ", " + string(resp))
 	}
 	if ret.Code != 0 {
-		return "", errors.New(bo.Name() + " fail! msg=" + ret.Msg)
+		return "", bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	if ret.Data.OrderId == 0 {
@@ -297,6 +297,12 @@ func (bo *Bigone) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 	}
 	return strconv.FormatInt(ret.Data.OrderId, 10), nil
 }
+func (bo *Bigone) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(bo, orders), nil
+}
+func (bo *Bigone) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(bo, orders), nil
+}
 func (bo *Bigone) SpotCancelOrder(symbol, orderId, cltId string) error {
 	url := boSpotEndpoint + "/viewer/order/cancel"
 	payload := `{"order_id":` + orderId + `}`
@@ -311,7 +317,7 @@ func (bo *Bigone) SpotCancelOrder(symbol, orderId, cltId string) error {
 	}
 	respCode, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
 	if err != nil {
-		return errors.New(bo.Name() + " net error! " + err.Error())
+		return newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -325,7 +331,7 @@ func (bo *Bigone) SpotCancelOrder(symbol, orderId, cltId string) error {
 		return errors.New(bo.Name() + " cancel order unmarshal fail! " + err.Error() + strconv.FormatInt(int64(respCode), 10))
 	}
 	if ret.Code != 0 {
-		return errors.New(bo.Name() + " cancel order fail! " + ret.Msg)
+		return bo.apiError(respCode, ret.Code, ret.Msg)
 	}
 	st := bo.toStdOrderStatus(ret.Data.Status)
 	if st != "CANCELED" {
@@ -339,9 +345,9 @@ func (bo *Bigone) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 		url = boSpotEndpoint + "/viewer/order?client_order_id=" + cltId
 	}
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -366,7 +372,7 @@ func (bo *Bigone) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " get order fail! " + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	ctime, _ := time.Parse(time.RFC3339, ret.Data.Time)
@@ -394,9 +400,9 @@ func (bo *Bigone) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	symbolS := bo.getSpotSymbol(symbol)
 	url := boSpotEndpoint + "/viewer/orders?limit=200&state=PENDING&asset_pair_name=" + symbolS
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -421,7 +427,7 @@ func (bo *Bigone) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " openorder fail! " + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	dl := make([]*SpotOrder, 0, len(ret.L))
@@ -450,9 +456,9 @@ func (bo *Bigone) SpotGetFilledOrders(symbol string) ([]*SpotOrder, error) {
 	symbolS := bo.getSpotSymbol(symbol)
 	url := boSpotEndpoint + "/viewer/orders?limit=200&state=FILLED&asset_pair_name=" + symbolS
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -477,7 +483,7 @@ func (bo *Bigone) SpotGetFilledOrders(symbol string) ([]*SpotOrder, error) {
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " openorder fail! " + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	dl := make([]*SpotOrder, 0, len(ret.L))
@@ -507,9 +513,9 @@ func (bo *Bigone) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 	var f SpotTradeFee
 	url := boSpotEndpoint + "/viewer/trading_fees?asset_pair_names=" + symbolS
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return f, errors.New(bo.Name() + " error! " + err.Error())
+		return f, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -525,7 +531,7 @@ func (bo *Bigone) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 		return f, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return f, errors.New(bo.Name() + " get trade fee fail! " + ret.Msg)
+		return f, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	for i := range ret.L {
@@ -539,3 +545,57 @@ func (bo *Bigone) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 	}
 	return f, errors.New("not found")
 }
+
+// bigone 只支持 截止时间+条数 查询, 一次最多500根, 不提供成交额
+func (bo *Bigone) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	periods := map[string]string{"1m": "min1", "5m": "min5", "15m": "min15", "30m": "min30",
+		"1h": "hour1", "4h": "hour4", "6h": "hour6", "12h": "hour12", "1d": "day1"}
+	period, ok := periods[interval]
+	if !ok {
+		return nil, errors.New(bo.Name() + " not support interval " + interval)
+	}
+	url := boSpotEndpoint + "/asset_pairs/" + bo.getSpotSymbol(symbol) + "/candles?period=" + period +
+		"&limit=" + strconv.FormatInt(limit, 10)
+	if end := klineEndTime(interval, startTime, endTime, limit); end > 0 {
+		url += "&time=" + time.Unix(end, 0).UTC().Format(time.RFC3339)
+	}
+	httpCode, resp, err := bo.Get(url, boApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bo.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"message,omitempty"`
+		Data []struct {
+			Open   decimal.Decimal `json:"open"`
+			High   decimal.Decimal `json:"high"`
+			Low    decimal.Decimal `json:"low"`
+			Close  decimal.Decimal `json:"close"`
+			Volume decimal.Decimal `json:"volume"`
+			Time   time.Time       `json:"time"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bo.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, bo.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	all := make([]KLine, 0, len(recv.Data))
+	for i := len(recv.Data) - 1; i >= 0; i-- { // bigone 是倒序
+		v := &recv.Data[i]
+		if v.Time.Unix() < startTime {
+			continue
+		}
+		all = append(all, KLine{
+			OpenTime:   v.Time.Unix(),
+			OpenPrice:  v.Open,
+			HighPrice:  v.High,
+			LowPrice:   v.Low,
+			ClosePrice: v.Close,
+			Volume:     v.Volume,
+		})
+	}
+	return all, nil
+}
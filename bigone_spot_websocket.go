@@ -51,7 +51,7 @@ func init() {
 	}
 }
 func (bo *Bigone) SpotWsPublicOpen() error {
-	url := "wss://big.one/ws/v2"
+	url := bo.wsUrl("wss://big.one/ws/v2")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -237,14 +237,14 @@ func (bo *Bigone) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := bo.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !bo.SpotWsPublicIsClosed() {
-				ilog.Warning(bo.Name() + " spot.ws.public channel read: " + err.Error())
+				ilog.Warning("%s", bo.Name()+" spot.ws.public channel read: "+err.Error())
 			}
 			break
 		}
 		msg := boSpotWsPubMsgPool.Get().(*BigoneSpotWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bo.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", bo.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
 
@@ -267,7 +267,7 @@ func (bo *Bigone) SpotWsPublicLoop(ch chan<- any) {
 		} else if bytes.Contains(recv, []byte(`"heartbeat":`)) {
 		} else if bytes.Contains(recv, []byte(`"success":`)) {
 		} else {
-			ilog.Error(bo.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+			ilog.Error("%s", bo.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 		}
 	END:
 		boSpotWsPubMsgPool.Put(msg)
@@ -326,7 +326,7 @@ func (bo *Bigone) spotWsHandleOrderBookUpdate(data json.RawMessage) (string, boo
 		base, quote, _ := strings.Cut(depth.Depth.Symbol, "-")
 		symbol := base + quote
 		if depth.PrevId != bo.spotWsOrderBookSeqId[symbol] {
-			ilog.Error(bo.Name() + " spot.ws.public orderbook seq error!")
+			ilog.Error("%s", bo.Name()+" spot.ws.public orderbook seq error!")
 			return "", false
 		}
 		bo.spotWsOrderBookSeqId[symbol] = depth.ChangeId
@@ -493,7 +493,7 @@ func (bo *Bigone) SpotWsPrivateSupported() bool {
 	return true
 }
 func (bo *Bigone) SpotWsPrivateOpen() error {
-	url := "wss://big.one/ws/v2"
+	url := bo.wsUrl("wss://big.one/ws/v2")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -607,17 +607,17 @@ func (bo *Bigone) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := bo.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !bo.SpotWsPrivateIsClosed() {
-				ilog.Warning(bo.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", bo.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if bo.debug {
-			ilog.Rinfo(bo.Name() + " spot priv ws: " + string(recv))
+			ilog.Rinfo("%s", bo.Name()+" spot priv ws: "+string(recv))
 		}
 		msg := boSpotWsPrivMsgPool.Get().(*BigoneSpotWsPrivMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bo.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", bo.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.OrderUpdate != nil {
@@ -629,7 +629,7 @@ func (bo *Bigone) SpotWsPrivateLoop(ch chan<- any) {
 		} else if bytes.Contains(recv, []byte(`"heartbeat":`)) {
 		} else if bytes.Contains(recv, []byte(`"success":`)) {
 		} else {
-			ilog.Error(bo.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+			ilog.Error("%s", bo.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 		}
 	END:
 		boSpotWsPrivMsgPool.Put(msg)
@@ -41,9 +41,9 @@ func (bo *Bigone) Transfer(symbol, from, to, typ, subAccount string, qty decimal
 		"Content-Type":  "application/json",
 		"Authorization": jwt,
 	}
-	_, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
+	httpCode, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
 	if err != nil {
-		return errors.New(bo.Name() + " net error! " + err.Error())
+		return newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -54,7 +54,7 @@ func (bo *Bigone) Transfer(symbol, from, to, typ, subAccount string, qty decimal
 		return errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(bo.Name() + " transfer fail! msg=" + ret.Msg)
+		return bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return nil
 }
@@ -76,9 +76,9 @@ func (bo *Bigone) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 		"Content-Type":  "application/json",
 		"Authorization": jwt,
 	}
-	_, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
+	httpCode, resp, err := bo.Post(url, []byte(payload), boApiDeadline, header)
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -93,7 +93,7 @@ func (bo *Bigone) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " withdrawals fail! msg=" + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	wr := &WithdrawReturn{
 		WId:    strconv.FormatInt(ret.Data.Id, 10),
@@ -102,16 +102,16 @@ func (bo *Bigone) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 	}
 	return wr, nil
 }
-func (bo *Bigone) CancelWithdrawal(wid string) error {
+func (bo *Bigone) CancelWithdrawal(symbol, wid string) error {
 	url := boSpotEndpoint + "/viewer/withdrawals/" + wid + "/cancel"
 	jwt := "Bearer " + bo.jwt()
 	header := map[string]string{
 		"Content-Type":  "application/json",
 		"Authorization": jwt,
 	}
-	_, resp, err := bo.Post(url, nil, boApiDeadline, header)
+	httpCode, resp, err := bo.Post(url, nil, boApiDeadline, header)
 	if err != nil {
-		return errors.New(bo.Name() + " net error! " + err.Error())
+		return newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -122,7 +122,7 @@ func (bo *Bigone) CancelWithdrawal(wid string) error {
 		return errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(bo.Name() + " cancel withdrawals fail! msg=" + ret.Msg)
+		return bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return nil
@@ -135,7 +135,7 @@ func (bo *Bigone) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
 	jwt := "Bearer " + bo.jwt()
 	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -175,12 +175,72 @@ func (bo *Bigone) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
 	}
 	return res, nil
 }
+
+// bigone 不支持按时间查询, 返回最近的记录后按startTime,endTime过滤
+func (bo *Bigone) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	if symbol == "XAUT" {
+		symbol = "XAUt"
+	}
+	url := boSpotEndpoint + "/viewer/deposits?limit=50"
+	if symbol != "" {
+		url += "&asset_symbol=" + symbol
+	}
+	jwt := "Bearer " + bo.jwt()
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	if err != nil {
+		return nil, newNetError(bo.Name(), err)
+	}
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"message,omitempty"`
+		Data []struct {
+			Id       int64           `json:"id"`
+			Symbol   string          `json:"asset_symbol"`
+			Qty      decimal.Decimal `json:"amount"`
+			Network  string          `json:"gateway_name"`
+			Addr     string          `json:"address"`
+			Memo     string          `json:"memo"`
+			Txid     string          `json:"txid"`
+			Confirms int64           `json:"confirms"`
+			Status   string          `json:"state"`
+			CTime    string          `json:"inserted_at"`
+		} `json:"data"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	res := make([]DepositResult, 0, len(ret.Data))
+	for i := range ret.Data {
+		ctime, _ := time.Parse(time.RFC3339, ret.Data[i].CTime)
+		if (startTime > 0 && ctime.Unix() < startTime) || (endTime > 0 && ctime.Unix() > endTime) {
+			continue
+		}
+		a := DepositResult{
+			Id:            strconv.FormatInt(ret.Data[i].Id, 10),
+			Symbol:        ret.Data[i].Symbol,
+			Network:       ret.Data[i].Network,
+			Addr:          ret.Data[i].Addr,
+			Memo:          ret.Data[i].Memo,
+			Txid:          ret.Data[i].Txid,
+			Qty:           ret.Data[i].Qty,
+			Confirmations: ret.Data[i].Confirms,
+			Status:        bo.toStdDepositStatus(ret.Data[i].Status),
+			Time:          ctime.Unix(),
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
 func (bo *Bigone) FundingGetAllAssets() (map[string]*FundingAsset, error) {
 	url := boSpotEndpoint + "/viewer/fund/accounts"
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -196,7 +256,7 @@ func (bo *Bigone) FundingGetAllAssets() (map[string]*FundingAsset, error) {
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " resp fail! msg=" + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	assetsMap := make(map[string]*FundingAsset)
 	for _, v := range ret.Data {
@@ -216,9 +276,9 @@ func (bo *Bigone) FundingGetAsset(symbol string) (FundingAsset, error) {
 	url := boSpotEndpoint + "/viewer/fund/accounts/" + symbol
 	jwt := "Bearer " + bo.jwt()
 	var fa FundingAsset
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return fa, errors.New(bo.Name() + " net error! " + err.Error())
+		return fa, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -234,7 +294,7 @@ func (bo *Bigone) FundingGetAsset(symbol string) (FundingAsset, error) {
 		return fa, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return fa, errors.New(bo.Name() + " get funding asset fail! msg=" + ret.Msg)
+		return fa, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return FundingAsset{
 		Symbol: ret.Data.Symbol,
@@ -246,9 +306,9 @@ func (bo *Bigone) FundingGetAsset(symbol string) (FundingAsset, error) {
 func (bo *Bigone) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
 	url := boSpotEndpoint + "/viewer/assets/" + symbol + "/address"
 	jwt := "Bearer " + bo.jwt()
-	_, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
+	httpCode, resp, err := bo.Get(url, boApiDeadline, map[string]string{"Authorization": jwt})
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -264,7 +324,7 @@ func (bo *Bigone) GetDepositAddress(symbol, network string) ([]DepositAddress, e
 		return nil, errors.New(bo.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bo.Name() + " get deposit addr fail! msg=" + ret.Msg)
+		return nil, bo.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	daL := make([]DepositAddress, 0, len(ret.Data))
 	for i := range ret.Data {
@@ -281,9 +341,9 @@ func (bo *Bigone) GetDepositAddress(symbol, network string) ([]DepositAddress, e
 }
 func (bo *Bigone) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
 	url := boSpotEndpoint + "/assets"
-	_, resp, err := bo.Get(url, boApiDeadline, nil)
+	httpCode, resp, err := bo.Get(url, boApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bo.Name() + " net error! " + err.Error())
+		return nil, newNetError(bo.Name(), err)
 	}
 
 	recv := struct {
@@ -310,7 +370,7 @@ func (bo *Bigone) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
 		return nil, errors.New(bo.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(recv.Msg)
+		return nil, bo.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	waiMap := make(map[string]*WalletAssetInfo)
 	for _, v := range recv.Data {
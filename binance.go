@@ -2,11 +2,11 @@ package cex
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"sort"
 	"strconv"
 	"sync"
@@ -32,6 +32,7 @@ type Binance struct {
 	spotWsPublicConnMtx   sync.Mutex
 	spotWsPublicClosed    bool
 	spotWsPublicClosedMtx sync.RWMutex
+	spotWsOrderBooks      localOrderBooks
 
 	spotWsPrivateConn      *websocket.Conn
 	spotWsPrivateConnMtx   sync.Mutex
@@ -99,13 +100,15 @@ func NewBinance(account, apikey, secretkey, localIP string) (*Binance, error) {
 	}
 	cexObj := &Binance{
 		Http: Http{
-			client: client,
+			client:  client,
+			limiter: getRateLimiter("binance"),
 		},
-		name:      "binance",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
-		localIP:   localIP,
+		Unsupported: Unsupported{name: "binance"},
+		name:        "binance",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+		localIP:     localIP,
 	}
 	return cexObj, nil
 }
@@ -121,6 +124,20 @@ func (bn *Binance) ApiKey() string {
 func (bn *Binance) Debug(v bool) {
 	bn.debug = v
 }
+func (bn *Binance) withContext(ctx context.Context) Exchanger {
+	cexObj := &Binance{
+		Http:      bn.Http.withContext(ctx),
+		name:      bn.name,
+		account:   bn.account,
+		apikey:    bn.apikey,
+		secretkey: bn.secretkey,
+		localIP:   bn.localIP,
+		isUnified: bn.isUnified,
+	}
+	cexObj.Init()
+	cexObj.debug = bn.debug
+	return cexObj
+}
 func (bn *Binance) Init() error {
 	bn.spotWsPublicClosed = true
 	bn.spotWsPrivateClosed = true
@@ -167,7 +184,7 @@ func (bn *Binance) wsSign(kv map[string]any) string {
 		case int64:
 			valStr = strconv.FormatInt(v, 10)
 		default:
-			ilog.Error(bn.Name() + " wsSign unsupported type")
+			ilog.Error("%s", bn.Name()+" wsSign unsupported type")
 		}
 		if i > 0 {
 			buf.WriteByte('&')
@@ -178,15 +195,19 @@ func (bn *Binance) wsSign(kv map[string]any) string {
 	}
 	return bn.sign(buf.String())
 }
-func (bn *Binance) handleExceptionResp(api string, resp []byte) error {
+func (bn *Binance) handleExceptionResp(httpStatus int, api string, resp []byte) error {
 	ret := struct {
 		Code int    `json:"code,omitempty"`
 		Msg  string `json:"msg,omitempty"`
 	}{}
 	if err := json.Unmarshal(resp, &ret); err != nil {
-		return errors.New(bn.Name() + " " + api + " " + err.Error() + " " + string(resp))
+		// 非json, 如WAF/网关返回的html页面, 按http状态码分类
+		return newApiError(bn.Name(), httpStatus, "", api+" "+string(resp))
 	}
-	return errors.New(ret.Msg)
+	return bn.apiError(httpStatus, ret.Code, ret.Msg)
+}
+func (bn *Binance) apiError(httpStatus, code int, msg string) error {
+	return newApiError(bn.Name(), httpStatus, strconv.Itoa(code), msg)
 }
 func (bn *Binance) toStdSide(side string) string {
 	if side == "Buy" || side == "BUY" {
@@ -226,3 +247,13 @@ func (bn *Binance) toStdWithdrawStatus(v int) string {
 	}
 	return ""
 }
+
+// 0:pending 8:等待用户确认 1:成功 6:已入账不可提现 2:拒绝 7:错误入账
+func (bn *Binance) toStdDepositStatus(v int) string {
+	if v == 1 || v == 6 {
+		return "CREDITED"
+	} else if v == 2 || v == 7 {
+		return "FAILED"
+	}
+	return "PENDING"
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -24,7 +25,7 @@ func (bn *Binance) FuturesServerTime(typ string) (int64, error) {
 	}
 	_, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(bn.Name() + " net error! " + err.Error())
+		return 0, newNetError(bn.Name(), err)
 	}
 	recv := struct {
 		Time int64 `json:"serverTime,omitempty"`
@@ -45,9 +46,9 @@ func (bn *Binance) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchan
 	if typ == "CM" {
 		url = bnCMFuturesEndpoint + "/dapi/v1/exchangeInfo"
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " " + typ + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	recv := struct {
@@ -78,7 +79,7 @@ func (bn *Binance) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchan
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bn.Name() + " api err! " + recv.Msg)
+		return nil, bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	all := make(map[string]*FuturesExchangePairRule)
 	now := time.Now().Unix()
@@ -121,12 +122,12 @@ func (bn *Binance) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker,
 	if typ == "CM" {
 		url = bnCMFuturesEndpoint + "/dapi/v1/ticker/24hr"
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesGetAll24hTicker", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetAll24hTicker", resp)
 	}
 
 	tks := []struct {
@@ -161,7 +162,7 @@ func (bn *Binance) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
 		url := bnUMFuturesEndpoint + "/fapi/v1/ticker/bookTicker?symbol=" + symbol
 		_, resp, err := bn.Get(url, bnApiDeadline, nil)
 		if err != nil {
-			return BestBidAsk{}, errors.New(bn.Name() + " net error! " + err.Error())
+			return BestBidAsk{}, newNetError(bn.Name(), err)
 		}
 		bbo := struct {
 			Symbol   string          `json:"symbol,omitempty"`
@@ -188,7 +189,7 @@ func (bn *Binance) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
 		url := bnCMFuturesEndpoint + "/dapi/v1/ticker/bookTicker?symbol=" + symbol
 		_, resp, err := bn.Get(url, bnApiDeadline, nil)
 		if err != nil {
-			return BestBidAsk{}, errors.New(bn.Name() + " net error! " + err.Error())
+			return BestBidAsk{}, newNetError(bn.Name(), err)
 		}
 		bboL := []struct {
 			Symbol   string          `json:"symbol,omitempty"`
@@ -212,19 +213,19 @@ func (bn *Binance) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
 			AskQty:   bbo.AskQty,
 		}, nil
 	}
-	return BestBidAsk{}, errors.New("not support")
+	return BestBidAsk{}, newNotSupportError(bn.Name())
 }
 func (bn *Binance) FuturesGetAllFundingRate(typ string) (map[string]FundingRate, error) {
 	url := bnUMFuturesEndpoint + "/fapi/v1/premiumIndex"
 	if typ == "CM" {
 		url = bnCMFuturesEndpoint + "/dapi/v1/premiumIndex"
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesGetAllFundingRate", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetAllFundingRate", resp)
 	}
 
 	frs := []struct {
@@ -264,12 +265,12 @@ func (bn *Binance) FuturesGetFundingRateHistory(typ, symbol string,
 	params := fmt.Sprintf("&symbol=%s&startTime=%d&endTime=%d&limit=1000",
 		symbol, startTime, endTime)
 	url = url + "?" + params
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesGetFundingRateHistory", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetFundingRateHistory", resp)
 	}
 	ret := []struct {
 		FundingRate decimal.Decimal `json:"fundingRate"`
@@ -294,7 +295,7 @@ func (bn *Binance) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRa
 		url := bnUMFuturesEndpoint + "/fapi/v1/premiumIndex?symbol=" + symbol
 		_, resp, err := bn.Get(url, bnApiDeadline, nil)
 		if err != nil {
-			return FundingRateMarkPrice{}, errors.New(bn.Name() + " net error! " + err.Error())
+			return FundingRateMarkPrice{}, newNetError(bn.Name(), err)
 		}
 
 		fr := struct {
@@ -316,12 +317,12 @@ func (bn *Binance) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRa
 			symbol += "_PERP"
 		}
 		url := bnCMFuturesEndpoint + "/dapi/v1/premiumIndex?symbol=" + symbol
-		_, resp, err := bn.Get(url, bnApiDeadline, nil)
+		httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 		if err != nil {
-			return FundingRateMarkPrice{}, errors.New(bn.Name() + " net error! " + err.Error())
+			return FundingRateMarkPrice{}, newNetError(bn.Name(), err)
 		}
 		if resp[0] != '[' {
-			return FundingRateMarkPrice{}, bn.handleExceptionResp("FuturesGetFundingRateMarkPrice", resp)
+			return FundingRateMarkPrice{}, bn.handleExceptionResp(httpCode, "FuturesGetFundingRateMarkPrice", resp)
 		}
 
 		frs := []struct {
@@ -342,7 +343,7 @@ func (bn *Binance) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRa
 			NextTime:    frs[0].NextTime,
 		}, nil
 	}
-	return FundingRateMarkPrice{}, errors.New("not support")
+	return FundingRateMarkPrice{}, newNotSupportError(bn.Name())
 }
 func (bn *Binance) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
 	url := bnUMFuturesEndpoint + "/fapi/v3/balance"
@@ -352,7 +353,7 @@ func (bn *Binance) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, er
 	url += "?" + bn.httpQuerySign("")
 	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	alls := []struct {
 		Symbol              string          `json:"asset,omitempty"`
@@ -393,12 +394,12 @@ func (bn *Binance) FuturesGetKLine(typ, symbol, interval string,
 	if typ == "CM" {
 		url = bnCMFuturesEndpoint + "/dapi/v1/klines?" + params
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesGetKLine", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetKLine", resp)
 	}
 
 	var klines [][]any
@@ -458,9 +459,9 @@ func (bn *Binance) FuturesPlaceOrder(typ, symbol, clientId string, /*BTCUSDT*/
 			link = bnUnifiedEndpoint + "/papi/v1/cm/order?" + bn.httpQuerySign(query)
 		}
 	}
-	_, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return "", errors.New(bn.Name() + " net error! " + err.Error())
+		return "", newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code    int    `json:"code"`
@@ -471,7 +472,7 @@ func (bn *Binance) FuturesPlaceOrder(typ, symbol, clientId string, /*BTCUSDT*/
 		return "", errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return "", errors.New(bn.Name() + " futures place order fail! " + ret.Msg)
+		return "", bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return strconv.FormatInt(ret.OrderId, 10), nil
 }
@@ -495,9 +496,9 @@ func (bn *Binance) FuturesGetOrder(typ, symbol, orderId, cltId string) (*Futures
 		params = fmt.Sprintf("&symbol=%s&origClientOrderId=%s", symbol, cltId)
 	}
 	url += "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	order := struct {
@@ -523,7 +524,7 @@ func (bn *Binance) FuturesGetOrder(typ, symbol, orderId, cltId string) (*Futures
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if order.Code != 0 {
-		return nil, errors.New(order.Msg)
+		return nil, bn.apiError(httpCode, order.Code, order.Msg)
 	}
 	fo := &FuturesOrder{
 		Symbol:    order.Symbol,
@@ -564,12 +565,12 @@ func (bn *Binance) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, er
 		params = ""
 	}
 	url += "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] == '{' {
-		return nil, bn.handleExceptionResp("FuturesGetOpenOrders", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetOpenOrders", resp)
 	}
 
 	orders := []struct {
@@ -634,9 +635,9 @@ func (bn *Binance) FuturesCancelOrder(typ, symbol, orderId, cltId string) error
 		params = "&symbol=" + symbol + "&origClientOrderId=" + cltId
 	}
 	url = url + "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Delete(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"code,omitempty"`
@@ -647,13 +648,541 @@ func (bn *Binance) FuturesCancelOrder(typ, symbol, orderId, cltId string) error
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	if ret.Status == "CANCELED" {
 		return nil
 	}
 	return errors.New("cancel fail " + ret.Status)
 }
+func (bn *Binance) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(bn.Name() + " symbol empty!")
+	}
+	url := bnUMFuturesEndpoint + "/fapi/v1/allOpenOrders"
+	if typ == "CM" {
+		url = bnCMFuturesEndpoint + "/dapi/v1/allOpenOrders"
+	}
+	if bn.isUnified {
+		url = bnUnifiedEndpoint + "/papi/v1/um/allOpenOrders"
+		if typ == "CM" {
+			url = bnUnifiedEndpoint + "/papi/v1/cm/allOpenOrders"
+		}
+	}
+	if typ == "CM" && strings.Index(symbol, "_") == -1 {
+		symbol += "_PERP"
+	}
+	url += "?" + bn.httpQuerySign("&symbol="+symbol)
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 200 && ret.Code != 0 { // 成功返回 {"code":200,"msg":"The operation of cancel all open order is done."}
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
+func (bn *Binance) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	if (typ != "UM" && typ != "CM") || bn.isUnified {
+		return newNotSupportError(bn.Name())
+	}
+	if symbol == "" {
+		return errors.New(bn.Name() + " symbol empty!")
+	}
+	if typ == "CM" && strings.Index(symbol, "_") == -1 {
+		symbol += "_PERP"
+	}
+	params := fmt.Sprintf("&symbol=%s&countdownTime=%d", symbol, timeout*1000)
+	url := bnUMFuturesEndpoint + "/fapi/v1/countdownCancelAll?" + bn.httpQuerySign(params)
+	if typ == "CM" {
+		url = bnCMFuturesEndpoint + "/dapi/v1/countdownCancelAll?" + bn.httpQuerySign(params)
+	}
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
+func (bn *Binance) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	if bn.isUnified { // 统一账户没有批量下单接口
+		return futuresPlaceOrdersConcurrently(bn, typ, orders), nil
+	}
+	type PostOrder struct {
+		Symbol       string `json:"symbol"`
+		Side         string `json:"side"`
+		Type         string `json:"type"`
+		Qty          string `json:"quantity"`
+		Price        string `json:"price,omitempty"`
+		TimeInForce  string `json:"timeInForce,omitempty"`
+		ClientId     string `json:"newClientOrderId,omitempty"`
+		PositionSide string `json:"positionSide,omitempty"`
+		ReduceOnly   string `json:"reduceOnly,omitempty"`
+	}
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 5 { // 每批最多5个
+		end := min(start+5, len(orders))
+		poL := make([]PostOrder, 0, end-start)
+		for i := start; i < end; i++ {
+			o := &orders[i]
+			results[i].ClientId = o.ClientId
+			symbol := o.Symbol
+			if typ == "CM" && strings.Index(symbol, "_") == -1 {
+				symbol += "_PERP"
+			}
+			po := PostOrder{
+				Symbol:       symbol,
+				Side:         o.Side,
+				Type:         o.Type,
+				Qty:          o.Qty.String(),
+				ClientId:     o.ClientId,
+				PositionSide: o.PositionMode,
+			}
+			if o.Type == "LIMIT" {
+				po.Price = o.Price.String()
+				po.TimeInForce = o.TimeInForce
+			}
+			if o.ReduceOnly == 1 {
+				po.ReduceOnly = "true"
+			}
+			poL = append(poL, po)
+		}
+		batch, _ := json.Marshal(poL)
+		query := "&batchOrders=" + url.QueryEscape(string(batch))
+		link := bnUMFuturesEndpoint + "/fapi/v1/batchOrders?" + bn.httpQuerySign(query)
+		if typ == "CM" {
+			link = bnCMFuturesEndpoint + "/dapi/v1/batchOrders?" + bn.httpQuerySign(query)
+		}
+		retCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+		bn.futuresHandleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (bn *Binance) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	if bn.isUnified { // 统一账户没有批量撤单接口
+		return futuresCancelOrdersConcurrently(bn, typ, orders), nil
+	}
+	// 批量撤单只能是同一个symbol, 且orderId/cltId只能用一种
+	groupKeys := make([]string, 0, 2)
+	groups := make(map[string][]int)
+	for i := range orders {
+		key := orders[i].Symbol + "|o"
+		if orders[i].OrderId == "" {
+			key = orders[i].Symbol + "|c"
+		}
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	results := make([]BatchOrderResult, len(orders))
+	for _, key := range groupKeys {
+		idxL := groups[key]
+		for start := 0; start < len(idxL); start += 10 { // 每批最多10个
+			end := min(start+10, len(idxL))
+			symbol := orders[idxL[start]].Symbol
+			if typ == "CM" && strings.Index(symbol, "_") == -1 {
+				symbol += "_PERP"
+			}
+			ids := make([]string, 0, end-start)
+			for _, i := range idxL[start:end] {
+				results[i].OrderId = orders[i].OrderId
+				results[i].ClientId = orders[i].ClientId
+				if strings.HasSuffix(key, "|o") {
+					ids = append(ids, orders[i].OrderId)
+				} else {
+					ids = append(ids, `"`+orders[i].ClientId+`"`)
+				}
+			}
+			query := "&symbol=" + symbol
+			if strings.HasSuffix(key, "|o") {
+				query += "&orderIdList=" + url.QueryEscape("["+strings.Join(ids, ",")+"]")
+			} else {
+				query += "&origClientOrderIdList=" + url.QueryEscape("["+strings.Join(ids, ",")+"]")
+			}
+			link := bnUMFuturesEndpoint + "/fapi/v1/batchOrders?" + bn.httpQuerySign(query)
+			if typ == "CM" {
+				link = bnCMFuturesEndpoint + "/dapi/v1/batchOrders?" + bn.httpQuerySign(query)
+			}
+			retCode, resp, err := bn.Delete(link, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+			chunk := make([]BatchOrderResult, end-start)
+			bn.futuresHandleBatchResp(chunk, retCode, resp, err)
+			for j, i := range idxL[start:end] {
+				results[i].Err = chunk[j].Err
+			}
+		}
+	}
+	return results, nil
+}
+
+// 批量接口的返回与请求顺序一致, 失败的为{code,msg}
+func (bn *Binance) futuresHandleBatchResp(results []BatchOrderResult, retCode int, resp []byte, err error) {
+	if err != nil {
+		err = newNetError(bn.Name(), err)
+	} else if len(resp) > 0 && resp[0] == '{' {
+		err = bn.handleExceptionResp(retCode, "batchOrders", resp)
+	}
+	ret := []struct {
+		Code     int    `json:"code,omitempty"`
+		Msg      string `json:"msg,omitempty"`
+		OrderId  int64  `json:"orderId,omitempty"`
+		ClientId string `json:"clientOrderId,omitempty"`
+	}{}
+	if err == nil {
+		if e := json.Unmarshal(resp, &ret); e != nil {
+			err = errors.New(bn.Name() + " unmarshal fail! " + e.Error())
+		} else if len(ret) != len(results) {
+			err = errors.New(bn.Name() + " batch resp size not match! " + string(resp))
+		}
+	}
+	for i := range results {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if ret[i].Code != 0 {
+			results[i].Err = bn.apiError(retCode, ret[i].Code, ret[i].Msg)
+			continue
+		}
+		results[i].OrderId = strconv.FormatInt(ret[i].OrderId, 10)
+		results[i].ClientId = ret[i].ClientId
+	}
+}
+func (bn *Binance) FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*FuturesOrder, error) {
+	url := bnUMFuturesEndpoint + "/fapi/v1/order"
+	if typ == "CM" {
+		url = bnCMFuturesEndpoint + "/dapi/v1/order"
+	}
+	if bn.isUnified {
+		url = bnUnifiedEndpoint + "/papi/v1/um/order"
+		if typ == "CM" {
+			url = bnUnifiedEndpoint + "/papi/v1/cm/order"
+		}
+	}
+	if typ == "CM" && strings.Index(symbol, "_") == -1 {
+		symbol += "_PERP"
+	}
+	params := fmt.Sprintf("&symbol=%s&side=%s&quantity=%s&price=%s",
+		symbol, side, qty.String(), price.String())
+	if orderId != "" {
+		params += "&orderId=" + orderId
+	} else if cltId != "" {
+		params += "&origClientOrderId=" + cltId
+	} else {
+		return nil, errors.New(bn.Name() + " orderId or cltId empty!")
+	}
+	url += "?" + bn.httpQuerySign(params)
+	httpCode, resp, err := bn.Put(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+
+	order := struct {
+		Code         int             `json:"code,omitempty"`
+		Msg          string          `json:"msg,omitempty"`
+		Symbol       string          `json:"symbol,omitempty"` // BTCUSDT
+		OrderId      int64           `json:"orderId,omitempty"`
+		ClientId     string          `json:"clientOrderId,omitempty"` // BTCUSDT
+		Price        decimal.Decimal `json:"price"`
+		Quantity     decimal.Decimal `json:"origQty"`     // 用户设置的原始订单数量
+		ExecutedQty  decimal.Decimal `json:"executedQty"` // 交易的订单数量
+		CummQuoteQty decimal.Decimal `json:"cumQuote"`    // 累计交易的金额 for UM
+		CummBaseQty  decimal.Decimal `json:"cumBase"`     // 累计交易的金额(标地数量) for CM
+		AvgPrice     decimal.Decimal `json:"avgPrice"`    // for CM
+		Status       string          `json:"status,omitempty"`
+		Type         string          `json:"type,omitempty"` // LIMIT/MARKET
+		Side         string          `json:"side,omitempty"`
+		UTime        int64           `json:"updateTime,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &order); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if order.Code != 0 {
+		return nil, bn.apiError(httpCode, order.Code, order.Msg)
+	}
+	fo := &FuturesOrder{
+		Symbol:    order.Symbol,
+		OrderId:   strconv.FormatInt(order.OrderId, 10),
+		ClientId:  order.ClientId,
+		Price:     order.Price,
+		Qty:       order.Quantity,
+		FilledQty: order.ExecutedQty,
+		FilledAmt: order.CummQuoteQty,
+		Status:    order.Status,
+		Type:      order.Type,
+		Side:      order.Side,
+		UTime:     order.UTime,
+	}
+	if typ == "CM" {
+		fo.FilledAmt = order.CummBaseQty
+		fo.AvgPrice = order.AvgPrice
+		fo.Symbol = strings.ReplaceAll(fo.Symbol, "_PERP", "")
+	}
+	return fo, nil
+}
+func (bn *Binance) fromStdFuturesConditionalOrderType(orderType string) string {
+	if orderType == "STOP_MARKET" {
+		return "STOP_MARKET"
+	} else if orderType == "STOP_LIMIT" {
+		return "STOP"
+	} else if orderType == "TAKE_PROFIT_MARKET" {
+		return "TAKE_PROFIT_MARKET"
+	} else if orderType == "TAKE_PROFIT_LIMIT" {
+		return "TAKE_PROFIT"
+	}
+	return ""
+}
+func (bn *Binance) toStdFuturesConditionalOrderType(orderType string) string {
+	if orderType == "STOP_MARKET" {
+		return "STOP_MARKET"
+	} else if orderType == "STOP" {
+		return "STOP_LIMIT"
+	} else if orderType == "TAKE_PROFIT_MARKET" {
+		return "TAKE_PROFIT_MARKET"
+	} else if orderType == "TAKE_PROFIT" {
+		return "TAKE_PROFIT_LIMIT"
+	}
+	return ""
+}
+func (bn *Binance) fromStdWorkingType(triggerBy string) string {
+	if triggerBy == "" || triggerBy == "MARKET_PRICE" {
+		return "CONTRACT_PRICE"
+	} else if triggerBy == "MARK_PRICE" {
+		return "MARK_PRICE"
+	}
+	return ""
+}
+func (bn *Binance) toStdWorkingType(workingType string) string {
+	if workingType == "CONTRACT_PRICE" {
+		return "MARKET_PRICE"
+	} else if workingType == "MARK_PRICE" {
+		return "MARK_PRICE"
+	}
+	return ""
+}
+
+// UM走algo order接口, CM走普通下单接口, 统一账户走conditional接口
+// 不支持按指数价格触发
+func (bn *Binance) FuturesPlaceConditionalOrder(typ, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy, positionMode string,
+	reduceOnly int) (string, error) {
+	if typ == "CM" && strings.Index(symbol, "_") == -1 {
+		symbol += "_PERP"
+	}
+	_, isLimit, ok := parseConditionalOrderType(orderType)
+	if !ok {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	workingType := bn.fromStdWorkingType(triggerBy)
+	if workingType == "" {
+		return "", errors.New(bn.Name() + " not support trigger by " + triggerBy)
+	}
+	if !qty.IsPositive() {
+		return "", errors.New("qty too small! qty=" + qty.String())
+	}
+	bnType := bn.fromStdFuturesConditionalOrderType(orderType)
+	query := fmt.Sprintf("&symbol=%s&side=%s&positionSide=%s&quantity=%s&workingType=%s",
+		symbol, side, positionMode, qty.String(), workingType)
+	if isLimit {
+		query += "&timeInForce=GTC&price=" + price.String()
+	}
+	if reduceOnly == 1 {
+		query += "&reduceOnly=true" // 双开模式下不接受此参数
+	}
+	link := ""
+	if bn.isUnified {
+		query += "&strategyType=" + bnType + "&stopPrice=" + triggerPrice.String()
+		if cltId != "" {
+			query += "&newClientStrategyId=" + cltId
+		}
+		link = bnUnifiedEndpoint + "/papi/v1/um/conditional/order?" + bn.httpQuerySign(query)
+		if typ == "CM" {
+			link = bnUnifiedEndpoint + "/papi/v1/cm/conditional/order?" + bn.httpQuerySign(query)
+		}
+	} else if typ == "CM" {
+		query += "&newOrderRespType=ACK&type=" + bnType + "&stopPrice=" + triggerPrice.String()
+		if cltId != "" {
+			query += "&newClientOrderId=" + cltId
+		}
+		link = bnCMFuturesEndpoint + "/dapi/v1/order?" + bn.httpQuerySign(query)
+	} else {
+		query += "&algoType=CONDITIONAL&type=" + bnType + "&triggerPrice=" + triggerPrice.String()
+		if cltId != "" {
+			query += "&clientAlgoId=" + cltId
+		}
+		link = bnUMFuturesEndpoint + "/fapi/v1/algoOrder?" + bn.httpQuerySign(query)
+	}
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return "", newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code       int    `json:"code"`
+		Msg        string `json:"msg"`
+		OrderId    int64  `json:"orderId"`
+		AlgoId     int64  `json:"algoId"`
+		StrategyId int64  `json:"strategyId"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return "", errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return "", bn.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	if ret.AlgoId != 0 {
+		return strconv.FormatInt(ret.AlgoId, 10), nil
+	} else if ret.StrategyId != 0 {
+		return strconv.FormatInt(ret.StrategyId, 10), nil
+	}
+	return strconv.FormatInt(ret.OrderId, 10), nil
+}
+func (bn *Binance) FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error) {
+	url := bnUMFuturesEndpoint + "/fapi/v1/openAlgoOrders"
+	if typ == "CM" {
+		url = bnCMFuturesEndpoint + "/dapi/v1/openOrders"
+	}
+	if bn.isUnified {
+		url = bnUnifiedEndpoint + "/papi/v1/um/conditional/openOrders"
+		if typ == "CM" {
+			url = bnUnifiedEndpoint + "/papi/v1/cm/conditional/openOrders"
+		}
+	}
+	if typ == "CM" && symbol != "" && strings.Index(symbol, "_") == -1 {
+		symbol += "_PERP"
+	}
+	params := fmt.Sprintf("&symbol=%s", symbol)
+	if symbol == "" {
+		params = ""
+	}
+	url += "?" + bn.httpQuerySign(params)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] == '{' {
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetOpenConditionalOrders", resp)
+	}
+
+	// 三个接口的字段名不同
+	orders := []struct {
+		Symbol           string          `json:"symbol"`
+		OrderId          int64           `json:"orderId"`
+		AlgoId           int64           `json:"algoId"`
+		StrategyId       int64           `json:"strategyId"`
+		ClientId         string          `json:"clientOrderId"`
+		ClientAlgoId     string          `json:"clientAlgoId"`
+		ClientStrategyId string          `json:"newClientStrategyId"`
+		Type             string          `json:"type"`
+		OrderType        string          `json:"orderType"`
+		StrategyType     string          `json:"strategyType"`
+		Side             string          `json:"side"`
+		Price            decimal.Decimal `json:"price"`
+		StopPrice        decimal.Decimal `json:"stopPrice"`
+		TriggerPrice     decimal.Decimal `json:"triggerPrice"`
+		OrigQty          decimal.Decimal `json:"origQty"`
+		Quantity         decimal.Decimal `json:"quantity"`
+		WorkingType      string          `json:"workingType"`
+		Time             int64           `json:"time"`
+		CreateTime       int64           `json:"createTime"`
+		BookTime         int64           `json:"bookTime"`
+	}{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	oL := make([]*ConditionalOrder, 0, len(orders))
+	for _, order := range orders {
+		co := &ConditionalOrder{
+			Symbol:    order.Symbol,
+			Side:      order.Side,
+			TriggerBy: bn.toStdWorkingType(order.WorkingType),
+			Status:    "NEW",
+		}
+		if bn.isUnified {
+			co.OrderId = strconv.FormatInt(order.StrategyId, 10)
+			co.ClientId = order.ClientStrategyId
+			co.Type = bn.toStdFuturesConditionalOrderType(order.StrategyType)
+			co.TriggerPrice = order.StopPrice
+			co.Qty = order.OrigQty
+			co.CTime = order.BookTime
+		} else if typ == "CM" {
+			co.OrderId = strconv.FormatInt(order.OrderId, 10)
+			co.ClientId = order.ClientId
+			co.Type = bn.toStdFuturesConditionalOrderType(order.Type)
+			co.TriggerPrice = order.StopPrice
+			co.Qty = order.OrigQty
+			co.CTime = order.Time
+		} else {
+			co.OrderId = strconv.FormatInt(order.AlgoId, 10)
+			co.ClientId = order.ClientAlgoId
+			co.Type = bn.toStdFuturesConditionalOrderType(order.OrderType)
+			co.TriggerPrice = order.TriggerPrice
+			co.Qty = order.Quantity
+			co.CTime = order.CreateTime
+		}
+		if co.Type == "" { // 普通挂单
+			continue
+		}
+		if _, isLimit, _ := parseConditionalOrderType(co.Type); isLimit {
+			co.Price = order.Price
+		}
+		if typ == "CM" {
+			co.Symbol = strings.ReplaceAll(co.Symbol, "_PERP", "")
+		}
+		oL = append(oL, co)
+	}
+	return oL, nil
+}
+func (bn *Binance) FuturesCancelConditionalOrder(typ, symbol, orderId string) error {
+	if !bn.isUnified && typ == "CM" {
+		return bn.FuturesCancelOrder(typ, symbol, orderId, "")
+	}
+	url := bnUMFuturesEndpoint + "/fapi/v1/algoOrder"
+	params := "&algoId=" + orderId
+	if bn.isUnified {
+		if typ == "CM" && strings.Index(symbol, "_") == -1 {
+			symbol += "_PERP"
+		}
+		url = bnUnifiedEndpoint + "/papi/v1/um/conditional/order"
+		if typ == "CM" {
+			url = bnUnifiedEndpoint + "/papi/v1/cm/conditional/order"
+		}
+		params = "&symbol=" + symbol + "&strategyId=" + orderId
+	}
+	url = url + "?" + bn.httpQuerySign(params)
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code json.Number `json:"code,omitempty"` // algo接口成功时返回"200"
+		Msg  string      `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "" && ret.Code != "0" && ret.Code != "200" {
+		code, _ := strconv.Atoi(ret.Code.String())
+		return bn.apiError(httpCode, code, ret.Msg)
+	}
+	return nil
+}
 func (bn *Binance) FuturesSwitchPositionMode(typ string /*BTCUSDT*/, mode int) error {
 	m := ""
 	if mode == 1 {
@@ -677,9 +1206,9 @@ func (bn *Binance) FuturesSwitchPositionMode(typ string /*BTCUSDT*/, mode int) e
 	}
 	params := "&dualSidePosition=" + m
 	link += "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -689,7 +1218,7 @@ func (bn *Binance) FuturesSwitchPositionMode(typ string /*BTCUSDT*/, mode int) e
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 200 && ret.Code != -4059 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return nil
 }
@@ -721,9 +1250,9 @@ func (bn *Binance) futuresSwitchTradeMode(typ, symbol string /*BTCUSDT*/, mode i
 	}
 	params := "&marginType=" + m + "&symbol=" + symbol
 	link += "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -733,7 +1262,7 @@ func (bn *Binance) futuresSwitchTradeMode(typ, symbol string /*BTCUSDT*/, mode i
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 200 && ret.Code != -4046 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return nil
 }
@@ -758,9 +1287,9 @@ func (bn *Binance) futuresSetLeverage(typ, symbol string /*BTCUSDT*/, leverage i
 	ls := strconv.FormatInt(int64(leverage), 10)
 	params := "&leverage=" + ls + "&symbol=" + symbol
 	link += "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -770,7 +1299,7 @@ func (bn *Binance) futuresSetLeverage(typ, symbol string /*BTCUSDT*/, leverage i
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 200 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return nil
 }
@@ -789,12 +1318,12 @@ func (bn *Binance) FuturesMaintMargin(typ, symbol string) ([]*FuturesLeverageBra
 		symbol += "_PERP"
 	}
 	url = url + "?" + bn.httpQuerySign("&symbol="+symbol)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesMaintMargin", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesMaintMargin", resp)
 	}
 	type Bracket struct {
 		Bracket          int64           `json:"bracket,omitempty"`
@@ -842,13 +1371,13 @@ func (bn *Binance) FuturesGetAllPositions(typ string) (map[string]*FuturesPositi
 			url = bnUnifiedEndpoint + "/papi/v1/cm/positionRisk?" + bn.httpQuerySign("")
 		}
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	if resp[0] == '{' {
-		return nil, bn.handleExceptionResp("FuturesGetAllPositions", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetAllPositions", resp)
 	}
 	recv := []struct {
 		Symbol     string          `json:"symbol"`
@@ -919,13 +1448,13 @@ func (bn *Binance) FuturesGetAllPositionList(typ string) (map[string]*FuturesPos
 			url = bnUnifiedEndpoint + "/papi/v1/cm/positionRisk?" + bn.httpQuerySign("")
 		}
 	}
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	if resp[0] == '{' {
-		return nil, bn.handleExceptionResp("FuturesGetAllPositionList", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetAllPositionList", resp)
 	}
 	recv := []struct {
 		Symbol     string          `json:"symbol"`
@@ -990,12 +1519,12 @@ func (bn *Binance) FuturesGetProfitLossHistory(typ, symbol, plType string,
 	params := fmt.Sprintf("&symbol=%s&incomeType=%s&startTime=%d&endTime=%d&limit=1000",
 		symbol, plType, startTime, endTime)
 	url = url + "?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FuturesGetProfitLossHistory", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FuturesGetProfitLossHistory", resp)
 	}
 	ret := []struct {
 		//Symbol          string           `json:"symbol,omitempty"`
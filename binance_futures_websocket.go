@@ -51,9 +51,9 @@ func init() {
 }
 
 func (bn *Binance) FuturesWsPublicOpen(typ string) error {
-	url := "wss://fstream.binance.com/stream"
+	url := bn.wsUrl("wss://fstream.binance.com/stream")
 	if typ == "CM" {
-		url = "wss://dstream.binance.com/stream"
+		url = bn.wsUrl("wss://dstream.binance.com/stream")
 	}
 	bn.futuresWsPublicTyp = typ
 	var err error
@@ -121,6 +121,17 @@ func (bn *Binance) FuturesWsPublicSubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@miniTicker")
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					if bn.futuresWsPublicTyp == "CM" {
+						sym += "_PERP"
+					}
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@kline_"+interval)
+				}
+			}
 		}
 	}
 	if len(arg.Params) > 0 {
@@ -168,6 +179,17 @@ func (bn *Binance) FuturesWsPublicUnsubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@miniTicker")
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					if bn.futuresWsPublicTyp == "CM" {
+						sym += "_PERP"
+					}
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@kline_"+interval)
+				}
+			}
 		}
 	}
 	if len(arg.Params) > 0 {
@@ -186,6 +208,9 @@ func (bn *Binance) FuturesWsPublicOrderBook5PoolPut(v any) {
 func (bn *Binance) FuturesWsPublicBBOPoolPut(v any) {
 	wsPublicBBOPool.Put(v)
 }
+func (bn *Binance) FuturesWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (bn *Binance) FuturesWsPublicLoop(ch chan<- any) {
 	defer bn.FuturesWsPublicClose()
 	defer close(ch)
@@ -222,18 +247,18 @@ func (bn *Binance) FuturesWsPublicLoop(ch chan<- any) {
 		_, recv, err := bn.futuresWsPublicConn.ReadMessage()
 		if err != nil {
 			if !bn.FuturesWsPublicIsClosed() {
-				ilog.Warning(bn.Name() + " futures.ws.public read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" futures.ws.public read: "+err.Error())
 			}
 			break
 		}
 		msg := bnWsPubMsgPool.Get().(*BinanceWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bn.Name() + " futures.ws.public invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.public invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Code != 0 {
-			ilog.Error(bn.Name() + " futures.ws.public recv subscribe err:" + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.public recv subscribe err:"+string(recv))
 			goto END
 		}
 		l = len(msg.Stream)
@@ -243,9 +268,15 @@ func (bn *Binance) FuturesWsPublicLoop(ch chan<- any) {
 			bn.futuresWsHandleBBO(msg.Data, ch)
 		} else if l > 11 && msg.Stream[l-11:l] == "@miniTicker" {
 			bn.futuresWsHandle24hTickers(msg.Data, ch)
+		} else if strings.Contains(msg.Stream, "@kline_") {
+			if bn.futuresWsPublicTyp == "CM" {
+				bn.wsHandleKLine(msg.Data, "_PERP", ch)
+			} else {
+				bn.wsHandleKLine(msg.Data, "", ch)
+			}
 		} else {
 			if strings.Index(string(recv), `"result":null`) == -1 {
-				ilog.Error(bn.Name() + " futures.ws.public recv unknown msg: " + string(recv))
+				ilog.Error("%s", bn.Name()+" futures.ws.public recv unknown msg: "+string(recv))
 			}
 		}
 	END:
@@ -272,7 +303,7 @@ func (bn *Binance) futuresWsHandleOrderBook5(data json.RawMessage, ch chan<- any
 	depth.reset()
 	if err := easyjson.Unmarshal(data, depth); err == nil {
 		if len(depth.Bids) != len(depth.Asks) || len(depth.Bids) == 0 {
-			ilog.Error(bn.Name() + " futures.ws.public " + depth.Symbol + " orderbook5 exception")
+			ilog.Error("%s", bn.Name()+" futures.ws.public "+depth.Symbol+" orderbook5 exception")
 			return
 		}
 		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
@@ -339,9 +370,9 @@ func (bn *Binance) getListenKey(typ string) (string, error) {
 	} else if typ == "UNIFIED" {
 		link = bnUnifiedEndpoint + "/papi/v1/listenKey"
 	}
-	_, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return "", errors.New(bn.Name() + " net error! " + err.Error())
+		return "", newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code      int    `json:"code,omitempty"`
@@ -353,7 +384,7 @@ func (bn *Binance) getListenKey(typ string) (string, error) {
 		return "", errors.New(bn.Name() + " unmarshal error! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return "", errors.New(bn.Name() + " api err! " + ret.Msg)
+		return "", bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	return ret.ListenKey, nil
 }
@@ -372,9 +403,9 @@ func (bn *Binance) futuresWsPrivateOpen(typ string) error {
 	if err != nil {
 		return errors.New(bn.Name() + " get listenkey fail! " + err.Error())
 	}
-	url := "wss://fstream.binance.com/private/ws/" + listenKey
+	url := bn.wsUrl("wss://fstream.binance.com/private/ws/") + listenKey
 	if typ == "CM" {
-		url = "wss://dstream.binance.com/ws/" + listenKey
+		url = bn.wsUrl("wss://dstream.binance.com/ws/") + listenKey
 	}
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -403,9 +434,9 @@ func (bn *Binance) futuresWsPrivateOpen(typ string) error {
 	return nil
 }
 func (bn *Binance) futuresWsPrivateApiOpen(typ string) error {
-	url := "wss://ws-fapi.binance.com/ws-fapi/v1?returnRateLimits=false"
+	url := bn.wsUrl("wss://ws-fapi.binance.com/ws-fapi/v1?returnRateLimits=false")
 	if typ == "CM" {
-		url = "wss://ws-dapi.binance.com/ws-dapi/v1?returnRateLimits=false"
+		url = bn.wsUrl("wss://ws-dapi.binance.com/ws-dapi/v1?returnRateLimits=false")
 	}
 	var err error
 	dialer := websocket.Dialer{
@@ -548,17 +579,17 @@ func (bn *Binance) futuresWsPrivateLoop(ch chan<- any, wg *sync.WaitGroup) {
 		_, recv, err := bn.futuresWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !bn.futuresWsPrivateIsClosed() {
-				ilog.Warning(bn.Name() + " futures.ws.priv read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" futures.ws.priv read: "+err.Error())
 			}
 			break
 		}
 		if bn.debug {
-			ilog.Rinfo(bn.Name() + " futures.ws.priv: " + string(recv))
+			ilog.Rinfo("%s", bn.Name()+" futures.ws.priv: "+string(recv))
 		}
 		msg := bnFuturesWsPrivMsgPool.Get().(*BnFuturesWsPrivMsg)
 		msg.reset()
 		if err = json.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bn.Name() + " futures.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Event == "ORDER_TRADE_UPDATE" { // order
@@ -568,7 +599,7 @@ func (bn *Binance) futuresWsPrivateLoop(ch chan<- any, wg *sync.WaitGroup) {
 		} else if msg.Event == "TRADE_LITE" { // trade
 		} else if msg.Event == "ACCOUNT_CONFIG_UPDATE" { //
 		} else {
-			ilog.Error(bn.Name() + " futures.ws.priv recv unknown msg: " + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.priv recv unknown msg: "+string(recv))
 		}
 	END:
 		bnFuturesWsPrivMsgPool.Put(msg)
@@ -721,21 +752,21 @@ func (bn *Binance) futuresWsPrivateApiLoop(ch chan<- any, wg *sync.WaitGroup) {
 		_, recv, err := bn.futuresWsPrivateApiConn.ReadMessage()
 		if err != nil {
 			if !bn.futuresWsPrivateApiIsClosed() {
-				ilog.Warning(bn.Name() + " futures.ws.priv.api channel read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" futures.ws.priv.api channel read: "+err.Error())
 			}
 			break
 		}
 		msg := Msg{}
 		if err = json.Unmarshal(recv, &msg); err != nil {
-			ilog.Error(bn.Name() + " futures.ws.priv.api recv invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.priv.api recv invalid msg:"+string(recv))
 			continue
 		}
-		if len(msg.Id) > 5 && msg.Id[0:5] == "ford-" {
+		if len(msg.Id) > 5 && (msg.Id[0:5] == "ford-" || msg.Id[0:5] == "fmod-") {
 			bn.futuresWsHandlePlaceOrderResp(msg.Id, msg.Err.Msg, msg.Result, ch)
 		} else if len(msg.Id) > 5 && msg.Id[0:5] == "fcle-" {
 			bn.futuresWsHandleCancelOrderResp(msg.Err.Msg)
 		} else {
-			ilog.Error(bn.Name() + " futures.ws.priv.api recv unknown msg: " + string(recv))
+			ilog.Error("%s", bn.Name()+" futures.ws.priv.api recv unknown msg: "+string(recv))
 		}
 	}
 }
@@ -756,7 +787,7 @@ func (bn *Binance) futuresWsHandlePlaceOrderResp(reqId, errS string,
 		ClientId string `json:"clientOrderId,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &ret); err != nil {
-		ilog.Error(bn.Name() + " futures.ws.priv.api handle place order resp: " + err.Error())
+		ilog.Error("%s", bn.Name()+" futures.ws.priv.api handle place order resp: "+err.Error())
 		return
 	}
 	ch <- &FuturesOrder{
@@ -767,7 +798,7 @@ func (bn *Binance) futuresWsHandlePlaceOrderResp(reqId, errS string,
 }
 func (bn *Binance) futuresWsHandleCancelOrderResp(errS string) {
 	if errS != "" {
-		ilog.Error(bn.Name() + " futures cancel order fail! " + errS)
+		ilog.Error("%s", bn.Name()+" futures cancel order fail! "+errS)
 	}
 }
 
@@ -813,7 +844,7 @@ func (bn *Binance) FuturesWsPlaceOrder(symbol, cltId string,
 	req := BnWsApiArg{Id: "ford-" + gutils.RandomStr(14), Method: "order.place", Params: &params}
 	reqJson, _ := json.Marshal(req)
 	if bn.debug {
-		ilog.Rinfo(bn.Name() + " ws post future order:" + string(reqJson))
+		ilog.Rinfo("%s", bn.Name()+" ws post future order:"+string(reqJson))
 	}
 
 	bn.futuresWsPrivateApiConnMtx.Lock()
@@ -858,3 +889,42 @@ func (bn *Binance) FuturesWsCancelOrder(symbol, orderId, cltId string) (string,
 	}
 	return req.Id, nil
 }
+func (bn *Binance) FuturesWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	if bn.futuresWsPrivateApiIsClosed() {
+		return "", errors.New(bn.Name() + " futures.ws.priv.api ws closed")
+	}
+	if bn.futuresWsPrivateTyp == "CM" {
+		if strings.Index(symbol, "_") == -1 {
+			symbol += "_PERP"
+		}
+	}
+	params := map[string]any{
+		"apiKey":     bn.apikey,
+		"timestamp":  time.Now().UnixMilli(),
+		"recvWindow": 3000,
+
+		"symbol":   symbol,
+		"side":     side,
+		"quantity": qty.String(),
+		"price":    price.String(),
+	}
+	ordId, _ := strconv.ParseInt(orderId, 10, 64)
+	if ordId > 0 {
+		params["orderId"] = ordId
+	} else if cltId != "" {
+		params["origClientOrderId"] = cltId
+	} else {
+		return "", errors.New("orderId or clientId is empty")
+	}
+	params["signature"] = bn.wsSign(params)
+	req := BnWsApiArg{Id: "fmod-" + gutils.RandomStr(14), Method: "order.modify", Params: params}
+	reqJson, _ := json.Marshal(req)
+
+	bn.futuresWsPrivateApiConnMtx.Lock()
+	defer bn.futuresWsPrivateApiConnMtx.Unlock()
+	if err := bn.futuresWsPrivateApiConn.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(bn.Name() + " send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
@@ -14,9 +14,9 @@ func (bn *Binance) MarginSupported() bool {
 }
 func (bn *Binance) MarginGetCrossAccountInfo() (*MarginCrossAccountInfo, error) {
 	url := bnMarginEndpoint + "/sapi/v1/margin/account?" + bn.httpQuerySign("")
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	recv := struct {
 		Code                       int             `json:"code,omitempty"`
@@ -43,7 +43,7 @@ func (bn *Binance) MarginGetCrossAccountInfo() (*MarginCrossAccountInfo, error)
 		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 || len(recv.Msg) != 0 {
-		return nil, errors.New(bn.Name() + " api err! " + recv.Msg)
+		return nil, bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 
 	mac := MarginCrossAccountInfo{
@@ -78,9 +78,9 @@ func (bn *Binance) MarginGetCrossAccountInfo() (*MarginCrossAccountInfo, error)
 func (bn *Binance) MarginGetMaxBorrowable(symbol string) (MarginMaxBorrowable, error) {
 	params := fmt.Sprintf("&asset=%s", symbol)
 	url := bnMarginEndpoint + "/sapi/v1/margin/maxBorrowable?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return MarginMaxBorrowable{}, errors.New(bn.Name() + " net error! " + err.Error())
+		return MarginMaxBorrowable{}, newNetError(bn.Name(), err)
 	}
 	recv := struct {
 		Code int    `json:"code,omitempty"`
@@ -97,7 +97,7 @@ func (bn *Binance) MarginGetMaxBorrowable(symbol string) (MarginMaxBorrowable, e
 		if recv.Code == -3045 {
 			return MarginMaxBorrowable{}, nil
 		}
-		return MarginMaxBorrowable{}, errors.New(bn.Name() + " api err! " + recv.Msg)
+		return MarginMaxBorrowable{}, bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 
 	return MarginMaxBorrowable{
@@ -134,9 +134,9 @@ func (bn *Binance) MarginPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 	}
 	url := bnMarginEndpoint + "/sapi/v1/margin/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
 	if err != nil {
-		return "", decimal.Zero, "", errors.New(bn.Name() + " net error! " + err.Error())
+		return "", decimal.Zero, "", newNetError(bn.Name(), err)
 	}
 
 	ret := struct {
@@ -154,7 +154,7 @@ func (bn *Binance) MarginPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 		return "", decimal.Zero, "", errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return "", decimal.Zero, "", errors.New(bn.Name() + " api err! " + ret.Msg)
+		return "", decimal.Zero, "", bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return strconv.FormatInt(ret.OrderId, 10), ret.Borrowed, ret.BorrowedAsset, nil
@@ -175,9 +175,9 @@ func (bn *Binance) MarginCancelOrder(symbol string, /*BTCUSDT*/
 	}
 	url := bnMarginEndpoint + "/sapi/v1/margin/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Delete(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, headers)
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 
 	ret := struct {
@@ -190,7 +190,7 @@ func (bn *Binance) MarginCancelOrder(symbol string, /*BTCUSDT*/
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	if ret.Status != "CANCELED" {
 		return errors.New(bn.Name() + " cancel failed! status now: " + ret.Status)
@@ -212,9 +212,9 @@ func (bn *Binance) MarginGetOrder(symbol, orderId, cltId string, isIsolated bool
 	}
 	url := bnMarginEndpoint + "/sapi/v1/margin/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Get(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	order := struct {
@@ -239,7 +239,7 @@ func (bn *Binance) MarginGetOrder(symbol, orderId, cltId string, isIsolated bool
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if order.Code != 0 {
-		return nil, errors.New(order.Msg)
+		return nil, bn.apiError(httpCode, order.Code, order.Msg)
 	}
 	return &MarginOrder{
 		Symbol:      order.Symbol,
@@ -266,12 +266,12 @@ func (bn *Binance) MarginGetTrades(symbol, orderId string, isIsolated bool) ([]*
 	params += "&orderId=" + orderId
 	url := bnMarginEndpoint + "/sapi/v1/margin/myTrades?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Get(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("MarginGetTrades", resp)
+		return nil, bn.handleExceptionResp(httpCode, "MarginGetTrades", resp)
 	}
 
 	trades := []struct {
@@ -305,9 +305,9 @@ func (bn *Binance) MarginRepay(symbol string, qty decimal.Decimal, isIsolated bo
 		symbol, qty.String(), isIsolateds, "REPAY")
 	url := bnMarginEndpoint + "/sapi/v1/margin/borrow-repay?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 
 	ret := struct {
@@ -320,7 +320,7 @@ func (bn *Binance) MarginRepay(symbol string, qty decimal.Decimal, isIsolated bo
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(bn.Name() + " api err! " + ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return nil
@@ -329,12 +329,12 @@ func (bn *Binance) MarginGetAssetInfo(symbol string) (MarginAssetInfo, error) {
 	info := MarginAssetInfo{}
 	url := bnMarginEndpoint + "/sapi/v1/margin/allAssets?asset=" + symbol
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Get(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, headers)
 	if err != nil {
-		return info, errors.New(bn.Name() + " net error! " + err.Error())
+		return info, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return info, bn.handleExceptionResp("MarginGetAssetInfo", resp)
+		return info, bn.handleExceptionResp(httpCode, "MarginGetAssetInfo", resp)
 	}
 
 	ret := []struct {
@@ -17,7 +17,7 @@ func (bn *Binance) SpotServerTime() (int64, error) {
 	url := bnSpotEndpoint + "/api/v3/time"
 	_, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(bn.Name() + " net error! " + err.Error())
+		return 0, newNetError(bn.Name(), err)
 	}
 	recv := struct {
 		Time int64 `json:"serverTime,omitempty"`
@@ -29,9 +29,9 @@ func (bn *Binance) SpotServerTime() (int64, error) {
 }
 func (bn *Binance) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	url := bnSpotEndpoint + "/api/v3/exchangeInfo?permissions=SPOT&symbolStatus=TRADING"
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	recv := struct {
@@ -60,7 +60,7 @@ func (bn *Binance) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, erro
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bn.Name() + " api err! " + recv.Msg)
+		return nil, bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	all := make(map[string]*SpotExchangePairRule)
 	now := time.Now().Unix()
@@ -95,12 +95,12 @@ func (bn *Binance) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, erro
 }
 func (bn *Binance) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
 	url := bnSpotEndpoint + "/api/v3/ticker/24hr"
-	_, resp, err := bn.Get(url, bnApiDeadline, nil)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("SpotGetAll24hTicker", resp)
+		return nil, bn.handleExceptionResp(httpCode, "SpotGetAll24hTicker", resp)
 	}
 	tickers := []struct {
 		Symbol      string          `json:"symbol"`
@@ -130,7 +130,7 @@ func (bn *Binance) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	url := bnSpotEndpoint + "/api/v3/ticker/bookTicker?symbol=" + symbol
 	_, resp, err := bn.Get(url, bnApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(bn.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(bn.Name(), err)
 	}
 	bbo := struct {
 		Symbol   string          `json:"symbol,omitempty"`
@@ -150,11 +150,47 @@ func (bn *Binance) SpotGetBBO(symbol string) (BestBidAsk, error) {
 		AskQty:   bbo.AskQty,
 	}, nil
 }
+
+// 按订阅档位选快照的limit, 留一倍余量(增量删档后快照之外的档位会进入前level档)
+// 取权重分档的上限: 100/500/1000 权重5/25/50, 5000 权重250; level=0 表示全量
+func bnSpotOrderBookLimit(level int) string {
+	if level == 0 || level > 500 {
+		return "5000"
+	} else if level > 250 {
+		return "1000"
+	} else if level > 50 {
+		return "500"
+	}
+	return "100"
+}
+
+// 深度快照, 用于同步本地订单簿
+func (bn *Binance) spotGetOrderBook(symbol string, level int) (int64, [][2]string, [][2]string, error) {
+	url := bnSpotEndpoint + "/api/v3/depth?symbol=" + symbol + "&limit=" + bnSpotOrderBookLimit(level)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
+	if err != nil {
+		return 0, nil, nil, newNetError(bn.Name(), err)
+	}
+	recv := struct {
+		Code         int         `json:"code,omitempty"`
+		Msg          string      `json:"msg,omitempty"`
+		LastUpdateId int64       `json:"lastUpdateId"`
+		Bids         [][2]string `json:"bids"`
+		Asks         [][2]string `json:"asks"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return 0, nil, nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 || len(recv.Msg) != 0 {
+		return 0, nil, nil, bn.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.LastUpdateId, recv.Bids, recv.Asks, nil
+}
 func (bn *Binance) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	url := bnSpotEndpoint + "/api/v3/account?" + bn.httpQuerySign("")
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	recv := struct {
 		Code     int    `json:"code,omitempty"`
@@ -169,7 +205,7 @@ func (bn *Binance) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 || len(recv.Msg) != 0 {
-		return nil, errors.New(bn.Name() + " api err! " + recv.Msg)
+		return nil, bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 
 	assetsMap := make(map[string]*SpotAsset, len(recv.Balances))
@@ -208,9 +244,9 @@ func (bn *Binance) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 	}
 	url := bnSpotEndpoint + "/api/v3/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
 	if err != nil {
-		return "", errors.New(bn.Name() + " net error! " + err.Error())
+		return "", newNetError(bn.Name(), err)
 	}
 
 	ret := struct {
@@ -226,7 +262,7 @@ func (bn *Binance) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 		return "", errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return "", errors.New(bn.Name() + " api err! " + ret.Msg)
+		return "", bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return strconv.FormatInt(ret.OrderId, 10), nil
@@ -242,9 +278,9 @@ func (bn *Binance) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId str
 	}
 	url := bnSpotEndpoint + "/api/v3/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Delete(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, headers)
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 
 	ret := struct {
@@ -257,13 +293,95 @@ func (bn *Binance) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId str
 		return errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(ret.Msg)
+		return bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	if ret.Status != "CANCELED" {
 		return errors.New(bn.Name() + " cancel failed! status now: " + ret.Status)
 	}
 	return nil
 }
+func (bn *Binance) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(bn, orders), nil
+}
+func (bn *Binance) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(bn, orders), nil
+}
+func (bn *Binance) SpotCancelAllOrders(symbol string) error {
+	if symbol == "" {
+		return errors.New(bn.Name() + " symbol empty!")
+	}
+	url := bnSpotEndpoint + "/api/v3/openOrders?" + bn.httpQuerySign("&symbol="+symbol)
+	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
+	httpCode, resp, err := bn.Delete(url, bnApiDeadline, headers)
+	if err != nil {
+		return newNetError(bn.Name(), err)
+	}
+	if len(resp) > 0 && resp[0] == '{' {
+		return bn.handleExceptionResp(httpCode, "SpotCancelAllOrders", resp)
+	}
+	return nil
+}
+func (bn *Binance) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	params := fmt.Sprintf("&symbol=%s&side=%s&type=LIMIT&timeInForce=GTC&quantity=%s&price=%s",
+		symbol, side, qty.String(), price.String())
+	params += "&cancelReplaceMode=STOP_ON_FAILURE&newOrderRespType=RESULT"
+	if orderId != "" {
+		params += "&cancelOrderId=" + orderId
+	} else if cltId != "" {
+		params += "&cancelOrigClientOrderId=" + cltId
+	} else {
+		return nil, errors.New(bn.Name() + " orderId or cltId empty!")
+	}
+	url := bnSpotEndpoint + "/api/v3/order/cancelReplace?" + bn.httpQuerySign(params)
+	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+
+		NewOrder struct {
+			Symbol       string          `json:"symbol,omitempty"`
+			OrderId      int64           `json:"orderId,omitempty"`
+			ClientId     string          `json:"clientOrderId,omitempty"`
+			Price        decimal.Decimal `json:"price"`
+			Quantity     decimal.Decimal `json:"origQty"`
+			ExecutedQty  decimal.Decimal `json:"executedQty"`
+			CummQuoteQty decimal.Decimal `json:"cummulativeQuoteQty"`
+			Status       string          `json:"status,omitempty"`
+			Type         string          `json:"type,omitempty"`
+			TimeInForce  string          `json:"timeInForce,omitempty"`
+			Side         string          `json:"side,omitempty"`
+			Time         int64           `json:"transactTime,omitempty"`
+		} `json:"newOrderResponse"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bn.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	order := &ret.NewOrder
+	return &SpotOrder{
+		Symbol:      order.Symbol,
+		OrderId:     strconv.FormatInt(order.OrderId, 10),
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Quantity,
+		FilledQty:   order.ExecutedQty,
+		FilledAmt:   order.CummQuoteQty,
+		Status:      order.Status,
+		Type:        order.Type,
+		TimeInForce: order.TimeInForce,
+		Side:        order.Side,
+		CTime:       order.Time,
+		UTime:       order.Time,
+	}, nil
+}
 func (bn *Binance) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	params := fmt.Sprintf("&symbol=%s", symbol)
 	if orderId != "" {
@@ -275,9 +393,9 @@ func (bn *Binance) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, erro
 	}
 	url := bnSpotEndpoint + "/api/v3/order?" + bn.httpQuerySign(params)
 	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
-	_, resp, err := bn.Get(url, bnApiDeadline, headers)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 
 	order := struct {
@@ -302,7 +420,7 @@ func (bn *Binance) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, erro
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if order.Code != 0 {
-		return nil, errors.New(order.Msg)
+		return nil, bn.apiError(httpCode, order.Code, order.Msg)
 	}
 	return &SpotOrder{
 		Symbol:      order.Symbol,
@@ -326,12 +444,12 @@ func (bn *Binance) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 		params += "&symbol=" + symbol
 	}
 	url := bnSpotEndpoint + "/api/v3/openOrders?" + bn.httpQuerySign(params)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("SpotGetOpenOrders", resp)
+		return nil, bn.handleExceptionResp(httpCode, "SpotGetOpenOrders", resp)
 	}
 	orders := []struct {
 		Symbol       string          `json:"symbol,omitempty"` // BTCUSDT
@@ -373,13 +491,173 @@ func (bn *Binance) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 
 	return dl, nil
 }
+func (bn *Binance) fromStdConditionalOrderType(orderType string) string {
+	if orderType == "STOP_MARKET" {
+		return "STOP_LOSS"
+	} else if orderType == "STOP_LIMIT" {
+		return "STOP_LOSS_LIMIT"
+	} else if orderType == "TAKE_PROFIT_MARKET" {
+		return "TAKE_PROFIT"
+	} else if orderType == "TAKE_PROFIT_LIMIT" {
+		return "TAKE_PROFIT_LIMIT"
+	}
+	return ""
+}
+func (bn *Binance) toStdConditionalOrderType(orderType string) string {
+	if orderType == "STOP_LOSS" {
+		return "STOP_MARKET"
+	} else if orderType == "STOP_LOSS_LIMIT" {
+		return "STOP_LIMIT"
+	} else if orderType == "TAKE_PROFIT" {
+		return "TAKE_PROFIT_MARKET"
+	} else if orderType == "TAKE_PROFIT_LIMIT" {
+		return "TAKE_PROFIT_LIMIT"
+	}
+	return ""
+}
+
+// 现货条件单就是带stopPrice的普通订单, 只能按最新价触发
+func (bn *Binance) SpotPlaceConditionalOrder(symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string) (string, error) {
+	_, isLimit, ok := parseConditionalOrderType(orderType)
+	if !ok {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	if triggerBy != "" && triggerBy != "MARKET_PRICE" {
+		return "", errors.New(bn.Name() + " spot not support trigger by " + triggerBy)
+	}
+	params := fmt.Sprintf("&newOrderRespType=ACK&symbol=%s&side=%s&type=%s&quantity=%s&stopPrice=%s",
+		symbol, side, bn.fromStdConditionalOrderType(orderType), qty.String(), triggerPrice.String())
+	if isLimit {
+		params += "&timeInForce=GTC&price=" + price.String()
+	}
+	if cltId != "" {
+		params += "&newClientOrderId=" + cltId
+	}
+	url := bnSpotEndpoint + "/api/v3/order?" + bn.httpQuerySign(params)
+	headers := map[string]string{"X-MBX-APIKEY": bn.apikey}
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code    int    `json:"code,omitempty"`
+		Msg     string `json:"msg,omitempty"`
+		OrderId int64  `json:"orderId,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return "", errors.New(bn.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return "", bn.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return strconv.FormatInt(ret.OrderId, 10), nil
+}
+
+// 从挂单中筛选出条件单
+func (bn *Binance) SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error) {
+	params := ""
+	if symbol != "" {
+		params += "&symbol=" + symbol
+	}
+	url := bnSpotEndpoint + "/api/v3/openOrders?" + bn.httpQuerySign(params)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, bn.handleExceptionResp(httpCode, "SpotGetOpenConditionalOrders", resp)
+	}
+	orders := []struct {
+		Symbol      string          `json:"symbol"`
+		OrderId     int64           `json:"orderId"`
+		ClientId    string          `json:"clientOrderId"`
+		Price       decimal.Decimal `json:"price"`
+		StopPrice   decimal.Decimal `json:"stopPrice"`
+		Quantity    decimal.Decimal `json:"origQty"`
+		Type        string          `json:"type"`
+		Side        string          `json:"side"`
+		Time        int64           `json:"time"`
+		WorkingTime int64           `json:"workingTime"` // 触发前为-1
+	}{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(bn.Name() + " Unmarshal err! " + err.Error())
+	}
+	dl := make([]*ConditionalOrder, 0, len(orders))
+	for _, order := range orders {
+		typ := bn.toStdConditionalOrderType(order.Type)
+		if typ == "" {
+			continue
+		}
+		co := &ConditionalOrder{
+			Symbol:       order.Symbol,
+			OrderId:      strconv.FormatInt(order.OrderId, 10),
+			ClientId:     order.ClientId,
+			Side:         order.Side,
+			Type:         typ,
+			TriggerPrice: order.StopPrice,
+			TriggerBy:    "MARKET_PRICE",
+			Qty:          order.Quantity,
+			Status:       "NEW",
+			CTime:        order.Time,
+		}
+		if _, isLimit, _ := parseConditionalOrderType(typ); isLimit {
+			co.Price = order.Price
+		}
+		if order.WorkingTime > 0 { // 已触发, 在订单簿中等待成交
+			co.Status = "TRIGGERED"
+		}
+		dl = append(dl, co)
+	}
+	return dl, nil
+}
+func (bn *Binance) SpotCancelConditionalOrder(symbol, orderId string) error {
+	return bn.SpotCancelOrder(symbol, orderId, "")
+}
+func (bn *Binance) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	params := fmt.Sprintf("symbol=%s&interval=%s&limit=%d", symbol, interval, limit)
+	if startTime > 0 {
+		params += fmt.Sprintf("&startTime=%d", startTime*1000)
+	}
+	if endTime > 0 {
+		params += fmt.Sprintf("&endTime=%d", endTime*1000-1)
+	}
+	url := bnSpotEndpoint + "/api/v3/klines?" + params
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, bn.handleExceptionResp(httpCode, "SpotGetKLine", resp)
+	}
+
+	var klines [][]any
+	if err = json.Unmarshal(resp, &klines); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make([]KLine, 0, len(klines))
+	for _, v := range klines {
+		kl := KLine{}
+		kl.OpenTime = int64(v[0].(float64)) / 1000
+		kl.OpenPrice, _ = decimal.NewFromString(v[1].(string))
+		kl.HighPrice, _ = decimal.NewFromString(v[2].(string))
+		kl.LowPrice, _ = decimal.NewFromString(v[3].(string))
+		kl.ClosePrice, _ = decimal.NewFromString(v[4].(string))
+		kl.Volume, _ = decimal.NewFromString(v[5].(string))
+		kl.QuoteVolume, _ = decimal.NewFromString(v[7].(string))
+
+		all = append(all, kl)
+	}
+	return all, nil
+}
 func (bn *Binance) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 	params := "&symbol=" + symbol
 	var f SpotTradeFee
 	url := bnSpotEndpoint + "/api/v3/account/commission?" + bn.httpQuerySign(params)
 	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return f, errors.New(bn.Name() + " net error! " + err.Error())
+		return f, newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Symbol string `json:"symbol,omitempty"` // BTCUSDT
@@ -24,6 +24,7 @@ var (
 	bnSpotWsPublicBBOInnerPool       sync.Pool
 	bnSpotWsPublicTickerInnerPool    sync.Pool
 	bnSpotWsPublicTradeInnerPool     sync.Pool
+	bnWsPublicKLineInnerPool         sync.Pool
 )
 
 func init() {
@@ -55,9 +56,14 @@ func init() {
 			return &BinanceSpotPublicTrade{}
 		},
 	}
+	bnWsPublicKLineInnerPool = sync.Pool{
+		New: func() any {
+			return &BinanceWsKLine{}
+		},
+	}
 }
 func (bn *Binance) SpotWsPublicOpen() error {
-	url := "wss://stream.binance.com:443/stream"
+	url := bn.wsUrl("wss://stream.binance.com:443/stream")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -100,6 +106,15 @@ func (bn *Binance) SpotWsPublicSubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@depth5@100ms")
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, level := parseOrderBookSymbol(v)
+					bn.spotWsOrderBooks.add(sym, level)
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@depth@100ms")
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolArr := strings.SplitSeq(arr[1], ",")
@@ -121,6 +136,14 @@ func (bn *Binance) SpotWsPublicSubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@aggTrade")
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@kline_"+interval)
+				}
+			}
 		}
 	}
 	if len(arg.Params) > 0 {
@@ -145,6 +168,15 @@ func (bn *Binance) SpotWsPublicUnsubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@depth5@100ms")
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, _ := parseOrderBookSymbol(v)
+					bn.spotWsOrderBooks.remove(sym)
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@depth@100ms")
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolArr := strings.SplitSeq(arr[1], ",")
@@ -166,6 +198,14 @@ func (bn *Binance) SpotWsPublicUnsubscribe(channels []string) {
 					arg.Params = append(arg.Params, strings.ToLower(sym)+"@aggTrade")
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					arg.Params = append(arg.Params, strings.ToLower(sym)+"@kline_"+interval)
+				}
+			}
 		}
 	}
 	if len(arg.Params) > 0 {
@@ -184,6 +224,9 @@ func (bn *Binance) SpotWsPublicOrderBook5PoolPut(v any) {
 func (bn *Binance) SpotWsPublicBBOPoolPut(v any) {
 	wsPublicBBOPool.Put(v)
 }
+func (bn *Binance) SpotWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (bn *Binance) SpotWsPublicLoop(ch chan<- any) {
 	defer bn.SpotWsPublicClose()
 	defer close(ch)
@@ -220,18 +263,18 @@ func (bn *Binance) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := bn.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !bn.SpotWsPublicIsClosed() { // 并非主动断开
-				ilog.Warning(bn.Name() + " spot.ws.public read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" spot.ws.public read: "+err.Error())
 			}
 			break
 		}
 		msg := bnWsPubMsgPool.Get().(*BinanceWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bn.Name() + " spot.ws.public invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" spot.ws.public invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Code != 0 { // 订阅的响应
-			ilog.Error(bn.Name() + " spot.ws.public recv subscribe err:" + string(recv))
+			ilog.Error("%s", bn.Name()+" spot.ws.public recv subscribe err:"+string(recv))
 			goto END
 		}
 		l = len(msg.Stream)
@@ -239,13 +282,17 @@ func (bn *Binance) SpotWsPublicLoop(ch chan<- any) {
 			bn.spotWsHandleBBO(msg.Data, ch)
 		} else if l > 13 && msg.Stream[l-13:l] == "@depth5@100ms" {
 			bn.spotWsHandleOrderBook5(strings.ToUpper(msg.Stream[0:l-13]), msg.Data, ch)
+		} else if l > 12 && msg.Stream[l-12:l] == "@depth@100ms" {
+			bn.spotWsHandleOrderBook(strings.ToUpper(msg.Stream[0:l-12]), msg.Data, ch)
 		} else if l > 11 && msg.Stream[l-11:l] == "@miniTicker" {
 			bn.spotWsHandle24hTickers(msg.Data, ch)
 		} else if l > 9 && msg.Stream[l-9:l] == "@aggTrade" {
 			bn.spotWsHandlePublicTrade(msg.Data, ch)
+		} else if strings.Contains(msg.Stream, "@kline_") {
+			bn.wsHandleKLine(msg.Data, "", ch)
 		} else {
 			if strings.Index(string(recv), `"result":null`) == -1 {
-				ilog.Error(bn.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+				ilog.Error("%s", bn.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 			}
 		}
 	END:
@@ -272,7 +319,7 @@ func (bn *Binance) spotWsHandleOrderBook5(symbol string, data json.RawMessage, c
 	depth.reset()
 	if err := easyjson.Unmarshal(data, depth); err == nil {
 		if len(depth.Bids) != len(depth.Asks) || len(depth.Bids) == 0 {
-			ilog.Error(bn.Name() + " spot.ws.public " + symbol + " orderbook5 exception")
+			ilog.Error("%s", bn.Name()+" spot.ws.public "+symbol+" orderbook5 exception")
 			return
 		}
 		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
@@ -292,6 +339,44 @@ func (bn *Binance) spotWsHandleOrderBook5(symbol string, data json.RawMessage, c
 		ch <- obd
 	}
 }
+
+// 增量深度 + rest快照同步, U/u 必须连续, 否则重新拉快照
+// 快照在单独的goroutine中拉取, 期间的增量先缓存, 拉取完成后在下一条增量时回放
+func (bn *Binance) spotWsHandleOrderBook(symbol string, data json.RawMessage, ch chan<- any) {
+	ob := bn.spotWsOrderBooks.get(symbol)
+	if ob == nil {
+		return
+	}
+	depth := struct {
+		Time    int64       `json:"E"`
+		FirstId int64       `json:"U"`
+		LastId  int64       `json:"u"`
+		Bids    [][2]string `json:"b"`
+		Asks    [][2]string `json:"a"`
+	}{}
+	if err := json.Unmarshal(data, &depth); err != nil {
+		ilog.Error("%s", bn.Name()+" spot.ws.public "+symbol+" orderbook exception")
+		return
+	}
+	d := &obDiff{
+		firstId: depth.FirstId,
+		lastId:  depth.LastId,
+		time:    depth.Time,
+		bids:    depth.Bids,
+		asks:    depth.Asks,
+	}
+	updated := ob.applyDiff(d)
+	synced, err := ob.pollSnapshot()
+	if err != nil {
+		ilog.Warning("%s", bn.Name()+" spot.ws.public "+symbol+" get orderbook snapshot: "+err.Error())
+	}
+	if updated || synced {
+		ch <- ob.snapshot()
+	}
+	ob.fetchSnapshot(func() (int64, [][2]string, [][2]string, error) {
+		return bn.spotGetOrderBook(symbol, ob.level)
+	})
+}
 func (bn *Binance) spotWsHandleBBO(data json.RawMessage, ch chan<- any) {
 	bbo := bnSpotWsPublicBBOInnerPool.Get().(*BinanceSpotBBO)
 	defer bnSpotWsPublicBBOInnerPool.Put(bbo)
@@ -331,12 +416,32 @@ func (bn *Binance) spotWsHandlePublicTrade(data json.RawMessage, ch chan<- any)
 	}
 }
 
+// 现货和合约的kline推送格式相同, trimSuffix 用于去掉币本位合约的 _PERP
+func (bn *Binance) wsHandleKLine(data json.RawMessage, trimSuffix string, ch chan<- any) {
+	kl := bnWsPublicKLineInnerPool.Get().(*BinanceWsKLine)
+	defer bnWsPublicKLineInnerPool.Put(kl)
+	if err := easyjson.Unmarshal(data, kl); err == nil {
+		v := wsPublicKLinePool.Get().(*KLine)
+		v.Symbol = strings.TrimSuffix(kl.Symbol, trimSuffix)
+		v.Interval = kl.K.Interval
+		v.OpenTime = kl.K.OpenTime / 1000
+		v.OpenPrice = kl.K.Open
+		v.HighPrice = kl.K.High
+		v.LowPrice = kl.K.Low
+		v.ClosePrice = kl.K.Close
+		v.Volume = kl.K.Volume
+		v.QuoteVolume = kl.K.QuoteVolume
+		v.IsClosed = kl.K.IsClosed
+		ch <- v
+	}
+}
+
 // = priv channel
 func (bn *Binance) SpotWsPrivateSupported() bool {
 	return true
 }
 func (bn *Binance) SpotWsPrivateOpen() error {
-	url := "wss://ws-api.binance.com:443/ws-api/v3?returnRateLimits=false"
+	url := bn.wsUrl("wss://ws-api.binance.com:443/ws-api/v3?returnRateLimits=false")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -387,7 +492,7 @@ func (bn *Binance) SpotWsPrivateOpen() error {
 		Status int `json:"status"`
 	}{}
 	if err = json.Unmarshal(recv, &resp); err != nil || resp.Status != 200 {
-		ilog.Error(bn.Name() + " spot.ws.priv userDataStream.subscribe.signature : " + string(recv))
+		ilog.Error("%s", bn.Name()+" spot.ws.priv userDataStream.subscribe.signature : "+string(recv))
 		bn.SpotWsPrivateClose()
 		return errors.New(bn.Name() + " spot.ws.priv subscribe userdata fail!")
 	}
@@ -473,17 +578,17 @@ func (bn *Binance) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := bn.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !bn.SpotWsPrivateIsClosed() {
-				ilog.Warning(bn.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if bn.debug {
-			ilog.Rinfo(bn.Name() + " spot.ws.priv " + string(recv))
+			ilog.Rinfo("%s", bn.Name()+" spot.ws.priv "+string(recv))
 		}
 		msg := bnSpotWsPrivMsgPool.Get().(*BnSpotWsPrivMsg)
 		msg.reset()
 		if err = json.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bn.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Status == 0 {
@@ -494,15 +599,17 @@ func (bn *Binance) SpotWsPrivateLoop(ch chan<- any) {
 				bn.spotWsHandleBalanceUpdate(recv, ch)
 			} else if msg.Data.Event == "eventStreamTerminated" { // will be closed
 			} else {
-				ilog.Error(bn.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+				ilog.Error("%s", bn.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 			}
 		} else { // ws api
 			if len(msg.Id) > 5 && msg.Id[0:5] == "sord-" {
 				bn.spotWsHandlePlaceOrderResp(msg.Id, msg.Err.Msg, msg.Result, ch)
 			} else if len(msg.Id) > 5 && msg.Id[0:5] == "scle-" {
 				bn.spotWsHandleCancelOrderResp(msg.Err.Msg)
+			} else if len(msg.Id) > 5 && msg.Id[0:5] == "samd-" {
+				bn.spotWsHandleAmendOrderResp(msg.Id, msg.Err.Msg, msg.Result, ch)
 			} else if len(msg.Id) == 0 {
-				ilog.Error(bn.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+				ilog.Error("%s", bn.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 			}
 		}
 	END:
@@ -599,7 +706,7 @@ func (bn *Binance) spotWsHandlePlaceOrderResp(reqId, errS string,
 		ClientId string `json:"clientOrderId,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &ret); err != nil {
-		ilog.Error(bn.Name() + " spot.ws.priv handle place order resp: " + err.Error())
+		ilog.Error("%s", bn.Name()+" spot.ws.priv handle place order resp: "+err.Error())
 		return
 	}
 	ch <- &SpotOrder{
@@ -608,9 +715,37 @@ func (bn *Binance) spotWsHandlePlaceOrderResp(reqId, errS string,
 		ClientId:  ret.ClientId,
 	}
 }
+func (bn *Binance) spotWsHandleAmendOrderResp(reqId, errS string,
+	data json.RawMessage, ch chan<- any) {
+	if errS != "" {
+		ch <- &SpotOrder{
+			RequestId: reqId,
+			Err:       errS,
+		}
+		return
+	}
+
+	ret := struct {
+		NewOrder struct {
+			Symbol   string `json:"symbol,omitempty"`
+			OrderId  int64  `json:"orderId,omitempty"`
+			ClientId string `json:"clientOrderId,omitempty"`
+		} `json:"newOrderResponse"`
+	}{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		ilog.Error("%s", bn.Name()+" spot.ws.priv handle amend order resp: "+err.Error())
+		return
+	}
+	ch <- &SpotOrder{
+		RequestId: reqId,
+		Symbol:    ret.NewOrder.Symbol,
+		OrderId:   strconv.FormatInt(ret.NewOrder.OrderId, 10),
+		ClientId:  ret.NewOrder.ClientId,
+	}
+}
 func (bn *Binance) spotWsHandleCancelOrderResp(errS string) {
 	if errS != "" {
-		ilog.Error(bn.Name() + " spot cancel order fail! " + errS)
+		ilog.Error("%s", bn.Name()+" spot cancel order fail! "+errS)
 	}
 }
 
@@ -647,7 +782,7 @@ func (bn *Binance) SpotWsPlaceOrder(symbol, cltId string,
 	req := BnWsApiArg{Id: "sord-" + gutils.RandomStr(16), Method: "order.place", Params: params}
 	reqJson, _ := json.Marshal(req)
 	if bn.debug {
-		ilog.Rinfo(bn.Name() + " ws post spot order:" + string(reqJson))
+		ilog.Rinfo("%s", bn.Name()+" ws post spot order:"+string(reqJson))
 	}
 
 	bn.spotWsPrivateConnMtx.Lock()
@@ -687,3 +822,41 @@ func (bn *Binance) SpotWsCancelOrder(symbol, orderId, cltId string) (string, err
 	}
 	return req.Id, nil
 }
+func (bn *Binance) SpotWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	if bn.SpotWsPrivateIsClosed() {
+		return "", errors.New(bn.Name() + " spot.ws.priv closed")
+	}
+	params := map[string]any{
+		"apiKey":     bn.apikey,
+		"timestamp":  time.Now().UnixMilli(),
+		"recvWindow": 3000,
+
+		"symbol":            symbol,
+		"cancelReplaceMode": "STOP_ON_FAILURE",
+		"newOrderRespType":  "ACK",
+		"side":              side,
+		"type":              "LIMIT",
+		"timeInForce":       "GTC",
+		"quantity":          qty.String(),
+		"price":             price.String(),
+	}
+	ordId, _ := strconv.ParseInt(orderId, 10, 64)
+	if ordId > 0 {
+		params["cancelOrderId"] = ordId
+	} else if cltId != "" {
+		params["cancelOrigClientOrderId"] = cltId
+	} else {
+		return "", errors.New("orderId or clientId is empty")
+	}
+	params["signature"] = bn.wsSign(params)
+	req := BnWsApiArg{Id: "samd-" + gutils.RandomStr(16), Method: "order.cancelReplace", Params: params}
+	reqJson, _ := json.Marshal(req)
+
+	bn.spotWsPrivateConnMtx.Lock()
+	defer bn.spotWsPrivateConnMtx.Unlock()
+	if err := bn.spotWsPrivateConn.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(bn.Name() + " spot.ws.priv send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
@@ -78,3 +78,17 @@ type BinanceSpotPublicTrade struct {
 	Price decimal.Decimal `json:"p"`
 	Qty   decimal.Decimal `json:"q"`
 }
+type BinanceWsKLine struct {
+	Symbol string `json:"s"`
+	K      struct {
+		OpenTime    int64           `json:"t"` // msec
+		Interval    string          `json:"i"`
+		Open        decimal.Decimal `json:"o"`
+		High        decimal.Decimal `json:"h"`
+		Low         decimal.Decimal `json:"l"`
+		Close       decimal.Decimal `json:"c"`
+		Volume      decimal.Decimal `json:"v"`
+		QuoteVolume decimal.Decimal `json:"q"`
+		IsClosed    bool            `json:"x"`
+	} `json:"k"`
+}
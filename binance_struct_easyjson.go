@@ -118,7 +118,240 @@ func (v *BinanceWsPubMsg) UnmarshalJSON(data []byte) error {
 func (v *BinanceWsPubMsg) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC5a5ed42DecodeGithubComShaovieCex(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *BinanceSpotPublicTrade) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *BinanceWsKLine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "s":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "k":
+			easyjsonC5a5ed42Decode(in, &out.K)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC5a5ed42EncodeGithubComShaovieCex1(out *jwriter.Writer, in BinanceWsKLine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"s\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"k\":"
+		out.RawString(prefix)
+		easyjsonC5a5ed42Encode(out, in.K)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BinanceWsKLine) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC5a5ed42EncodeGithubComShaovieCex1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BinanceWsKLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC5a5ed42EncodeGithubComShaovieCex1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BinanceWsKLine) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC5a5ed42DecodeGithubComShaovieCex1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BinanceWsKLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC5a5ed42DecodeGithubComShaovieCex1(l, v)
+}
+func easyjsonC5a5ed42Decode(in *jlexer.Lexer, out *struct {
+	OpenTime    int64           `json:"t"`
+	Interval    string          `json:"i"`
+	Open        decimal.Decimal `json:"o"`
+	High        decimal.Decimal `json:"h"`
+	Low         decimal.Decimal `json:"l"`
+	Close       decimal.Decimal `json:"c"`
+	Volume      decimal.Decimal `json:"v"`
+	QuoteVolume decimal.Decimal `json:"q"`
+	IsClosed    bool            `json:"x"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "t":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OpenTime = int64(in.Int64())
+			}
+		case "i":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Interval = string(in.String())
+			}
+		case "o":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Open).UnmarshalJSON(data))
+				}
+			}
+		case "h":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.High).UnmarshalJSON(data))
+				}
+			}
+		case "l":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Low).UnmarshalJSON(data))
+				}
+			}
+		case "c":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Close).UnmarshalJSON(data))
+				}
+			}
+		case "v":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "q":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
+		case "x":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IsClosed = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC5a5ed42Encode(out *jwriter.Writer, in struct {
+	OpenTime    int64           `json:"t"`
+	Interval    string          `json:"i"`
+	Open        decimal.Decimal `json:"o"`
+	High        decimal.Decimal `json:"h"`
+	Low         decimal.Decimal `json:"l"`
+	Close       decimal.Decimal `json:"c"`
+	Volume      decimal.Decimal `json:"v"`
+	QuoteVolume decimal.Decimal `json:"q"`
+	IsClosed    bool            `json:"x"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"t\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.OpenTime))
+	}
+	{
+		const prefix string = ",\"i\":"
+		out.RawString(prefix)
+		out.String(string(in.Interval))
+	}
+	{
+		const prefix string = ",\"o\":"
+		out.RawString(prefix)
+		out.Raw((in.Open).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"h\":"
+		out.RawString(prefix)
+		out.Raw((in.High).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"l\":"
+		out.RawString(prefix)
+		out.Raw((in.Low).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"c\":"
+		out.RawString(prefix)
+		out.Raw((in.Close).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"v\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"q\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"x\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsClosed))
+	}
+	out.RawByte('}')
+}
+func easyjsonC5a5ed42DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *BinanceSpotPublicTrade) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -170,7 +403,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *BinanceSp
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex1(out *jwriter.Writer, in BinanceSpotPublicTrade) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex2(out *jwriter.Writer, in BinanceSpotPublicTrade) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -200,27 +433,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex1(out *jwriter.Writer, in BinanceS
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceSpotPublicTrade) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex1(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceSpotPublicTrade) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex1(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceSpotPublicTrade) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex1(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceSpotPublicTrade) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex1(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex2(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *BinanceSpotOrderBook) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *BinanceSpotOrderBook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -332,7 +565,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *BinanceSp
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex2(out *jwriter.Writer, in BinanceSpotOrderBook) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex3(out *jwriter.Writer, in BinanceSpotOrderBook) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -390,27 +623,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex2(out *jwriter.Writer, in BinanceS
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceSpotOrderBook) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex2(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceSpotOrderBook) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex2(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceSpotOrderBook) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex2(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceSpotOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex2(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex3(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *BinanceSpotBBO) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *BinanceSpotBBO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -472,7 +705,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *BinanceSp
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex3(out *jwriter.Writer, in BinanceSpotBBO) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex4(out *jwriter.Writer, in BinanceSpotBBO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -513,27 +746,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex3(out *jwriter.Writer, in BinanceS
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceSpotBBO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex3(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceSpotBBO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex3(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceSpotBBO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex3(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceSpotBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex3(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex4(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *BinanceSpot24hTicker) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *BinanceSpot24hTicker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -587,7 +820,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *BinanceSp
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex4(out *jwriter.Writer, in BinanceSpot24hTicker) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex5(out *jwriter.Writer, in BinanceSpot24hTicker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -617,27 +850,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex4(out *jwriter.Writer, in BinanceS
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceSpot24hTicker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex4(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceSpot24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex4(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceSpot24hTicker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex4(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceSpot24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex4(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex5(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *BinanceFuturesOrderBook) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *BinanceFuturesOrderBook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -767,7 +1000,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *BinanceFu
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex5(out *jwriter.Writer, in BinanceFuturesOrderBook) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex6(out *jwriter.Writer, in BinanceFuturesOrderBook) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -855,27 +1088,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex5(out *jwriter.Writer, in BinanceF
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceFuturesOrderBook) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex5(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceFuturesOrderBook) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex5(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceFuturesOrderBook) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex5(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceFuturesOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex5(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex6(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *BinanceFuturesBBO) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *BinanceFuturesBBO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -943,7 +1176,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *BinanceFu
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex6(out *jwriter.Writer, in BinanceFuturesBBO) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex7(out *jwriter.Writer, in BinanceFuturesBBO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -994,27 +1227,27 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex6(out *jwriter.Writer, in BinanceF
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceFuturesBBO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex6(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceFuturesBBO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex6(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceFuturesBBO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex6(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceFuturesBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex6(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex7(l, v)
 }
-func easyjsonC5a5ed42DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *BinanceFutures24hTicker) {
+func easyjsonC5a5ed42DecodeGithubComShaovieCex8(in *jlexer.Lexer, out *BinanceFutures24hTicker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1068,7 +1301,7 @@ func easyjsonC5a5ed42DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *BinanceFu
 		in.Consumed()
 	}
 }
-func easyjsonC5a5ed42EncodeGithubComShaovieCex7(out *jwriter.Writer, in BinanceFutures24hTicker) {
+func easyjsonC5a5ed42EncodeGithubComShaovieCex8(out *jwriter.Writer, in BinanceFutures24hTicker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1098,23 +1331,23 @@ func easyjsonC5a5ed42EncodeGithubComShaovieCex7(out *jwriter.Writer, in BinanceF
 // MarshalJSON supports json.Marshaler interface
 func (v BinanceFutures24hTicker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5a5ed42EncodeGithubComShaovieCex7(&w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v BinanceFutures24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5a5ed42EncodeGithubComShaovieCex7(w, v)
+	easyjsonC5a5ed42EncodeGithubComShaovieCex8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *BinanceFutures24hTicker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5a5ed42DecodeGithubComShaovieCex7(&r, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *BinanceFutures24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5a5ed42DecodeGithubComShaovieCex7(l, v)
+	easyjsonC5a5ed42DecodeGithubComShaovieCex8(l, v)
 }
@@ -0,0 +1,288 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// 每页最多200个, 逐页取完
+func (bn *Binance) SubAccountList() ([]SubAccount, error) {
+	res := make([]SubAccount, 0, 200)
+	for page := 1; ; page++ {
+		l, err := bn.subAccountList(page, 200)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, l...)
+		if len(l) < 200 {
+			break
+		}
+	}
+	return res, nil
+}
+func (bn *Binance) subAccountList(page, limit int) ([]SubAccount, error) {
+	query := "&page=" + strconv.Itoa(page) + "&limit=" + strconv.Itoa(limit)
+	link := bnWalletEndpoint + "/sapi/v1/sub-account/list?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Get(link, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code        int    `json:"code"`
+		Msg         string `json:"msg"`
+		SubAccounts []struct {
+			Email    string `json:"email"`
+			IsFreeze bool   `json:"isFreeze"`
+			CTime    int64  `json:"createTime"` // msec
+		} `json:"subAccounts"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountList", resp)
+	}
+	res := make([]SubAccount, 0, len(ret.SubAccounts))
+	for _, v := range ret.SubAccounts {
+		sa := SubAccount{
+			Id:     v.Email,
+			Name:   v.Email,
+			Status: "NORMAL",
+			CTime:  v.CTime / 1000,
+		}
+		if v.IsFreeze {
+			sa.Status = "FROZEN"
+		}
+		res = append(res, sa)
+	}
+	return res, nil
+}
+func (bn *Binance) SubAccountCreate(name, remark string) (*SubAccount, error) {
+	query := "&subAccountString=" + url.QueryEscape(name)
+	link := bnWalletEndpoint + "/sapi/v1/sub-account/virtualSubAccount?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code  int    `json:"code"`
+		Msg   string `json:"msg"`
+		Email string `json:"email"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Email == "" {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountCreate", resp)
+	}
+	return &SubAccount{Id: ret.Email, Name: ret.Email, Status: "NORMAL"}, nil
+}
+
+// 不支持 FUNDING, binance没有母账户查询子账户资金账户余额的接口
+func (bn *Binance) SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error) {
+	if typ == "SPOT" {
+		return bn.subAccountGetSpotAssets(subAccount)
+	} else if typ == "UM_FUTURE" {
+		return bn.subAccountGetUMAssets(subAccount)
+	}
+	return nil, newNotSupportError(bn.Name())
+}
+func (bn *Binance) subAccountGetSpotAssets(subAccount string) (map[string]*SubAccountAsset, error) {
+	query := "&email=" + url.QueryEscape(subAccount)
+	link := bnWalletEndpoint + "/sapi/v4/sub-account/assets?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Get(link, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code     int    `json:"code"`
+		Msg      string `json:"msg"`
+		Balances []struct {
+			Symbol string          `json:"asset"`
+			Free   decimal.Decimal `json:"free"`
+			Locked decimal.Decimal `json:"locked"`
+		} `json:"balances"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountGetAssets", resp)
+	}
+	assets := make(map[string]*SubAccountAsset, len(ret.Balances))
+	for _, v := range ret.Balances {
+		assets[v.Symbol] = &SubAccountAsset{
+			Symbol: v.Symbol,
+			Total:  v.Free.Add(v.Locked),
+			Avail:  v.Free,
+			Locked: v.Locked,
+		}
+	}
+	return assets, nil
+}
+func (bn *Binance) subAccountGetUMAssets(subAccount string) (map[string]*SubAccountAsset, error) {
+	query := "&futuresType=1&email=" + url.QueryEscape(subAccount)
+	link := bnWalletEndpoint + "/sapi/v2/sub-account/futures/account?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Get(link, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code    int    `json:"code"`
+		Msg     string `json:"msg"`
+		Account struct {
+			Assets []struct {
+				Symbol        string          `json:"asset"`
+				WalletBalance decimal.Decimal `json:"walletBalance"`
+				MaxWithdraw   decimal.Decimal `json:"maxWithdrawAmount"`
+			} `json:"assets"`
+		} `json:"futureAccountResp"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountGetAssets", resp)
+	}
+	assets := make(map[string]*SubAccountAsset, len(ret.Account.Assets))
+	for _, v := range ret.Account.Assets {
+		assets[v.Symbol] = &SubAccountAsset{
+			Symbol: v.Symbol,
+			Total:  v.WalletBalance,
+			Avail:  v.MaxWithdraw,
+			Locked: v.WalletBalance.Sub(v.MaxWithdraw),
+		}
+	}
+	return assets, nil
+}
+
+// 接口不能同时按转出/转入查询, 指定子账户时分别查询后合并
+func (bn *Binance) SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error) {
+	query := "&limit=500"
+	if startTime > 0 {
+		query += fmt.Sprintf("&startTime=%d", startTime*1000)
+	}
+	if endTime > 0 {
+		query += fmt.Sprintf("&endTime=%d", endTime*1000)
+	}
+	if subAccount == "" {
+		return bn.subAccountTransferHistory(query)
+	}
+	out, err := bn.subAccountTransferHistory(query + "&fromEmail=" + url.QueryEscape(subAccount))
+	if err != nil {
+		return nil, err
+	}
+	in, err := bn.subAccountTransferHistory(query + "&toEmail=" + url.QueryEscape(subAccount))
+	if err != nil {
+		return nil, err
+	}
+	return append(out, in...), nil
+}
+func (bn *Binance) subAccountTransferHistory(query string) ([]SubAccountTransfer, error) {
+	link := bnWalletEndpoint + "/sapi/v1/sub-account/universalTransfer?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Get(link, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"code"`
+		Msg    string `json:"msg"`
+		Result []struct {
+			Id     int64           `json:"tranId"`
+			From   string          `json:"fromEmail"`
+			To     string          `json:"toEmail"`
+			Symbol string          `json:"asset"`
+			Qty    decimal.Decimal `json:"amount"`
+			Status string          `json:"status"`          // SUCCESS/PROCESS/FAILURE
+			CTime  int64           `json:"createTimeStamp"` // msec
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountTransferHistory", resp)
+	}
+	res := make([]SubAccountTransfer, 0, len(ret.Result))
+	for _, v := range ret.Result {
+		t := SubAccountTransfer{
+			Id:     strconv.FormatInt(v.Id, 10),
+			Symbol: v.Symbol,
+			Qty:    v.Qty,
+			From:   v.From,
+			To:     v.To,
+			Status: "PENDING",
+			Time:   v.CTime / 1000,
+		}
+		if v.Status == "SUCCESS" {
+			t.Status = "SUCCESS"
+		} else if v.Status == "FAILURE" {
+			t.Status = "FAILED"
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// perm TRADE 开放现货和合约交易, 不支持label
+// ips 不为空时再设置ip白名单, 设置失败时仍返回已创建的key
+func (bn *Binance) SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error) {
+	canTrade := "false"
+	if perm == "TRADE" {
+		canTrade = "true"
+	} else if perm != "READ" {
+		return nil, errors.New(bn.Name() + " not support perm " + perm)
+	}
+	query := "&email=" + url.QueryEscape(subAccount) + "&canTrade=" + canTrade +
+		"&marginTrade=false&futuresTrade=" + canTrade
+	link := bnWalletEndpoint + "/sapi/v1/sub-account/subAccountApi?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	ret := struct {
+		Code      int    `json:"code"`
+		Msg       string `json:"msg"`
+		ApiKey    string `json:"apiKey"`
+		SecretKey string `json:"secretKey"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.ApiKey == "" {
+		return nil, bn.handleExceptionResp(httpCode, "SubAccountCreateApiKey", resp)
+	}
+	key := &SubAccountApiKey{
+		ApiKey:    ret.ApiKey,
+		SecretKey: ret.SecretKey,
+		Perm:      perm,
+	}
+	if len(ips) == 0 {
+		return key, nil
+	}
+	query = "&email=" + url.QueryEscape(subAccount) + "&subAccountApiKey=" + ret.ApiKey +
+		"&status=2&ipAddress=" + url.QueryEscape(strings.Join(ips, ","))
+	link = bnWalletEndpoint + "/sapi/v2/sub-account/subAccountApi/ipRestriction?" + bn.httpQuerySign(query)
+	httpCode, resp, err = bn.Post(link, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return key, newNetError(bn.Name(), err)
+	}
+	ipRet := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err = json.Unmarshal(resp, &ipRet); err != nil {
+		return key, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	if ipRet.Code != 0 {
+		return key, bn.handleExceptionResp(httpCode, "SubAccountCreateApiKey", resp)
+	}
+	key.Ips = ips
+	return key, nil
+}
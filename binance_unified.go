@@ -12,12 +12,12 @@ import (
 
 func (bn *Binance) UnifiedGetAssets() (map[string]*UnifiedAsset, error) {
 	url := bnUnifiedEndpoint + "/papi/v1/balance?" + bn.httpQuerySign("")
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("UnifiedGetAssets", resp)
+		return nil, bn.handleExceptionResp(httpCode, "UnifiedGetAssets", resp)
 	}
 	ret := []struct {
 		Symbol string          `json:"asset"`
@@ -53,7 +53,7 @@ func (bn *Binance) UnifiedWsOpen() error {
 	if err != nil {
 		return errors.New(bn.Name() + " get listenkey fail! " + err.Error())
 	}
-	url := "wss://fstream.binance.com/pm/ws/" + listenKey
+	url := bn.wsUrl("wss://fstream.binance.com/pm/ws/") + listenKey
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
 		HandshakeTimeout:  2 * time.Second,
@@ -127,14 +127,14 @@ func (bn *Binance) UnifiedWsLoop(ch chan<- any) {
 		_, recv, err := bn.unifiedWsConn.ReadMessage()
 		if err != nil {
 			if !bn.UnifiedWsIsClosed() {
-				ilog.Warning(bn.Name() + " unified.ws channel read: " + err.Error())
+				ilog.Warning("%s", bn.Name()+" unified.ws channel read: "+err.Error())
 			}
 			break
 		}
-		ilog.Rinfo("bn unified :" + string(recv))
+		ilog.Rinfo("%s", "bn unified :"+string(recv))
 		msg := Msg{}
 		if err = json.Unmarshal(recv, &msg); err != nil {
-			ilog.Error(bn.Name() + " unified.ws recv invalid msg:" + string(recv))
+			ilog.Error("%s", bn.Name()+" unified.ws recv invalid msg:"+string(recv))
 			continue
 		}
 		if msg.Event == "outboundAccountPosition" { // outboundAccountPosition
@@ -158,7 +158,7 @@ func (bn *Binance) unifiedWsHandleBalance(data json.RawMessage, ch chan<- any) {
 			}
 		}
 	} else {
-		ilog.Error(bn.Name() + " unified.ws account channle unmarshal: " +
-			err.Error() + " " + string(data))
+		ilog.Error("%s", bn.Name()+" unified.ws account channle unmarshal: "+
+			err.Error()+" "+string(data))
 	}
 }
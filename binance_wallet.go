@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -44,7 +46,7 @@ func (bn *Binance) Transfer(symbol, from, to, typ, subAccount string, qty decima
 	} else if from == "MARGIN" && to == "SPOT" {
 		t = "MARGIN_SPOT"
 	} else {
-		return errors.New("not support")
+		return newNotSupportError(bn.Name())
 	}
 
 	if !qty.IsPositive() {
@@ -52,9 +54,9 @@ func (bn *Binance) Transfer(symbol, from, to, typ, subAccount string, qty decima
 	}
 	query := fmt.Sprintf("&type=%s&asset=%s&amount=%s", t, symbol, qty.String())
 	url := bnWalletEndpoint + "/sapi/v1/asset/transfer?" + bn.httpQuerySign(query)
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return errors.New(bn.Name() + " net error! " + err.Error())
+		return newNetError(bn.Name(), err)
 	}
 
 	recv := struct {
@@ -67,7 +69,7 @@ func (bn *Binance) Transfer(symbol, from, to, typ, subAccount string, qty decima
 		return errors.New(bn.Name() + " transfer unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 || len(recv.Msg) != 0 {
-		return errors.New(bn.Name() + " transfer api err! " + recv.Msg)
+		return bn.apiError(httpCode, recv.Code, recv.Msg)
 	}
 
 	if recv.TranId == 0 {
@@ -77,12 +79,12 @@ func (bn *Binance) Transfer(symbol, from, to, typ, subAccount string, qty decima
 }
 func (bn *Binance) FundingGetAllAssets() (map[string]*FundingAsset, error) {
 	url := bnWalletEndpoint + "/sapi/v1/asset/get-funding-asset?" + bn.httpQuerySign("")
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("FundingGetAsset", resp)
+		return nil, bn.handleExceptionResp(httpCode, "FundingGetAsset", resp)
 	}
 	recv := []struct {
 		Symbol      string          `json:"asset"`
@@ -112,12 +114,12 @@ func (bn *Binance) FundingGetAsset(symbol string) (FundingAsset, error) {
 	query = ""
 	url := bnWalletEndpoint + "/sapi/v1/asset/get-funding-asset?" + bn.httpQuerySign(query)
 	var fa FundingAsset
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return fa, errors.New(bn.Name() + " net error! " + err.Error())
+		return fa, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return fa, bn.handleExceptionResp("FundingGetAsset", resp)
+		return fa, bn.handleExceptionResp(httpCode, "FundingGetAsset", resp)
 	}
 	recv := []struct {
 		Symbol      string          `json:"asset"`
@@ -147,9 +149,9 @@ func (bn *Binance) Withdrawal(symbol, addr, memo, chain string, qty decimal.Deci
 	query := fmt.Sprintf("&coin=%s&network=%s&address=%s&addressTag=%s&amount=%s&walletType=%d",
 		symbol, chain, addr, memo, qty.String(), 1)
 	url := bnWalletEndpoint + "/sapi/v1/capital/withdraw/apply?" + bn.httpQuerySign(query)
-	_, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Post(url, nil, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	ret := struct {
 		Code int    `json:"code,omitempty"`
@@ -161,7 +163,7 @@ func (bn *Binance) Withdrawal(symbol, addr, memo, chain string, qty decimal.Deci
 		return nil, errors.New(bn.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bn.Name() + " fail! msg=" + ret.Msg)
+		return nil, bn.apiError(httpCode, ret.Code, ret.Msg)
 	}
 	wr := &WithdrawReturn{
 		WId:    ret.Id,
@@ -172,12 +174,12 @@ func (bn *Binance) Withdrawal(symbol, addr, memo, chain string, qty decimal.Deci
 func (bn *Binance) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	query := fmt.Sprintf("&coin=%s", symbol)
 	url := bnWalletEndpoint + "/sapi/v1/capital/withdraw/history?" + bn.httpQuerySign(query)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("GetWithdrawalHistory", resp)
+		return nil, bn.handleExceptionResp(httpCode, "GetWithdrawalHistory", resp)
 	}
 	ret := []struct {
 		Id       string          `json:"id"`
@@ -209,18 +211,70 @@ func (bn *Binance) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
 	}
 	return res, nil
 }
+func (bn *Binance) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	query := fmt.Sprintf("&coin=%s", symbol)
+	if startTime > 0 {
+		query += fmt.Sprintf("&startTime=%d", startTime*1000)
+	}
+	if endTime > 0 {
+		query += fmt.Sprintf("&endTime=%d", endTime*1000)
+	}
+	url := bnWalletEndpoint + "/sapi/v1/capital/deposit/hisrec?" + bn.httpQuerySign(query)
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, bn.handleExceptionResp(httpCode, "GetDepositHistory", resp)
+	}
+	ret := []struct {
+		Id           string          `json:"id"`
+		Symbol       string          `json:"coin"`
+		Qty          decimal.Decimal `json:"amount"`
+		Network      string          `json:"network"`
+		Status       int             `json:"status"`
+		Addr         string          `json:"address"`
+		Memo         string          `json:"addressTag"`
+		Txid         string          `json:"txId"`
+		InsertTime   int64           `json:"insertTime"`   // msec
+		ConfirmTimes string          `json:"confirmTimes"` // 1/12
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+
+	res := make([]DepositResult, 0, len(ret))
+	for i := range ret {
+		confirms, _, _ := strings.Cut(ret[i].ConfirmTimes, "/")
+		n, _ := strconv.ParseInt(confirms, 10, 64)
+		a := DepositResult{
+			Id:            ret[i].Id,
+			Symbol:        ret[i].Symbol,
+			Network:       ret[i].Network,
+			Addr:          ret[i].Addr,
+			Memo:          ret[i].Memo,
+			Txid:          ret[i].Txid,
+			Qty:           ret[i].Qty,
+			Confirmations: n,
+			Status:        bn.toStdDepositStatus(ret[i].Status),
+			Time:          ret[i].InsertTime / 1000,
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
 func (bn *Binance) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
 	query := fmt.Sprintf("&coin=%s", symbol)
 	if network != "" {
 		query += "&network=" + network
 	}
 	url := bnWalletEndpoint + "/sapi/v1/capital/deposit/address/list?" + bn.httpQuerySign(query)
-	_, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
 	if err != nil {
-		return nil, errors.New(bn.Name() + " net error! " + err.Error())
+		return nil, newNetError(bn.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, bn.handleExceptionResp("GetDepositAddress", resp)
+		return nil, bn.handleExceptionResp(httpCode, "GetDepositAddress", resp)
 	}
 	ret := []struct {
 		Network string `json:"coin"`
@@ -241,3 +295,86 @@ func (bn *Binance) GetDepositAddress(symbol, network string) ([]DepositAddress,
 
 	return daL, nil
 }
+func (bn *Binance) GetWithdrawAddressBook(symbol string) ([]WithdrawAddress, error) {
+	url := bnWalletEndpoint + "/sapi/v1/capital/withdraw/address/list?" + bn.httpQuerySign("")
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, bn.handleExceptionResp(httpCode, "GetWithdrawAddressBook", resp)
+	}
+	ret := []struct {
+		Symbol  string `json:"coin"` // 通用地址为空
+		Network string `json:"network"`
+		Addr    string `json:"address"`
+		Memo    string `json:"addressTag"`
+		Name    string `json:"name"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	waL := make([]WithdrawAddress, 0, len(ret))
+	for i := range ret {
+		if symbol != "" && ret[i].Symbol != "" && ret[i].Symbol != symbol {
+			continue
+		}
+		waL = append(waL, WithdrawAddress{
+			Symbol:  ret[i].Symbol,
+			Network: ret[i].Network,
+			Addr:    ret[i].Addr,
+			Memo:    ret[i].Memo,
+			Name:    ret[i].Name,
+		})
+	}
+	return waL, nil
+}
+func (bn *Binance) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
+	url := bnWalletEndpoint + "/sapi/v1/capital/config/getall?" + bn.httpQuerySign("")
+	httpCode, resp, err := bn.Get(url, bnApiDeadline, map[string]string{"X-MBX-APIKEY": bn.apikey})
+	if err != nil {
+		return nil, newNetError(bn.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, bn.handleExceptionResp(httpCode, "GetWalletAllAssetInfo", resp)
+	}
+	ret := []struct {
+		Symbol       string `json:"coin"`
+		BindNetworks []struct {
+			Network                 string          `json:"network"`
+			IsDepositEnabled        bool            `json:"depositEnable"`
+			IsWithdrawalEnabled     bool            `json:"withdrawEnable"`
+			WithdrawFee             decimal.Decimal `json:"withdrawFee"`
+			MinWithdrawalAmount     decimal.Decimal `json:"withdrawMin"`
+			WithdrawIntegerMultiple decimal.Decimal `json:"withdrawIntegerMultiple"` // 0.00000001
+		} `json:"networkList"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bn.Name() + " unmarshal error! " + err.Error())
+	}
+	waiMap := make(map[string]*WalletAssetInfo)
+	for _, v := range ret {
+		if len(v.BindNetworks) == 0 {
+			continue
+		}
+		wai := WalletAssetInfo{
+			Symbol:       v.Symbol,
+			BindNetworks: make(map[string]*WalletAssetBindNetworkInfo),
+		}
+		for _, vv := range v.BindNetworks {
+			var scale int32
+			if _, frac, ok := strings.Cut(vv.WithdrawIntegerMultiple.String(), "."); ok {
+				scale = int32(len(frac))
+			}
+			wai.BindNetworks[vv.Network] = &WalletAssetBindNetworkInfo{
+				IsWithdrawalEnabled: vv.IsWithdrawalEnabled,
+				IsDepositEnabled:    vv.IsDepositEnabled,
+				WithdrawScale:       scale,
+				WithdrawFee:         vv.WithdrawFee,
+				MinWithdrawalAmount: vv.MinWithdrawalAmount,
+			}
+		}
+		waiMap[v.Symbol] = &wai
+	}
+	return waiMap, nil
+}
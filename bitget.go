@@ -0,0 +1,250 @@
+package cex
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type Bitget struct {
+	Unsupported
+	Http
+	name      string
+	account   string
+	apikey    string
+	secretkey string
+	passwd    string
+	debug     bool
+
+	// spot websocket
+	spotWsPublicConn      *websocket.Conn
+	spotWsPublicConnMtx   sync.Mutex
+	spotWsPublicClosed    bool
+	spotWsPublicClosedMtx sync.RWMutex
+
+	spotWsPrivateConn      *websocket.Conn
+	spotWsPrivateConnMtx   sync.Mutex
+	spotWsPrivateClosed    bool
+	spotWsPrivateClosedMtx sync.RWMutex
+
+	// futures websocket
+	futuresWsPublicConn      *websocket.Conn
+	futuresWsPublicConnMtx   sync.Mutex
+	futuresWsPublicClosed    bool
+	futuresWsPublicClosedMtx sync.RWMutex
+	futuresWsPublicTyp       string
+
+	futuresWsPrivateConn      *websocket.Conn
+	futuresWsPrivateConnMtx   sync.Mutex
+	futuresWsPrivateClosed    bool
+	futuresWsPrivateClosedMtx sync.RWMutex
+	futuresWsPrivateTyp       string
+}
+
+type BgSubscribeArg struct {
+	Op   string          `json:"op"`
+	Args []BgSubscribeOp `json:"args"`
+}
+type BgSubscribeOp struct {
+	InstType string `json:"instType"`
+	Channel  string `json:"channel"`
+	InstId   string `json:"instId,omitempty"`
+	Coin     string `json:"coin,omitempty"`
+}
+
+var (
+	bgWsMsgPool sync.Pool
+)
+
+const bgUniEndpoint = "https://api.bitget.com"
+const bgApiDeadline = 1500 * time.Millisecond
+
+func init() {
+	bgWsMsgPool = sync.Pool{
+		New: func() any {
+			return &BitgetWsMsg{}
+		},
+	}
+}
+
+func NewBitget(account, apikey, secretkey, passwd string) *Bitget {
+	cexObj := &Bitget{
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("bitget"),
+		},
+		Unsupported: Unsupported{name: "bitget"},
+		name:        "bitget",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+		passwd:      passwd,
+	}
+	return cexObj
+}
+func (bg *Bitget) Name() string {
+	return bg.name
+}
+func (bg *Bitget) Account() string {
+	return bg.account
+}
+func (bg *Bitget) ApiKey() string {
+	return bg.apikey
+}
+func (bg *Bitget) Debug(v bool) {
+	bg.debug = v
+}
+func (bg *Bitget) withContext(ctx context.Context) Exchanger {
+	cexObj := &Bitget{
+		Http:      bg.Http.withContext(ctx),
+		name:      bg.name,
+		account:   bg.account,
+		apikey:    bg.apikey,
+		secretkey: bg.secretkey,
+		passwd:    bg.passwd,
+		debug:     bg.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
+func (bg *Bitget) Init() error {
+	bg.spotWsPublicClosed = true
+	bg.spotWsPrivateClosed = true
+	bg.futuresWsPublicClosed = true
+	bg.futuresWsPrivateClosed = true
+	return nil
+}
+
+// 签名串: timestamp + method + path(?query) + body, base64(HMAC-SHA256)
+func (bg *Bitget) buildHeaders(method, path, body string) map[string]string {
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return map[string]string{
+		"ACCESS-KEY":        bg.apikey,
+		"ACCESS-SIGN":       bg.sign(ts + method + path + body),
+		"ACCESS-TIMESTAMP":  ts,
+		"ACCESS-PASSPHRASE": bg.passwd,
+		"Content-Type":      "application/json",
+		"locale":            "en-US",
+	}
+}
+func (bg *Bitget) sign(params string) string {
+	h := hmac.New(sha256.New, []byte(bg.secretkey))
+	h.Write([]byte(params))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+func (bg *Bitget) apiError(httpStatus int, code, msg string) error {
+	return newApiError(bg.Name(), httpStatus, code, msg)
+}
+func (bg *Bitget) toStdSide(side string) string {
+	if side == "buy" {
+		return "BUY"
+	} else if side == "sell" {
+		return "SELL"
+	}
+	return ""
+}
+func (bg *Bitget) fromStdSide(side string) string {
+	if side == "BUY" {
+		return "buy"
+	} else if side == "SELL" {
+		return "sell"
+	}
+	return ""
+}
+func (bg *Bitget) toStdOrderType(orderType string) string {
+	if orderType == "limit" {
+		return "LIMIT"
+	} else if orderType == "market" {
+		return "MARKET"
+	}
+	return ""
+}
+func (bg *Bitget) fromStdOrderType(orderType string) string {
+	if orderType == "LIMIT" {
+		return "limit"
+	} else if orderType == "MARKET" {
+		return "market"
+	}
+	return ""
+}
+
+// force: gtc/ioc/fok/post_only
+func (bg *Bitget) fromStdTimeInForce(timeInForce string, postOnly bool) string {
+	if postOnly {
+		return "post_only"
+	} else if timeInForce == "IOC" {
+		return "ioc"
+	} else if timeInForce == "FOK" {
+		return "fok"
+	}
+	return "gtc"
+}
+func (bg *Bitget) toStdTimeInForce(force string) string {
+	if force == "ioc" {
+		return "IOC"
+	} else if force == "fok" {
+		return "FOK"
+	}
+	return "GTC"
+}
+
+// 现货为cancelled, 合约为canceled
+func (bg *Bitget) toStdOrderStatus(status string) string {
+	if status == "init" || status == "new" || status == "live" {
+		return "NEW"
+	} else if status == "partially_filled" {
+		return "PARTIALLY_FILLED"
+	} else if status == "filled" {
+		return "FILLED"
+	} else if status == "cancelled" || status == "canceled" {
+		return "CANCELED"
+	}
+	return ""
+}
+func (bg *Bitget) fromStdProductType(typ string) string {
+	if typ == "CM" {
+		return "COIN-FUTURES"
+	}
+	return "USDT-FUTURES"
+}
+
+// UM 为USDT, CM 为标的币 BTCUSD -> BTC
+func (bg *Bitget) marginCoin(typ, symbol string) string {
+	if typ == "CM" {
+		return strings.TrimSuffix(symbol, "USD")
+	}
+	return "USDT"
+}
+
+// from,to: FUNDING,SPOT,UM_FUTURE,CM_FUTURE,MARGIN
+func (bg *Bitget) fromStdAccountType(v string) string {
+	if v == "FUNDING" {
+		return "p2p"
+	} else if v == "SPOT" {
+		return "spot"
+	} else if v == "UM_FUTURE" {
+		return "usdt_futures"
+	} else if v == "CM_FUTURE" {
+		return "coin_futures"
+	} else if v == "MARGIN" {
+		return "crossed_margin"
+	}
+	return ""
+}
+func (bg *Bitget) toStdWithdrawStatus(v string) string {
+	if v == "pending" {
+		return "PENDING"
+	} else if v == "success" {
+		return "COMPLETED"
+	} else if v == "fail" {
+		return "FAILED"
+	}
+	return ""
+}
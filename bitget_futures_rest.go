@@ -0,0 +1,489 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (bg *Bitget) FuturesSupported(typ string) bool {
+	if typ == "UM" || typ == "CM" {
+		return true
+	}
+	return false
+}
+func (bg *Bitget) FuturesServerTime(typ string) (int64, error) {
+	return bg.serverTime()
+}
+
+// bitget UM/CM 的下单数量都是标的数量
+func (bg *Bitget) FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal {
+	return size
+}
+func (bg *Bitget) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal) decimal.Decimal {
+	return qty
+}
+func (bg *Bitget) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
+	url := bgUniEndpoint + "/api/v2/mix/market/contracts?productType=" + bg.fromStdProductType(typ)
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol       string          `json:"symbol"`
+			Base         string          `json:"baseCoin"`
+			Quote        string          `json:"quoteCoin"`
+			Status       string          `json:"symbolStatus"`
+			MinQty       decimal.Decimal `json:"minTradeNum"`
+			QtyStep      decimal.Decimal `json:"sizeMultiplier"`
+			MinNotional  decimal.Decimal `json:"minTradeUSDT"`
+			PricePlace   string          `json:"pricePlace"`
+			PriceEndStep string          `json:"priceEndStep"`
+			MaxOrderQty  decimal.Decimal `json:"maxOrderQty"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal fail! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	all := make(map[string]*FuturesExchangePairRule)
+	now := time.Now().Unix()
+	for _, pair := range recv.Data {
+		if pair.Status != "normal" {
+			continue
+		}
+		pricePlace, _ := strconv.ParseInt(pair.PricePlace, 10, 32)
+		priceEndStep, _ := strconv.ParseInt(pair.PriceEndStep, 10, 64)
+		if priceEndStep <= 0 {
+			priceEndStep = 1
+		}
+		ep := &FuturesExchangePairRule{
+			Typ:           typ,
+			Symbol:        pair.Symbol,
+			Base:          pair.Base,
+			Quote:         pair.Quote,
+			PriceTickSize: decimal.New(priceEndStep, -int32(pricePlace)),
+			MaxPrice:      decimal.NewFromFloat(9999999999999999.99),
+			MinOrderQty:   pair.MinQty,
+			MaxOrderQty:   pair.MaxOrderQty,
+			QtyStep:       pair.QtyStep,
+			MinNotional:   pair.MinNotional,
+			Time:          now,
+		}
+		ep.MinPrice = ep.PriceTickSize
+		if !ep.MaxOrderQty.IsPositive() {
+			ep.MaxOrderQty = decimal.NewFromFloat(999999999999.99)
+		}
+		all[ep.Symbol] = ep
+	}
+	return all, nil
+}
+func (bg *Bitget) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error) {
+	url := bgUniEndpoint + "/api/v2/mix/market/tickers?productType=" + bg.fromStdProductType(typ)
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol      string          `json:"symbol"`
+			Last        decimal.Decimal `json:"lastPr"`
+			Volume      decimal.Decimal `json:"baseVolume"`
+			QuoteVolume decimal.Decimal `json:"quoteVolume"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	allTk := make(map[string]Pub24hTicker, len(recv.Data))
+	for _, tk := range recv.Data {
+		v := Pub24hTicker{
+			Symbol:      tk.Symbol,
+			LastPrice:   tk.Last,
+			Volume:      tk.Volume,
+			QuoteVolume: tk.QuoteVolume,
+		}
+		if typ == "CM" {
+			v.BaseVolume = tk.Volume
+		}
+		allTk[v.Symbol] = v
+	}
+	return allTk, nil
+}
+func (bg *Bitget) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
+	url := bgUniEndpoint + "/api/v2/mix/market/ticker?productType=" + bg.fromStdProductType(typ) +
+		"&symbol=" + symbol
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			BidPrice decimal.Decimal `json:"bidPr"`
+			BidQty   decimal.Decimal `json:"bidSz"`
+			AskPrice decimal.Decimal `json:"askPr"`
+			AskQty   decimal.Decimal `json:"askSz"`
+			Time     string          `json:"ts"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return BestBidAsk{}, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return BestBidAsk{}, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	if len(recv.Data) == 0 {
+		return BestBidAsk{}, errors.New(bg.Name() + " resp empty")
+	}
+	bbo := &(recv.Data[0])
+	t, _ := strconv.ParseInt(bbo.Time, 10, 64)
+	return BestBidAsk{
+		Symbol:   symbol,
+		Time:     t,
+		BidPrice: bbo.BidPrice,
+		BidQty:   bbo.BidQty,
+		AskPrice: bbo.AskPrice,
+		AskQty:   bbo.AskQty,
+	}, nil
+}
+func (bg *Bitget) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
+	path := "/api/v2/mix/account/accounts?productType=" + bg.fromStdProductType(typ)
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol      string          `json:"marginCoin"`
+			Total       decimal.Decimal `json:"accountEquity"`
+			Avail       decimal.Decimal `json:"crossedMaxAvailable"`
+			MaxTransfer decimal.Decimal `json:"maxTransferOut"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	assetsMap := make(map[string]*FuturesAsset, len(recv.Data))
+	for _, v := range recv.Data {
+		assetsMap[v.Symbol] = &FuturesAsset{
+			Symbol:            v.Symbol,
+			Total:             v.Total,
+			Avail:             v.Avail,
+			MaxWithdrawAmount: v.MaxTransfer,
+		}
+	}
+	return assetsMap, nil
+}
+func (bg *Bitget) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosition, error) {
+	path := "/api/v2/mix/position/all-position?productType=" + bg.fromStdProductType(typ)
+	if typ == "UM" {
+		path += "&marginCoin=USDT"
+	}
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol        string          `json:"symbol"`
+			HoldSide      string          `json:"holdSide"` // long/short
+			PosMode       string          `json:"posMode"`  // one_way_mode/hedge_mode
+			PositionQty   decimal.Decimal `json:"total"`
+			EntryPrice    decimal.Decimal `json:"openPriceAvg"`
+			Leverage      decimal.Decimal `json:"leverage"`
+			LiqPrice      decimal.Decimal `json:"liquidationPrice"`
+			UnrealisedPnl decimal.Decimal `json:"unrealizedPL"`
+			Time          string          `json:"uTime"` // msec
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal fail! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	positionM := make(map[string]*FuturesPosition)
+	for _, v := range recv.Data {
+		cp := FuturesPosition{
+			Symbol:           v.Symbol,
+			Side:             bg.toStdHoldSide(v.HoldSide),
+			PositionQty:      v.PositionQty,
+			EntryPrice:       v.EntryPrice,
+			UnRealizedProfit: v.UnrealisedPnl,
+			LiqPrice:         v.LiqPrice,
+			Leverage:         v.Leverage,
+		}
+		if v.PosMode == "hedge_mode" {
+			cp.Mode = 1
+		}
+		cp.UTime, _ = strconv.ParseInt(v.Time, 10, 64)
+		positionM[cp.Symbol] = &cp
+	}
+	return positionM, nil
+}
+func (bg *Bitget) toStdHoldSide(v string) string {
+	if v == "long" {
+		return "BUY"
+	} else if v == "short" {
+		return "SELL"
+	}
+	return ""
+}
+
+// 双仓时 side 表示持仓方向: 开/平多都是buy, 开/平空都是sell, 用tradeSide区分开平
+func (bg *Bitget) FuturesPlaceOrder(typ, symbol, cltId string, /*BTCUSDT*/
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	params := map[string]any{
+		"symbol":      symbol,
+		"productType": bg.fromStdProductType(typ),
+		"marginCoin":  bg.marginCoin(typ, symbol),
+		"marginMode":  "crossed",
+		"side":        bg.fromStdSide(side),
+		"orderType":   bg.fromStdOrderType(orderType),
+		"size":        qty.String(),
+	}
+	if tradeMode == 1 {
+		params["marginMode"] = "isolated"
+	}
+	if cltId != "" {
+		params["clientOid"] = cltId
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+		params["force"] = bg.fromStdTimeInForce(timeInForce, false)
+	}
+	if positionMode == "LONG" {
+		params["side"] = "buy"
+		params["tradeSide"] = "open"
+		if side == "SELL" {
+			params["tradeSide"] = "close"
+		}
+	} else if positionMode == "SHORT" {
+		params["side"] = "sell"
+		params["tradeSide"] = "open"
+		if side == "BUY" {
+			params["tradeSide"] = "close"
+		}
+	} else if reduceOnly == 1 {
+		params["reduceOnly"] = "YES"
+	}
+	body, _ := json.Marshal(params)
+	path := "/api/v2/mix/order/place-order"
+	httpCode, resp, err := bg.Post(bgUniEndpoint+path, body, bgApiDeadline,
+		bg.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return "", newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			OrderId string `json:"orderId"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return "", bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Data.OrderId, nil
+}
+func (bg *Bitget) toStdFuturesOrder(order *BitgetFuturesOrder) *FuturesOrder {
+	status := order.Status
+	if status == "" {
+		status = order.State
+	}
+	o := &FuturesOrder{
+		Symbol:   order.Symbol,
+		OrderId:  order.OrderId,
+		ClientId: order.ClientId,
+		Status:   bg.toStdOrderStatus(status),
+		Type:     bg.toStdOrderType(order.Type),
+		Side:     bg.toStdSide(order.Side),
+		FeeAsset: order.MarginCoin,
+	}
+	o.Price, _ = decimal.NewFromString(order.Price)
+	o.Qty, _ = decimal.NewFromString(order.Size)
+	o.FilledQty, _ = decimal.NewFromString(order.FilledQty)
+	o.FilledAmt, _ = decimal.NewFromString(order.FilledAmt)
+	o.AvgPrice, _ = decimal.NewFromString(order.AvgPrice)
+	fee, _ := decimal.NewFromString(order.Fee)
+	o.FeeQty = fee.Abs()
+	o.CTime, _ = strconv.ParseInt(order.Time, 10, 64)
+	o.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+	return o
+}
+func (bg *Bitget) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOrder, error) {
+	query := "productType=" + bg.fromStdProductType(typ) + "&symbol=" + symbol
+	if orderId != "" {
+		query += "&orderId=" + orderId
+	} else if cltId != "" {
+		query += "&clientOid=" + cltId
+	} else {
+		return nil, errors.New(bg.Name() + " orderId or cltId empty!")
+	}
+	path := "/api/v2/mix/order/detail?" + query
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string             `json:"code"`
+		Msg  string             `json:"msg"`
+		Data BitgetFuturesOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return bg.toStdFuturesOrder(&recv.Data), nil
+}
+func (bg *Bitget) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error) {
+	path := "/api/v2/mix/order/orders-pending?limit=100&productType=" + bg.fromStdProductType(typ)
+	if symbol != "" {
+		path += "&symbol=" + symbol
+	}
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			List []BitgetFuturesOrder `json:"entrustedList"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	oL := make([]*FuturesOrder, 0, len(recv.Data.List))
+	for i := range recv.Data.List {
+		oL = append(oL, bg.toStdFuturesOrder(&recv.Data.List[i]))
+	}
+	return oL, nil
+}
+func (bg *Bitget) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
+	params := map[string]any{
+		"symbol":      symbol,
+		"productType": bg.fromStdProductType(typ),
+		"marginCoin":  bg.marginCoin(typ, symbol),
+	}
+	if orderId != "" {
+		params["orderId"] = orderId
+	} else if cltId != "" {
+		params["clientOid"] = cltId
+	} else {
+		return errors.New(bg.Name() + " orderId or cltId empty!")
+	}
+	return bg.simplePost("/api/v2/mix/order/cancel-order", params)
+}
+func (bg *Bitget) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	return futuresPlaceOrdersConcurrently(bg, typ, orders), nil
+}
+func (bg *Bitget) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return futuresCancelOrdersConcurrently(bg, typ, orders), nil
+}
+
+// batch-cancel-orders 不传orderIdList时撤销symbol的所有挂单
+func (bg *Bitget) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(bg.Name() + " symbol empty!")
+	}
+	params := map[string]any{
+		"symbol":      symbol,
+		"productType": bg.fromStdProductType(typ),
+		"marginCoin":  bg.marginCoin(typ, symbol),
+	}
+	return bg.simplePost("/api/v2/mix/order/batch-cancel-orders", params)
+}
+func (bg *Bitget) FuturesSwitchPositionMode(typ string, mode int) error {
+	posMode := ""
+	if mode == 1 {
+		posMode = "hedge_mode"
+	} else if mode == 0 {
+		posMode = "one_way_mode"
+	} else {
+		return errors.New("params error")
+	}
+	params := map[string]any{
+		"productType": bg.fromStdProductType(typ),
+		"posMode":     posMode,
+	}
+	return bg.simplePost("/api/v2/mix/account/set-position-mode", params)
+}
+
+// 先切换保证金模式再设置杠杆(全仓时多空杠杆相同)
+func (bg *Bitget) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int) error {
+	marginMode := "crossed"
+	if mode == 1 {
+		marginMode = "isolated"
+	}
+	params := map[string]any{
+		"symbol":      symbol,
+		"productType": bg.fromStdProductType(typ),
+		"marginCoin":  bg.marginCoin(typ, symbol),
+		"marginMode":  marginMode,
+	}
+	if err := bg.simplePost("/api/v2/mix/account/set-margin-mode", params); err != nil {
+		return err
+	}
+	params = map[string]any{
+		"symbol":      symbol,
+		"productType": bg.fromStdProductType(typ),
+		"marginCoin":  bg.marginCoin(typ, symbol),
+		"leverage":    strconv.Itoa(leverage),
+	}
+	return bg.simplePost("/api/v2/mix/account/set-leverage", params)
+}
+
+// 只需要判断code的签名POST请求
+func (bg *Bitget) simplePost(path string, params map[string]any) error {
+	body, _ := json.Marshal(params)
+	httpCode, resp, err := bg.Post(bgUniEndpoint+path, body, bgApiDeadline,
+		bg.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+func (bg *Bitget) FuturesWsPublicOpen(typ string) error {
+	if !bg.FuturesSupported(typ) {
+		return errors.New(bg.Name() + " not support futures " + typ)
+	}
+	var err error
+	bg.futuresWsPublicConn, err = bg.wsDial(bgWsPublicUrl)
+	if err != nil {
+		return errors.New(bg.Name() + " futures.ws.public con failed! " + err.Error())
+	}
+	bg.futuresWsPublicTyp = typ
+	bg.futuresWsPublicClosedMtx.Lock()
+	bg.futuresWsPublicClosed = false
+	bg.futuresWsPublicClosedMtx.Unlock()
+	return nil
+}
+func (bg *Bitget) FuturesWsPublicSubscribe(channels []string) {
+	bg.wsSend(bg.futuresWsPublicConn, &bg.futuresWsPublicConnMtx, "subscribe",
+		bg.wsPublicArgs(bg.fromStdProductType(bg.futuresWsPublicTyp), channels))
+}
+func (bg *Bitget) FuturesWsPublicUnsubscribe(channels []string) {
+	bg.wsSend(bg.futuresWsPublicConn, &bg.futuresWsPublicConnMtx, "unsubscribe",
+		bg.wsPublicArgs(bg.fromStdProductType(bg.futuresWsPublicTyp), channels))
+}
+func (bg *Bitget) FuturesWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (bg *Bitget) FuturesWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (bg *Bitget) FuturesWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (bg *Bitget) FuturesWsPublicLoop(ch chan<- any) {
+	defer bg.FuturesWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 30 * time.Second
+	pongWait := pingInterval + 3*time.Second
+	bg.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bg.wsPing(bg.futuresWsPublicConn, &bg.futuresWsPublicConnMtx,
+		bg.FuturesWsPublicIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := bg.futuresWsPublicConn.ReadMessage()
+		if err != nil {
+			if !bg.FuturesWsPublicIsClosed() {
+				ilog.Warning("%s", bg.Name()+" futures.ws.public channel read: "+err.Error())
+			}
+			break
+		}
+		if string(recv) == "pong" {
+			bg.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		bg.wsHandlePublicMsg("futures.ws.public", bg.futuresWsPublicTyp, recv, ch)
+	}
+}
+func (bg *Bitget) FuturesWsPublicIsClosed() bool {
+	bg.futuresWsPublicClosedMtx.RLock()
+	defer bg.futuresWsPublicClosedMtx.RUnlock()
+	return bg.futuresWsPublicClosed
+}
+func (bg *Bitget) FuturesWsPublicClose() {
+	bg.futuresWsPublicClosedMtx.Lock()
+	defer bg.futuresWsPublicClosedMtx.Unlock()
+	if bg.futuresWsPublicClosed {
+		return
+	}
+	bg.futuresWsPublicClosed = true
+	bg.futuresWsPublicConn.Close()
+}
+
+// = priv channel
+func (bg *Bitget) FuturesWsPrivateSupported(typ string) bool {
+	return bg.FuturesSupported(typ)
+}
+func (bg *Bitget) FuturesWsPrivateOpen(typ string) error {
+	if !bg.FuturesSupported(typ) {
+		return errors.New(bg.Name() + " not support futures " + typ)
+	}
+	var err error
+	bg.futuresWsPrivateConn, err = bg.wsAuthDial()
+	if err != nil {
+		return errors.New(bg.Name() + " futures.ws.priv con failed! " + err.Error())
+	}
+	bg.futuresWsPrivateTyp = typ
+	bg.futuresWsPrivateClosedMtx.Lock()
+	bg.futuresWsPrivateClosed = false
+	bg.futuresWsPrivateClosedMtx.Unlock()
+	return nil
+}
+
+// channels: orders, positions, balance
+func (bg *Bitget) FuturesWsPrivateSubscribe(channels []string) {
+	instType := bg.fromStdProductType(bg.futuresWsPrivateTyp)
+	args := make([]BgSubscribeOp, 0, 3)
+	for _, c := range channels {
+		if c == "orders" {
+			args = append(args, BgSubscribeOp{InstType: instType, Channel: "orders", InstId: "default"})
+		} else if c == "positions" {
+			args = append(args, BgSubscribeOp{InstType: instType, Channel: "positions", InstId: "default"})
+		} else if c == "balance" {
+			args = append(args, BgSubscribeOp{InstType: instType, Channel: "account", Coin: "default"})
+		}
+	}
+	err := bg.wsSend(bg.futuresWsPrivateConn, &bg.futuresWsPrivateConnMtx, "subscribe", args)
+	if err != nil {
+		ilog.Warning("%s", bg.Name()+" futures.ws.priv subscribe net error! "+err.Error())
+	}
+}
+func (bg *Bitget) FuturesWsPrivateIsClosed() bool {
+	bg.futuresWsPrivateClosedMtx.RLock()
+	defer bg.futuresWsPrivateClosedMtx.RUnlock()
+	return bg.futuresWsPrivateClosed
+}
+func (bg *Bitget) FuturesWsPrivateClose() {
+	bg.futuresWsPrivateClosedMtx.Lock()
+	defer bg.futuresWsPrivateClosedMtx.Unlock()
+	if bg.futuresWsPrivateClosed {
+		return
+	}
+	bg.futuresWsPrivateClosed = true
+	bg.futuresWsPrivateConn.Close()
+}
+func (bg *Bitget) FuturesWsPrivateLoop(ch chan<- any) {
+	defer bg.FuturesWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 30 * time.Second
+	pongWait := pingInterval + 3*time.Second
+	bg.futuresWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bg.wsPing(bg.futuresWsPrivateConn, &bg.futuresWsPrivateConnMtx,
+		bg.FuturesWsPrivateIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := bg.futuresWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !bg.FuturesWsPrivateIsClosed() {
+				ilog.Warning("%s", bg.Name()+" futures.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if string(recv) == "pong" {
+			bg.futuresWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		if bg.debug {
+			ilog.Rinfo("%s", bg.Name()+" futures priv ws: "+string(recv))
+		}
+		msg := bgWsMsgPool.Get().(*BitgetWsMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", bg.Name()+" futures.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Event == "error" {
+			ilog.Error("%s", bg.Name()+" futures.ws.priv recv err: "+string(recv))
+		} else if msg.Arg.Channel == "orders" {
+			bg.futuresWsHandleOrder(msg.Data, ch)
+		} else if msg.Arg.Channel == "positions" {
+			bg.futuresWsHandlePosition(msg.Data, ch)
+		} else if msg.Arg.Channel == "account" {
+			bg.futuresWsHandleBalance(msg.Data, ch)
+		}
+	END:
+		bgWsMsgPool.Put(msg)
+	}
+}
+
+// status: live/partially_filled/filled/canceled
+func (bg *Bitget) futuresWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	var orders []struct {
+		Symbol     string `json:"instId"`
+		OrderId    string `json:"orderId"`
+		ClientId   string `json:"clientOid"`
+		Price      string `json:"price"`
+		Size       string `json:"size"`
+		Type       string `json:"orderType"`
+		Side       string `json:"side"`
+		FilledQty  string `json:"accBaseVolume"`
+		AvgPrice   string `json:"priceAvg"`
+		Status     string `json:"status"`
+		MarginCoin string `json:"marginCoin"`
+		CTime      string `json:"cTime"`
+		UTime      string `json:"uTime"`
+		FeeDetail  []struct {
+			FeeCoin string `json:"feeCoin"`
+			Fee     string `json:"fee"`
+		} `json:"feeDetail"`
+	}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		ilog.Error("%s", bg.Name()+" futures.ws.priv handle order: "+err.Error())
+		return
+	}
+	for _, order := range orders {
+		o := &FuturesOrder{
+			Symbol:   order.Symbol,
+			OrderId:  order.OrderId,
+			ClientId: order.ClientId,
+			Status:   bg.toStdOrderStatus(order.Status),
+			Type:     bg.toStdOrderType(order.Type),
+			Side:     bg.toStdSide(order.Side),
+			FeeAsset: order.MarginCoin,
+		}
+		o.Price, _ = decimal.NewFromString(order.Price)
+		o.Qty, _ = decimal.NewFromString(order.Size)
+		o.FilledQty, _ = decimal.NewFromString(order.FilledQty)
+		o.AvgPrice, _ = decimal.NewFromString(order.AvgPrice)
+		o.FilledAmt = o.FilledQty.Mul(o.AvgPrice)
+		if len(order.FeeDetail) > 0 {
+			o.FeeAsset = order.FeeDetail[0].FeeCoin
+			fee, _ := decimal.NewFromString(order.FeeDetail[0].Fee)
+			o.FeeQty = fee.Abs()
+		}
+		o.CTime, _ = strconv.ParseInt(order.CTime, 10, 64)
+		o.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+		ch <- o
+	}
+}
+
+// 每次推送当前所有持仓, 平仓后推送空数组
+func (bg *Bitget) futuresWsHandlePosition(data json.RawMessage, ch chan<- any) {
+	var positions []struct {
+		Symbol        string          `json:"instId"`
+		HoldSide      string          `json:"holdSide"`
+		PosMode       string          `json:"posMode"`
+		PositionQty   decimal.Decimal `json:"total"`
+		EntryPrice    decimal.Decimal `json:"openPriceAvg"`
+		Leverage      decimal.Decimal `json:"leverage"`
+		LiqPrice      decimal.Decimal `json:"liquidationPrice"`
+		UnrealisedPnl decimal.Decimal `json:"unrealizedPL"`
+		UTime         string          `json:"uTime"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		ilog.Error("%s", bg.Name()+" futures.ws.priv handle position: "+err.Error())
+		return
+	}
+	for _, v := range positions {
+		p := &FuturesPosition{
+			Symbol:           v.Symbol,
+			Side:             bg.toStdHoldSide(v.HoldSide),
+			PositionQty:      v.PositionQty,
+			EntryPrice:       v.EntryPrice,
+			UnRealizedProfit: v.UnrealisedPnl,
+			LiqPrice:         v.LiqPrice,
+			Leverage:         v.Leverage,
+		}
+		if v.PosMode == "hedge_mode" {
+			p.Mode = 1
+		}
+		p.UTime, _ = strconv.ParseInt(v.UTime, 10, 64)
+		ch <- p
+	}
+}
+func (bg *Bitget) futuresWsHandleBalance(data json.RawMessage, ch chan<- any) {
+	var bls []struct {
+		Symbol      string          `json:"marginCoin"`
+		Equity      decimal.Decimal `json:"equity"`
+		Avail       decimal.Decimal `json:"maxOpenPosAvailable"`
+		MaxTransfer decimal.Decimal `json:"maxTransferOut"`
+	}
+	if err := json.Unmarshal(data, &bls); err != nil {
+		ilog.Error("%s", bg.Name()+" futures.ws.priv handle balance: "+err.Error())
+		return
+	}
+	for _, bl := range bls {
+		ch <- &FuturesAsset{
+			Symbol:            bl.Symbol,
+			Total:             bl.Equity,
+			Avail:             bl.Avail,
+			MaxWithdrawAmount: bl.MaxTransfer,
+		}
+	}
+}
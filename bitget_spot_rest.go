@@ -0,0 +1,385 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (bg *Bitget) SpotSupported() bool {
+	return true
+}
+func (bg *Bitget) SpotServerTime() (int64, error) {
+	return bg.serverTime()
+}
+func (bg *Bitget) serverTime() (int64, error) {
+	url := bgUniEndpoint + "/api/v2/public/time"
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return 0, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			Time string `json:"serverTime"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return 0, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return 0, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	t, _ := strconv.ParseInt(recv.Data.Time, 10, 64)
+	return t, nil
+}
+func (bg *Bitget) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
+	url := bgUniEndpoint + "/api/v2/spot/public/symbols"
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol         string          `json:"symbol"`
+			Base           string          `json:"baseCoin"`
+			Quote          string          `json:"quoteCoin"`
+			Status         string          `json:"status"`
+			MinQty         decimal.Decimal `json:"minTradeAmount"`
+			MaxQty         decimal.Decimal `json:"maxTradeAmount"`
+			PricePrecision string          `json:"pricePrecision"`
+			QtyPrecision   string          `json:"quantityPrecision"`
+			MinNotional    decimal.Decimal `json:"minTradeUSDT"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal fail! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	all := make(map[string]*SpotExchangePairRule)
+	now := time.Now().Unix()
+	for _, pair := range recv.Data {
+		if pair.Status != "online" {
+			continue
+		}
+		pricePrecision, _ := strconv.ParseInt(pair.PricePrecision, 10, 32)
+		qtyPrecision, _ := strconv.ParseInt(pair.QtyPrecision, 10, 32)
+		ep := &SpotExchangePairRule{
+			Symbol:        pair.Symbol,
+			Base:          pair.Base,
+			Quote:         pair.Quote,
+			Status:        "online",
+			PriceTickSize: decimal.New(1, -int32(pricePrecision)),
+			QtyStep:       decimal.New(1, -int32(qtyPrecision)),
+			MinOrderQty:   pair.MinQty,
+			MaxOrderQty:   pair.MaxQty,
+			MaxPrice:      decimal.NewFromFloat(9999999999999999.99),
+			MinNotional:   pair.MinNotional,
+			Time:          now,
+		}
+		ep.MinPrice = ep.PriceTickSize
+		if !ep.MinOrderQty.IsPositive() {
+			ep.MinOrderQty = ep.QtyStep
+		}
+		all[ep.Symbol] = ep
+	}
+	return all, nil
+}
+func (bg *Bitget) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
+	url := bgUniEndpoint + "/api/v2/spot/market/tickers"
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol      string          `json:"symbol"`
+			Last        decimal.Decimal `json:"lastPr"`
+			Volume      decimal.Decimal `json:"baseVolume"`
+			QuoteVolume decimal.Decimal `json:"quoteVolume"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	allTk := make(map[string]Pub24hTicker, len(recv.Data))
+	for _, tk := range recv.Data {
+		allTk[tk.Symbol] = Pub24hTicker{
+			Symbol:      tk.Symbol,
+			LastPrice:   tk.Last,
+			Volume:      tk.Volume,
+			QuoteVolume: tk.QuoteVolume,
+		}
+	}
+	return allTk, nil
+}
+func (bg *Bitget) SpotGetBBO(symbol string) (BestBidAsk, error) {
+	url := bgUniEndpoint + "/api/v2/spot/market/tickers?symbol=" + symbol
+	httpCode, resp, err := bg.Get(url, bgApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			BidPrice decimal.Decimal `json:"bidPr"`
+			BidQty   decimal.Decimal `json:"bidSz"`
+			AskPrice decimal.Decimal `json:"askPr"`
+			AskQty   decimal.Decimal `json:"askSz"`
+			Time     string          `json:"ts"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return BestBidAsk{}, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return BestBidAsk{}, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	if len(recv.Data) == 0 {
+		return BestBidAsk{}, errors.New(bg.Name() + " resp empty")
+	}
+	bbo := &(recv.Data[0])
+	t, _ := strconv.ParseInt(bbo.Time, 10, 64)
+	return BestBidAsk{
+		Symbol:   symbol,
+		Time:     t,
+		BidPrice: bbo.BidPrice,
+		BidQty:   bbo.BidQty,
+		AskPrice: bbo.AskPrice,
+		AskQty:   bbo.AskQty,
+	}, nil
+}
+func (bg *Bitget) SpotGetAllAssets() (map[string]*SpotAsset, error) {
+	path := "/api/v2/spot/account/assets"
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol string          `json:"coin"`
+			Avail  decimal.Decimal `json:"available"`
+			Frozen decimal.Decimal `json:"frozen"`
+			Locked decimal.Decimal `json:"locked"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	assetsMap := make(map[string]*SpotAsset, len(recv.Data))
+	for _, v := range recv.Data {
+		locked := v.Frozen.Add(v.Locked)
+		total := v.Avail.Add(locked)
+		if total.IsZero() {
+			continue
+		}
+		assetsMap[v.Symbol] = &SpotAsset{
+			Symbol: v.Symbol,
+			Total:  total,
+			Avail:  v.Avail,
+			Locked: locked,
+		}
+	}
+	return assetsMap, nil
+}
+
+// 市价买单size为计价币数量(amt), 市价卖单为标的数量(qty)
+func (bg *Bitget) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
+	price, amt, qty decimal.Decimal,
+	side, timeInForce, orderType string, postOnly bool) (string, error) {
+	params := map[string]any{
+		"symbol":    symbol,
+		"side":      bg.fromStdSide(side),
+		"orderType": bg.fromStdOrderType(orderType),
+	}
+	if cltId != "" {
+		params["clientOid"] = cltId
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+		params["size"] = qty.String()
+		params["force"] = bg.fromStdTimeInForce(timeInForce, postOnly)
+	} else if orderType == "MARKET" {
+		if side == "BUY" {
+			if !amt.IsPositive() {
+				return "", errors.New(bg.Name() + " market buy order need amt")
+			}
+			params["size"] = amt.String()
+		} else {
+			params["size"] = qty.String()
+		}
+	} else {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	body, _ := json.Marshal(params)
+	path := "/api/v2/spot/trade/place-order"
+	httpCode, resp, err := bg.Post(bgUniEndpoint+path, body, bgApiDeadline,
+		bg.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return "", newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			OrderId string `json:"orderId"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return "", bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Data.OrderId, nil
+}
+func (bg *Bitget) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
+	params := map[string]any{
+		"symbol": symbol,
+	}
+	if orderId != "" {
+		params["orderId"] = orderId
+	} else if cltId != "" {
+		params["clientOid"] = cltId
+	} else {
+		return errors.New(bg.Name() + " orderId or cltId empty!")
+	}
+	return bg.simplePost("/api/v2/spot/trade/cancel-order", params)
+}
+func (bg *Bitget) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(bg, orders), nil
+}
+func (bg *Bitget) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(bg, orders), nil
+}
+
+// 异步撤单, 返回成功表示已受理
+func (bg *Bitget) SpotCancelAllOrders(symbol string) error {
+	if symbol == "" {
+		return errors.New(bg.Name() + " symbol empty!")
+	}
+	params := map[string]any{
+		"symbol": symbol,
+	}
+	return bg.simplePost("/api/v2/spot/trade/cancel-symbol-order", params)
+}
+func (bg *Bitget) toStdSpotOrder(order *BitgetSpotOrder) *SpotOrder {
+	so := &SpotOrder{
+		Symbol:      order.Symbol,
+		OrderId:     order.OrderId,
+		ClientId:    order.ClientId,
+		Status:      bg.toStdOrderStatus(order.Status),
+		Type:        bg.toStdOrderType(order.Type),
+		TimeInForce: bg.toStdTimeInForce(order.Force),
+		Side:        bg.toStdSide(order.Side),
+	}
+	so.Price, _ = decimal.NewFromString(order.Price)
+	so.Qty, _ = decimal.NewFromString(order.Size)
+	so.AvgPrice, _ = decimal.NewFromString(order.AvgPrice)
+	so.FilledQty, _ = decimal.NewFromString(order.FilledQty)
+	so.FilledAmt, _ = decimal.NewFromString(order.FilledAmt)
+	so.CTime, _ = strconv.ParseInt(order.Time, 10, 64)
+	so.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+	return so
+}
+func (bg *Bitget) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
+	query := ""
+	if orderId != "" {
+		query = "orderId=" + orderId
+	} else if cltId != "" {
+		query = "clientOid=" + cltId
+	} else {
+		return nil, errors.New(bg.Name() + " orderId or cltId empty!")
+	}
+	path := "/api/v2/spot/trade/orderInfo?" + query
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Data []BitgetSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	if len(recv.Data) == 0 {
+		return nil, errors.New(bg.Name() + " resp empty")
+	}
+	return bg.toStdSpotOrder(&recv.Data[0]), nil
+}
+func (bg *Bitget) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	path := "/api/v2/spot/trade/unfilled-orders?limit=100"
+	if symbol != "" {
+		path += "&symbol=" + symbol
+	}
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Data []BitgetSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	dl := make([]*SpotOrder, 0, len(recv.Data))
+	for i := range recv.Data {
+		dl = append(dl, bg.toStdSpotOrder(&recv.Data[i]))
+	}
+	return dl, nil
+}
+func (bg *Bitget) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
+	path := "/api/v2/common/trade-rate?businessType=spot&symbol=" + symbol
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return SpotTradeFee{}, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			Maker decimal.Decimal `json:"makerFeeRate"`
+			Taker decimal.Decimal `json:"takerFeeRate"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return SpotTradeFee{}, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return SpotTradeFee{}, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return SpotTradeFee{
+		Maker: recv.Data.Maker,
+		Taker: recv.Data.Taker,
+	}, nil
+}
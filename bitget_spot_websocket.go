@@ -0,0 +1,439 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+// 公共/私有频道 spot 和 futures 共用地址, 用instType区分
+const bgWsPublicUrl = "wss://ws.bitget.com/v2/ws/public"
+const bgWsPrivateUrl = "wss://ws.bitget.com/v2/ws/private"
+
+func (bg *Bitget) wsDial(link string) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	conn, _, err := dialer.Dial(bg.wsUrl(link), nil)
+	return conn, err
+}
+
+// 签名串: timestamp(秒) + "GET" + "/user/verify"
+func (bg *Bitget) wsAuthDial() (*websocket.Conn, error) {
+	conn, err := bg.wsDial(bgWsPrivateUrl)
+	if err != nil {
+		return nil, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	arg := map[string]any{
+		"op": "login",
+		"args": []map[string]string{{
+			"apiKey":     bg.apikey,
+			"passphrase": bg.passwd,
+			"timestamp":  ts,
+			"sign":       bg.sign(ts + "GET" + "/user/verify"),
+		}},
+	}
+	req, _ := json.Marshal(arg)
+	if err = conn.WriteMessage(websocket.TextMessage, req); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	_, recv, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	msg := BitgetWsMsg{}
+	if err = easyjson.Unmarshal(recv, &msg); err != nil || msg.Event != "login" || msg.Code != 0 {
+		conn.Close()
+		return nil, errors.New("login failed: " + string(recv))
+	}
+	return conn, nil
+}
+
+// channels: orderbook5@symbolA,symbolB bbo@symbolA ticker@symbolA
+func (bg *Bitget) wsPublicArgs(instType string, channels []string) []BgSubscribeOp {
+	args := make([]BgSubscribeOp, 0, 4)
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		channel := ""
+		if arr[0] == "orderbook5" {
+			channel = "books5"
+		} else if arr[0] == "bbo" {
+			channel = "books1"
+		} else if arr[0] == "ticker" {
+			channel = "ticker"
+		} else {
+			continue
+		}
+		for sym := range strings.SplitSeq(arr[1], ",") {
+			args = append(args, BgSubscribeOp{
+				InstType: instType,
+				Channel:  channel,
+				InstId:   strings.ToUpper(sym),
+			})
+		}
+	}
+	return args
+}
+func (bg *Bitget) wsSend(conn *websocket.Conn, mtx *sync.Mutex, op string, args []BgSubscribeOp) error {
+	if len(args) == 0 {
+		return nil
+	}
+	req, _ := json.Marshal(&BgSubscribeArg{Op: op, Args: args})
+	mtx.Lock()
+	defer mtx.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, req)
+}
+
+// 30秒发送一次字符串ping, 服务端回复字符串pong
+func (bg *Bitget) wsPing(conn *websocket.Conn, mtx *sync.Mutex,
+	isClosed func() bool, pingInterval time.Duration, exitChan <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exitChan:
+			return
+		case <-ticker.C:
+			if isClosed() {
+				break
+			}
+			mtx.Lock()
+			conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+			mtx.Unlock()
+		}
+	}
+}
+func (bg *Bitget) SpotWsPublicOpen() error {
+	var err error
+	bg.spotWsPublicConn, err = bg.wsDial(bgWsPublicUrl)
+	if err != nil {
+		return errors.New(bg.Name() + " spot.ws.public con failed! " + err.Error())
+	}
+	bg.spotWsPublicClosedMtx.Lock()
+	bg.spotWsPublicClosed = false
+	bg.spotWsPublicClosedMtx.Unlock()
+	return nil
+}
+func (bg *Bitget) SpotWsPublicSubscribe(channels []string) {
+	bg.wsSend(bg.spotWsPublicConn, &bg.spotWsPublicConnMtx, "subscribe",
+		bg.wsPublicArgs("SPOT", channels))
+}
+func (bg *Bitget) SpotWsPublicUnsubscribe(channels []string) {
+	bg.wsSend(bg.spotWsPublicConn, &bg.spotWsPublicConnMtx, "unsubscribe",
+		bg.wsPublicArgs("SPOT", channels))
+}
+func (bg *Bitget) SpotWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (bg *Bitget) SpotWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (bg *Bitget) SpotWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (bg *Bitget) SpotWsPublicLoop(ch chan<- any) {
+	defer bg.SpotWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 30 * time.Second
+	pongWait := pingInterval + 3*time.Second
+	bg.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bg.wsPing(bg.spotWsPublicConn, &bg.spotWsPublicConnMtx,
+		bg.SpotWsPublicIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := bg.spotWsPublicConn.ReadMessage()
+		if err != nil {
+			if !bg.SpotWsPublicIsClosed() {
+				ilog.Warning("%s", bg.Name()+" spot.ws.public channel read: "+err.Error())
+			}
+			break
+		}
+		if string(recv) == "pong" {
+			bg.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		bg.wsHandlePublicMsg("spot.ws.public", "", recv, ch)
+	}
+}
+func (bg *Bitget) SpotWsPublicIsClosed() bool {
+	bg.spotWsPublicClosedMtx.RLock()
+	defer bg.spotWsPublicClosedMtx.RUnlock()
+	return bg.spotWsPublicClosed
+}
+func (bg *Bitget) SpotWsPublicClose() {
+	bg.spotWsPublicClosedMtx.Lock()
+	defer bg.spotWsPublicClosedMtx.Unlock()
+	if bg.spotWsPublicClosed {
+		return
+	}
+	bg.spotWsPublicClosed = true
+	bg.spotWsPublicConn.Close()
+}
+
+// spot/futures 公共频道的推送格式相同, typ 为CM时ticker填BaseVolume
+func (bg *Bitget) wsHandlePublicMsg(tag, typ string, recv []byte, ch chan<- any) {
+	msg := bgWsMsgPool.Get().(*BitgetWsMsg)
+	defer bgWsMsgPool.Put(msg)
+	msg.reset()
+	if err := easyjson.Unmarshal(recv, msg); err != nil {
+		ilog.Error("%s", bg.Name()+" "+tag+" recv invalid msg:"+string(recv))
+		return
+	}
+	if msg.Event != "" {
+		if msg.Event == "error" {
+			ilog.Error("%s", bg.Name()+" "+tag+" recv err: "+string(recv))
+		}
+		return
+	}
+	if msg.Arg.Channel == "books5" {
+		bg.wsHandleOrderBook5(msg.Arg.InstId, msg.Data, ch)
+	} else if msg.Arg.Channel == "books1" {
+		bg.wsHandleBBO(msg.Arg.InstId, msg.Data, ch)
+	} else if msg.Arg.Channel == "ticker" {
+		bg.wsHandle24hTickers(typ, msg.Data, ch)
+	}
+}
+func (bg *Bitget) wsHandleOrderBook5(symbol string, data json.RawMessage, ch chan<- any) {
+	var books []BitgetOrderBook
+	if err := json.Unmarshal(data, &books); err != nil {
+		ilog.Error("%s", bg.Name()+" ws.public "+symbol+" orderbook5 exception")
+		return
+	}
+	for i := range books {
+		depth := &books[i]
+		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+		obd.Symbol = symbol
+		obd.Level = 5
+		obd.Time, _ = strconv.ParseInt(depth.Time, 10, 64)
+		obd.Bids = obd.Bids[:0]
+		obd.Asks = obd.Asks[:0]
+		for _, v := range depth.Bids {
+			obd.Bids = append(obd.Bids, Ticker{Price: v[0], Quantity: v[1]})
+		}
+		for _, v := range depth.Asks {
+			obd.Asks = append(obd.Asks, Ticker{Price: v[0], Quantity: v[1]})
+		}
+		ch <- obd
+	}
+}
+func (bg *Bitget) wsHandleBBO(symbol string, data json.RawMessage, ch chan<- any) {
+	var books []BitgetOrderBook
+	if err := json.Unmarshal(data, &books); err != nil {
+		ilog.Error("%s", bg.Name()+" ws.public "+symbol+" bbo exception")
+		return
+	}
+	for i := range books {
+		depth := &books[i]
+		if len(depth.Bids) == 0 || len(depth.Asks) == 0 {
+			continue
+		}
+		bbo := wsPublicBBOPool.Get().(*BestBidAsk)
+		bbo.Symbol = symbol
+		bbo.Time, _ = strconv.ParseInt(depth.Time, 10, 64)
+		bbo.BidPrice = depth.Bids[0][0]
+		bbo.BidQty = depth.Bids[0][1]
+		bbo.AskPrice = depth.Asks[0][0]
+		bbo.AskQty = depth.Asks[0][1]
+		ch <- bbo
+	}
+}
+func (bg *Bitget) wsHandle24hTickers(typ string, data json.RawMessage, ch chan<- any) {
+	var tickers []BitgetTicker
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		ilog.Error("%s", bg.Name()+" ws.public ticker exception")
+		return
+	}
+	for i := range tickers {
+		tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+		tk.Symbol = tickers[i].Symbol
+		tk.LastPrice = tickers[i].Last
+		tk.Volume = tickers[i].Volume
+		tk.QuoteVolume = tickers[i].QuoteVolume
+		tk.BaseVolume = decimal.Zero
+		if typ == "CM" {
+			tk.BaseVolume = tickers[i].Volume
+		}
+		ch <- tk
+	}
+}
+
+// = priv channel
+func (bg *Bitget) SpotWsPrivateSupported() bool {
+	return true
+}
+func (bg *Bitget) SpotWsPrivateOpen() error {
+	var err error
+	bg.spotWsPrivateConn, err = bg.wsAuthDial()
+	if err != nil {
+		return errors.New(bg.Name() + " spot.ws.priv con failed! " + err.Error())
+	}
+	bg.spotWsPrivateClosedMtx.Lock()
+	bg.spotWsPrivateClosed = false
+	bg.spotWsPrivateClosedMtx.Unlock()
+	return nil
+}
+
+// channels: orders, balance
+func (bg *Bitget) SpotWsPrivateSubscribe(channels []string) {
+	args := make([]BgSubscribeOp, 0, 2)
+	for _, c := range channels {
+		if c == "orders" {
+			args = append(args, BgSubscribeOp{InstType: "SPOT", Channel: "orders", InstId: "default"})
+		} else if c == "balance" {
+			args = append(args, BgSubscribeOp{InstType: "SPOT", Channel: "account", Coin: "default"})
+		}
+	}
+	err := bg.wsSend(bg.spotWsPrivateConn, &bg.spotWsPrivateConnMtx, "subscribe", args)
+	if err != nil {
+		ilog.Warning("%s", bg.Name()+" spot.ws.priv subscribe net error! "+err.Error())
+	}
+}
+func (bg *Bitget) SpotWsPrivateIsClosed() bool {
+	bg.spotWsPrivateClosedMtx.RLock()
+	defer bg.spotWsPrivateClosedMtx.RUnlock()
+	return bg.spotWsPrivateClosed
+}
+func (bg *Bitget) SpotWsPrivateClose() {
+	bg.spotWsPrivateClosedMtx.Lock()
+	defer bg.spotWsPrivateClosedMtx.Unlock()
+	if bg.spotWsPrivateClosed {
+		return
+	}
+	bg.spotWsPrivateClosed = true
+	bg.spotWsPrivateConn.Close()
+}
+func (bg *Bitget) SpotWsPrivateLoop(ch chan<- any) {
+	defer bg.SpotWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 30 * time.Second
+	pongWait := pingInterval + 3*time.Second
+	bg.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bg.wsPing(bg.spotWsPrivateConn, &bg.spotWsPrivateConnMtx,
+		bg.SpotWsPrivateIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := bg.spotWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !bg.SpotWsPrivateIsClosed() {
+				ilog.Warning("%s", bg.Name()+" spot.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if string(recv) == "pong" {
+			bg.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		if bg.debug {
+			ilog.Rinfo("%s", bg.Name()+" spot priv ws: "+string(recv))
+		}
+		msg := bgWsMsgPool.Get().(*BitgetWsMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", bg.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Event == "error" {
+			ilog.Error("%s", bg.Name()+" spot.ws.priv recv err: "+string(recv))
+		} else if msg.Arg.Channel == "orders" {
+			bg.spotWsHandleOrder(msg.Data, ch)
+		} else if msg.Arg.Channel == "account" {
+			bg.spotWsHandleBalance(msg.Data, ch)
+		}
+	END:
+		bgWsMsgPool.Put(msg)
+	}
+}
+
+// status: live/partially_filled/filled/cancelled
+func (bg *Bitget) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	var orders []struct {
+		Symbol    string `json:"instId"`
+		OrderId   string `json:"orderId"`
+		ClientId  string `json:"clientOid"`
+		Price     string `json:"price"`
+		Size      string `json:"size"`
+		Notional  string `json:"notional"` // 市价买单的计价币数量
+		Type      string `json:"orderType"`
+		Force     string `json:"force"`
+		Side      string `json:"side"`
+		FilledQty string `json:"accBaseVolume"`
+		AvgPrice  string `json:"priceAvg"`
+		Status    string `json:"status"`
+		CTime     string `json:"cTime"`
+		UTime     string `json:"uTime"`
+		FeeDetail []struct {
+			FeeCoin string `json:"feeCoin"`
+			Fee     string `json:"totalFee"`
+		} `json:"feeDetail"`
+	}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		ilog.Error("%s", bg.Name()+" spot.ws.priv handle order: "+err.Error())
+		return
+	}
+	for _, order := range orders {
+		o := &SpotOrder{
+			Symbol:      order.Symbol,
+			OrderId:     order.OrderId,
+			ClientId:    order.ClientId,
+			Status:      bg.toStdOrderStatus(order.Status),
+			Type:        bg.toStdOrderType(order.Type),
+			TimeInForce: bg.toStdTimeInForce(order.Force),
+			Side:        bg.toStdSide(order.Side),
+		}
+		o.Price, _ = decimal.NewFromString(order.Price)
+		o.Qty, _ = decimal.NewFromString(order.Size)
+		o.FilledQty, _ = decimal.NewFromString(order.FilledQty)
+		o.AvgPrice, _ = decimal.NewFromString(order.AvgPrice)
+		o.FilledAmt = o.FilledQty.Mul(o.AvgPrice)
+		if len(order.FeeDetail) > 0 {
+			o.FeeAsset = order.FeeDetail[0].FeeCoin
+			o.FeeQty, _ = decimal.NewFromString(order.FeeDetail[0].Fee)
+		}
+		o.CTime, _ = strconv.ParseInt(order.CTime, 10, 64)
+		o.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+		ch <- o
+	}
+}
+func (bg *Bitget) spotWsHandleBalance(data json.RawMessage, ch chan<- any) {
+	var bls []struct {
+		Symbol string          `json:"coin"`
+		Avail  decimal.Decimal `json:"available"`
+		Frozen decimal.Decimal `json:"frozen"`
+		Locked decimal.Decimal `json:"locked"`
+	}
+	if err := json.Unmarshal(data, &bls); err != nil {
+		ilog.Error("%s", bg.Name()+" spot.ws.priv handle balance: "+err.Error())
+		return
+	}
+	for _, bl := range bls {
+		locked := bl.Frozen.Add(bl.Locked)
+		ch <- &SpotAsset{
+			Symbol: bl.Symbol,
+			Total:  bl.Avail.Add(locked),
+			Avail:  bl.Avail,
+			Locked: locked,
+		}
+	}
+}
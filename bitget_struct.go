@@ -0,0 +1,85 @@
+package cex
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+type BitgetWsMsg struct {
+	Event  string `json:"event,omitempty"`
+	Code   int    `json:"code,omitempty"`
+	Msg    string `json:"msg,omitempty"`
+	Action string `json:"action,omitempty"` // snapshot/update
+	Arg    struct {
+		InstType string `json:"instType,omitempty"`
+		Channel  string `json:"channel,omitempty"`
+		InstId   string `json:"instId,omitempty"`
+	} `json:"arg"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+func (v *BitgetWsMsg) reset() {
+	v.Event = ""
+	v.Code = 0
+	v.Msg = ""
+	v.Action = ""
+	v.Arg.InstType = ""
+	v.Arg.Channel = ""
+	v.Arg.InstId = ""
+	v.Data = nil
+}
+
+type BitgetOrderBook struct {
+	Bids [][2]decimal.Decimal `json:"bids,omitempty"`
+	Asks [][2]decimal.Decimal `json:"asks,omitempty"`
+	Time string               `json:"ts,omitempty"` // msec
+}
+type BitgetTicker struct {
+	Symbol      string          `json:"instId"`
+	Last        decimal.Decimal `json:"lastPr"`
+	BidPrice    decimal.Decimal `json:"bidPr"`
+	BidQty      decimal.Decimal `json:"bidSz"`
+	AskPrice    decimal.Decimal `json:"askPr"`
+	AskQty      decimal.Decimal `json:"askSz"`
+	Volume      decimal.Decimal `json:"baseVolume"`
+	QuoteVolume decimal.Decimal `json:"quoteVolume"`
+	Time        string          `json:"ts,omitempty"` // msec
+}
+
+// 订单中价格/均价等未成交时可能为空串, 用string解析
+type BitgetSpotOrder struct {
+	Symbol    string `json:"symbol"`
+	OrderId   string `json:"orderId"`
+	ClientId  string `json:"clientOid"`
+	Price     string `json:"price"`
+	Size      string `json:"size"` // 市价买单为计价币数量
+	Type      string `json:"orderType"`
+	Side      string `json:"side"`
+	Force     string `json:"force"`
+	Status    string `json:"status"`
+	AvgPrice  string `json:"priceAvg"`
+	FilledQty string `json:"baseVolume"`
+	FilledAmt string `json:"quoteVolume"`
+	Time      string `json:"cTime"`
+	UTime     string `json:"uTime"`
+}
+type BitgetFuturesOrder struct {
+	Symbol     string `json:"symbol"`
+	OrderId    string `json:"orderId"`
+	ClientId   string `json:"clientOid"`
+	Price      string `json:"price"`
+	Size       string `json:"size"`
+	Type       string `json:"orderType"`
+	Side       string `json:"side"`
+	Force      string `json:"force"`
+	Status     string `json:"status"` // orders-pending
+	State      string `json:"state"`  // detail
+	AvgPrice   string `json:"priceAvg"`
+	FilledQty  string `json:"baseVolume"`
+	FilledAmt  string `json:"quoteVolume"`
+	Fee        string `json:"fee"`
+	MarginCoin string `json:"marginCoin"`
+	Time       string `json:"cTime"`
+	UTime      string `json:"uTime"`
+}
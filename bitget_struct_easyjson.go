@@ -0,0 +1,1063 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package cex
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	decimal "github.com/shopspring/decimal"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonFc8fb673DecodeGithubComShaovieCex(in *jlexer.Lexer, out *BitgetWsMsg) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "event":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Event = string(in.String())
+			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = int(in.Int())
+			}
+		case "msg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Msg = string(in.String())
+			}
+		case "action":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Action = string(in.String())
+			}
+		case "arg":
+			easyjsonFc8fb673Decode(in, &out.Arg)
+		case "data":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Data).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673EncodeGithubComShaovieCex(out *jwriter.Writer, in BitgetWsMsg) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Event != "" {
+		const prefix string = ",\"event\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Event))
+	}
+	if in.Code != 0 {
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Code))
+	}
+	if in.Msg != "" {
+		const prefix string = ",\"msg\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Msg))
+	}
+	if in.Action != "" {
+		const prefix string = ",\"action\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Action))
+	}
+	{
+		const prefix string = ",\"arg\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonFc8fb673Encode(out, in.Arg)
+	}
+	if len(in.Data) != 0 {
+		const prefix string = ",\"data\":"
+		out.RawString(prefix)
+		out.Raw((in.Data).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BitgetWsMsg) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFc8fb673EncodeGithubComShaovieCex(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BitgetWsMsg) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFc8fb673EncodeGithubComShaovieCex(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BitgetWsMsg) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFc8fb673DecodeGithubComShaovieCex(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BitgetWsMsg) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFc8fb673DecodeGithubComShaovieCex(l, v)
+}
+func easyjsonFc8fb673Decode(in *jlexer.Lexer, out *struct {
+	InstType string `json:"instType,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	InstId   string `json:"instId,omitempty"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "instType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.InstType = string(in.String())
+			}
+		case "channel":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Channel = string(in.String())
+			}
+		case "instId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.InstId = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673Encode(out *jwriter.Writer, in struct {
+	InstType string `json:"instType,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	InstId   string `json:"instId,omitempty"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.InstType != "" {
+		const prefix string = ",\"instType\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.InstType))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.InstId != "" {
+		const prefix string = ",\"instId\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.InstId))
+	}
+	out.RawByte('}')
+}
+func easyjsonFc8fb673DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *BitgetTicker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "instId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "lastPr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Last).UnmarshalJSON(data))
+				}
+			}
+		case "bidPr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.BidPrice).UnmarshalJSON(data))
+				}
+			}
+		case "bidSz":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.BidQty).UnmarshalJSON(data))
+				}
+			}
+		case "askPr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AskPrice).UnmarshalJSON(data))
+				}
+			}
+		case "askSz":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AskQty).UnmarshalJSON(data))
+				}
+			}
+		case "baseVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "quoteVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
+		case "ts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673EncodeGithubComShaovieCex1(out *jwriter.Writer, in BitgetTicker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"instId\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"lastPr\":"
+		out.RawString(prefix)
+		out.Raw((in.Last).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"bidPr\":"
+		out.RawString(prefix)
+		out.Raw((in.BidPrice).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"bidSz\":"
+		out.RawString(prefix)
+		out.Raw((in.BidQty).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"askPr\":"
+		out.RawString(prefix)
+		out.Raw((in.AskPrice).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"askSz\":"
+		out.RawString(prefix)
+		out.Raw((in.AskQty).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"baseVolume\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"quoteVolume\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
+	if in.Time != "" {
+		const prefix string = ",\"ts\":"
+		out.RawString(prefix)
+		out.String(string(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BitgetTicker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFc8fb673EncodeGithubComShaovieCex1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BitgetTicker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFc8fb673EncodeGithubComShaovieCex1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BitgetTicker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFc8fb673DecodeGithubComShaovieCex1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BitgetTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFc8fb673DecodeGithubComShaovieCex1(l, v)
+}
+func easyjsonFc8fb673DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *BitgetSpotOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "symbol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "orderId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = string(in.String())
+			}
+		case "clientOid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Price = string(in.String())
+			}
+		case "size":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Size = string(in.String())
+			}
+		case "orderType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "side":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Side = string(in.String())
+			}
+		case "force":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Force = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "priceAvg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvgPrice = string(in.String())
+			}
+		case "baseVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FilledQty = string(in.String())
+			}
+		case "quoteVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FilledAmt = string(in.String())
+			}
+		case "cTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
+		case "uTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UTime = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673EncodeGithubComShaovieCex2(out *jwriter.Writer, in BitgetSpotOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"symbol\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"orderId\":"
+		out.RawString(prefix)
+		out.String(string(in.OrderId))
+	}
+	{
+		const prefix string = ",\"clientOid\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.String(string(in.Price))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.String(string(in.Size))
+	}
+	{
+		const prefix string = ",\"orderType\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"side\":"
+		out.RawString(prefix)
+		out.String(string(in.Side))
+	}
+	{
+		const prefix string = ",\"force\":"
+		out.RawString(prefix)
+		out.String(string(in.Force))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"priceAvg\":"
+		out.RawString(prefix)
+		out.String(string(in.AvgPrice))
+	}
+	{
+		const prefix string = ",\"baseVolume\":"
+		out.RawString(prefix)
+		out.String(string(in.FilledQty))
+	}
+	{
+		const prefix string = ",\"quoteVolume\":"
+		out.RawString(prefix)
+		out.String(string(in.FilledAmt))
+	}
+	{
+		const prefix string = ",\"cTime\":"
+		out.RawString(prefix)
+		out.String(string(in.Time))
+	}
+	{
+		const prefix string = ",\"uTime\":"
+		out.RawString(prefix)
+		out.String(string(in.UTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BitgetSpotOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFc8fb673EncodeGithubComShaovieCex2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BitgetSpotOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFc8fb673EncodeGithubComShaovieCex2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BitgetSpotOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFc8fb673DecodeGithubComShaovieCex2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BitgetSpotOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFc8fb673DecodeGithubComShaovieCex2(l, v)
+}
+func easyjsonFc8fb673DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *BitgetOrderBook) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bids":
+			if in.IsNull() {
+				in.Skip()
+				out.Bids = nil
+			} else {
+				in.Delim('[')
+				if out.Bids == nil {
+					if !in.IsDelim(']') {
+						out.Bids = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Bids = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Bids = (out.Bids)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v2 := 0
+						for !in.IsDelim(']') {
+							if v2 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v1)[v2]).UnmarshalJSON(data))
+									}
+								}
+								v2++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Bids = append(out.Bids, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "asks":
+			if in.IsNull() {
+				in.Skip()
+				out.Asks = nil
+			} else {
+				in.Delim('[')
+				if out.Asks == nil {
+					if !in.IsDelim(']') {
+						out.Asks = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Asks = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Asks = (out.Asks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v4 := 0
+						for !in.IsDelim(']') {
+							if v4 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v3)[v4]).UnmarshalJSON(data))
+									}
+								}
+								v4++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Asks = append(out.Asks, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673EncodeGithubComShaovieCex3(out *jwriter.Writer, in BitgetOrderBook) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Bids) != 0 {
+		const prefix string = ",\"bids\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Bids {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v7 := range v6 {
+					if v7 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v6)[v7]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Asks) != 0 {
+		const prefix string = ",\"asks\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v8, v9 := range in.Asks {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v10 := range v9 {
+					if v10 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v9)[v10]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Time != "" {
+		const prefix string = ",\"ts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BitgetOrderBook) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFc8fb673EncodeGithubComShaovieCex3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BitgetOrderBook) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFc8fb673EncodeGithubComShaovieCex3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BitgetOrderBook) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFc8fb673DecodeGithubComShaovieCex3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BitgetOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFc8fb673DecodeGithubComShaovieCex3(l, v)
+}
+func easyjsonFc8fb673DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *BitgetFuturesOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "symbol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "orderId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = string(in.String())
+			}
+		case "clientOid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Price = string(in.String())
+			}
+		case "size":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Size = string(in.String())
+			}
+		case "orderType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "side":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Side = string(in.String())
+			}
+		case "force":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Force = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "state":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.State = string(in.String())
+			}
+		case "priceAvg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvgPrice = string(in.String())
+			}
+		case "baseVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FilledQty = string(in.String())
+			}
+		case "quoteVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FilledAmt = string(in.String())
+			}
+		case "fee":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Fee = string(in.String())
+			}
+		case "marginCoin":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MarginCoin = string(in.String())
+			}
+		case "cTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
+		case "uTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UTime = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonFc8fb673EncodeGithubComShaovieCex4(out *jwriter.Writer, in BitgetFuturesOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"symbol\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"orderId\":"
+		out.RawString(prefix)
+		out.String(string(in.OrderId))
+	}
+	{
+		const prefix string = ",\"clientOid\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.String(string(in.Price))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.String(string(in.Size))
+	}
+	{
+		const prefix string = ",\"orderType\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"side\":"
+		out.RawString(prefix)
+		out.String(string(in.Side))
+	}
+	{
+		const prefix string = ",\"force\":"
+		out.RawString(prefix)
+		out.String(string(in.Force))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"priceAvg\":"
+		out.RawString(prefix)
+		out.String(string(in.AvgPrice))
+	}
+	{
+		const prefix string = ",\"baseVolume\":"
+		out.RawString(prefix)
+		out.String(string(in.FilledQty))
+	}
+	{
+		const prefix string = ",\"quoteVolume\":"
+		out.RawString(prefix)
+		out.String(string(in.FilledAmt))
+	}
+	{
+		const prefix string = ",\"fee\":"
+		out.RawString(prefix)
+		out.String(string(in.Fee))
+	}
+	{
+		const prefix string = ",\"marginCoin\":"
+		out.RawString(prefix)
+		out.String(string(in.MarginCoin))
+	}
+	{
+		const prefix string = ",\"cTime\":"
+		out.RawString(prefix)
+		out.String(string(in.Time))
+	}
+	{
+		const prefix string = ",\"uTime\":"
+		out.RawString(prefix)
+		out.String(string(in.UTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BitgetFuturesOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonFc8fb673EncodeGithubComShaovieCex4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BitgetFuturesOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonFc8fb673EncodeGithubComShaovieCex4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BitgetFuturesOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonFc8fb673DecodeGithubComShaovieCex4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BitgetFuturesOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonFc8fb673DecodeGithubComShaovieCex4(l, v)
+}
@@ -0,0 +1,189 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (bg *Bitget) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal) (*WithdrawReturn, error) {
+	path := "/api/v2/spot/wallet/withdrawal"
+	params := map[string]any{
+		"coin":         symbol,
+		"transferType": "on_chain",
+		"address":      addr,
+		"chain":        chain,
+		"size":         qty.String(),
+	}
+	if memo != "" {
+		params["tag"] = memo
+	}
+	body, _ := json.Marshal(params)
+	httpCode, resp, err := bg.Post(bgUniEndpoint+path, body, bgApiDeadline,
+		bg.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			OrderId string `json:"orderId"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return &WithdrawReturn{
+		Symbol: symbol,
+		WId:    recv.Data.OrderId,
+	}, nil
+}
+
+// 查询最近90天
+func (bg *Bitget) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
+	now := time.Now()
+	query := url.Values{}
+	query.Add("coin", symbol)
+	query.Add("startTime", strconv.FormatInt(now.Add(-90*24*time.Hour).UnixMilli(), 10))
+	query.Add("endTime", strconv.FormatInt(now.UnixMilli(), 10))
+	path := "/api/v2/spot/wallet/withdrawal-records?" + query.Encode()
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Id       string          `json:"orderId"`
+			Txid     string          `json:"tradeId"`
+			Symbol   string          `json:"coin"`
+			Qty      decimal.Decimal `json:"size"`
+			Fee      decimal.Decimal `json:"fee"`
+			Status   string          `json:"status"`
+			DoneTime string          `json:"uTime"` // msec
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	res := make([]WithdrawResult, 0, len(recv.Data))
+	for _, v := range recv.Data {
+		dtime, _ := strconv.ParseInt(v.DoneTime, 10, 64)
+		res = append(res, WithdrawResult{
+			WId:      v.Id,
+			Symbol:   v.Symbol,
+			Status:   bg.toStdWithdrawStatus(v.Status),
+			Qty:      v.Qty,
+			Txid:     v.Txid,
+			Fee:      v.Fee.Abs(),
+			DoneTime: dtime / 1000,
+		})
+	}
+	return res, nil
+}
+
+// typ: NORMAL, MASTER_TO_SUB, SUB_TO_MASTER
+// subAccount 为子账户UID
+func (bg *Bitget) Transfer(symbol, from, to, typ, subAccount string, qty decimal.Decimal) error {
+	if !qty.IsPositive() {
+		return errors.New(bg.Name() + " transfer qty <= 0. =" + qty.String())
+	}
+	fromType := bg.fromStdAccountType(from)
+	toType := bg.fromStdAccountType(to)
+	if fromType == "" || toType == "" {
+		return errors.New(bg.Name() + " not support account type " + from + " -> " + to)
+	}
+	params := map[string]any{
+		"fromType": fromType,
+		"toType":   toType,
+		"amount":   qty.String(),
+		"coin":     symbol,
+	}
+	if typ == "NORMAL" {
+		return bg.simplePost("/api/v2/spot/wallet/transfer", params)
+	}
+	if subAccount == "" {
+		return errors.New(bg.Name() + " sub account is empty")
+	}
+	userId, err := bg.userId()
+	if err != nil {
+		return err
+	}
+	if typ == "MASTER_TO_SUB" {
+		params["fromUserId"] = userId
+		params["toUserId"] = subAccount
+	} else if typ == "SUB_TO_MASTER" {
+		params["fromUserId"] = subAccount
+		params["toUserId"] = userId
+	} else {
+		return errors.New(bg.Name() + " not support transfer type " + typ)
+	}
+	return bg.simplePost("/api/v2/spot/wallet/subaccount-transfer", params)
+}
+func (bg *Bitget) userId() (string, error) {
+	path := "/api/v2/spot/account/info"
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return "", newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			UserId string `json:"userId"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return "", bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Data.UserId, nil
+}
+
+// network 为空时使用币种默认链
+func (bg *Bitget) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
+	query := url.Values{}
+	query.Add("coin", symbol)
+	if network != "" {
+		query.Add("chain", network)
+	}
+	path := "/api/v2/spot/wallet/deposit-address?" + query.Encode()
+	httpCode, resp, err := bg.Get(bgUniEndpoint+path, bgApiDeadline, bg.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(bg.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			Addr    string `json:"address"`
+			Network string `json:"chain"`
+			Memo    string `json:"tag"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bg.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "00000" {
+		return nil, bg.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return []DepositAddress{{
+		Addr:    recv.Data.Addr,
+		Network: recv.Data.Network,
+		Memo:    recv.Data.Memo,
+	}}, nil
+}
@@ -1,6 +1,7 @@
 package cex
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -14,6 +15,7 @@ import (
 
 type Bybit struct {
 	Unsupported
+	Http
 	name      string
 	account   string
 	apikey    string
@@ -25,11 +27,33 @@ type Bybit struct {
 	spotWsPublicConnMtx   sync.Mutex
 	spotWsPublicClosed    bool
 	spotWsPublicClosedMtx sync.RWMutex
+	spotWsOrderBooks      localOrderBooks
 
 	spotWsPrivateConn      *websocket.Conn
 	spotWsPrivateConnMtx   sync.Mutex
 	spotWsPrivateClosed    bool
 	spotWsPrivateClosedMtx sync.RWMutex
+
+	// futures websocket
+	futuresWsPublicConn      *websocket.Conn
+	futuresWsPublicConnMtx   sync.Mutex
+	futuresWsPublicClosed    bool
+	futuresWsPublicClosedMtx sync.RWMutex
+	futuresWsPublicTyp       string
+	futuresWsOrderBooks      localOrderBooks
+	futuresWsBBOBooks        localOrderBooks
+	futuresWsTickers         map[string]*Pub24hTicker // 只在loop中访问
+
+	futuresWsPrivateConn      *websocket.Conn
+	futuresWsPrivateConnMtx   sync.Mutex
+	futuresWsPrivateClosed    bool
+	futuresWsPrivateClosedMtx sync.RWMutex
+	futuresWsPrivateTyp       string
+
+	futuresWsPrivateApiConn      *websocket.Conn
+	futuresWsPrivateApiConnMtx   sync.Mutex
+	futuresWsPrivateApiClosed    bool
+	futuresWsPrivateApiClosedMtx sync.RWMutex
 }
 
 type BbSubscribeArg struct {
@@ -61,10 +85,15 @@ func init() {
 
 func NewBybit(account, apikey, secretkey string) *Bybit {
 	cexObj := &Bybit{
-		name:      "bybit",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("bybit"),
+		},
+		Unsupported: Unsupported{name: "bybit"},
+		name:        "bybit",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
 	}
 	return cexObj
 }
@@ -80,9 +109,24 @@ func (bb *Bybit) ApiKey() string {
 func (bb *Bybit) Debug(v bool) {
 	bb.debug = v
 }
+func (bb *Bybit) withContext(ctx context.Context) Exchanger {
+	cexObj := &Bybit{
+		Http:      bb.Http.withContext(ctx),
+		name:      bb.name,
+		account:   bb.account,
+		apikey:    bb.apikey,
+		secretkey: bb.secretkey,
+		debug:     bb.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (bb *Bybit) Init() error {
 	bb.spotWsPublicClosed = true
 	bb.spotWsPrivateClosed = true
+	bb.futuresWsPublicClosed = true
+	bb.futuresWsPrivateClosed = true
+	bb.futuresWsPrivateApiClosed = true
 	return nil
 }
 func (bb *Bybit) buildHeaders(query, body string) map[string]string {
@@ -103,6 +147,9 @@ func (bb *Bybit) sign(params string) string {
 func (bb *Bybit) wsSign(expires int64) string {
 	return bb.sign(fmt.Sprintf("GET/realtime%d", expires))
 }
+func (bb *Bybit) apiError(httpStatus, code int, msg string) error {
+	return newApiError(bb.Name(), httpStatus, strconv.Itoa(code), msg)
+}
 func (bb *Bybit) toStdSide(side string) string {
 	if side == "Buy" {
 		return "BUY"
@@ -149,6 +196,38 @@ func (bb *Bybit) toStdOrderStatus(status string) string {
 	}
 	return ""
 }
+func (bb *Bybit) fromStdTriggerBy(triggerBy string) string {
+	if triggerBy == "MARKET_PRICE" || triggerBy == "" {
+		return "LastPrice"
+	} else if triggerBy == "MARK_PRICE" {
+		return "MarkPrice"
+	} else if triggerBy == "INDEX_PRICE" {
+		return "IndexPrice"
+	}
+	return ""
+}
+func (bb *Bybit) toStdTriggerBy(triggerBy string) string {
+	if triggerBy == "LastPrice" {
+		return "MARKET_PRICE"
+	} else if triggerBy == "MarkPrice" {
+		return "MARK_PRICE"
+	} else if triggerBy == "IndexPrice" {
+		return "INDEX_PRICE"
+	}
+	return ""
+}
+func (bb *Bybit) toStdConditionalOrderStatus(status string) string {
+	if status == "Untriggered" {
+		return "NEW"
+	} else if status == "Triggered" {
+		return "TRIGGERED"
+	} else if status == "Deactivated" || status == "Cancelled" {
+		return "CANCELED"
+	} else if status == "Rejected" {
+		return "REJECTED"
+	}
+	return ""
+}
 func (bb *Bybit) fromStdCategory(v string) string {
 	if v == "CM" {
 		return "inverse"
@@ -167,3 +246,14 @@ func (bb *Bybit) toStdWithdrawStatus(v string) string {
 	}
 	return ""
 }
+
+// 0:unknown 1:toBeConfirmed 2:processing 3:success 4:deposit failed
+// 10011:pending to be credited to funding pool 10012:credited to funding pool successfully
+func (bb *Bybit) toStdDepositStatus(v int) string {
+	if v == 3 || v == 10012 {
+		return "CREDITED"
+	} else if v == 4 {
+		return "FAILED"
+	}
+	return "PENDING"
+}
@@ -8,8 +8,6 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
-
-	"github.com/shaovie/gutils/ihttp"
 )
 
 func (bb *Bybit) FuturesSupported(typ string) bool {
@@ -30,9 +28,9 @@ func (bb *Bybit) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal) decim
 func (bb *Bybit) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
 	typ = bb.fromStdCategory(typ)
 	url := bbUniEndpoint + "/v5/market/instruments-info?category=" + typ
-	_, resp, err := ihttp.Get(url, bbApiDeadline, nil)
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 
 	recv := struct {
@@ -62,7 +60,7 @@ func (bb *Bybit) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchange
 		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	all := make(map[string]*FuturesExchangePairRule)
 	now := time.Now().Unix()
@@ -91,9 +89,9 @@ func (bb *Bybit) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchange
 func (bb *Bybit) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
 	typ = bb.fromStdCategory(typ)
 	url := bbUniEndpoint + "/v5/market/tickers?category=" + typ + "&symbol=" + symbol
-	_, resp, err := ihttp.Get(url, bbApiDeadline, nil)
+	_, resp, err := bb.Get(url, bbApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(bb.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -129,9 +127,9 @@ func (bb *Bybit) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, erro
 		query += "&coin=USDT"
 	}
 	url := bbUniEndpoint + "/v5/account/wallet-balance?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -150,7 +148,7 @@ func (bb *Bybit) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, erro
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	if len(recv.Result.List) == 0 {
 		return nil, errors.New(bb.Name() + " resp empty")
@@ -197,7 +195,10 @@ func (bb *Bybit) FuturesPlaceOrder(typ, symbol, cltId string, /*BTCUSDT*/
 	}
 	body, _ := json.Marshal(params)
 	url := bbUniEndpoint + "/v5/order/create"
-	_, resp, err := ihttp.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return "", newNetError(bb.Name(), err)
+	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
 		Msg    string `json:"retMsg,omitempty"`
@@ -209,7 +210,7 @@ func (bb *Bybit) FuturesPlaceOrder(typ, symbol, cltId string, /*BTCUSDT*/
 		return "", errors.New(bb.Name() + " Unmarshal err! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return "", errors.New(recv.Msg)
+		return "", bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return recv.Result.OrderId, nil
 }
@@ -224,9 +225,9 @@ func (bb *Bybit) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOr
 		return nil, errors.New(bb.Name() + " orderId or cltId empty!")
 	}
 	url := bbUniEndpoint + "/v5/order/realtime?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -256,10 +257,10 @@ func (bb *Bybit) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOr
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
-	if len(recv.Result.List) == 0 {
-		return nil, errors.New(bb.Name() + " resp empty")
+	if len(recv.Result.List) == 0 { // 查不到订单时返回空列表
+		return nil, newApiError(bb.Name(), httpCode, "", "order not found")
 	}
 	order := recv.Result.List[0]
 	o := &FuturesOrder{
@@ -298,9 +299,9 @@ func (bb *Bybit) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, erro
 		query += "&settleCoin=USDT"
 	}
 	url := bbUniEndpoint + "/v5/order/realtime?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -330,7 +331,7 @@ func (bb *Bybit) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, erro
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	oL := make([]*FuturesOrder, 0, len(recv.Result.List))
 	for _, order := range recv.Result.List {
@@ -379,7 +380,10 @@ func (bb *Bybit) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
 	}
 	body, _ := json.Marshal(params)
 	url := bbUniEndpoint + "/v5/order/cancel"
-	_, resp, err := ihttp.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return newNetError(bb.Name(), err)
+	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
 		Msg    string `json:"retMsg,omitempty"`
@@ -391,10 +395,79 @@ func (bb *Bybit) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
 		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return errors.New(recv.Msg)
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return nil
 }
+func (bb *Bybit) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	reqs := make([]map[string]any, 0, len(orders))
+	results := make([]BatchOrderResult, len(orders))
+	for i := range orders {
+		o := &orders[i]
+		results[i].ClientId = o.ClientId
+		params := map[string]any{
+			"symbol":    o.Symbol,
+			"side":      bb.fromStdSide(o.Side),
+			"orderType": bb.fromStdOrderType(o.Type),
+			"qty":       o.Qty.String(),
+		}
+		if o.ClientId != "" {
+			params["orderLinkId"] = o.ClientId
+		}
+		if o.Type == "LIMIT" {
+			params["price"] = o.Price.String()
+			if o.TimeInForce != "" {
+				params["timeInForce"] = o.TimeInForce
+			}
+		}
+		if o.ReduceOnly == 1 {
+			params["reduceOnly"] = true
+		}
+		reqs = append(reqs, params)
+	}
+	bb.batchOrders(bb.fromStdCategory(typ), "/v5/order/create-batch", reqs, results)
+	return results, nil
+}
+func (bb *Bybit) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return bb.cancelOrders(bb.fromStdCategory(typ), orders)
+}
+func (bb *Bybit) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(bb.Name() + " symbol empty!")
+	}
+	return bb.cancelAllOrders(bb.fromStdCategory(typ), symbol)
+}
+func (bb *Bybit) FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*FuturesOrder, error) {
+	ordId, cltId, err := bb.amendOrder(bb.fromStdCategory(typ), symbol, orderId, cltId, price, qty)
+	if err != nil {
+		return nil, err
+	}
+	return &FuturesOrder{ // 改单为异步, 以订单推送为准
+		Symbol:   symbol,
+		OrderId:  ordId,
+		ClientId: cltId,
+		Price:    price,
+		Qty:      qty,
+		Type:     "LIMIT",
+		Side:     side,
+	}, nil
+}
+func (bb *Bybit) FuturesPlaceConditionalOrder(typ, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy, positionMode string,
+	reduceOnly int) (string, error) {
+	if bb.fromStdTriggerBy(triggerBy) == "" {
+		return "", errors.New(bb.Name() + " not support trigger by " + triggerBy)
+	}
+	return bb.placeConditionalOrder(bb.fromStdCategory(typ), symbol, cltId, triggerPrice, price, qty,
+		side, orderType, triggerBy, reduceOnly)
+}
+func (bb *Bybit) FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error) {
+	return bb.getOpenConditionalOrders(bb.fromStdCategory(typ), symbol)
+}
+func (bb *Bybit) FuturesCancelConditionalOrder(typ, symbol, orderId string) error {
+	return bb.cancelConditionalOrder(bb.fromStdCategory(typ), symbol, orderId)
+}
 func (bb *Bybit) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int) error {
 	typ = bb.fromStdCategory(typ)
 	leverageS := fmt.Sprintf("%d", leverage)
@@ -406,7 +479,7 @@ func (bb *Bybit) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int)
 	}
 	body, _ := json.Marshal(params)
 	url := bbUniEndpoint + "/v5/position/set-leverage"
-	_, resp, err := ihttp.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
 	recv := struct {
 		Code int    `json:"retCode,omitempty"`
 		Msg  string `json:"retMsg,omitempty"`
@@ -415,7 +488,7 @@ func (bb *Bybit) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int)
 		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return errors.New(recv.Msg)
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return nil
 }
@@ -426,7 +499,7 @@ func (bb *Bybit) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosit
 		query += "&settleCoin=USDT"
 	}
 	url := bbUniEndpoint + "/v5/position/list?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
 		Msg    string `json:"retMsg,omitempty"`
@@ -450,7 +523,7 @@ func (bb *Bybit) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosit
 		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	positionM := make(map[string]*FuturesPosition)
 	for _, v := range recv.Result.List {
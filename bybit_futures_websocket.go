@@ -0,0 +1,760 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/gutils"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+func (bb *Bybit) FuturesWsPublicOpen(typ string) error {
+	url := bb.wsUrl("wss://stream.bybit.com/v5/public/" + bb.fromStdCategory(typ))
+	bb.futuresWsPublicTyp = typ
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	bb.futuresWsPublicConn, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(bb.Name() + " futures.ws.public con failed! " + err.Error())
+	}
+	bb.futuresWsTickers = make(map[string]*Pub24hTicker, 8)
+	bb.futuresWsPublicClosedMtx.Lock()
+	bb.futuresWsPublicClosed = false
+	bb.futuresWsPublicClosedMtx.Unlock()
+	return nil
+}
+
+// orderbook5 用orderbook.50维护本地盘口, 推前5档
+// bbo 用orderbook.1维护本地盘口(delta可能只有单边)
+func (bb *Bybit) FuturesWsPublicSubscribe(channels []string) {
+	bb.futuresWsPublicSubscribe("subscribe", channels)
+}
+func (bb *Bybit) FuturesWsPublicUnsubscribe(channels []string) {
+	bb.futuresWsPublicSubscribe("unsubscribe", channels)
+}
+func (bb *Bybit) futuresWsPublicSubscribe(op string, channels []string) {
+	if len(channels) == 0 {
+		return
+	}
+	arg := BbSubscribeArg{Op: op}
+	arg.Id = "sub-" + gutils.RandomStr(8)
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		symbolArr := strings.SplitSeq(arr[1], ",")
+		if arr[0] == "orderbook5" {
+			for sym := range symbolArr {
+				sym = strings.ToUpper(sym)
+				if op == "subscribe" {
+					bb.futuresWsOrderBooks.add(sym, 5)
+				} else {
+					bb.futuresWsOrderBooks.remove(sym)
+				}
+				arg.Args = append(arg.Args, "orderbook.50."+sym)
+			}
+		} else if arr[0] == "bbo" {
+			for sym := range symbolArr {
+				sym = strings.ToUpper(sym)
+				if op == "subscribe" {
+					bb.futuresWsBBOBooks.add(sym, 1)
+				} else {
+					bb.futuresWsBBOBooks.remove(sym)
+				}
+				arg.Args = append(arg.Args, "orderbook.1."+sym)
+			}
+		} else if arr[0] == "ticker" {
+			for sym := range symbolArr {
+				arg.Args = append(arg.Args, "tickers."+strings.ToUpper(sym))
+			}
+		}
+	}
+	if len(arg.Args) > 0 {
+		req, _ := json.Marshal(&arg)
+		bb.futuresWsPublicConnMtx.Lock()
+		bb.futuresWsPublicConn.WriteMessage(websocket.TextMessage, req)
+		bb.futuresWsPublicConnMtx.Unlock()
+	}
+}
+func (bb *Bybit) FuturesWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (bb *Bybit) FuturesWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (bb *Bybit) FuturesWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (bb *Bybit) FuturesWsPublicLoop(ch chan<- any) {
+	defer bb.FuturesWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 20 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	bb.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		ping := `{"op":"ping"}`
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if bb.FuturesWsPublicIsClosed() {
+					break
+				}
+				bb.futuresWsPublicConnMtx.Lock()
+				bb.futuresWsPublicConn.WriteMessage(websocket.TextMessage, []byte(ping))
+				bb.futuresWsPublicConnMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	l := 0
+	for {
+		_, recv, err := bb.futuresWsPublicConn.ReadMessage()
+		if err != nil {
+			if !bb.FuturesWsPublicIsClosed() { // 并非主动断开
+				ilog.Warning("%s", bb.Name()+" futures.ws.public read: "+err.Error())
+			}
+			break
+		}
+		msg := bbWsPubMsgPool.Get().(*BybitWsPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", bb.Name()+" futures.ws.public invalid msg:"+string(recv))
+			goto END
+		}
+		l = len(msg.Topic)
+		if l > 12 && msg.Topic[:12] == "orderbook.1." {
+			if ob := bb.futuresWsHandleOrderBook(&bb.futuresWsBBOBooks, msg); ob != nil {
+				bb.futuresWsPushBBO(ob, ch)
+			}
+		} else if l > 10 && msg.Topic[:10] == "orderbook." {
+			if ob := bb.futuresWsHandleOrderBook(&bb.futuresWsOrderBooks, msg); ob != nil {
+				bb.futuresWsPushOrderBook5(ob, ch)
+			}
+		} else if l > 8 && msg.Topic[:8] == "tickers." {
+			bb.futuresWsHandle24hTickers(msg, ch)
+		} else {
+			if msg.Op == "ping" || msg.Op == "pong" {
+				bb.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+			} else if msg.Op == "subscribe" || msg.Op == "unsubscribe" { // 订阅的响应
+				if strings.Index(string(recv), "false") != -1 {
+					ilog.Error("%s", bb.Name()+" futures.ws.public recv subscribe err:"+string(recv))
+				}
+			}
+		}
+	END:
+		bbWsPubMsgPool.Put(msg)
+	}
+}
+func (bb *Bybit) FuturesWsPublicIsClosed() bool {
+	bb.futuresWsPublicClosedMtx.RLock()
+	defer bb.futuresWsPublicClosedMtx.RUnlock()
+	return bb.futuresWsPublicClosed
+}
+func (bb *Bybit) FuturesWsPublicClose() {
+	bb.futuresWsPublicClosedMtx.Lock()
+	defer bb.futuresWsPublicClosedMtx.Unlock()
+	if bb.futuresWsPublicClosed {
+		return
+	}
+	bb.futuresWsPublicClosed = true
+	bb.futuresWsPublicConn.Close()
+}
+
+// 同现货: 先推snapshot再推delta, u 必须连续, 丢包时重新订阅
+func (bb *Bybit) futuresWsHandleOrderBook(books *localOrderBooks,
+	msg *BybitWsPubMsg) *localOrderBook {
+	depth := struct {
+		Symbol   string      `json:"s"`
+		Bids     [][2]string `json:"b"`
+		Asks     [][2]string `json:"a"`
+		UpdateId int64       `json:"u"`
+	}{}
+	if err := json.Unmarshal(msg.Data, &depth); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.public "+msg.Topic+" orderbook exception")
+		return nil
+	}
+	ob := books.get(depth.Symbol)
+	if ob == nil {
+		return nil
+	}
+	if msg.Type == "snapshot" {
+		ob.reset()
+		ob.synced = true
+	} else if !ob.synced { // 等待重新订阅后的快照
+		return nil
+	} else if depth.UpdateId != ob.seqId+1 {
+		ilog.Warning("%s", bb.Name()+" futures.ws.public "+msg.Topic+" orderbook seq gap, resync")
+		bb.futuresWsResyncOrderBook(ob, msg.Topic)
+		return nil
+	}
+	ob.setLevels(depth.Bids, depth.Asks)
+	ob.seqId = depth.UpdateId
+	ob.time = msg.Time
+	if ob.crossed() {
+		ilog.Warning("%s", bb.Name()+" futures.ws.public "+msg.Topic+" orderbook crossed, resync")
+		bb.futuresWsResyncOrderBook(ob, msg.Topic)
+		return nil
+	}
+	return ob
+}
+func (bb *Bybit) futuresWsResyncOrderBook(ob *localOrderBook, topic string) {
+	ob.reset()
+	for _, op := range []string{"unsubscribe", "subscribe"} {
+		arg := BbSubscribeArg{Op: op, Args: []string{topic}}
+		arg.Id = "resync-" + gutils.RandomStr(8)
+		req, _ := json.Marshal(&arg)
+		bb.futuresWsPublicConnMtx.Lock()
+		bb.futuresWsPublicConn.WriteMessage(websocket.TextMessage, req)
+		bb.futuresWsPublicConnMtx.Unlock()
+	}
+}
+func (bb *Bybit) futuresWsPushOrderBook5(ob *localOrderBook, ch chan<- any) {
+	bids := ob.top(true, ob.level)
+	asks := ob.top(false, ob.level)
+	obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+	obd.Symbol = ob.symbol
+	obd.Level = ob.level
+	obd.Time = ob.time
+	obd.Bids = obd.Bids[:0]
+	obd.Asks = obd.Asks[:0]
+	for _, l := range bids {
+		obd.Bids = append(obd.Bids, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	for _, l := range asks {
+		obd.Asks = append(obd.Asks, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	ch <- obd
+}
+func (bb *Bybit) futuresWsPushBBO(ob *localOrderBook, ch chan<- any) {
+	bids := ob.top(true, 1)
+	asks := ob.top(false, 1)
+	if len(bids) == 0 || len(asks) == 0 {
+		return
+	}
+	obd := wsPublicBBOPool.Get().(*BestBidAsk)
+	obd.Symbol = ob.symbol
+	obd.Time = ob.time
+	obd.BidPrice = bids[0].price
+	obd.BidQty = bids[0].qty
+	obd.AskPrice = asks[0].price
+	obd.AskQty = asks[0].qty
+	ch <- obd
+}
+
+// 先推snapshot, 之后delta只包含变化的字段, 需要本地合并
+// linear: volume24h为标的数量, turnover24h为USDT
+// inverse: volume24h为合约张数, turnover24h为标的数量
+func (bb *Bybit) futuresWsHandle24hTickers(msg *BybitWsPubMsg, ch chan<- any) {
+	ticker := struct {
+		Symbol   string `json:"symbol"`
+		Last     string `json:"lastPrice"`
+		Volume   string `json:"volume24h"`
+		Turnover string `json:"turnover24h"`
+	}{}
+	if err := json.Unmarshal(msg.Data, &ticker); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.public "+msg.Topic+" ticker exception")
+		return
+	}
+	last, ok := bb.futuresWsTickers[ticker.Symbol]
+	if !ok || msg.Type == "snapshot" {
+		last = &Pub24hTicker{Symbol: ticker.Symbol}
+		bb.futuresWsTickers[ticker.Symbol] = last
+	}
+	if ticker.Last != "" {
+		last.LastPrice, _ = decimal.NewFromString(ticker.Last)
+	}
+	if ticker.Volume != "" {
+		last.Volume, _ = decimal.NewFromString(ticker.Volume)
+	}
+	if ticker.Turnover != "" {
+		if bb.futuresWsPublicTyp == "CM" {
+			last.BaseVolume, _ = decimal.NewFromString(ticker.Turnover)
+		} else {
+			last.QuoteVolume, _ = decimal.NewFromString(ticker.Turnover)
+		}
+	}
+	tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+	*tk = *last
+	ch <- tk
+}
+
+// = priv channel
+func (bb *Bybit) FuturesWsPrivateSupported(typ string) bool {
+	return true
+}
+
+// 私有推送和下单分别使用 v5/private 与 v5/trade 两个连接
+func (bb *Bybit) FuturesWsPrivateOpen(typ string) error {
+	bb.futuresWsPrivateTyp = typ
+	conn, err := bb.futuresWsAuthOpen("wss://stream.bybit.com/v5/private")
+	if err != nil {
+		return err
+	}
+	bb.futuresWsPrivateConn = conn
+	bb.futuresWsPrivateClosedMtx.Lock()
+	bb.futuresWsPrivateClosed = false
+	bb.futuresWsPrivateClosedMtx.Unlock()
+
+	conn, err = bb.futuresWsAuthOpen("wss://stream.bybit.com/v5/trade")
+	if err != nil {
+		bb.futuresWsPrivateClose()
+		return err
+	}
+	bb.futuresWsPrivateApiConn = conn
+	bb.futuresWsPrivateApiClosedMtx.Lock()
+	bb.futuresWsPrivateApiClosed = false
+	bb.futuresWsPrivateApiClosedMtx.Unlock()
+	return nil
+}
+func (bb *Bybit) futuresWsAuthOpen(link string) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	conn, _, err := dialer.Dial(bb.wsUrl(link), nil)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " futures.ws.priv connect failed! " + err.Error())
+	}
+	expires := time.Now().UnixMilli() + 3000
+	authMessage := map[string]interface{}{
+		"op":   "auth",
+		"args": []any{bb.apikey, expires, bb.wsSign(expires)},
+	}
+	req, _ := json.Marshal(authMessage)
+	conn.WriteMessage(websocket.TextMessage, req)
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, errors.New(bb.Name() + " futures.ws.priv recv auth resp err:" + err.Error())
+	}
+	// private: {"success":true,"op":"auth"}, trade: {"retCode":0,"op":"auth"}
+	resp := struct {
+		Result bool   `json:"success"`
+		Code   int    `json:"retCode"`
+		Op     string `json:"op"`
+	}{Code: -1}
+	if err = json.Unmarshal(msg, &resp); err != nil {
+		conn.Close()
+		return nil, errors.New(bb.Name() + " futures.ws.priv auth resp err:" + err.Error())
+	}
+	if resp.Op != "auth" || (resp.Result != true && resp.Code != 0) {
+		conn.Close()
+		return nil, errors.New(bb.Name() + " futures.ws.priv auth fail:" + string(msg))
+	}
+	return conn, nil
+}
+func (bb *Bybit) FuturesWsPrivateSubscribe(channels []string) {
+	category := bb.fromStdCategory(bb.futuresWsPrivateTyp)
+	arg := BbSubscribeArg{Op: "subscribe"}
+	for _, c := range channels {
+		if c == "orders" {
+			arg.Args = append(arg.Args, "order."+category)
+		} else if c == "positions" {
+			arg.Args = append(arg.Args, "position."+category)
+		} else if c == "balance" {
+			arg.Args = append(arg.Args, "wallet")
+		}
+	}
+	if len(arg.Args) > 0 {
+		req, _ := json.Marshal(&arg)
+		bb.futuresWsPrivateConnMtx.Lock()
+		if err := bb.futuresWsPrivateConn.WriteMessage(websocket.TextMessage, req); err != nil {
+			ilog.Warning("%s", bb.Name()+" futures.ws.priv subscribe net error! "+err.Error())
+		}
+		bb.futuresWsPrivateConnMtx.Unlock()
+	}
+}
+func (bb *Bybit) FuturesWsPrivateIsClosed() bool {
+	return bb.futuresWsPrivateIsClosed() || bb.futuresWsPrivateApiIsClosed()
+}
+func (bb *Bybit) futuresWsPrivateIsClosed() bool {
+	bb.futuresWsPrivateClosedMtx.RLock()
+	defer bb.futuresWsPrivateClosedMtx.RUnlock()
+	return bb.futuresWsPrivateClosed
+}
+func (bb *Bybit) futuresWsPrivateApiIsClosed() bool {
+	bb.futuresWsPrivateApiClosedMtx.RLock()
+	defer bb.futuresWsPrivateApiClosedMtx.RUnlock()
+	return bb.futuresWsPrivateApiClosed
+}
+func (bb *Bybit) FuturesWsPrivateClose() {
+	bb.futuresWsPrivateClose()
+	bb.futuresWsPrivateApiClose()
+}
+func (bb *Bybit) futuresWsPrivateClose() {
+	bb.futuresWsPrivateClosedMtx.Lock()
+	defer bb.futuresWsPrivateClosedMtx.Unlock()
+	if bb.futuresWsPrivateClosed {
+		return
+	}
+	bb.futuresWsPrivateClosed = true
+	bb.futuresWsPrivateConn.Close()
+}
+func (bb *Bybit) futuresWsPrivateApiClose() {
+	bb.futuresWsPrivateApiClosedMtx.Lock()
+	defer bb.futuresWsPrivateApiClosedMtx.Unlock()
+	if bb.futuresWsPrivateApiClosed {
+		return
+	}
+	bb.futuresWsPrivateApiClosed = true
+	bb.futuresWsPrivateApiConn.Close()
+}
+
+// 任一连接断开, 两个连接都会关闭
+func (bb *Bybit) FuturesWsPrivateLoop(ch chan<- any) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go bb.futuresWsPrivateLoop(ch, &wg)
+	go bb.futuresWsPrivateApiLoop(ch, &wg)
+	wg.Wait()
+	close(ch)
+}
+func (bb *Bybit) futuresWsPing(conn *websocket.Conn, mtx *sync.Mutex,
+	isClosed func() bool, interval time.Duration, exitChan <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ping := `{"op":"ping"}`
+	for {
+		select {
+		case <-exitChan:
+			return
+		case <-ticker.C:
+			if isClosed() {
+				break
+			}
+			mtx.Lock()
+			conn.WriteMessage(websocket.TextMessage, []byte(ping))
+			mtx.Unlock()
+		}
+	}
+}
+func (bb *Bybit) futuresWsPrivateLoop(ch chan<- any, wg *sync.WaitGroup) {
+	defer bb.FuturesWsPrivateClose()
+	defer wg.Done()
+
+	pingInterval := 20 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	bb.futuresWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bb.futuresWsPing(bb.futuresWsPrivateConn, &bb.futuresWsPrivateConnMtx,
+		bb.futuresWsPrivateIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := bb.futuresWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !bb.futuresWsPrivateIsClosed() {
+				ilog.Warning("%s", bb.Name()+" futures.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if bb.debug {
+			ilog.Rinfo("%s", bb.Name()+" futures.ws.priv: "+string(recv))
+		}
+		msg := bbWsPrivMsgPool.Get().(*BybitWsPrivMsg)
+		msg.reset()
+		if err = json.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", bb.Name()+" futures.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Op == "pong" || msg.Op == "ping" {
+			bb.futuresWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+		} else if len(msg.Topic) > 6 && msg.Topic[:6] == "order." {
+			bb.futuresWsHandleOrder(msg.Data, ch)
+		} else if len(msg.Topic) > 9 && msg.Topic[:9] == "position." {
+			bb.futuresWsHandlePosition(msg.Data, ch)
+		} else if msg.Topic == "wallet" {
+			bb.futuresWsHandleBalanceUpdate(msg.Data, ch)
+		} else {
+			if msg.Op == "subscribe" { // 订阅的响应
+				if strings.Index(string(recv), "false") != -1 {
+					ilog.Error("%s", bb.Name()+" futures.ws.priv recv subscribe err:"+string(recv))
+				}
+			}
+		}
+	END:
+		bbWsPrivMsgPool.Put(msg)
+	}
+}
+func (bb *Bybit) futuresWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	orders := []struct {
+		Category     string            `json:"category"`
+		Symbol       string            `json:"symbol"` // BTCUSDT
+		OrderId      string            `json:"orderId"`
+		ClientId     string            `json:"orderLinkId"`
+		Price        decimal.Decimal   `json:"price"`
+		Quantity     decimal.Decimal   `json:"qty"`       // 用户设置的原始订单数量
+		Type         string            `json:"orderType"` // LIMIT/MARKET
+		Side         string            `json:"side"`
+		ExecutedQty  decimal.Decimal   `json:"cumExecQty"`   // 交易的订单数量
+		CummQuoteQty decimal.Decimal   `json:"cumExecValue"` // 累计交易的金额
+		AvgPrice     decimal.Decimal   `json:"avgPrice"`
+		FeeQty       decimal.Decimal   `json:"cumExecFee"`
+		Status       string            `json:"orderStatus"`
+		Time         string            `json:"createdTime"` // msec
+		UTime        string            `json:"updatedTime"` // msec
+		FeeDetail    map[string]string `json:"cumFeeDetail"`
+	}{}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.priv handle order: "+err.Error())
+		return
+	}
+	for i := range orders {
+		order := &orders[i]
+		o := &FuturesOrder{
+			Symbol:    order.Symbol,
+			OrderId:   order.OrderId,
+			ClientId:  order.ClientId,
+			Price:     order.Price,
+			Qty:       order.Quantity,
+			FilledQty: order.ExecutedQty,
+			FilledAmt: order.CummQuoteQty,
+			AvgPrice:  order.AvgPrice,
+			Status:    bb.toStdOrderStatus(order.Status),
+			Type:      bb.toStdOrderType(order.Type),
+			Side:      bb.toStdSide(order.Side),
+		}
+		o.CTime, _ = strconv.ParseInt(order.Time, 10, 64)
+		o.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+		if order.Category == "inverse" {
+			for k, v := range order.FeeDetail {
+				o.FeeAsset = k
+				o.FeeQty, _ = decimal.NewFromString(v)
+				break
+			}
+		} else {
+			o.FeeAsset = "USDT"
+			o.FeeQty = order.FeeQty
+		}
+		ch <- o
+	}
+}
+
+// positionIdx: 0单向持仓, 1双向多仓, 2双向空仓. 平仓后side为空
+func (bb *Bybit) futuresWsHandlePosition(data json.RawMessage, ch chan<- any) {
+	positions := []struct {
+		Symbol        string          `json:"symbol"`
+		Side          string          `json:"side"`
+		PositionIdx   int             `json:"positionIdx"`
+		PositionQty   decimal.Decimal `json:"size"`
+		EntryPrice    decimal.Decimal `json:"entryPrice"`
+		Leverage      decimal.Decimal `json:"leverage"`
+		LiqPrice      decimal.Decimal `json:"liqPrice"`
+		UnrealisedPnl decimal.Decimal `json:"unrealisedPnl"`
+		UTime         string          `json:"updatedTime"` // msec
+	}{}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.priv handle position: "+err.Error())
+		return
+	}
+	for _, v := range positions {
+		p := &FuturesPosition{
+			Symbol:           v.Symbol,
+			Side:             bb.toStdSide(v.Side),
+			PositionQty:      v.PositionQty,
+			EntryPrice:       v.EntryPrice,
+			UnRealizedProfit: v.UnrealisedPnl,
+			LiqPrice:         v.LiqPrice,
+			Leverage:         v.Leverage,
+		}
+		if v.PositionIdx > 0 {
+			p.Mode = 1
+		}
+		p.UTime, _ = strconv.ParseInt(v.UTime, 10, 64)
+		ch <- p
+	}
+}
+
+// 同FuturesGetAllAssets, 统一账户 Avail 取 walletBalance
+func (bb *Bybit) futuresWsHandleBalanceUpdate(data json.RawMessage, ch chan<- any) {
+	bls := []struct {
+		Coin []struct {
+			Symbol string          `json:"coin"`
+			Avail  decimal.Decimal `json:"walletBalance"`
+		} `json:"coin"`
+	}{}
+	if err := json.Unmarshal(data, &bls); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.priv handle wallet: "+err.Error())
+		return
+	}
+	for i := range bls {
+		for _, as := range bls[i].Coin {
+			ch <- &FuturesAsset{
+				Symbol:            as.Symbol,
+				Total:             as.Avail,
+				Avail:             as.Avail,
+				MaxWithdrawAmount: decimal.NewFromInt(-999999999),
+			}
+		}
+	}
+}
+func (bb *Bybit) futuresWsPrivateApiLoop(ch chan<- any, wg *sync.WaitGroup) {
+	defer bb.FuturesWsPrivateClose()
+	defer wg.Done()
+
+	pingInterval := 20 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	bb.futuresWsPrivateApiConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go bb.futuresWsPing(bb.futuresWsPrivateApiConn, &bb.futuresWsPrivateApiConnMtx,
+		bb.futuresWsPrivateApiIsClosed, pingInterval, pingExit)
+
+	type Msg struct {
+		ReqId string          `json:"reqId"`
+		Code  int             `json:"retCode"`
+		Msg   string          `json:"retMsg"`
+		Op    string          `json:"op"`
+		Data  json.RawMessage `json:"data"`
+	}
+	for {
+		_, recv, err := bb.futuresWsPrivateApiConn.ReadMessage()
+		if err != nil {
+			if !bb.futuresWsPrivateApiIsClosed() {
+				ilog.Warning("%s", bb.Name()+" futures.ws.priv.api channel read: "+err.Error())
+			}
+			break
+		}
+		if bb.debug {
+			ilog.Rinfo("%s", bb.Name()+" futures.ws.priv.api: "+string(recv))
+		}
+		msg := Msg{}
+		if err = json.Unmarshal(recv, &msg); err != nil {
+			ilog.Error("%s", bb.Name()+" futures.ws.priv.api recv invalid msg:"+string(recv))
+			continue
+		}
+		errS := ""
+		if msg.Code != 0 {
+			errS = strconv.Itoa(msg.Code) + ":" + msg.Msg
+		}
+		if msg.Op == "pong" || msg.Op == "ping" {
+			bb.futuresWsPrivateApiConn.SetReadDeadline(time.Now().Add(pongWait))
+		} else if msg.Op == "order.create" {
+			bb.futuresWsHandlePlaceOrderResp(msg.ReqId, errS, msg.Data, ch)
+		} else if msg.Op == "order.cancel" {
+			bb.futuresWsHandleCancelOrderResp(errS)
+		} else {
+			ilog.Error("%s", bb.Name()+" futures.ws.priv.api recv unknown msg: "+string(recv))
+		}
+	}
+}
+func (bb *Bybit) futuresWsHandlePlaceOrderResp(reqId, errS string,
+	data json.RawMessage, ch chan<- any) {
+	if errS != "" {
+		ch <- &FuturesOrder{
+			RequestId: reqId,
+			Err:       errS,
+		}
+		return
+	}
+	ret := struct {
+		OrderId  string `json:"orderId"`
+		ClientId string `json:"orderLinkId"`
+	}{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		ilog.Error("%s", bb.Name()+" futures.ws.priv.api handle place order resp: "+err.Error())
+		return
+	}
+	ch <- &FuturesOrder{
+		RequestId: reqId,
+		OrderId:   ret.OrderId,
+		ClientId:  ret.ClientId,
+	}
+}
+func (bb *Bybit) futuresWsHandleCancelOrderResp(errS string) {
+	if errS != "" {
+		ilog.Error("%s", bb.Name()+" futures cancel order fail! "+errS)
+	}
+}
+func (bb *Bybit) futuresWsSendApiReq(op string, params map[string]any) (string, error) {
+	if bb.futuresWsPrivateApiIsClosed() {
+		return "", errors.New(bb.Name() + " futures.ws.priv.api ws closed")
+	}
+	reqId := "ford-" + gutils.RandomStr(14)
+	if op == "order.cancel" {
+		reqId = "fcle-" + gutils.RandomStr(14)
+	}
+	req := map[string]any{
+		"reqId": reqId,
+		"header": map[string]string{
+			"X-BAPI-TIMESTAMP":   strconv.FormatInt(time.Now().UnixMilli(), 10),
+			"X-BAPI-RECV-WINDOW": "3000",
+		},
+		"op":   op,
+		"args": []any{params},
+	}
+	reqJson, _ := json.Marshal(req)
+
+	bb.futuresWsPrivateApiConnMtx.Lock()
+	defer bb.futuresWsPrivateApiConnMtx.Unlock()
+	if err := bb.futuresWsPrivateApiConn.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(bb.Name() + " send fail: " + err.Error())
+	}
+	return reqId, nil
+}
+
+// priv ws api
+func (bb *Bybit) FuturesWsPlaceOrder(symbol, cltId string,
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	if !qty.IsPositive() {
+		return "", errors.New("qty too small! qty=" + qty.String())
+	}
+	params := map[string]any{
+		"category":  bb.fromStdCategory(bb.futuresWsPrivateTyp),
+		"symbol":    symbol,
+		"side":      bb.fromStdSide(side),
+		"orderType": bb.fromStdOrderType(orderType),
+		"qty":       qty.String(),
+	}
+	if cltId != "" {
+		params["orderLinkId"] = cltId
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+		if timeInForce != "" {
+			params["timeInForce"] = timeInForce
+		}
+	}
+	if positionMode == "LONG" {
+		params["positionIdx"] = 1
+	} else if positionMode == "SHORT" {
+		params["positionIdx"] = 2
+	}
+	if reduceOnly == 1 {
+		params["reduceOnly"] = true
+	}
+	return bb.futuresWsSendApiReq("order.create", params)
+}
+func (bb *Bybit) FuturesWsCancelOrder(symbol, orderId, cltId string) (string, error) {
+	params := map[string]any{
+		"category": bb.fromStdCategory(bb.futuresWsPrivateTyp),
+		"symbol":   symbol,
+	}
+	if orderId != "" {
+		params["orderId"] = orderId
+	} else if cltId != "" {
+		params["orderLinkId"] = cltId
+	} else {
+		return "", errors.New("orderId or clientId is empty")
+	}
+	return bb.futuresWsSendApiReq("order.cancel", params)
+}
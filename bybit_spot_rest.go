@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -18,9 +17,9 @@ func (bb *Bybit) SpotServerTime() (int64, error) {
 }
 func (bb *Bybit) serverTime() (int64, error) {
 	url := bbUniEndpoint + "/v5/market/time"
-	_, resp, err := ihttp.Get(url, bbApiDeadline, nil)
+	_, resp, err := bb.Get(url, bbApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(bb.Name() + " net error! " + err.Error())
+		return 0, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Result struct {
@@ -35,9 +34,9 @@ func (bb *Bybit) serverTime() (int64, error) {
 }
 func (bb *Bybit) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	url := bbUniEndpoint + "/v5/market/instruments-info?category=spot&limit=1000"
-	_, resp, err := ihttp.Get(url, bbApiDeadline, nil)
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 
 	recv := struct {
@@ -67,7 +66,7 @@ func (bb *Bybit) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	all := make(map[string]*SpotExchangePairRule)
 	now := time.Now().Unix()
@@ -96,9 +95,9 @@ func (bb *Bybit) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 }
 func (bb *Bybit) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	url := bbUniEndpoint + "/v5/market/tickers?category=spot&symbol=" + symbol
-	_, resp, err := ihttp.Get(url, bbApiDeadline, nil)
+	_, resp, err := bb.Get(url, bbApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(bb.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -131,9 +130,9 @@ func (bb *Bybit) SpotGetBBO(symbol string) (BestBidAsk, error) {
 func (bb *Bybit) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	query := "accountType=UNIFIED"
 	url := bbUniEndpoint + "/v5/account/wallet-balance?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -154,7 +153,7 @@ func (bb *Bybit) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	if len(recv.Result.List) == 0 {
 		return nil, errors.New(bb.Name() + " resp empty")
@@ -210,7 +209,7 @@ func (bb *Bybit) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 	}
 	body, _ := json.Marshal(params)
 	url := bbUniEndpoint + "/v5/order/create"
-	_, resp, err := ihttp.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
 		Msg    string `json:"retMsg,omitempty"`
@@ -222,7 +221,7 @@ func (bb *Bybit) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
 		return "", errors.New(bb.Name() + " Unmarshal err! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return "", errors.New(recv.Msg)
+		return "", bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return recv.Result.OrderId, nil
 }
@@ -241,7 +240,7 @@ func (bb *Bybit) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId strin
 	}
 	body, _ := json.Marshal(params)
 	url := bbUniEndpoint + "/v5/order/cancel"
-	_, resp, err := ihttp.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
 		Msg    string `json:"retMsg,omitempty"`
@@ -253,10 +252,240 @@ func (bb *Bybit) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId strin
 		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return errors.New(recv.Msg)
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	return nil
 }
+func (bb *Bybit) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	reqs := make([]map[string]any, 0, len(orders))
+	results := make([]BatchOrderResult, len(orders))
+	for i := range orders {
+		o := &orders[i]
+		results[i].ClientId = o.ClientId
+		params := map[string]any{
+			"symbol":     o.Symbol,
+			"isLeverage": 0,
+			"side":       bb.fromStdSide(o.Side),
+			"orderType":  bb.fromStdOrderType(o.Type),
+		}
+		if o.ClientId != "" {
+			params["orderLinkId"] = o.ClientId
+		}
+		if o.Type == "MARKET" {
+			if o.Amt.IsPositive() {
+				params["qty"] = o.Amt.String()
+				params["marketUnit"] = "quoteCoin"
+			} else {
+				params["qty"] = o.Qty.String()
+				params["marketUnit"] = "baseCoin"
+			}
+		} else {
+			params["qty"] = o.Qty.String()
+			params["price"] = o.Price.String()
+			if o.TimeInForce != "" {
+				params["timeInForce"] = o.TimeInForce
+			}
+			if o.Type == "LIMIT" && o.PostOnly {
+				params["timeInForce"] = "PostOnly"
+			}
+		}
+		reqs = append(reqs, params)
+	}
+	bb.batchOrders("spot", "/v5/order/create-batch", reqs, results)
+	return results, nil
+}
+func (bb *Bybit) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return bb.cancelOrders("spot", orders)
+}
+func (bb *Bybit) cancelOrders(category string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	reqs := make([]map[string]any, 0, len(orders))
+	results := make([]BatchOrderResult, len(orders))
+	for i := range orders {
+		results[i].OrderId = orders[i].OrderId
+		results[i].ClientId = orders[i].ClientId
+		params := map[string]any{
+			"symbol": orders[i].Symbol,
+		}
+		if orders[i].OrderId != "" {
+			params["orderId"] = orders[i].OrderId
+		} else {
+			params["orderLinkId"] = orders[i].ClientId
+		}
+		reqs = append(reqs, params)
+	}
+	bb.batchOrders(category, "/v5/order/cancel-batch", reqs, results)
+	return results, nil
+}
+
+// 每批spot最多10个, linear/inverse最多20个, 结果与请求顺序一致
+func (bb *Bybit) batchOrders(category, path string, reqs []map[string]any, results []BatchOrderResult) {
+	size := 20
+	if category == "spot" {
+		size = 10
+	}
+	for start := 0; start < len(reqs); start += size {
+		end := min(start+size, len(reqs))
+		body, _ := json.Marshal(map[string]any{
+			"category": category,
+			"request":  reqs[start:end],
+		})
+		url := bbUniEndpoint + path
+		httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+		recv := struct {
+			Code   int    `json:"retCode,omitempty"`
+			Msg    string `json:"retMsg,omitempty"`
+			Result struct {
+				List []struct {
+					OrderId  string `json:"orderId"`
+					ClientId string `json:"orderLinkId"`
+				} `json:"list"`
+			} `json:"result"`
+			ExtInfo struct {
+				List []struct {
+					Code int    `json:"code"`
+					Msg  string `json:"msg"`
+				} `json:"list"`
+			} `json:"retExtInfo"`
+		}{}
+		if err != nil {
+			err = newNetError(bb.Name(), err)
+		} else if e := json.Unmarshal(resp, &recv); e != nil {
+			err = errors.New(bb.Name() + " Unmarshal err! " + e.Error())
+		} else if recv.Code != 0 {
+			err = bb.apiError(httpCode, recv.Code, recv.Msg)
+		} else if len(recv.Result.List) != end-start || len(recv.ExtInfo.List) != end-start {
+			err = errors.New(bb.Name() + " batch resp size not match! " + string(resp))
+		}
+		for i := start; i < end; i++ {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			ext := recv.ExtInfo.List[i-start]
+			if ext.Code != 0 {
+				results[i].Err = bb.apiError(httpCode, ext.Code, ext.Msg)
+				continue
+			}
+			results[i].OrderId = recv.Result.List[i-start].OrderId
+			results[i].ClientId = recv.Result.List[i-start].ClientId
+		}
+	}
+}
+func (bb *Bybit) SpotCancelAllOrders(symbol string) error {
+	return bb.cancelAllOrders("spot", symbol)
+}
+func (bb *Bybit) cancelAllOrders(category, symbol string) error {
+	params := map[string]any{
+		"category": category,
+	}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/order/cancel-all"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"retCode,omitempty"`
+		Msg  string `json:"retMsg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+
+// DCP: 私有ws断开后timeout秒撤销所有挂单, 需要在账户中开启DCP
+func (bb *Bybit) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	if timeout <= 0 {
+		return errors.New(bb.Name() + " dcp can not be disabled by api")
+	}
+	product := "DERIVATIVES"
+	if typ == "SPOT" {
+		product = "SPOT"
+	}
+	params := map[string]any{
+		"product":    product,
+		"timeWindow": timeout, // 3~300秒
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/order/disconnected-cancel-all"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"retCode,omitempty"`
+		Msg  string `json:"retMsg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+func (bb *Bybit) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	ordId, cltId, err := bb.amendOrder("spot", symbol, orderId, cltId, price, qty)
+	if err != nil {
+		return nil, err
+	}
+	return &SpotOrder{ // 改单为异步, 以订单推送为准
+		Symbol:   symbol,
+		OrderId:  ordId,
+		ClientId: cltId,
+		Price:    price,
+		Qty:      qty,
+		Type:     "LIMIT",
+		Side:     side,
+	}, nil
+}
+
+// 返回 orderId, orderLinkId
+func (bb *Bybit) amendOrder(category, symbol, orderId, cltId string,
+	price, qty decimal.Decimal) (string, string, error) {
+	params := map[string]any{
+		"category": category,
+		"symbol":   symbol,
+		"qty":      qty.String(),
+		"price":    price.String(),
+	}
+	if orderId != "" {
+		params["orderId"] = orderId
+	} else if cltId != "" {
+		params["orderLinkId"] = cltId
+	} else {
+		return "", "", errors.New(bb.Name() + " orderId or cltId empty!")
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/order/amend"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return "", "", newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			OrderId  string `json:"orderId"`
+			ClientId string `json:"orderLinkId"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", "", errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return "", "", bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Result.OrderId, recv.Result.ClientId, nil
+}
 func (bb *Bybit) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	query := "category=spot&symbol=" + symbol
 	if orderId != "" {
@@ -267,9 +496,9 @@ func (bb *Bybit) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 		return nil, errors.New(bb.Name() + " orderId or cltId empty!")
 	}
 	url := bbUniEndpoint + "/v5/order/realtime?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -298,10 +527,10 @@ func (bb *Bybit) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
-	if len(recv.Result.List) == 0 {
-		return nil, errors.New(bb.Name() + " resp empty")
+	if len(recv.Result.List) == 0 { // 查不到订单时返回空列表
+		return nil, newApiError(bb.Name(), httpCode, "", "order not found")
 	}
 	order := recv.Result.List[0]
 	o := &SpotOrder{
@@ -329,9 +558,9 @@ func (bb *Bybit) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 func (bb *Bybit) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	query := "category=spot&limit=50&symbol=" + symbol
 	url := bbUniEndpoint + "/v5/order/realtime?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -360,10 +589,7 @@ func (bb *Bybit) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return nil, errors.New(bb.Name() + " api err! " + recv.Msg)
-	}
-	if len(recv.Result.List) == 0 {
-		return nil, errors.New(bb.Name() + " resp empty")
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	dl := make([]*SpotOrder, 0, len(recv.Result.List))
 	for _, order := range recv.Result.List {
@@ -391,12 +617,187 @@ func (bb *Bybit) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	}
 	return dl, nil
 }
+func (bb *Bybit) SpotPlaceConditionalOrder(symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string) (string, error) {
+	if triggerBy != "" && triggerBy != "MARKET_PRICE" {
+		return "", errors.New(bb.Name() + " spot not support trigger by " + triggerBy)
+	}
+	return bb.placeConditionalOrder("spot", symbol, cltId, triggerPrice, price, qty,
+		side, orderType, triggerBy, 0)
+}
+func (bb *Bybit) SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error) {
+	return bb.getOpenConditionalOrders("spot", symbol)
+}
+func (bb *Bybit) SpotCancelConditionalOrder(symbol, orderId string) error {
+	return bb.cancelConditionalOrder("spot", symbol, orderId)
+}
+
+// 现货/合约共用, 现货的触发方向由交易所根据下单时的价格判断
+func (bb *Bybit) placeConditionalOrder(category, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string,
+	reduceOnly int) (string, error) {
+	isStop, isLimit, ok := parseConditionalOrderType(orderType)
+	if !ok {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	params := map[string]any{
+		"category":     category,
+		"symbol":       symbol,
+		"side":         bb.fromStdSide(side),
+		"orderType":    "Market",
+		"qty":          qty.String(),
+		"triggerPrice": triggerPrice.String(),
+	}
+	if category == "spot" {
+		params["isLeverage"] = 0
+		params["orderFilter"] = "StopOrder"
+		params["marketUnit"] = "baseCoin"
+	} else {
+		params["triggerBy"] = bb.fromStdTriggerBy(triggerBy)
+		params["triggerDirection"] = 2
+		if conditionalTriggerRise(isStop, side) {
+			params["triggerDirection"] = 1
+		}
+	}
+	if isLimit {
+		params["orderType"] = "Limit"
+		params["price"] = price.String()
+	}
+	if cltId != "" {
+		params["orderLinkId"] = cltId
+	}
+	if reduceOnly == 1 {
+		params["reduceOnly"] = true
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/order/create"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return "", newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			OrderId string `json:"orderId"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return "", bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Result.OrderId, nil
+}
+func (bb *Bybit) getOpenConditionalOrders(category, symbol string) ([]*ConditionalOrder, error) {
+	query := "category=" + category + "&limit=50&orderFilter=StopOrder"
+	if symbol != "" {
+		query += "&symbol=" + symbol
+	} else if category == "linear" {
+		query += "&settleCoin=USDT"
+	}
+	url := bbUniEndpoint + "/v5/order/realtime?" + query
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			List []struct {
+				Symbol           string          `json:"symbol"`
+				OrderId          string          `json:"orderId"`
+				ClientId         string          `json:"orderLinkId"`
+				Price            decimal.Decimal `json:"price"`
+				Quantity         decimal.Decimal `json:"qty"`
+				Type             string          `json:"orderType"`
+				Side             string          `json:"side"`
+				TriggerPrice     decimal.Decimal `json:"triggerPrice"`
+				TriggerBy        string          `json:"triggerBy"`
+				TriggerDirection int             `json:"triggerDirection"` // 1:上涨触发 2:下跌触发
+				LastPrice        decimal.Decimal `json:"lastPriceOnCreated"`
+				Status           string          `json:"orderStatus"`
+				Time             string          `json:"createdTime"`
+			} `json:"list,omitempty"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	oL := make([]*ConditionalOrder, 0, len(recv.Result.List))
+	for _, order := range recv.Result.List {
+		co := &ConditionalOrder{
+			Symbol:       order.Symbol,
+			OrderId:      order.OrderId,
+			ClientId:     order.ClientId,
+			Side:         bb.toStdSide(order.Side),
+			TriggerPrice: order.TriggerPrice,
+			TriggerBy:    bb.toStdTriggerBy(order.TriggerBy),
+			Qty:          order.Quantity,
+			Status:       bb.toStdConditionalOrderStatus(order.Status),
+		}
+		if co.TriggerBy == "" {
+			co.TriggerBy = "MARKET_PRICE"
+		}
+		co.CTime, _ = strconv.ParseInt(order.Time, 10, 64)
+		// bybit不区分止损止盈, 根据触发方向推算
+		rise := order.TriggerDirection == 1
+		if order.TriggerDirection == 0 {
+			rise = order.TriggerPrice.GreaterThan(order.LastPrice)
+		}
+		if rise == conditionalTriggerRise(true, co.Side) {
+			co.Type = "STOP"
+		} else {
+			co.Type = "TAKE_PROFIT"
+		}
+		if order.Type == "Limit" {
+			co.Type += "_LIMIT"
+			co.Price = order.Price
+		} else {
+			co.Type += "_MARKET"
+		}
+		oL = append(oL, co)
+	}
+	return oL, nil
+}
+func (bb *Bybit) cancelConditionalOrder(category, symbol, orderId string) error {
+	params := map[string]any{
+		"category": category,
+		"symbol":   symbol,
+		"orderId":  orderId,
+	}
+	if category == "spot" {
+		params["orderFilter"] = "StopOrder"
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/order/cancel"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return newNetError(bb.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"retCode,omitempty"`
+		Msg  string `json:"retMsg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return bb.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
 func (bb *Bybit) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 	query := "category=spot&symbol=" + symbol
 	url := bbUniEndpoint + "/v5/account/fee-rate?" + query
-	_, resp, err := ihttp.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
 	if err != nil {
-		return SpotTradeFee{}, errors.New(bb.Name() + " net error! " + err.Error())
+		return SpotTradeFee{}, newNetError(bb.Name(), err)
 	}
 	recv := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -413,7 +814,7 @@ func (bb *Bybit) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 		return SpotTradeFee{}, errors.New(bb.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != 0 {
-		return SpotTradeFee{}, errors.New(bb.Name() + " api err! " + recv.Msg)
+		return SpotTradeFee{}, bb.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	if len(recv.Result.List) == 0 {
 		return SpotTradeFee{}, errors.New(bb.Name() + " resp empty")
@@ -429,3 +830,58 @@ func (bb *Bybit) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
 	}
 	return SpotTradeFee{}, errors.New("not found")
 }
+
+// 一次最多1000根
+// 1m..1d 对应bybit的interval
+var bbKLineIntervals = map[string]string{"1m": "1", "5m": "5", "15m": "15", "30m": "30",
+	"1h": "60", "4h": "240", "6h": "360", "12h": "720", "1d": "D"}
+
+func (bb *Bybit) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	itv, ok := bbKLineIntervals[interval]
+	if !ok {
+		return nil, errors.New(bb.Name() + " not support interval " + interval)
+	}
+	query := "?category=spot&symbol=" + symbol + "&interval=" + itv +
+		"&limit=" + strconv.FormatInt(limit, 10)
+	if startTime > 0 {
+		query += "&start=" + strconv.FormatInt(startTime*1000, 10)
+	}
+	if end := klineEndTime(interval, startTime, endTime, limit); end > 0 {
+		query += "&end=" + strconv.FormatInt(end*1000-1, 10)
+	}
+	url := bbUniEndpoint + "/v5/market/kline" + query
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			List [][7]string `json:"list,omitempty"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(bb.Name() + " Unmarshal err! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	all := make([]KLine, 0, len(ret.Result.List))
+	for i := len(ret.Result.List) - 1; i >= 0; i-- { // bybit 是倒序
+		v := ret.Result.List[i]
+		kl := KLine{}
+		ts, _ := strconv.ParseInt(v[0], 10, 64)
+		kl.OpenTime = ts / 1000
+		kl.OpenPrice, _ = decimal.NewFromString(v[1])
+		kl.HighPrice, _ = decimal.NewFromString(v[2])
+		kl.LowPrice, _ = decimal.NewFromString(v[3])
+		kl.ClosePrice, _ = decimal.NewFromString(v[4])
+		kl.Volume, _ = decimal.NewFromString(v[5])
+		kl.QuoteVolume, _ = decimal.NewFromString(v[6])
+
+		all = append(all, kl)
+	}
+	return all, nil
+}
@@ -42,7 +42,7 @@ func init() {
 	}
 }
 func (bb *Bybit) SpotWsPublicOpen() error {
-	url := "wss://stream.bybit.com/v5/public/spot"
+	url := bb.wsUrl("wss://stream.bybit.com/v5/public/spot")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -72,6 +72,15 @@ func (bb *Bybit) SpotWsPublicSubscribe(channels []string) {
 					arg.Args = append(arg.Args, "orderbook.1."+strings.ToUpper(sym))
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, level := parseOrderBookSymbol(v)
+					bb.spotWsOrderBooks.add(sym, level)
+					arg.Args = append(arg.Args, bb.spotOrderBookTopic(sym, level))
+				}
+			}
 		} else if arr[0] == "ticker" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolArr := strings.SplitSeq(arr[1], ",")
@@ -79,6 +88,16 @@ func (bb *Bybit) SpotWsPublicSubscribe(channels []string) {
 					arg.Args = append(arg.Args, "tickers."+strings.ToUpper(sym))
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					if itv, ok := bbKLineIntervals[interval]; ok {
+						arg.Args = append(arg.Args, "kline."+itv+"."+sym)
+					}
+				}
+			}
 		}
 	}
 	if len(arg.Args) > 0 {
@@ -103,6 +122,17 @@ func (bb *Bybit) SpotWsPublicUnsubscribe(channels []string) {
 					arg.Args = append(arg.Args, "orderbook.1."+strings.ToUpper(sym))
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, _ := parseOrderBookSymbol(v)
+					if ob := bb.spotWsOrderBooks.get(sym); ob != nil {
+						bb.spotWsOrderBooks.remove(sym)
+						arg.Args = append(arg.Args, bb.spotOrderBookTopic(sym, ob.level))
+					}
+				}
+			}
 		} else if arr[0] == "ticker" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolArr := strings.SplitSeq(arr[1], ",")
@@ -110,6 +140,16 @@ func (bb *Bybit) SpotWsPublicUnsubscribe(channels []string) {
 					arg.Args = append(arg.Args, "tickers."+strings.ToUpper(sym))
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				symbolArr := strings.SplitSeq(arr[1], ",")
+				for v := range symbolArr {
+					sym, interval := parseKLineSymbol(v)
+					if itv, ok := bbKLineIntervals[interval]; ok {
+						arg.Args = append(arg.Args, "kline."+itv+"."+sym)
+					}
+				}
+			}
 		}
 	}
 	if len(arg.Args) > 0 {
@@ -128,6 +168,9 @@ func (bb *Bybit) SpotWsPublicOrderBook5PoolPut(v any) {
 func (bb *Bybit) SpotWsPublicBBOPoolPut(v any) {
 	wsPublicBBOPool.Put(v)
 }
+func (bb *Bybit) SpotWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (bb *Bybit) SpotWsPublicLoop(ch chan<- any) {
 	defer bb.SpotWsPublicClose()
 	defer close(ch)
@@ -161,27 +204,31 @@ func (bb *Bybit) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := bb.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !bb.SpotWsPublicIsClosed() { // 并非主动断开
-				ilog.Warning(bb.Name() + " spot.ws.public read: " + err.Error())
+				ilog.Warning("%s", bb.Name()+" spot.ws.public read: "+err.Error())
 			}
 			break
 		}
 		msg := bbWsPubMsgPool.Get().(*BybitWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bb.Name() + " spot.ws.public invalid msg:" + string(recv))
+			ilog.Error("%s", bb.Name()+" spot.ws.public invalid msg:"+string(recv))
 			goto END
 		}
 		l = len(msg.Topic)
 		if l > 12 && msg.Topic[:12] == "orderbook.1." {
 			bb.spotWsHandleBBO(msg, ch)
+		} else if l > 10 && msg.Topic[:10] == "orderbook." {
+			bb.spotWsHandleOrderBook(msg, ch)
 		} else if l > 8 && msg.Topic[:8] == "tickers." {
 			bb.spotWsHandle24hTickers(msg, ch)
+		} else if l > 6 && msg.Topic[:6] == "kline." {
+			bb.spotWsHandleKLine(msg, ch)
 		} else {
 			if msg.Op == "ping" {
 				bb.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
 			} else if msg.Op == "subscribe" || msg.Op == "unsubscribe" { // 订阅的响应
 				if strings.Index(string(recv), "false") != -1 {
-					ilog.Error(bb.Name() + " spot.ws.public recv subscribe err:" + string(recv))
+					ilog.Error("%s", bb.Name()+" spot.ws.public recv subscribe err:"+string(recv))
 				}
 			}
 		}
@@ -210,7 +257,7 @@ func (bb *Bybit) spotWsHandleOrderBook5(msg *BybitWsPubMsg, ch chan<- any) {
 	depth.Asks = depth.Asks[:0]
 	if err := easyjson.Unmarshal(msg.Data, depth); err == nil {
 		if len(depth.Bids) != len(depth.Asks) {
-			ilog.Error(bb.Name() + " spot.ws.public " + msg.Topic + " orderbook5 exception")
+			ilog.Error("%s", bb.Name()+" spot.ws.public "+msg.Topic+" orderbook5 exception")
 			return
 		}
 		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
@@ -230,6 +277,65 @@ func (bb *Bybit) spotWsHandleOrderBook5(msg *BybitWsPubMsg, ch chan<- any) {
 		ch <- obd
 	}
 }
+
+// orderbook.1 已经用于bbo, 所以最少50档
+func (bb *Bybit) spotOrderBookTopic(symbol string, level int) string {
+	depth := "1000"
+	if level > 0 && level <= 50 {
+		depth = "50"
+	} else if level > 0 && level <= 200 {
+		depth = "200"
+	}
+	return "orderbook." + depth + "." + symbol
+}
+
+// 先推snapshot再推delta, u 必须连续, 丢包时重新订阅, 交易所会重新推快照
+func (bb *Bybit) spotWsHandleOrderBook(msg *BybitWsPubMsg, ch chan<- any) {
+	depth := struct {
+		Symbol   string      `json:"s"`
+		Bids     [][2]string `json:"b"`
+		Asks     [][2]string `json:"a"`
+		UpdateId int64       `json:"u"`
+	}{}
+	if err := json.Unmarshal(msg.Data, &depth); err != nil {
+		ilog.Error("%s", bb.Name()+" spot.ws.public "+msg.Topic+" orderbook exception")
+		return
+	}
+	ob := bb.spotWsOrderBooks.get(depth.Symbol)
+	if ob == nil {
+		return
+	}
+	if msg.Type == "snapshot" {
+		ob.reset()
+		ob.synced = true
+	} else if !ob.synced { // 等待重新订阅后的快照
+		return
+	} else if depth.UpdateId != ob.seqId+1 {
+		ilog.Warning("%s", bb.Name()+" spot.ws.public "+msg.Topic+" orderbook seq gap, resync")
+		bb.spotWsResyncOrderBook(ob, msg.Topic)
+		return
+	}
+	ob.setLevels(depth.Bids, depth.Asks)
+	ob.seqId = depth.UpdateId
+	ob.time = msg.Time
+	if ob.crossed() {
+		ilog.Warning("%s", bb.Name()+" spot.ws.public "+msg.Topic+" orderbook crossed, resync")
+		bb.spotWsResyncOrderBook(ob, msg.Topic)
+		return
+	}
+	ch <- ob.snapshot()
+}
+func (bb *Bybit) spotWsResyncOrderBook(ob *localOrderBook, topic string) {
+	ob.reset()
+	for _, op := range []string{"unsubscribe", "subscribe"} {
+		arg := BbSubscribeArg{Op: op, Args: []string{topic}}
+		arg.Id = "resync-" + gutils.RandomStr(8)
+		req, _ := json.Marshal(&arg)
+		bb.spotWsPublicConnMtx.Lock()
+		bb.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+		bb.spotWsPublicConnMtx.Unlock()
+	}
+}
 func (bb *Bybit) spotWsHandleBBO(msg *BybitWsPubMsg, ch chan<- any) {
 	bbo := bbSpotWsPublicBBOInnerPool.Get().(*BybitSpotBBO)
 	defer bbSpotWsPublicBBOInnerPool.Put(bbo)
@@ -260,12 +366,44 @@ func (bb *Bybit) spotWsHandle24hTickers(msg *BybitWsPubMsg, ch chan<- any) {
 	}
 }
 
+// topic: kline.{interval}.{symbol}
+func (bb *Bybit) spotWsHandleKLine(msg *BybitWsPubMsg, ch chan<- any) {
+	arr := strings.Split(msg.Topic, ".")
+	if len(arr) != 3 {
+		return
+	}
+	interval := ""
+	for k, v := range bbKLineIntervals {
+		if v == arr[1] {
+			interval = k
+			break
+		}
+	}
+	var list []BybitKLine
+	if err := json.Unmarshal(msg.Data, &list); err == nil {
+		for _, kl := range list {
+			v := wsPublicKLinePool.Get().(*KLine)
+			v.Symbol = arr[2]
+			v.Interval = interval
+			v.OpenTime = kl.OpenTime / 1000
+			v.OpenPrice = kl.Open
+			v.HighPrice = kl.High
+			v.LowPrice = kl.Low
+			v.ClosePrice = kl.Close
+			v.Volume = kl.Volume
+			v.QuoteVolume = kl.QuoteVolume
+			v.IsClosed = kl.IsClosed
+			ch <- v
+		}
+	}
+}
+
 // = priv channel
 func (bb *Bybit) SpotWsPrivateSupported() bool {
 	return true
 }
 func (bb *Bybit) SpotWsPrivateOpen() error {
-	url := "wss://stream.bybit.com/v5/private"
+	url := bb.wsUrl("wss://stream.bybit.com/v5/private")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -319,7 +457,7 @@ func (bb *Bybit) SpotWsPrivateSubscribe(channels []string) {
 		req, _ := json.Marshal(&arg)
 		bb.spotWsPrivateConnMtx.Lock()
 		if err := bb.spotWsPrivateConn.WriteMessage(websocket.TextMessage, req); err != nil {
-			ilog.Warning(bb.Name() + " spot.ws.priv subscribe net error! " + err.Error())
+			ilog.Warning("%s", bb.Name()+" spot.ws.priv subscribe net error! "+err.Error())
 		}
 		bb.spotWsPrivateConnMtx.Unlock()
 	}
@@ -357,8 +495,8 @@ func (bb *Bybit) SpotWsPrivateLoop(ch chan<- any) {
 	pingInterval := 31 * time.Second
 	pongWait := pingInterval + 2*time.Second
 	bb.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
-	bb.spotWsPublicConn.SetPongHandler(func(message string) error {
-		bb.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+	bb.spotWsPrivateConn.SetPongHandler(func(message string) error {
+		bb.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
 	pingExit := make(chan struct{})
@@ -386,17 +524,17 @@ func (bb *Bybit) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := bb.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !bb.SpotWsPrivateIsClosed() {
-				ilog.Warning(bb.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", bb.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if bb.debug {
-			ilog.Rinfo(bb.Name() + " spot priv ws: " + string(recv))
+			ilog.Rinfo("%s", bb.Name()+" spot priv ws: "+string(recv))
 		}
 		msg := bbWsPrivMsgPool.Get().(*BybitWsPrivMsg)
 		msg.reset()
 		if err = json.Unmarshal(recv, msg); err != nil {
-			ilog.Error(bb.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", bb.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Op == "ping" {
@@ -408,7 +546,7 @@ func (bb *Bybit) SpotWsPrivateLoop(ch chan<- any) {
 		} else {
 			if msg.Op == "subscribe" { // 订阅的响应
 				if strings.Index(string(recv), "false") != -1 {
-					ilog.Error(bb.Name() + " spot.ws.priv recv subscribe err:" + string(recv))
+					ilog.Error("%s", bb.Name()+" spot.ws.priv recv subscribe err:"+string(recv))
 				}
 			}
 		}
@@ -463,20 +601,22 @@ func (bb *Bybit) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
 }
 func (bb *Bybit) spotWsHandleBalanceUpdate(data json.RawMessage, ch chan<- any) {
 	bls := []struct {
-		Coin struct {
+		Coin []struct {
 			Symbol string          `json:"coin"`
 			Total  decimal.Decimal `json:"equity"`
-			Avail  decimal.Decimal `json:"walletBalance"`
+			Wallet decimal.Decimal `json:"walletBalance"` // 包含冻结
 			Locked decimal.Decimal `json:"locked"`
 		} `json:"coin"`
 	}{}
 	if err := json.Unmarshal(data, &bls); err == nil && len(bls) > 0 {
 		for i := range bls {
-			ch <- &SpotAsset{
-				Symbol: bls[i].Coin.Symbol,
-				Avail:  bls[i].Coin.Avail,
-				Locked: bls[i].Coin.Locked,
-				Total:  bls[i].Coin.Avail.Add(bls[i].Coin.Locked),
+			for _, coin := range bls[i].Coin {
+				ch <- &SpotAsset{
+					Symbol: coin.Symbol,
+					Avail:  coin.Wallet.Sub(coin.Locked),
+					Locked: coin.Locked,
+					Total:  coin.Wallet,
+				}
 			}
 		}
 	}
@@ -32,6 +32,17 @@ type BybitSpotBBO struct {
 	Bids   [][2]decimal.Decimal `json:"b,omitempty"`
 	Asks   [][2]decimal.Decimal `json:"a,omitempty"`
 }
+type BybitKLine struct {
+	OpenTime    int64           `json:"start"` // msec
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Volume      decimal.Decimal `json:"volume"`
+	QuoteVolume decimal.Decimal `json:"turnover"`
+	IsClosed    bool            `json:"confirm"`
+}
+
 type BybitSpot24hTicker struct {
 	Symbol      string          `json:"symbol"`
 	Last        decimal.Decimal `json:"lastPrice"`
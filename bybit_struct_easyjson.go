@@ -666,3 +666,157 @@ func (v *BybitOrderBook) UnmarshalJSON(data []byte) error {
 func (v *BybitOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonA6afe24DecodeGithubComShaovieCex3(l, v)
 }
+func easyjsonA6afe24DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *BybitKLine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "start":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OpenTime = int64(in.Int64())
+			}
+		case "open":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Open).UnmarshalJSON(data))
+				}
+			}
+		case "high":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.High).UnmarshalJSON(data))
+				}
+			}
+		case "low":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Low).UnmarshalJSON(data))
+				}
+			}
+		case "close":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Close).UnmarshalJSON(data))
+				}
+			}
+		case "volume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "turnover":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
+		case "confirm":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IsClosed = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA6afe24EncodeGithubComShaovieCex4(out *jwriter.Writer, in BybitKLine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"start\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.OpenTime))
+	}
+	{
+		const prefix string = ",\"open\":"
+		out.RawString(prefix)
+		out.Raw((in.Open).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"high\":"
+		out.RawString(prefix)
+		out.Raw((in.High).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"low\":"
+		out.RawString(prefix)
+		out.Raw((in.Low).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"close\":"
+		out.RawString(prefix)
+		out.Raw((in.Close).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"volume\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"turnover\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"confirm\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsClosed))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BybitKLine) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonA6afe24EncodeGithubComShaovieCex4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BybitKLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonA6afe24EncodeGithubComShaovieCex4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BybitKLine) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonA6afe24DecodeGithubComShaovieCex4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BybitKLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonA6afe24DecodeGithubComShaovieCex4(l, v)
+}
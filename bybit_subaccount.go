@@ -0,0 +1,266 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+func (bb *Bybit) SubAccountList() ([]SubAccount, error) {
+	url := bbUniEndpoint + "/v5/user/query-sub-members"
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders("", ""))
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			SubMembers []struct {
+				Uid      string `json:"uid"`
+				Username string `json:"username"`
+				Remark   string `json:"remark"`
+				Status   int    `json:"status"` // 1:normal 2:login banned 4:frozen
+			} `json:"subMembers"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	res := make([]SubAccount, 0, len(ret.Result.SubMembers))
+	for _, v := range ret.Result.SubMembers {
+		sa := SubAccount{
+			Id:     v.Uid,
+			Name:   v.Username,
+			Remark: v.Remark,
+			Status: "NORMAL",
+		}
+		if v.Status != 1 {
+			sa.Status = "FROZEN"
+		}
+		res = append(res, sa)
+	}
+	return res, nil
+}
+
+// 创建普通子账户(memberType=1)
+func (bb *Bybit) SubAccountCreate(name, remark string) (*SubAccount, error) {
+	body, _ := json.Marshal(map[string]any{
+		"username":   name,
+		"memberType": 1,
+		"remark":     remark,
+	})
+	url := bbUniEndpoint + "/v5/user/create-sub-member"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Uid      string `json:"uid"`
+			Username string `json:"username"`
+			Remark   string `json:"remark"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return &SubAccount{
+		Id:     ret.Result.Uid,
+		Name:   ret.Result.Username,
+		Remark: ret.Result.Remark,
+		Status: "NORMAL",
+	}, nil
+}
+
+// 统一账户, SPOT/UM_FUTURE 返回UNIFIED账户资产
+func (bb *Bybit) SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error) {
+	var accountType string
+	if typ == "SPOT" || typ == "UM_FUTURE" {
+		accountType = "UNIFIED"
+	} else if typ == "FUNDING" {
+		accountType = "FUND"
+	} else {
+		return nil, newNotSupportError(bb.Name())
+	}
+	query := "memberId=" + subAccount + "&accountType=" + accountType
+	url := bbUniEndpoint + "/v5/asset/transfer/query-account-coins-balance?" + query
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, bb.buildHeaders(query, ""))
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Balance []struct {
+				Symbol          string          `json:"coin"`
+				WalletBalance   decimal.Decimal `json:"walletBalance"`
+				TransferBalance decimal.Decimal `json:"transferBalance"`
+			} `json:"balance"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	assets := make(map[string]*SubAccountAsset, len(ret.Result.Balance))
+	for _, v := range ret.Result.Balance {
+		assets[v.Symbol] = &SubAccountAsset{
+			Symbol: v.Symbol,
+			Total:  v.WalletBalance,
+			Avail:  v.TransferBalance,
+			Locked: v.WalletBalance.Sub(v.TransferBalance),
+		}
+	}
+	return assets, nil
+}
+
+// startTime,endTime 间隔不能超过7天, 都为0时返回最近7天
+// 指定子账户时由服务端按memberId过滤, 每页最多50条, 按nextPageCursor取完
+func (bb *Bybit) SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error) {
+	query := "limit=50"
+	if subAccount != "" {
+		query += "&memberId=" + subAccount
+	}
+	if startTime > 0 {
+		query += "&startTime=" + strconv.FormatInt(startTime*1000, 10)
+	}
+	if endTime > 0 {
+		query += "&endTime=" + strconv.FormatInt(endTime*1000, 10)
+	}
+	res := make([]SubAccountTransfer, 0, 50)
+	cursor := ""
+	for {
+		l, next, err := bb.subAccountTransferHistory(query, cursor)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, l...)
+		if next == "" || len(l) == 0 {
+			break
+		}
+		cursor = next
+	}
+	return res, nil
+}
+func (bb *Bybit) subAccountTransferHistory(query, cursor string) ([]SubAccountTransfer, string, error) {
+	if cursor != "" {
+		query += "&cursor=" + url.QueryEscape(cursor)
+	}
+	link := bbUniEndpoint + "/v5/asset/transfer/query-universal-transfer-list?" + query
+	httpCode, resp, err := bb.Get(link, bbApiDeadline, bb.buildHeaders(query, ""))
+	if err != nil {
+		return nil, "", newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			List []struct {
+				Id     string          `json:"transferId"`
+				Symbol string          `json:"coin"`
+				Qty    decimal.Decimal `json:"amount"`
+				From   string          `json:"fromMemberId"`
+				To     string          `json:"toMemberId"`
+				Status string          `json:"status"`    // SUCCESS/PENDING/FAILED
+				CTime  string          `json:"timestamp"` // msec
+			} `json:"list"`
+			NextPageCursor string `json:"nextPageCursor"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, "", errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, "", bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	res := make([]SubAccountTransfer, 0, len(ret.Result.List))
+	for _, v := range ret.Result.List {
+		ctime, _ := strconv.ParseInt(v.CTime, 10, 64)
+		res = append(res, SubAccountTransfer{
+			Id:     v.Id,
+			Symbol: v.Symbol,
+			Qty:    v.Qty,
+			From:   v.From,
+			To:     v.To,
+			Status: v.Status,
+			Time:   ctime / 1000,
+		})
+	}
+	return res, ret.Result.NextPageCursor, nil
+}
+
+// perm READ:readOnly=1 TRADE:readOnly=0, 都只开放现货和合约交易权限
+// ips 为空时不绑定ip("*"), 这样的key 3个月不用会失效
+func (bb *Bybit) SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error) {
+	uid, err := strconv.ParseInt(subAccount, 10, 64)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " sub account must be uid")
+	}
+	params := map[string]any{
+		"subuid":   uid,
+		"note":     label,
+		"readOnly": 1,
+		"ips":      "*",
+		"permissions": map[string][]string{
+			"ContractTrade": {"Order", "Position"},
+			"Spot":          {"SpotTrade"},
+		},
+	}
+	if perm == "TRADE" {
+		params["readOnly"] = 0
+	} else if perm != "READ" {
+		return nil, errors.New(bb.Name() + " not support perm " + perm)
+	}
+	if len(ips) > 0 {
+		params["ips"] = strings.Join(ips, ",")
+	}
+	body, _ := json.Marshal(params)
+	url := bbUniEndpoint + "/v5/user/create-sub-api"
+	httpCode, resp, err := bb.Post(url, body, bbApiDeadline, bb.buildHeaders("", string(body)))
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Note   string `json:"note"`
+			ApiKey string `json:"apiKey"`
+			Secret string `json:"secret"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return &SubAccountApiKey{
+		ApiKey:    ret.Result.ApiKey,
+		SecretKey: ret.Result.Secret,
+		Label:     ret.Result.Note,
+		Perm:      perm,
+		Ips:       ips,
+	}, nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -30,9 +29,9 @@ func (bb *Bybit) Transfer(symbol, from, to, typ, subAccount string, qty decimal.
 	path := "/v5/asset/transfer/inter-transfer"
 	headers := bb.buildHeaders("", payload)
 	url := bbUniEndpoint + path
-	_, resp, err := ihttp.Get(url, bbApiDeadline, headers)
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, headers)
 	if err != nil {
-		return errors.New(bb.Name() + " net error! " + err.Error())
+		return newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -47,7 +46,7 @@ func (bb *Bybit) Transfer(symbol, from, to, typ, subAccount string, qty decimal.
 		return errors.New(bb.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return errors.New(bb.Name() + " transfer " + qty.String() + " fail! " + ret.Msg)
+		return bb.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	return nil
@@ -65,9 +64,9 @@ func (bb *Bybit) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decima
 		`,"chain":"` + chain + `"` +
 		`}`
 	headers := bb.buildHeaders("", payload)
-	_, resp, err := ihttp.Post(url, []byte(payload), bbApiDeadline, headers)
+	httpCode, resp, err := bb.Post(url, []byte(payload), bbApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -81,7 +80,7 @@ func (bb *Bybit) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decima
 		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != 0 {
-		return nil, errors.New(bb.Name() + " withdrawal fail! msg=" + ret.Msg)
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
 	}
 
 	wr := &WithdrawReturn{
@@ -90,14 +89,42 @@ func (bb *Bybit) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decima
 	}
 	return wr, nil
 }
+func (bb *Bybit) CancelWithdrawal(symbol, wid string) error {
+	path := "/v5/asset/withdraw/cancel"
+	url := bbUniEndpoint + path
+	payload := `{"id":"` + wid + `"}`
+	headers := bb.buildHeaders("", payload)
+	httpCode, resp, err := bb.Post(url, []byte(payload), bbApiDeadline, headers)
+	if err != nil {
+		return newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Status int `json:"status"` // 1:success
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	if ret.Result.Status != 1 {
+		return errors.New(bb.Name() + " cancel withdrawal fail! " + string(resp))
+	}
+	return nil
+}
 func (bb *Bybit) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	path := "/v5/asset/withdraw/query-record"
 	params := "coin=" + symbol
 	headers := bb.buildHeaders(params, "")
 	url := bbUniEndpoint + path + "?" + params
-	_, resp, err := ihttp.Get(url, bbApiDeadline, headers)
+	_, resp, err := bb.Get(url, bbApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -135,14 +162,77 @@ func (bb *Bybit) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	}
 	return res, nil
 }
+
+// startTime,endTime 间隔不能超过30天, 都为0时返回最近30天
+func (bb *Bybit) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	path := "/v5/asset/deposit/query-record"
+	params := "coin=" + symbol + "&limit=50"
+	if startTime > 0 {
+		params += "&startTime=" + strconv.FormatInt(startTime*1000, 10)
+	}
+	if endTime > 0 {
+		params += "&endTime=" + strconv.FormatInt(endTime*1000, 10)
+	}
+	headers := bb.buildHeaders(params, "")
+	url := bbUniEndpoint + path + "?" + params
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Rows []struct {
+				Id            string          `json:"id"`
+				Symbol        string          `json:"coin"`
+				Network       string          `json:"chain"`
+				Qty           decimal.Decimal `json:"amount"`
+				Addr          string          `json:"toAddress"`
+				Memo          string          `json:"tag"`
+				Status        int             `json:"status"`
+				Txid          string          `json:"txID"`
+				Confirmations string          `json:"confirmations"`
+				Time          string          `json:"successAt"` // msec
+			} `json:"rows"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+
+	res := make([]DepositResult, 0, len(ret.Result.Rows))
+	for _, v2 := range ret.Result.Rows {
+		ctime, _ := strconv.ParseInt(v2.Time, 10, 64)
+		confirms, _ := strconv.ParseInt(v2.Confirmations, 10, 64)
+		a := DepositResult{
+			Id:            v2.Id,
+			Symbol:        v2.Symbol,
+			Network:       v2.Network,
+			Addr:          v2.Addr,
+			Memo:          v2.Memo,
+			Txid:          v2.Txid,
+			Qty:           v2.Qty,
+			Confirmations: confirms,
+			Status:        bb.toStdDepositStatus(v2.Status),
+			Time:          ctime / 1000,
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
 func (bb *Bybit) FundingGetAsset(symbol string) (FundingAsset, error) {
 	path := "/v5/asset/transfer/query-account-coin-balance"
 	params := "accountType=FUND&coin=" + symbol
 	headers := bb.buildHeaders(params, "")
 	url := bbUniEndpoint + path + "?" + params
-	_, resp, err := ihttp.Get(url, bbApiDeadline, headers)
+	_, resp, err := bb.Get(url, bbApiDeadline, headers)
 	if err != nil {
-		return FundingAsset{}, errors.New(bb.Name() + " net error! " + err.Error())
+		return FundingAsset{}, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -175,9 +265,9 @@ func (bb *Bybit) GetDepositAddress(symbol, network string) ([]DepositAddress, er
 	}
 	headers := bb.buildHeaders(params, "")
 	url := bbUniEndpoint + path + "?" + params
-	_, resp, err := ihttp.Get(url, bbApiDeadline, headers)
+	_, resp, err := bb.Get(url, bbApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(bb.Name() + " net error! " + err.Error())
+		return nil, newNetError(bb.Name(), err)
 	}
 	ret := struct {
 		Code   int    `json:"retCode,omitempty"`
@@ -204,3 +294,60 @@ func (bb *Bybit) GetDepositAddress(symbol, network string) ([]DepositAddress, er
 	}
 	return daL, nil
 }
+func (bb *Bybit) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
+	path := "/v5/asset/coin/query-info"
+	headers := bb.buildHeaders("", "")
+	url := bbUniEndpoint + path
+	httpCode, resp, err := bb.Get(url, bbApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(bb.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"retCode,omitempty"`
+		Msg    string `json:"retMsg,omitempty"`
+		Result struct {
+			Rows []struct {
+				Symbol string `json:"coin"`
+				Chains []struct {
+					Network             string          `json:"chain"`
+					ChainDeposit        string          `json:"chainDeposit"`  // 1:enabled
+					ChainWithdraw       string          `json:"chainWithdraw"` // 1:enabled
+					WithdrawScale       string          `json:"minAccuracy"`
+					WithdrawFee         decimal.Decimal `json:"withdrawFee"`
+					MinWithdrawalAmount decimal.Decimal `json:"withdrawMin"`
+					MinDepositAmount    decimal.Decimal `json:"depositMin"`
+				} `json:"chains"`
+			} `json:"rows"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(bb.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return nil, bb.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	waiMap := make(map[string]*WalletAssetInfo)
+	for _, v := range ret.Result.Rows {
+		if len(v.Chains) == 0 {
+			continue
+		}
+		wai := WalletAssetInfo{
+			Symbol:       v.Symbol,
+			BindNetworks: make(map[string]*WalletAssetBindNetworkInfo),
+		}
+		for _, vv := range v.Chains {
+			scale, _ := strconv.ParseInt(vv.WithdrawScale, 10, 32)
+			wai.BindNetworks[vv.Network] = &WalletAssetBindNetworkInfo{
+				IsWithdrawalEnabled: vv.ChainWithdraw == "1",
+				IsDepositEnabled:    vv.ChainDeposit == "1",
+				WithdrawScale:       int32(scale),
+				WithdrawFee:         vv.WithdrawFee,
+				MinWithdrawalAmount: vv.MinWithdrawalAmount,
+				MinDepositAmount:    vv.MinDepositAmount,
+			}
+		}
+		waiMap[v.Symbol] = &wai
+	}
+	return waiMap, nil
+}
@@ -31,21 +31,50 @@ type Exchanger interface {
 		side, timeInForce, orderType string, postOnly bool) (string, error)
 	// only bigone
 	SpotPlaceOrderMultiple([]SpotPostOrder) error
+	// 批量下单/撤单 okx,bybit,gate使用批量接口(自动分批), binance,kraken,bigone,mexc,kucoin,ktx,bitget,htx并发逐个下单
+	// 返回结果与请求顺序一一对应, 每个订单的错误(包括网络错误)在BatchOrderResult.Err
+	SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error)
+	SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error)
+	// 撤销symbol的所有挂单, symbol为空表示所有symbol(只bybit,gate,kraken,kucoin,ktx,htx支持)
+	// kraken只支持撤销所有symbol, mexc,bitget必须指定symbol, 只binance,okx,bybit,gate,kraken,mexc,kucoin,ktx,bitget,htx实现
+	// okx没有撤销全部的接口, 分页查询挂单后批量撤销, 返回所有撤销失败的错误(errors.Join)
+	SpotCancelAllOrders(symbol string) error
 	// orderId, cltId 二选一
 	SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error
+	// 改单(只限价单) orderId, cltId 二选一, qty为改后的总数量, side只有binance需要
+	// binance现货为cancelReplace, kraken为EditOrder(只支持orderId), 改单后OrderId会变化
+	// 只binance,okx,bybit,gate,kraken实现
+	SpotAmendOrder(symbol, orderId, cltId, side string, price, qty decimal.Decimal) (*SpotOrder, error)
 	// orderId, cltId 二选一 (kraken 不支持cltId)
 	SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 	SpotGetOpenOrders(symbol string) ([]*SpotOrder, error)
 	SpotGetFilledOrders(symbol string) ([]*SpotOrder, error)
 	SpotGetTradeFee(symbol string) (SpotTradeFee, error)
+	// interval 1m,5m,15m,30m,1h,4h,6h,12h,1d startTime/endTime is second (各交易所支持的interval不同)
+	// 返回顺序[11:15:00,11:16:00,11:17:00]
+	SpotGetKLine(symbol, interval string, startTime, endTime, lmt int64) ([]KLine, error)
+	// 条件单 orderType: STOP_MARKET/STOP_LIMIT/TAKE_PROFIT_MARKET/TAKE_PROFIT_LIMIT
+	// triggerBy: MARKET_PRICE(最新价,缺省)/MARK_PRICE/INDEX_PRICE, 现货一般只支持MARKET_PRICE
+	// 市价单price传0, 只binance,okx,bybit,gate实现, 返回条件单id(okx为algoId)
+	// gate不支持cltId, 且市价买单qty为计价币数量
+	SpotPlaceConditionalOrder(symbol, cltId string, triggerPrice, price, qty decimal.Decimal,
+		side, orderType, triggerBy string) (string, error)
+	// 未触发的条件单 (binance已触发未成交的也会返回, Status=TRIGGERED)
+	SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error)
+	SpotCancelConditionalOrder(symbol, orderId string) error
 
 	//= ws public
-	// cex object 如果closed需要重新连接时，请不要复用，一定要创建新的obj
+	// cex object 如果closed需要重新连接时，请不要复用，一定要创建新的obj (或使用WsSession自动重连)
 	SpotWsPublicOpen() error
 	// channels: orderbook5@symbolA,symbolB (5档)
-	//           bbo@symbolA,symbolB     // 最优买卖价 只binance,bybit,bbo,okx,gate实现
+	//           bbo@symbolA,symbolB     // 最优买卖价 只binance,bybit,bbo,okx,gate,mexc,kucoin,ktx,bitget,htx实现
+	//           orderbook@symbolA:depth,symbolB // 本地维护的N档订单簿(depth缺省为全量), 推送*OrderBook
+	//                                   // 断档/checksum错误时自动重新同步, 只binance,okx,gate,bybit,kraken实现
+	//                                   // okx最多400档, bybit最多1000档, kraken最多1000档
 	//           ticker@symbolA,symbolB     // bigone不支持
-	//           trades@symbolA,symbolB // 仅限bigone,binance
+	//           trades@symbolA,symbolB // 仅限bigone,binance,mexc
+	//           kline@symbolA:1m,symbolB:5m // 推送*KLine 只binance,bybit,gate,kraken,okx实现
+	//                                   // okx在订阅时另建business连接, 断开时public连接一并关闭
 	// 每个交易所支持的参数数量不同
 	SpotWsPublicSubscribe(channels []string)
 	SpotWsPublicUnsubscribe(channels []string)
@@ -53,13 +82,14 @@ type Exchanger interface {
 	SpotWsPublicOrderBook5PoolPut(v any)
 	SpotWsPublicBBOPoolPut(v any)
 	SpotWsPublicTradePoolPut(v any)
+	SpotWsPublicKLinePoolPut(v any)
 	// Loop结束时会close(ch)
 	SpotWsPublicLoop(ch chan<- any)
 	SpotWsPublicClose()
 	SpotWsPublicIsClosed() bool
 
 	//= ws private
-	// cex object 如果closed需要重新连接时，请不要复用，一定要创建新的obj
+	// cex object 如果closed需要重新连接时，请不要复用，一定要创建新的obj (或使用WsSession自动重连)
 	SpotWsPrivateSupported() bool
 	SpotWsPrivateOpen() error
 	// channels: orders
@@ -78,6 +108,8 @@ type Exchanger interface {
 		side, timeInForce, orderType string, postOnly bool) (string /*req id*/, error)
 	// orderId, cltId 二选一
 	SpotWsCancelOrder(symbol, orderId, cltId string) (string, error)
+	// 参数涵义同SpotAmendOrder, 结果以*SpotOrder推送(RequestId) 只binance,okx,gate实现
+	SpotWsAmendOrder(symbol, orderId, cltId, side string, price, qty decimal.Decimal) (string /*req id*/, error)
 
 	//= margin
 	// 全仓杠杆账户详情
@@ -98,10 +130,17 @@ type Exchanger interface {
 	MarginRepay(symbol string, qty decimal.Decimal, isIsolated bool) error
 	MarginGetAssetInfo(symbol /*BTC*/ string) (MarginAssetInfo, error)
 
+	// 倒计时撤单(dead man's switch): timeout秒内没有再次调用, 交易所自动撤销所有挂单, timeout=0取消倒计时
+	// typ=SPOT/UM/CM, 需要定时调用续期, 参考 DeadManSwitch
+	// binance只支持UM/CM(按symbol, 非统一账户), gate支持SPOT/UM/CM(按symbol,可为空), okx,kraken为整个账户(忽略symbol)
+	// bybit为DCP(私有ws断开timeout秒后撤单, 不能通过timeout=0取消, UM/CM共用一个设置)
+	CancelAllOrdersAfter(typ, symbol string, timeout int) error
+
 	//= futures, typ=UM,U本位 typ=CM,币本位
 	FuturesSupported(typ string) bool
 	FuturesServerTime(typ string) (int64, error)
 	FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error)
+	// 张数(size)与标的数量(qty)换算, okx,gate UM需要先加载交易规则
 	FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal
 	FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error)
 	FuturesGetBBO(typ, symbol string) (BestBidAsk, error)
@@ -124,26 +163,43 @@ type Exchanger interface {
 	// symbol 为空取所有的
 	FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error)
 	FuturesCancelOrder(typ string, symbol /*BTCUSDT*/, orderId, cltId string) error
+	// 参数涵义同SpotPlaceOrders binance(非统一账户),bybit,okx,gate使用批量接口
+	FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error)
+	FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error)
+	// 撤销symbol的所有挂单(symbol必填) 只binance,bybit,okx,gate,bitget,htx实现
+	// okx没有撤销全部的接口, 分页查询挂单后批量撤销, 返回所有撤销失败的错误(errors.Join)
+	FuturesCancelAllOrders(typ, symbol string) error
+	// 参数涵义同SpotAmendOrder, CM中 qty为合约张数 只binance,bybit,okx,gate实现
+	FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+		price, qty decimal.Decimal) (*FuturesOrder, error)
+	// 条件单 参数涵义同SpotPlaceConditionalOrder, 只binance,bybit,okx,gate实现(gate不支持cltId)
+	FuturesPlaceConditionalOrder(typ, symbol, cltId string, triggerPrice, price, qty decimal.Decimal,
+		side, orderType, triggerBy, positionMode string, reduceOnly int) (string, error)
+	// symbol 为空取所有的
+	FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error)
+	FuturesCancelConditionalOrder(typ, symbol, orderId string) error
 	//  单仓:0/双仓:1 切换
 	FuturesSwitchPositionMode(typ string, mode int) error
 	//  全仓:0/逐仓:1 切换
 	FuturesSwitchTradeMode(typ, symbol string /*BTCUSDT*/, mode, leverage int) error
-	// 获取交易对的杠杆分层标准 For binance
+	// 获取交易对的杠杆分层标准 For binance,okx,gate
 	FuturesMaintMargin(typ, symbol string) ([]*FuturesLeverageBracket, error)
-	// 获取账户损益资金流水 For binance, plType: FUNDING_FEE
+	// 获取账户损益资金流水 For binance,okx,gate, plType: FUNDING_FEE
 	FuturesGetProfitLossHistory(typ, symbol, plType string, startTime, endTime int64) (
 		[]FuturesProfitLossHistory, error)
 
 	// ws
 	// channels: orderbook5@symbolA,symbolB
-	//           bbo@symbolA,symbolB     // 最优买卖价 只binance实现
+	//           bbo@symbolA,symbolB     // 最优买卖价 只binance,okx,gate,bybit,bitget,htx实现
 	//           ticker@symbol,symbol2
+	//           kline@symbolA:1m,symbolB:5m // 推送*KLine 只binance,okx,gate实现
 	FuturesWsPublicOpen(typ string) error
 	FuturesWsPublicSubscribe(channels []string)
 	FuturesWsPublicUnsubscribe(channels []string)
 	FuturesWsPublicTickerPoolPut(v any)
 	FuturesWsPublicOrderBook5PoolPut(v any)
 	FuturesWsPublicBBOPoolPut(v any)
+	FuturesWsPublicKLinePoolPut(v any)
 	// Loop结束时会close(ch)
 	FuturesWsPublicLoop(ch chan<- any)
 	FuturesWsPublicClose()
@@ -154,7 +210,7 @@ type Exchanger interface {
 	FuturesWsPrivateOpen(typ string) error
 	// channels: orders
 	//           positions
-	//           balance // 只有binance
+	//           balance // 只有binance,bybit,bitget
 	FuturesWsPrivateSubscribe(channels []string)
 	FuturesWsPrivateLoop(ch chan<- any)
 	FuturesWsPrivateClose()
@@ -165,6 +221,8 @@ type Exchanger interface {
 		side, orderType, timeInForce, positionMode string,
 		tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error)
 	FuturesWsCancelOrder(symbol, orderId, cltId string) (string, error)
+	// 参数涵义同FuturesAmendOrder, 结果以*FuturesOrder推送(RequestId) 只binance,okx实现
+	FuturesWsAmendOrder(symbol, orderId, cltId, side string, price, qty decimal.Decimal) (string, error)
 
 	//= 统一账户
 	// rest api
@@ -183,7 +241,13 @@ type Exchanger interface {
 	// chain: TRX/MOB
 	// memo is key at Kraken
 	Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal) (*WithdrawReturn, error)
+	// 撤销处理中的提现, wid 为 Withdrawal 返回的WId, symbol 只有kraken需要
+	// only bigone,gate,bybit,okx,kraken (binance 没有撤销提现的接口)
+	CancelWithdrawal(symbol, wid string) error
 	GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
+	// startTime,endTime: second, 0 使用交易所默认范围, 跟踪到账参考 DepositWatcher
+	// only binance,gate,bybit,kraken,bigone
+	GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error)
 	// from,to:FUNDING,SPOT,UM_FUTURE,CM_FUTURE,UNIFIED,MARGIN
 	// typ: NORMAL, MASTER_TO_SUB, SUB_TO_MASTER, SUB_INTERNAL
 	Transfer(symbol, from, to, typ, subAccount string, qty decimal.Decimal) error
@@ -192,8 +256,25 @@ type Exchanger interface {
 	FundingGetAsset(symbol string) (FundingAsset, error)
 	// network is optional
 	GetDepositAddress(symbol, network string) ([]DepositAddress, error)
-	// only bigone
+	// 地址簿中的提现地址, 参考 WithdrawGuard
+	// only binance,gate(symbol必填),kraken
+	GetWithdrawAddressBook(symbol string) ([]WithdrawAddress, error)
+	// 各币种的提现网络/手续费/最小提现额/精度
+	// only bigone,binance,gate,bybit,okx,kraken
 	GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error)
+
+	//= sub account, 需要母账户apikey
+	// only binance,okx,bybit,gate
+	SubAccountList() ([]SubAccount, error)
+	// binance 创建虚拟子账户, name 为邮箱前缀, 不支持remark
+	SubAccountCreate(name, remark string) (*SubAccount, error)
+	// typ: SPOT/UM_FUTURE/FUNDING, okx,bybit 统一账户SPOT/UM_FUTURE返回相同的资产
+	SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error)
+	// 母子账户之间的划转记录, startTime,endTime: second, 0 使用交易所默认范围
+	SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error)
+	// 为子账户创建apikey, perm: READ/TRADE(现货和合约交易), 不开放提现权限, ips 为空不绑定ip
+	// passphrase 只okx需要, binance 不支持label, 其它交易所返回 ErrNotSupported
+	SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error)
 }
 
 var (
@@ -217,8 +298,9 @@ func init() {
 	CexList["kraken"] = "Kraken"
 	CexList["ktx"] = "Ktx"
 	CexList["kucoin"] = "Kucoin"
-	//CexList["mexc"] = "Mexc"
-	//CexList["bitget"]= "Bitget"
+	CexList["mexc"] = "Mexc"
+	CexList["bitget"] = "Bitget"
+	CexList["htx"] = "HTX"
 
 	CexSXList = make(map[string]string)
 	CexSXList["binance"] = "BN"
@@ -230,6 +312,8 @@ func init() {
 	CexSXList["mexc"] = "MC"
 	CexSXList["ktx"] = "KTX"
 	CexSXList["kucoin"] = "KC"
+	CexSXList["bitget"] = "BG"
+	CexSXList["htx"] = "HT"
 
 	CexFeeCoinMap = make(map[string]string)
 	CexFeeCoinMap["gate"] = "GT"
@@ -245,7 +329,9 @@ func NewPrivate(cexName, account, apikey, secretkey, passwd string) (Exchanger,
 func NewPrivateWithLocalIP(cexName, account, apikey, secretkey, passwd, localIp string) (Exchanger, error) {
 	return New(cexName, account, apikey, secretkey, passwd, localIp)
 }
-func New(cexName, account, apikey, secretkey, passwd, localIp string) (Exchanger, error) {
+
+// opts 可选, 用于测试网/区域域名/本地mock server, 参考 Options
+func New(cexName, account, apikey, secretkey, passwd, localIp string, opts ...*Options) (Exchanger, error) {
 	var cexObj Exchanger
 	var err error
 	if cexName == "binance" {
@@ -259,17 +345,34 @@ func New(cexName, account, apikey, secretkey, passwd, localIp string) (Exchanger
 	} else if cexName == "bybit" {
 		cexObj = NewBybit(account, apikey, secretkey)
 	} else if cexName == "ktx" {
-		cexObj = NewKtx()
+		cexObj = NewKtx(account, apikey, secretkey)
 	} else if cexName == "kucoin" {
-		cexObj = NewKucoin()
+		cexObj = NewKucoin(account, apikey, secretkey, passwd)
+	} else if cexName == "bitget" {
+		cexObj = NewBitget(account, apikey, secretkey, passwd)
+	} else if cexName == "htx" {
+		cexObj = NewHtx(account, apikey, secretkey)
 	} else if cexName == "kraken" {
 		cexObj = NewKraken(account, apikey, secretkey)
+	} else if cexName == "mexc" {
+		cexObj = NewMexc(account, apikey, secretkey)
 	} else {
 		return nil, errors.New("unknown cex platform : " + cexName)
 	}
 	if err != nil {
 		return nil, errors.New(cexName + " create failed! " + err.Error())
 	}
+	if len(opts) > 0 && opts[0] != nil {
+		o, ok := cexObj.(interface {
+			applyOptions(string, *Options) error
+		})
+		if !ok {
+			return nil, errors.New(cexName + " not support endpoint options")
+		}
+		if err = o.applyOptions(cexName, opts[0]); err != nil {
+			return nil, errors.New(cexName + " create failed! " + err.Error())
+		}
+	}
 	if err = cexObj.Init(); err != nil {
 		return nil, errors.New(cexObj.Name() + " init failed! " + err.Error())
 	}
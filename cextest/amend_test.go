@@ -0,0 +1,73 @@
+package cextest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shaovie/cex"
+	"github.com/shopspring/decimal"
+)
+
+// binance,kraken 改单为撤单后重新下单, 其它原地修改
+func TestSpotAmendOrder(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "bybit", "gate", "kraken"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			orderId, err := ex.SpotPlaceOrder("BTCUSDT", "c1", decimal.NewFromInt(90), decimal.Zero,
+				decimal.NewFromInt(2), "BUY", "GTC", "LIMIT", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, err := ex.SpotAmendOrder("BTCUSDT", orderId, "", "BUY", decimal.NewFromInt(95), decimal.NewFromInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.OrderId == "" || !o.Price.Equal(decimal.NewFromInt(95)) || !o.Qty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+			l := srv.Engine.OpenOrders("BTCUSDT")
+			if len(l) != 1 || !l[0].Price.Equal(decimal.NewFromInt(95)) || !l[0].Qty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected open orders %+v", l)
+			}
+			if b := srv.Engine.Balance("USDT"); !b.Locked.Equal(decimal.NewFromInt(95)) {
+				t.Fatalf("want locked 95 got %s", b.Locked)
+			}
+			_, err = ex.SpotAmendOrder("BTCUSDT", "12345", "", "BUY", decimal.NewFromInt(95), decimal.NewFromInt(1))
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Fatalf("want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
+func TestFuturesAmendOrder(t *testing.T) {
+	for _, name := range []string{"binance", "bybit", "okx", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			orderId, err := ex.FuturesPlaceOrder("UM", "BTCUSDT", "c1", decimal.NewFromInt(110),
+				decimal.NewFromInt(2), "SELL", "LIMIT", "GTC", "BOTH", 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, err := ex.FuturesAmendOrder("UM", "BTCUSDT", orderId, "", "SELL",
+				decimal.NewFromInt(105), decimal.NewFromInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.OrderId != orderId || !o.Price.Equal(decimal.NewFromInt(105)) || !o.Qty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+			l := srv.Futures.OpenOrders("BTCUSDT")
+			if len(l) != 1 || !l[0].Price.Equal(decimal.NewFromInt(105)) || !l[0].Qty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("unexpected open orders %+v", l)
+			}
+			if b := srv.Futures.Balance("USDT"); !b.Locked.Equal(decimal.NewFromInt(105)) {
+				t.Fatalf("want margin 105 got %s", b.Locked)
+			}
+			_, err = ex.FuturesAmendOrder("UM", "BTCUSDT", "12345", "", "SELL",
+				decimal.NewFromInt(105), decimal.NewFromInt(1))
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Fatalf("want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
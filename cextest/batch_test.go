@@ -0,0 +1,117 @@
+package cextest
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/shaovie/cex"
+	"github.com/shopspring/decimal"
+)
+
+// okx 走批量接口, 其它并发逐个请求
+func TestSpotBatchOrder(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "kraken", "bigone"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			orders := []cex.SpotPostOrder{
+				{Symbol: "BTCUSDT", ClientId: "b1", Side: "BUY", Type: "LIMIT", TimeInForce: "GTC",
+					Price: decimal.NewFromInt(90), Qty: decimal.NewFromInt(1)},
+				{Symbol: "BTCUSDT", ClientId: "b2", Side: "BUY", Type: "LIMIT", TimeInForce: "GTC",
+					Price: decimal.NewFromInt(91), Qty: decimal.NewFromInt(1)},
+				{Symbol: "BTCUSDT", ClientId: "b3", Side: "BUY", Type: "LIMIT", TimeInForce: "GTC",
+					Price: decimal.NewFromInt(90), Qty: decimal.NewFromInt(1000)},
+			}
+			results, err := ex.SpotPlaceOrders(orders)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("want 3 results got %d", len(results))
+			}
+			for i := 0; i < 2; i++ {
+				if results[i].Err != nil || results[i].OrderId == "" {
+					t.Fatalf("order %d: unexpected result %+v", i, results[i])
+				}
+			}
+			if !errors.Is(results[2].Err, cex.ErrInsufficientFunds) {
+				t.Fatalf("order 2: want ErrInsufficientFunds got %v", results[2].Err)
+			}
+			if l := srv.Engine.OpenOrders("BTCUSDT"); len(l) != 2 {
+				t.Fatalf("want 2 open orders got %d", len(l))
+			}
+
+			results, err = ex.SpotCancelOrders([]cex.CancelOrderArg{
+				{Symbol: "BTCUSDT", OrderId: results[0].OrderId},
+				{Symbol: "BTCUSDT", OrderId: results[1].OrderId},
+				{Symbol: "BTCUSDT", OrderId: "12345"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 3 || results[0].Err != nil || results[1].Err != nil {
+				t.Fatalf("unexpected results %+v", results)
+			}
+			if !errors.Is(results[2].Err, cex.ErrOrderNotFound) {
+				t.Fatalf("order 2: want ErrOrderNotFound got %v", results[2].Err)
+			}
+			if l := srv.Engine.OpenOrders("BTCUSDT"); len(l) != 0 {
+				t.Fatalf("want no open orders got %d", len(l))
+			}
+		})
+	}
+}
+
+// binance 每批最多5个, 6个订单分两批; okx 走批量接口
+func TestFuturesBatchOrder(t *testing.T) {
+	for _, name := range []string{"binance", "okx"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			orders := make([]cex.FuturesPostOrder, 0, 6)
+			for i := int64(0); i < 5; i++ {
+				orders = append(orders, cex.FuturesPostOrder{Symbol: "BTCUSDT", ClientId: "b" + strconv.FormatInt(i, 10),
+					Side: "SELL", Type: "LIMIT", TimeInForce: "GTC", PositionMode: "BOTH",
+					Price: decimal.NewFromInt(110 + i), Qty: decimal.NewFromInt(1)})
+			}
+			orders = append(orders, cex.FuturesPostOrder{Symbol: "BTCUSDT", ClientId: "b5",
+				Side: "SELL", Type: "LIMIT", TimeInForce: "GTC", PositionMode: "BOTH",
+				Price: decimal.NewFromInt(110), Qty: decimal.NewFromInt(1000)})
+			results, err := ex.FuturesPlaceOrders("UM", orders)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 6 {
+				t.Fatalf("want 6 results got %d", len(results))
+			}
+			args := make([]cex.CancelOrderArg, 0, 6)
+			for i := 0; i < 5; i++ {
+				if results[i].Err != nil || results[i].OrderId == "" || results[i].ClientId != orders[i].ClientId {
+					t.Fatalf("order %d: unexpected result %+v", i, results[i])
+				}
+				args = append(args, cex.CancelOrderArg{Symbol: "BTCUSDT", OrderId: results[i].OrderId})
+			}
+			if !errors.Is(results[5].Err, cex.ErrInsufficientFunds) {
+				t.Fatalf("order 5: want ErrInsufficientFunds got %v", results[5].Err)
+			}
+			if l := srv.Futures.OpenOrders("BTCUSDT"); len(l) != 5 {
+				t.Fatalf("want 5 open orders got %d", len(l))
+			}
+
+			args = append(args, cex.CancelOrderArg{Symbol: "BTCUSDT", OrderId: "12345"})
+			if results, err = ex.FuturesCancelOrders("UM", args); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 5; i++ {
+				if results[i].Err != nil {
+					t.Fatalf("cancel %d: %v", i, results[i].Err)
+				}
+			}
+			if !errors.Is(results[5].Err, cex.ErrOrderNotFound) {
+				t.Fatalf("cancel 5: want ErrOrderNotFound got %v", results[5].Err)
+			}
+			if b := srv.Futures.Balance("USDT"); !b.Locked.IsZero() {
+				t.Fatalf("want no margin locked got %s", b.Locked)
+			}
+		})
+	}
+}
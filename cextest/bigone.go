@@ -0,0 +1,403 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *Server) initBigone() {
+	s.route("GET", "/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {
+		s.boData(w, map[string]any{"Timestamp": time.Now().UnixNano()})
+	})
+	s.route("GET", "/api/v3/asset_pairs", s.boAssetPairs)
+	s.route("GET", "/api/v3/viewer/accounts", s.boSigned(s.boAccounts))
+	s.route("POST", "/api/v3/viewer/orders", s.boSigned(s.boPlaceOrder))
+	s.route("POST", "/api/v3/viewer/order/cancel", s.boSigned(s.boCancelOrder))
+	s.route("GET", "/api/v3/viewer/order", s.boSigned(s.boGetOrder))
+	s.route("GET", "/api/v3/viewer/orders", s.boSigned(s.boOrders))
+
+	// 公有和私有频道共用一个地址
+	s.wsRoute("/ws/v2", s.boWs)
+	s.Engine.OnBook(s.boPushBook)
+	s.Engine.OnOrder(s.boPushOrder)
+	s.Engine.OnBalance(s.boPushBalance)
+}
+
+func (s *Server) boData(w http.ResponseWriter, data any) {
+	writeJSON(w, 200, map[string]any{"code": 0, "data": data})
+}
+func (s *Server) boError(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]any{"code": code, "message": msg})
+}
+func (s *Server) boEngineError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInsufficientFunds:
+		s.boError(w, 400, 54041, "Insufficient balance")
+	case ErrOrderNotFound:
+		s.boError(w, 404, 10013, "Order does not exist")
+	case ErrInvalidSymbol:
+		s.boError(w, 400, 40004, "Invalid symbol")
+	default:
+		s.boError(w, 400, 10014, err.Error())
+	}
+}
+
+// 校验 Authorization: Bearer <jwt(HS256)>, payload.sub 为apikey
+func (s *Server) boSigned(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if msg := s.boCheckToken(r.Header.Get("Authorization")); msg != "" {
+			s.boError(w, 401, 10001, msg)
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// rest和ws共用, 返回空表示校验通过
+func (s *Server) boCheckToken(auth string) string {
+	parts := strings.Split(strings.TrimPrefix(auth, "Bearer "), ".")
+	if len(parts) != 3 {
+		return "Unauthorized"
+	}
+	h := hmac.New(sha256.New, []byte(s.cfg.SecretKey))
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal([]byte(parts[2]), []byte(base64.RawURLEncoding.EncodeToString(h.Sum(nil)))) {
+		return "Unauthorized: invalid signature"
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	claims := struct {
+		Type string `json:"type"`
+		Sub  string `json:"sub"`
+	}{}
+	if json.Unmarshal(payload, &claims) != nil || claims.Sub != s.cfg.ApiKey ||
+		claims.Type != "OpenAPIV2" {
+		return "Unauthorized: invalid api key"
+	}
+	return ""
+}
+func (s *Server) boPair(symbol string) string {
+	if sym, ok := s.Engine.Symbol(symbol); ok {
+		return sym.Base + "-" + sym.Quote
+	}
+	return symbol
+}
+func (s *Server) boAssetPairs(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, sym := range s.Engine.Symbols() {
+		l = append(l, map[string]any{
+			"name":            sym.Base + "-" + sym.Quote,
+			"quote_scale":     decimalPlaces(sym.TickSize),
+			"base_scale":      decimalPlaces(sym.StepSize),
+			"min_quote_value": sym.MinNotional,
+			"base_asset":      map[string]any{"symbol": sym.Base, "name": sym.Base},
+			"quote_asset":     map[string]any{"symbol": sym.Quote, "name": sym.Quote},
+		})
+	}
+	s.boData(w, l)
+}
+func (s *Server) boAccounts(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, b := range s.Engine.Balances() {
+		l = append(l, map[string]any{"asset_symbol": b.Asset,
+			"balance": b.Free.Add(b.Locked), "locked_balance": b.Locked})
+	}
+	s.boData(w, l)
+}
+func (s *Server) boPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		AssetPairName     string `json:"asset_pair_name"`
+		ClientOrderId     string `json:"client_order_id"`
+		Price             string `json:"price"`
+		Amount            string `json:"amount"`
+		Side              string `json:"side"`
+		Type              string `json:"type"`
+		PostOnly          bool   `json:"post_only"`
+		ImmediateOrCancel bool   `json:"immediate_or_cancel"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.boError(w, 400, 10014, "Invalid request body")
+		return
+	}
+	req := OrderRequest{
+		Symbol:   strings.ReplaceAll(arg.AssetPairName, "-", ""),
+		ClientId: arg.ClientOrderId,
+		Type:     arg.Type,
+		PostOnly: arg.PostOnly,
+	}
+	switch arg.Side {
+	case "BID":
+		req.Side = "BUY"
+	case "ASK":
+		req.Side = "SELL"
+	}
+	if arg.ImmediateOrCancel {
+		req.TimeInForce = "IOC"
+	}
+	amount, _ := decimal.NewFromString(arg.Amount)
+	req.Price, _ = decimal.NewFromString(arg.Price)
+	req.Qty = amount
+	if req.Type == "MARKET" && req.Side == "BUY" { // 市价买单amount为quote金额
+		req.Qty, req.QuoteQty = decimal.Zero, amount
+	}
+	o, err := s.Engine.PlaceOrder(req)
+	if err != nil {
+		s.boEngineError(w, err)
+		return
+	}
+	s.boData(w, s.boOrder(o))
+}
+func (s *Server) boCancelOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		OrderId       int64  `json:"order_id"`
+		ClientOrderId string `json:"client_order_id"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.boError(w, 400, 10014, "Invalid request body")
+		return
+	}
+	o, err := s.Engine.CancelOrder("", arg.OrderId, arg.ClientOrderId)
+	if err != nil {
+		s.boEngineError(w, err)
+		return
+	}
+	s.boData(w, s.boOrder(o))
+}
+func (s *Server) boGetOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("order_id"), 10, 64)
+	o, err := s.Engine.GetOrder(id, q.Get("client_order_id"))
+	if err != nil {
+		s.boEngineError(w, err)
+		return
+	}
+	s.boData(w, s.boOrder(o))
+}
+
+// state=PENDING 返回挂单, state=FILLED 返回已成交的订单
+func (s *Server) boOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	symbol := strings.ReplaceAll(q.Get("asset_pair_name"), "-", "")
+	l := []any{}
+	if q.Get("state") == "FILLED" {
+		for _, o := range s.Engine.Orders(symbol) {
+			if o.Status == "FILLED" {
+				l = append(l, s.boOrder(o))
+			}
+		}
+	} else {
+		for _, o := range s.Engine.OpenOrders(symbol) {
+			l = append(l, s.boOrder(o))
+		}
+	}
+	s.boData(w, l)
+}
+func (s *Server) boOrder(o Order) map[string]any {
+	state := "CANCELLED"
+	switch o.Status {
+	case "NEW", "PARTIALLY_FILLED":
+		state = "PENDING"
+	case "FILLED":
+		state = "FILLED"
+	}
+	side := "BID"
+	if o.Side == "SELL" {
+		side = "ASK"
+	}
+	amount := o.Qty
+	if o.Type == "MARKET" && o.QuoteQty.IsPositive() {
+		amount = o.QuoteQty
+	}
+	return map[string]any{
+		"id":                  o.Id,
+		"asset_pair_name":     s.boPair(o.Symbol),
+		"client_order_id":     o.ClientId,
+		"price":               o.Price,
+		"amount":              amount,
+		"filled_amount":       o.FilledQty,
+		"avg_deal_price":      o.AvgPrice(),
+		"state":               state,
+		"type":                o.Type,
+		"side":                side,
+		"post_only":           o.PostOnly,
+		"immediate_or_cancel": o.TimeInForce == "IOC",
+		"created_at":          time.UnixMilli(o.CTime).UTC().Format(time.RFC3339),
+		"updated_at":          time.UnixMilli(o.UTime).UTC().Format(time.RFC3339),
+	}
+}
+
+// ws
+type boWsReq struct {
+	RequestId string `json:"requestId"`
+	Depth     *struct {
+		Market string `json:"market"`
+	} `json:"subscribeMarketDepthRequest"`
+	UnDepth *struct {
+		Market string `json:"market"`
+	} `json:"unsubscribeMarketDepthRequest"`
+	Ticker *struct {
+		Markets []string `json:"markets"`
+	} `json:"subscribeMarketsTickerRequest"`
+	UnTicker *struct {
+		Markets []string `json:"markets"`
+	} `json:"unsubscribeMarketsTickerRequest"`
+	Auth *struct {
+		Token string `json:"token"`
+	} `json:"authenticateCustomerRequest"`
+	Orders   *struct{} `json:"subscribeAllViewerOrdersRequest"`
+	Accounts *struct{} `json:"subscribeViewerAccountsRequest"`
+}
+
+func (s *Server) boWsOk(c *wsConn, requestId string) {
+	resp, _ := json.Marshal(map[string]any{"requestId": requestId, "success": map[string]any{"ok": true}})
+	c.write(resp)
+}
+func (s *Server) boWsError(c *wsConn, requestId string, code int, msg string) {
+	resp, _ := json.Marshal(map[string]any{"requestId": requestId,
+		"error": map[string]any{"code": code, "message": msg}})
+	c.write(resp)
+}
+func (s *Server) boWs(c *wsConn, msg []byte) {
+	var req boWsReq
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	switch {
+	case req.Depth != nil:
+		c.subscribe("depth:"+req.Depth.Market, true)
+		s.boWsOk(c, req.RequestId)
+		s.boPushDepthTo(c, strings.ReplaceAll(req.Depth.Market, "-", ""))
+	case req.UnDepth != nil:
+		c.subscribe("depth:"+req.UnDepth.Market, false)
+		s.boWsOk(c, req.RequestId)
+	case req.Ticker != nil:
+		for _, m := range req.Ticker.Markets {
+			c.subscribe("ticker:"+m, true)
+		}
+		s.boWsOk(c, req.RequestId)
+		// 订阅后先推一次快照
+		tickers := []any{}
+		for _, m := range req.Ticker.Markets {
+			if t := s.boTicker(strings.ReplaceAll(m, "-", "")); t != nil {
+				tickers = append(tickers, t)
+			}
+		}
+		frame, _ := json.Marshal(map[string]any{"requestId": req.RequestId,
+			"tickersSnapshot": map[string]any{"tickers": tickers}})
+		c.write(frame)
+	case req.UnTicker != nil:
+		for _, m := range req.UnTicker.Markets {
+			c.subscribe("ticker:"+m, false)
+		}
+		s.boWsOk(c, req.RequestId)
+	case req.Auth != nil:
+		if msg := s.boCheckToken(req.Auth.Token); msg != "" {
+			s.boWsError(c, req.RequestId, 10001, msg)
+			return
+		}
+		c.setAuthed()
+		s.boWsOk(c, req.RequestId)
+	case req.Orders != nil, req.Accounts != nil:
+		if !c.isAuthed() {
+			s.boWsError(c, req.RequestId, 10001, "Unauthorized")
+			return
+		}
+		if req.Orders != nil {
+			c.subscribe("orders", true)
+			s.boWsOk(c, req.RequestId)
+			return
+		}
+		c.subscribe("accounts", true)
+		s.boWsOk(c, req.RequestId)
+		accounts := []any{}
+		for _, b := range s.Engine.Balances() {
+			accounts = append(accounts, s.boWsAccount(b))
+		}
+		frame, _ := json.Marshal(map[string]any{"requestId": req.RequestId,
+			"accountsSnapshot": map[string]any{"accounts": accounts}})
+		c.write(frame)
+	}
+}
+func (s *Server) boTicker(symbol string) map[string]any {
+	bids, asks := s.Engine.Book(symbol, 1)
+	if len(bids) == 0 || len(asks) == 0 {
+		return nil
+	}
+	return map[string]any{"market": s.boPair(symbol),
+		"bid": map[string]any{"price": bids[0].Price, "amount": bids[0].Qty},
+		"ask": map[string]any{"price": asks[0].Price, "amount": asks[0].Qty}}
+}
+func (s *Server) boWsAccount(b Balance) map[string]any {
+	return map[string]any{"asset": b.Asset, "balance": b.Free.Add(b.Locked), "lockedBalance": b.Locked}
+}
+func (s *Server) boPushBook(symbol string) {
+	var frame []byte
+	if t := s.boTicker(symbol); t != nil {
+		frame, _ = json.Marshal(map[string]any{"tickerUpdate": map[string]any{"ticker": t}})
+	}
+	for _, c := range s.wsConns("/ws/v2") {
+		s.boPushDepthTo(c, symbol)
+		if frame != nil && c.subscribed("ticker:"+s.boPair(symbol)) {
+			c.write(frame)
+		}
+	}
+}
+
+// 盘口每次都以depthSnapshot推送全量
+func (s *Server) boPushDepthTo(c *wsConn, symbol string) {
+	market := s.boPair(symbol)
+	if !c.subscribed("depth:" + market) {
+		return
+	}
+	levels := func(l []Level) []any {
+		r := []any{}
+		for _, v := range l {
+			r = append(r, map[string]any{"price": v.Price, "amount": v.Qty})
+		}
+		return r
+	}
+	bids, asks := s.Engine.Book(symbol, 50)
+	changeId := s.changeId.Add(1)
+	frame, _ := json.Marshal(map[string]any{"depthSnapshot": map[string]any{
+		"depth":    map[string]any{"market": market, "bids": levels(bids), "asks": levels(asks)},
+		"changeId": itoa(changeId), "prevId": itoa(changeId - 1)}})
+	c.write(frame)
+}
+func (s *Server) boPushOrder(o Order) {
+	order := s.boOrder(o)
+	frame, _ := json.Marshal(map[string]any{"orderUpdate": map[string]any{"order": map[string]any{
+		"id":            itoa(o.Id),
+		"market":        order["asset_pair_name"],
+		"clientOrderId": o.ClientId,
+		"price":         o.Price,
+		"amount":        order["amount"],
+		"filledAmount":  o.FilledQty,
+		"avgDealPrice":  o.AvgPrice(),
+		"state":         order["state"],
+		"type":          o.Type,
+		"side":          order["side"],
+		"filledFees":    o.Fee,
+		"createdAt":     order["created_at"],
+		"updatedAt":     order["updated_at"],
+	}}})
+	for _, c := range s.wsConns("/ws/v2") {
+		if c.subscribed("orders") {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) boPushBalance(b Balance) {
+	frame, _ := json.Marshal(map[string]any{"accountUpdate": map[string]any{"account": s.boWsAccount(b)}})
+	for _, c := range s.wsConns("/ws/v2") {
+		if c.subscribed("accounts") {
+			c.write(frame)
+		}
+	}
+}
@@ -0,0 +1,675 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *Server) initBinance() {
+	s.route("GET", "/api/v3/time", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, 200, map[string]any{"serverTime": time.Now().UnixMilli()})
+	})
+	s.route("GET", "/api/v3/exchangeInfo", s.bnExchangeInfo)
+	s.route("GET", "/api/v3/ticker/bookTicker", s.bnBookTicker)
+	s.route("GET", "/api/v3/klines", s.bnKLines)
+	s.route("GET", "/api/v3/account", s.bnSigned(s.bnAccount))
+	s.route("POST", "/api/v3/order", s.bnSigned(s.bnPlaceOrder))
+	s.route("POST", "/api/v3/order/cancelReplace", s.bnSigned(s.bnCancelReplace))
+	s.route("DELETE", "/api/v3/order", s.bnSigned(s.bnCancelOrder))
+	s.route("GET", "/api/v3/order", s.bnSigned(s.bnGetOrder))
+	s.route("GET", "/api/v3/openOrders", s.bnSigned(s.bnOpenOrders))
+	s.route("DELETE", "/api/v3/openOrders", s.bnSigned(s.bnCancelAllOrders))
+	// U本位合约, 和现货共用处理函数, 按path区分引擎
+	s.route("GET", "/fapi/v1/time", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, 200, map[string]any{"serverTime": time.Now().UnixMilli()})
+	})
+	s.route("GET", "/fapi/v1/exchangeInfo", s.bnFuturesExchangeInfo)
+	s.route("POST", "/fapi/v1/order", s.bnSigned(s.bnPlaceOrder))
+	s.route("DELETE", "/fapi/v1/order", s.bnSigned(s.bnCancelOrder))
+	s.route("PUT", "/fapi/v1/order", s.bnSigned(s.bnAmendOrder))
+	s.route("POST", "/fapi/v1/algoOrder", s.bnSigned(s.bnPlaceAlgoOrder))
+	s.route("DELETE", "/fapi/v1/algoOrder", s.bnSigned(s.bnCancelAlgoOrder))
+	s.route("GET", "/fapi/v1/openAlgoOrders", s.bnSigned(s.bnOpenAlgoOrders))
+	s.route("GET", "/fapi/v1/order", s.bnSigned(s.bnGetOrder))
+	s.route("GET", "/fapi/v1/openOrders", s.bnSigned(s.bnOpenOrders))
+	s.route("DELETE", "/fapi/v1/allOpenOrders", s.bnSigned(s.bnCancelAllOrders))
+	s.route("POST", "/fapi/v1/batchOrders", s.bnSigned(s.bnBatchPlaceOrders))
+	s.route("DELETE", "/fapi/v1/batchOrders", s.bnSigned(s.bnBatchCancelOrders))
+	for _, p := range []string{"/fapi/v1/listenKey", "/dapi/v1/listenKey", "/papi/v1/listenKey"} {
+		s.route("POST", p, s.bnApiKey(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, 200, map[string]any{"listenKey": s.newListenKey()})
+		}))
+		s.route("PUT", p, s.bnApiKey(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, 200, map[string]any{})
+		}))
+		s.route("DELETE", p, s.bnApiKey(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, 200, map[string]any{})
+		}))
+	}
+
+	s.wsRoute("/stream", s.bnWsPublic)
+	s.wsRoute("/ws-api/v3", s.bnWsPrivate)
+	s.Engine.OnBook(s.bnPushBook)
+	s.Engine.OnKLine(s.bnPushKLine)
+	s.Futures.OnKLine(s.bnPushKLine) // 合约和现货的公共ws都是/stream
+	s.Engine.OnOrder(s.bnPushOrder)
+	s.Engine.OnBalance(s.bnPushBalance)
+}
+
+func (s *Server) bnSign(params string) string {
+	h := hmac.New(sha256.New, []byte(s.cfg.SecretKey))
+	h.Write([]byte(params))
+	return hex.EncodeToString(h.Sum(nil))
+}
+func (s *Server) bnError(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]any{"code": code, "msg": msg})
+}
+func (s *Server) bnEngineError(w http.ResponseWriter, err error) {
+	code, msg := s.bnEngineCode(err)
+	s.bnError(w, 400, code, msg)
+}
+func (s *Server) bnEngineCode(err error) (int, string) {
+	switch err {
+	case ErrInsufficientFunds:
+		return -2010, "Account has insufficient balance for requested action."
+	case ErrOrderNotFound:
+		return -2013, "Order does not exist."
+	case ErrInvalidSymbol:
+		return -1121, "Invalid symbol."
+	}
+	return -1013, err.Error()
+}
+func (s *Server) bnIsFutures(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/fapi/")
+}
+func (s *Server) bnEngine(r *http.Request) *Engine {
+	if s.bnIsFutures(r) {
+		return s.Futures
+	}
+	return s.Engine
+}
+func (s *Server) bnApiKey(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-MBX-APIKEY") != s.cfg.ApiKey {
+			s.bnError(w, 401, -2015, "Invalid API-key, IP, or permissions for action.")
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// 校验 query 中的 signature
+func (s *Server) bnSigned(fn http.HandlerFunc) http.HandlerFunc {
+	return s.bnApiKey(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.RawQuery
+		params, sig, ok := strings.Cut(query, "&signature=")
+		if !ok || !hmac.Equal([]byte(sig), []byte(s.bnSign(params))) {
+			s.bnError(w, 400, -1022, "Signature for this request is not valid.")
+			return
+		}
+		fn(w, r)
+	})
+}
+func (s *Server) bnExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	var symbols []any
+	for _, sym := range s.Engine.Symbols() {
+		symbols = append(symbols, map[string]any{
+			"symbol":     sym.Symbol,
+			"baseAsset":  sym.Base,
+			"quoteAsset": sym.Quote,
+			"status":     "TRADING",
+			"filters": []any{
+				map[string]any{"filterType": "PRICE_FILTER", "minPrice": sym.TickSize,
+					"maxPrice": "1000000", "tickSize": sym.TickSize},
+				map[string]any{"filterType": "LOT_SIZE", "minQty": sym.MinQty,
+					"maxQty": "9000000", "stepSize": sym.StepSize},
+				map[string]any{"filterType": "NOTIONAL", "minNotional": sym.MinNotional},
+			},
+		})
+	}
+	writeJSON(w, 200, map[string]any{"symbols": symbols})
+}
+func (s *Server) bnFuturesExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	var symbols []any
+	for _, sym := range s.Futures.Symbols() {
+		symbols = append(symbols, map[string]any{
+			"symbol":       sym.Symbol,
+			"pair":         sym.Symbol,
+			"baseAsset":    sym.Base,
+			"quoteAsset":   sym.Quote,
+			"contractType": "PERPETUAL",
+			"status":       "TRADING",
+			"filters": []any{
+				map[string]any{"filterType": "PRICE_FILTER", "minPrice": sym.TickSize,
+					"maxPrice": "1000000", "tickSize": sym.TickSize},
+				map[string]any{"filterType": "LOT_SIZE", "minQty": sym.MinQty,
+					"maxQty": "9000000", "stepSize": sym.StepSize},
+				map[string]any{"filterType": "MIN_NOTIONAL", "notional": sym.MinNotional},
+			},
+		})
+	}
+	writeJSON(w, 200, map[string]any{"symbols": symbols})
+}
+func (s *Server) bnBookTicker(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+	if _, ok := s.Engine.Symbol(symbol); !ok {
+		s.bnEngineError(w, ErrInvalidSymbol)
+		return
+	}
+	bids, asks := s.Engine.Book(symbol, 1)
+	v := map[string]any{"symbol": symbol}
+	if len(bids) > 0 {
+		v["bidPrice"], v["bidQty"] = bids[0].Price, bids[0].Qty
+	}
+	if len(asks) > 0 {
+		v["askPrice"], v["askQty"] = asks[0].Price, asks[0].Qty
+	}
+	writeJSON(w, 200, v)
+}
+
+// startTime/endTime 为msec, 包含endTime
+func (s *Server) bnKLines(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+	end, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if end > 0 {
+		end = end/1000 + 1
+	}
+	l := s.bnEngine(r).KLines(q.Get("symbol"), q.Get("interval"), start/1000, end)
+	l = limitKLines(l, limit, start > 0)
+	seconds := intervalSeconds(q.Get("interval"))
+	ret := make([]any, 0, len(l))
+	for _, k := range l {
+		ret = append(ret, []any{k.OpenTime * 1000, k.Open.String(), k.High.String(), k.Low.String(),
+			k.Close.String(), k.Volume.String(), (k.OpenTime+seconds)*1000 - 1, k.QuoteVolume.String(),
+			1, "0", "0", "0"})
+	}
+	writeJSON(w, 200, ret)
+}
+func (s *Server) bnAccount(w http.ResponseWriter, r *http.Request) {
+	var balances []any
+	for _, b := range s.Engine.Balances() {
+		balances = append(balances, map[string]any{"asset": b.Asset, "free": b.Free, "locked": b.Locked})
+	}
+	writeJSON(w, 200, map[string]any{"balances": balances})
+}
+func (s *Server) bnPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("stopPrice") != "" && !s.bnIsFutures(r) { // 现货条件单是带stopPrice的普通订单
+		s.bnPlaceConditionalOrder(w, r)
+		return
+	}
+	req := OrderRequest{
+		Symbol:      q.Get("symbol"),
+		ClientId:    q.Get("newClientOrderId"),
+		Side:        q.Get("side"),
+		Type:        q.Get("type"),
+		TimeInForce: q.Get("timeInForce"),
+	}
+	if req.Type == "LIMIT_MAKER" {
+		req.Type, req.PostOnly = "LIMIT", true
+	}
+	req.Price, _ = decimal.NewFromString(q.Get("price"))
+	req.Qty, _ = decimal.NewFromString(q.Get("quantity"))
+	req.QuoteQty, _ = decimal.NewFromString(q.Get("quoteOrderQty"))
+	o, err := s.bnEngine(r).PlaceOrder(req)
+	if err == ErrInsufficientFunds && s.bnIsFutures(r) {
+		s.bnError(w, 400, -2019, "Margin is insufficient.")
+		return
+	} else if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, map[string]any{
+		"symbol":        o.Symbol,
+		"orderId":       o.Id,
+		"clientOrderId": o.ClientId,
+		"transactTime":  o.CTime,
+	})
+}
+
+// 条件单类型, 现货和U本位合约的命名不同
+var bnSpotConditionalTypes = map[string]string{"STOP_MARKET": "STOP_LOSS", "STOP_LIMIT": "STOP_LOSS_LIMIT",
+	"TAKE_PROFIT_MARKET": "TAKE_PROFIT", "TAKE_PROFIT_LIMIT": "TAKE_PROFIT_LIMIT"}
+var bnFuturesConditionalTypes = map[string]string{"STOP_MARKET": "STOP_MARKET", "STOP_LIMIT": "STOP",
+	"TAKE_PROFIT_MARKET": "TAKE_PROFIT_MARKET", "TAKE_PROFIT_LIMIT": "TAKE_PROFIT"}
+
+func bnToStdConditionalType(types map[string]string, typ string) string {
+	for k, v := range types {
+		if v == typ {
+			return k
+		}
+	}
+	return ""
+}
+func (s *Server) bnPlaceConditionalOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	o := ConditionalOrder{
+		Symbol:   q.Get("symbol"),
+		ClientId: q.Get("newClientOrderId"),
+		Side:     q.Get("side"),
+		Type:     bnToStdConditionalType(bnSpotConditionalTypes, q.Get("type")),
+	}
+	o.TriggerPrice, _ = decimal.NewFromString(q.Get("stopPrice"))
+	o.Price, _ = decimal.NewFromString(q.Get("price"))
+	o.Qty, _ = decimal.NewFromString(q.Get("quantity"))
+	o, err := s.Engine.PlaceConditionalOrder(o)
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, map[string]any{
+		"symbol":        o.Symbol,
+		"orderId":       o.Id,
+		"clientOrderId": o.ClientId,
+		"transactTime":  o.CTime,
+	})
+}
+
+// 未触发的现货条件单workingTime为-1
+func (s *Server) bnConditionalOrder(o ConditionalOrder) map[string]any {
+	return map[string]any{
+		"symbol":        o.Symbol,
+		"orderId":       o.Id,
+		"clientOrderId": o.ClientId,
+		"price":         o.Price,
+		"stopPrice":     o.TriggerPrice,
+		"origQty":       o.Qty,
+		"status":        o.Status,
+		"type":          bnSpotConditionalTypes[o.Type],
+		"side":          o.Side,
+		"time":          o.CTime,
+		"workingTime":   -1,
+	}
+}
+
+// U本位合约条件单走algo接口
+func (s *Server) bnPlaceAlgoOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("algoType") != "CONDITIONAL" {
+		s.bnError(w, 400, -1102, "Mandatory parameter 'algoType' was not sent, was empty/null, or malformed.")
+		return
+	}
+	o := ConditionalOrder{
+		Symbol:    q.Get("symbol"),
+		ClientId:  q.Get("clientAlgoId"),
+		Side:      q.Get("side"),
+		Type:      bnToStdConditionalType(bnFuturesConditionalTypes, q.Get("type")),
+		TriggerBy: "MARKET_PRICE",
+	}
+	if q.Get("workingType") == "MARK_PRICE" {
+		o.TriggerBy = "MARK_PRICE"
+	}
+	o.TriggerPrice, _ = decimal.NewFromString(q.Get("triggerPrice"))
+	o.Price, _ = decimal.NewFromString(q.Get("price"))
+	o.Qty, _ = decimal.NewFromString(q.Get("quantity"))
+	o, err := s.Futures.PlaceConditionalOrder(o)
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.bnAlgoOrder(o))
+}
+func (s *Server) bnCancelAlgoOrder(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(r.URL.Query().Get("algoId"), 10, 64)
+	if _, err := s.Futures.CancelConditionalOrder("", id, ""); err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, map[string]any{"algoId": id, "code": "200", "msg": "success"})
+}
+func (s *Server) bnOpenAlgoOrders(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, o := range s.Futures.OpenConditionalOrders(r.URL.Query().Get("symbol")) {
+		l = append(l, s.bnAlgoOrder(o))
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) bnAlgoOrder(o ConditionalOrder) map[string]any {
+	workingType := "CONTRACT_PRICE"
+	if o.TriggerBy == "MARK_PRICE" {
+		workingType = "MARK_PRICE"
+	}
+	return map[string]any{
+		"algoId":       o.Id,
+		"clientAlgoId": o.ClientId,
+		"algoType":     "CONDITIONAL",
+		"orderType":    bnFuturesConditionalTypes[o.Type],
+		"symbol":       o.Symbol,
+		"side":         o.Side,
+		"price":        o.Price,
+		"triggerPrice": o.TriggerPrice,
+		"quantity":     o.Qty,
+		"workingType":  workingType,
+		"algoStatus":   o.Status,
+		"createTime":   o.CTime,
+	}
+}
+
+// 合约批量接口的返回与请求顺序一致, 失败的为{code,msg}
+func (s *Server) bnBatchPlaceOrders(w http.ResponseWriter, r *http.Request) {
+	args := []struct {
+		Symbol      string `json:"symbol"`
+		Side        string `json:"side"`
+		Type        string `json:"type"`
+		Qty         string `json:"quantity"`
+		Price       string `json:"price"`
+		TimeInForce string `json:"timeInForce"`
+		ClientId    string `json:"newClientOrderId"`
+	}{}
+	if json.Unmarshal([]byte(r.URL.Query().Get("batchOrders")), &args) != nil || len(args) > 5 {
+		s.bnError(w, 400, -1130, "Data sent for parameter 'batchOrders' is not valid.")
+		return
+	}
+	l := []any{}
+	for _, arg := range args {
+		req := OrderRequest{
+			Symbol:      arg.Symbol,
+			ClientId:    arg.ClientId,
+			Side:        arg.Side,
+			Type:        arg.Type,
+			TimeInForce: arg.TimeInForce,
+		}
+		req.Price, _ = decimal.NewFromString(arg.Price)
+		req.Qty, _ = decimal.NewFromString(arg.Qty)
+		o, err := s.Futures.PlaceOrder(req)
+		if err == ErrInsufficientFunds {
+			l = append(l, map[string]any{"code": -2019, "msg": "Margin is insufficient."})
+			continue
+		} else if err != nil {
+			code, msg := s.bnEngineCode(err)
+			l = append(l, map[string]any{"code": code, "msg": msg})
+			continue
+		}
+		l = append(l, s.bnOrderResp(r, o))
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) bnBatchCancelOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var ids []int64
+	var cltIds []string
+	json.Unmarshal([]byte(q.Get("orderIdList")), &ids)
+	json.Unmarshal([]byte(q.Get("origClientOrderIdList")), &cltIds)
+	l := []any{}
+	cancel := func(id int64, cltId string) {
+		o, err := s.Futures.CancelOrder(q.Get("symbol"), id, cltId)
+		if err != nil {
+			code, msg := s.bnEngineCode(err)
+			l = append(l, map[string]any{"code": code, "msg": msg})
+			return
+		}
+		l = append(l, s.bnOrderResp(r, o))
+	}
+	for _, id := range ids {
+		cancel(id, "")
+	}
+	for _, cltId := range cltIds {
+		cancel(0, cltId)
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) bnCancelOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+	o, err := s.bnEngine(r).CancelOrder(q.Get("symbol"), id, q.Get("origClientOrderId"))
+	if err == ErrOrderNotFound && !s.bnIsFutures(r) {
+		if co, err := s.Engine.CancelConditionalOrder(q.Get("symbol"), id, q.Get("origClientOrderId")); err == nil {
+			writeJSON(w, 200, s.bnConditionalOrder(co))
+			return
+		}
+	}
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.bnOrderResp(r, o))
+}
+
+// 现货改单为撤单后重新下单, 新订单的orderId不同
+func (s *Server) bnCancelReplace(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("cancelOrderId"), 10, 64)
+	canceled, err := s.Engine.CancelOrder(q.Get("symbol"), id, q.Get("cancelOrigClientOrderId"))
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	req := OrderRequest{
+		Symbol:      q.Get("symbol"),
+		ClientId:    q.Get("newClientOrderId"),
+		Side:        q.Get("side"),
+		Type:        q.Get("type"),
+		TimeInForce: q.Get("timeInForce"),
+	}
+	req.Price, _ = decimal.NewFromString(q.Get("price"))
+	req.Qty, _ = decimal.NewFromString(q.Get("quantity"))
+	o, err := s.Engine.PlaceOrder(req)
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	placed := s.bnOrder(o)
+	placed["transactTime"] = o.CTime
+	writeJSON(w, 200, map[string]any{"cancelResult": "SUCCESS", "newOrderResult": "SUCCESS",
+		"cancelResponse": s.bnOrder(canceled), "newOrderResponse": placed})
+}
+
+// 合约原地改单, orderId不变
+func (s *Server) bnAmendOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+	price, _ := decimal.NewFromString(q.Get("price"))
+	qty, _ := decimal.NewFromString(q.Get("quantity"))
+	o, err := s.Futures.AmendOrder(q.Get("symbol"), id, q.Get("origClientOrderId"), price, qty)
+	if err != nil {
+		s.bnEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.bnOrderResp(r, o))
+}
+func (s *Server) bnGetOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+	o, err := s.bnEngine(r).GetOrder(id, q.Get("origClientOrderId"))
+	if err != nil || o.Symbol != q.Get("symbol") {
+		s.bnEngineError(w, ErrOrderNotFound)
+		return
+	}
+	writeJSON(w, 200, s.bnOrderResp(r, o))
+}
+func (s *Server) bnOpenOrders(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, o := range s.bnEngine(r).OpenOrders(r.URL.Query().Get("symbol")) {
+		l = append(l, s.bnOrderResp(r, o))
+	}
+	if !s.bnIsFutures(r) {
+		for _, o := range s.Engine.OpenConditionalOrders(r.URL.Query().Get("symbol")) {
+			l = append(l, s.bnConditionalOrder(o))
+		}
+	}
+	writeJSON(w, 200, l)
+}
+
+// 现货返回撤销的订单列表, 合约返回 {"code":200}
+func (s *Server) bnCancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	e := s.bnEngine(r)
+	l := []any{}
+	for _, o := range e.OpenOrders(r.URL.Query().Get("symbol")) {
+		if o, err := e.CancelOrder(o.Symbol, o.Id, ""); err == nil {
+			l = append(l, s.bnOrderResp(r, o))
+		}
+	}
+	if e == s.Futures {
+		writeJSON(w, 200, map[string]any{"code": 200, "msg": "The operation of cancel all open order is done."})
+		return
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) bnOrder(o Order) map[string]any {
+	return map[string]any{
+		"symbol":              o.Symbol,
+		"orderId":             o.Id,
+		"clientOrderId":       o.ClientId,
+		"price":               o.Price,
+		"origQty":             o.Qty,
+		"executedQty":         o.FilledQty,
+		"cummulativeQuoteQty": o.FilledAmt,
+		"status":              o.Status,
+		"type":                o.Type,
+		"timeInForce":         o.TimeInForce,
+		"side":                o.Side,
+		"time":                o.CTime,
+		"updateTime":          o.UTime,
+	}
+}
+
+// 合约订单的成交额字段为cumQuote
+func (s *Server) bnOrderResp(r *http.Request, o Order) map[string]any {
+	v := s.bnOrder(o)
+	if s.bnIsFutures(r) {
+		delete(v, "cummulativeQuoteQty")
+		v["cumQuote"] = o.FilledAmt
+		v["positionSide"] = "BOTH"
+	}
+	return v
+}
+
+// ws
+func (s *Server) bnWsPublic(c *wsConn, msg []byte) {
+	req := struct {
+		Id     any      `json:"id"`
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	for _, p := range req.Params {
+		c.subscribe(p, req.Method == "SUBSCRIBE")
+	}
+	resp, _ := json.Marshal(map[string]any{"result": nil, "id": req.Id})
+	c.write(resp)
+	if req.Method == "SUBSCRIBE" { // 订阅后推送一次当前盘口
+		for _, p := range req.Params {
+			sym, _, _ := strings.Cut(p, "@")
+			s.bnPushBookTo(c, strings.ToUpper(sym))
+		}
+	}
+}
+func (s *Server) bnWsPrivate(c *wsConn, msg []byte) {
+	req := struct {
+		Id     string `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			ApiKey    string `json:"apiKey"`
+			Signature string `json:"signature"`
+			Timestamp int64  `json:"timestamp"`
+		} `json:"params"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	var resp []byte
+	if req.Method != "userDataStream.subscribe.signature" {
+		resp, _ = json.Marshal(map[string]any{"id": req.Id, "status": 400,
+			"error": map[string]any{"code": -1100, "msg": "method not supported by cextest"}})
+	} else if payload := fmt.Sprintf("apiKey=%s&timestamp=%d", req.Params.ApiKey, req.Params.Timestamp); req.Params.ApiKey != s.cfg.ApiKey ||
+		!hmac.Equal([]byte(req.Params.Signature), []byte(s.bnSign(payload))) {
+		resp, _ = json.Marshal(map[string]any{"id": req.Id, "status": 401,
+			"error": map[string]any{"code": -1022, "msg": "Signature for this request is not valid."}})
+	} else {
+		c.setAuthed()
+		resp, _ = json.Marshal(map[string]any{"id": req.Id, "status": 200, "result": map[string]any{}})
+	}
+	c.write(resp)
+}
+func (s *Server) bnPushBook(symbol string) {
+	for _, c := range s.wsConns("/stream") {
+		s.bnPushBookTo(c, symbol)
+	}
+}
+func (s *Server) bnPushKLine(symbol, interval string, k KLine) {
+	stream := strings.ToLower(symbol) + "@kline_" + interval
+	data := map[string]any{"e": "kline", "E": time.Now().UnixMilli(), "s": symbol,
+		"k": map[string]any{"t": k.OpenTime * 1000, "T": (k.OpenTime+intervalSeconds(interval))*1000 - 1,
+			"s": symbol, "i": interval, "o": k.Open.String(), "c": k.Close.String(),
+			"h": k.High.String(), "l": k.Low.String(), "v": k.Volume.String(),
+			"q": k.QuoteVolume.String(), "x": k.Closed}}
+	frame, _ := json.Marshal(map[string]any{"stream": stream, "data": data})
+	for _, c := range s.wsConns("/stream") {
+		if c.subscribed(stream) {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) bnPushBookTo(c *wsConn, symbol string) {
+	lower := strings.ToLower(symbol)
+	bids, asks := s.Engine.Book(symbol, 5)
+	if c.subscribed(lower+"@depth5@100ms") && len(bids) > 0 && len(bids) == len(asks) {
+		data := map[string]any{"lastUpdateId": time.Now().UnixNano(),
+			"bids": bnLevels(bids), "asks": bnLevels(asks)}
+		frame, _ := json.Marshal(map[string]any{"stream": lower + "@depth5@100ms", "data": data})
+		c.write(frame)
+	}
+	if c.subscribed(lower+"@bookTicker") && len(bids) > 0 && len(asks) > 0 {
+		data := map[string]any{"u": time.Now().UnixNano(), "s": symbol,
+			"b": bids[0].Price, "B": bids[0].Qty, "a": asks[0].Price, "A": asks[0].Qty}
+		frame, _ := json.Marshal(map[string]any{"stream": lower + "@bookTicker", "data": data})
+		c.write(frame)
+	}
+}
+func (s *Server) bnPushOrder(o Order) {
+	execType := "TRADE"
+	switch o.Status {
+	case "NEW":
+		execType = "NEW"
+	case "CANCELED":
+		execType = "CANCELED"
+	case "EXPIRED":
+		execType = "EXPIRED"
+	}
+	data := map[string]any{
+		"e": "executionReport", "E": time.Now().UnixMilli(),
+		"s": o.Symbol, "c": o.ClientId, "C": o.ClientId, "i": o.Id,
+		"S": o.Side, "o": o.Type, "f": o.TimeInForce, "O": o.CTime, "T": o.UTime,
+		"q": o.Qty, "Q": o.QuoteQty, "p": o.Price, "l": o.LastQty, "L": o.LastPrice,
+		"z": o.FilledQty, "Z": o.FilledAmt, "n": o.Fee, "N": o.FeeAsset,
+		"x": execType, "X": o.Status,
+	}
+	frame, _ := json.Marshal(map[string]any{"event": data})
+	for _, c := range s.wsConns("/ws-api/v3") {
+		if c.isAuthed() {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) bnPushBalance(b Balance) {
+	data := map[string]any{
+		"e": "outboundAccountPosition", "E": time.Now().UnixMilli(), "u": time.Now().UnixMilli(),
+		"B": []any{map[string]any{"a": b.Asset, "f": b.Free, "l": b.Locked}},
+	}
+	frame, _ := json.Marshal(map[string]any{"event": data})
+	for _, c := range s.wsConns("/ws-api/v3") {
+		if c.isAuthed() {
+			c.write(frame)
+		}
+	}
+}
+func bnLevels(l []Level) [][2]decimal.Decimal {
+	v := make([][2]decimal.Decimal, 0, len(l))
+	for _, lv := range l {
+		v = append(v, [2]decimal.Decimal{lv.Price, lv.Qty})
+	}
+	return v
+}
@@ -0,0 +1,562 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type bbOrderArg struct {
+	Category    string `json:"category"`
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"`
+	OrderType   string `json:"orderType"`
+	Qty         string `json:"qty"`
+	Price       string `json:"price"`
+	TimeInForce string `json:"timeInForce"`
+	OrderLinkId string `json:"orderLinkId"`
+	OrderId     string `json:"orderId"`
+	MarketUnit  string `json:"marketUnit"`
+	OrderFilter string `json:"orderFilter"`
+	// 条件单
+	TriggerPrice     string `json:"triggerPrice"`
+	TriggerBy        string `json:"triggerBy"`
+	TriggerDirection int    `json:"triggerDirection"`
+}
+
+var bbTriggerBys = map[string]string{"LastPrice": "MARKET_PRICE", "MarkPrice": "MARK_PRICE", "IndexPrice": "INDEX_PRICE"}
+
+func (s *Server) initBybit() {
+	s.route("GET", "/v5/market/time", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		s.bbResult(w, map[string]any{"timeSecond": itoa(now.Unix()), "timeNano": itoa(now.UnixNano())})
+	})
+	s.route("GET", "/v5/market/instruments-info", s.bbInstruments)
+	s.route("GET", "/v5/market/tickers", s.bbTickers)
+	s.route("GET", "/v5/account/wallet-balance", s.bbSigned(s.bbWalletBalance))
+	s.route("POST", "/v5/order/create", s.bbSigned(s.bbPlaceOrder))
+	s.route("POST", "/v5/order/cancel", s.bbSigned(s.bbCancelOrder))
+	s.route("POST", "/v5/order/cancel-all", s.bbSigned(s.bbCancelAllOrders))
+	s.route("POST", "/v5/order/amend", s.bbSigned(s.bbAmendOrder))
+	s.route("GET", "/v5/order/realtime", s.bbSigned(s.bbRealtime))
+
+	s.wsRoute("/v5/public/spot", s.bbWsPublic)
+	s.wsRoute("/v5/private", s.bbWsPrivate)
+	s.Engine.OnBook(s.bbPushBook)
+	s.Engine.OnOrder(s.bbPushOrder)
+	s.Engine.OnBalance(s.bbPushBalance)
+}
+
+func (s *Server) bbResult(w http.ResponseWriter, result any) {
+	writeJSON(w, 200, map[string]any{"retCode": 0, "retMsg": "OK", "result": result,
+		"time": time.Now().UnixMilli()})
+}
+func (s *Server) bbError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, 200, map[string]any{"retCode": code, "retMsg": msg, "result": map[string]any{},
+		"time": time.Now().UnixMilli()})
+}
+
+// category=linear 为U本位合约
+func (s *Server) bbEngine(category string) *Engine {
+	if category == "linear" {
+		return s.Futures
+	}
+	return s.Engine
+}
+func (s *Server) bbFuturesEngineError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInsufficientFunds:
+		s.bbError(w, 110007, "ab not enough for new order")
+	case ErrOrderNotFound:
+		s.bbError(w, 110001, "order not exists or too late to cancel")
+	default:
+		s.bbEngineError(w, err)
+	}
+}
+func (s *Server) bbEngineError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInsufficientFunds:
+		s.bbError(w, 170131, "Insufficient balance.")
+	case ErrOrderNotFound:
+		s.bbError(w, 170213, "Order does not exist.")
+	case ErrInvalidSymbol:
+		s.bbError(w, 170121, "Invalid symbol.")
+	default:
+		s.bbError(w, 10001, err.Error())
+	}
+}
+
+func (s *Server) bbSign(payload string) string {
+	h := hmac.New(sha256.New, []byte(s.cfg.SecretKey))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 校验 X-BAPI-SIGN = hex(hmac(ts + apikey + recvWindow + query|body))
+func (s *Server) bbSigned(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-BAPI-API-KEY") != s.cfg.ApiKey {
+			s.bbError(w, 10003, "API key is invalid.")
+			return
+		}
+		payload := r.Header.Get("X-BAPI-TIMESTAMP") + s.cfg.ApiKey +
+			r.Header.Get("X-BAPI-RECV-WINDOW") + r.URL.RawQuery + string(readBody(r))
+		if !hmac.Equal([]byte(r.Header.Get("X-BAPI-SIGN")), []byte(s.bbSign(payload))) {
+			s.bbError(w, 10004, "error sign!")
+			return
+		}
+		fn(w, r)
+	}
+}
+func (s *Server) bbInstruments(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	if category := r.URL.Query().Get("category"); category == "linear" {
+		for _, sym := range s.Futures.Symbols() {
+			l = append(l, map[string]any{
+				"symbol":       sym.Symbol,
+				"contractType": "LinearPerpetual",
+				"baseCoin":     sym.Base,
+				"quoteCoin":    sym.Quote,
+				"settleCoin":   sym.Quote,
+				"status":       "Trading",
+				"lotSizeFilter": map[string]any{
+					"qtyStep":          sym.StepSize,
+					"minOrderQty":      sym.MinQty,
+					"maxOrderQty":      "9000000",
+					"minNotionalValue": sym.MinNotional,
+				},
+				"priceFilter": map[string]any{"tickSize": sym.TickSize,
+					"minPrice": sym.TickSize, "maxPrice": "1000000"},
+			})
+		}
+		s.bbResult(w, map[string]any{"category": category, "list": l})
+		return
+	}
+	for _, sym := range s.Engine.Symbols() {
+		l = append(l, map[string]any{
+			"symbol":    sym.Symbol,
+			"baseCoin":  sym.Base,
+			"quoteCoin": sym.Quote,
+			"status":    "Trading",
+			"lotSizeFilter": map[string]any{
+				"basePrecision":  sym.StepSize,
+				"quotePrecision": sym.TickSize,
+				"minOrderQty":    sym.MinQty,
+				"maxOrderQty":    "9000000",
+				"minOrderAmt":    sym.MinNotional,
+				"maxOrderAmt":    "90000000",
+			},
+			"priceFilter": map[string]any{"tickSize": sym.TickSize},
+		})
+	}
+	s.bbResult(w, map[string]any{"category": "spot", "list": l})
+}
+func (s *Server) bbTickers(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+	if _, ok := s.Engine.Symbol(symbol); !ok {
+		s.bbEngineError(w, ErrInvalidSymbol)
+		return
+	}
+	bids, asks := s.Engine.Book(symbol, 1)
+	v := map[string]any{"symbol": symbol}
+	if len(bids) > 0 {
+		v["bid1Price"], v["bid1Size"] = bids[0].Price, bids[0].Qty
+	}
+	if len(asks) > 0 {
+		v["ask1Price"], v["ask1Size"] = asks[0].Price, asks[0].Qty
+	}
+	s.bbResult(w, map[string]any{"category": "spot", "list": []any{v}})
+}
+func (s *Server) bbWalletBalance(w http.ResponseWriter, r *http.Request) {
+	coins := []any{}
+	for _, b := range s.Engine.Balances() {
+		coins = append(coins, map[string]any{"coin": b.Asset, "equity": b.Free.Add(b.Locked),
+			"walletBalance": b.Free.Add(b.Locked), "locked": b.Locked})
+	}
+	s.bbResult(w, map[string]any{"list": []any{map[string]any{"accountType": "UNIFIED", "coin": coins}}})
+}
+func (s *Server) bbPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var arg bbOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.bbError(w, 10001, "params error")
+		return
+	}
+	if arg.TriggerPrice != "" {
+		s.bbPlaceConditionalOrder(w, arg)
+		return
+	}
+	req := OrderRequest{
+		Symbol:      arg.Symbol,
+		ClientId:    arg.OrderLinkId,
+		TimeInForce: arg.TimeInForce,
+	}
+	switch arg.Side {
+	case "Buy":
+		req.Side = "BUY"
+	case "Sell":
+		req.Side = "SELL"
+	}
+	switch arg.OrderType {
+	case "Limit":
+		req.Type = "LIMIT"
+	case "Market":
+		req.Type = "MARKET"
+	}
+	if req.TimeInForce == "PostOnly" {
+		req.TimeInForce, req.PostOnly = "GTC", true
+	}
+	qty, _ := decimal.NewFromString(arg.Qty)
+	req.Price, _ = decimal.NewFromString(arg.Price)
+	req.Qty = qty
+	if req.Type == "MARKET" && (arg.MarketUnit == "quoteCoin" ||
+		(arg.MarketUnit == "" && req.Side == "BUY")) {
+		req.Qty, req.QuoteQty = decimal.Zero, qty
+	}
+	if arg.Category == "linear" { // 合约qty都是标的数量
+		req.Qty, req.QuoteQty = qty, decimal.Zero
+	}
+	o, err := s.bbEngine(arg.Category).PlaceOrder(req)
+	if err != nil && arg.Category == "linear" {
+		s.bbFuturesEngineError(w, err)
+		return
+	} else if err != nil {
+		s.bbEngineError(w, err)
+		return
+	}
+	s.bbResult(w, map[string]any{"orderId": itoa(o.Id), "orderLinkId": o.ClientId})
+}
+func (s *Server) bbCancelOrder(w http.ResponseWriter, r *http.Request) {
+	var arg bbOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.bbError(w, 10001, "params error")
+		return
+	}
+	id, _ := strconv.ParseInt(arg.OrderId, 10, 64)
+	e := s.bbEngine(arg.Category)
+	o, err := e.CancelOrder(arg.Symbol, id, arg.OrderLinkId)
+	// 现货条件单需要orderFilter=StopOrder, 合约条件单和普通订单共用撤单接口
+	if arg.OrderFilter == "StopOrder" || (arg.Category == "linear" && errors.Is(err, ErrOrderNotFound)) {
+		co, err := e.CancelConditionalOrder(arg.Symbol, id, arg.OrderLinkId)
+		if err != nil {
+			s.bbEngineError(w, err)
+			return
+		}
+		s.bbResult(w, map[string]any{"orderId": itoa(co.Id), "orderLinkId": co.ClientId})
+		return
+	}
+	if err != nil && arg.Category == "linear" {
+		s.bbFuturesEngineError(w, err)
+		return
+	} else if err != nil {
+		s.bbEngineError(w, err)
+		return
+	}
+	s.bbResult(w, map[string]any{"orderId": itoa(o.Id), "orderLinkId": o.ClientId})
+}
+func (s *Server) bbAmendOrder(w http.ResponseWriter, r *http.Request) {
+	var arg bbOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.bbError(w, 10001, "params error")
+		return
+	}
+	id, _ := strconv.ParseInt(arg.OrderId, 10, 64)
+	price, _ := decimal.NewFromString(arg.Price)
+	qty, _ := decimal.NewFromString(arg.Qty)
+	o, err := s.bbEngine(arg.Category).AmendOrder(arg.Symbol, id, arg.OrderLinkId, price, qty)
+	if err != nil && arg.Category == "linear" {
+		s.bbFuturesEngineError(w, err)
+		return
+	} else if err != nil {
+		s.bbEngineError(w, err)
+		return
+	}
+	s.bbResult(w, map[string]any{"orderId": itoa(o.Id), "orderLinkId": o.ClientId})
+}
+func (s *Server) bbCancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	var arg bbOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.bbError(w, 10001, "params error")
+		return
+	}
+	e := s.bbEngine(arg.Category)
+	l := []any{}
+	for _, o := range e.OpenOrders(arg.Symbol) {
+		if o, err := e.CancelOrder(o.Symbol, o.Id, ""); err == nil {
+			l = append(l, map[string]any{"orderId": itoa(o.Id), "orderLinkId": o.ClientId})
+		}
+	}
+	s.bbResult(w, map[string]any{"list": l, "success": "1"})
+}
+
+// 现货条件单需要orderFilter=StopOrder, 合约条件单是带triggerPrice的普通下单
+func (s *Server) bbPlaceConditionalOrder(w http.ResponseWriter, arg bbOrderArg) {
+	o := ConditionalOrder{Symbol: arg.Symbol, ClientId: arg.OrderLinkId, Side: strings.ToUpper(arg.Side),
+		TriggerBy: bbTriggerBys[arg.TriggerBy]}
+	o.TriggerPrice, _ = decimal.NewFromString(arg.TriggerPrice)
+	o.Qty, _ = decimal.NewFromString(arg.Qty)
+	e := s.bbEngine(arg.Category)
+	if arg.Category == "spot" && arg.OrderFilter != "StopOrder" {
+		s.bbError(w, 10001, "params error")
+		return
+	}
+	// 现货没有triggerDirection, 按当前价格判断
+	rise := arg.TriggerDirection == 1
+	if arg.Category == "spot" {
+		rise = o.TriggerPrice.GreaterThan(s.bbLastPrice(e, arg.Symbol))
+	}
+	o.Type = "TAKE_PROFIT"
+	if rise == (o.Side == "BUY") {
+		o.Type = "STOP"
+	}
+	o.Type += "_MARKET"
+	if arg.OrderType == "Limit" {
+		o.Type = strings.TrimSuffix(o.Type, "_MARKET") + "_LIMIT"
+		o.Price, _ = decimal.NewFromString(arg.Price)
+	}
+	o, err := e.PlaceConditionalOrder(o)
+	if err != nil {
+		s.bbEngineError(w, err)
+		return
+	}
+	s.bbResult(w, map[string]any{"orderId": itoa(o.Id), "orderLinkId": o.ClientId})
+}
+func (s *Server) bbLastPrice(e *Engine, symbol string) decimal.Decimal {
+	if bids, _ := e.Book(symbol, 1); len(bids) > 0 {
+		return bids[0].Price
+	}
+	return decimal.Zero
+}
+func (s *Server) bbConditionalOrder(e *Engine, o ConditionalOrder) map[string]any {
+	triggerBy := ""
+	for k, v := range bbTriggerBys {
+		if v == o.TriggerBy {
+			triggerBy = k
+		}
+	}
+	orderType, direction := "Market", 2
+	if o.IsLimit() {
+		orderType = "Limit"
+	}
+	if o.TriggerRise() {
+		direction = 1
+	}
+	side := "Buy"
+	if o.Side == "SELL" {
+		side = "Sell"
+	}
+	return map[string]any{
+		"symbol":             o.Symbol,
+		"orderId":            itoa(o.Id),
+		"orderLinkId":        o.ClientId,
+		"price":              o.Price,
+		"qty":                o.Qty,
+		"orderType":          orderType,
+		"side":               side,
+		"triggerPrice":       o.TriggerPrice,
+		"triggerBy":          triggerBy,
+		"triggerDirection":   direction,
+		"lastPriceOnCreated": s.bbLastPrice(e, o.Symbol),
+		"orderStatus":        "Untriggered",
+		"createdTime":        itoa(o.CTime),
+	}
+}
+
+// 指定orderId/orderLinkId时返回该订单, 否则返回挂单, orderFilter=StopOrder时返回条件单
+func (s *Server) bbRealtime(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	e := s.bbEngine(q.Get("category"))
+	l := []any{}
+	if q.Get("orderFilter") == "StopOrder" {
+		for _, o := range e.OpenConditionalOrders(q.Get("symbol")) {
+			l = append(l, s.bbConditionalOrder(e, o))
+		}
+	} else if q.Get("orderId") != "" || q.Get("orderLinkId") != "" {
+		id, _ := strconv.ParseInt(q.Get("orderId"), 10, 64)
+		if o, err := e.GetOrder(id, q.Get("orderLinkId")); err == nil &&
+			(q.Get("symbol") == "" || o.Symbol == q.Get("symbol")) {
+			l = append(l, s.bbOrder(o))
+		}
+	} else {
+		for _, o := range e.OpenOrders(q.Get("symbol")) {
+			l = append(l, s.bbOrder(o))
+		}
+	}
+	s.bbResult(w, map[string]any{"category": q.Get("category"), "list": l})
+}
+func (s *Server) bbOrder(o Order) map[string]any {
+	status := "Cancelled"
+	switch o.Status {
+	case "NEW":
+		status = "New"
+	case "PARTIALLY_FILLED":
+		status = "PartiallyFilled"
+	case "FILLED":
+		status = "Filled"
+	case "REJECTED":
+		status = "Rejected"
+	}
+	side := "Buy"
+	if o.Side == "SELL" {
+		side = "Sell"
+	}
+	orderType := "Limit"
+	if o.Type == "MARKET" {
+		orderType = "Market"
+	}
+	timeInForce := o.TimeInForce
+	if o.PostOnly {
+		timeInForce = "PostOnly"
+	}
+	qty := o.Qty
+	if o.Type == "MARKET" && o.QuoteQty.IsPositive() {
+		qty = o.QuoteQty
+	}
+	feeDetail := map[string]string{}
+	if o.FeeAsset != "" {
+		feeDetail[o.FeeAsset] = o.Fee.String()
+	}
+	return map[string]any{
+		"symbol":       o.Symbol,
+		"orderId":      itoa(o.Id),
+		"orderLinkId":  o.ClientId,
+		"price":        o.Price,
+		"qty":          qty,
+		"orderType":    orderType,
+		"timeInForce":  timeInForce,
+		"side":         side,
+		"cumExecQty":   o.FilledQty,
+		"cumExecValue": o.FilledAmt,
+		"cumExecFee":   o.Fee,
+		"avgPrice":     o.AvgPrice(),
+		"orderStatus":  status,
+		"createdTime":  itoa(o.CTime),
+		"updatedTime":  itoa(o.UTime),
+		"cumFeeDetail": feeDetail,
+	}
+}
+
+// ws
+type bbWsReq struct {
+	Id   string `json:"req_id"`
+	Op   string `json:"op"`
+	Args []any  `json:"args"`
+}
+
+func (s *Server) bbWsPublic(c *wsConn, msg []byte) {
+	var req bbWsReq
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	if req.Op == "ping" {
+		resp, _ := json.Marshal(map[string]any{"success": true, "ret_msg": "pong", "op": "ping"})
+		c.write(resp)
+		return
+	}
+	var symbols []string
+	for _, v := range req.Args {
+		topic, _ := v.(string)
+		c.subscribe(topic, req.Op == "subscribe")
+		if i := strings.LastIndex(topic, "."); i != -1 {
+			symbols = append(symbols, topic[i+1:])
+		}
+	}
+	resp, _ := json.Marshal(map[string]any{"success": true, "ret_msg": "", "req_id": req.Id, "op": req.Op})
+	c.write(resp)
+	if req.Op == "subscribe" { // 订阅后推送一次当前盘口
+		for _, symbol := range symbols {
+			s.bbPushBookTo(c, symbol)
+		}
+	}
+}
+func (s *Server) bbWsPrivate(c *wsConn, msg []byte) {
+	var req bbWsReq
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	var resp []byte
+	switch req.Op {
+	case "ping":
+		resp, _ = json.Marshal(map[string]any{"success": true, "ret_msg": "pong", "op": "ping"})
+	case "auth":
+		// args: [apikey, expires, hex(hmac("GET/realtime" + expires))]
+		ok := len(req.Args) == 3
+		if ok {
+			key, _ := req.Args[0].(string)
+			expires, _ := req.Args[1].(float64)
+			sign, _ := req.Args[2].(string)
+			ok = key == s.cfg.ApiKey &&
+				hmac.Equal([]byte(sign), []byte(s.bbSign("GET/realtime"+itoa(int64(expires)))))
+		}
+		if ok {
+			c.setAuthed()
+			resp, _ = json.Marshal(map[string]any{"success": true, "ret_msg": "", "op": "auth"})
+		} else {
+			resp, _ = json.Marshal(map[string]any{"success": false, "ret_msg": "Params Error", "op": "auth"})
+		}
+	case "subscribe", "unsubscribe":
+		if !c.isAuthed() {
+			resp, _ = json.Marshal(map[string]any{"success": false, "ret_msg": "Request not authorized",
+				"req_id": req.Id, "op": req.Op})
+			break
+		}
+		for _, v := range req.Args {
+			topic, _ := v.(string)
+			c.subscribe(topic, req.Op == "subscribe")
+		}
+		resp, _ = json.Marshal(map[string]any{"success": true, "ret_msg": "", "req_id": req.Id, "op": req.Op})
+	}
+	if resp != nil {
+		c.write(resp)
+	}
+}
+func (s *Server) bbPushBook(symbol string) {
+	for _, c := range s.wsConns("/v5/public/spot") {
+		s.bbPushBookTo(c, symbol)
+	}
+}
+
+// orderbook.{depth}.{symbol}, 都以snapshot推送
+func (s *Server) bbPushBookTo(c *wsConn, symbol string) {
+	for _, depth := range []int{1, 50, 200, 1000} {
+		topic := "orderbook." + strconv.Itoa(depth) + "." + symbol
+		if !c.subscribed(topic) {
+			continue
+		}
+		bids, asks := s.Engine.Book(symbol, depth)
+		if len(bids) == 0 || len(asks) == 0 {
+			continue
+		}
+		frame, _ := json.Marshal(map[string]any{"topic": topic, "type": "snapshot",
+			"ts": time.Now().UnixMilli(), "data": map[string]any{"s": symbol,
+				"b": bnLevels(bids), "a": bnLevels(asks), "u": time.Now().UnixNano()}})
+		c.write(frame)
+	}
+}
+func (s *Server) bbPushOrder(o Order) {
+	frame, _ := json.Marshal(map[string]any{"topic": "order.spot",
+		"creationTime": time.Now().UnixMilli(), "data": []any{s.bbOrder(o)}})
+	for _, c := range s.wsConns("/v5/private") {
+		if c.subscribed("order.spot") {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) bbPushBalance(b Balance) {
+	coin := map[string]any{"coin": b.Asset, "equity": b.Free.Add(b.Locked),
+		"walletBalance": b.Free.Add(b.Locked), "locked": b.Locked}
+	frame, _ := json.Marshal(map[string]any{"topic": "wallet", "creationTime": time.Now().UnixMilli(),
+		"data": []any{map[string]any{"accountType": "UNIFIED", "coin": []any{coin}}}})
+	for _, c := range s.wsConns("/v5/private") {
+		if c.subscribed("wallet") {
+			c.write(frame)
+		}
+	}
+}
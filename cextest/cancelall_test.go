@@ -0,0 +1,114 @@
+package cextest
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSpotCancelAllOrders(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "bybit", "gate", "kraken"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			for i := int64(0); i < 3; i++ {
+				_, err := ex.SpotPlaceOrder("BTCUSDT", "", decimal.NewFromInt(90+i), decimal.Zero,
+					decimal.NewFromInt(1), "BUY", "GTC", "LIMIT", false)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			symbol := "BTCUSDT"
+			if name == "kraken" { // kraken 只能撤销全部交易对
+				symbol = ""
+			}
+			if err := ex.SpotCancelAllOrders(symbol); err != nil {
+				t.Fatal(err)
+			}
+			if l := srv.Engine.OpenOrders("BTCUSDT"); len(l) != 0 {
+				t.Fatalf("want no open orders got %d", len(l))
+			}
+			if b := srv.Engine.Balance("USDT"); !b.Locked.IsZero() {
+				t.Fatalf("want nothing locked got %s", b.Locked)
+			}
+		})
+	}
+}
+func TestFuturesCancelAllOrders(t *testing.T) {
+	for _, name := range []string{"binance", "bybit", "okx"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			for i := int64(0); i < 3; i++ {
+				_, err := ex.FuturesPlaceOrder("UM", "BTCUSDT", "", decimal.NewFromInt(110+i),
+					decimal.NewFromInt(1), "SELL", "LIMIT", "GTC", "BOTH", 0, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := ex.FuturesCancelAllOrders("UM", "BTCUSDT"); err != nil {
+				t.Fatal(err)
+			}
+			if l := srv.Futures.OpenOrders("BTCUSDT"); len(l) != 0 {
+				t.Fatalf("want no open orders got %d", len(l))
+			}
+			if b := srv.Futures.Balance("USDT"); !b.Locked.IsZero() {
+				t.Fatalf("want no margin locked got %s", b.Locked)
+			}
+		})
+	}
+}
+func placeTestOrders(t *testing.T, e *Engine, n int) {
+	for i := 0; i < n; i++ {
+		_, err := e.PlaceOrder(OrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT",
+			Price: decimal.NewFromInt(90), Qty: decimal.NewFromFloat(0.1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// okx 每次最多查到100个挂单, 需要分页撤销
+func TestOkxSpotCancelAllOrdersPaged(t *testing.T) {
+	srv, ex := newTestServer(t, "okx")
+	placeTestOrders(t, srv.Engine, 130)
+	if err := ex.SpotCancelAllOrders("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if l := srv.Engine.OpenOrders("BTCUSDT"); len(l) != 0 {
+		t.Fatalf("want no open orders got %d", len(l))
+	}
+
+	// 第一批撤单失败, 其它批次继续撤销, 返回所有失败订单的错误
+	placeTestOrders(t, srv.Engine, 130)
+	srv.FailNext("POST", "/api/v5/trade/cancel-batch-orders", 200,
+		`{"code":"50013","msg":"Systems are busy. Please try again later.","data":[]}`)
+	err := ex.SpotCancelAllOrders("BTCUSDT")
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 20 {
+		t.Fatalf("want 20 joined errors got %v", err)
+	}
+	if l := srv.Engine.OpenOrders("BTCUSDT"); len(l) != 20 {
+		t.Fatalf("want 20 open orders left got %d", len(l))
+	}
+}
+func TestOkxFuturesCancelAllOrdersPaged(t *testing.T) {
+	srv, ex := newTestFuturesServer(t, "okx")
+	placeTestOrders(t, srv.Futures, 130)
+	if err := ex.FuturesCancelAllOrders("UM", "BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if l := srv.Futures.OpenOrders("BTCUSDT"); len(l) != 0 {
+		t.Fatalf("want no open orders got %d", len(l))
+	}
+
+	placeTestOrders(t, srv.Futures, 130)
+	srv.FailNext("POST", "/api/v5/trade/cancel-batch-orders", 200,
+		`{"code":"50013","msg":"Systems are busy. Please try again later.","data":[]}`)
+	err := ex.FuturesCancelAllOrders("UM", "BTCUSDT")
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 20 {
+		t.Fatalf("want 20 joined errors got %v", err)
+	}
+	if l := srv.Futures.OpenOrders("BTCUSDT"); len(l) != 20 {
+		t.Fatalf("want 20 open orders left got %d", len(l))
+	}
+}
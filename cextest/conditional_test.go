@@ -0,0 +1,102 @@
+package cextest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shaovie/cex"
+	"github.com/shopspring/decimal"
+)
+
+func checkConditionalOrder(t *testing.T, o *cex.ConditionalOrder, id, typ, side string, trigger, price, qty int64) {
+	t.Helper()
+	if o.OrderId != id || o.Symbol != "BTCUSDT" || o.Type != typ || o.Side != side || o.Status != "NEW" ||
+		o.TriggerBy != "MARKET_PRICE" || !o.TriggerPrice.Equal(decimal.NewFromInt(trigger)) ||
+		!o.Price.Equal(decimal.NewFromInt(price)) || !o.Qty.Equal(decimal.NewFromInt(qty)) {
+		t.Fatalf("unexpected conditional order %+v", *o)
+	}
+}
+
+// 止损限价卖单和止盈市价卖单, 下单后查询再撤销
+func TestSpotConditionalOrder(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "bybit", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			stopId, err := ex.SpotPlaceConditionalOrder("BTCUSDT", "", decimal.NewFromInt(95), decimal.NewFromInt(94),
+				decimal.NewFromInt(1), "SELL", "STOP_LIMIT", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tpId, err := ex.SpotPlaceConditionalOrder("BTCUSDT", "", decimal.NewFromInt(110), decimal.Zero,
+				decimal.NewFromInt(2), "SELL", "TAKE_PROFIT_MARKET", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			l, err := ex.SpotGetOpenConditionalOrders("BTCUSDT")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 2 {
+				t.Fatalf("want 2 conditional orders got %d", len(l))
+			}
+			checkConditionalOrder(t, l[0], stopId, "STOP_LIMIT", "SELL", 95, 94, 1)
+			checkConditionalOrder(t, l[1], tpId, "TAKE_PROFIT_MARKET", "SELL", 110, 0, 2)
+			if ol := srv.Engine.OpenOrders("BTCUSDT"); len(ol) != 0 {
+				t.Fatalf("conditional orders should not be open orders, got %d", len(ol))
+			}
+
+			for _, id := range []string{stopId, tpId} {
+				if err = ex.SpotCancelConditionalOrder("BTCUSDT", id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if l, err = ex.SpotGetOpenConditionalOrders("BTCUSDT"); err != nil || len(l) != 0 {
+				t.Fatalf("want no conditional orders got %d %v", len(l), err)
+			}
+			if err = ex.SpotCancelConditionalOrder("BTCUSDT", stopId); !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Fatalf("want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
+func TestFuturesConditionalOrder(t *testing.T) {
+	for _, name := range []string{"binance", "bybit", "okx", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			stopId, err := ex.FuturesPlaceConditionalOrder("UM", "BTCUSDT", "", decimal.NewFromInt(105),
+				decimal.NewFromInt(106), decimal.NewFromInt(1), "BUY", "STOP_LIMIT", "", "BOTH", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tpId, err := ex.FuturesPlaceConditionalOrder("UM", "BTCUSDT", "", decimal.NewFromInt(90),
+				decimal.Zero, decimal.NewFromInt(2), "BUY", "TAKE_PROFIT_MARKET", "", "BOTH", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l, err := ex.FuturesGetOpenConditionalOrders("UM", "BTCUSDT")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 2 {
+				t.Fatalf("want 2 conditional orders got %d", len(l))
+			}
+			checkConditionalOrder(t, l[0], stopId, "STOP_LIMIT", "BUY", 105, 106, 1)
+			checkConditionalOrder(t, l[1], tpId, "TAKE_PROFIT_MARKET", "BUY", 90, 0, 2)
+			if b := srv.Futures.Balance("USDT"); !b.Locked.IsZero() {
+				t.Fatalf("conditional orders should not lock margin, got %s", b.Locked)
+			}
+
+			for _, id := range []string{stopId, tpId} {
+				if err = ex.FuturesCancelConditionalOrder("UM", "BTCUSDT", id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if l, err = ex.FuturesGetOpenConditionalOrders("UM", "BTCUSDT"); err != nil || len(l) != 0 {
+				t.Fatalf("want no conditional orders got %d %v", len(l), err)
+			}
+			if err = ex.FuturesCancelConditionalOrder("UM", "BTCUSDT", stopId); !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Fatalf("want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
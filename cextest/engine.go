@@ -0,0 +1,860 @@
+package cextest
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 撮合引擎返回的错误, 各协议转成交易所的原生错误码
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidSymbol     = errors.New("invalid symbol")
+	ErrInvalidOrder      = errors.New("invalid order")
+)
+
+type Symbol struct {
+	Symbol      string // BTCUSDT
+	Base        string // BTC
+	Quote       string // USDT
+	TickSize    decimal.Decimal
+	StepSize    decimal.Decimal
+	MinQty      decimal.Decimal
+	MinNotional decimal.Decimal
+	// 合约面值(标的数量), 只用于合约, okx/gate按张下单, 默认1
+	ContractSize decimal.Decimal
+}
+
+type Level struct {
+	Price decimal.Decimal
+	Qty   decimal.Decimal
+}
+
+// 字段含义与 cex.KLine 相同, Volume为base数量
+type KLine struct {
+	OpenTime    int64 // sec
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	Volume      decimal.Decimal
+	QuoteVolume decimal.Decimal
+	Closed      bool
+}
+
+type Balance struct {
+	Asset  string
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+}
+
+type OrderRequest struct {
+	Symbol      string // BTCUSDT
+	ClientId    string
+	Side        string // BUY/SELL
+	Type        string // LIMIT/MARKET
+	TimeInForce string // GTC/IOC/FOK, 空为GTC
+	PostOnly    bool
+	Price       decimal.Decimal
+	Qty         decimal.Decimal // base数量
+	QuoteQty    decimal.Decimal // 市价买单按金额下单
+}
+
+// 字段含义与 cex.SpotOrder 相同
+type Order struct {
+	Id          int64
+	ClientId    string
+	Symbol      string
+	Side        string
+	Type        string
+	TimeInForce string
+	PostOnly    bool
+	Price       decimal.Decimal
+	Qty         decimal.Decimal
+	QuoteQty    decimal.Decimal
+	FilledQty   decimal.Decimal
+	FilledAmt   decimal.Decimal
+	LastQty     decimal.Decimal // 末次成交数量
+	LastPrice   decimal.Decimal // 末次成交价格
+	Fee         decimal.Decimal // 正数
+	FeeAsset    string
+	Status      string // NEW/PARTIALLY_FILLED/FILLED/CANCELED/REJECTED/EXPIRED
+	CTime       int64  // msec
+	UTime       int64  // msec
+
+	locked decimal.Decimal // 冻结的资产(买单为quote, 卖单为base, 合约都为quote)
+}
+
+// 条件单只记录, 不会触发, 字段含义与 cex.ConditionalOrder 相同
+type ConditionalOrder struct {
+	Id           int64
+	ClientId     string
+	Symbol       string
+	Side         string
+	Type         string // STOP_MARKET/STOP_LIMIT/TAKE_PROFIT_MARKET/TAKE_PROFIT_LIMIT
+	TriggerBy    string // MARKET_PRICE/MARK_PRICE/INDEX_PRICE
+	TriggerPrice decimal.Decimal
+	Price        decimal.Decimal // 市价为0
+	Qty          decimal.Decimal // base数量
+	Status       string          // NEW/CANCELED
+	CTime        int64           // msec
+}
+
+// 价格上涨到触发价时触发, 止损买单和止盈卖单
+func (o *ConditionalOrder) TriggerRise() bool {
+	return strings.HasPrefix(o.Type, "STOP") == (o.Side == "BUY")
+}
+func (o *ConditionalOrder) IsLimit() bool {
+	return strings.HasSuffix(o.Type, "_LIMIT")
+}
+
+func (o *Order) IsFinal() bool {
+	return o.Status == "FILLED" || o.Status == "CANCELED" ||
+		o.Status == "REJECTED" || o.Status == "EXPIRED"
+}
+func (o *Order) AvgPrice() decimal.Decimal {
+	if o.FilledQty.IsZero() {
+		return decimal.Zero
+	}
+	return o.FilledAmt.Div(o.FilledQty)
+}
+
+// 可脚本化的撮合引擎
+// 订单只和 SetBook 设置的外部盘口撮合, 也可以通过 Fill 模拟对手方成交
+type Engine struct {
+	mtx       sync.Mutex
+	nextId    int64
+	feeRate   decimal.Decimal
+	futures   bool
+	symbols   map[string]*Symbol
+	balances  map[string]*Balance
+	orders    map[int64]*Order
+	algos     map[int64]*ConditionalOrder
+	positions map[string]decimal.Decimal // 合约持仓, 多为正空为负
+	bids      map[string][]Level         // 价格从高到低
+	asks      map[string][]Level         // 价格从低到高
+	klines    map[string][]KLine         // symbol+"@"+interval, OpenTime升序
+
+	onOrder   []func(Order)
+	onBalance []func(Balance)
+	onBook    []func(symbol string)
+	onKLine   []func(symbol, interval string, k KLine)
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		nextId:    1000000,
+		feeRate:   decimal.NewFromFloat(0.001),
+		symbols:   make(map[string]*Symbol),
+		balances:  make(map[string]*Balance),
+		orders:    make(map[int64]*Order),
+		algos:     make(map[int64]*ConditionalOrder),
+		positions: make(map[string]decimal.Decimal),
+		bids:      make(map[string][]Level),
+		asks:      make(map[string][]Level),
+		klines:    make(map[string][]KLine),
+	}
+}
+
+// U本位合约撮合引擎, 买卖都按 价格*数量 冻结quote作为保证金(不计杠杆)
+// 成交后释放保证金并记录持仓, 不模拟盈亏/强平/资金费
+func NewFuturesEngine() *Engine {
+	e := NewEngine()
+	e.futures = true
+	return e
+}
+
+// 手续费率, 默认0.001, 从收到的资产中扣除
+func (e *Engine) SetFeeRate(v decimal.Decimal) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.feeRate = v
+}
+func (e *Engine) AddSymbol(sym Symbol) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if sym.TickSize.IsZero() {
+		sym.TickSize = decimal.NewFromFloat(0.01)
+	}
+	if sym.StepSize.IsZero() {
+		sym.StepSize = decimal.NewFromFloat(0.0001)
+	}
+	if sym.MinQty.IsZero() {
+		sym.MinQty = sym.StepSize
+	}
+	if sym.ContractSize.IsZero() {
+		sym.ContractSize = decimal.NewFromInt(1)
+	}
+	e.symbols[sym.Symbol] = &sym
+}
+func (e *Engine) Symbols() []Symbol {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	l := make([]Symbol, 0, len(e.symbols))
+	for _, s := range e.symbols {
+		l = append(l, *s)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Symbol < l[j].Symbol })
+	return l
+}
+func (e *Engine) Symbol(symbol string) (Symbol, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return Symbol{}, false
+	}
+	return *s, true
+}
+
+// 设置可用余额, 冻结部分不变
+func (e *Engine) SetBalance(asset string, free decimal.Decimal) {
+	e.mtx.Lock()
+	b := e.balance(asset)
+	b.Free = free
+	bv := *b
+	e.mtx.Unlock()
+	e.emitBalance(bv)
+}
+func (e *Engine) Balances() []Balance {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	l := make([]Balance, 0, len(e.balances))
+	for _, b := range e.balances {
+		l = append(l, *b)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Asset < l[j].Asset })
+	return l
+}
+func (e *Engine) Balance(asset string) Balance {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return *e.balance(asset)
+}
+
+// 设置外部盘口, 设置后会用新盘口撮合挂单
+func (e *Engine) SetBook(symbol string, bids, asks []Level) {
+	e.mtx.Lock()
+	b := append([]Level(nil), bids...)
+	a := append([]Level(nil), asks...)
+	sort.Slice(b, func(i, j int) bool { return b[i].Price.GreaterThan(b[j].Price) })
+	sort.Slice(a, func(i, j int) bool { return a[i].Price.LessThan(a[j].Price) })
+	e.bids[symbol] = b
+	e.asks[symbol] = a
+	var updated []*Order
+	for _, o := range e.sortedOrders() {
+		if o.Symbol != symbol || o.IsFinal() {
+			continue
+		}
+		if e.match(o) {
+			updated = append(updated, o)
+		}
+	}
+	orders, balances := e.snapshot(updated)
+	e.mtx.Unlock()
+	e.emit(orders, balances)
+	e.emitBook(symbol)
+}
+func (e *Engine) Book(symbol string, depth int) ([]Level, []Level) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	b, a := e.bids[symbol], e.asks[symbol]
+	if depth > 0 {
+		b, a = b[:min(depth, len(b))], a[:min(depth, len(a))]
+	}
+	return append([]Level(nil), b...), append([]Level(nil), a...)
+}
+
+// 按OpenTime更新或追加k线, 并推送给订阅了该symbol和周期的连接
+func (e *Engine) SetKLine(symbol, interval string, k KLine) {
+	e.mtx.Lock()
+	key := symbol + "@" + interval
+	l := e.klines[key]
+	i := sort.Search(len(l), func(i int) bool { return l[i].OpenTime >= k.OpenTime })
+	if i < len(l) && l[i].OpenTime == k.OpenTime {
+		l[i] = k
+	} else {
+		l = append(l, KLine{})
+		copy(l[i+1:], l[i:])
+		l[i] = k
+	}
+	e.klines[key] = l
+	onKLine := e.onKLine
+	e.mtx.Unlock()
+	for _, fn := range onKLine {
+		fn(symbol, interval, k)
+	}
+}
+
+// OpenTime在[start, end)内的k线, start/end为0表示不限
+func (e *Engine) KLines(symbol, interval string, start, end int64) []KLine {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	var l []KLine
+	for _, k := range e.klines[symbol+"@"+interval] {
+		if (start == 0 || k.OpenTime >= start) && (end == 0 || k.OpenTime < end) {
+			l = append(l, k)
+		}
+	}
+	return l
+}
+
+func (e *Engine) PlaceOrder(req OrderRequest) (Order, error) {
+	e.mtx.Lock()
+	sym, ok := e.symbols[req.Symbol]
+	if !ok {
+		e.mtx.Unlock()
+		return Order{}, ErrInvalidSymbol
+	}
+	if req.Side != "BUY" && req.Side != "SELL" {
+		e.mtx.Unlock()
+		return Order{}, ErrInvalidOrder
+	}
+	if req.TimeInForce == "" {
+		req.TimeInForce = "GTC"
+	}
+	now := time.Now().UnixMilli()
+	e.nextId++
+	o := &Order{
+		Id:          e.nextId,
+		ClientId:    req.ClientId,
+		Symbol:      req.Symbol,
+		Side:        req.Side,
+		Type:        req.Type,
+		TimeInForce: req.TimeInForce,
+		PostOnly:    req.PostOnly,
+		Price:       req.Price,
+		Qty:         req.Qty,
+		QuoteQty:    req.QuoteQty,
+		Status:      "NEW",
+		CTime:       now,
+		UTime:       now,
+	}
+	switch req.Type {
+	case "LIMIT":
+		if !req.Price.IsPositive() || !req.Qty.IsPositive() {
+			e.mtx.Unlock()
+			return Order{}, ErrInvalidOrder
+		}
+		if o.Side == "BUY" || e.futures {
+			o.locked = req.Price.Mul(req.Qty)
+		} else {
+			o.locked = req.Qty
+		}
+	case "MARKET":
+		if o.Side == "BUY" && req.QuoteQty.IsPositive() {
+			o.locked = req.QuoteQty
+		} else if (o.Side == "BUY" || e.futures) && req.Qty.IsPositive() {
+			// 按当前盘口估算需要冻结的金额
+			o.locked = e.estimateCost(sym.Symbol, o.Side, req.Qty)
+		} else if o.Side == "SELL" {
+			o.locked = req.Qty
+		}
+		if !o.locked.IsPositive() {
+			e.mtx.Unlock()
+			return Order{}, ErrInvalidOrder
+		}
+	default:
+		e.mtx.Unlock()
+		return Order{}, ErrInvalidOrder
+	}
+	lockAsset := sym.Base
+	if o.Side == "BUY" || e.futures {
+		lockAsset = sym.Quote
+	}
+	b := e.balance(lockAsset)
+	if b.Free.LessThan(o.locked) {
+		e.mtx.Unlock()
+		return Order{}, ErrInsufficientFunds
+	}
+	b.Free = b.Free.Sub(o.locked)
+	b.Locked = b.Locked.Add(o.locked)
+	e.orders[o.Id] = o
+	placed := *o // 先推送NEW, 再推送撮合结果
+
+	if o.PostOnly && e.crossed(o) {
+		o.Status = "EXPIRED"
+		e.unlock(o)
+	} else if o.TimeInForce == "FOK" && !e.canFill(o) {
+		o.Status = "EXPIRED"
+		e.unlock(o)
+	} else {
+		e.match(o)
+		if !o.IsFinal() && (o.Type == "MARKET" || o.TimeInForce != "GTC") {
+			o.Status = "EXPIRED"
+			e.unlock(o)
+		}
+	}
+	orders, balances := e.snapshot([]*Order{o})
+	if o.Status != "NEW" {
+		orders = append([]Order{placed}, orders...)
+	}
+	e.mtx.Unlock()
+	e.emit(orders, balances)
+	return orders[len(orders)-1], nil
+}
+
+// orderId为0时使用clientId
+func (e *Engine) CancelOrder(symbol string, orderId int64, clientId string) (Order, error) {
+	e.mtx.Lock()
+	o := e.find(orderId, clientId)
+	if o == nil || (symbol != "" && o.Symbol != symbol) {
+		e.mtx.Unlock()
+		return Order{}, ErrOrderNotFound
+	}
+	if o.IsFinal() {
+		e.mtx.Unlock()
+		return Order{}, ErrOrderNotFound
+	}
+	o.Status = "CANCELED"
+	o.UTime = time.Now().UnixMilli()
+	e.unlock(o)
+	orders, balances := e.snapshot([]*Order{o})
+	e.mtx.Unlock()
+	e.emit(orders, balances)
+	return orders[0], nil
+}
+
+// 原地改单, 只支持限价单, qty为新的订单总数量, 不能小于已成交数量
+// 改单后和盘口重新撮合, orderId为0时使用clientId
+func (e *Engine) AmendOrder(symbol string, orderId int64, clientId string,
+	price, qty decimal.Decimal) (Order, error) {
+	e.mtx.Lock()
+	o := e.find(orderId, clientId)
+	if o == nil || (symbol != "" && o.Symbol != symbol) || o.IsFinal() {
+		e.mtx.Unlock()
+		return Order{}, ErrOrderNotFound
+	}
+	if o.Type != "LIMIT" || !price.IsPositive() || qty.LessThanOrEqual(o.FilledQty) {
+		e.mtx.Unlock()
+		return Order{}, ErrInvalidOrder
+	}
+	sym := e.symbols[o.Symbol]
+	lockAsset := sym.Base
+	locked := qty.Sub(o.FilledQty)
+	if o.Side == "BUY" || e.futures {
+		lockAsset = sym.Quote
+		locked = price.Mul(locked)
+	}
+	b := e.balance(lockAsset)
+	if b.Free.Add(o.locked).LessThan(locked) {
+		e.mtx.Unlock()
+		return Order{}, ErrInsufficientFunds
+	}
+	b.Free = b.Free.Add(o.locked).Sub(locked)
+	b.Locked = b.Locked.Sub(o.locked).Add(locked)
+	o.locked = locked
+	o.Price = price
+	o.Qty = qty
+	o.UTime = time.Now().UnixMilli()
+	if o.PostOnly && e.crossed(o) {
+		o.Status = "EXPIRED"
+		e.unlock(o)
+	} else {
+		e.match(o)
+	}
+	orders, balances := e.snapshot([]*Order{o})
+	e.mtx.Unlock()
+	e.emit(orders, balances)
+	return orders[0], nil
+}
+func (e *Engine) GetOrder(orderId int64, clientId string) (Order, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	o := e.find(orderId, clientId)
+	if o == nil {
+		return Order{}, ErrOrderNotFound
+	}
+	return *o, nil
+}
+
+// symbol 为空返回所有
+func (e *Engine) OpenOrders(symbol string) []Order {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	var l []Order
+	for _, o := range e.sortedOrders() {
+		if !o.IsFinal() && (symbol == "" || o.Symbol == symbol) {
+			l = append(l, *o)
+		}
+	}
+	return l
+}
+
+// 所有订单(含已结束的), symbol 为空返回所有
+func (e *Engine) Orders(symbol string) []Order {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	var l []Order
+	for _, o := range e.sortedOrders() {
+		if symbol == "" || o.Symbol == symbol {
+			l = append(l, *o)
+		}
+	}
+	return l
+}
+
+// 条件单和普通订单共用id序列, 返回填好Id/Status/CTime的条件单
+func (e *Engine) PlaceConditionalOrder(o ConditionalOrder) (ConditionalOrder, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if _, ok := e.symbols[o.Symbol]; !ok {
+		return ConditionalOrder{}, ErrInvalidSymbol
+	}
+	switch o.Type {
+	case "STOP_MARKET", "STOP_LIMIT", "TAKE_PROFIT_MARKET", "TAKE_PROFIT_LIMIT":
+	default:
+		return ConditionalOrder{}, ErrInvalidOrder
+	}
+	if (o.Side != "BUY" && o.Side != "SELL") || !o.TriggerPrice.IsPositive() || !o.Qty.IsPositive() ||
+		(o.IsLimit() && !o.Price.IsPositive()) {
+		return ConditionalOrder{}, ErrInvalidOrder
+	}
+	if o.TriggerBy == "" {
+		o.TriggerBy = "MARKET_PRICE"
+	}
+	e.nextId++
+	o.Id = e.nextId
+	o.Status = "NEW"
+	o.CTime = time.Now().UnixMilli()
+	e.algos[o.Id] = &o
+	return o, nil
+}
+
+// orderId为0时使用clientId
+func (e *Engine) CancelConditionalOrder(symbol string, orderId int64, clientId string) (ConditionalOrder, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for _, o := range e.algos {
+		if ((orderId != 0 && o.Id == orderId) || (orderId == 0 && clientId != "" && o.ClientId == clientId)) &&
+			o.Status == "NEW" && (symbol == "" || o.Symbol == symbol) {
+			o.Status = "CANCELED"
+			return *o, nil
+		}
+	}
+	return ConditionalOrder{}, ErrOrderNotFound
+}
+
+// 等待触发的条件单, symbol 为空返回所有
+func (e *Engine) OpenConditionalOrders(symbol string) []ConditionalOrder {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	var l []ConditionalOrder
+	for _, o := range e.algos {
+		if o.Status == "NEW" && (symbol == "" || o.Symbol == symbol) {
+			l = append(l, *o)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Id < l[j].Id })
+	return l
+}
+
+// 模拟对手方成交, price为0时使用订单价格
+func (e *Engine) Fill(orderId int64, qty, price decimal.Decimal) (Order, error) {
+	e.mtx.Lock()
+	o := e.orders[orderId]
+	if o == nil || o.IsFinal() {
+		e.mtx.Unlock()
+		return Order{}, ErrOrderNotFound
+	}
+	if price.IsZero() {
+		price = o.Price
+	}
+	qty = decimal.Min(qty, o.Qty.Sub(o.FilledQty))
+	if !qty.IsPositive() {
+		e.mtx.Unlock()
+		return Order{}, ErrInvalidOrder
+	}
+	e.fill(o, qty, price)
+	orders, balances := e.snapshot([]*Order{o})
+	e.mtx.Unlock()
+	e.emit(orders, balances)
+	return orders[0], nil
+}
+
+// 合约持仓, 多为正空为负
+func (e *Engine) Position(symbol string) decimal.Decimal {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.positions[symbol]
+}
+
+// 订阅引擎事件, 回调中不要再调用引擎的方法
+func (e *Engine) OnOrder(fn func(Order)) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.onOrder = append(e.onOrder, fn)
+}
+func (e *Engine) OnBalance(fn func(Balance)) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.onBalance = append(e.onBalance, fn)
+}
+func (e *Engine) OnBook(fn func(symbol string)) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.onBook = append(e.onBook, fn)
+}
+func (e *Engine) OnKLine(fn func(symbol, interval string, k KLine)) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.onKLine = append(e.onKLine, fn)
+}
+
+// 以下方法需要持有锁
+func (e *Engine) balance(asset string) *Balance {
+	b := e.balances[asset]
+	if b == nil {
+		b = &Balance{Asset: asset}
+		e.balances[asset] = b
+	}
+	return b
+}
+func (e *Engine) find(orderId int64, clientId string) *Order {
+	if orderId != 0 {
+		return e.orders[orderId]
+	}
+	if clientId == "" {
+		return nil
+	}
+	var found *Order
+	for _, o := range e.orders {
+		if o.ClientId == clientId && (found == nil || o.Id > found.Id) {
+			found = o
+		}
+	}
+	return found
+}
+func (e *Engine) sortedOrders() []*Order {
+	l := make([]*Order, 0, len(e.orders))
+	for _, o := range e.orders {
+		l = append(l, o)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Id < l[j].Id })
+	return l
+}
+func (e *Engine) estimateCost(symbol, side string, qty decimal.Decimal) decimal.Decimal {
+	cost := decimal.Zero
+	left := qty
+	levels := e.asks[symbol]
+	if side == "SELL" {
+		levels = e.bids[symbol]
+	}
+	for _, lv := range levels {
+		q := decimal.Min(left, lv.Qty)
+		cost = cost.Add(q.Mul(lv.Price))
+		left = left.Sub(q)
+		if !left.IsPositive() {
+			break
+		}
+	}
+	return cost
+}
+func (e *Engine) crossed(o *Order) bool {
+	if o.Side == "BUY" {
+		a := e.asks[o.Symbol]
+		return len(a) > 0 && a[0].Price.LessThanOrEqual(o.Price)
+	}
+	b := e.bids[o.Symbol]
+	return len(b) > 0 && b[0].Price.GreaterThanOrEqual(o.Price)
+}
+func (e *Engine) canFill(o *Order) bool {
+	left := o.Qty
+	levels := e.asks[o.Symbol]
+	if o.Side == "SELL" {
+		levels = e.bids[o.Symbol]
+	}
+	for _, lv := range levels {
+		if o.Type == "LIMIT" && ((o.Side == "BUY" && lv.Price.GreaterThan(o.Price)) ||
+			(o.Side == "SELL" && lv.Price.LessThan(o.Price))) {
+			break
+		}
+		left = left.Sub(lv.Qty)
+		if !left.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// 和外部盘口撮合, 有成交返回true
+func (e *Engine) match(o *Order) bool {
+	levels := e.asks[o.Symbol]
+	if o.Side == "SELL" {
+		levels = e.bids[o.Symbol]
+	}
+	matched, dust := false, false
+	for len(levels) > 0 && !o.IsFinal() {
+		lv := &levels[0]
+		if o.Type == "LIMIT" && ((o.Side == "BUY" && lv.Price.GreaterThan(o.Price)) ||
+			(o.Side == "SELL" && lv.Price.LessThan(o.Price))) {
+			break
+		}
+		var qty decimal.Decimal
+		if o.Type == "MARKET" && o.Side == "BUY" && o.QuoteQty.IsPositive() {
+			leftAmt := o.QuoteQty.Sub(o.FilledAmt)
+			qty = decimal.Min(lv.Qty, leftAmt.Div(lv.Price))
+			if sym := e.symbols[o.Symbol]; sym != nil {
+				qty = qty.Div(sym.StepSize).Floor().Mul(sym.StepSize)
+			}
+		} else {
+			qty = decimal.Min(lv.Qty, o.Qty.Sub(o.FilledQty))
+		}
+		if !qty.IsPositive() {
+			dust = matched
+			break
+		}
+		e.fill(o, qty, lv.Price)
+		matched = true
+		lv.Qty = lv.Qty.Sub(qty)
+		if !lv.Qty.IsPositive() {
+			levels = levels[1:]
+		}
+		if o.Type == "MARKET" && o.Side == "BUY" && o.QuoteQty.IsPositive() &&
+			!o.IsFinal() && lv.Qty.IsPositive() {
+			dust = true // 剩余金额不够买一个最小单位
+			break
+		}
+	}
+	if o.Side == "SELL" {
+		e.bids[o.Symbol] = levels
+	} else {
+		e.asks[o.Symbol] = levels
+	}
+	if matched && o.Type == "MARKET" && !o.IsFinal() {
+		o.Status = "EXPIRED" // 盘口深度不够
+		if dust {
+			o.Status = "FILLED"
+		}
+		e.unlock(o)
+	}
+	return matched
+}
+func (e *Engine) fill(o *Order, qty, price decimal.Decimal) {
+	sym := e.symbols[o.Symbol]
+	amt := qty.Mul(price)
+	left := o.Qty.Sub(o.FilledQty)
+	o.FilledQty = o.FilledQty.Add(qty)
+	o.FilledAmt = o.FilledAmt.Add(amt)
+	o.LastQty = qty
+	o.LastPrice = price
+	o.UTime = time.Now().UnixMilli()
+	if e.futures {
+		// 按成交数量释放保证金, 手续费从quote扣
+		fee := amt.Mul(e.feeRate)
+		o.Fee = o.Fee.Add(fee)
+		o.FeeAsset = sym.Quote
+		margin := o.locked
+		if qty.LessThan(left) {
+			margin = o.locked.Mul(qty).Div(left)
+		}
+		quote := e.balance(sym.Quote)
+		quote.Locked = quote.Locked.Sub(margin)
+		quote.Free = quote.Free.Add(margin).Sub(fee)
+		o.locked = o.locked.Sub(margin)
+		if o.Side == "BUY" {
+			e.positions[o.Symbol] = e.positions[o.Symbol].Add(qty)
+		} else {
+			e.positions[o.Symbol] = e.positions[o.Symbol].Sub(qty)
+		}
+	} else if o.Side == "BUY" {
+		fee := qty.Mul(e.feeRate)
+		o.Fee = o.Fee.Add(fee)
+		o.FeeAsset = sym.Base
+		quote := e.balance(sym.Quote)
+		quote.Locked = quote.Locked.Sub(amt)
+		o.locked = o.locked.Sub(amt)
+		base := e.balance(sym.Base)
+		base.Free = base.Free.Add(qty.Sub(fee))
+	} else {
+		fee := amt.Mul(e.feeRate)
+		o.Fee = o.Fee.Add(fee)
+		o.FeeAsset = sym.Quote
+		base := e.balance(sym.Base)
+		base.Locked = base.Locked.Sub(qty)
+		o.locked = o.locked.Sub(qty)
+		quote := e.balance(sym.Quote)
+		quote.Free = quote.Free.Add(amt.Sub(fee))
+	}
+	full := o.FilledQty.GreaterThanOrEqual(o.Qty) && o.Qty.IsPositive()
+	if o.Type == "MARKET" && o.Side == "BUY" && o.QuoteQty.IsPositive() {
+		full = o.FilledAmt.GreaterThanOrEqual(o.QuoteQty)
+	}
+	if full {
+		o.Status = "FILLED"
+		e.unlock(o)
+	} else {
+		o.Status = "PARTIALLY_FILLED"
+	}
+}
+
+// 释放订单剩余的冻结
+func (e *Engine) unlock(o *Order) {
+	if !o.locked.IsPositive() {
+		return
+	}
+	sym := e.symbols[o.Symbol]
+	asset := sym.Base
+	if o.Side == "BUY" || e.futures {
+		asset = sym.Quote
+	}
+	b := e.balance(asset)
+	b.Locked = b.Locked.Sub(o.locked)
+	b.Free = b.Free.Add(o.locked)
+	o.locked = decimal.Zero
+}
+func (e *Engine) snapshot(updated []*Order) ([]Order, []Balance) {
+	orders := make([]Order, 0, len(updated))
+	assets := make(map[string]bool)
+	for _, o := range updated {
+		orders = append(orders, *o)
+		if sym := e.symbols[o.Symbol]; sym != nil {
+			assets[sym.Quote] = true
+			if !e.futures {
+				assets[sym.Base] = true
+			}
+		}
+	}
+	balances := make([]Balance, 0, len(assets))
+	for a := range assets {
+		balances = append(balances, *e.balance(a))
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return orders, balances
+}
+
+// 以下方法不能持有锁
+func (e *Engine) emit(orders []Order, balances []Balance) {
+	e.mtx.Lock()
+	onOrder := e.onOrder
+	e.mtx.Unlock()
+	for _, o := range orders {
+		for _, fn := range onOrder {
+			fn(o)
+		}
+	}
+	for _, b := range balances {
+		e.emitBalance(b)
+	}
+}
+func (e *Engine) emitBalance(b Balance) {
+	e.mtx.Lock()
+	onBalance := e.onBalance
+	e.mtx.Unlock()
+	for _, fn := range onBalance {
+		fn(b)
+	}
+}
+func (e *Engine) emitBook(symbol string) {
+	e.mtx.Lock()
+	onBook := e.onBook
+	e.mtx.Unlock()
+	for _, fn := range onBook {
+		fn(symbol)
+	}
+}
@@ -0,0 +1,942 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *Server) initGate() {
+	s.route("GET", "/api/v4/spot/time", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, 200, map[string]any{"server_time": time.Now().UnixMilli()})
+	})
+	s.route("GET", "/api/v4/spot/currency_pairs", s.gtCurrencyPairs)
+	s.route("GET", "/api/v4/spot/order_book", s.gtOrderBook)
+	s.route("GET", "/api/v4/spot/candlesticks", s.gtCandlesticks)
+	s.route("GET", "/api/v4/spot/accounts", s.gtSigned(s.gtAccounts))
+	s.route("POST", "/api/v4/spot/orders", s.gtSigned(s.gtPlaceOrder))
+	s.route("DELETE", "/api/v4/spot/orders", s.gtSigned(s.gtCancelAllOrders))
+	s.route("DELETE", "/api/v4/spot/orders/", s.gtSigned(s.gtCancelOrder))
+	s.route("GET", "/api/v4/spot/orders/", s.gtSigned(s.gtGetOrder))
+	s.route("PATCH", "/api/v4/spot/orders/", s.gtSigned(s.gtAmendOrder))
+	s.route("GET", "/api/v4/spot/open_orders", s.gtSigned(s.gtOpenOrders))
+	s.route("POST", "/api/v4/spot/price_orders", s.gtSigned(s.gtPlacePriceOrder))
+	s.route("GET", "/api/v4/spot/price_orders", s.gtSigned(s.gtPriceOrders))
+	s.route("DELETE", "/api/v4/spot/price_orders/", s.gtSigned(s.gtCancelPriceOrder))
+	// U本位合约, size为带符号的张数
+	s.route("GET", "/api/v4/futures/usdt/contracts", s.gtContracts)
+	s.route("POST", "/api/v4/futures/usdt/orders", s.gtSigned(s.gtFuturesPlaceOrder))
+	s.route("GET", "/api/v4/futures/usdt/orders", s.gtSigned(s.gtFuturesOpenOrders))
+	s.route("DELETE", "/api/v4/futures/usdt/orders/", s.gtSigned(s.gtFuturesCancelOrder))
+	s.route("GET", "/api/v4/futures/usdt/orders/", s.gtSigned(s.gtFuturesGetOrder))
+	s.route("PUT", "/api/v4/futures/usdt/orders/", s.gtSigned(s.gtFuturesAmendOrder))
+	s.route("POST", "/api/v4/futures/usdt/price_orders", s.gtSigned(s.gtFuturesPlacePriceOrder))
+	s.route("GET", "/api/v4/futures/usdt/price_orders", s.gtSigned(s.gtFuturesPriceOrders))
+	s.route("DELETE", "/api/v4/futures/usdt/price_orders/", s.gtSigned(s.gtFuturesCancelPriceOrder))
+
+	s.wsRoute("/ws/v4/", s.gtWs) // 公共和私有频道同一个地址
+	s.wsRoute("/v4/ws/", s.gtFuturesWs)
+	s.Engine.OnBook(s.gtPushBook)
+	s.Engine.OnKLine(s.gtPushKLine)
+	s.Futures.OnKLine(s.gtPushFuturesKLine)
+	s.Engine.OnOrder(s.gtPushOrder)
+	s.Engine.OnBalance(s.gtPushBalance)
+}
+
+func (s *Server) gtError(w http.ResponseWriter, status int, label, msg string) {
+	writeJSON(w, status, map[string]any{"label": label, "message": msg})
+}
+func (s *Server) gtEngineError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInsufficientFunds:
+		s.gtError(w, 400, "BALANCE_NOT_ENOUGH", "Not enough balance")
+	case ErrOrderNotFound:
+		s.gtError(w, 404, "ORDER_NOT_FOUND", "Order not found")
+	case ErrInvalidSymbol:
+		s.gtError(w, 400, "INVALID_CURRENCY_PAIR", "Invalid currency pair")
+	default:
+		s.gtError(w, 400, "INVALID_PARAM_VALUE", err.Error())
+	}
+}
+
+// 校验 SIGN = hex(hmac_sha512(method\npath\nquery\nhex(sha512(body))\nts))
+func (s *Server) gtSigned(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("KEY") != s.cfg.ApiKey {
+			s.gtError(w, 401, "INVALID_KEY", "Invalid key provided")
+			return
+		}
+		h := sha512.New()
+		h.Write(readBody(r))
+		msg := fmt.Sprintf("%s\n%s\n%s\n%s\n%s", r.Method, r.URL.Path, r.URL.RawQuery,
+			hex.EncodeToString(h.Sum(nil)), r.Header.Get("Timestamp"))
+		if !hmac.Equal([]byte(r.Header.Get("SIGN")), []byte(s.gtHmac(msg))) {
+			s.gtError(w, 401, "INVALID_SIGNATURE", "Signature mismatch")
+			return
+		}
+		fn(w, r)
+	}
+}
+func (s *Server) gtHmac(msg string) string {
+	hm := hmac.New(sha512.New, []byte(s.cfg.SecretKey))
+	hm.Write([]byte(msg))
+	return hex.EncodeToString(hm.Sum(nil))
+}
+func (s *Server) gtSymbol(pair string) string {
+	return strings.ReplaceAll(pair, "_", "")
+}
+
+// from/to 为秒, 包含to
+func (s *Server) gtCandlesticks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, _ := strconv.ParseInt(q.Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(q.Get("to"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if to > 0 {
+		to++
+	}
+	l := s.Engine.KLines(s.gtSymbol(q.Get("currency_pair")), q.Get("interval"), from, to)
+	l = limitKLines(l, limit, false)
+	// [时间(秒), 成交额, 收盘价, 最高价, 最低价, 开盘价, 成交量, 是否结束]
+	ret := make([]any, 0, len(l))
+	for _, k := range l {
+		ret = append(ret, []string{itoa(k.OpenTime), k.QuoteVolume.String(), k.Close.String(),
+			k.High.String(), k.Low.String(), k.Open.String(), k.Volume.String(), strconv.FormatBool(k.Closed)})
+	}
+	writeJSON(w, 200, ret)
+}
+func (s *Server) gtCurrencyPairs(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, sym := range s.Engine.Symbols() {
+		l = append(l, map[string]any{
+			"id":               sym.Base + "_" + sym.Quote,
+			"base":             sym.Base,
+			"quote":            sym.Quote,
+			"trade_status":     "tradable",
+			"min_quote_amount": sym.MinNotional,
+			"min_base_amount":  sym.MinQty,
+			"amount_precision": decimalPlaces(sym.StepSize),
+			"precision":        decimalPlaces(sym.TickSize),
+		})
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) gtOrderBook(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	symbol := s.gtSymbol(q.Get("currency_pair"))
+	if _, ok := s.Engine.Symbol(symbol); !ok {
+		s.gtEngineError(w, ErrInvalidSymbol)
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	bids, asks := s.Engine.Book(symbol, limit)
+	writeJSON(w, 200, map[string]any{"current": time.Now().UnixMilli(),
+		"bids": bnLevels(bids), "asks": bnLevels(asks)})
+}
+func (s *Server) gtAccounts(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, b := range s.Engine.Balances() {
+		l = append(l, map[string]any{"currency": b.Asset, "available": b.Free, "locked": b.Locked})
+	}
+	writeJSON(w, 200, l)
+}
+
+type gtOrderArg struct {
+	CurrencyPair string `json:"currency_pair"`
+	Text         string `json:"text"`
+	Price        string `json:"price"`
+	TimeInForce  string `json:"time_in_force"`
+	Amount       string `json:"amount"`
+	Side         string `json:"side"`
+	Type         string `json:"type"`
+	OrderId      string `json:"order_id"` // ws撤单
+}
+
+func (s *Server) gtPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var arg gtOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	o, err := s.gtPlace(arg)
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	writeJSON(w, 201, s.gtOrder(o))
+}
+func (s *Server) gtPlace(arg gtOrderArg) (Order, error) {
+	req := OrderRequest{
+		Symbol:      s.gtSymbol(arg.CurrencyPair),
+		ClientId:    strings.TrimPrefix(arg.Text, "t-"),
+		Side:        strings.ToUpper(arg.Side),
+		Type:        strings.ToUpper(arg.Type),
+		TimeInForce: strings.ToUpper(arg.TimeInForce),
+	}
+	if req.TimeInForce == "POC" {
+		req.TimeInForce, req.PostOnly = "GTC", true
+	}
+	amount, _ := decimal.NewFromString(arg.Amount)
+	req.Price, _ = decimal.NewFromString(arg.Price)
+	req.Qty = amount
+	if req.Type == "MARKET" && req.Side == "BUY" { // 市价买单amount为quote金额
+		req.Qty, req.QuoteQty = decimal.Zero, amount
+	}
+	return s.Engine.PlaceOrder(req)
+}
+
+// /api/v4/spot/orders/{id}, id 为 t-xxx 时按clientId查找
+func (s *Server) gtOrderFromPath(r *http.Request) (Order, error) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v4/spot/orders/")
+	symbol := s.gtSymbol(r.URL.Query().Get("currency_pair"))
+	var o Order
+	var err error
+	if strings.HasPrefix(id, "t-") {
+		o, err = s.Engine.GetOrder(0, id[2:])
+	} else {
+		oid, _ := strconv.ParseInt(id, 10, 64)
+		o, err = s.Engine.GetOrder(oid, "")
+	}
+	if err == nil && o.Symbol != symbol {
+		err = ErrOrderNotFound
+	}
+	return o, err
+}
+func (s *Server) gtCancelOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := s.gtOrderFromPath(r)
+	if err == nil {
+		o, err = s.Engine.CancelOrder(o.Symbol, o.Id, "")
+	}
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtOrder(o))
+}
+func (s *Server) gtAmendOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		Amount string `json:"amount"`
+		Price  string `json:"price"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	o, err := s.gtOrderFromPath(r)
+	if err == nil {
+		price, _ := decimal.NewFromString(arg.Price)
+		qty, _ := decimal.NewFromString(arg.Amount)
+		o, err = s.Engine.AmendOrder(o.Symbol, o.Id, "", price, qty)
+	}
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtOrder(o))
+}
+func (s *Server) gtCancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	symbol := ""
+	if pair := r.URL.Query().Get("currency_pair"); pair != "" {
+		symbol = s.gtSymbol(pair)
+	}
+	l := []any{}
+	for _, o := range s.Engine.OpenOrders(symbol) {
+		if o, err := s.Engine.CancelOrder(o.Symbol, o.Id, ""); err == nil {
+			l = append(l, s.gtOrder(o))
+		}
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) gtGetOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := s.gtOrderFromPath(r)
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtOrder(o))
+}
+func (s *Server) gtOpenOrders(w http.ResponseWriter, r *http.Request) {
+	bySymbol := make(map[string][]any)
+	var symbols []string
+	for _, o := range s.Engine.OpenOrders("") {
+		pair := s.gtPair(o.Symbol)
+		if _, ok := bySymbol[pair]; !ok {
+			symbols = append(symbols, pair)
+		}
+		bySymbol[pair] = append(bySymbol[pair], s.gtOrder(o))
+	}
+	l := []any{}
+	for _, pair := range symbols {
+		l = append(l, map[string]any{"currency_pair": pair,
+			"total": len(bySymbol[pair]), "orders": bySymbol[pair]})
+	}
+	writeJSON(w, 200, l)
+}
+
+// 条件单, 触发方向(rule)和买卖方向决定止损/止盈, 市价买单的amount原样记为Qty
+func (s *Server) gtPlacePriceOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		Market  string `json:"market"`
+		Trigger struct {
+			Price string `json:"price"`
+			Rule  string `json:"rule"`
+		} `json:"trigger"`
+		Put struct {
+			Type   string `json:"type"`
+			Side   string `json:"side"`
+			Price  string `json:"price"`
+			Amount string `json:"amount"`
+		} `json:"put"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	o := ConditionalOrder{Symbol: s.gtSymbol(arg.Market), Side: strings.ToUpper(arg.Put.Side)}
+	o.TriggerPrice, _ = decimal.NewFromString(arg.Trigger.Price)
+	o.Qty, _ = decimal.NewFromString(arg.Put.Amount)
+	o.Type = "TAKE_PROFIT"
+	if (arg.Trigger.Rule == ">=") == (o.Side == "BUY") {
+		o.Type = "STOP"
+	}
+	if arg.Put.Type == "limit" {
+		o.Type += "_LIMIT"
+		o.Price, _ = decimal.NewFromString(arg.Put.Price)
+	} else {
+		o.Type += "_MARKET"
+	}
+	o, err := s.Engine.PlaceConditionalOrder(o)
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	writeJSON(w, 201, map[string]any{"id": o.Id})
+}
+func (s *Server) gtPriceOrders(w http.ResponseWriter, r *http.Request) {
+	symbol := ""
+	if market := r.URL.Query().Get("market"); market != "" {
+		symbol = s.gtSymbol(market)
+	}
+	l := []any{}
+	for _, o := range s.Engine.OpenConditionalOrders(symbol) {
+		l = append(l, s.gtPriceOrder(o))
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) gtCancelPriceOrder(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v4/spot/price_orders/"), 10, 64)
+	o, err := s.Engine.CancelConditionalOrder("", id, "")
+	if err != nil {
+		s.gtEngineError(w, err)
+		return
+	}
+	v := s.gtPriceOrder(o)
+	v["status"] = "cancelled"
+	writeJSON(w, 200, v)
+}
+func (s *Server) gtPriceOrder(o ConditionalOrder) map[string]any {
+	rule, typ := "<=", "market"
+	if o.TriggerRise() {
+		rule = ">="
+	}
+	price := o.TriggerPrice
+	if o.IsLimit() {
+		typ, price = "limit", o.Price
+	}
+	return map[string]any{
+		"id":      o.Id,
+		"market":  s.gtPair(o.Symbol),
+		"trigger": map[string]any{"price": o.TriggerPrice, "rule": rule},
+		"put": map[string]any{"type": typ, "side": strings.ToLower(o.Side),
+			"price": price, "amount": o.Qty, "account": "normal"},
+		"status": "open",
+		"ctime":  o.CTime / 1000,
+	}
+}
+func (s *Server) gtPair(symbol string) string {
+	if sym, ok := s.Engine.Symbol(symbol); ok {
+		return sym.Base + "_" + sym.Quote
+	}
+	return symbol
+}
+func (s *Server) gtOrder(o Order) map[string]any {
+	status, finishAs := "open", "open"
+	switch o.Status {
+	case "FILLED":
+		status, finishAs = "closed", "filled"
+	case "CANCELED":
+		status, finishAs = "cancelled", "cancelled"
+	case "EXPIRED", "REJECTED":
+		status, finishAs = "cancelled", "ioc"
+		if o.PostOnly {
+			finishAs = "poc"
+		}
+	}
+	amount, left := o.Qty, o.Qty.Sub(o.FilledQty)
+	if o.Type == "MARKET" && o.QuoteQty.IsPositive() {
+		amount, left = o.QuoteQty, o.QuoteQty.Sub(o.FilledAmt)
+	}
+	timeInForce := strings.ToLower(o.TimeInForce)
+	if o.PostOnly {
+		timeInForce = "poc"
+	}
+	text := ""
+	if o.ClientId != "" {
+		text = "t-" + o.ClientId
+	}
+	return map[string]any{
+		"currency_pair":  s.gtPair(o.Symbol),
+		"id":             itoa(o.Id),
+		"text":           text,
+		"price":          o.Price,
+		"amount":         amount,
+		"filled_amount":  o.FilledQty,
+		"filled_total":   o.FilledAmt,
+		"left":           left,
+		"status":         status,
+		"finish_as":      finishAs,
+		"type":           strings.ToLower(o.Type),
+		"time_in_force":  timeInForce,
+		"side":           strings.ToLower(o.Side),
+		"fee_currency":   o.FeeAsset,
+		"fee":            o.Fee,
+		"gt_fee":         "0",
+		"create_time_ms": o.CTime,
+		"update_time_ms": o.UTime,
+	}
+}
+
+func (s *Server) gtContracts(w http.ResponseWriter, r *http.Request) {
+	l := []any{}
+	for _, sym := range s.Futures.Symbols() {
+		l = append(l, map[string]any{
+			"name":              sym.Base + "_" + sym.Quote,
+			"type":              "direct",
+			"quanto_multiplier": sym.ContractSize,
+			"order_price_round": sym.TickSize,
+			"order_size_min":    sym.MinQty.Div(sym.ContractSize).Ceil().IntPart(),
+			"order_size_max":    1000000,
+			"in_delisting":      false,
+		})
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) gtFuturesEngineError(w http.ResponseWriter, err error) {
+	if err == ErrInsufficientFunds {
+		s.gtError(w, 400, "INSUFFICIENT_AVAILABLE", "Insufficient available balance")
+		return
+	}
+	s.gtEngineError(w, err)
+}
+func (s *Server) gtFuturesPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		Contract   string `json:"contract"`
+		Size       int64  `json:"size"`
+		Text       string `json:"text"`
+		Price      string `json:"price"`
+		Tif        string `json:"tif"`
+		ReduceOnly bool   `json:"reduce_only"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil || arg.Size == 0 {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	sym, ok := s.Futures.Symbol(s.gtSymbol(arg.Contract))
+	if !ok {
+		s.gtError(w, 400, "CONTRACT_NOT_FOUND", "Contract not found")
+		return
+	}
+	req := OrderRequest{
+		Symbol:      sym.Symbol,
+		ClientId:    strings.TrimPrefix(arg.Text, "t-"),
+		Side:        "BUY",
+		Type:        "LIMIT",
+		TimeInForce: strings.ToUpper(arg.Tif),
+		Qty:         decimal.NewFromInt(arg.Size).Abs().Mul(sym.ContractSize),
+	}
+	if arg.Size < 0 {
+		req.Side = "SELL"
+	}
+	if req.TimeInForce == "" {
+		req.TimeInForce = "GTC"
+	} else if req.TimeInForce == "POC" {
+		req.TimeInForce, req.PostOnly = "GTC", true
+	}
+	req.Price, _ = decimal.NewFromString(arg.Price)
+	if req.Price.IsZero() { // price为0是市价单
+		req.Type, req.TimeInForce = "MARKET", ""
+	}
+	o, err := s.Futures.PlaceOrder(req)
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	writeJSON(w, 201, s.gtFuturesOrder(o))
+}
+
+// /api/v4/futures/usdt/orders/{id}, id 为 t-xxx 时按clientId查找
+func (s *Server) gtFuturesOrderFromPath(r *http.Request) (Order, error) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v4/futures/usdt/orders/")
+	if strings.HasPrefix(id, "t-") {
+		return s.Futures.GetOrder(0, id[2:])
+	}
+	oid, _ := strconv.ParseInt(id, 10, 64)
+	return s.Futures.GetOrder(oid, "")
+}
+func (s *Server) gtFuturesCancelOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := s.gtFuturesOrderFromPath(r)
+	if err == nil {
+		o, err = s.Futures.CancelOrder(o.Symbol, o.Id, "")
+	}
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtFuturesOrder(o))
+}
+
+// size为带符号的张数, 方向不能修改
+func (s *Server) gtFuturesAmendOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		Size  int64  `json:"size"`
+		Price string `json:"price"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	o, err := s.gtFuturesOrderFromPath(r)
+	if err == nil {
+		sym, _ := s.Futures.Symbol(o.Symbol)
+		price, _ := decimal.NewFromString(arg.Price)
+		qty := decimal.NewFromInt(arg.Size).Abs().Mul(sym.ContractSize)
+		o, err = s.Futures.AmendOrder(o.Symbol, o.Id, "", price, qty)
+	}
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtFuturesOrder(o))
+}
+func (s *Server) gtFuturesGetOrder(w http.ResponseWriter, r *http.Request) {
+	o, err := s.gtFuturesOrderFromPath(r)
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	writeJSON(w, 200, s.gtFuturesOrder(o))
+}
+func (s *Server) gtFuturesOpenOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("status") != "open" { // 历史订单不支持
+		s.gtError(w, 400, "INVALID_PARAM_VALUE", "status not supported by cextest")
+		return
+	}
+	l := []any{}
+	for _, o := range s.Futures.OpenOrders(s.gtSymbol(q.Get("contract"))) {
+		l = append(l, s.gtFuturesOrder(o))
+	}
+	writeJSON(w, 200, l)
+}
+
+var gtPriceTypes = []string{"MARKET_PRICE", "MARK_PRICE", "INDEX_PRICE"}
+
+// 合约条件单, size为带符号的张数, rule 1:>= 2:<=, price为0表示市价
+func (s *Server) gtFuturesPlacePriceOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		Initial struct {
+			Contract string `json:"contract"`
+			Size     int64  `json:"size"`
+			Price    string `json:"price"`
+		} `json:"initial"`
+		Trigger struct {
+			PriceType int    `json:"price_type"`
+			Price     string `json:"price"`
+			Rule      int    `json:"rule"`
+		} `json:"trigger"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil || arg.Initial.Size == 0 ||
+		arg.Trigger.PriceType < 0 || arg.Trigger.PriceType >= len(gtPriceTypes) {
+		s.gtError(w, 400, "INVALID_REQUEST_BODY", "Invalid request body")
+		return
+	}
+	sym, ok := s.Futures.Symbol(s.gtSymbol(arg.Initial.Contract))
+	if !ok {
+		s.gtError(w, 400, "CONTRACT_NOT_FOUND", "Contract not found")
+		return
+	}
+	o := ConditionalOrder{Symbol: sym.Symbol, Side: "BUY", TriggerBy: gtPriceTypes[arg.Trigger.PriceType],
+		Qty: decimal.NewFromInt(arg.Initial.Size).Abs().Mul(sym.ContractSize)}
+	if arg.Initial.Size < 0 {
+		o.Side = "SELL"
+	}
+	o.TriggerPrice, _ = decimal.NewFromString(arg.Trigger.Price)
+	o.Price, _ = decimal.NewFromString(arg.Initial.Price)
+	o.Type = "TAKE_PROFIT"
+	if (arg.Trigger.Rule == 1) == (o.Side == "BUY") {
+		o.Type = "STOP"
+	}
+	if o.Price.IsZero() {
+		o.Type += "_MARKET"
+	} else {
+		o.Type += "_LIMIT"
+	}
+	o, err := s.Futures.PlaceConditionalOrder(o)
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	writeJSON(w, 201, map[string]any{"id": o.Id})
+}
+func (s *Server) gtFuturesPriceOrders(w http.ResponseWriter, r *http.Request) {
+	symbol := ""
+	if contract := r.URL.Query().Get("contract"); contract != "" {
+		symbol = s.gtSymbol(contract)
+	}
+	l := []any{}
+	for _, o := range s.Futures.OpenConditionalOrders(symbol) {
+		l = append(l, s.gtFuturesPriceOrder(o))
+	}
+	writeJSON(w, 200, l)
+}
+func (s *Server) gtFuturesCancelPriceOrder(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v4/futures/usdt/price_orders/"), 10, 64)
+	o, err := s.Futures.CancelConditionalOrder("", id, "")
+	if err != nil {
+		s.gtFuturesEngineError(w, err)
+		return
+	}
+	v := s.gtFuturesPriceOrder(o)
+	v["status"] = "finished"
+	writeJSON(w, 200, v)
+}
+func (s *Server) gtFuturesPriceOrder(o ConditionalOrder) map[string]any {
+	sym, _ := s.Futures.Symbol(o.Symbol)
+	size := o.Qty.Div(sym.ContractSize).IntPart()
+	if o.Side == "SELL" {
+		size = -size
+	}
+	rule, priceType := 2, 0
+	if o.TriggerRise() {
+		rule = 1
+	}
+	for i, v := range gtPriceTypes {
+		if v == o.TriggerBy {
+			priceType = i
+		}
+	}
+	return map[string]any{
+		"id": o.Id,
+		"initial": map[string]any{"contract": sym.Base + "_" + sym.Quote, "size": size,
+			"price": o.Price, "tif": "gtc"},
+		"trigger":     map[string]any{"strategy_type": 0, "price_type": priceType, "price": o.TriggerPrice, "rule": rule},
+		"status":      "open",
+		"create_time": decimal.NewFromInt(o.CTime).Shift(-3),
+	}
+}
+func (s *Server) gtFuturesOrder(o Order) map[string]any {
+	status, finishAs := "open", ""
+	switch o.Status {
+	case "FILLED":
+		status, finishAs = "finished", "filled"
+	case "CANCELED":
+		status, finishAs = "finished", "cancelled"
+	case "EXPIRED", "REJECTED":
+		status, finishAs = "finished", "ioc"
+		if o.PostOnly {
+			finishAs = "poc"
+		}
+	}
+	contract, ctVal := o.Symbol, decimal.NewFromInt(1)
+	if sym, ok := s.Futures.Symbol(o.Symbol); ok {
+		contract, ctVal = sym.Base+"_"+sym.Quote, sym.ContractSize
+	}
+	size := o.Qty.Div(ctVal).IntPart()
+	left := o.Qty.Sub(o.FilledQty).Div(ctVal).IntPart()
+	if o.Side == "SELL" {
+		size, left = -size, -left
+	}
+	tif := strings.ToLower(o.TimeInForce)
+	if o.PostOnly {
+		tif = "poc"
+	} else if o.Type == "MARKET" {
+		tif = "ioc"
+	}
+	text := ""
+	if o.ClientId != "" {
+		text = "t-" + o.ClientId
+	}
+	v := map[string]any{
+		"id":          o.Id,
+		"contract":    contract,
+		"text":        text,
+		"size":        size,
+		"left":        left,
+		"price":       o.Price,
+		"fill_price":  o.AvgPrice(),
+		"status":      status,
+		"finish_as":   finishAs,
+		"tif":         tif,
+		"create_time": decimal.New(o.CTime, -3),
+	}
+	if status == "finished" {
+		v["finish_time"] = decimal.New(o.UTime, -3)
+	}
+	return v
+}
+
+// ws
+func (s *Server) gtWs(c *wsConn, msg []byte) {
+	req := struct {
+		Time    int64           `json:"time"`
+		Channel string          `json:"channel"`
+		Event   string          `json:"event"`
+		Payload json.RawMessage `json:"payload"`
+		Auth    *struct {
+			Key  string `json:"KEY"`
+			Sign string `json:"SIGN"`
+		} `json:"auth"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	if req.Channel == "spot.ping" {
+		resp, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "spot.pong"})
+		c.write(resp)
+		return
+	}
+	if req.Event == "api" {
+		s.gtWsApi(c, req.Channel, req.Payload)
+		return
+	}
+	var payload, pairs []string
+	json.Unmarshal(req.Payload, &payload)
+	result := map[string]any{"status": "success"}
+	switch req.Channel {
+	case "spot.order_book", "spot.book_ticker":
+		pairs = payload
+		if req.Channel == "spot.order_book" && len(payload) > 0 { // [pair, level, interval]
+			pairs = payload[:1]
+		}
+		for _, v := range pairs {
+			c.subscribe(req.Channel+":"+v, req.Event == "subscribe")
+		}
+	case "spot.candlesticks": // [interval, pair]
+		if len(payload) == 2 {
+			c.subscribe(req.Channel+":"+payload[0]+"_"+payload[1], req.Event == "subscribe")
+		}
+	case "spot.orders", "spot.balances":
+		// SIGN = hex(hmac_sha512(channel=%s&event=%s&time=%d))
+		if req.Auth == nil || req.Auth.Key != s.cfg.ApiKey ||
+			!hmac.Equal([]byte(req.Auth.Sign), []byte(s.gtHmac(fmt.Sprintf("channel=%s&event=%s&time=%d",
+				req.Channel, req.Event, req.Time)))) {
+			resp, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": req.Channel,
+				"event": req.Event, "error": map[string]any{"code": 2, "message": "Invalid key provided"}})
+			c.write(resp)
+			return
+		}
+		c.subscribe(req.Channel, req.Event == "subscribe")
+	default:
+		result = map[string]any{"status": "fail"}
+	}
+	resp, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": req.Channel,
+		"event": req.Event, "result": result})
+	c.write(resp)
+	if req.Event == "subscribe" { // 订阅后推送一次当前盘口
+		for _, v := range pairs {
+			s.gtPushBookTo(c, s.gtSymbol(v))
+		}
+	}
+}
+
+// spot.login / spot.order_place / spot.order_cancel
+func (s *Server) gtWsApi(c *wsConn, channel string, payload json.RawMessage) {
+	req := struct {
+		ApiKey    string     `json:"api_key"`
+		Signature string     `json:"signature"`
+		Timestamp string     `json:"timestamp"`
+		ReqId     string     `json:"req_id"`
+		ReqParam  gtOrderArg `json:"req_param"`
+	}{}
+	json.Unmarshal(payload, &req)
+	status, label, message := "200", "", ""
+	var result any = map[string]any{}
+	switch channel {
+	case "spot.login":
+		// signature = hex(hmac_sha512(api\nspot.login\n\nts))
+		if req.ApiKey != s.cfg.ApiKey || !hmac.Equal([]byte(req.Signature),
+			[]byte(s.gtHmac("api\nspot.login\n\n"+req.Timestamp))) {
+			status, label, message = "401", "INVALID_SIGNATURE", "Signature mismatch"
+		} else {
+			c.setAuthed()
+		}
+	case "spot.order_place", "spot.order_cancel":
+		if !c.isAuthed() {
+			status, label, message = "401", "INVALID_KEY", "Please login first"
+			break
+		}
+		var o Order
+		var err error
+		if channel == "spot.order_place" {
+			o, err = s.gtPlace(req.ReqParam)
+		} else if id := req.ReqParam.OrderId; strings.HasPrefix(id, "t-") {
+			o, err = s.Engine.CancelOrder(s.gtSymbol(req.ReqParam.CurrencyPair), 0, id[2:])
+		} else {
+			oid, _ := strconv.ParseInt(id, 10, 64)
+			o, err = s.Engine.CancelOrder(s.gtSymbol(req.ReqParam.CurrencyPair), oid, "")
+		}
+		if err != nil {
+			status, label, message = "400", "INVALID_PARAM_VALUE", err.Error()
+			switch err {
+			case ErrInsufficientFunds:
+				label, message = "BALANCE_NOT_ENOUGH", "Not enough balance"
+			case ErrOrderNotFound:
+				label, message = "ORDER_NOT_FOUND", "Order not found"
+			}
+			break
+		}
+		result = s.gtOrder(o)
+	default:
+		status, label, message = "400", "INVALID_PARAM_VALUE", "channel not supported by cextest"
+	}
+	data := map[string]any{"result": result}
+	if label != "" {
+		data = map[string]any{"errs": map[string]any{"label": label, "message": message}}
+	}
+	resp, _ := json.Marshal(map[string]any{"request_id": req.ReqId, "ack": false,
+		"header": map[string]any{"response_time": itoa(time.Now().UnixMilli()),
+			"status": status, "channel": channel},
+		"data": data})
+	c.write(resp)
+}
+func (s *Server) gtPushKLine(symbol, interval string, k KLine) {
+	name := interval + "_" + s.gtPair(symbol)
+	frame, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "spot.candlesticks",
+		"event": "update", "result": map[string]any{"t": itoa(k.OpenTime), "v": k.QuoteVolume.String(),
+			"c": k.Close.String(), "h": k.High.String(), "l": k.Low.String(), "o": k.Open.String(),
+			"n": name, "a": k.Volume.String(), "w": k.Closed}})
+	for _, c := range s.wsConns("/ws/v4/") {
+		if c.subscribed("spot.candlesticks:" + name) {
+			c.write(frame)
+		}
+	}
+}
+
+// 合约ws只模拟futures.candlesticks
+func (s *Server) gtFuturesWs(c *wsConn, msg []byte) {
+	req := struct {
+		Channel string   `json:"channel"`
+		Event   string   `json:"event"`
+		Payload []string `json:"payload"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	if req.Channel == "futures.ping" {
+		resp, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "futures.pong"})
+		c.write(resp)
+		return
+	}
+	result := map[string]any{"status": "success"}
+	if req.Channel == "futures.candlesticks" && len(req.Payload) == 2 { // [interval, contract]
+		c.subscribe(req.Channel+":"+req.Payload[0]+"_"+req.Payload[1], req.Event == "subscribe")
+	} else {
+		result = map[string]any{"status": "fail"}
+	}
+	resp, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": req.Channel,
+		"event": req.Event, "result": result})
+	c.write(resp)
+}
+
+// v为张数
+func (s *Server) gtPushFuturesKLine(symbol, interval string, k KLine) {
+	sym, ok := s.Futures.Symbol(symbol)
+	if !ok {
+		return
+	}
+	name := interval + "_" + sym.Base + "_" + sym.Quote
+	size := k.Volume
+	if sym.ContractSize.IsPositive() {
+		size = k.Volume.Div(sym.ContractSize)
+	}
+	frame, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "futures.candlesticks",
+		"event": "update", "result": []any{map[string]any{"t": k.OpenTime, "v": size.IntPart(),
+			"c": k.Close.String(), "h": k.High.String(), "l": k.Low.String(), "o": k.Open.String(),
+			"n": name, "a": k.Volume.String(), "w": k.Closed}}})
+	for _, c := range s.wsConns("/v4/ws/") {
+		if c.subscribed("futures.candlesticks:" + name) {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) gtPushBook(symbol string) {
+	for _, c := range s.wsConns("/ws/v4/") {
+		s.gtPushBookTo(c, symbol)
+	}
+}
+func (s *Server) gtPushBookTo(c *wsConn, symbol string) {
+	if _, ok := s.Engine.Symbol(symbol); !ok {
+		return
+	}
+	pair := s.gtPair(symbol)
+	now := time.Now().UnixMilli()
+	bids, asks := s.Engine.Book(symbol, 5)
+	if c.subscribed("spot.order_book:"+pair) && len(bids) > 0 && len(bids) == len(asks) {
+		frame, _ := json.Marshal(map[string]any{"time": now / 1000, "channel": "spot.order_book",
+			"event": "update", "result": map[string]any{"t": now, "s": pair,
+				"bids": bnLevels(bids), "asks": bnLevels(asks)}})
+		c.write(frame)
+	}
+	if c.subscribed("spot.book_ticker:"+pair) && len(bids) > 0 && len(asks) > 0 {
+		frame, _ := json.Marshal(map[string]any{"time": now / 1000, "channel": "spot.book_ticker",
+			"event": "update", "result": map[string]any{"t": now, "s": pair,
+				"b": bids[0].Price, "B": bids[0].Qty, "a": asks[0].Price, "A": asks[0].Qty}})
+		c.write(frame)
+	}
+}
+func (s *Server) gtPushOrder(o Order) {
+	v := s.gtOrder(o)
+	// 推送中的毫秒时间为字符串, rest中为数字
+	v["create_time_ms"], v["update_time_ms"] = itoa(o.CTime), itoa(o.UTime)
+	v["event"] = "update"
+	if o.Status == "NEW" && o.FilledQty.IsZero() {
+		v["event"] = "put"
+	} else if v["status"] != "open" {
+		v["event"] = "finish"
+	}
+	frame, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "spot.orders",
+		"event": "update", "result": []any{v}})
+	for _, c := range s.wsConns("/ws/v4/") {
+		if c.subscribed("spot.orders") {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) gtPushBalance(b Balance) {
+	frame, _ := json.Marshal(map[string]any{"time": time.Now().Unix(), "channel": "spot.balances",
+		"event": "update", "result": []any{map[string]any{"timestamp_ms": itoa(time.Now().UnixMilli()),
+			"currency": b.Asset, "total": b.Free.Add(b.Locked), "available": b.Free, "freeze": b.Locked}}})
+	for _, c := range s.wsConns("/ws/v4/") {
+		if c.subscribed("spot.balances") {
+			c.write(frame)
+		}
+	}
+}
+
+// 0.001 -> 3
+func decimalPlaces(d decimal.Decimal) int32 {
+	var n int32
+	for !d.IsInteger() && n < 18 {
+		d = d.Shift(1)
+		n++
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+package cextest
+
+import (
+	"testing"
+
+	"github.com/shaovie/cex"
+	"github.com/shopspring/decimal"
+)
+
+func testKLine(openTime int64, closePrice int64, closed bool) KLine {
+	return KLine{OpenTime: openTime, Open: decimal.NewFromInt(100), High: decimal.NewFromInt(110),
+		Low: decimal.NewFromInt(90), Close: decimal.NewFromInt(closePrice),
+		Volume: decimal.NewFromInt(2), QuoteVolume: decimal.NewFromInt(200), Closed: closed}
+}
+func TestSpotKLine(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			for i := int64(0); i < 5; i++ {
+				srv.Engine.SetKLine("BTCUSDT", "1m", testKLine(1700000000+i*60, 100+i, true))
+			}
+			l, err := ex.SpotGetKLine("BTCUSDT", "1m", 0, 0, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 3 || l[0].OpenTime != 1700000120 || l[2].OpenTime != 1700000240 {
+				t.Fatalf("want latest 3 klines got %+v", l)
+			}
+			if k := l[2]; !k.OpenPrice.Equal(decimal.NewFromInt(100)) || !k.HighPrice.Equal(decimal.NewFromInt(110)) ||
+				!k.LowPrice.Equal(decimal.NewFromInt(90)) || !k.ClosePrice.Equal(decimal.NewFromInt(104)) ||
+				!k.Volume.Equal(decimal.NewFromInt(2)) || !k.QuoteVolume.Equal(decimal.NewFromInt(200)) {
+				t.Fatalf("unexpected kline %+v", k)
+			}
+			// [start, end)
+			if l, err = ex.SpotGetKLine("BTCUSDT", "1m", 1700000060, 1700000180, 10); err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 2 || l[0].OpenTime != 1700000060 || l[1].OpenTime != 1700000120 {
+				t.Fatalf("want 2 klines in range got %+v", l)
+			}
+		})
+	}
+}
+func TestSpotWsKLine(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			if err := ex.SpotWsPublicOpen(); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan any, 64)
+			go ex.SpotWsPublicLoop(ch)
+			t.Cleanup(func() {
+				ex.SpotWsPublicClose()
+				for range ch {
+				}
+			})
+			ex.SpotWsPublicSubscribe([]string{"kline@BTCUSDT:1m"})
+			waitWsConn(t, srv, func(c *wsConn) bool {
+				return c.subscribed("btcusdt@kline_1m") || c.subscribed("candle1m:BTC-USDT") ||
+					c.subscribed("spot.candlesticks:1m_BTC_USDT")
+			})
+			srv.Engine.SetKLine("BTCUSDT", "1m", testKLine(1700000000, 105, false))
+			waitWsMsg(t, ch, "kline", func(v any) bool {
+				k, ok := v.(*cex.KLine)
+				return ok && k.Symbol == "BTCUSDT" && k.Interval == "1m" && k.OpenTime == 1700000000 &&
+					k.ClosePrice.Equal(decimal.NewFromInt(105)) && k.Volume.Equal(decimal.NewFromInt(2)) && !k.IsClosed
+			})
+			srv.Engine.SetKLine("BTCUSDT", "1m", testKLine(1700000000, 106, true))
+			waitWsMsg(t, ch, "kline closed", func(v any) bool {
+				k, ok := v.(*cex.KLine)
+				return ok && k.OpenTime == 1700000000 && k.ClosePrice.Equal(decimal.NewFromInt(106)) && k.IsClosed
+			})
+		})
+	}
+}
+
+// 合约k线的Volume为标的数量, 张数按面值0.1换算
+func TestFuturesWsKLine(t *testing.T) {
+	for _, name := range []string{"binance", "okx", "gate"} {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			if err := ex.FuturesWsPublicOpen("UM"); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan any, 64)
+			go ex.FuturesWsPublicLoop(ch)
+			t.Cleanup(func() {
+				ex.FuturesWsPublicClose()
+				for range ch {
+				}
+			})
+			ex.FuturesWsPublicSubscribe([]string{"kline@BTCUSDT:1h"})
+			waitWsConn(t, srv, func(c *wsConn) bool {
+				return c.subscribed("btcusdt@kline_1h") || c.subscribed("candle1H:BTC-USDT-SWAP") ||
+					c.subscribed("futures.candlesticks:1h_BTC_USDT")
+			})
+			srv.Futures.SetKLine("BTCUSDT", "1h", testKLine(1700002800, 105, true))
+			waitWsMsg(t, ch, "kline", func(v any) bool {
+				k, ok := v.(*cex.KLine)
+				return ok && k.Symbol == "BTCUSDT" && k.Interval == "1h" && k.OpenTime == 1700002800 &&
+					k.ClosePrice.Equal(decimal.NewFromInt(105)) && k.Volume.Equal(decimal.NewFromInt(2)) && k.IsClosed
+			})
+		})
+	}
+}
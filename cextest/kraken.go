@@ -0,0 +1,449 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	kkTxidPrefix = "OMOCK-"
+	kkWsToken    = "mockwstoken"
+)
+
+func (s *Server) initKraken() {
+	s.route("GET", "/0/public/Time", func(w http.ResponseWriter, r *http.Request) {
+		s.kkResult(w, map[string]any{"unixtime": time.Now().Unix()})
+	})
+	s.route("GET", "/0/public/AssetPairs", s.kkAssetPairs)
+	s.route("GET", "/0/public/Depth", s.kkDepth)
+	s.route("POST", "/0/private/Balance", s.kkSigned(s.kkBalance))
+	s.route("POST", "/0/private/AddOrder", s.kkSigned(s.kkAddOrder))
+	s.route("POST", "/0/private/CancelOrder", s.kkSigned(s.kkCancelOrder))
+	s.route("POST", "/0/private/CancelAll", s.kkSigned(s.kkCancelAll))
+	s.route("POST", "/0/private/EditOrder", s.kkSigned(s.kkEditOrder))
+	s.route("POST", "/0/private/QueryOrders", s.kkSigned(s.kkQueryOrders))
+	s.route("POST", "/0/private/OpenOrders", s.kkSigned(s.kkOpenOrders))
+	s.route("POST", "/0/private/GetWebSocketsToken", s.kkSigned(func(w http.ResponseWriter, values url.Values) {
+		s.kkResult(w, map[string]any{"token": kkWsToken, "expires": 900})
+	}))
+
+	// ws.kraken.com/v2 和 ws-auth.kraken.com/v2 映射到同一个地址
+	s.wsRoute("/v2", s.kkWs)
+	s.Engine.OnBook(s.kkPushBook)
+	s.Engine.OnOrder(s.kkPushOrder)
+	s.Engine.OnBalance(s.kkPushBalance)
+}
+
+func (s *Server) kkResult(w http.ResponseWriter, result any) {
+	writeJSON(w, 200, map[string]any{"error": []string{}, "result": result})
+}
+func (s *Server) kkError(w http.ResponseWriter, msg string) {
+	writeJSON(w, 200, map[string]any{"error": []string{msg}})
+}
+func (s *Server) kkEngineError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInsufficientFunds:
+		s.kkError(w, "EOrder:Insufficient funds")
+	case ErrOrderNotFound:
+		s.kkError(w, "EOrder:Unknown order")
+	case ErrInvalidSymbol:
+		s.kkError(w, "EQuery:Unknown asset pair")
+	default:
+		s.kkError(w, "EGeneral:Invalid arguments")
+	}
+}
+
+// 校验 API-Sign = base64(hmac_sha512(base64decode(secret), path + sha256(nonce + postdata)))
+func (s *Server) kkSigned(fn func(http.ResponseWriter, url.Values)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("API-Key") != s.cfg.ApiKey {
+			s.kkError(w, "EAPI:Invalid key")
+			return
+		}
+		body := readBody(r)
+		values, err := url.ParseQuery(string(body))
+		if err != nil || values.Get("nonce") == "" {
+			s.kkError(w, "EAPI:Invalid nonce")
+			return
+		}
+		secret, _ := base64.StdEncoding.DecodeString(s.cfg.SecretKey)
+		sha := sha256.New()
+		sha.Write([]byte(values.Get("nonce") + string(body)))
+		h := hmac.New(sha512.New, secret)
+		h.Write(append([]byte(r.URL.Path), sha.Sum(nil)...))
+		if !hmac.Equal([]byte(r.Header.Get("API-Sign")), []byte(base64.StdEncoding.EncodeToString(h.Sum(nil)))) {
+			s.kkError(w, "EAPI:Invalid signature")
+			return
+		}
+		fn(w, values)
+	}
+}
+func (s *Server) kkAssetPairs(w http.ResponseWriter, r *http.Request) {
+	result := map[string]any{}
+	if r.URL.Query().Get("aclass_base") != "" { // 没有模拟 xstocks
+		s.kkResult(w, result)
+		return
+	}
+	for _, sym := range s.Engine.Symbols() {
+		result[sym.Symbol] = map[string]any{
+			"altname":      sym.Symbol,
+			"wsname":       sym.Base + "/" + sym.Quote,
+			"base":         sym.Base,
+			"quote":        sym.Quote,
+			"status":       "online",
+			"tick_size":    sym.TickSize,
+			"lot_decimals": decimalPlaces(sym.StepSize),
+			"ordermin":     sym.MinQty,
+			"costmin":      sym.MinNotional,
+		}
+	}
+	s.kkResult(w, result)
+}
+func (s *Server) kkDepth(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pair := q.Get("pair")
+	if _, ok := s.Engine.Symbol(pair); !ok {
+		s.kkEngineError(w, ErrInvalidSymbol)
+		return
+	}
+	count, _ := strconv.Atoi(q.Get("count"))
+	bids, asks := s.Engine.Book(pair, count)
+	ts := time.Now().Unix()
+	levels := func(l []Level) [][]any {
+		v := make([][]any, 0, len(l))
+		for _, lv := range l {
+			v = append(v, []any{lv.Price.String(), lv.Qty.String(), ts})
+		}
+		return v
+	}
+	s.kkResult(w, map[string]any{pair: map[string]any{"bids": levels(bids), "asks": levels(asks)}})
+}
+
+// kraken 的余额为总额(含冻结)
+func (s *Server) kkBalance(w http.ResponseWriter, values url.Values) {
+	result := map[string]string{}
+	for _, b := range s.Engine.Balances() {
+		result[b.Asset] = b.Free.Add(b.Locked).String()
+	}
+	s.kkResult(w, result)
+}
+func (s *Server) kkAddOrder(w http.ResponseWriter, values url.Values) {
+	req := OrderRequest{
+		Symbol:      values.Get("pair"),
+		ClientId:    values.Get("cl_ord_id"),
+		Side:        strings.ToUpper(values.Get("type")),
+		Type:        strings.ToUpper(values.Get("ordertype")),
+		TimeInForce: values.Get("timeinforce"),
+		PostOnly:    strings.Contains(values.Get("oflags"), "post"),
+	}
+	req.Price, _ = decimal.NewFromString(values.Get("price"))
+	req.Qty, _ = decimal.NewFromString(values.Get("volume"))
+	if req.Type == "MARKET" {
+		req.Price = decimal.Zero
+	}
+	o, err := s.Engine.PlaceOrder(req)
+	if err != nil {
+		s.kkEngineError(w, err)
+		return
+	}
+	s.kkResult(w, map[string]any{
+		"descr": map[string]any{"order": strings.ToLower(o.Side) + " " + o.Qty.String() + " " + o.Symbol},
+		"txid":  []string{kkTxidPrefix + itoa(o.Id)},
+	})
+}
+func (s *Server) kkOrderId(txid string) int64 {
+	id, _ := strconv.ParseInt(strings.TrimPrefix(txid, kkTxidPrefix), 10, 64)
+	return id
+}
+func (s *Server) kkCancelOrder(w http.ResponseWriter, values url.Values) {
+	_, err := s.Engine.CancelOrder("", s.kkOrderId(values.Get("txid")), values.Get("cl_ord_id"))
+	if err != nil {
+		s.kkEngineError(w, err)
+		return
+	}
+	s.kkResult(w, map[string]any{"count": 1})
+}
+
+// EditOrder 撤销原订单后重新下单, 返回新的txid
+func (s *Server) kkEditOrder(w http.ResponseWriter, values url.Values) {
+	old, err := s.Engine.CancelOrder(values.Get("pair"), s.kkOrderId(values.Get("txid")), "")
+	if err != nil {
+		s.kkEngineError(w, err)
+		return
+	}
+	req := OrderRequest{
+		Symbol:      old.Symbol,
+		Side:        old.Side,
+		Type:        old.Type,
+		TimeInForce: old.TimeInForce,
+		PostOnly:    old.PostOnly,
+	}
+	req.Price, _ = decimal.NewFromString(values.Get("price"))
+	req.Qty, _ = decimal.NewFromString(values.Get("volume"))
+	o, err := s.Engine.PlaceOrder(req)
+	if err != nil {
+		s.kkEngineError(w, err)
+		return
+	}
+	s.kkResult(w, map[string]any{"txid": kkTxidPrefix + itoa(o.Id), "originaltxid": values.Get("txid"),
+		"status": "ok", "price": o.Price, "volume": o.Qty})
+}
+func (s *Server) kkCancelAll(w http.ResponseWriter, values url.Values) {
+	n := 0
+	for _, o := range s.Engine.OpenOrders("") {
+		if _, err := s.Engine.CancelOrder("", o.Id, ""); err == nil {
+			n++
+		}
+	}
+	s.kkResult(w, map[string]any{"count": n})
+}
+func (s *Server) kkQueryOrders(w http.ResponseWriter, values url.Values) {
+	result := map[string]any{}
+	for _, txid := range strings.Split(values.Get("txid"), ",") {
+		if o, err := s.Engine.GetOrder(s.kkOrderId(txid), ""); err == nil {
+			result[txid] = s.kkOrder(o)
+		}
+	}
+	if len(result) == 0 {
+		s.kkEngineError(w, ErrOrderNotFound)
+		return
+	}
+	s.kkResult(w, result)
+}
+func (s *Server) kkOpenOrders(w http.ResponseWriter, values url.Values) {
+	open := map[string]any{}
+	for _, o := range s.Engine.OpenOrders("") {
+		open[kkTxidPrefix+itoa(o.Id)] = s.kkOrder(o)
+	}
+	s.kkResult(w, map[string]any{"open": open})
+}
+func (s *Server) kkOrder(o Order) map[string]any {
+	status := "open"
+	switch o.Status {
+	case "FILLED":
+		status = "closed"
+	case "CANCELED":
+		status = "canceled"
+	case "EXPIRED", "REJECTED":
+		status = "expired"
+	}
+	fee := o.Fee
+	if o.Side == "BUY" { // kraken 手续费都按quote计
+		fee = o.Fee.Mul(o.AvgPrice())
+	}
+	closetm := 0.0
+	if status != "open" {
+		closetm = float64(o.UTime) / 1000
+	}
+	return map[string]any{
+		"cl_ord_id": o.ClientId,
+		"status":    status,
+		"descr": map[string]any{
+			"pair":      o.Symbol,
+			"ordertype": strings.ToLower(o.Type),
+			"type":      strings.ToLower(o.Side),
+			"price":     o.Price,
+		},
+		"vol":      o.Qty,
+		"vol_exec": o.FilledQty,
+		"cost":     o.FilledAmt,
+		"price":    o.AvgPrice(),
+		"fee":      fee,
+		"opentm":   float64(o.CTime) / 1000,
+		"closetm":  closetm,
+	}
+}
+
+// ws
+type kkWsReq struct {
+	Method string `json:"method"`
+	Params struct {
+		Channel string   `json:"channel"`
+		Depth   int      `json:"depth"`
+		Symbol  []string `json:"symbol"`
+		Token   string   `json:"token"`
+	} `json:"params"`
+}
+
+func (s *Server) kkWs(c *wsConn, msg []byte) {
+	var req kkWsReq
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	if req.Method == "ping" {
+		c.write([]byte(`{"method":"pong"}`))
+		return
+	}
+	if req.Method != "subscribe" && req.Method != "unsubscribe" {
+		return
+	}
+	on := req.Method == "subscribe"
+	ch := req.Params.Channel
+	resp := func(success bool, errMsg string) {
+		v := map[string]any{"method": req.Method, "success": success, "result": map[string]any{"channel": ch}}
+		if errMsg != "" {
+			v["error"] = errMsg
+		}
+		frame, _ := json.Marshal(v)
+		c.write(frame)
+	}
+	switch ch {
+	case "book", "ticker":
+		for _, sym := range req.Params.Symbol {
+			key := ch + ":" + sym
+			if ch == "book" {
+				key = "book:" + strconv.Itoa(req.Params.Depth) + ":" + sym
+			}
+			c.subscribe(key, on)
+		}
+		resp(true, "")
+		if on { // 订阅后推送一次快照
+			for _, sym := range req.Params.Symbol {
+				s.kkPushBookTo(c, strings.ReplaceAll(sym, "/", ""))
+			}
+		}
+	case "executions", "balances":
+		if req.Params.Token != kkWsToken {
+			resp(false, "EAccess:Invalid token")
+			return
+		}
+		c.subscribe(ch, on)
+		resp(true, "")
+		if on && ch == "balances" {
+			l := []any{}
+			for _, b := range s.Engine.Balances() {
+				total := b.Free.Add(b.Locked)
+				l = append(l, map[string]any{"asset": b.Asset, "balance": json.Number(total.String()),
+					"wallets": []any{map[string]any{"type": "spot", "id": "main",
+						"balance": json.Number(total.String())}}})
+			}
+			frame, _ := json.Marshal(map[string]any{"channel": "balances", "type": "snapshot", "data": l})
+			c.write(frame)
+		}
+	default:
+		resp(false, "Channel not supported")
+	}
+}
+func (s *Server) kkWsSymbol(symbol string) string {
+	if sym, ok := s.Engine.Symbol(symbol); ok {
+		return sym.Base + "/" + sym.Quote
+	}
+	return symbol
+}
+func (s *Server) kkPushBook(symbol string) {
+	for _, c := range s.wsConns("/v2") {
+		s.kkPushBookTo(c, symbol)
+	}
+}
+
+// book 都以snapshot推送, checksum为前10档 卖盘+买盘 的crc32
+func (s *Server) kkPushBookTo(c *wsConn, symbol string) {
+	wsSymbol := s.kkWsSymbol(symbol)
+	num := func(d decimal.Decimal) json.Number {
+		return json.Number(d.String())
+	}
+	for _, depth := range []int{10, 25, 100, 500, 1000} {
+		if !c.subscribed("book:" + strconv.Itoa(depth) + ":" + wsSymbol) {
+			continue
+		}
+		bids, asks := s.Engine.Book(symbol, depth)
+		var buf strings.Builder
+		trim := func(v decimal.Decimal) string {
+			return strings.TrimLeft(strings.ReplaceAll(v.String(), ".", ""), "0")
+		}
+		levels := func(l []Level) []any {
+			r := []any{}
+			for _, v := range l {
+				r = append(r, map[string]any{"price": num(v.Price), "qty": num(v.Qty)})
+			}
+			return r
+		}
+		for i := 0; i < len(asks) && i < 10; i++ {
+			buf.WriteString(trim(asks[i].Price) + trim(asks[i].Qty))
+		}
+		for i := 0; i < len(bids) && i < 10; i++ {
+			buf.WriteString(trim(bids[i].Price) + trim(bids[i].Qty))
+		}
+		frame, _ := json.Marshal(map[string]any{"channel": "book", "type": "snapshot",
+			"data": []any{map[string]any{"symbol": wsSymbol, "bids": levels(bids), "asks": levels(asks),
+				"checksum":  crc32.ChecksumIEEE([]byte(buf.String())),
+				"timestamp": time.Now().UTC().Format(time.RFC3339Nano)}}})
+		c.write(frame)
+	}
+	if c.subscribed("ticker:" + wsSymbol) {
+		bids, asks := s.Engine.Book(symbol, 1)
+		if len(bids) == 0 || len(asks) == 0 {
+			return
+		}
+		frame, _ := json.Marshal(map[string]any{"channel": "ticker", "type": "update",
+			"data": []any{map[string]any{"symbol": wsSymbol,
+				"bid": num(bids[0].Price), "bid_qty": num(bids[0].Qty),
+				"ask": num(asks[0].Price), "ask_qty": num(asks[0].Qty)}}})
+		c.write(frame)
+	}
+}
+func (s *Server) kkPushOrder(o Order) {
+	status := "new"
+	switch o.Status {
+	case "PARTIALLY_FILLED":
+		status = "partially_filled"
+	case "FILLED":
+		status = "filled"
+	case "CANCELED":
+		status = "canceled"
+	case "EXPIRED", "REJECTED":
+		status = "expired"
+	}
+	fee := o.Fee
+	if o.Side == "BUY" { // kraken 手续费都按quote计
+		fee = o.Fee.Mul(o.AvgPrice())
+	}
+	quote := ""
+	if sym, ok := s.Engine.Symbol(o.Symbol); ok {
+		quote = sym.Quote
+	}
+	frame, _ := json.Marshal(map[string]any{"channel": "executions", "type": "update",
+		"data": []any{map[string]any{
+			"order_id":     kkTxidPrefix + itoa(o.Id),
+			"cl_ord_id":    o.ClientId,
+			"symbol":       s.kkWsSymbol(o.Symbol),
+			"limit_price":  o.Price,
+			"order_qty":    o.Qty,
+			"cum_qty":      o.FilledQty,
+			"cum_cost":     o.FilledAmt,
+			"avg_price":    o.AvgPrice(),
+			"order_status": status,
+			"order_type":   strings.ToLower(o.Type),
+			"side":         strings.ToLower(o.Side),
+			"fees":         []any{map[string]any{"asset": quote, "qty": fee}},
+			"timestamp":    time.UnixMilli(o.UTime).UTC().Format(time.RFC3339Nano),
+		}}})
+	for _, c := range s.wsConns("/v2") {
+		if c.subscribed("executions") {
+			c.write(frame)
+		}
+	}
+}
+
+// kraken 推送的是总额(含冻结)
+func (s *Server) kkPushBalance(b Balance) {
+	frame, _ := json.Marshal(map[string]any{"channel": "balances", "type": "update",
+		"data": []any{map[string]any{"asset": b.Asset, "balance": json.Number(b.Free.Add(b.Locked).String()),
+			"wallet_type": "spot", "wallet_id": "main"}}})
+	for _, c := range s.wsConns("/v2") {
+		if c.subscribed("balances") {
+			c.write(frame)
+		}
+	}
+}
@@ -0,0 +1,742 @@
+package cextest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type okOrderArg struct {
+	InstId   string `json:"instId"`
+	TdMode   string `json:"tdMode"`
+	OrdType  string `json:"ordType"`
+	Sz       string `json:"sz"`
+	Px       string `json:"px"`
+	Side     string `json:"side"`
+	ClOrdId  string `json:"clOrdId"`
+	OrdId    string `json:"ordId"`
+	NewSz    string `json:"newSz"`
+	NewPx    string `json:"newPx"`
+	InstType string `json:"instType"`
+	Channel  string `json:"channel"`
+}
+
+// orders-pending 默认每页100条
+const okOpenOrdersLimit = 100
+
+func (s *Server) initOkx() {
+	s.route("GET", "/api/v5/public/time", func(w http.ResponseWriter, r *http.Request) {
+		s.okData(w, []any{map[string]any{"ts": itoa(time.Now().UnixMilli())}})
+	})
+	s.route("GET", "/api/v5/public/instruments", s.okInstruments)
+	s.route("GET", "/api/v5/market/candles", s.okCandles)
+	s.route("GET", "/api/v5/account/balance", s.okSigned(s.okBalance))
+	s.route("POST", "/api/v5/trade/order", s.okSigned(s.okPlaceOrder))
+	s.route("POST", "/api/v5/trade/cancel-order", s.okSigned(s.okCancelOrder))
+	s.route("POST", "/api/v5/trade/amend-order", s.okSigned(s.okAmendOrder))
+	s.route("GET", "/api/v5/trade/order", s.okSigned(s.okGetOrder))
+	s.route("GET", "/api/v5/trade/orders-pending", s.okSigned(s.okOpenOrders))
+	s.route("POST", "/api/v5/trade/order-algo", s.okSigned(s.okPlaceAlgoOrder))
+	s.route("POST", "/api/v5/trade/cancel-algos", s.okSigned(s.okCancelAlgos))
+	s.route("GET", "/api/v5/trade/orders-algo-pending", s.okSigned(s.okAlgoOrdersPending))
+	s.route("POST", "/api/v5/trade/batch-orders", s.okSigned(s.okBatchOrders(s.okPlace)))
+	s.route("POST", "/api/v5/trade/cancel-batch-orders", s.okSigned(s.okBatchOrders(s.okCancel)))
+
+	s.wsRoute("/ws/v5/public", s.okWsPublic)
+	s.wsRoute("/ws/v5/private", s.okWsPrivate)
+	s.wsRoute("/ws/v5/business", s.okWsBusiness) // candle频道
+	s.Engine.OnBook(s.okPushBook)
+	s.Engine.OnKLine(func(symbol, interval string, k KLine) { s.okPushKLine(s.Engine, symbol, interval, k) })
+	s.Futures.OnKLine(func(symbol, interval string, k KLine) { s.okPushKLine(s.Futures, symbol, interval, k) })
+	s.Engine.OnOrder(s.okPushOrder)
+	s.Engine.OnBalance(s.okPushBalance)
+}
+
+func (s *Server) okSign(params string) string {
+	h := hmac.New(sha256.New, []byte(s.cfg.SecretKey))
+	h.Write([]byte(params))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+func (s *Server) okData(w http.ResponseWriter, data []any) {
+	writeJSON(w, 200, map[string]any{"code": "0", "msg": "", "data": data})
+}
+
+// okx 业务错误http status也是200, 错误码在data[0].sCode
+func (s *Server) okError(w http.ResponseWriter, code, msg string) {
+	writeJSON(w, 200, map[string]any{"code": "1", "msg": "",
+		"data": []any{map[string]any{"sCode": code, "sMsg": msg}}})
+}
+func (s *Server) okEngineError(err error) (string, string) {
+	switch err {
+	case ErrInsufficientFunds:
+		return "51008", "Order failed. Insufficient balance."
+	case ErrOrderNotFound:
+		return "51603", "Order does not exist"
+	case ErrInvalidSymbol:
+		return "51001", "Instrument ID does not exist"
+	}
+	return "51000", "Parameter error: " + err.Error()
+}
+
+// 校验 OK-ACCESS-SIGN = base64(hmac(ts + method + path?query + body))
+func (s *Server) okSigned(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("OK-ACCESS-KEY") != s.cfg.ApiKey {
+			writeJSON(w, 401, map[string]any{"code": "50111", "msg": "Invalid OK-ACCESS-KEY"})
+			return
+		}
+		if r.Header.Get("OK-ACCESS-PASSPHRASE") != s.cfg.Passphrase {
+			writeJSON(w, 401, map[string]any{"code": "50105", "msg": "Invalid OK-ACCESS-PASSPHRASE"})
+			return
+		}
+		path := r.URL.Path
+		if r.URL.RawQuery != "" {
+			path += "?" + r.URL.RawQuery
+		}
+		payload := r.Header.Get("OK-ACCESS-TIMESTAMP") + r.Method + path + string(readBody(r))
+		if !hmac.Equal([]byte(r.Header.Get("OK-ACCESS-SIGN")), []byte(s.okSign(payload))) {
+			writeJSON(w, 401, map[string]any{"code": "50113", "msg": "Invalid Sign"})
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// BTC-USDT-SWAP 为U本位永续, 对应 Futures 引擎
+func (s *Server) okEngine(instId string) (*Engine, string) {
+	if strings.HasSuffix(instId, "-SWAP") {
+		return s.Futures, strings.ReplaceAll(strings.TrimSuffix(instId, "-SWAP"), "-", "")
+	}
+	return s.Engine, strings.ReplaceAll(instId, "-", "")
+}
+func (s *Server) okInstruments(w http.ResponseWriter, r *http.Request) {
+	var data []any
+	if r.URL.Query().Get("instType") == "SWAP" {
+		for _, sym := range s.Futures.Symbols() {
+			data = append(data, map[string]any{
+				"instId":    sym.Base + "-" + sym.Quote + "-SWAP",
+				"instType":  "SWAP",
+				"ctType":    "linear",
+				"ctVal":     sym.ContractSize,
+				"settleCcy": sym.Quote,
+				"state":     "live",
+				"tickSz":    sym.TickSize,
+				"lotSz":     sym.StepSize.Div(sym.ContractSize),
+				"minSz":     sym.MinQty.Div(sym.ContractSize),
+				"maxLmtSz":  "9000000",
+			})
+		}
+		s.okData(w, data)
+		return
+	}
+	for _, sym := range s.Engine.Symbols() {
+		data = append(data, map[string]any{
+			"instId":   sym.Base + "-" + sym.Quote,
+			"instType": "SPOT",
+			"baseCcy":  sym.Base,
+			"quoteCcy": sym.Quote,
+			"state":    "live",
+			"tickSz":   sym.TickSize,
+			"lotSz":    sym.StepSize,
+			"minSz":    sym.MinQty,
+			"maxLmtSz": "9000000",
+		})
+	}
+	s.okData(w, data)
+}
+
+// bar 1m/1H/6Hutc/1Dutc => 1m/1h/6h/1d
+func okInterval(bar string) string {
+	return strings.ToLower(strings.TrimSuffix(bar, "utc"))
+}
+
+// okInterval 的逆转换
+func okBar(interval string) string {
+	switch interval {
+	case "1h", "2h", "4h":
+		return strings.ToUpper(interval)
+	case "6h", "12h", "1d":
+		return strings.ToUpper(interval) + "utc"
+	}
+	return interval
+}
+
+// [ts,o,h,l,c,vol,volCcy,volCcyQuote,confirm], 合约的vol为张数, volCcy为标的数量
+func (s *Server) okCandle(e *Engine, symbol string, k KLine) []string {
+	vol, volCcy := k.Volume, k.QuoteVolume
+	if e == s.Futures {
+		volCcy = k.Volume
+		if sym, ok := e.Symbol(symbol); ok && sym.ContractSize.IsPositive() {
+			vol = k.Volume.Div(sym.ContractSize)
+		}
+	}
+	confirm := "0"
+	if k.Closed {
+		confirm = "1"
+	}
+	return []string{itoa(k.OpenTime * 1000), k.Open.String(), k.High.String(), k.Low.String(),
+		k.Close.String(), vol.String(), volCcy.String(), k.QuoteVolume.String(), confirm}
+}
+
+// after: 早于该时间, before: 晚于该时间 (msec), 按时间倒序返回
+func (s *Server) okCandles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	after, _ := strconv.ParseInt(q.Get("after"), 10, 64)
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+	start, end := int64(0), int64(0)
+	if before > 0 {
+		start = before/1000 + 1
+	}
+	if after > 0 {
+		end = (after + 999) / 1000
+	}
+	e, symbol := s.okEngine(q.Get("instId"))
+	l := limitKLines(e.KLines(symbol, okInterval(q.Get("bar")), start, end), limit, false)
+	data := make([]any, 0, len(l))
+	for i := len(l) - 1; i >= 0; i-- {
+		data = append(data, s.okCandle(e, symbol, l[i]))
+	}
+	s.okData(w, data)
+}
+func (s *Server) okBalance(w http.ResponseWriter, r *http.Request) {
+	var details []any
+	for _, b := range s.Engine.Balances() {
+		details = append(details, map[string]any{"ccy": b.Asset,
+			"eq": b.Free.Add(b.Locked), "cashBal": b.Free, "frozenBal": b.Locked})
+	}
+	s.okData(w, []any{map[string]any{"details": details}})
+}
+func (s *Server) okPlace(arg okOrderArg) (Order, error) {
+	e, symbol := s.okEngine(arg.InstId)
+	req := OrderRequest{
+		Symbol:      symbol,
+		ClientId:    arg.ClOrdId,
+		Side:        strings.ToUpper(arg.Side),
+		TimeInForce: "GTC",
+	}
+	sz, _ := decimal.NewFromString(arg.Sz)
+	req.Price, _ = decimal.NewFromString(arg.Px)
+	req.Qty = sz
+	if e == s.Futures { // 合约sz为张数
+		sym, ok := e.Symbol(symbol)
+		if !ok {
+			return Order{}, ErrInvalidSymbol
+		}
+		req.Qty = sz.Mul(sym.ContractSize)
+	}
+	switch arg.OrdType {
+	case "limit":
+		req.Type = "LIMIT"
+	case "post_only":
+		req.Type, req.PostOnly = "LIMIT", true
+	case "ioc", "fok":
+		req.Type, req.TimeInForce = "LIMIT", strings.ToUpper(arg.OrdType)
+	case "market":
+		req.Type = "MARKET"
+		if req.Side == "BUY" && e == s.Engine { // 现货市价买单sz为quote金额
+			req.Qty, req.QuoteQty = decimal.Zero, sz
+		}
+	}
+	return e.PlaceOrder(req)
+}
+func (s *Server) okPlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var arg okOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.okError(w, "50002", "JSON syntax error")
+		return
+	}
+	o, err := s.okPlace(arg)
+	if err != nil {
+		code, msg := s.okEngineError(err)
+		s.okError(w, code, msg)
+		return
+	}
+	s.okData(w, []any{map[string]any{"ordId": itoa(o.Id), "clOrdId": o.ClientId,
+		"sCode": "0", "sMsg": "Order placed"}})
+}
+func (s *Server) okCancel(arg okOrderArg) (Order, error) {
+	id, _ := strconv.ParseInt(arg.OrdId, 10, 64)
+	e, symbol := s.okEngine(arg.InstId)
+	return e.CancelOrder(symbol, id, arg.ClOrdId)
+}
+func (s *Server) okCancelOrder(w http.ResponseWriter, r *http.Request) {
+	var arg okOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.okError(w, "50002", "JSON syntax error")
+		return
+	}
+	o, err := s.okCancel(arg)
+	if err != nil {
+		code, msg := s.okEngineError(err)
+		s.okError(w, code, msg)
+		return
+	}
+	s.okData(w, []any{map[string]any{"ordId": itoa(o.Id), "clOrdId": o.ClientId,
+		"sCode": "0", "sMsg": ""}})
+}
+
+// 原地改单, 合约newSz为张数
+func (s *Server) okAmendOrder(w http.ResponseWriter, r *http.Request) {
+	var arg okOrderArg
+	if json.Unmarshal(readBody(r), &arg) != nil {
+		s.okError(w, "50002", "JSON syntax error")
+		return
+	}
+	id, _ := strconv.ParseInt(arg.OrdId, 10, 64)
+	e, symbol := s.okEngine(arg.InstId)
+	px, _ := decimal.NewFromString(arg.NewPx)
+	sz, _ := decimal.NewFromString(arg.NewSz)
+	if e == s.Futures {
+		sym, ok := e.Symbol(symbol)
+		if !ok {
+			code, msg := s.okEngineError(ErrInvalidSymbol)
+			s.okError(w, code, msg)
+			return
+		}
+		sz = sz.Mul(sym.ContractSize)
+	}
+	o, err := e.AmendOrder(symbol, id, arg.ClOrdId, px, sz)
+	if err != nil {
+		code, msg := s.okEngineError(err)
+		s.okError(w, code, msg)
+		return
+	}
+	s.okData(w, []any{map[string]any{"ordId": itoa(o.Id), "clOrdId": o.ClientId,
+		"sCode": "0", "sMsg": ""}})
+}
+
+var okTriggerPxTypes = map[string]string{"last": "MARKET_PRICE", "mark": "MARK_PRICE", "index": "INDEX_PRICE"}
+
+// 条件单(ordType=conditional), 止损为sl*, 止盈为tp*, 委托价-1为市价
+func (s *Server) okPlaceAlgoOrder(w http.ResponseWriter, r *http.Request) {
+	arg := struct {
+		InstId          string `json:"instId"`
+		Side            string `json:"side"`
+		OrdType         string `json:"ordType"`
+		Sz              string `json:"sz"`
+		AlgoClOrdId     string `json:"algoClOrdId"`
+		SlTriggerPx     string `json:"slTriggerPx"`
+		SlOrdPx         string `json:"slOrdPx"`
+		SlTriggerPxType string `json:"slTriggerPxType"`
+		TpTriggerPx     string `json:"tpTriggerPx"`
+		TpOrdPx         string `json:"tpOrdPx"`
+		TpTriggerPxType string `json:"tpTriggerPxType"`
+	}{}
+	if json.Unmarshal(readBody(r), &arg) != nil || arg.OrdType != "conditional" {
+		s.okError(w, "50002", "JSON syntax error")
+		return
+	}
+	e, symbol := s.okEngine(arg.InstId)
+	o := ConditionalOrder{Symbol: symbol, ClientId: arg.AlgoClOrdId, Side: strings.ToUpper(arg.Side)}
+	typ, triggerPx, ordPx, pxType := "TAKE_PROFIT", arg.TpTriggerPx, arg.TpOrdPx, arg.TpTriggerPxType
+	if arg.SlTriggerPx != "" {
+		typ, triggerPx, ordPx, pxType = "STOP", arg.SlTriggerPx, arg.SlOrdPx, arg.SlTriggerPxType
+	}
+	o.Type = typ + "_MARKET"
+	if ordPx != "-1" {
+		o.Type = typ + "_LIMIT"
+		o.Price, _ = decimal.NewFromString(ordPx)
+	}
+	o.TriggerBy = okTriggerPxTypes[pxType]
+	o.TriggerPrice, _ = decimal.NewFromString(triggerPx)
+	o.Qty, _ = decimal.NewFromString(arg.Sz)
+	if sym, ok := e.Symbol(symbol); ok && e == s.Futures { // 合约sz为张数
+		o.Qty = o.Qty.Mul(sym.ContractSize)
+	}
+	o, err := e.PlaceConditionalOrder(o)
+	if err != nil {
+		code, msg := s.okEngineError(err)
+		s.okError(w, code, msg)
+		return
+	}
+	s.okData(w, []any{map[string]any{"algoId": itoa(o.Id), "algoClOrdId": o.ClientId,
+		"sCode": "0", "sMsg": ""}})
+}
+func (s *Server) okCancelAlgos(w http.ResponseWriter, r *http.Request) {
+	var args []struct {
+		InstId string `json:"instId"`
+		AlgoId string `json:"algoId"`
+	}
+	if json.Unmarshal(readBody(r), &args) != nil || len(args) == 0 {
+		s.okError(w, "50002", "JSON syntax error")
+		return
+	}
+	data := []any{}
+	for _, arg := range args {
+		id, _ := strconv.ParseInt(arg.AlgoId, 10, 64)
+		e, symbol := s.okEngine(arg.InstId)
+		if _, err := e.CancelConditionalOrder(symbol, id, ""); err != nil {
+			code, msg := s.okEngineError(err)
+			s.okError(w, code, msg)
+			return
+		}
+		data = append(data, map[string]any{"algoId": arg.AlgoId, "sCode": "0", "sMsg": ""})
+	}
+	s.okData(w, data)
+}
+func (s *Server) okAlgoOrdersPending(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	e, symbol := s.okEngine(q.Get("instId"))
+	if q.Get("instType") == "SWAP" {
+		e = s.Futures
+	}
+	data := []any{}
+	for _, o := range e.OpenConditionalOrders(symbol) {
+		sym, _ := e.Symbol(o.Symbol)
+		instId, sz := sym.Base+"-"+sym.Quote, o.Qty
+		if e == s.Futures {
+			instId, sz = instId+"-SWAP", o.Qty.Div(sym.ContractSize)
+		}
+		ordPx := "-1"
+		if o.IsLimit() {
+			ordPx = o.Price.String()
+		}
+		prefix := "tp"
+		if strings.HasPrefix(o.Type, "STOP") {
+			prefix = "sl"
+		}
+		pxType := ""
+		for k, v := range okTriggerPxTypes {
+			if v == o.TriggerBy {
+				pxType = k
+			}
+		}
+		data = append(data, map[string]any{
+			"instId":                 instId,
+			"algoId":                 itoa(o.Id),
+			"algoClOrdId":            o.ClientId,
+			"ordType":                "conditional",
+			"side":                   strings.ToLower(o.Side),
+			"sz":                     sz,
+			prefix + "TriggerPx":     o.TriggerPrice,
+			prefix + "OrdPx":         ordPx,
+			prefix + "TriggerPxType": pxType,
+			"state":                  "live",
+			"cTime":                  itoa(o.CTime),
+		})
+	}
+	s.okData(w, data)
+}
+
+// 批量接口逐个处理, 每个订单的结果在data[i].sCode
+func (s *Server) okBatchOrders(fn func(okOrderArg) (Order, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var args []okOrderArg
+		if json.Unmarshal(readBody(r), &args) != nil {
+			s.okError(w, "50002", "JSON syntax error")
+			return
+		}
+		if len(args) == 0 || len(args) > 20 {
+			s.okError(w, "51000", "Parameter error")
+			return
+		}
+		data := []any{}
+		failed := 0
+		for _, arg := range args {
+			o, err := fn(arg)
+			if err != nil {
+				failed++
+				code, msg := s.okEngineError(err)
+				data = append(data, map[string]any{"ordId": arg.OrdId, "clOrdId": arg.ClOrdId,
+					"sCode": code, "sMsg": msg})
+				continue
+			}
+			data = append(data, map[string]any{"ordId": itoa(o.Id), "clOrdId": o.ClientId,
+				"sCode": "0", "sMsg": ""})
+		}
+		code := "0" // 全部失败为1, 部分失败为2
+		if failed == len(args) {
+			code = "1"
+		} else if failed > 0 {
+			code = "2"
+		}
+		writeJSON(w, 200, map[string]any{"code": code, "msg": "", "data": data})
+	}
+}
+func (s *Server) okGetOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id, _ := strconv.ParseInt(q.Get("ordId"), 10, 64)
+	e, symbol := s.okEngine(q.Get("instId"))
+	o, err := e.GetOrder(id, q.Get("clOrdId"))
+	if err != nil || o.Symbol != symbol { // 查询类接口错误码在外层
+		code, msg := s.okEngineError(ErrOrderNotFound)
+		writeJSON(w, 200, map[string]any{"code": code, "msg": msg, "data": []any{}})
+		return
+	}
+	if e == s.Futures {
+		s.okData(w, []any{s.okFuturesOrder(o)})
+		return
+	}
+	s.okData(w, []any{s.okOrder(o)})
+}
+func (s *Server) okOpenOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	data := []any{}
+	if e, symbol := s.okEngine(q.Get("instId")); e == s.Futures || q.Get("instType") == "SWAP" {
+		for _, o := range s.Futures.OpenOrders(symbol) {
+			data = append(data, s.okFuturesOrder(o))
+		}
+		s.okData(w, data[:min(len(data), okOpenOrdersLimit)])
+		return
+	}
+	for _, o := range s.Engine.OpenOrders(strings.ReplaceAll(q.Get("instId"), "-", "")) {
+		data = append(data, s.okOrder(o))
+	}
+	s.okData(w, data[:min(len(data), okOpenOrdersLimit)])
+}
+func (s *Server) okOrder(o Order) map[string]any {
+	state := "canceled"
+	switch o.Status {
+	case "NEW":
+		state = "live"
+	case "PARTIALLY_FILLED":
+		state = "partially_filled"
+	case "FILLED":
+		state = "filled"
+	}
+	ordType := strings.ToLower(o.Type)
+	if o.PostOnly {
+		ordType = "post_only"
+	} else if o.Type == "LIMIT" && o.TimeInForce != "GTC" {
+		ordType = strings.ToLower(o.TimeInForce)
+	}
+	sz := o.Qty
+	if o.Type == "MARKET" && o.QuoteQty.IsPositive() {
+		sz = o.QuoteQty
+	}
+	instId := o.Symbol
+	if sym, ok := s.Engine.Symbol(o.Symbol); ok {
+		instId = sym.Base + "-" + sym.Quote
+	}
+	return map[string]any{
+		"instType":  "SPOT",
+		"instId":    instId,
+		"ordId":     itoa(o.Id),
+		"clOrdId":   o.ClientId,
+		"px":        o.Price,
+		"sz":        sz,
+		"accFillSz": o.FilledQty,
+		"avgPx":     o.AvgPrice(),
+		"state":     state,
+		"ordType":   ordType,
+		"side":      strings.ToLower(o.Side),
+		"feeCcy":    o.FeeAsset,
+		"fee":       o.Fee.Neg(), // okx 手续费为负数
+		"cTime":     itoa(o.CTime),
+		"uTime":     itoa(o.UTime),
+	}
+}
+
+// 合约订单sz/accFillSz为张数
+func (s *Server) okFuturesOrder(o Order) map[string]any {
+	v := s.okOrder(o)
+	sym, ok := s.Futures.Symbol(o.Symbol)
+	if !ok {
+		return v
+	}
+	v["instType"] = "SWAP"
+	v["instId"] = sym.Base + "-" + sym.Quote + "-SWAP"
+	v["sz"] = o.Qty.Div(sym.ContractSize)
+	v["accFillSz"] = o.FilledQty.Div(sym.ContractSize)
+	return v
+}
+
+// ws
+func (s *Server) okWsPublic(c *wsConn, msg []byte) {
+	req := struct {
+		Id   string       `json:"id"`
+		Op   string       `json:"op"`
+		Args []okOrderArg `json:"args"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	for _, arg := range req.Args {
+		c.subscribe(arg.Channel+":"+arg.InstId, req.Op == "subscribe")
+		resp, _ := json.Marshal(map[string]any{"id": req.Id, "event": req.Op,
+			"arg": map[string]any{"channel": arg.Channel, "instId": arg.InstId}})
+		c.write(resp)
+	}
+	if req.Op == "subscribe" {
+		for _, arg := range req.Args {
+			s.okPushBookTo(c, strings.ReplaceAll(arg.InstId, "-", ""))
+		}
+	}
+}
+func (s *Server) okWsBusiness(c *wsConn, msg []byte) {
+	req := struct {
+		Id   string       `json:"id"`
+		Op   string       `json:"op"`
+		Args []okOrderArg `json:"args"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	for _, arg := range req.Args {
+		c.subscribe(arg.Channel+":"+arg.InstId, req.Op == "subscribe")
+		resp, _ := json.Marshal(map[string]any{"id": req.Id, "event": req.Op,
+			"arg": map[string]any{"channel": arg.Channel, "instId": arg.InstId}})
+		c.write(resp)
+	}
+}
+func (s *Server) okWsPrivate(c *wsConn, msg []byte) {
+	req := struct {
+		Id   string       `json:"id"`
+		Op   string       `json:"op"`
+		Args []okOrderArg `json:"args"`
+	}{}
+	if json.Unmarshal(msg, &req) != nil {
+		return
+	}
+	var resp []byte
+	switch req.Op {
+	case "login":
+		login := struct {
+			Args []struct {
+				ApiKey     string `json:"apiKey"`
+				Passphrase string `json:"passphrase"`
+				Timestamp  string `json:"timestamp"`
+				Sign       string `json:"sign"`
+			} `json:"args"`
+		}{}
+		json.Unmarshal(msg, &login)
+		if len(login.Args) == 0 || login.Args[0].ApiKey != s.cfg.ApiKey ||
+			login.Args[0].Passphrase != s.cfg.Passphrase ||
+			!hmac.Equal([]byte(login.Args[0].Sign),
+				[]byte(s.okSign(login.Args[0].Timestamp+"GET/users/self/verify"))) {
+			resp, _ = json.Marshal(map[string]any{"event": "error", "code": "60009", "msg": "Login failed."})
+		} else {
+			c.setAuthed()
+			resp, _ = json.Marshal(map[string]any{"event": "login", "code": "0", "msg": ""})
+		}
+	case "subscribe", "unsubscribe":
+		if !c.isAuthed() {
+			resp, _ = json.Marshal(map[string]any{"event": "error", "code": "60011", "msg": "Please log in"})
+			break
+		}
+		for _, arg := range req.Args {
+			c.subscribe(arg.Channel, req.Op == "subscribe")
+		}
+		if len(req.Args) > 0 {
+			resp, _ = json.Marshal(map[string]any{"event": req.Op, "arg": req.Args[0]})
+		}
+	case "order", "cancel-order":
+		if !c.isAuthed() || len(req.Args) == 0 {
+			resp, _ = json.Marshal(map[string]any{"id": req.Id, "op": req.Op, "code": "60011", "msg": "Please log in"})
+			break
+		}
+		var o Order
+		var err error
+		if req.Op == "order" {
+			o, err = s.okPlace(req.Args[0])
+		} else {
+			o, err = s.okCancel(req.Args[0])
+		}
+		data := map[string]any{"ordId": itoa(o.Id), "clOrdId": o.ClientId, "sCode": "0", "sMsg": ""}
+		code := "0"
+		if err != nil {
+			sCode, sMsg := s.okEngineError(err)
+			data = map[string]any{"ordId": "", "clOrdId": req.Args[0].ClOrdId, "sCode": sCode, "sMsg": sMsg}
+			code = "1"
+		}
+		resp, _ = json.Marshal(map[string]any{"id": req.Id, "op": req.Op, "code": code, "msg": "",
+			"data": []any{data}})
+	}
+	if resp != nil {
+		c.write(resp)
+	}
+}
+func (s *Server) okPushBook(symbol string) {
+	for _, c := range s.wsConns("/ws/v5/public") {
+		s.okPushBookTo(c, symbol)
+	}
+}
+func (s *Server) okPushBookTo(c *wsConn, symbol string) {
+	sym, ok := s.Engine.Symbol(symbol)
+	if !ok {
+		return
+	}
+	instId := sym.Base + "-" + sym.Quote
+	ts := itoa(time.Now().UnixMilli())
+	bids, asks := s.Engine.Book(symbol, 5)
+	if len(bids) == 0 || len(asks) == 0 {
+		return
+	}
+	if c.subscribed("books5:" + instId) {
+		frame, _ := json.Marshal(map[string]any{
+			"arg":  map[string]any{"channel": "books5", "instId": instId},
+			"data": []any{map[string]any{"ts": ts, "bids": okLevels(bids), "asks": okLevels(asks)}},
+		})
+		c.write(frame)
+	}
+	if c.subscribed("bbo-tbt:" + instId) {
+		frame, _ := json.Marshal(map[string]any{
+			"arg":  map[string]any{"channel": "bbo-tbt", "instId": instId},
+			"data": []any{map[string]any{"ts": ts, "bids": okLevels(bids[:1]), "asks": okLevels(asks[:1])}},
+		})
+		c.write(frame)
+	}
+}
+func (s *Server) okPushKLine(e *Engine, symbol, interval string, k KLine) {
+	sym, ok := e.Symbol(symbol)
+	if !ok {
+		return
+	}
+	instId := sym.Base + "-" + sym.Quote
+	if e == s.Futures {
+		instId += "-SWAP"
+	}
+	channel := "candle" + okBar(interval)
+	frame, _ := json.Marshal(map[string]any{
+		"arg":  map[string]any{"channel": channel, "instId": instId},
+		"data": []any{s.okCandle(e, symbol, k)},
+	})
+	for _, c := range s.wsConns("/ws/v5/business") {
+		if c.subscribed(channel + ":" + instId) {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) okPushOrder(o Order) {
+	frame, _ := json.Marshal(map[string]any{
+		"arg":  map[string]any{"channel": "orders", "instType": "SPOT"},
+		"data": []any{s.okOrder(o)},
+	})
+	for _, c := range s.wsConns("/ws/v5/private") {
+		if c.subscribed("orders") {
+			c.write(frame)
+		}
+	}
+}
+func (s *Server) okPushBalance(b Balance) {
+	frame, _ := json.Marshal(map[string]any{
+		"arg": map[string]any{"channel": "balance_and_position"},
+		"data": []any{map[string]any{"pTime": itoa(time.Now().UnixMilli()),
+			"balData": []any{map[string]any{"ccy": b.Asset, "cashBal": b.Free.Add(b.Locked)}}}},
+	})
+	for _, c := range s.wsConns("/ws/v5/private") {
+		if c.subscribed("balance_and_position") {
+			c.write(frame)
+		}
+	}
+}
+
+// okx 深度为 [价格, 数量, 0, 订单数]
+func okLevels(l []Level) [][4]string {
+	v := make([][4]string, 0, len(l))
+	for _, lv := range l {
+		v = append(v, [4]string{lv.Price.String(), lv.Qty.String(), "0", "1"})
+	}
+	return v
+}
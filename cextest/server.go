@@ -0,0 +1,235 @@
+// cextest 提供进程内的mock交易所, 模拟各adapter用到的协议子集(签名rest, listenKey, 下单, ws推送),
+// 配合 cex.Options 使用, 不需要访问真实交易所即可测试策略代码
+//
+//	srv, _ := cextest.NewServer("binance", cextest.Config{ApiKey: "k", SecretKey: "s"})
+//	defer srv.Close()
+//	srv.Engine.AddSymbol(cextest.Symbol{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"})
+//	srv.Engine.SetBalance("USDT", decimal.NewFromInt(10000))
+//	ex, _ := cex.New("binance", "test", "k", "s", "", "", srv.Options())
+package cextest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/shaovie/cex"
+)
+
+type Config struct {
+	ApiKey     string
+	SecretKey  string
+	Passphrase string // okx
+}
+
+// 收到的rest请求
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+type scripted struct {
+	status int
+	body   string
+}
+
+type Server struct {
+	Engine  *Engine
+	Futures *Engine // U本位合约, 只有binance/okx/gate/bybit有合约路由
+
+	cexName string
+	cfg     Config
+	srv     *httptest.Server
+
+	mtx       sync.Mutex
+	routes    map[string]http.HandlerFunc // "METHOD /path"
+	prefixes  map[string]http.HandlerFunc // "METHOD /path/" 前缀匹配, 如 /api/v4/spot/orders/{id}
+	overrides map[string]http.HandlerFunc
+	failNext  map[string][]scripted
+	requests  []Request
+	listenKey int
+	changeId  atomic.Int64 // bigone depth推送序号
+
+	ws wsHub
+}
+
+func NewServer(cexName string, cfg Config) (*Server, error) {
+	s := &Server{
+		Engine:    NewEngine(),
+		Futures:   NewFuturesEngine(),
+		cexName:   cexName,
+		cfg:       cfg,
+		routes:    make(map[string]http.HandlerFunc),
+		prefixes:  make(map[string]http.HandlerFunc),
+		overrides: make(map[string]http.HandlerFunc),
+		failNext:  make(map[string][]scripted),
+	}
+	s.ws.init()
+	switch cexName {
+	case "binance":
+		s.initBinance()
+	case "okx":
+		s.initOkx()
+	case "gate":
+		s.initGate()
+	case "bybit":
+		s.initBybit()
+	case "bigone":
+		s.initBigone()
+	case "kraken":
+		s.initKraken()
+	default:
+		return nil, errors.New("cextest not support " + cexName)
+	}
+	s.srv = httptest.NewServer(s)
+	return s, nil
+}
+
+// 形如 http://127.0.0.1:port
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// 把cex object的rest/ws地址都指向本server
+func (s *Server) Options() *cex.Options {
+	return &cex.Options{
+		RestURL: s.srv.URL,
+		WsURL:   "ws" + strings.TrimPrefix(s.srv.URL, "http"),
+	}
+}
+func (s *Server) Close() {
+	s.ws.closeAll()
+	s.srv.CloseClientConnections()
+	s.srv.Close()
+}
+
+// 覆盖某个rest接口的处理, 用于模拟协议子集以外的接口
+func (s *Server) Handle(method, path string, fn http.HandlerFunc) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.overrides[method+" "+path] = fn
+}
+
+// 下一次请求该接口时直接返回status和body, 可以多次调用排队
+func (s *Server) FailNext(method, path string, status int, body string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k := method + " " + path
+	s.failNext[k] = append(s.failNext[k], scripted{status: status, body: body})
+}
+
+// 收到的所有rest请求
+func (s *Server) Requests() []Request {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.serveWs(w, r)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	k := r.Method + " " + r.URL.Path
+
+	s.mtx.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	if l := s.failNext[k]; len(l) > 0 {
+		s.failNext[k] = l[1:]
+		s.mtx.Unlock()
+		w.WriteHeader(l[0].status)
+		w.Write([]byte(l[0].body))
+		return
+	}
+	fn := s.overrides[k]
+	if fn == nil {
+		fn = s.routes[k]
+	}
+	if fn == nil {
+		matched := ""
+		for p, h := range s.prefixes {
+			if strings.HasPrefix(k, p) && len(p) > len(matched) {
+				matched, fn = p, h
+			}
+		}
+	}
+	s.mtx.Unlock()
+	if fn == nil {
+		http.NotFound(w, r)
+		return
+	}
+	fn(w, r)
+}
+
+// path以/结尾时为前缀匹配
+func (s *Server) route(method, path string, fn http.HandlerFunc) {
+	if strings.HasSuffix(path, "/") {
+		s.prefixes[method+" "+path] = fn
+		return
+	}
+	s.routes[method+" "+path] = fn
+}
+func (s *Server) newListenKey() string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.listenKey++
+	return "mocklistenkey" + itoa(int64(s.listenKey))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+func readBody(r *http.Request) []byte {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body
+}
+
+// 按limit截取k线, fromStart为true时取最早的limit根, 否则取最近的
+func limitKLines(l []KLine, limit int, fromStart bool) []KLine {
+	if limit <= 0 || len(l) <= limit {
+		return l
+	}
+	if fromStart {
+		return l[:limit]
+	}
+	return l[len(l)-limit:]
+}
+
+// 1m/1h/1d 等周期的秒数
+func intervalSeconds(interval string) int64 {
+	if len(interval) < 2 {
+		return 0
+	}
+	n, _ := strconv.ParseInt(interval[:len(interval)-1], 10, 64)
+	switch interval[len(interval)-1] {
+	case 'm':
+		return n * 60
+	case 'h', 'H':
+		return n * 3600
+	case 'd', 'D':
+		return n * 86400
+	}
+	return 0
+}
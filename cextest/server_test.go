@@ -0,0 +1,338 @@
+package cextest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shaovie/cex"
+	"github.com/shopspring/decimal"
+)
+
+var testCexNames = []string{"binance", "okx", "gate", "bybit", "bigone", "kraken"}
+
+func newTestServer(t *testing.T, cexName string) (*Server, cex.Exchanger) {
+	cfg := Config{ApiKey: "k", SecretKey: "c2VjcmV0", Passphrase: "p"} // kraken secret 为base64
+	srv, err := NewServer(cexName, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	srv.Engine.AddSymbol(Symbol{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"})
+	srv.Engine.SetBalance("USDT", decimal.NewFromInt(10000))
+	srv.Engine.SetBook("BTCUSDT",
+		[]Level{{Price: decimal.NewFromInt(99), Qty: decimal.NewFromInt(1)}},
+		[]Level{{Price: decimal.NewFromInt(101), Qty: decimal.NewFromInt(1)}})
+	ex, err := cex.New(cexName, "test", cfg.ApiKey, cfg.SecretKey, cfg.Passphrase, "", srv.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.SpotLoadAllPairRule(); err != nil { // symbol转换依赖交易对规则
+		t.Fatal(err)
+	}
+	return srv, ex
+}
+func TestSpotOrder(t *testing.T) {
+	for _, name := range testCexNames {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			orderId, err := ex.SpotPlaceOrder("BTCUSDT", "c1", decimal.NewFromInt(90), decimal.Zero,
+				decimal.NewFromInt(2), "BUY", "GTC", "LIMIT", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := srv.Engine.Balance("USDT"); !b.Locked.Equal(decimal.NewFromInt(180)) {
+				t.Fatalf("want locked 180 got %s", b.Locked)
+			}
+			o, err := ex.SpotGetOrder("BTCUSDT", orderId, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.OrderId != orderId || o.ClientId != "c1" || o.Symbol != "BTCUSDT" || o.Status != "NEW" || o.Side != "BUY" ||
+				!o.Price.Equal(decimal.NewFromInt(90)) || !o.Qty.Equal(decimal.NewFromInt(2)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+			l, err := ex.SpotGetOpenOrders("BTCUSDT")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 1 || l[0].OrderId != orderId || l[0].ClientId != "c1" || l[0].Symbol != "BTCUSDT" {
+				t.Fatalf("want 1 open order got %d", len(l))
+			}
+
+			// 部分成交
+			id := srv.Engine.OpenOrders("BTCUSDT")[0].Id
+			if _, err = srv.Engine.Fill(id, decimal.NewFromFloat(0.5), decimal.Zero); err != nil {
+				t.Fatal(err)
+			}
+			if o, err = ex.SpotGetOrder("BTCUSDT", orderId, ""); err != nil {
+				t.Fatal(err)
+			}
+			if o.Status != "PARTIALLY_FILLED" || !o.FilledQty.Equal(decimal.NewFromFloat(0.5)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+
+			if err = ex.SpotCancelOrder("BTCUSDT", orderId, ""); err != nil {
+				t.Fatal(err)
+			}
+			if l, err = ex.SpotGetOpenOrders("BTCUSDT"); err != nil || len(l) != 0 {
+				t.Fatalf("want no open orders got %d %v", len(l), err)
+			}
+			if b := srv.Engine.Balance("USDT"); !b.Locked.IsZero() || !b.Free.Equal(decimal.NewFromInt(9955)) {
+				t.Fatalf("unexpected balance %+v", b)
+			}
+		})
+	}
+}
+func TestSpotOrderError(t *testing.T) {
+	for _, name := range testCexNames {
+		t.Run(name, func(t *testing.T) {
+			_, ex := newTestServer(t, name)
+			_, err := ex.SpotPlaceOrder("BTCUSDT", "c2", decimal.NewFromInt(90), decimal.Zero,
+				decimal.NewFromInt(1000), "BUY", "GTC", "LIMIT", false)
+			if !errors.Is(err, cex.ErrInsufficientFunds) {
+				t.Errorf("place: want ErrInsufficientFunds got %v", err)
+			}
+			err = ex.SpotCancelOrder("BTCUSDT", "12345", "")
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Errorf("cancel: want ErrOrderNotFound got %v", err)
+			}
+			_, err = ex.SpotGetOrder("BTCUSDT", "12345", "")
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Errorf("get: want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
+
+var testFuturesCexNames = []string{"binance", "okx", "gate", "bybit"}
+
+func newTestFuturesServer(t *testing.T, cexName string) (*Server, cex.Exchanger) {
+	cfg := Config{ApiKey: "k", SecretKey: "c2VjcmV0", Passphrase: "p"}
+	srv, err := NewServer(cexName, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	srv.Futures.AddSymbol(Symbol{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT",
+		StepSize: decimal.NewFromFloat(0.1), ContractSize: decimal.NewFromFloat(0.1)})
+	srv.Futures.SetBalance("USDT", decimal.NewFromInt(10000))
+	srv.Futures.SetBook("BTCUSDT",
+		[]Level{{Price: decimal.NewFromInt(99), Qty: decimal.NewFromInt(1)}},
+		[]Level{{Price: decimal.NewFromInt(101), Qty: decimal.NewFromInt(1)}})
+	ex, err := cex.New(cexName, "test", cfg.ApiKey, cfg.SecretKey, cfg.Passphrase, "", srv.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.FuturesLoadAllPairRule("UM"); err != nil { // okx/gate 张数换算依赖合约面值
+		t.Fatal(err)
+	}
+	return srv, ex
+}
+func TestFuturesOrder(t *testing.T) {
+	for _, name := range testFuturesCexNames {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestFuturesServer(t, name)
+			orderId, err := ex.FuturesPlaceOrder("UM", "BTCUSDT", "c1", decimal.NewFromInt(110),
+				decimal.NewFromInt(2), "SELL", "LIMIT", "GTC", "BOTH", 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := srv.Futures.Balance("USDT"); !b.Locked.Equal(decimal.NewFromInt(220)) {
+				t.Fatalf("want margin 220 got %s", b.Locked)
+			}
+			o, err := ex.FuturesGetOrder("UM", "BTCUSDT", orderId, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.OrderId != orderId || o.ClientId != "c1" || o.Symbol != "BTCUSDT" || o.Status != "NEW" || o.Side != "SELL" ||
+				!o.Price.Equal(decimal.NewFromInt(110)) || !o.Qty.Equal(decimal.NewFromInt(2)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+			l, err := ex.FuturesGetOpenOrders("UM", "BTCUSDT")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 1 || l[0].OrderId != orderId || l[0].ClientId != "c1" || l[0].Symbol != "BTCUSDT" {
+				t.Fatalf("want 1 open order got %d", len(l))
+			}
+
+			// 部分成交
+			id := srv.Futures.OpenOrders("BTCUSDT")[0].Id
+			if _, err = srv.Futures.Fill(id, decimal.NewFromFloat(0.5), decimal.Zero); err != nil {
+				t.Fatal(err)
+			}
+			if o, err = ex.FuturesGetOrder("UM", "BTCUSDT", orderId, ""); err != nil {
+				t.Fatal(err)
+			}
+			if o.Status != "PARTIALLY_FILLED" || !o.FilledQty.Equal(decimal.NewFromFloat(0.5)) {
+				t.Fatalf("unexpected order %+v", *o)
+			}
+			if p := srv.Futures.Position("BTCUSDT"); !p.Equal(decimal.NewFromFloat(-0.5)) {
+				t.Fatalf("want position -0.5 got %s", p)
+			}
+
+			if err = ex.FuturesCancelOrder("UM", "BTCUSDT", orderId, ""); err != nil {
+				t.Fatal(err)
+			}
+			if l, err = ex.FuturesGetOpenOrders("UM", "BTCUSDT"); err != nil || len(l) != 0 {
+				t.Fatalf("want no open orders got %d %v", len(l), err)
+			}
+			// 成交部分的保证金已释放, 只扣手续费
+			if b := srv.Futures.Balance("USDT"); !b.Locked.IsZero() || !b.Free.Equal(decimal.RequireFromString("9999.945")) {
+				t.Fatalf("unexpected balance %+v", b)
+			}
+		})
+	}
+}
+func TestFuturesOrderError(t *testing.T) {
+	for _, name := range testFuturesCexNames {
+		t.Run(name, func(t *testing.T) {
+			_, ex := newTestFuturesServer(t, name)
+			_, err := ex.FuturesPlaceOrder("UM", "BTCUSDT", "c2", decimal.NewFromInt(90),
+				decimal.NewFromInt(1000), "BUY", "LIMIT", "GTC", "BOTH", 0, 0)
+			if !errors.Is(err, cex.ErrInsufficientFunds) {
+				t.Errorf("place: want ErrInsufficientFunds got %v", err)
+			}
+			err = ex.FuturesCancelOrder("UM", "BTCUSDT", "12345", "")
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Errorf("cancel: want ErrOrderNotFound got %v", err)
+			}
+			_, err = ex.FuturesGetOrder("UM", "BTCUSDT", "12345", "")
+			if !errors.Is(err, cex.ErrOrderNotFound) {
+				t.Errorf("get: want ErrOrderNotFound got %v", err)
+			}
+		})
+	}
+}
+
+// 等待服务端某个ws连接满足条件(订阅/登录完成), 避免推送早于订阅
+func waitWsConn(t *testing.T, srv *Server, ready func(c *wsConn) bool) {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, c := range srv.wsConns("/") {
+			if ready(c) {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("ws subscribe timeout")
+}
+
+// 从ch中读取直到match返回true
+func waitWsMsg(t *testing.T, ch <-chan any, what string, match func(v any) bool) {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				t.Fatal("ws closed, waiting for " + what)
+			}
+			if match(v) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("ws timeout, waiting for " + what)
+		}
+	}
+}
+func TestSpotWsPublic(t *testing.T) {
+	for _, name := range testCexNames {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			if err := ex.SpotWsPublicOpen(); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan any, 64)
+			go ex.SpotWsPublicLoop(ch)
+			t.Cleanup(func() {
+				ex.SpotWsPublicClose()
+				for range ch {
+				}
+			})
+			ex.SpotWsPublicSubscribe([]string{"bbo@BTCUSDT"})
+			waitWsMsg(t, ch, "bbo snapshot", func(v any) bool {
+				bbo, ok := v.(*cex.BestBidAsk)
+				return ok && bbo.Symbol == "BTCUSDT" &&
+					bbo.BidPrice.Equal(decimal.NewFromInt(99)) && bbo.AskPrice.Equal(decimal.NewFromInt(101))
+			})
+
+			srv.Engine.SetBook("BTCUSDT",
+				[]Level{{Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(2)}},
+				[]Level{{Price: decimal.NewFromInt(102), Qty: decimal.NewFromInt(3)}})
+			waitWsMsg(t, ch, "bbo update", func(v any) bool {
+				bbo, ok := v.(*cex.BestBidAsk)
+				return ok && bbo.Symbol == "BTCUSDT" &&
+					bbo.BidPrice.Equal(decimal.NewFromInt(100)) && bbo.BidQty.Equal(decimal.NewFromInt(2)) &&
+					bbo.AskPrice.Equal(decimal.NewFromInt(102)) && bbo.AskQty.Equal(decimal.NewFromInt(3))
+			})
+		})
+	}
+}
+
+// 服务端完成订单和余额订阅的标志
+var testWsPrivateReady = map[string]func(c *wsConn) bool{
+	"binance": func(c *wsConn) bool { return c.isAuthed() },
+	"okx": func(c *wsConn) bool {
+		return c.subscribed("orders") && c.subscribed("balance_and_position")
+	},
+	"gate": func(c *wsConn) bool {
+		return c.subscribed("spot.orders") && c.subscribed("spot.balances")
+	},
+	"bybit":  func(c *wsConn) bool { return c.subscribed("order.spot") && c.subscribed("wallet") },
+	"bigone": func(c *wsConn) bool { return c.subscribed("orders") && c.subscribed("accounts") },
+	"kraken": func(c *wsConn) bool { return c.subscribed("executions") && c.subscribed("balances") },
+}
+
+func TestSpotWsPrivate(t *testing.T) {
+	for _, name := range testCexNames {
+		t.Run(name, func(t *testing.T) {
+			srv, ex := newTestServer(t, name)
+			if err := ex.SpotWsPrivateOpen(); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan any, 64)
+			go ex.SpotWsPrivateLoop(ch)
+			t.Cleanup(func() {
+				ex.SpotWsPrivateClose()
+				for range ch {
+				}
+			})
+			ex.SpotWsPrivateSubscribe([]string{"orders", "balance"})
+			waitWsConn(t, srv, testWsPrivateReady[name])
+
+			orderId, err := ex.SpotPlaceOrder("BTCUSDT", "c1", decimal.NewFromInt(90), decimal.Zero,
+				decimal.NewFromInt(2), "BUY", "GTC", "LIMIT", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			waitWsMsg(t, ch, "order new", func(v any) bool {
+				o, ok := v.(*cex.SpotOrder)
+				return ok && o.OrderId == orderId && o.Symbol == "BTCUSDT" && o.Status == "NEW" &&
+					o.Side == "BUY" && o.Price.Equal(decimal.NewFromInt(90)) && o.Qty.Equal(decimal.NewFromInt(2))
+			})
+			waitWsMsg(t, ch, "balance", func(v any) bool {
+				a, ok := v.(*cex.SpotAsset)
+				return ok && a.Symbol == "USDT" && a.Total.Equal(decimal.NewFromInt(10000))
+			})
+
+			id := srv.Engine.OpenOrders("BTCUSDT")[0].Id
+			if _, err = srv.Engine.Fill(id, decimal.NewFromFloat(0.5), decimal.Zero); err != nil {
+				t.Fatal(err)
+			}
+			waitWsMsg(t, ch, "order partially filled", func(v any) bool {
+				o, ok := v.(*cex.SpotOrder)
+				return ok && o.OrderId == orderId && o.Status == "PARTIALLY_FILLED" &&
+					o.FilledQty.Equal(decimal.NewFromFloat(0.5))
+			})
+			if err = ex.SpotCancelOrder("BTCUSDT", orderId, ""); err != nil {
+				t.Fatal(err)
+			}
+			waitWsMsg(t, ch, "order canceled", func(v any) bool {
+				o, ok := v.(*cex.SpotOrder)
+				return ok && o.OrderId == orderId && o.Status == "CANCELED"
+			})
+		})
+	}
+}
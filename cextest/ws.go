@@ -0,0 +1,166 @@
+package cextest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+type wsConn struct {
+	conn *websocket.Conn
+	path string
+
+	mtx    sync.Mutex
+	subs   map[string]bool // 协议自定义的订阅key
+	authed bool
+}
+
+func (c *wsConn) write(msg []byte) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.conn.WriteMessage(websocket.TextMessage, msg)
+}
+func (c *wsConn) subscribe(key string, on bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if on {
+		c.subs[key] = true
+	} else {
+		delete(c.subs, key)
+	}
+}
+func (c *wsConn) subscribed(key string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.subs[key]
+}
+func (c *wsConn) setAuthed() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.authed = true
+}
+func (c *wsConn) isAuthed() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.authed
+}
+
+type wsHandler func(c *wsConn, msg []byte)
+
+type wsHub struct {
+	upgrader websocket.Upgrader
+	mtx      sync.Mutex
+	conns    map[*wsConn]struct{}
+	handlers map[string]wsHandler // path前缀 -> 处理函数
+	received map[string][][]byte  // path -> 收到的消息
+}
+
+func (h *wsHub) init() {
+	h.upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	h.conns = make(map[*wsConn]struct{})
+	h.handlers = make(map[string]wsHandler)
+	h.received = make(map[string][][]byte)
+}
+func (h *wsHub) closeAll() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for c := range h.conns {
+		c.conn.Close()
+	}
+}
+func (h *wsHub) handler(path string) wsHandler {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	matched := ""
+	var fn wsHandler
+	for p, f := range h.handlers {
+		if strings.HasPrefix(path, p) && len(p) > len(matched) {
+			matched, fn = p, f
+		}
+	}
+	return fn
+}
+
+// 注册ws处理, path以/结尾时为前缀匹配
+func (s *Server) wsRoute(path string, fn wsHandler) {
+	s.ws.mtx.Lock()
+	defer s.ws.mtx.Unlock()
+	s.ws.handlers[path] = fn
+}
+
+// 所有path有该前缀的连接都会收到frame, 用于推送协议子集以外的消息
+// 返回推送到的连接数
+func (s *Server) PushWs(pathPrefix string, frame []byte) int {
+	n := 0
+	for _, c := range s.wsConns(pathPrefix) {
+		if c.write(frame) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// 客户端发到该path的所有消息
+func (s *Server) WsReceived(path string) [][]byte {
+	s.ws.mtx.Lock()
+	defer s.ws.mtx.Unlock()
+	return append([][]byte(nil), s.ws.received[path]...)
+}
+
+// 断开所有path有该前缀的连接, 用于测试重连
+func (s *Server) DropWs(pathPrefix string) {
+	for _, c := range s.wsConns(pathPrefix) {
+		c.conn.Close()
+	}
+}
+func (s *Server) wsConns(pathPrefix string) []*wsConn {
+	s.ws.mtx.Lock()
+	defer s.ws.mtx.Unlock()
+	var l []*wsConn
+	for c := range s.ws.conns {
+		if strings.HasPrefix(c.path, pathPrefix) {
+			l = append(l, c)
+		}
+	}
+	return l
+}
+func (s *Server) serveWs(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &wsConn{conn: conn, path: r.URL.Path, subs: make(map[string]bool)}
+	s.ws.mtx.Lock()
+	s.ws.conns[c] = struct{}{}
+	s.ws.mtx.Unlock()
+	defer func() {
+		s.ws.mtx.Lock()
+		delete(s.ws.conns, c)
+		s.ws.mtx.Unlock()
+		conn.Close()
+	}()
+	fn := s.ws.handler(c.path)
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.ws.mtx.Lock()
+		s.ws.received[c.path] = append(s.ws.received[c.path], msg)
+		s.ws.mtx.Unlock()
+		if string(msg) == "ping" {
+			c.write([]byte("pong"))
+			continue
+		}
+		if fn != nil {
+			fn(c, msg)
+		}
+	}
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -0,0 +1,42 @@
+package cex
+
+import (
+	"context"
+	"maps"
+)
+
+type requestHeadersKey struct{}
+
+// 返回绑定ctx的rest客户端, 用于取消请求/设置单次请求的deadline/透传trace信息
+// ctx带deadline时会替代交易所默认的超时(如bnApiDeadline)
+// 返回的对象与ex共享key和连接池, 仅用于rest api, 不要在上面调用ws相关接口
+//
+//	cctx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+//	defer cancel()
+//	orderId, err := cex.WithContext(cctx, ex).SpotPlaceOrder(...)
+func WithContext(ctx context.Context, ex Exchanger) Exchanger {
+	if c, ok := ex.(interface {
+		withContext(context.Context) Exchanger
+	}); ok {
+		return c.withContext(ctx)
+	}
+	return ex
+}
+
+// 在ctx中附加http请求头(如 X-Trace-Id), 通过WithContext发出的请求都会带上
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	merged := make(map[string]string, len(headers))
+	maps.Copy(merged, requestHeadersFromContext(ctx))
+	maps.Copy(merged, headers)
+	return context.WithValue(ctx, requestHeadersKey{}, merged)
+}
+func requestHeadersFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	v, _ := ctx.Value(requestHeadersKey{}).(map[string]string)
+	return v
+}
@@ -0,0 +1,55 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = WithContext(ctx, ex).SpotServerTime()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("want ErrTimeout, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded in chain, got %v", err)
+	}
+	if strings.Contains(err.Error(), bnApiDeadline.String()) {
+		t.Fatalf("error reports default timeout instead of caller deadline: %v", err)
+	}
+}
+func TestWithContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = WithContext(ctx, ex).SpotServerTime()
+	if !errors.Is(err, ErrNetwork) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("want ErrNetwork wrapping context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package cex
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shaovie/gutils/ilog"
+)
+
+// 倒计时撤单的守护协程: 定时调用CancelAllOrdersAfter续期,
+// 进程退出/卡死/断网超过timeout秒后, 交易所自动撤销所有挂单
+//
+//	d := cex.NewDeadManSwitch(ex, "UM", "BTCUSDT", 60, nil)
+//	if err := d.Start(); err != nil {
+//		return err
+//	}
+//	defer d.Stop()
+type DeadManSwitch struct {
+	ex       Exchanger
+	typ      string // SPOT/UM/CM
+	symbol   string
+	timeout  int           // 秒
+	interval time.Duration // 续期周期, timeout/3
+	onError  func(error)   // 续期失败回调, nil时打印日志
+
+	mtx       sync.Mutex
+	started   bool
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewDeadManSwitch(ex Exchanger, typ, symbol string, timeout int,
+	onError func(error)) *DeadManSwitch {
+	d := &DeadManSwitch{
+		ex:        ex,
+		typ:       typ,
+		symbol:    symbol,
+		timeout:   timeout,
+		interval:  time.Duration(timeout) * time.Second / 3,
+		onError:   onError,
+		closeChan: make(chan struct{}),
+	}
+	if d.interval < time.Second {
+		d.interval = time.Second
+	}
+	return d
+}
+
+// 同步设置一次倒计时, 成功后启动续期协程
+func (d *DeadManSwitch) Start() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.closed {
+		return errors.New("dead man switch closed")
+	}
+	if d.started {
+		return nil
+	}
+	if d.timeout <= 0 {
+		return errors.New("dead man switch timeout must be positive")
+	}
+	if err := d.ex.CancelAllOrdersAfter(d.typ, d.symbol, d.timeout); err != nil {
+		return err
+	}
+	d.started = true
+	go d.loop()
+	return nil
+}
+
+// 停止续期并取消倒计时(bybit DCP不支持取消)
+func (d *DeadManSwitch) Stop() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	close(d.closeChan)
+	if !d.started {
+		return nil
+	}
+	return d.ex.CancelAllOrdersAfter(d.typ, d.symbol, 0)
+}
+func (d *DeadManSwitch) loop() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.closeChan:
+			return
+		case <-ticker.C:
+			if err := d.ex.CancelAllOrdersAfter(d.typ, d.symbol, d.timeout); err != nil {
+				if d.onError != nil {
+					d.onError(err)
+				} else {
+					ilog.Warning("%s", d.ex.Name()+" dead man switch rearm fail: "+err.Error())
+				}
+			}
+		}
+	}
+}
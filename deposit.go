@@ -0,0 +1,144 @@
+package cex
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shaovie/gutils/ilog"
+)
+
+// 充值到账跟踪: 定时调用GetDepositHistory查询最近lookback时间内的充值记录,
+// 新出现的记录或状态(PENDING/CREDITED/FAILED)发生变化的记录推送到ch
+// Start时已存在的记录只作为基准不推送, 之后其状态变化仍会推送
+// Stop后会close(ch)
+//
+//	w := cex.NewDepositWatcher(ex, "USDT", 30*time.Second, 24*time.Hour, nil)
+//	ch := make(chan cex.DepositResult, 16)
+//	if err := w.Start(ch); err != nil {
+//		return err
+//	}
+//	defer w.Stop()
+//	for d := range ch {
+//		...
+//	}
+type DepositWatcher struct {
+	ex       Exchanger
+	symbol   string
+	interval time.Duration // 查询周期
+	lookback time.Duration // 查询时间范围
+	onError  func(error)   // 查询失败回调, nil时打印日志
+
+	states map[string]DepositResult // id -> 上次的记录, 只在loop中访问
+
+	mtx       sync.Mutex
+	started   bool
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewDepositWatcher(ex Exchanger, symbol string, interval, lookback time.Duration,
+	onError func(error)) *DepositWatcher {
+	w := &DepositWatcher{
+		ex:        ex,
+		symbol:    symbol,
+		interval:  interval,
+		lookback:  lookback,
+		onError:   onError,
+		states:    make(map[string]DepositResult),
+		closeChan: make(chan struct{}),
+	}
+	if w.interval < time.Second {
+		w.interval = time.Second
+	}
+	if w.lookback < w.interval {
+		w.lookback = w.interval
+	}
+	return w
+}
+
+// 同步查询一次作为基准, 成功后启动查询协程
+func (w *DepositWatcher) Start(ch chan<- DepositResult) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.closed {
+		return errors.New("deposit watcher closed")
+	}
+	if w.started {
+		return nil
+	}
+	l, err := w.query()
+	if err != nil {
+		return err
+	}
+	for _, d := range l {
+		w.states[w.key(&d)] = d
+	}
+	w.started = true
+	go w.loop(ch)
+	return nil
+}
+func (w *DepositWatcher) Stop() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.closeChan)
+}
+func (w *DepositWatcher) loop(ch chan<- DepositResult) {
+	defer close(ch)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.closeChan:
+			return
+		case <-ticker.C:
+			l, err := w.query()
+			if err != nil {
+				if w.onError != nil {
+					w.onError(err)
+				} else {
+					ilog.Warning("%s", w.ex.Name()+" deposit watcher query fail: "+err.Error())
+				}
+				continue
+			}
+			for _, d := range l {
+				k := w.key(&d)
+				if old, ok := w.states[k]; ok && old.Status == d.Status {
+					continue
+				}
+				w.states[k] = d
+				select {
+				case ch <- d:
+				case <-w.closeChan:
+					return
+				}
+			}
+			w.prune()
+		}
+	}
+}
+func (w *DepositWatcher) query() ([]DepositResult, error) {
+	return w.ex.GetDepositHistory(w.symbol, time.Now().Add(-w.lookback).Unix(), 0)
+}
+
+// 部分交易所待确认的记录没有txid, 优先使用记录ID
+func (w *DepositWatcher) key(d *DepositResult) string {
+	if d.Id != "" {
+		return d.Id
+	}
+	return d.Network + ":" + d.Txid
+}
+
+// 清理已超出查询范围的记录
+func (w *DepositWatcher) prune() {
+	expire := time.Now().Add(-2 * w.lookback).Unix()
+	for k, d := range w.states {
+		if d.Time > 0 && d.Time < expire {
+			delete(w.states, k)
+		}
+	}
+}
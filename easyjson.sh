@@ -6,3 +6,5 @@ $GOPATH/bin/easyjson -all bybit_struct.go
 $GOPATH/bin/easyjson -all kraken_struct.go
 $GOPATH/bin/easyjson -all ktx_struct.go
 $GOPATH/bin/easyjson -all kucoin_struct.go
+$GOPATH/bin/easyjson -all bitget_struct.go
+$GOPATH/bin/easyjson -all htx_struct.go
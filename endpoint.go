@@ -0,0 +1,180 @@
+package cex
+
+import (
+	"errors"
+	"maps"
+	"strings"
+)
+
+// 创建cex object时的可选项, 用于测试网/模拟盘/区域域名/本地mock server
+type Options struct {
+	// 使用交易所的测试网(binance testnet, okx 模拟盘, bybit testnet, gate testnet(rest和合约ws))
+	Testnet bool
+	// 所有rest请求都发到这个地址(只替换scheme和host, path保持不变), 如 http://127.0.0.1:8080
+	RestURL string
+	// 所有ws连接都连到这个地址(只替换scheme和host, path保持不变), 如 ws://127.0.0.1:8080
+	WsURL string
+	// 按名字覆盖单个地址, 优先级最高, 名字见 EndpointNames
+	// 如 binance: {"spot": "https://api.binance.us"}
+	Endpoints map[string]string
+	// 每个rest请求附加的header
+	Headers map[string]string
+}
+
+// 各交易所可覆盖的地址 name -> 默认的base url, ws开头的为websocket地址
+var cexEndpoints = map[string]map[string]string{
+	"binance": {
+		"spot":        bnSpotEndpoint, // 同时用于 margin/wallet
+		"um":          bnUMFuturesEndpoint,
+		"cm":          bnCMFuturesEndpoint,
+		"unified":     bnUnifiedEndpoint,
+		"ws.spot":     "wss://stream.binance.com:443",
+		"ws.spot.api": "wss://ws-api.binance.com:443",
+		"ws.um":       "wss://fstream.binance.com", // 同时用于 unified
+		"ws.cm":       "wss://dstream.binance.com",
+		"ws.um.api":   "wss://ws-fapi.binance.com",
+		"ws.cm.api":   "wss://ws-dapi.binance.com",
+	},
+	"okx": {
+		"rest": okUniEndpoint,
+		"ws":   "wss://ws.okx.com:8443",
+	},
+	"gate": {
+		"rest":       gtUniEndpoint,
+		"ws":         "wss://api.gateio.ws",
+		"ws.futures": "wss://fx-ws.gateio.ws",
+	},
+	"bybit": {
+		"rest": bbUniEndpoint,
+		"ws":   "wss://stream.bybit.com",
+	},
+	"bigone": {
+		"rest": boSpotEndpoint,
+		"ws":   "wss://big.one",
+	},
+	"htx": {
+		"spot":    htSpotEndpoint, // 同时用于 wallet
+		"swap":    htSwapEndpoint,
+		"ws.spot": "wss://api.huobi.pro",
+		"ws.swap": "wss://api.hbdm.com",
+	},
+	"kraken": {
+		"rest":    kkSpotEndpoint,
+		"ws":      "wss://ws.kraken.com",
+		"ws.auth": "wss://ws-auth.kraken.com",
+	},
+	"ktx": {
+		"rest":    ktxSpotEndpoint,
+		"ws":      "wss://m-stream.ktx.com",
+		"ws.auth": "wss://u-stream.ktx.com",
+	},
+	"kucoin": {
+		"rest": kcSpotEndpoint,
+		"ws":   "wss://ws-api-spot.kucoin.com", // bullet返回的地址
+	},
+	"bitget": {
+		"rest": bgUniEndpoint,
+		"ws":   "wss://ws.bitget.com",
+	},
+	"mexc": {
+		"rest": mcUniEndpoint,
+		"ws":   "wss://wbs-api.mexc.com",
+	},
+}
+
+// 测试网地址 name -> base url, 没有列出的保持默认
+var cexTestnetEndpoints = map[string]map[string]string{
+	"binance": {
+		"spot":        "https://testnet.binance.vision",
+		"um":          "https://testnet.binancefuture.com",
+		"cm":          "https://testnet.binancefuture.com",
+		"ws.spot":     "wss://stream.testnet.binance.vision",
+		"ws.spot.api": "wss://ws-api.testnet.binance.vision",
+		"ws.um":       "wss://fstream.binancefuture.com",
+		"ws.cm":       "wss://dstream.binancefuture.com",
+		"ws.um.api":   "wss://testnet.binancefuture.com",
+		"ws.cm.api":   "wss://testnet.binancefuture.com",
+	},
+	"okx": { // 模拟盘rest域名不变, 通过header区分
+		"ws": "wss://wspap.okx.com:8443",
+	},
+	"gate": { // 现货ws没有测试网
+		"rest":       "https://api-testnet.gateapi.io",
+		"ws.futures": "wss://fx-ws-testnet.gateio.ws",
+	},
+	"bybit": {
+		"rest": "https://api-testnet.bybit.com",
+		"ws":   "wss://stream-testnet.bybit.com",
+	},
+}
+var cexTestnetHeaders = map[string]map[string]string{
+	"okx": {"x-simulated-trading": "1"},
+}
+
+// 返回交易所可以在 Options.Endpoints 中覆盖的地址名及默认值
+func EndpointNames(cexName string) map[string]string {
+	return maps.Clone(cexEndpoints[cexName])
+}
+
+// 根据opts生成 默认base url -> 替换的base url
+func (h *Http) applyOptions(cexName string, opts *Options) error {
+	defaults := cexEndpoints[cexName]
+	if defaults == nil {
+		return errors.New(cexName + " not support endpoint options")
+	}
+	endpoints := make(map[string]string)
+	headers := make(map[string]string)
+	if opts.Testnet {
+		testnet := cexTestnetEndpoints[cexName]
+		if testnet == nil {
+			return errors.New(cexName + " not support testnet")
+		}
+		for name, url := range testnet {
+			endpoints[defaults[name]] = url
+		}
+		maps.Copy(headers, cexTestnetHeaders[cexName])
+	}
+	for name, def := range defaults {
+		isWs := strings.HasPrefix(name, "ws")
+		if !isWs && opts.RestURL != "" {
+			endpoints[urlOrigin(def)] = strings.TrimSuffix(opts.RestURL, "/")
+		} else if isWs && opts.WsURL != "" {
+			endpoints[urlOrigin(def)] = strings.TrimSuffix(opts.WsURL, "/")
+		}
+	}
+	for name, url := range opts.Endpoints {
+		def, ok := defaults[name]
+		if !ok {
+			return errors.New(cexName + " unknown endpoint name: " + name)
+		}
+		endpoints[def] = strings.TrimSuffix(url, "/")
+	}
+	maps.Copy(headers, opts.Headers)
+	h.endpoints = endpoints
+	h.headers = headers
+	return nil
+}
+
+// 把默认地址替换成Options中指定的地址, 多个匹配时取最长的
+func (h *Http) rewriteUrl(link string) string {
+	matched := ""
+	for def := range h.endpoints {
+		if len(def) > len(matched) && strings.HasPrefix(link, def) {
+			matched = def
+		}
+	}
+	if matched == "" {
+		return link
+	}
+	return h.endpoints[matched] + link[len(matched):]
+}
+func (h *Http) wsUrl(link string) string {
+	return h.rewriteUrl(link)
+}
+
+// https://big.one/api/v3 -> https://big.one
+func urlOrigin(link string) string {
+	scheme, rest, _ := strings.Cut(link, "://")
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host
+}
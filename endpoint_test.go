@@ -0,0 +1,61 @@
+package cex
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGateFuturesWsEndpoint(t *testing.T) {
+	cases := []struct {
+		opts *Options
+		want string
+	}{
+		{&Options{}, "wss://fx-ws.gateio.ws/v4/ws/usdt"},
+		{&Options{WsURL: "ws://127.0.0.1:8080"}, "ws://127.0.0.1:8080/v4/ws/usdt"},
+		{&Options{Testnet: true}, "wss://fx-ws-testnet.gateio.ws/v4/ws/usdt"},
+		{&Options{Endpoints: map[string]string{"ws.futures": "wss://fx.example.com/"}}, "wss://fx.example.com/v4/ws/usdt"},
+	}
+	for _, c := range cases {
+		ex, err := New("gate", "test", "k", "s", "", "", c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gt := ex.(*Gate)
+		if got := gt.wsUrl("wss://fx-ws.gateio.ws/v4/ws/" + gt.futuresSettle("UM")); got != c.want {
+			t.Errorf("%+v: want %s got %s", *c.opts, c.want, got)
+		}
+	}
+}
+func TestHtxSignedUrlEndpoint(t *testing.T) {
+	cases := []struct {
+		opts     *Options
+		wantHost string
+		wantPath string
+	}{
+		{&Options{}, "api.huobi.pro", "/v1/order/openOrders"},
+		{&Options{RestURL: "http://127.0.0.1:8080"}, "127.0.0.1:8080", "/v1/order/openOrders"},
+		{&Options{Endpoints: map[string]string{"spot": "https://htx.example.com/proxy/"}},
+			"htx.example.com", "/proxy/v1/order/openOrders"},
+	}
+	for _, c := range cases {
+		ex, err := New("htx", "test", "k", "s", "", "", c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ht := ex.(*Htx)
+		link := ht.signedUrl("GET", htSpotEndpoint, "/v1/order/openOrders", nil)
+		u, err := url.Parse(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Host != c.wantHost || u.Path != c.wantPath {
+			t.Errorf("%+v: want %s%s got %s", *c.opts, c.wantHost, c.wantPath, link)
+		}
+		q := u.Query()
+		sig := q.Get("Signature")
+		q.Del("Signature")
+		if want := ht.sign("GET\n" + c.wantHost + "\n" + c.wantPath + "\n" + q.Encode()); sig != want {
+			t.Errorf("%+v: signature not match the rewritten host", *c.opts)
+		}
+	}
+}
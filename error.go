@@ -0,0 +1,320 @@
+package cex
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 统一的错误分类, 方便调用方不依赖错误文本做判断
+type ErrorCategory string
+
+const (
+	CategoryUnknown           ErrorCategory = ""
+	CategoryInsufficientFunds ErrorCategory = "InsufficientFunds"
+	CategoryOrderNotFound     ErrorCategory = "OrderNotFound"
+	CategoryRateLimited       ErrorCategory = "RateLimited"
+	CategoryInvalidSymbol     ErrorCategory = "InvalidSymbol"
+	CategoryAuthFailed        ErrorCategory = "AuthFailed"
+	CategoryTimeout           ErrorCategory = "Timeout"
+	CategoryNetwork           ErrorCategory = "Network"
+	CategoryNotSupported      ErrorCategory = "NotSupported"
+)
+
+// 所有交易所接口返回的错误都可以用 errors.As(err, &cexErr) 取出
+// 也可以用 errors.Is(err, cex.ErrOrderNotFound) 按分类判断
+type Error struct {
+	Exchange   string // binance
+	HttpStatus int    // 0 表示未知
+	Code       string // 交易所原生错误码, 如 -2010, 51008, BALANCE_NOT_ENOUGH
+	Msg        string // 交易所原生错误信息
+	Category   ErrorCategory
+
+	err error // 底层错误(网络错误等)
+}
+
+// 用于 errors.Is 的分类哨兵
+var (
+	ErrInsufficientFunds = &Error{Category: CategoryInsufficientFunds}
+	ErrOrderNotFound     = &Error{Category: CategoryOrderNotFound}
+	ErrRateLimited       = &Error{Category: CategoryRateLimited}
+	ErrInvalidSymbol     = &Error{Category: CategoryInvalidSymbol}
+	ErrAuthFailed        = &Error{Category: CategoryAuthFailed}
+	ErrTimeout           = &Error{Category: CategoryTimeout}
+	ErrNetwork           = &Error{Category: CategoryNetwork}
+	ErrNotSupported      = &Error{Category: CategoryNotSupported}
+)
+
+func (e *Error) Error() string {
+	if e.err != nil {
+		if e.Exchange == "" {
+			return e.Msg + e.err.Error()
+		}
+		return e.Exchange + " " + e.Msg + e.err.Error()
+	}
+	if e.Code == "" && e.Exchange == "" {
+		return e.Msg
+	}
+	s := e.Exchange + " api err!"
+	if e.Code != "" {
+		s += " code=" + e.Code
+	}
+	if e.HttpStatus != 0 && e.HttpStatus != http.StatusOK {
+		s += " http=" + strconv.Itoa(e.HttpStatus)
+	}
+	return s + " msg=" + e.Msg
+}
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// 哨兵只比较非空字段: Category, Exchange, Code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Category != CategoryUnknown && t.Category != e.Category {
+		return false
+	}
+	if t.Exchange != "" && t.Exchange != e.Exchange {
+		return false
+	}
+	if t.Code != "" && t.Code != e.Code {
+		return false
+	}
+	return t.Category != CategoryUnknown || t.Exchange != "" || t.Code != ""
+}
+
+// 返回err的分类, 非*Error返回CategoryUnknown
+func ErrorCategoryOf(err error) ErrorCategory {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Category
+	}
+	return CategoryUnknown
+}
+
+func newApiError(exchange string, httpStatus int, code, msg string) *Error {
+	return &Error{
+		Exchange:   exchange,
+		HttpStatus: httpStatus,
+		Code:       code,
+		Msg:        msg,
+		Category:   classifyError(exchange, httpStatus, code, msg),
+	}
+}
+
+// 包装Http返回的网络错误, 保留超时/网络分类
+func newNetError(exchange string, err error) *Error {
+	category := CategoryNetwork
+	var e *Error
+	if errors.As(err, &e) && e.Category != CategoryUnknown {
+		category = e.Category
+	}
+	return &Error{
+		Exchange: exchange,
+		Msg:      "net error! ",
+		Category: category,
+		err:      err,
+	}
+}
+func newNotSupportError(exchange string) *Error {
+	return &Error{Exchange: exchange, Msg: "not support", Category: CategoryNotSupported}
+}
+
+// 各交易所原生错误码 -> 分类
+var exchangeErrorCodes = map[string]map[string]ErrorCategory{
+	"binance": {
+		"-1003": CategoryRateLimited, // TOO_MANY_REQUESTS
+		"-1015": CategoryRateLimited, // TOO_MANY_ORDERS
+		"-1121": CategoryInvalidSymbol,
+		"-1022": CategoryAuthFailed, // INVALID_SIGNATURE
+		"-2008": CategoryAuthFailed, // BAD_API_ID
+		"-2014": CategoryAuthFailed, // BAD_API_KEY_FMT
+		"-2015": CategoryAuthFailed, // REJECTED_MBX_KEY
+		"-2011": CategoryOrderNotFound,
+		"-2013": CategoryOrderNotFound,
+		"-2018": CategoryInsufficientFunds,
+		"-2019": CategoryInsufficientFunds,
+		"-3041": CategoryInsufficientFunds,
+		"-4131": CategoryInsufficientFunds,
+	},
+	"okx": {
+		"50011": CategoryRateLimited,
+		"50061": CategoryRateLimited,
+		"50101": CategoryAuthFailed,
+		"50102": CategoryAuthFailed,
+		"50103": CategoryAuthFailed,
+		"50104": CategoryAuthFailed,
+		"50105": CategoryAuthFailed,
+		"50111": CategoryAuthFailed,
+		"50113": CategoryAuthFailed,
+		"51001": CategoryInvalidSymbol,
+		"51008": CategoryInsufficientFunds,
+		"51131": CategoryInsufficientFunds,
+		"58350": CategoryInsufficientFunds,
+		"51400": CategoryOrderNotFound,
+		"51603": CategoryOrderNotFound,
+	},
+	"gate": {
+		"BALANCE_NOT_ENOUGH":      CategoryInsufficientFunds,
+		"INSUFFICIENT_AVAILABLE":  CategoryInsufficientFunds,
+		"MARGIN_NOT_ENOUGH":       CategoryInsufficientFunds,
+		"ORDER_NOT_FOUND":         CategoryOrderNotFound,
+		"INVALID_CURRENCY_PAIR":   CategoryInvalidSymbol,
+		"INVALID_CURRENCY":        CategoryInvalidSymbol,
+		"CONTRACT_NOT_FOUND":      CategoryInvalidSymbol,
+		"TOO_MANY_REQUESTS":       CategoryRateLimited,
+		"INVALID_KEY":             CategoryAuthFailed,
+		"INVALID_SIGNATURE":       CategoryAuthFailed,
+		"MISSING_REQUIRED_HEADER": CategoryAuthFailed,
+		"REQUEST_EXPIRED":         CategoryAuthFailed,
+		"FORBIDDEN":               CategoryAuthFailed,
+	},
+	"bybit": {
+		"10003":  CategoryAuthFailed,
+		"10004":  CategoryAuthFailed,
+		"10005":  CategoryAuthFailed,
+		"10006":  CategoryRateLimited,
+		"10018":  CategoryRateLimited,
+		"110001": CategoryOrderNotFound,
+		"170213": CategoryOrderNotFound,
+		"110004": CategoryInsufficientFunds,
+		"110007": CategoryInsufficientFunds,
+		"170131": CategoryInsufficientFunds,
+		"131212": CategoryInsufficientFunds,
+		"170121": CategoryInvalidSymbol,
+	},
+	"kraken": {
+		"EOrder:Insufficient funds":   CategoryInsufficientFunds,
+		"EFunding:Insufficient funds": CategoryInsufficientFunds,
+		"EOrder:Unknown order":        CategoryOrderNotFound,
+		"EQuery:Unknown asset pair":   CategoryInvalidSymbol,
+		"EAPI:Rate limit exceeded":    CategoryRateLimited,
+		"EOrder:Rate limit exceeded":  CategoryRateLimited,
+		"EService:Throttled":          CategoryRateLimited,
+		"EAPI:Invalid key":            CategoryAuthFailed,
+		"EAPI:Invalid signature":      CategoryAuthFailed,
+		"EAPI:Invalid nonce":          CategoryAuthFailed,
+		"EGeneral:Permission denied":  CategoryAuthFailed,
+		"EService:Unavailable":        CategoryNetwork,
+		"EGeneral:Temporary lockout":  CategoryRateLimited,
+	},
+	"bigone": {
+		"10013": CategoryOrderNotFound, // resource not found
+		"10014": CategoryInsufficientFunds,
+		"40603": CategoryInsufficientFunds, // insufficient resource
+		"10429": CategoryRateLimited,
+		"40004": CategoryAuthFailed, // unauthorized
+		"40103": CategoryAuthFailed,
+		"40104": CategoryAuthFailed,
+		"10403": CategoryAuthFailed, // permission denied
+		"40301": CategoryAuthFailed,
+	},
+	"kucoin": {
+		"200004": CategoryInsufficientFunds,
+		"400001": CategoryAuthFailed,
+		"400002": CategoryAuthFailed, // KC-API-TIMESTAMP Invalid
+		"400003": CategoryAuthFailed, // KC-API-KEY not exists
+		"400004": CategoryAuthFailed, // KC-API-PASSPHRASE error
+		"400005": CategoryAuthFailed, // Signature error
+		"400006": CategoryAuthFailed, // IP白名单
+		"400007": CategoryAuthFailed, // Access Denied
+		"429000": CategoryRateLimited,
+		"900001": CategoryInvalidSymbol,
+	},
+	// ktx 没有公开错误码表, 按HTTP状态和错误信息关键字分类
+	"mexc": {
+		"429":    CategoryRateLimited,
+		"510":    CategoryRateLimited,
+		"-2011":  CategoryOrderNotFound,
+		"-2013":  CategoryOrderNotFound,
+		"10101":  CategoryInsufficientFunds,
+		"30004":  CategoryInsufficientFunds,
+		"30005":  CategoryInsufficientFunds,
+		"10007":  CategoryInvalidSymbol,
+		"30014":  CategoryInvalidSymbol,
+		"602":    CategoryAuthFailed,
+		"10072":  CategoryAuthFailed,
+		"700002": CategoryAuthFailed,
+		"700003": CategoryAuthFailed,
+		"700006": CategoryAuthFailed,
+	},
+	"bitget": {
+		"429":   CategoryRateLimited,
+		"43012": CategoryInsufficientFunds,
+		"40762": CategoryInsufficientFunds,
+		"43001": CategoryOrderNotFound,
+		"43025": CategoryOrderNotFound,
+		"40309": CategoryInvalidSymbol,
+		"40006": CategoryAuthFailed,
+		"40009": CategoryAuthFailed,
+		"40012": CategoryAuthFailed,
+		"40037": CategoryAuthFailed,
+	},
+	"htx": {
+		"account-frozen-balance-insufficient-error": CategoryInsufficientFunds,
+		"insufficient-balance":                      CategoryInsufficientFunds,
+		"order-accountbalance-error":                CategoryInsufficientFunds,
+		"base-record-invalid":                       CategoryOrderNotFound,
+		"order-queryorder-invalid":                  CategoryOrderNotFound,
+		"base-symbol-error":                         CategoryInvalidSymbol,
+		"api-signature-not-valid":                   CategoryAuthFailed,
+		"api-signature-check-failed":                CategoryAuthFailed,
+		"too-many-requests":                         CategoryRateLimited,
+		"1032":                                      CategoryRateLimited, // swap
+		"1047":                                      CategoryInsufficientFunds,
+		"1048":                                      CategoryInsufficientFunds,
+		"1061":                                      CategoryOrderNotFound,
+		"1014":                                      CategoryInvalidSymbol,
+	},
+}
+
+// 原生错误码找不到时根据错误信息关键字兜底
+var errorMsgKeywords = []struct {
+	keyword  string
+	category ErrorCategory
+}{
+	{"insufficient", CategoryInsufficientFunds},
+	{"not enough", CategoryInsufficientFunds},
+	{"unknown order", CategoryOrderNotFound},
+	{"order does not exist", CategoryOrderNotFound},
+	{"order not exist", CategoryOrderNotFound},
+	{"order not found", CategoryOrderNotFound},
+	{"too many", CategoryRateLimited},
+	{"rate limit", CategoryRateLimited},
+	{"invalid symbol", CategoryInvalidSymbol},
+	{"unknown asset pair", CategoryInvalidSymbol},
+	{"symbol not exist", CategoryInvalidSymbol},
+	{"invalid api", CategoryAuthFailed},
+	{"api-key", CategoryAuthFailed},
+	{"signature", CategoryAuthFailed},
+	{"unauthorized", CategoryAuthFailed},
+	{"permission", CategoryAuthFailed},
+}
+
+func classifyError(exchange string, httpStatus int, code, msg string) ErrorCategory {
+	if codes := exchangeErrorCodes[exchange]; codes != nil {
+		if c := codes[code]; c != CategoryUnknown {
+			return c
+		}
+		if c := codes[msg]; c != CategoryUnknown {
+			return c
+		}
+	}
+	if httpStatus == http.StatusTooManyRequests || httpStatus == 418 { // binance 418 为封禁IP
+		return CategoryRateLimited
+	}
+	if httpStatus == http.StatusUnauthorized {
+		return CategoryAuthFailed
+	}
+	lmsg := strings.ToLower(msg)
+	for _, kw := range errorMsgKeywords {
+		if strings.Contains(lmsg, kw.keyword) {
+			return kw.category
+		}
+	}
+	return CategoryUnknown
+}
@@ -0,0 +1,105 @@
+package cex
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		exchange   string
+		httpStatus int
+		code       string
+		msg        string
+		want       ErrorCategory
+	}{
+		{"binance", 400, "-2010", "Account has insufficient balance", CategoryInsufficientFunds},
+		{"binance", 400, "-2013", "Order does not exist.", CategoryOrderNotFound},
+		{"okx", 200, "51008", "", CategoryInsufficientFunds},
+		{"gate", 400, "ORDER_NOT_FOUND", "", CategoryOrderNotFound},
+		{"bigone", 200, "10014", "", CategoryInsufficientFunds},
+		{"kucoin", 200, "200004", "", CategoryInsufficientFunds},
+		{"kucoin", 401, "400005", "", CategoryAuthFailed},
+		{"mexc", 400, "-2013", "", CategoryOrderNotFound},
+		{"mexc", 400, "30004", "", CategoryInsufficientFunds},
+		{"bitget", 400, "43012", "", CategoryInsufficientFunds},
+		{"bitget", 400, "43001", "", CategoryOrderNotFound},
+		{"htx", 200, "account-frozen-balance-insufficient-error", "", CategoryInsufficientFunds},
+		{"htx", 200, "1061", "", CategoryOrderNotFound},
+		{"ktx", 429, "1", "", CategoryRateLimited},
+		{"ktx", 200, "1", "order not found", CategoryOrderNotFound},
+		{"bybit", 200, "0", "xxx", CategoryUnknown},
+	}
+	for _, c := range cases {
+		if got := classifyError(c.exchange, c.httpStatus, c.code, c.msg); got != c.want {
+			t.Errorf("%s code=%s msg=%q: want %q got %q", c.exchange, c.code, c.msg, c.want, got)
+		}
+	}
+}
+func TestApiErrorHttpStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"code":-1099,"msg":"slow down"}`))
+	}))
+	defer srv.Close()
+	defer clearRatePause("binance") // 429会暂停限频器, 不要影响其它用例
+
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ex.SpotGetOpenOrders("BTCUSDT")
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("want *Error, got %v", err)
+	}
+	if e.HttpStatus != http.StatusTooManyRequests {
+		t.Fatalf("want http status 429, got %d", e.HttpStatus)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited, got %v", err)
+	}
+}
+func TestApiErrorHtmlBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`<html><body>401 Authorization Required</body></html>`))
+	}))
+	defer srv.Close()
+
+	for _, name := range []string{"binance", "gate", "mexc"} {
+		ex, err := New(name, "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ex.SpotGetOpenOrders("BTCUSDT")
+		if !errors.Is(err, ErrAuthFailed) {
+			t.Errorf("%s: want ErrAuthFailed, got %v", name, err)
+		}
+		if !errors.Is(err, &Error{Exchange: name}) {
+			t.Errorf("%s: want exchange in error, got %v", name, err)
+		}
+	}
+}
+func TestNotSupportError(t *testing.T) {
+	ex, err := New("bigone", "test", "k", "s", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ex.FuturesGetAllPositions("UM")
+	if !errors.Is(err, ErrNotSupported) || !errors.Is(err, &Error{Exchange: "bigone"}) {
+		t.Fatalf("want bigone ErrNotSupported, got %v", err)
+	}
+	if err == error(ErrNotSupported) {
+		t.Fatal("should not return the shared sentinel")
+	}
+}
+func clearRatePause(cexName string) {
+	rl := getRateLimiter(cexName)
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	rl.pausedUntil = time.Time{}
+}
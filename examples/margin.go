@@ -102,7 +102,7 @@ func main() {
 	passphrase := os.Getenv("PASSPHRASE")
 	ilog.Rinfo("spot api:ws test. cex = %s", cexName)
 	// ok,gate,bybit,binance
-	cexObj, _ := cex.New(cexName, "", apiKey, secretKey, passphrase)
+	cexObj, _ := cex.New(cexName, "", apiKey, secretKey, passphrase, "")
 	testRest(cexObj)
 
 	time.Sleep(1 * time.Second)
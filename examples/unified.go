@@ -25,7 +25,7 @@ func main() {
 	passphrase := os.Getenv("PASSPHRASE")
 	ilog.Rinfo("cex=%s", cexName)
 	// ok,gate,bybit,binance
-	cexObj, _ := cex.New(cexName, "", apiKey, secretKey, passphrase)
+	cexObj, _ := cex.New(cexName, "", apiKey, secretKey, passphrase, "")
 	asL, _ := cexObj.UnifiedGetAssets()
 	for _, v := range asL {
 		ilog.Rinfo("asset %s %s", v.Symbol, v.Avail.String())
@@ -1,13 +1,14 @@
 package cex
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ type Gate struct {
 	spotWsPublicClosedMtx          sync.RWMutex
 	spotWsPublicTickerInnerPool    *sync.Pool
 	spotWsPublicOrderBookInnerPool *sync.Pool
+	spotWsOrderBooks               localOrderBooks
 
 	spotWsPrivateConn      *websocket.Conn
 	spotWsPrivateConnMtx   sync.Mutex
@@ -44,11 +46,15 @@ type Gate struct {
 	wsContractPubChannelClosedMtx sync.RWMutex
 	wsContractPubTickerPool       *sync.Pool
 	wsContractPubOrderBookPool    *sync.Pool
+	wsContractPubTyp              string
+	wsContractOrderBooks          localOrderBooks
 
 	wsContractPrivCon              *websocket.Conn
 	wsContractPrivConMtx           sync.Mutex
 	wsContractPrivChannelClosed    bool
 	wsContractPrivChannelClosedMtx sync.RWMutex
+	wsContractPrivTyp              string
+	wsContractUserId               string
 
 	wsUnifiedCon              *websocket.Conn
 	wsUnifiedConMtx           sync.Mutex
@@ -74,6 +80,7 @@ var (
 	gtSpotSymbolMapMtx sync.RWMutex
 
 	gtContractSymbolMap    map[string]string
+	gtContractValMap       map[string]decimal.Decimal // quanto_multiplier
 	gtContractSymbolMapMtx sync.RWMutex
 )
 
@@ -83,6 +90,7 @@ const gtApiDeadline = 1500 * time.Millisecond
 func init() {
 	gtSpotSymbolMap = make(map[string]string)
 	gtContractSymbolMap = make(map[string]string)
+	gtContractValMap = make(map[string]decimal.Decimal)
 }
 func NewGate(account, apikey, secretkey, localIP string) (*Gate, error) {
 	client, err := NewClientWithLocalIP(localIP)
@@ -91,12 +99,14 @@ func NewGate(account, apikey, secretkey, localIP string) (*Gate, error) {
 	}
 	cexObj := &Gate{
 		Http: Http{
-			client: client,
+			client:  client,
+			limiter: getRateLimiter("gate"),
 		},
-		name:      "gate",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
+		Unsupported: Unsupported{name: "gate"},
+		name:        "gate",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
 	}
 	return cexObj, nil
 }
@@ -112,6 +122,18 @@ func (gt *Gate) ApiKey() string {
 func (gt *Gate) Debug(v bool) {
 	gt.debug = v
 }
+func (gt *Gate) withContext(ctx context.Context) Exchanger {
+	cexObj := &Gate{
+		Http:      gt.Http.withContext(ctx),
+		name:      gt.name,
+		account:   gt.account,
+		apikey:    gt.apikey,
+		secretkey: gt.secretkey,
+		debug:     gt.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (gt *Gate) Init() error {
 	gt.spotWsPublicClosed = true
 	gt.spotWsPrivateClosed = true
@@ -160,15 +182,41 @@ func (gt *Gate) getContractSymbol(symbol string) string {
 	defer gtContractSymbolMapMtx.RUnlock()
 	return gtContractSymbolMap[symbol]
 }
-func (gt *Gate) handleExceptionResp(api string, resp []byte) error {
+func (gt *Gate) getContractVal(symbol string) decimal.Decimal {
+	gtContractSymbolMapMtx.RLock()
+	defer gtContractSymbolMapMtx.RUnlock()
+	return gtContractValMap[symbol]
+}
+
+// UM:usdt结算(BTC_USDT) CM:btc结算(BTC_USD)
+func (gt *Gate) futuresSettle(typ string) string {
+	if typ == "CM" {
+		return "btc"
+	}
+	return "usdt"
+}
+
+// BTC_USDT -> BTCUSDT,UM  BTC_USD -> BTCUSD,CM
+func (gt *Gate) toStdContractSymbol(contract string) (string, string) {
+	typ := "UM"
+	if strings.HasSuffix(contract, "_USD") {
+		typ = "CM"
+	}
+	return strings.ReplaceAll(contract, "_", ""), typ
+}
+func (gt *Gate) handleExceptionResp(httpStatus int, api string, resp []byte) error {
 	ret := struct {
 		Label string `json:"label,omitempty"`
 		Msg   string `json:"message,omitempty"`
 	}{}
 	if err := json.Unmarshal(resp, &ret); err != nil {
-		return errors.New(gt.Name() + " " + api + " " + err.Error() + " " + string(resp))
+		// 非json, 如WAF/网关返回的html页面, 按http状态码分类
+		return newApiError(gt.Name(), httpStatus, "", api+" "+string(resp))
 	}
-	return errors.New(ret.Msg)
+	return gt.apiError(httpStatus, ret.Label, ret.Msg)
+}
+func (gt *Gate) apiError(httpStatus int, label, msg string) error {
+	return newApiError(gt.Name(), httpStatus, label, msg)
 }
 func (gt *Gate) sign(method, path, params, body, ts string) string {
 	h := sha512.New()
@@ -233,6 +281,8 @@ func (gt *Gate) toStdTimeInForce(timeInForce string) string {
 		return "IOC"
 	} else if timeInForce == "fok" {
 		return "FOK"
+	} else if timeInForce == "poc" {
+		return "GTX"
 	}
 	return ""
 }
@@ -243,9 +293,29 @@ func (gt *Gate) fromStdTimeInForce(timeInForce string) string {
 		return "ioc"
 	} else if timeInForce == "FOK" {
 		return "fok"
+	} else if timeInForce == "GTX" {
+		return "poc"
+	}
+	return ""
+}
+func (gt *Gate) fromStdTriggerBy(triggerBy string) string {
+	if triggerBy == "MARKET_PRICE" || triggerBy == "" {
+		return "0"
+	} else if triggerBy == "MARK_PRICE" {
+		return "1"
+	} else if triggerBy == "INDEX_PRICE" {
+		return "2"
 	}
 	return ""
 }
+func (gt *Gate) toStdTriggerBy(priceType int) string {
+	if priceType == 1 {
+		return "MARK_PRICE"
+	} else if priceType == 2 {
+		return "INDEX_PRICE"
+	}
+	return "MARKET_PRICE"
+}
 func (gt *Gate) toStdOrderStatus(status string) string {
 	if status == "open" {
 		return "NEW"
@@ -270,3 +340,13 @@ func (gt *Gate) toStdWithdrawStatus(v string) string {
 	}
 	return ""
 }
+
+// gate 充值记录不返回确认数
+func (gt *Gate) toStdDepositStatus(v string) string {
+	if v == "DONE" {
+		return "CREDITED"
+	} else if v == "CANCEL" || v == "FAIL" || v == "INVALID" || v == "BLOCKED" {
+		return "FAILED"
+	}
+	return "PENDING"
+}
@@ -0,0 +1,1010 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/shopspring/decimal"
+)
+
+// gate 永续合约 UM:/futures/usdt(BTC_USDT) CM:/futures/btc(BTC_USD)
+// 下单数量size为张数(整数), UM中 qty=size*quanto_multiplier, CM中 qty就是张数(1张=1USD)
+func (gt *Gate) FuturesSupported(typ string) bool {
+	if typ == "UM" || typ == "CM" {
+		return true
+	}
+	return false
+}
+func (gt *Gate) FuturesServerTime(typ string) (int64, error) {
+	return gt.SpotServerTime()
+}
+
+// 需要先调用FuturesLoadAllPairRule, 取不到quanto_multiplier时返回0
+func (gt *Gate) FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal {
+	if typ == "CM" {
+		return size
+	}
+	return size.Mul(gt.getContractVal(symbol))
+}
+func (gt *Gate) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal) decimal.Decimal {
+	if typ == "CM" {
+		return qty
+	}
+	ctVal := gt.getContractVal(symbol)
+	if ctVal.IsZero() {
+		return decimal.Zero
+	}
+	return qty.Div(ctVal)
+}
+func (gt *Gate) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/contracts"
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] == '{' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesLoadAllPairRule", resp)
+	}
+	recv := []struct {
+		Name         string          `json:"name"`
+		Type         string          `json:"type"` // direct/inverse
+		Multiplier   decimal.Decimal `json:"quanto_multiplier"`
+		PriceTick    decimal.Decimal `json:"order_price_round"`
+		MinOrderSize int64           `json:"order_size_min"`
+		MaxOrderSize int64           `json:"order_size_max"`
+		Delisting    bool            `json:"in_delisting"`
+	}{}
+	err = json.Unmarshal(resp, &recv)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make(map[string]*FuturesExchangePairRule)
+	now := time.Now().Unix()
+	tgtContractSymbolMap := make(map[string]string)
+	tgtContractValMap := make(map[string]decimal.Decimal)
+	for _, pair := range recv {
+		if pair.Delisting {
+			continue
+		}
+		base, quote, found := strings.Cut(pair.Name, "_")
+		if !found {
+			continue
+		}
+		ep := &FuturesExchangePairRule{
+			Typ:           typ,
+			Symbol:        base + quote,
+			Base:          base,
+			Quote:         quote,
+			MinPrice:      pair.PriceTick,
+			MaxPrice:      decimal.NewFromFloat(999999999.99),
+			PriceTickSize: pair.PriceTick,
+			QtyStep:       decimal.NewFromInt(1),
+			MinOrderQty:   decimal.NewFromInt(pair.MinOrderSize),
+			MaxOrderQty:   decimal.NewFromInt(pair.MaxOrderSize),
+			Time:          now,
+		}
+		if typ == "CM" { // 反向合约 1张=1USD
+			ep.ContractSize = decimal.NewFromInt(1)
+		} else { // 张数换算成标的数量
+			if !pair.Multiplier.IsPositive() {
+				continue
+			}
+			ep.ContractMultiplier = pair.Multiplier
+			ep.QtyStep = pair.Multiplier
+			ep.MinOrderQty = ep.MinOrderQty.Mul(pair.Multiplier)
+			ep.MaxOrderQty = ep.MaxOrderQty.Mul(pair.Multiplier)
+		}
+		all[ep.Symbol] = ep
+		tgtContractSymbolMap[ep.Symbol] = pair.Name
+		tgtContractValMap[ep.Symbol] = pair.Multiplier
+	}
+
+	gtContractSymbolMapMtx.Lock() // UM/CM 共用, 合并
+	for k, v := range tgtContractSymbolMap {
+		gtContractSymbolMap[k] = v
+		gtContractValMap[k] = tgtContractValMap[k]
+	}
+	gtContractSymbolMapMtx.Unlock()
+	return all, nil
+}
+func (gt *Gate) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/tickers"
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] == '{' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetAll24hTicker", resp)
+	}
+
+	tickers := []GateContract24hTicker{}
+	if err = json.Unmarshal(resp, &tickers); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	if len(tickers) == 0 {
+		return nil, errors.New(gt.Name() + " resp empty")
+	}
+	allTk := make(map[string]Pub24hTicker, len(tickers))
+	for _, tk := range tickers {
+		sym, t := gt.toStdContractSymbol(tk.Symbol)
+		if t != typ {
+			continue
+		}
+		v := Pub24hTicker{
+			Symbol:    sym,
+			LastPrice: tk.Last,
+			Volume:    tk.Volume,
+		}
+		if typ == "CM" {
+			v.BaseVolume = tk.Volume
+		} else {
+			v.QuoteVolume = tk.QuoteVolume
+		}
+		allTk[v.Symbol] = v
+	}
+	return allTk, nil
+}
+func (gt *Gate) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/order_book"
+	params := "limit=1&contract=" + gt.getContractSymbol(symbol)
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string                      `json:"label"`
+		Msg   string                      `json:"message"`
+		Time  decimal.Decimal             `json:"current"` // sec
+		Asks  []GateContractOrderBookTick `json:"asks"`
+		Bids  []GateContractOrderBookTick `json:"bids"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return BestBidAsk{}, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Label != "" {
+		return BestBidAsk{}, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	if len(ret.Asks) == 0 || len(ret.Bids) == 0 {
+		return BestBidAsk{}, errors.New(gt.Name() + " resp is empty!")
+	}
+	return BestBidAsk{
+		Symbol:   symbol,
+		Time:     ret.Time.Shift(3).IntPart(),
+		BidPrice: ret.Bids[0].Price,
+		BidQty:   gt.FuturesSizeToQty(typ, symbol, ret.Bids[0].Size),
+		AskPrice: ret.Asks[0].Price,
+		AskQty:   gt.FuturesSizeToQty(typ, symbol, ret.Asks[0].Size),
+	}, nil
+}
+func (gt *Gate) FuturesGetAllFundingRate(typ string) (map[string]FundingRate, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/contracts"
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] == '{' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetAllFundingRate", resp)
+	}
+	frs := []GateFundingRate{}
+	if err = json.Unmarshal(resp, &frs); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make(map[string]FundingRate, len(frs))
+	now := time.Now().Unix()
+	for _, fr := range frs {
+		if fr.Offline {
+			continue
+		}
+		sym, t := gt.toStdContractSymbol(fr.Name)
+		if t != typ {
+			continue
+		}
+		all[sym] = FundingRate{
+			Symbol:   sym,
+			Val:      fr.Fr,
+			NextTime: fr.NextTime,
+			UTime:    now,
+		}
+	}
+	return all, nil
+}
+
+// startTime/endTime msec, 一次最多1000条
+func (gt *Gate) FuturesGetFundingRateHistory(typ, symbol string,
+	startTime, endTime int64) ([]FundingRateHistory, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/funding_rate"
+	params := "limit=1000&contract=" + gt.getContractSymbol(symbol)
+	if startTime > 0 {
+		params += "&from=" + strconv.FormatInt(startTime/1000, 10)
+	}
+	if endTime > 0 {
+		params += "&to=" + strconv.FormatInt(endTime/1000, 10)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] == '{' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetFundingRateHistory", resp)
+	}
+	ret := []struct {
+		Time        int64           `json:"t"` // sec
+		FundingRate decimal.Decimal `json:"r"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	frh := make([]FundingRateHistory, 0, len(ret))
+	for i := len(ret) - 1; i >= 0; i-- { // gate 是倒序
+		frh = append(frh, FundingRateHistory{
+			FundingRate: ret[i].FundingRate,
+			Time:        ret[i].Time * 1000,
+		})
+	}
+	return frh, nil
+}
+func (gt *Gate) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRateMarkPrice, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/contracts/" + gt.getContractSymbol(symbol)
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return FundingRateMarkPrice{}, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label       string          `json:"label"`
+		Msg         string          `json:"message"`
+		MarkPrice   decimal.Decimal `json:"mark_price"`
+		FundingRate decimal.Decimal `json:"funding_rate"`
+		NextTime    int64           `json:"funding_next_apply"` // sec
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return FundingRateMarkPrice{}, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Label != "" {
+		return FundingRateMarkPrice{}, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return FundingRateMarkPrice{
+		MarkPrice:   ret.MarkPrice,
+		FundingRate: ret.FundingRate,
+		NextTime:    ret.NextTime * 1000,
+	}, nil
+}
+func (gt *Gate) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/accounts"
+	url := gtUniEndpoint + path
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label     string          `json:"label"`
+		Msg       string          `json:"message"`
+		Symbol    string          `json:"currency"`
+		Total     decimal.Decimal `json:"total"`
+		Available decimal.Decimal `json:"available"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Label != "" {
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	assetsMap := make(map[string]*FuturesAsset)
+	if ret.Total.IsZero() && ret.Available.IsZero() {
+		return assetsMap, nil
+	}
+	symbol := strings.ToUpper(ret.Symbol)
+	assetsMap[symbol] = &FuturesAsset{
+		Symbol:            symbol,
+		Total:             ret.Total,
+		Avail:             ret.Available,
+		MaxWithdrawAmount: ret.Available,
+	}
+	return assetsMap, nil
+}
+
+// 参数同SpotGetKLine, Volume由张数换算成标的数量(CM为张数)
+func (gt *Gate) FuturesGetKLine(typ, symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	switch interval {
+	case "1m", "5m", "15m", "30m", "1h", "4h", "1d":
+	default:
+		return nil, errors.New(gt.Name() + " not support interval " + interval)
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/candlesticks"
+	params := "contract=" + gt.getContractSymbol(symbol) + "&interval=" + interval
+	if startTime > 0 {
+		params += "&from=" + strconv.FormatInt(startTime, 10)
+		if end := klineEndTime(interval, startTime, endTime, limit); end > 0 {
+			params += "&to=" + strconv.FormatInt(end-1, 10)
+		}
+	} else if endTime > 0 {
+		params += "&from=" + strconv.FormatInt(endTime-limit*klineIntervalSeconds(interval), 10) +
+			"&to=" + strconv.FormatInt(endTime-1, 10)
+	} else {
+		params += "&limit=" + strconv.FormatInt(limit, 10)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetKLine", resp)
+	}
+	klines := []struct {
+		OpenTime    int64           `json:"t"` // sec
+		Volume      decimal.Decimal `json:"v"` // 张数
+		Close       decimal.Decimal `json:"c"`
+		High        decimal.Decimal `json:"h"`
+		Low         decimal.Decimal `json:"l"`
+		Open        decimal.Decimal `json:"o"`
+		QuoteVolume decimal.Decimal `json:"sum"`
+	}{}
+	if err = json.Unmarshal(resp, &klines); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make([]KLine, 0, len(klines))
+	for _, v := range klines {
+		all = append(all, KLine{
+			OpenTime:    v.OpenTime,
+			OpenPrice:   v.Open,
+			HighPrice:   v.High,
+			LowPrice:    v.Low,
+			ClosePrice:  v.Close,
+			Volume:      gt.FuturesSizeToQty(typ, symbol, v.Volume),
+			QuoteVolume: v.QuoteVolume,
+		})
+	}
+	return all, nil
+}
+func (gt *Gate) futuresGetPositions(typ string) ([]*FuturesPosition, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/positions"
+	params := "holding=true"
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetAllPositions", resp)
+	}
+	ret := []struct {
+		Symbol        string          `json:"contract"`
+		Mode          string          `json:"mode"` // single/dual_long/dual_short
+		Size          decimal.Decimal `json:"size"` // 张数, 单仓模式下符号代表多空方向
+		EntryPrice    decimal.Decimal `json:"entry_price"`
+		LiqPrice      decimal.Decimal `json:"liq_price"`
+		Leverage      decimal.Decimal `json:"leverage"` // 0为全仓
+		CrossLeverage decimal.Decimal `json:"cross_leverage_limit"`
+		UnrealisedPnl decimal.Decimal `json:"unrealised_pnl"`
+		UTime         int64           `json:"update_time"` // sec
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	all := make([]*FuturesPosition, 0, len(ret))
+	for _, v := range ret {
+		sym, _ := gt.toStdContractSymbol(v.Symbol)
+		cp := &FuturesPosition{
+			Symbol:           sym,
+			Side:             "SELL",
+			PositionQty:      gt.FuturesSizeToQty(typ, sym, v.Size.Abs()),
+			EntryPrice:       v.EntryPrice,
+			LiqPrice:         v.LiqPrice,
+			Leverage:         v.Leverage,
+			UnRealizedProfit: v.UnrealisedPnl,
+			UTime:            v.UTime * 1000,
+		}
+		if v.Mode == "dual_long" {
+			cp.Mode = 1
+			cp.Side = "BUY"
+		} else if v.Mode == "dual_short" {
+			cp.Mode = 1
+		} else if v.Size.IsPositive() {
+			cp.Side = "BUY"
+		}
+		if cp.Leverage.IsZero() {
+			cp.Leverage = v.CrossLeverage
+		}
+		all = append(all, cp)
+	}
+	return all, nil
+}
+func (gt *Gate) FuturesGetAllPositions(typ string) (map[string]*FuturesPositions, error) {
+	all, err := gt.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPositions)
+	for _, v := range all {
+		fp := positionM[v.Symbol]
+		if fp == nil {
+			fp = &FuturesPositions{}
+			positionM[v.Symbol] = fp
+		}
+		fp.Val(v)
+	}
+	return positionM, nil
+}
+func (gt *Gate) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosition, error) {
+	all, err := gt.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPosition)
+	for _, v := range all {
+		if v.Mode != 0 {
+			continue // 只支持单仓模式
+		}
+		positionM[v.Symbol] = v
+	}
+	return positionM, nil
+}
+
+// size带符号表示方向, 双仓模式下平仓需要reduce_only
+func (gt *Gate) futuresOrderPayload(typ, symbol, clientId string,
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	reduceOnly int) string {
+	size := gt.FuturesQtyToSize(typ, symbol, qty).IntPart()
+	if side == "SELL" {
+		size = -size
+	}
+	payload := `{"contract":"` + gt.getContractSymbol(symbol) + `"` +
+		`,"size":` + strconv.FormatInt(size, 10)
+	if clientId != "" {
+		payload += `,"text":"t-` + clientId + `"` // len(clientId) LessOrEqual than 28
+	}
+	if orderType == "MARKET" {
+		payload += `,"price":"0","tif":"ioc"`
+	} else {
+		payload += `,"price":"` + price.String() + `"`
+		if tif := gt.fromStdTimeInForce(timeInForce); tif != "" {
+			payload += `,"tif":"` + tif + `"`
+		}
+	}
+	if reduceOnly == 1 ||
+		(positionMode == "LONG" && side == "SELL") ||
+		(positionMode == "SHORT" && side == "BUY") {
+		payload += `,"reduce_only":true`
+	}
+	payload += `}`
+	return payload
+}
+
+// 全仓/逐仓由FuturesSwitchTradeMode设置, 下单时忽略tradeMode
+// timeInForce=GTX 为只做maker
+func (gt *Gate) FuturesPlaceOrder(typ, symbol, clientId string, /*BTCUSDT*/
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders"
+	url := gtUniEndpoint + path
+	payload := gt.futuresOrderPayload(typ, symbol, clientId, price, qty,
+		side, orderType, timeInForce, positionMode, reduceOnly)
+	headers := gt.buildHeaders("POST", path, "", payload)
+	httpCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label   string `json:"label"`
+		Msg     string `json:"message"`
+		OrderId int64  `json:"id"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return "", gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	if ret.OrderId == 0 {
+		return "", errors.New(gt.Name() + " orderid is empty!")
+	}
+	return strconv.FormatInt(ret.OrderId, 10), nil
+}
+func (gt *Gate) toStdFuturesOrder(order *GateFuturesOrder) *FuturesOrder {
+	sym, typ := gt.toStdContractSymbol(order.Symbol)
+	size := decimal.NewFromInt(order.Size).Abs()
+	filledSize := size.Sub(decimal.NewFromInt(order.Left).Abs())
+	fo := &FuturesOrder{
+		Symbol:    sym,
+		OrderId:   strconv.FormatInt(order.OrderId, 10),
+		Price:     order.Price,
+		Qty:       gt.FuturesSizeToQty(typ, sym, size),
+		FilledQty: gt.FuturesSizeToQty(typ, sym, filledSize),
+		Type:      "LIMIT",
+		Side:      "BUY",
+		CTime:     order.CTime.Shift(3).IntPart(),
+		UTime:     order.FTime.Shift(3).IntPart(),
+	}
+	if len(order.ClientId) > 2 && strings.HasPrefix(order.ClientId, "t-") {
+		fo.ClientId = order.ClientId[2:]
+	}
+	if order.Size < 0 {
+		fo.Side = "SELL"
+	}
+	if order.Price.IsZero() {
+		fo.Type = "MARKET"
+	}
+	if fo.UTime == 0 {
+		fo.UTime = fo.CTime
+	}
+	if order.Status == "open" {
+		fo.Status = "NEW"
+		if filledSize.IsPositive() {
+			fo.Status = "PARTIALLY_FILLED"
+		}
+	} else if order.Status == "finished" {
+		fo.Status = "CANCELED" // cancelled/ioc/reduce_only/position_closed/stp...
+		if order.FinishAs == "filled" || order.Left == 0 {
+			fo.Status = "FILLED"
+		}
+	}
+	if typ == "CM" { // 成交金额换算成标的数量
+		fo.AvgPrice = order.AvgPrice
+		if order.AvgPrice.IsPositive() {
+			fo.FilledAmt = filledSize.Div(order.AvgPrice)
+		}
+	} else {
+		fo.FilledAmt = fo.FilledQty.Mul(order.AvgPrice)
+	}
+	return fo
+}
+func (gt *Gate) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOrder, error) {
+	if orderId == "" && cltId != "" {
+		orderId = "t-" + cltId
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders/" + orderId
+	url := gtUniEndpoint + path
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	order := GateFuturesOrder{}
+	if err = easyjson.Unmarshal(resp, &order); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if order.Label != "" {
+		return nil, gt.apiError(httpCode, order.Label, order.Msg)
+	}
+	return gt.toStdFuturesOrder(&order), nil
+}
+func (gt *Gate) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders"
+	params := "status=open"
+	if symbol != "" {
+		params += "&contract=" + gt.getContractSymbol(symbol)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetOpenOrders", resp)
+	}
+	ret := []GateFuturesOrder{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	orders := make([]*FuturesOrder, 0, len(ret))
+	for i := range ret {
+		orders = append(orders, gt.toStdFuturesOrder(&ret[i]))
+	}
+	return orders, nil
+}
+func (gt *Gate) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
+	if orderId == "" && cltId != "" {
+		orderId = "t-" + cltId
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders/" + orderId
+	url := gtUniEndpoint + path
+	headers := gt.buildHeaders("DELETE", path, "", "")
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	order := GateFuturesOrder{}
+	if err = easyjson.Unmarshal(resp, &order); err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error() + string(resp))
+	}
+	if order.Label != "" {
+		return gt.apiError(httpCode, order.Label, order.Msg)
+	}
+	if order.Status != "finished" {
+		return errors.New(gt.Name() + " cancel failed! status now: " + order.Status)
+	}
+	return nil
+}
+func (gt *Gate) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/batch_orders"
+	url := gtUniEndpoint + path
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 10 { // 每批最多10个
+		end := min(start+10, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			o := orders[i]
+			results[i].ClientId = o.ClientId
+			if i > start {
+				payload += ","
+			}
+			payload += gt.futuresOrderPayload(typ, o.Symbol, o.ClientId, o.Price, o.Qty,
+				o.Side, o.Type, o.TimeInForce, o.PositionMode, o.ReduceOnly)
+		}
+		payload += "]"
+		headers := gt.buildHeaders("POST", path, "", payload)
+		retCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+		gt.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (gt *Gate) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/batch_cancel_orders"
+	url := gtUniEndpoint + path
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			results[i].OrderId = orders[i].OrderId
+			results[i].ClientId = orders[i].ClientId
+			orderId := orders[i].OrderId
+			if orderId == "" && orders[i].ClientId != "" {
+				orderId = "t-" + orders[i].ClientId
+			}
+			if i > start {
+				payload += ","
+			}
+			payload += `"` + orderId + `"`
+		}
+		payload += "]"
+		headers := gt.buildHeaders("POST", path, "", payload)
+		retCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+		gt.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (gt *Gate) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(gt.Name() + " symbol empty!")
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders"
+	params := "contract=" + gt.getContractSymbol(symbol)
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("DELETE", path, params, "")
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	if len(resp) > 0 && resp[0] == '{' {
+		return gt.handleExceptionResp(httpCode, "FuturesCancelAllOrders", resp)
+	}
+	return nil
+}
+
+// size为包含已成交部分的总张数, 方向必须与原订单一致
+func (gt *Gate) FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*FuturesOrder, error) {
+	if orderId == "" && cltId != "" {
+		orderId = "t-" + cltId
+	}
+	size := gt.FuturesQtyToSize(typ, symbol, qty).IntPart()
+	if side == "SELL" {
+		size = -size
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/orders/" + orderId
+	url := gtUniEndpoint + path
+	payload := `{"size":` + strconv.FormatInt(size, 10) + `,"price":"` + price.String() + `"}`
+	headers := gt.buildHeaders("PUT", path, "", payload)
+	httpCode, resp, err := gt.Put(url, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	order := GateFuturesOrder{}
+	if err = easyjson.Unmarshal(resp, &order); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if order.Label != "" {
+		return nil, gt.apiError(httpCode, order.Label, order.Msg)
+	}
+	return gt.toStdFuturesOrder(&order), nil
+}
+
+// 用price_orders实现, 不支持cltId, 止损/止盈由触发方向区分(rule 1:>= 2:<=)
+func (gt *Gate) FuturesPlaceConditionalOrder(typ, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy, positionMode string,
+	reduceOnly int) (string, error) {
+	isStop, isLimit, ok := parseConditionalOrderType(orderType)
+	if !ok {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	priceType := gt.fromStdTriggerBy(triggerBy)
+	if priceType == "" {
+		return "", errors.New(gt.Name() + " not support trigger by " + triggerBy)
+	}
+	rule := "2"
+	if conditionalTriggerRise(isStop, side) {
+		rule = "1"
+	}
+	size := gt.FuturesQtyToSize(typ, symbol, qty).IntPart()
+	if side == "SELL" {
+		size = -size
+	}
+	initial := `"contract":"` + gt.getContractSymbol(symbol) + `"` +
+		`,"size":` + strconv.FormatInt(size, 10)
+	if isLimit {
+		initial += `,"price":"` + price.String() + `","tif":"gtc"`
+	} else {
+		initial += `,"price":"0","tif":"ioc"`
+	}
+	if reduceOnly == 1 ||
+		(positionMode == "LONG" && side == "SELL") ||
+		(positionMode == "SHORT" && side == "BUY") {
+		initial += `,"reduce_only":true`
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/price_orders"
+	url := gtUniEndpoint + path
+	payload := `{"initial":{` + initial + `}` +
+		`,"trigger":{"strategy_type":0,"price_type":` + priceType +
+		`,"price":"` + triggerPrice.String() + `","rule":` + rule + `,"expiration":2592000}` +
+		`}`
+	headers := gt.buildHeaders("POST", path, "", payload)
+	httpCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label   string `json:"label"`
+		Msg     string `json:"message"`
+		OrderId int64  `json:"id"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return "", gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return strconv.FormatInt(ret.OrderId, 10), nil
+}
+func (gt *Gate) FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/price_orders"
+	params := "status=open"
+	if symbol != "" {
+		params += "&contract=" + gt.getContractSymbol(symbol)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetOpenConditionalOrders", resp)
+	}
+	orders := []struct {
+		OrderId int64 `json:"id"`
+		Initial struct {
+			Contract string          `json:"contract"`
+			Size     int64           `json:"size"`
+			Price    decimal.Decimal `json:"price"`
+		} `json:"initial"`
+		Trigger struct {
+			PriceType int             `json:"price_type"`
+			Price     decimal.Decimal `json:"price"`
+			Rule      int             `json:"rule"`
+		} `json:"trigger"`
+		CTime decimal.Decimal `json:"create_time"` // sec
+	}{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	oL := make([]*ConditionalOrder, 0, len(orders))
+	for _, order := range orders {
+		sym, t := gt.toStdContractSymbol(order.Initial.Contract)
+		size := decimal.NewFromInt(order.Initial.Size)
+		co := &ConditionalOrder{
+			Symbol:       sym,
+			OrderId:      strconv.FormatInt(order.OrderId, 10),
+			Side:         "BUY",
+			TriggerPrice: order.Trigger.Price,
+			TriggerBy:    gt.toStdTriggerBy(order.Trigger.PriceType),
+			Qty:          gt.FuturesSizeToQty(t, sym, size.Abs()),
+			Status:       "NEW",
+			CTime:        order.CTime.Shift(3).IntPart(),
+		}
+		if order.Initial.Size < 0 {
+			co.Side = "SELL"
+		}
+		if (order.Trigger.Rule == 1) == conditionalTriggerRise(true, co.Side) {
+			co.Type = "STOP"
+		} else {
+			co.Type = "TAKE_PROFIT"
+		}
+		if order.Initial.Price.IsZero() {
+			co.Type += "_MARKET"
+		} else {
+			co.Type += "_LIMIT"
+			co.Price = order.Initial.Price
+		}
+		oL = append(oL, co)
+	}
+	return oL, nil
+}
+func (gt *Gate) FuturesCancelConditionalOrder(typ, symbol, orderId string) error {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/price_orders/" + orderId
+	headers := gt.buildHeaders("DELETE", path, "", "")
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error() + string(resp))
+	}
+	if ret.Label != "" {
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return nil
+}
+
+// 按结算币种生效, 有持仓或挂单时不能切换
+func (gt *Gate) FuturesSwitchPositionMode(typ string, mode int) error {
+	m := ""
+	if mode == 1 {
+		m = "true"
+	} else if mode == 0 {
+		m = "false"
+	}
+	if m == "" {
+		return errors.New("params error")
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/dual_mode"
+	params := "dual_mode=" + m
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("POST", path, params, "")
+	httpCode, resp, err := gt.Post(url, nil, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return nil
+}
+
+// gate 通过杠杆区分保证金模式: leverage=0为全仓(杠杆取cross_leverage_limit), 否则为逐仓
+func (gt *Gate) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int) error {
+	if leverage < 1 || leverage > 125 {
+		return errors.New("leverage error 1~125")
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/positions/" +
+		gt.getContractSymbol(symbol) + "/leverage"
+	params := "leverage=" + strconv.Itoa(leverage)
+	if mode == 0 {
+		params = "leverage=0&cross_leverage_limit=" + strconv.Itoa(leverage)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("POST", path, params, "")
+	httpCode, resp, err := gt.Post(url, nil, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	if len(resp) > 0 && resp[0] == '{' {
+		return gt.handleExceptionResp(httpCode, "FuturesSwitchTradeMode", resp)
+	}
+	return nil
+}
+
+// gate 按仓位价值(结算币种)分层, 只返回NotionalCap/NotionalFloor, 没有速算数
+func (gt *Gate) FuturesMaintMargin(typ, symbol string) ([]*FuturesLeverageBracket, error) {
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/risk_limit_tiers"
+	params := "contract=" + gt.getContractSymbol(symbol)
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesMaintMargin", resp)
+	}
+	ret := []struct {
+		Tier        int64           `json:"tier"`
+		RiskLimit   decimal.Decimal `json:"risk_limit"`
+		MaintRate   decimal.Decimal `json:"maintenance_rate"`
+		LeverageMax decimal.Decimal `json:"leverage_max"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(ret) == 0 {
+		return nil, errors.New(gt.Name() + " resp empty")
+	}
+	lbs := make([]*FuturesLeverageBracket, 0, len(ret))
+	floor := decimal.Zero
+	for _, v := range ret {
+		lbs = append(lbs, &FuturesLeverageBracket{
+			Bracket:          v.Tier,
+			InitialLeverage:  v.LeverageMax.IntPart(),
+			NotionalCap:      v.RiskLimit,
+			NotionalFloor:    floor,
+			MaintMarginRatio: v.MaintRate,
+		})
+		floor = v.RiskLimit
+	}
+	return lbs, nil
+}
+
+// 只支持FUNDING_FEE, startTime/endTime msec, 一次最多1000条
+func (gt *Gate) FuturesGetProfitLossHistory(typ, symbol, plType string,
+	startTime, endTime int64) ([]FuturesProfitLossHistory, error) {
+	if plType != "FUNDING_FEE" {
+		return nil, errors.New(gt.Name() + " not support " + plType)
+	}
+	path := "/api/v4/futures/" + gt.futuresSettle(typ) + "/account_book"
+	params := "type=fund&limit=1000"
+	if symbol != "" {
+		params += "&contract=" + gt.getContractSymbol(symbol)
+	}
+	if startTime > 0 {
+		params += "&from=" + strconv.FormatInt(startTime/1000, 10)
+	}
+	if endTime > 0 {
+		params += "&to=" + strconv.FormatInt(endTime/1000, 10)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "FuturesGetProfitLossHistory", resp)
+	}
+	ret := []struct {
+		Income decimal.Decimal `json:"change"`
+		Time   decimal.Decimal `json:"time"` // sec
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	asset := strings.ToUpper(gt.futuresSettle(typ))
+	plh := make([]FuturesProfitLossHistory, 0, len(ret))
+	for _, v := range ret {
+		plh = append(plh, FuturesProfitLossHistory{
+			Income: v.Income,
+			Asset:  asset,
+			Typ:    plType,
+			Time:   v.Time.Shift(3).IntPart(),
+		})
+	}
+	return plh, nil
+}
@@ -0,0 +1,583 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shopspring/decimal"
+
+	"github.com/shaovie/gutils/ilog"
+)
+
+var (
+	gtWsContractPubMsgPool sync.Pool
+)
+
+func init() {
+	gtWsContractPubMsgPool = sync.Pool{
+		New: func() any {
+			return &GateWsContractPubMsg{}
+		},
+	}
+}
+
+// UM/CM 是不同的连接(usdt/btc), 推送的数量已由张数换算成qty
+func (gt *Gate) FuturesWsPublicOpen(typ string) error {
+	if !gt.FuturesSupported(typ) {
+		return newNotSupportError(gt.Name())
+	}
+	url := gt.wsUrl("wss://fx-ws.gateio.ws/v4/ws/" + gt.futuresSettle(typ))
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	gt.wsContractPubCon, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(gt.Name() + " futures.ws.public con failed! " + err.Error())
+	}
+	gt.wsContractPubTyp = typ
+	gt.wsContractPubChannelClosedMtx.Lock()
+	gt.wsContractPubChannelClosed = false
+	gt.wsContractPubChannelClosedMtx.Unlock()
+	return nil
+}
+func (gt *Gate) parseContractSymbols(str string) []string {
+	symbolArr := strings.Split(str, ",")
+	symbolList := make([]string, 0, len(symbolArr))
+	for _, v := range symbolArr {
+		if sym := gt.getContractSymbol(v); sym != "" {
+			symbolList = append(symbolList, sym)
+		}
+	}
+	return symbolList
+}
+func (gt *Gate) FuturesWsPublicSubscribe(channels []string) {
+	gt.futuresWsPublicSubscribe("subscribe", channels)
+}
+func (gt *Gate) FuturesWsPublicUnsubscribe(channels []string) {
+	gt.futuresWsPublicSubscribe("unsubscribe", channels)
+}
+func (gt *Gate) futuresWsPublicSubscribe(event string, channels []string) {
+	if len(channels) == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	arg := GateSubscribeArg{Time: now, Event: event}
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		if arr[0] == "orderbook5" {
+			arg.Channel = "futures.order_book"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for sym := range symbolArr {
+				if symbol := gt.getContractSymbol(sym); symbol != "" {
+					if event == "subscribe" {
+						gt.wsContractOrderBooks.add(sym, 5)
+					} else {
+						gt.wsContractOrderBooks.remove(sym)
+					}
+					arg.Payload = []string{symbol, "5", "0"}
+					req, _ := json.Marshal(&arg)
+					gt.wsContractPubConMtx.Lock()
+					gt.wsContractPubCon.WriteMessage(websocket.TextMessage, req)
+					gt.wsContractPubConMtx.Unlock()
+				}
+			}
+		} else if arr[0] == "bbo" || arr[0] == "ticker" {
+			arg.Channel = "futures.book_ticker"
+			if arr[0] == "ticker" {
+				arg.Channel = "futures.tickers"
+			}
+			arg.Payload = gt.parseContractSymbols(arr[1])
+			if len(arg.Payload) > 0 {
+				req, _ := json.Marshal(&arg)
+				gt.wsContractPubConMtx.Lock()
+				gt.wsContractPubCon.WriteMessage(websocket.TextMessage, req)
+				gt.wsContractPubConMtx.Unlock()
+			}
+		} else if arr[0] == "kline" {
+			arg.Channel = "futures.candlesticks"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, interval := parseKLineSymbol(v)
+				if symbol := gt.getContractSymbol(sym); symbol != "" {
+					arg.Payload = []string{interval, symbol}
+					req, _ := json.Marshal(&arg)
+					gt.wsContractPubConMtx.Lock()
+					gt.wsContractPubCon.WriteMessage(websocket.TextMessage, req)
+					gt.wsContractPubConMtx.Unlock()
+				}
+			}
+		}
+	}
+}
+func (gt *Gate) FuturesWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (gt *Gate) FuturesWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (gt *Gate) FuturesWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (gt *Gate) FuturesWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
+func (gt *Gate) FuturesWsPublicLoop(ch chan<- any) {
+	defer gt.FuturesWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 21 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	gt.wsContractPubCon.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if gt.FuturesWsPublicIsClosed() {
+					break
+				}
+				s := fmt.Sprintf(`{"time":%d,"channel":"futures.ping"}`, time.Now().Unix())
+				gt.wsContractPubConMtx.Lock()
+				gt.wsContractPubCon.WriteMessage(websocket.TextMessage, []byte(s))
+				gt.wsContractPubConMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	for {
+		_, recv, err := gt.wsContractPubCon.ReadMessage()
+		if err != nil {
+			if !gt.FuturesWsPublicIsClosed() {
+				ilog.Warning("%s", gt.Name()+" futures.ws.public channel read: "+err.Error())
+			}
+			break
+		}
+		msg := gtWsContractPubMsgPool.Get().(*GateWsContractPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", gt.Name()+" futures.ws.public recv invalid msg:"+string(recv))
+			goto END
+		}
+
+		if msg.Channel == "futures.order_book" {
+			if msg.Event == "all" {
+				gt.futuresWsHandleOrderBook(msg.Data, ch)
+			} else if msg.Event == "update" {
+				gt.futuresWsHandleOrderBookUpdate(msg.Data, ch)
+			}
+		} else if msg.Channel == "futures.book_ticker" {
+			if msg.Event == "update" {
+				gt.futuresWsHandleBBO(msg.Data, ch)
+			}
+		} else if msg.Channel == "futures.tickers" {
+			if msg.Event == "update" {
+				gt.futuresWsHandle24hTickers(msg.Data, ch)
+			}
+		} else if msg.Channel == "futures.candlesticks" {
+			if msg.Event == "update" {
+				gt.futuresWsHandleKLine(msg.Data, ch)
+			}
+		} else if msg.Channel == "futures.pong" {
+			gt.wsContractPubCon.SetReadDeadline(time.Now().Add(pongWait))
+		} else {
+			ilog.Error("%s", gt.Name()+" futures.ws.public recv unknown msg: "+string(recv))
+		}
+	END:
+		gtWsContractPubMsgPool.Put(msg)
+	}
+}
+func (gt *Gate) FuturesWsPublicIsClosed() bool {
+	gt.wsContractPubChannelClosedMtx.RLock()
+	defer gt.wsContractPubChannelClosedMtx.RUnlock()
+	return gt.wsContractPubChannelClosed
+}
+func (gt *Gate) FuturesWsPublicClose() {
+	gt.wsContractPubChannelClosedMtx.Lock()
+	defer gt.wsContractPubChannelClosedMtx.Unlock()
+	if gt.wsContractPubChannelClosed {
+		return
+	}
+	gt.wsContractPubChannelClosed = true
+	gt.wsContractPubCon.Close()
+}
+
+// all为全量快照, 之后以update推送逐档变化, 在本地维护前5档
+func (gt *Gate) futuresWsHandleOrderBook(data json.RawMessage, ch chan<- any) {
+	depth := gt.wsContractPubOrderBookPool.Get().(*GateContractOrderBook)
+	defer gt.wsContractPubOrderBookPool.Put(depth)
+	depth.Bids = depth.Bids[:0]
+	depth.Asks = depth.Asks[:0]
+	if err := easyjson.Unmarshal(data, depth); err != nil {
+		ilog.Error("%s", gt.Name()+" futures.ws.public orderbook exception: "+string(data))
+		return
+	}
+	symbol, _ := gt.toStdContractSymbol(depth.Symbol)
+	ob := gt.wsContractOrderBooks.get(symbol)
+	if ob == nil {
+		return
+	}
+	typ := gt.wsContractPubTyp
+	ob.reset()
+	for _, v := range depth.Bids {
+		ob.set(true, v.Price.String(), gt.FuturesSizeToQty(typ, symbol, v.Size).String())
+	}
+	for _, v := range depth.Asks {
+		ob.set(false, v.Price.String(), gt.FuturesSizeToQty(typ, symbol, v.Size).String())
+	}
+	ob.time = depth.Time
+	ob.synced = true
+	gt.futuresWsPushOrderBook5(ob, ch)
+}
+
+// s>0为买, s<0为卖, s=0删除该价位
+func (gt *Gate) futuresWsHandleOrderBookUpdate(data json.RawMessage, ch chan<- any) {
+	updates := []struct {
+		Symbol string          `json:"c"`
+		Price  string          `json:"p"`
+		Size   decimal.Decimal `json:"s"`
+	}{}
+	if err := json.Unmarshal(data, &updates); err != nil || len(updates) == 0 {
+		ilog.Error("%s", gt.Name()+" futures.ws.public orderbook exception: "+string(data))
+		return
+	}
+	symbol, _ := gt.toStdContractSymbol(updates[0].Symbol)
+	ob := gt.wsContractOrderBooks.get(symbol)
+	if ob == nil || !ob.synced {
+		return
+	}
+	typ := gt.wsContractPubTyp
+	for _, v := range updates {
+		if v.Size.IsZero() {
+			ob.set(true, v.Price, "0")
+			ob.set(false, v.Price, "0")
+			continue
+		}
+		qty := gt.FuturesSizeToQty(typ, symbol, v.Size.Abs()).String()
+		ob.set(v.Size.IsPositive(), v.Price, qty)
+	}
+	ob.time = time.Now().UnixMilli()
+	gt.futuresWsPushOrderBook5(ob, ch)
+}
+func (gt *Gate) futuresWsPushOrderBook5(ob *localOrderBook, ch chan<- any) {
+	bids := ob.top(true, ob.level)
+	asks := ob.top(false, ob.level)
+	obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+	obd.Symbol = ob.symbol
+	obd.Level = ob.level
+	obd.Time = ob.time
+	obd.Bids = obd.Bids[:0]
+	obd.Asks = obd.Asks[:0]
+	for _, l := range bids {
+		obd.Bids = append(obd.Bids, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	for _, l := range asks {
+		obd.Asks = append(obd.Asks, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	ch <- obd
+}
+func (gt *Gate) futuresWsHandleBBO(data json.RawMessage, ch chan<- any) {
+	bbo := struct {
+		Time     int64           `json:"t"` // msec
+		Symbol   string          `json:"s"`
+		BidPrice decimal.Decimal `json:"b"`
+		BidSize  decimal.Decimal `json:"B"`
+		AskPrice decimal.Decimal `json:"a"`
+		AskSize  decimal.Decimal `json:"A"`
+	}{}
+	if err := json.Unmarshal(data, &bbo); err == nil {
+		symbol, _ := gt.toStdContractSymbol(bbo.Symbol)
+		typ := gt.wsContractPubTyp
+		obd := wsPublicBBOPool.Get().(*BestBidAsk)
+		obd.Symbol = symbol
+		obd.Time = bbo.Time
+		obd.BidPrice = bbo.BidPrice
+		obd.BidQty = gt.FuturesSizeToQty(typ, symbol, bbo.BidSize)
+		obd.AskPrice = bbo.AskPrice
+		obd.AskQty = gt.FuturesSizeToQty(typ, symbol, bbo.AskSize)
+		ch <- obd
+	}
+}
+func (gt *Gate) futuresWsHandle24hTickers(data json.RawMessage, ch chan<- any) {
+	tickers := gt.wsContractPubTickerPool.Get().(*[]GateContract24hTicker)
+	defer gt.wsContractPubTickerPool.Put(tickers)
+	*tickers = (*tickers)[:0]
+	if err := json.Unmarshal(data, tickers); err == nil {
+		for _, ticker := range *tickers {
+			symbol, typ := gt.toStdContractSymbol(ticker.Symbol)
+			tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+			tk.Symbol = symbol
+			tk.LastPrice = ticker.Last
+			tk.Volume = ticker.Volume
+			tk.BaseVolume = decimal.Zero
+			tk.QuoteVolume = decimal.Zero
+			if typ == "CM" {
+				tk.BaseVolume = ticker.Volume
+			} else {
+				tk.QuoteVolume = ticker.QuoteVolume
+			}
+			ch <- tk
+		}
+	}
+}
+
+// Volume由张数换算成标的数量(CM为张数), ws不推成交额
+func (gt *Gate) futuresWsHandleKLine(data json.RawMessage, ch chan<- any) {
+	klines := []struct {
+		OpenTime int64           `json:"t"` // sec
+		Name     string          `json:"n"` // 1m_BTC_USDT
+		Volume   decimal.Decimal `json:"v"` // 张数
+		Open     decimal.Decimal `json:"o"`
+		High     decimal.Decimal `json:"h"`
+		Low      decimal.Decimal `json:"l"`
+		Close    decimal.Decimal `json:"c"`
+		IsClosed bool            `json:"w"`
+	}{}
+	if err := json.Unmarshal(data, &klines); err != nil {
+		ilog.Error("%s", gt.Name()+" futures.ws.public kline exception: "+string(data))
+		return
+	}
+	typ := gt.wsContractPubTyp
+	for _, kl := range klines {
+		interval, contract, _ := strings.Cut(kl.Name, "_")
+		symbol, _ := gt.toStdContractSymbol(contract)
+		v := wsPublicKLinePool.Get().(*KLine)
+		v.Symbol = symbol
+		v.Interval = interval
+		v.OpenTime = kl.OpenTime
+		v.OpenPrice = kl.Open
+		v.HighPrice = kl.High
+		v.LowPrice = kl.Low
+		v.ClosePrice = kl.Close
+		v.Volume = gt.FuturesSizeToQty(typ, symbol, kl.Volume)
+		v.QuoteVolume = decimal.Zero
+		v.IsClosed = kl.IsClosed
+		ch <- v
+	}
+}
+
+// priv
+func (gt *Gate) FuturesWsPrivateSupported(typ string) bool {
+	return gt.FuturesSupported(typ)
+}
+
+// 私有频道需要user_id, 连接前先通过rest取一次
+func (gt *Gate) FuturesWsPrivateOpen(typ string) error {
+	if !gt.FuturesSupported(typ) {
+		return newNotSupportError(gt.Name())
+	}
+	if gt.wsContractUserId == "" {
+		path := "/api/v4/account/detail"
+		headers := gt.buildHeaders("GET", path, "", "")
+		httpCode, resp, err := gt.Get(gtUniEndpoint+path, gtApiDeadline, headers)
+		if err != nil {
+			return newNetError(gt.Name(), err)
+		}
+		ret := struct {
+			Label  string `json:"label"`
+			Msg    string `json:"message"`
+			UserId int64  `json:"user_id"`
+		}{}
+		if err = json.Unmarshal(resp, &ret); err != nil {
+			return errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+		}
+		if ret.Label != "" {
+			return gt.apiError(httpCode, ret.Label, ret.Msg)
+		}
+		gt.wsContractUserId = strconv.FormatInt(ret.UserId, 10)
+	}
+	url := gt.wsUrl("wss://fx-ws.gateio.ws/v4/ws/" + gt.futuresSettle(typ))
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	gt.wsContractPrivCon, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(gt.Name() + " connect failed! " + err.Error())
+	}
+	gt.wsContractPrivTyp = typ
+	gt.wsContractPrivChannelClosedMtx.Lock()
+	gt.wsContractPrivChannelClosed = false
+	gt.wsContractPrivChannelClosedMtx.Unlock()
+	return nil
+}
+func (gt *Gate) FuturesWsPrivateSubscribe(channels []string) {
+	now := time.Now().Unix()
+	arg := GateSubscribeArg{Time: now, Event: "subscribe"}
+	for _, c := range channels {
+		channel := ""
+		if c == "orders" {
+			channel = "futures.orders"
+		} else if c == "positions" {
+			channel = "futures.positions"
+		} else {
+			continue
+		}
+		arg.Channel = channel
+		arg.Payload = []string{gt.wsContractUserId, "!all"}
+		arg.Auth = &GatePrivAuth{
+			Method: "api_key",
+			Key:    gt.apikey,
+			Sign:   gt.wsSign(channel, "subscribe", now),
+		}
+		req, _ := json.Marshal(&arg)
+		gt.wsContractPrivConMtx.Lock()
+		if err := gt.wsContractPrivCon.WriteMessage(websocket.TextMessage, req); err != nil {
+			ilog.Warning("%s", gt.Name()+" futures.ws.priv subscribe net error! "+err.Error())
+		}
+		gt.wsContractPrivConMtx.Unlock()
+	}
+}
+func (gt *Gate) FuturesWsPrivateLoop(ch chan<- any) {
+	defer gt.FuturesWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 23 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	gt.wsContractPrivCon.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if gt.FuturesWsPrivateIsClosed() {
+					break
+				}
+				s := fmt.Sprintf(`{"time":%d,"channel":"futures.ping"}`, time.Now().Unix())
+				gt.wsContractPrivConMtx.Lock()
+				gt.wsContractPrivCon.WriteMessage(websocket.TextMessage, []byte(s))
+				gt.wsContractPrivConMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	for {
+		_, recv, err := gt.wsContractPrivCon.ReadMessage()
+		if err != nil {
+			if !gt.FuturesWsPrivateIsClosed() {
+				ilog.Warning("%s", gt.Name()+" futures.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if gt.debug {
+			ilog.Rinfo("%s", gt.Name()+" futures priv ws: "+string(recv))
+		}
+		msg := gtWsContractPubMsgPool.Get().(*GateWsContractPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", gt.Name()+" futures.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Channel == "futures.orders" {
+			if msg.Event == "update" {
+				gt.futuresWsHandleOrder(msg.Data, ch)
+			} else if msg.Event == "subscribe" {
+				gt.futuresWsHandleSubscribeResp(msg.Data, recv)
+			}
+		} else if msg.Channel == "futures.positions" {
+			if msg.Event == "update" {
+				gt.futuresWsHandlePosition(msg.Data, ch)
+			} else if msg.Event == "subscribe" {
+				gt.futuresWsHandleSubscribeResp(msg.Data, recv)
+			}
+		} else if msg.Channel == "futures.pong" {
+			gt.wsContractPrivCon.SetReadDeadline(time.Now().Add(pongWait))
+		} else {
+			ilog.Error("%s", gt.Name()+" futures.ws.priv recv unknown msg: "+string(recv))
+		}
+	END:
+		gtWsContractPubMsgPool.Put(msg)
+	}
+}
+func (gt *Gate) FuturesWsPrivateIsClosed() bool {
+	gt.wsContractPrivChannelClosedMtx.RLock()
+	defer gt.wsContractPrivChannelClosedMtx.RUnlock()
+	return gt.wsContractPrivChannelClosed
+}
+func (gt *Gate) FuturesWsPrivateClose() {
+	gt.wsContractPrivChannelClosedMtx.Lock()
+	defer gt.wsContractPrivChannelClosedMtx.Unlock()
+	if gt.wsContractPrivChannelClosed {
+		return
+	}
+	gt.wsContractPrivChannelClosed = true
+	gt.wsContractPrivCon.Close()
+}
+
+// 鉴权失败时result.status不是success
+func (gt *Gate) futuresWsHandleSubscribeResp(data json.RawMessage, recv []byte) {
+	ret := struct {
+		Status string `json:"status"`
+	}{}
+	if err := json.Unmarshal(data, &ret); err != nil || ret.Status != "success" {
+		ilog.Error("%s", gt.Name()+" futures.ws.priv subscribe fail: "+string(recv))
+	}
+}
+func (gt *Gate) futuresWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	orders := []GateFuturesOrder{}
+	if err := json.Unmarshal(data, &orders); err == nil && len(orders) > 0 {
+		for i := range orders {
+			ch <- gt.toStdFuturesOrder(&orders[i])
+		}
+	}
+}
+func (gt *Gate) futuresWsHandlePosition(data json.RawMessage, ch chan<- any) {
+	positions := []struct {
+		Symbol        string          `json:"contract"`
+		Mode          string          `json:"mode"` // single/dual_long/dual_short
+		Size          decimal.Decimal `json:"size"`
+		EntryPrice    decimal.Decimal `json:"entry_price"`
+		LiqPrice      decimal.Decimal `json:"liq_price"`
+		Leverage      decimal.Decimal `json:"leverage"` // 0为全仓
+		CrossLeverage decimal.Decimal `json:"cross_leverage_limit"`
+		UTime         int64           `json:"time_ms"`
+	}{}
+	if err := json.Unmarshal(data, &positions); err == nil && len(positions) > 0 {
+		for _, v := range positions {
+			sym, typ := gt.toStdContractSymbol(v.Symbol)
+			cp := &FuturesPosition{
+				Symbol:      sym,
+				Side:        "SELL",
+				PositionQty: gt.FuturesSizeToQty(typ, sym, v.Size.Abs()),
+				EntryPrice:  v.EntryPrice,
+				LiqPrice:    v.LiqPrice,
+				Leverage:    v.Leverage,
+				UTime:       v.UTime,
+			}
+			if v.Mode == "dual_long" {
+				cp.Mode = 1
+				cp.Side = "BUY"
+			} else if v.Mode == "dual_short" {
+				cp.Mode = 1
+			} else if v.Size.IsPositive() {
+				cp.Side = "BUY"
+			}
+			if cp.Leverage.IsZero() {
+				cp.Leverage = v.CrossLeverage
+			}
+			ch <- cp
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cex
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,7 +20,7 @@ func (gt *Gate) SpotServerTime() (int64, error) {
 	url := gtUniEndpoint + path
 	_, resp, err := gt.Get(url, gtApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(gt.Name() + " net error! " + err.Error())
+		return 0, newNetError(gt.Name(), err)
 	}
 	recv := struct {
 		Time int64 `json:"server_time,omitempty"`
@@ -35,7 +36,7 @@ func (gt *Gate) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 	url := gtUniEndpoint + path
 	_, resp, err := gt.Get(url, gtApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 
 	recv := []struct {
@@ -86,12 +87,12 @@ func (gt *Gate) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 func (gt *Gate) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
 	path := "/api/v4/spot/tickers"
 	url := gtUniEndpoint + path
-	_, resp, err := gt.Get(url, gtApiDeadline, nil)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	if resp[0] == '{' {
-		return nil, gt.handleExceptionResp("SpotGetAll24hTicker", resp)
+		return nil, gt.handleExceptionResp(httpCode, "SpotGetAll24hTicker", resp)
 	}
 
 	tickers := []GateSpot24hTicker{}
@@ -117,9 +118,9 @@ func (gt *Gate) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	path := "/api/v4/spot/accounts"
 	url := gtUniEndpoint + path
 	headers := gt.buildHeaders("GET", path, "", "")
-	_, resp, err := gt.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	assetL := []struct {
 		Symbol    string          `json:"currency"`
@@ -131,7 +132,7 @@ func (gt *Gate) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
 	}
 	if resp[0] == '{' {
-		return nil, gt.handleExceptionResp("SpotGetAllAssets", resp)
+		return nil, gt.handleExceptionResp(httpCode, "SpotGetAllAssets", resp)
 	}
 
 	assetsMap := make(map[string]*SpotAsset)
@@ -153,9 +154,9 @@ func (gt *Gate) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	path := "/api/v4/spot/order_book?limit=1&currency_pair=" + symbolS
 	url := gtUniEndpoint + path
 	headers := gt.buildHeaders("GET", path, "", "")
-	_, resp, err := gt.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return BestBidAsk{}, errors.New(gt.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(gt.Name(), err)
 	}
 	ret := struct {
 		Label string               `json:"label"`
@@ -169,7 +170,7 @@ func (gt *Gate) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	}
 
 	if ret.Label != "" {
-		return BestBidAsk{}, errors.New(gt.Name() + " request fail! err=" + ret.Msg)
+		return BestBidAsk{}, gt.apiError(httpCode, ret.Label, ret.Msg)
 	}
 	if len(ret.Asks) == 0 || len(ret.Bids) == 0 {
 		return BestBidAsk{}, errors.New(gt.Name() + " resp is empty!")
@@ -183,6 +184,35 @@ func (gt *Gate) SpotGetBBO(symbol string) (BestBidAsk, error) {
 		AskQty:   ret.Asks[0][1],
 	}, nil
 }
+
+// 深度快照(带id), 用于同步本地订单簿 level=0 或 >1000 时取1000档
+func (gt *Gate) spotGetOrderBook(symbolS string, level int) (int64, [][2]string, [][2]string, error) {
+	if level == 0 || level > 1000 {
+		level = 1000
+	}
+	path := "/api/v4/spot/order_book?with_id=true&limit=" + strconv.Itoa(level) + "&currency_pair=" + symbolS
+	url := gtUniEndpoint + path
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return 0, nil, nil, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string      `json:"label"`
+		Msg   string      `json:"message"`
+		Id    int64       `json:"id"`
+		Asks  [][2]string `json:"asks"`
+		Bids  [][2]string `json:"bids"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return 0, nil, nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Label != "" {
+		return 0, nil, nil, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return ret.Id, ret.Bids, ret.Asks, nil
+}
 func (gt *Gate) SpotPlaceOrder(symbol, clientId string,
 	price, amt, qty decimal.Decimal,
 	side, timeInForce, orderType string, postOnly bool) (string, error) {
@@ -210,9 +240,9 @@ func (gt *Gate) SpotPlaceOrder(symbol, clientId string,
 		`,"type":"` + gt.fromStdOrderType(orderType) + `"` + // LIMIT/MARKET
 		`}`
 	headers := gt.buildHeaders("POST", path, "", payload)
-	_, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+	httpCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
 	if err != nil {
-		return "", errors.New(gt.Name() + " net error! " + err.Error())
+		return "", newNetError(gt.Name(), err)
 	}
 	ret := struct {
 		Label   string `json:"label"`
@@ -225,7 +255,7 @@ func (gt *Gate) SpotPlaceOrder(symbol, clientId string,
 		return "", errors.New(gt.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Label != "" {
-		return "", errors.New(gt.Name() + " request fail! err=" + ret.Msg)
+		return "", gt.apiError(httpCode, ret.Label, ret.Msg)
 	}
 
 	if len(ret.OrderId) == 0 {
@@ -234,6 +264,166 @@ func (gt *Gate) SpotPlaceOrder(symbol, clientId string,
 
 	return ret.OrderId, nil
 }
+func (gt *Gate) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	path := "/api/v4/spot/batch_orders"
+	url := gtUniEndpoint + path
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 10 { // 每批最多10个
+		end := min(start+10, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			o := orders[i]
+			results[i].ClientId = o.ClientId
+			if i > start {
+				payload += ","
+			}
+			payload += `{"currency_pair":"` + gt.getSpotSymbol(o.Symbol) + `"`
+			if o.ClientId != "" {
+				payload += `,"text":"t-` + o.ClientId + `"`
+			}
+			if o.Type == "LIMIT" {
+				payload += `,"price":"` + o.Price.String() + `"`
+			} else if o.Type == "MARKET" && o.Side == "BUY" {
+				o.Qty = o.Amt
+			}
+			if o.TimeInForce != "" {
+				payload += `,"time_in_force":"` + gt.fromStdTimeInForce(o.TimeInForce) + `"`
+			} else if o.Type == "MARKET" {
+				payload += `,"time_in_force":"ioc"`
+			}
+			payload += "" +
+				`,"amount":"` + o.Qty.String() + `"` +
+				`,"side":"` + gt.fromStdSide(o.Side) + `"` +
+				`,"type":"` + gt.fromStdOrderType(o.Type) + `"` +
+				`}`
+		}
+		payload += "]"
+		headers := gt.buildHeaders("POST", path, "", payload)
+		retCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+		gt.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (gt *Gate) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	path := "/api/v4/spot/cancel_batch_orders"
+	url := gtUniEndpoint + path
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			results[i].OrderId = orders[i].OrderId
+			results[i].ClientId = orders[i].ClientId
+			orderId := orders[i].OrderId
+			if orderId == "" && orders[i].ClientId != "" {
+				orderId = "t-" + orders[i].ClientId
+			}
+			if i > start {
+				payload += ","
+			}
+			payload += `{"currency_pair":"` + gt.getSpotSymbol(orders[i].Symbol) + `"` +
+				`,"id":"` + orderId + `"}`
+		}
+		payload += "]"
+		headers := gt.buildHeaders("POST", path, "", payload)
+		retCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+		gt.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+
+// 返回与请求顺序一致, 以succeeded为准
+func (gt *Gate) handleBatchResp(results []BatchOrderResult, retCode int, resp []byte, err error) {
+	if err != nil {
+		err = newNetError(gt.Name(), err)
+	} else if len(resp) > 0 && resp[0] == '{' {
+		err = gt.handleExceptionResp(retCode, "batch orders", resp)
+	}
+	ret := []struct {
+		Succeeded bool            `json:"succeeded"`
+		Label     string          `json:"label"`
+		Msg       string          `json:"message"`
+		OrderId   json.RawMessage `json:"id"` // 合约下单返回的是数字
+		ClientId  string          `json:"text"`
+	}{}
+	if err == nil {
+		if e := json.Unmarshal(resp, &ret); e != nil {
+			err = errors.New(gt.Name() + " unmarshal fail! " + e.Error())
+		} else if len(ret) != len(results) {
+			err = errors.New(gt.Name() + " batch resp size not match! " + string(resp))
+		}
+	}
+	for i := range results {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if !ret[i].Succeeded {
+			results[i].Err = gt.apiError(retCode, ret[i].Label, ret[i].Msg)
+			continue
+		}
+		orderId := strings.Trim(string(ret[i].OrderId), `"`)
+		if orderId != "" && orderId != "null" && !strings.HasPrefix(orderId, "t-") {
+			results[i].OrderId = orderId
+		}
+		if len(ret[i].ClientId) > 2 && strings.HasPrefix(ret[i].ClientId, "t-") {
+			results[i].ClientId = ret[i].ClientId[2:]
+		}
+	}
+}
+func (gt *Gate) SpotCancelAllOrders(symbol string) error {
+	path := "/api/v4/spot/orders"
+	params := ""
+	if symbol != "" {
+		params = "currency_pair=" + gt.getSpotSymbol(symbol)
+	}
+	headers := gt.buildHeaders("DELETE", path, params, "")
+	url := gtUniEndpoint + path
+	if params != "" {
+		url += "?" + params
+	}
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	if len(resp) > 0 && resp[0] == '{' {
+		return gt.handleExceptionResp(httpCode, "SpotCancelAllOrders", resp)
+	}
+	return nil
+}
+func (gt *Gate) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	path := "/api/v4/spot/countdown_cancel_all"
+	payload := `{"timeout":` + strconv.Itoa(timeout)
+	if typ == "SPOT" {
+		if symbol != "" {
+			payload += `,"currency_pair":"` + gt.getSpotSymbol(symbol) + `"`
+		}
+	} else if gt.FuturesSupported(typ) {
+		path = "/api/v4/futures/" + gt.futuresSettle(typ) + "/countdown_cancel_all"
+		if symbol != "" {
+			payload += `,"contract":"` + gt.getContractSymbol(symbol) + `"`
+		}
+	} else {
+		return newNotSupportError(gt.Name())
+	}
+	payload += `}`
+	headers := gt.buildHeaders("POST", path, "", payload)
+	httpCode, resp, err := gt.Post(gtUniEndpoint+path, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return nil
+}
 func (gt *Gate) SpotCancelOrder(symbol string, orderId, cltId string) error {
 	symbolS := gt.getSpotSymbol(symbol)
 	if orderId == "" && cltId != "" {
@@ -243,9 +433,9 @@ func (gt *Gate) SpotCancelOrder(symbol string, orderId, cltId string) error {
 	params := "currency_pair=" + symbolS
 	headers := gt.buildHeaders("DELETE", path, params, "")
 	url := gtUniEndpoint + path + "?" + params
-	_, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
 	if err != nil {
-		return errors.New(gt.Name() + " net error! " + err.Error())
+		return newNetError(gt.Name(), err)
 	}
 
 	ret := struct {
@@ -259,7 +449,7 @@ func (gt *Gate) SpotCancelOrder(symbol string, orderId, cltId string) error {
 		return errors.New(gt.Name() + " unmarshal fail! " + err.Error() + string(resp))
 	}
 	if ret.Label != "" {
-		return errors.New(gt.Name() + " request fail! " + ret.Msg)
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
 	}
 
 	if gt.toStdOrderStatus(ret.Status) != "CANCELED" {
@@ -267,6 +457,72 @@ func (gt *Gate) SpotCancelOrder(symbol string, orderId, cltId string) error {
 	}
 	return nil
 }
+func (gt *Gate) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	symbolS := gt.getSpotSymbol(symbol)
+	if orderId == "" && cltId != "" {
+		orderId = "t-" + cltId
+	}
+	path := "/api/v4/spot/orders/" + orderId
+	params := "currency_pair=" + symbolS
+	payload := `{"currency_pair":"` + symbolS + `","amount":"` + qty.String() +
+		`","price":"` + price.String() + `"}`
+	headers := gt.buildHeaders("PATCH", path, params, payload)
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Patch(url, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	order := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+
+		OrderId      string          `json:"id"`
+		ClientId     string          `json:"text"`
+		Price        decimal.Decimal `json:"price"`
+		Qty          decimal.Decimal `json:"amount"`
+		ExecutedQty  decimal.Decimal `json:"filled_amount"`
+		CummQuoteQty decimal.Decimal `json:"filled_total"`
+		Left         decimal.Decimal `json:"left"`
+		Status       string          `json:"status"`
+		Type         string          `json:"type"`
+		TimeInForce  string          `json:"time_in_force"` // GTC/FOK/IOC
+		Side         string          `json:"side"`
+		Time         int64           `json:"create_time_ms"`
+		UTime        int64           `json:"update_time_ms"`
+	}{}
+	err = json.Unmarshal(resp, &order)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if order.Label != "" {
+		return nil, gt.apiError(httpCode, order.Label, order.Msg)
+	}
+
+	clientId := ""
+	idx := strings.Index(order.ClientId, "t-")
+	if idx != -1 && len(order.ClientId) > 2 {
+		clientId = order.ClientId[2:]
+	}
+	if order.Status == "open" && order.Left.IsPositive() && order.Qty.GreaterThan(order.Left) {
+		order.Status = "partially_filled"
+	}
+	return &SpotOrder{
+		Symbol:      symbol,
+		OrderId:     order.OrderId,
+		ClientId:    clientId,
+		Price:       order.Price,
+		Qty:         order.Qty,
+		FilledQty:   order.ExecutedQty,
+		FilledAmt:   order.CummQuoteQty,
+		Status:      gt.toStdOrderStatus(order.Status),
+		Type:        gt.toStdOrderType(order.Type),
+		TimeInForce: gt.toStdTimeInForce(order.TimeInForce),
+		Side:        gt.toStdSide(order.Side),
+		CTime:       order.Time,
+		UTime:       order.UTime,
+	}, nil
+}
 func (gt *Gate) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	symbolS := gt.getSpotSymbol(symbol)
 	if orderId == "" && cltId != "" {
@@ -276,9 +532,9 @@ func (gt *Gate) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 	params := "currency_pair=" + symbolS
 	headers := gt.buildHeaders("GET", path, params, "")
 	url := gtUniEndpoint + path + "?" + params
-	_, resp, err := gt.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	order := struct {
 		Label string `json:"label"`
@@ -309,7 +565,7 @@ func (gt *Gate) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
 	}
 	if order.Label != "" {
-		return nil, errors.New(gt.Name() + " resp err! " + order.Msg)
+		return nil, gt.apiError(httpCode, order.Label, order.Msg)
 	}
 
 	clientId := ""
@@ -338,7 +594,7 @@ func (gt *Gate) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error)
 		}
 	}
 	if order.Status == "" {
-		ilog.Error(gt.Name() + " unknow s status" + string(resp))
+		ilog.Error("%s", gt.Name()+" unknow s status"+string(resp))
 	}
 	return &SpotOrder{
 		Symbol:      symbol,
@@ -362,12 +618,12 @@ func (gt *Gate) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	path := "/api/v4/spot/open_orders"
 	url := gtUniEndpoint + path
 	headers := gt.buildHeaders("GET", path, "", "")
-	_, resp, err := gt.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, gt.handleExceptionResp("SpotGetOpenOrders", resp)
+		return nil, gt.handleExceptionResp(httpCode, "SpotGetOpenOrders", resp)
 	}
 	orders := []struct {
 		L []struct {
@@ -426,7 +682,7 @@ func (gt *Gate) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 				}
 			}
 			if order.Status == "" {
-				ilog.Error(gt.Name() + " unknow s status" + string(resp))
+				ilog.Error("%s", gt.Name()+" unknow s status"+string(resp))
 			}
 			so := SpotOrder{
 				Symbol:      strings.ReplaceAll(order.Symbol, "_", ""),
@@ -450,3 +706,195 @@ func (gt *Gate) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	}
 	return dl, nil
 }
+
+// gate 不支持6h,12h, limit 不能和from/to同时使用, 一次最多1000根
+
+// 用price_orders实现, 不支持cltId, 市价买单qty为计价币数量
+// 止损/止盈由触发方向区分(rule: >= 或 <=)
+func (gt *Gate) SpotPlaceConditionalOrder(symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string) (string, error) {
+	isStop, isLimit, ok := parseConditionalOrderType(orderType)
+	if !ok {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	if triggerBy != "" && triggerBy != "MARKET_PRICE" {
+		return "", errors.New(gt.Name() + " spot not support trigger by " + triggerBy)
+	}
+	rule := "<="
+	if conditionalTriggerRise(isStop, side) {
+		rule = ">="
+	}
+	put := `"type":"market","time_in_force":"ioc","price":"` + triggerPrice.String() + `"`
+	if isLimit {
+		put = `"type":"limit","time_in_force":"gtc","price":"` + price.String() + `"`
+	}
+	path := "/api/v4/spot/price_orders"
+	url := gtUniEndpoint + path
+	payload := `{"market":"` + gt.getSpotSymbol(symbol) + `"` +
+		`,"trigger":{"price":"` + triggerPrice.String() + `","rule":"` + rule + `","expiration":2592000}` +
+		`,"put":{` + put +
+		`,"side":"` + gt.fromStdSide(side) + `"` +
+		`,"amount":"` + qty.String() + `"` +
+		`,"account":"normal"}` +
+		`}`
+	headers := gt.buildHeaders("POST", path, "", payload)
+	httpCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label   string `json:"label"`
+		Msg     string `json:"message"`
+		OrderId int64  `json:"id"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return "", gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return strconv.FormatInt(ret.OrderId, 10), nil
+}
+func (gt *Gate) SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error) {
+	path := "/api/v4/spot/price_orders"
+	params := "status=open"
+	if symbol != "" {
+		params += "&market=" + gt.getSpotSymbol(symbol)
+	}
+	url := gtUniEndpoint + path + "?" + params
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "SpotGetOpenConditionalOrders", resp)
+	}
+	orders := []struct {
+		OrderId int64  `json:"id"`
+		Symbol  string `json:"market"`
+		Trigger struct {
+			Price decimal.Decimal `json:"price"`
+			Rule  string          `json:"rule"`
+		} `json:"trigger"`
+		Put struct {
+			Type   string          `json:"type"`
+			Side   string          `json:"side"`
+			Price  decimal.Decimal `json:"price"`
+			Amount decimal.Decimal `json:"amount"`
+		} `json:"put"`
+		Status string `json:"status"`
+		CTime  int64  `json:"ctime"` // sec
+	}{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	oL := make([]*ConditionalOrder, 0, len(orders))
+	for _, order := range orders {
+		co := &ConditionalOrder{
+			Symbol:       strings.ReplaceAll(order.Symbol, "_", ""),
+			OrderId:      strconv.FormatInt(order.OrderId, 10),
+			Side:         gt.toStdSide(order.Put.Side),
+			TriggerPrice: order.Trigger.Price,
+			TriggerBy:    "MARKET_PRICE",
+			Qty:          order.Put.Amount,
+			Status:       "NEW",
+			CTime:        order.CTime * 1000,
+		}
+		if (order.Trigger.Rule == ">=") == conditionalTriggerRise(true, co.Side) {
+			co.Type = "STOP"
+		} else {
+			co.Type = "TAKE_PROFIT"
+		}
+		if order.Put.Type == "limit" {
+			co.Type += "_LIMIT"
+			co.Price = order.Put.Price
+		} else {
+			co.Type += "_MARKET"
+		}
+		oL = append(oL, co)
+	}
+	return oL, nil
+}
+func (gt *Gate) SpotCancelConditionalOrder(symbol, orderId string) error {
+	path := "/api/v4/spot/price_orders/" + orderId
+	headers := gt.buildHeaders("DELETE", path, "", "")
+	url := gtUniEndpoint + path
+	httpCode, resp, err := gt.Delete(url, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label  string `json:"label"`
+		Msg    string `json:"message"`
+		Status string `json:"status"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error() + string(resp))
+	}
+	if ret.Label != "" {
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return nil
+}
+func (gt *Gate) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	switch interval {
+	case "1m", "5m", "15m", "30m", "1h", "4h", "1d":
+	default:
+		return nil, errors.New(gt.Name() + " not support interval " + interval)
+	}
+	path := "/api/v4/spot/candlesticks?currency_pair=" + gt.getSpotSymbol(symbol) + "&interval=" + interval
+	if startTime > 0 {
+		path += "&from=" + strconv.FormatInt(startTime, 10)
+		if end := klineEndTime(interval, startTime, endTime, limit); end > 0 {
+			path += "&to=" + strconv.FormatInt(end-1, 10)
+		}
+	} else if endTime > 0 {
+		path += "&from=" + strconv.FormatInt(endTime-limit*klineIntervalSeconds(interval), 10) +
+			"&to=" + strconv.FormatInt(endTime-1, 10)
+	} else {
+		path += "&limit=" + strconv.FormatInt(limit, 10)
+	}
+	url := gtUniEndpoint + path
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		ret := struct {
+			Label string `json:"label"`
+			Msg   string `json:"message"`
+		}{}
+		if err = json.Unmarshal(resp, &ret); err != nil {
+			return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+		}
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	// [时间(秒), 成交额, 收盘价, 最高价, 最低价, 开盘价, 成交量, 是否结束]
+	var klines [][]any
+	if err = json.Unmarshal(resp, &klines); err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make([]KLine, 0, len(klines))
+	for _, v := range klines {
+		if len(v) < 7 {
+			continue
+		}
+		kl := KLine{}
+		ts, _ := v[0].(string)
+		kl.OpenTime, _ = strconv.ParseInt(ts, 10, 64)
+		kl.QuoteVolume, _ = decimal.NewFromString(v[1].(string))
+		kl.ClosePrice, _ = decimal.NewFromString(v[2].(string))
+		kl.HighPrice, _ = decimal.NewFromString(v[3].(string))
+		kl.LowPrice, _ = decimal.NewFromString(v[4].(string))
+		kl.OpenPrice, _ = decimal.NewFromString(v[5].(string))
+		kl.Volume, _ = decimal.NewFromString(v[6].(string))
+
+		all = append(all, kl)
+	}
+	return all, nil
+}
@@ -18,9 +18,10 @@ import (
 )
 
 var (
-	gtWsPubMsgPool             sync.Pool
-	gtSpotWsPublicBBOInnerPool sync.Pool
-	gtWsPrivMsgPool            sync.Pool
+	gtWsPubMsgPool               sync.Pool
+	gtSpotWsPublicBBOInnerPool   sync.Pool
+	gtSpotWsPublicKLineInnerPool sync.Pool
+	gtWsPrivMsgPool              sync.Pool
 )
 
 func init() {
@@ -34,6 +35,11 @@ func init() {
 			return &GateSpotBBO{}
 		},
 	}
+	gtSpotWsPublicKLineInnerPool = sync.Pool{
+		New: func() any {
+			return &GateSpotKLine{}
+		},
+	}
 	gtWsPrivMsgPool = sync.Pool{
 		New: func() any {
 			return &GtWsPrivMsg{}
@@ -41,7 +47,7 @@ func init() {
 	}
 }
 func (gt *Gate) SpotWsPublicOpen() error {
-	url := "wss://api.gateio.ws/ws/v4/"
+	url := gt.wsUrl("wss://api.gateio.ws/ws/v4/")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -89,6 +95,23 @@ func (gt *Gate) SpotWsPublicSubscribe(channels []string) {
 					gt.spotWsPublicConnMtx.Unlock()
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			arg.Channel = "spot.order_book_update"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, level := parseOrderBookSymbol(v)
+				if symbol := gt.getSpotSymbol(sym); symbol != "" {
+					gt.spotWsOrderBooks.add(sym, level)
+					arg.Payload = []string{symbol, "100ms"}
+					req, _ := json.Marshal(&arg)
+					gt.spotWsPublicConnMtx.Lock()
+					gt.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+					gt.spotWsPublicConnMtx.Unlock()
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolList := gt.parseSymbols(arr[1])
@@ -113,6 +136,22 @@ func (gt *Gate) SpotWsPublicSubscribe(channels []string) {
 					gt.spotWsPublicConnMtx.Unlock()
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			arg.Channel = "spot.candlesticks"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, interval := parseKLineSymbol(v)
+				if symbol := gt.getSpotSymbol(sym); symbol != "" {
+					arg.Payload = []string{interval, symbol}
+					req, _ := json.Marshal(&arg)
+					gt.spotWsPublicConnMtx.Lock()
+					gt.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+					gt.spotWsPublicConnMtx.Unlock()
+				}
+			}
 		}
 	}
 }
@@ -139,6 +178,23 @@ func (gt *Gate) SpotWsPublicUnsubscribe(channels []string) {
 					gt.spotWsPublicConnMtx.Unlock()
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			arg.Channel = "spot.order_book_update"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, _ := parseOrderBookSymbol(v)
+				if symbol := gt.getSpotSymbol(sym); symbol != "" {
+					gt.spotWsOrderBooks.remove(sym)
+					arg.Payload = []string{symbol, "100ms"}
+					req, _ := json.Marshal(&arg)
+					gt.spotWsPublicConnMtx.Lock()
+					gt.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+					gt.spotWsPublicConnMtx.Unlock()
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) > 1 && len(arr[1]) > 0 {
 				symbolList := gt.parseSymbols(arr[1])
@@ -163,6 +219,22 @@ func (gt *Gate) SpotWsPublicUnsubscribe(channels []string) {
 					gt.spotWsPublicConnMtx.Unlock()
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			arg.Channel = "spot.candlesticks"
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, interval := parseKLineSymbol(v)
+				if symbol := gt.getSpotSymbol(sym); symbol != "" {
+					arg.Payload = []string{interval, symbol}
+					req, _ := json.Marshal(&arg)
+					gt.spotWsPublicConnMtx.Lock()
+					gt.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+					gt.spotWsPublicConnMtx.Unlock()
+				}
+			}
 		}
 	}
 }
@@ -172,6 +244,9 @@ func (gt *Gate) SpotWsPublicTickerPoolPut(v any) {
 func (gt *Gate) SpotWsPublicOrderBook5PoolPut(v any) {
 	wsPublicOrderBook5Pool.Put(v)
 }
+func (gt *Gate) SpotWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (gt *Gate) SpotWsPublicLoop(ch chan<- any) {
 	defer gt.SpotWsPublicClose()
 	defer close(ch)
@@ -204,14 +279,14 @@ func (gt *Gate) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := gt.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !gt.SpotWsPublicIsClosed() {
-				ilog.Warning(gt.Name() + " spot.ws.public channel read: " + err.Error())
+				ilog.Warning("%s", gt.Name()+" spot.ws.public channel read: "+err.Error())
 			}
 			break
 		}
 		msg := gtWsPubMsgPool.Get().(*GateWsSpotPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(gt.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", gt.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
 
@@ -223,14 +298,22 @@ func (gt *Gate) SpotWsPublicLoop(ch chan<- any) {
 			if msg.Event == "update" {
 				gt.spotWsHandleOrderBook(msg.Data, ch)
 			}
+		} else if msg.Channel == "spot.order_book_update" {
+			if msg.Event == "update" {
+				gt.spotWsHandleOrderBookUpdate(msg.Data, ch)
+			}
 		} else if msg.Channel == "spot.tickers" {
 			if msg.Event == "update" {
 				gt.spotWsHandle24hTickers(msg.Data, ch)
 			}
+		} else if msg.Channel == "spot.candlesticks" {
+			if msg.Event == "update" {
+				gt.spotWsHandleKLine(msg.Data, ch)
+			}
 		} else if msg.Channel == "spot.pong" {
 			gt.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
 		} else {
-			ilog.Error(gt.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+			ilog.Error("%s", gt.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 		}
 	END:
 		gtWsPubMsgPool.Put(msg)
@@ -257,7 +340,7 @@ func (gt *Gate) spotWsHandleOrderBook(data json.RawMessage, ch chan<- any) {
 	depth.Asks = depth.Asks[:0]
 	if err := easyjson.Unmarshal(data, depth); err == nil {
 		if len(depth.Bids) != len(depth.Asks) {
-			ilog.Error(gt.Name() + " spot.ws.public " + depth.Symbol + " orderbook exception")
+			ilog.Error("%s", gt.Name()+" spot.ws.public "+depth.Symbol+" orderbook exception")
 			return
 		}
 		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
@@ -277,6 +360,45 @@ func (gt *Gate) spotWsHandleOrderBook(data json.RawMessage, ch chan<- any) {
 		ch <- obd
 	}
 }
+
+// 增量深度 + rest快照同步, U/u 必须连续, 否则重新拉快照
+func (gt *Gate) spotWsHandleOrderBookUpdate(data json.RawMessage, ch chan<- any) {
+	depth := struct {
+		Time    int64       `json:"t"`
+		Symbol  string      `json:"s"`
+		FirstId int64       `json:"U"`
+		LastId  int64       `json:"u"`
+		Bids    [][2]string `json:"b"`
+		Asks    [][2]string `json:"a"`
+	}{}
+	if err := json.Unmarshal(data, &depth); err != nil {
+		ilog.Error("%s", gt.Name()+" spot.ws.public orderbook exception: "+string(data))
+		return
+	}
+	symbol := strings.ReplaceAll(depth.Symbol, "_", "")
+	ob := gt.spotWsOrderBooks.get(symbol)
+	if ob == nil {
+		return
+	}
+	d := &obDiff{
+		firstId: depth.FirstId,
+		lastId:  depth.LastId,
+		time:    depth.Time,
+		bids:    depth.Bids,
+		asks:    depth.Asks,
+	}
+	updated := ob.applyDiff(d)
+	synced, err := ob.pollSnapshot()
+	if err != nil {
+		ilog.Warning("%s", gt.Name()+" spot.ws.public "+symbol+" get orderbook snapshot: "+err.Error())
+	}
+	if updated || synced {
+		ch <- ob.snapshot()
+	}
+	ob.fetchSnapshot(func() (int64, [][2]string, [][2]string, error) {
+		return gt.spotGetOrderBook(depth.Symbol, ob.level)
+	})
+}
 func (gt *Gate) spotWsHandleBBO(data json.RawMessage, ch chan<- any) {
 	bbo := gtSpotWsPublicBBOInnerPool.Get().(*GateSpotBBO)
 	defer gtSpotWsPublicBBOInnerPool.Put(bbo)
@@ -303,13 +425,32 @@ func (gt *Gate) spotWsHandle24hTickers(data json.RawMessage, ch chan<- any) {
 		ch <- tk
 	}
 }
+func (gt *Gate) spotWsHandleKLine(data json.RawMessage, ch chan<- any) {
+	kl := gtSpotWsPublicKLineInnerPool.Get().(*GateSpotKLine)
+	defer gtSpotWsPublicKLineInnerPool.Put(kl)
+	if err := easyjson.Unmarshal(data, kl); err == nil {
+		interval, symbol, _ := strings.Cut(kl.Name, "_")
+		v := wsPublicKLinePool.Get().(*KLine)
+		v.Symbol = strings.ReplaceAll(symbol, "_", "")
+		v.Interval = interval
+		v.OpenTime = kl.OpenTime
+		v.OpenPrice = kl.Open
+		v.HighPrice = kl.High
+		v.LowPrice = kl.Low
+		v.ClosePrice = kl.Close
+		v.Volume = kl.Volume
+		v.QuoteVolume = kl.QuoteVolume
+		v.IsClosed = kl.IsClosed
+		ch <- v
+	}
+}
 
 // = priv channel
 func (gt *Gate) SpotWsPrivateSupported() bool {
 	return true
 }
 func (gt *Gate) SpotWsPrivateOpen() error {
-	url := "wss://api.gateio.ws/ws/v4/"
+	url := gt.wsUrl("wss://api.gateio.ws/ws/v4/")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -383,7 +524,7 @@ func (gt *Gate) SpotWsPrivateSubscribe(channels []string) {
 			req, _ := json.Marshal(&arg)
 			gt.spotWsPrivateConnMtx.Lock()
 			if err := gt.spotWsPrivateConn.WriteMessage(websocket.TextMessage, req); err != nil {
-				ilog.Warning(gt.Name() + " spot.ws.priv subscribe net error! " + err.Error())
+				ilog.Warning("%s", gt.Name()+" spot.ws.priv subscribe net error! "+err.Error())
 			}
 			gt.spotWsPrivateConnMtx.Unlock()
 		} else if c == "balance" {
@@ -397,7 +538,7 @@ func (gt *Gate) SpotWsPrivateSubscribe(channels []string) {
 			req, _ := json.Marshal(&arg)
 			gt.spotWsPrivateConnMtx.Lock()
 			if err := gt.spotWsPrivateConn.WriteMessage(websocket.TextMessage, req); err != nil {
-				ilog.Warning(gt.Name() + " spot.ws.priv subscribe net error! " + err.Error())
+				ilog.Warning("%s", gt.Name()+" spot.ws.priv subscribe net error! "+err.Error())
 			}
 			gt.spotWsPrivateConnMtx.Unlock()
 		}
@@ -482,29 +623,30 @@ func (gt *Gate) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := gt.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !gt.SpotWsPrivateIsClosed() {
-				ilog.Warning(gt.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", gt.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if gt.debug {
-			ilog.Rinfo(gt.Name() + " spot priv ws: " + string(recv))
+			ilog.Rinfo("%s", gt.Name()+" spot priv ws: "+string(recv))
 		}
 		msg := gtWsPrivMsgPool.Get().(*GtWsPrivMsg)
 		msg.reset()
 		if err = json.Unmarshal(recv, msg); err != nil {
-			ilog.Error(gt.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", gt.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.RequestId != "" { // ws api
 			if msg.Ack != true { // ack 忽略
-				if msg.Header.Channel == "spot.order_place" {
+				if msg.Header.Channel == "spot.order_place" ||
+					msg.Header.Channel == "spot.order_amend" {
 					gt.spotWsHandlePlaceOrderResp(msg.RequestId,
 						msg.RespData.Errs.Message, msg.RespData.Result, ch)
 				} else if msg.Header.Channel == "spot.order_cancel" {
 					gt.spotWsHandleCancelOrderResp(msg.RespData.Errs.Message)
 				} else if msg.Header.Channel == "spot.login" {
 					if msg.Header.Status != "200" {
-						ilog.Error(gt.Name() + " spot.ws.priv login fail: " + msg.RespData.Errs.Message)
+						ilog.Error("%s", gt.Name()+" spot.ws.priv login fail: "+msg.RespData.Errs.Message)
 						gtWsPrivMsgPool.Put(msg)
 						break // exit
 					}
@@ -522,7 +664,7 @@ func (gt *Gate) SpotWsPrivateLoop(ch chan<- any) {
 			} else if msg.Channel == "spot.pong" {
 				gt.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
 			} else {
-				ilog.Error(gt.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+				ilog.Error("%s", gt.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 			}
 		}
 	END:
@@ -579,7 +721,7 @@ func (gt *Gate) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
 				}
 			}
 			if order.Status == "" {
-				ilog.Error(gt.Name() + " unknow s status" + string(data))
+				ilog.Error("%s", gt.Name()+" unknow s status"+string(data))
 			}
 			t, _ := strconv.ParseInt(order.Time, 10, 64)
 			ut, _ := strconv.ParseInt(order.UTime, 10, 64)
@@ -637,7 +779,7 @@ func (gt *Gate) spotWsHandlePlaceOrderResp(reqId, errS string,
 		ClientId string `json:"text,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &ret); err != nil {
-		ilog.Error(gt.Name() + " spot.ws.priv handle place order resp: " + err.Error())
+		ilog.Error("%s", gt.Name()+" spot.ws.priv handle place order resp: "+err.Error())
 		return
 	}
 	clientId := ""
@@ -653,7 +795,7 @@ func (gt *Gate) spotWsHandlePlaceOrderResp(reqId, errS string,
 }
 func (gt *Gate) spotWsHandleCancelOrderResp(errS string) {
 	if errS != "" {
-		ilog.Error(gt.Name() + " spot cancel order fail! " + errS)
+		ilog.Error("%s", gt.Name()+" spot cancel order fail! "+errS)
 	}
 }
 
@@ -763,3 +905,51 @@ func (gt *Gate) SpotWsCancelOrder(symbol, orderId, cltId string) (string, error)
 	}
 	return req.Payload.ReqId, nil
 }
+func (gt *Gate) SpotWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	if gt.SpotWsPrivateIsClosed() {
+		return "", errors.New(gt.Name() + " spot priv ws closed")
+	}
+	if orderId == "" && cltId != "" {
+		orderId = "t-" + cltId
+	}
+	type Param struct {
+		OrderId string `json:"order_id"`
+		Symbol  string `json:"currency_pair"`
+		Price   string `json:"price"`
+		Qty     string `json:"amount"`
+	}
+	type Payload struct {
+		ReqId    string `json:"req_id"`
+		ReqParam Param  `json:"req_param"`
+	}
+	type Req struct {
+		Time    int64   `json:"time"`
+		Channel string  `json:"channel"`
+		Event   string  `json:"event"`
+		Payload Payload `json:"payload"`
+	}
+	symbolS := gt.getSpotSymbol(symbol)
+	req := &Req{
+		Time:    time.Now().Unix(),
+		Channel: "spot.order_amend",
+		Event:   "api",
+		Payload: Payload{
+			ReqId: gutils.RandomStr(16),
+			ReqParam: Param{
+				OrderId: orderId,
+				Symbol:  symbolS,
+				Price:   price.String(),
+				Qty:     qty.String(),
+			},
+		},
+	}
+	reqJson, _ := json.Marshal(req)
+
+	gt.spotWsPrivateConnMtx.Lock()
+	defer gt.spotWsPrivateConnMtx.Unlock()
+	if err := gt.spotWsPrivateConn.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(gt.Name() + " send fail: " + err.Error())
+	}
+	return req.Payload.ReqId, nil
+}
@@ -44,11 +44,30 @@ type GateSpotBBO struct {
 	Time     int64           `json:"t"`
 }
 
+type GateSpotKLine struct {
+	OpenTime    int64           `json:"t,string"` // sec
+	Name        string          `json:"n"`        // 1m_BTC_USDT
+	Open        decimal.Decimal `json:"o"`
+	High        decimal.Decimal `json:"h"`
+	Low         decimal.Decimal `json:"l"`
+	Close       decimal.Decimal `json:"c"`
+	Volume      decimal.Decimal `json:"a"` // 基础币种成交量
+	QuoteVolume decimal.Decimal `json:"v"` // 计价币种成交额
+	IsClosed    bool            `json:"w"`
+}
+
 type GateWsContractPubMsg struct {
 	Channel string          `json:"channel,omitempty"`
 	Event   string          `json:"event,omitempty"`
 	Data    json.RawMessage `json:"result,omitempty"`
 }
+
+func (v *GateWsContractPubMsg) reset() {
+	v.Channel = ""
+	v.Event = ""
+	v.Data = nil
+}
+
 type GateSpot24hTicker struct {
 	Symbol      string          `json:"currency_pair"`
 	Last        decimal.Decimal `json:"last"`
@@ -67,3 +86,22 @@ type GateFundingRate struct {
 	Fr       decimal.Decimal `json:"funding_rate"`
 	Offline  bool            `json:"in_delisting,omitempty"` // 下线过渡期
 }
+
+// size/left 为张数, 正数买负数卖
+type GateFuturesOrder struct {
+	Label string `json:"label,omitempty"`
+	Msg   string `json:"message,omitempty"`
+
+	OrderId     int64           `json:"id"`
+	Symbol      string          `json:"contract"`
+	ClientId    string          `json:"text"`
+	Size        int64           `json:"size"`
+	Left        int64           `json:"left"`
+	Price       decimal.Decimal `json:"price"`
+	AvgPrice    decimal.Decimal `json:"fill_price"`
+	Status      string          `json:"status"`    // open/finished
+	FinishAs    string          `json:"finish_as"` // filled/cancelled/ioc/...
+	TimeInForce string          `json:"tif"`
+	CTime       decimal.Decimal `json:"create_time"` // sec
+	FTime       decimal.Decimal `json:"finish_time"` // sec
+}
@@ -440,7 +440,172 @@ func (v *GateSpotOrderBook) UnmarshalJSON(data []byte) error {
 func (v *GateSpotOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC89930e1DecodeGithubComShaovieCex2(l, v)
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *GateSpotBBO) {
+func easyjsonC89930e1DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *GateSpotKLine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "t":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OpenTime = int64(in.Int64Str())
+			}
+		case "n":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "o":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Open).UnmarshalJSON(data))
+				}
+			}
+		case "h":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.High).UnmarshalJSON(data))
+				}
+			}
+		case "l":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Low).UnmarshalJSON(data))
+				}
+			}
+		case "c":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Close).UnmarshalJSON(data))
+				}
+			}
+		case "a":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "v":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
+		case "w":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IsClosed = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC89930e1EncodeGithubComShaovieCex3(out *jwriter.Writer, in GateSpotKLine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"t\":"
+		out.RawString(prefix[1:])
+		out.Int64Str(int64(in.OpenTime))
+	}
+	{
+		const prefix string = ",\"n\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"o\":"
+		out.RawString(prefix)
+		out.Raw((in.Open).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"h\":"
+		out.RawString(prefix)
+		out.Raw((in.High).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"l\":"
+		out.RawString(prefix)
+		out.Raw((in.Low).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"c\":"
+		out.RawString(prefix)
+		out.Raw((in.Close).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"a\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"v\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"w\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsClosed))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GateSpotKLine) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC89930e1EncodeGithubComShaovieCex3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GateSpotKLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC89930e1EncodeGithubComShaovieCex3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GateSpotKLine) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC89930e1DecodeGithubComShaovieCex3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GateSpotKLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC89930e1DecodeGithubComShaovieCex3(l, v)
+}
+func easyjsonC89930e1DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *GateSpotBBO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -508,7 +673,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *GateSpotB
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex3(out *jwriter.Writer, in GateSpotBBO) {
+func easyjsonC89930e1EncodeGithubComShaovieCex4(out *jwriter.Writer, in GateSpotBBO) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -548,27 +713,27 @@ func easyjsonC89930e1EncodeGithubComShaovieCex3(out *jwriter.Writer, in GateSpot
 // MarshalJSON supports json.Marshaler interface
 func (v GateSpotBBO) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex3(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateSpotBBO) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex3(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateSpotBBO) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex3(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateSpotBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex3(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex4(l, v)
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *GateSpot24hTicker) {
+func easyjsonC89930e1DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *GateSpot24hTicker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -622,7 +787,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *GateSpot2
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex4(out *jwriter.Writer, in GateSpot24hTicker) {
+func easyjsonC89930e1EncodeGithubComShaovieCex5(out *jwriter.Writer, in GateSpot24hTicker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -652,27 +817,254 @@ func easyjsonC89930e1EncodeGithubComShaovieCex4(out *jwriter.Writer, in GateSpot
 // MarshalJSON supports json.Marshaler interface
 func (v GateSpot24hTicker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex4(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateSpot24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex4(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateSpot24hTicker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex4(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateSpot24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex4(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex5(l, v)
+}
+func easyjsonC89930e1DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *GateFuturesOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "label":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Label = string(in.String())
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Msg = string(in.String())
+			}
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = int64(in.Int64())
+			}
+		case "contract":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "text":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "size":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Size = int64(in.Int64())
+			}
+		case "left":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Left = int64(in.Int64())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Price).UnmarshalJSON(data))
+				}
+			}
+		case "fill_price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AvgPrice).UnmarshalJSON(data))
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "finish_as":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FinishAs = string(in.String())
+			}
+		case "tif":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TimeInForce = string(in.String())
+			}
+		case "create_time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CTime).UnmarshalJSON(data))
+				}
+			}
+		case "finish_time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FTime).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *GateFundingRate) {
+func easyjsonC89930e1EncodeGithubComShaovieCex6(out *jwriter.Writer, in GateFuturesOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Label != "" {
+		const prefix string = ",\"label\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Label))
+	}
+	if in.Msg != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Msg))
+	}
+	{
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.OrderId))
+	}
+	{
+		const prefix string = ",\"contract\":"
+		out.RawString(prefix)
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Size))
+	}
+	{
+		const prefix string = ",\"left\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Left))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.Raw((in.Price).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"fill_price\":"
+		out.RawString(prefix)
+		out.Raw((in.AvgPrice).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"finish_as\":"
+		out.RawString(prefix)
+		out.String(string(in.FinishAs))
+	}
+	{
+		const prefix string = ",\"tif\":"
+		out.RawString(prefix)
+		out.String(string(in.TimeInForce))
+	}
+	{
+		const prefix string = ",\"create_time\":"
+		out.RawString(prefix)
+		out.Raw((in.CTime).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"finish_time\":"
+		out.RawString(prefix)
+		out.Raw((in.FTime).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GateFuturesOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC89930e1EncodeGithubComShaovieCex6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GateFuturesOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC89930e1EncodeGithubComShaovieCex6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GateFuturesOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC89930e1DecodeGithubComShaovieCex6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GateFuturesOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC89930e1DecodeGithubComShaovieCex6(l, v)
+}
+func easyjsonC89930e1DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *GateFundingRate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -722,7 +1114,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *GateFundi
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex5(out *jwriter.Writer, in GateFundingRate) {
+func easyjsonC89930e1EncodeGithubComShaovieCex7(out *jwriter.Writer, in GateFundingRate) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -752,27 +1144,27 @@ func easyjsonC89930e1EncodeGithubComShaovieCex5(out *jwriter.Writer, in GateFund
 // MarshalJSON supports json.Marshaler interface
 func (v GateFundingRate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex5(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateFundingRate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex5(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateFundingRate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex5(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateFundingRate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex5(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex7(l, v)
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *GateContractOrderBookTick) {
+func easyjsonC89930e1DecodeGithubComShaovieCex8(in *jlexer.Lexer, out *GateContractOrderBookTick) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -812,7 +1204,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *GateContr
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex6(out *jwriter.Writer, in GateContractOrderBookTick) {
+func easyjsonC89930e1EncodeGithubComShaovieCex8(out *jwriter.Writer, in GateContractOrderBookTick) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -832,27 +1224,27 @@ func easyjsonC89930e1EncodeGithubComShaovieCex6(out *jwriter.Writer, in GateCont
 // MarshalJSON supports json.Marshaler interface
 func (v GateContractOrderBookTick) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex6(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateContractOrderBookTick) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex6(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateContractOrderBookTick) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex6(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateContractOrderBookTick) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex6(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex8(l, v)
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *GateContractOrderBook) {
+func easyjsonC89930e1DecodeGithubComShaovieCex9(in *jlexer.Lexer, out *GateContractOrderBook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -942,7 +1334,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *GateContr
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex7(out *jwriter.Writer, in GateContractOrderBook) {
+func easyjsonC89930e1EncodeGithubComShaovieCex9(out *jwriter.Writer, in GateContractOrderBook) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1006,27 +1398,27 @@ func easyjsonC89930e1EncodeGithubComShaovieCex7(out *jwriter.Writer, in GateCont
 // MarshalJSON supports json.Marshaler interface
 func (v GateContractOrderBook) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex7(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex9(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateContractOrderBook) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex7(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex9(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateContractOrderBook) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex7(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex9(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateContractOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex7(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex9(l, v)
 }
-func easyjsonC89930e1DecodeGithubComShaovieCex8(in *jlexer.Lexer, out *GateContract24hTicker) {
+func easyjsonC89930e1DecodeGithubComShaovieCex10(in *jlexer.Lexer, out *GateContract24hTicker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1080,7 +1472,7 @@ func easyjsonC89930e1DecodeGithubComShaovieCex8(in *jlexer.Lexer, out *GateContr
 		in.Consumed()
 	}
 }
-func easyjsonC89930e1EncodeGithubComShaovieCex8(out *jwriter.Writer, in GateContract24hTicker) {
+func easyjsonC89930e1EncodeGithubComShaovieCex10(out *jwriter.Writer, in GateContract24hTicker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1110,23 +1502,23 @@ func easyjsonC89930e1EncodeGithubComShaovieCex8(out *jwriter.Writer, in GateCont
 // MarshalJSON supports json.Marshaler interface
 func (v GateContract24hTicker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC89930e1EncodeGithubComShaovieCex8(&w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex10(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v GateContract24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC89930e1EncodeGithubComShaovieCex8(w, v)
+	easyjsonC89930e1EncodeGithubComShaovieCex10(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *GateContract24hTicker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC89930e1DecodeGithubComShaovieCex8(&r, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex10(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *GateContract24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC89930e1DecodeGithubComShaovieCex8(l, v)
+	easyjsonC89930e1DecodeGithubComShaovieCex10(l, v)
 }
@@ -0,0 +1,260 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+func (gt *Gate) SubAccountList() ([]SubAccount, error) {
+	path := "/api/v4/sub_accounts"
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err := gt.Get(gtUniEndpoint+path, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "SubAccountList", resp)
+	}
+	ret := []struct {
+		Uid    int64  `json:"user_id"`
+		Name   string `json:"login_name"`
+		Remark string `json:"remark"`
+		State  int    `json:"state"`       // 1:normal 2:locked
+		CTime  int64  `json:"create_time"` // second
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	res := make([]SubAccount, 0, len(ret))
+	for _, v := range ret {
+		sa := SubAccount{
+			Id:     strconv.FormatInt(v.Uid, 10),
+			Name:   v.Name,
+			Remark: v.Remark,
+			Status: "NORMAL",
+			CTime:  v.CTime,
+		}
+		if v.State != 1 {
+			sa.Status = "FROZEN"
+		}
+		res = append(res, sa)
+	}
+	return res, nil
+}
+func (gt *Gate) SubAccountCreate(name, remark string) (*SubAccount, error) {
+	path := "/api/v4/sub_accounts"
+	payload := `{"login_name":"` + name + `","remark":"` + remark + `"}`
+	headers := gt.buildHeaders("POST", path, "", payload)
+	httpCode, resp, err := gt.Post(gtUniEndpoint+path, []byte(payload), gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+
+		Uid    int64  `json:"user_id"`
+		Name   string `json:"login_name"`
+		Remark string `json:"remark"`
+		CTime  int64  `json:"create_time"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return &SubAccount{
+		Id:     strconv.FormatInt(ret.Uid, 10),
+		Name:   ret.Name,
+		Remark: ret.Remark,
+		Status: "NORMAL",
+		CTime:  ret.CTime,
+	}, nil
+}
+
+// 不支持 FUNDING(gate现货账户即资金账户)
+// SPOT: 母账户的子账户余额接口只有可用余额, 没有冻结部分, Total=Avail, Locked为0
+func (gt *Gate) SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error) {
+	if typ == "SPOT" {
+		return gt.subAccountGetSpotAssets(subAccount)
+	} else if typ == "UM_FUTURE" {
+		return gt.subAccountGetUMAssets(subAccount)
+	}
+	return nil, newNotSupportError(gt.Name())
+}
+func (gt *Gate) subAccountGetSpotAssets(subAccount string) (map[string]*SubAccountAsset, error) {
+	path := "/api/v4/wallet/sub_account_balances"
+	params := "sub_uid=" + subAccount
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(gtUniEndpoint+path+"?"+params, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "SubAccountGetAssets", resp)
+	}
+	ret := []struct {
+		Available map[string]decimal.Decimal `json:"available"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	assets := make(map[string]*SubAccountAsset)
+	for _, v := range ret {
+		for symbol, qty := range v.Available {
+			assets[symbol] = &SubAccountAsset{
+				Symbol: symbol,
+				Total:  qty,
+				Avail:  qty,
+			}
+		}
+	}
+	return assets, nil
+}
+func (gt *Gate) subAccountGetUMAssets(subAccount string) (map[string]*SubAccountAsset, error) {
+	path := "/api/v4/wallet/sub_account_futures_balances"
+	params := "settle=usdt&sub_uid=" + subAccount
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(gtUniEndpoint+path+"?"+params, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "SubAccountGetAssets", resp)
+	}
+	ret := []struct {
+		Available map[string]struct {
+			Total     decimal.Decimal `json:"total"`
+			Available decimal.Decimal `json:"available"`
+		} `json:"available"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	assets := make(map[string]*SubAccountAsset)
+	for _, v := range ret {
+		for settle, as := range v.Available {
+			symbol := strings.ToUpper(settle)
+			assets[symbol] = &SubAccountAsset{
+				Symbol: symbol,
+				Total:  as.Total,
+				Avail:  as.Available,
+				Locked: as.Total.Sub(as.Available),
+			}
+		}
+	}
+	return assets, nil
+}
+
+// gate 划转记录没有ID
+func (gt *Gate) SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error) {
+	path := "/api/v4/wallet/sub_account_transfers"
+	params := "limit=1000"
+	if subAccount != "" {
+		params += "&sub_uid=" + subAccount
+	}
+	if startTime > 0 {
+		params += "&from=" + strconv.FormatInt(startTime, 10)
+	}
+	if endTime > 0 {
+		params += "&to=" + strconv.FormatInt(endTime, 10)
+	}
+	headers := gt.buildHeaders("GET", path, params, "")
+	httpCode, resp, err := gt.Get(gtUniEndpoint+path+"?"+params, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "SubAccountTransferHistory", resp)
+	}
+	ret := []struct {
+		SubAccount string          `json:"sub_account"`
+		Symbol     string          `json:"currency"`
+		Qty        decimal.Decimal `json:"amount"`
+		Direction  string          `json:"direction"` // to:母转子 from:子转母
+		Status     string          `json:"status"`
+		CTime      string          `json:"timest"` // second
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	res := make([]SubAccountTransfer, 0, len(ret))
+	for _, v := range ret {
+		ctime, _ := strconv.ParseInt(v.CTime, 10, 64)
+		t := SubAccountTransfer{
+			Symbol: v.Symbol,
+			Qty:    v.Qty,
+			Status: "SUCCESS",
+			Time:   ctime,
+		}
+		if v.Status == "PENDING" {
+			t.Status = "PENDING"
+		} else if v.Status == "FAIL" || v.Status == "FAILED" {
+			t.Status = "FAILED"
+		}
+		if v.Direction == "to" {
+			t.To = v.SubAccount
+		} else {
+			t.From = v.SubAccount
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// perm 作用于spot/futures, READ 为只读
+func (gt *Gate) SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error) {
+	if perm != "READ" && perm != "TRADE" {
+		return nil, errors.New(gt.Name() + " not support perm " + perm)
+	}
+	readOnly := perm == "READ"
+	params := map[string]any{
+		"name": label,
+		"perms": []map[string]any{
+			{"name": "spot", "read_only": readOnly},
+			{"name": "futures", "read_only": readOnly},
+		},
+	}
+	if len(ips) > 0 {
+		params["ip_whitelist"] = ips
+	}
+	body, _ := json.Marshal(params)
+	path := "/api/v4/sub_accounts/" + subAccount + "/keys"
+	headers := gt.buildHeaders("POST", path, "", string(body))
+	httpCode, resp, err := gt.Post(gtUniEndpoint+path, body, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+
+		Name   string `json:"name"`
+		Key    string `json:"key"`
+		Secret string `json:"secret"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return &SubAccountApiKey{
+		ApiKey:    ret.Key,
+		SecretKey: ret.Secret,
+		Label:     ret.Name,
+		Perm:      perm,
+		Ips:       ips,
+	}, nil
+}
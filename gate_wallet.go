@@ -5,7 +5,6 @@ import (
 	"errors"
 	"strconv"
 
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -22,9 +21,9 @@ func (gt *Gate) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal
 		`,"chain":"` + chain + `"` +
 		`}`
 	headers := gt.buildHeaders("POST", path, "", payload)
-	_, resp, err := ihttp.Post(url, []byte(payload), gtApiDeadline, headers)
+	httpCode, resp, err := gt.Post(url, []byte(payload), gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	ret := struct {
 		Label string `json:"label"`
@@ -37,7 +36,7 @@ func (gt *Gate) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal
 		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Label != "" {
-		return nil, errors.New(gt.Name() + " withdrawal fail! msg=" + ret.Msg)
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
 	}
 
 	wr := &WithdrawReturn{
@@ -46,17 +45,37 @@ func (gt *Gate) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal
 	}
 	return wr, nil
 }
+func (gt *Gate) CancelWithdrawal(symbol, wid string) error {
+	path := "/api/v4/withdrawals/" + wid
+	headers := gt.buildHeaders("DELETE", path, "", "")
+	httpCode, resp, err := gt.Delete(gtUniEndpoint+path, gtApiDeadline, headers)
+	if err != nil {
+		return newNetError(gt.Name(), err)
+	}
+	ret := struct {
+		Label string `json:"label"`
+		Msg   string `json:"message"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Label != "" {
+		return gt.apiError(httpCode, ret.Label, ret.Msg)
+	}
+	return nil
+}
 func (gt *Gate) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	path := "/api/v4/wallet/withdrawals"
 	params := "currency=" + symbol
 	headers := gt.buildHeaders("GET", path, params, "")
 	url := gtUniEndpoint + path + "?" + params
-	_, resp, err := ihttp.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, gt.handleExceptionResp("GetWithdrawalHistory", resp)
+		return nil, gt.handleExceptionResp(httpCode, "GetWithdrawalHistory", resp)
 	}
 	ret := []struct {
 		Id       string          `json:"id"`
@@ -91,14 +110,66 @@ func (gt *Gate) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	}
 	return res, nil
 }
+func (gt *Gate) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	path := "/api/v4/wallet/deposits"
+	params := "currency=" + symbol + "&limit=500"
+	if startTime > 0 {
+		params += "&from=" + strconv.FormatInt(startTime, 10)
+	}
+	if endTime > 0 {
+		params += "&to=" + strconv.FormatInt(endTime, 10)
+	}
+	headers := gt.buildHeaders("GET", path, params, "")
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "GetDepositHistory", resp)
+	}
+	ret := []struct {
+		Id      string          `json:"id"`
+		Symbol  string          `json:"currency"`
+		Qty     decimal.Decimal `json:"amount"`
+		Network string          `json:"chain"`
+		Addr    string          `json:"address"`
+		Memo    string          `json:"memo"`
+		Status  string          `json:"status"`
+		Txid    string          `json:"txid"`
+		Time    string          `json:"timestamp"` // second
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+
+	res := make([]DepositResult, 0, len(ret))
+	for _, v2 := range ret {
+		ctime, _ := strconv.ParseInt(v2.Time, 10, 64)
+		a := DepositResult{
+			Id:      v2.Id,
+			Symbol:  v2.Symbol,
+			Network: v2.Network,
+			Addr:    v2.Addr,
+			Memo:    v2.Memo,
+			Txid:    v2.Txid,
+			Qty:     v2.Qty,
+			Status:  gt.toStdDepositStatus(v2.Status),
+			Time:    ctime,
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
 func (gt *Gate) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
 	path := "/api/v4/wallet/deposit_address"
 	params := "currency=" + symbol
 	headers := gt.buildHeaders("GET", path, params, "")
 	url := gtUniEndpoint + path + "?" + params
-	_, resp, err := ihttp.Get(url, gtApiDeadline, headers)
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(gt.Name() + " net error! " + err.Error())
+		return nil, newNetError(gt.Name(), err)
 	}
 	ret := struct {
 		Label string `json:"label"`
@@ -115,7 +186,7 @@ func (gt *Gate) GetDepositAddress(symbol, network string) ([]DepositAddress, err
 		return nil, errors.New(gt.Name() + " unmarshal error! " + err.Error())
 	}
 	if ret.Label != "" {
-		return nil, errors.New(gt.Name() + " request fail! err=" + ret.Msg)
+		return nil, gt.apiError(httpCode, ret.Label, ret.Msg)
 	}
 	daL := make([]DepositAddress, 0, 4)
 	for _, v := range ret.BindNetworks {
@@ -130,3 +201,115 @@ func (gt *Gate) GetDepositAddress(symbol, network string) ([]DepositAddress, err
 	}
 	return daL, nil
 }
+func (gt *Gate) GetWithdrawAddressBook(symbol string) ([]WithdrawAddress, error) {
+	if symbol == "" {
+		return nil, errors.New(gt.Name() + " symbol is empty")
+	}
+	path := "/api/v4/wallet/saved_address"
+	params := "currency=" + symbol + "&limit=200"
+	headers := gt.buildHeaders("GET", path, params, "")
+	url := gtUniEndpoint + path + "?" + params
+	httpCode, resp, err := gt.Get(url, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "GetWithdrawAddressBook", resp)
+	}
+	ret := []struct {
+		Symbol  string `json:"currency"`
+		Network string `json:"chain"`
+		Addr    string `json:"address"`
+		Memo    string `json:"tag"`
+		Name    string `json:"name"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	waL := make([]WithdrawAddress, 0, len(ret))
+	for i := range ret {
+		waL = append(waL, WithdrawAddress{
+			Symbol:  ret[i].Symbol,
+			Network: ret[i].Network,
+			Addr:    ret[i].Addr,
+			Memo:    ret[i].Memo,
+			Name:    ret[i].Name,
+		})
+	}
+	return waL, nil
+}
+
+// gate 不提供提现精度, WithdrawScale 为-1
+func (gt *Gate) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
+	path := "/api/v4/spot/currencies"
+	httpCode, resp, err := gt.Get(gtUniEndpoint+path, gtApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "GetWalletAllAssetInfo", resp)
+	}
+	currencies := []struct {
+		Symbol string `json:"currency"`
+		Chains []struct {
+			Network          string `json:"name"`
+			WithdrawDisabled bool   `json:"withdraw_disabled"`
+			WithdrawDelayed  bool   `json:"withdraw_delayed"`
+			DepositDisabled  bool   `json:"deposit_disabled"`
+		} `json:"chains"`
+	}{}
+	err = json.Unmarshal(resp, &currencies)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+
+	path = "/api/v4/wallet/withdraw_status"
+	headers := gt.buildHeaders("GET", path, "", "")
+	httpCode, resp, err = gt.Get(gtUniEndpoint+path, gtApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(gt.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, gt.handleExceptionResp(httpCode, "GetWalletAllAssetInfo", resp)
+	}
+	status := []struct {
+		Symbol              string                     `json:"currency"`
+		MinWithdrawalAmount decimal.Decimal            `json:"withdraw_amount_mini"`
+		WithdrawFees        map[string]decimal.Decimal `json:"withdraw_fix_on_chains"`
+	}{}
+	err = json.Unmarshal(resp, &status)
+	if err != nil {
+		return nil, errors.New(gt.Name() + " unmarshal fail! " + err.Error())
+	}
+	statusMap := make(map[string]int, len(status))
+	for i := range status {
+		statusMap[status[i].Symbol] = i
+	}
+
+	waiMap := make(map[string]*WalletAssetInfo)
+	for _, v := range currencies {
+		if len(v.Chains) == 0 {
+			continue
+		}
+		wai := WalletAssetInfo{
+			Symbol:       v.Symbol,
+			BindNetworks: make(map[string]*WalletAssetBindNetworkInfo),
+		}
+		idx, hasStatus := statusMap[v.Symbol]
+		for _, vv := range v.Chains {
+			wbni := WalletAssetBindNetworkInfo{
+				IsWithdrawalEnabled: !vv.WithdrawDisabled && !vv.WithdrawDelayed,
+				IsDepositEnabled:    !vv.DepositDisabled,
+				WithdrawScale:       -1,
+			}
+			if hasStatus {
+				wbni.MinWithdrawalAmount = status[idx].MinWithdrawalAmount
+				wbni.WithdrawFee = status[idx].WithdrawFees[vv.Network]
+			}
+			wai.BindNetworks[vv.Network] = &wbni
+		}
+		waiMap[v.Symbol] = &wai
+	}
+	return waiMap, nil
+}
@@ -35,7 +35,19 @@ var sharedClient = &http.Client{
 }
 
 type Http struct {
-	client *http.Client
+	client  *http.Client
+	ctx     context.Context // 由WithContext绑定, nil表示context.Background()
+	limiter *rateLimiter    // 同一交易所共享, nil表示不限频
+
+	endpoints map[string]string // Options生成的 默认base url -> 替换的base url
+	headers   map[string]string // Options指定的附加header
+}
+
+// 返回绑定ctx的副本, 共享同一个client(连接池)
+func (h *Http) withContext(ctx context.Context) Http {
+	c := *h
+	c.ctx = ctx
+	return c
 }
 
 func NewClientWithLocalIP(localIP string) (*http.Client, error) {
@@ -85,47 +97,95 @@ func (h *Http) Delete(link string, timeout time.Duration,
 	headers map[string]string) (int, []byte, error) {
 	return h.doRequest(http.MethodDelete, link, nil, timeout, headers)
 }
-func (h *Http) Put(link string, timeout time.Duration,
+func (h *Http) Put(link string, pl []byte, timeout time.Duration,
 	headers map[string]string) (int, []byte, error) {
-	return h.doRequest(http.MethodPut, link, nil, timeout, headers)
+	return h.doRequest(http.MethodPut, link, pl, timeout, headers)
+}
+func (h *Http) Patch(link string, pl []byte, timeout time.Duration,
+	headers map[string]string) (int, []byte, error) {
+	return h.doRequest(http.MethodPatch, link, pl, timeout, headers)
 }
 func (h *Http) doRequest(method, link string, pl []byte, timeout time.Duration,
 	headers map[string]string) (int, []byte, error) {
 	buffer := bytes.NewBuffer(pl)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	if len(h.endpoints) > 0 {
+		link = h.rewriteUrl(link)
+	}
+	parent := h.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	if h.limiter != nil { // 限频等待不计入本次请求的timeout
+		if u, err := url.Parse(link); err == nil {
+			if err = h.limiter.wait(parent, method, u, len(headers) > 0); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if _, ok := parent.Deadline(); ok { // 调用方指定了deadline, 以调用方为准
+		ctx, cancel = context.WithCancel(parent)
+	} else {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	}
 	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, method, link, buffer)
 	if err != nil {
 		return 0, nil, errors.New("create request failed: " +
 			link + ", err: " + err.Error())
 	}
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range requestHeadersFromContext(ctx) {
+		req.Header.Set(k, v)
+	}
 
-	resp, err := h.client.Do(req)
+	client := h.client
+	if client == nil {
+		client = sharedClient
+	}
+	resp, err := client.Do(req)
 	defer func() {
 		if resp != nil && resp.Body != nil {
 			_ = resp.Body.Close() // 忽略关闭错误（核心是确保关闭）
 		}
 	}()
 	if err != nil {
-		var timeoutErr error
+		if ctxErr := parent.Err(); ctxErr != nil { // 调用方的ctx取消或到期, 报告调用方的错误而不是默认timeout
+			category := CategoryNetwork
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				category = CategoryTimeout
+			}
+			return 0, nil, &Error{Category: category, Msg: "request " + link + ": ", err: ctxErr}
+		}
+		if errors.Is(err, context.Canceled) {
+			return 0, nil, &Error{Category: CategoryNetwork, Msg: "request canceled: " + link}
+		}
+		var timeoutErr *Error
 		if uErr, ok := err.(*url.Error); ok {
 			if netErr, ok := uErr.Err.(net.Error); ok {
 				if netErr.Timeout() {
-					timeoutErr = errors.New("request timeout: " + link +
-						", timeout: " + timeout.String())
+					timeoutErr = &Error{Category: CategoryTimeout, Msg: "request timeout: " + link +
+						", timeout: " + timeout.String()}
 				} else if netErr.Temporary() {
-					timeoutErr = errors.New("temporary error (network issue): " +
-						link + ", err: " + netErr.Error())
+					timeoutErr = &Error{Category: CategoryNetwork, Msg: "temporary error (network issue): " +
+						link + ", err: " + netErr.Error()}
 				}
 			}
 		}
 		if timeoutErr != nil {
 			return 0, nil, timeoutErr
 		}
-		return 0, nil, errors.New("request failed: " + link + ", err: " + err.Error())
+		return 0, nil, &Error{Category: CategoryNetwork, Msg: "request failed: " + link + ", err: " + err.Error()}
+	}
+
+	if h.limiter != nil {
+		h.limiter.update(req.URL, resp.StatusCode, resp.Header)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -0,0 +1,298 @@
+package cex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// HTX(原Huobi) 现货 + U本位永续(linear-swap, 只支持全仓)
+// 现货symbol: btcusdt  永续: BTC-USDT, 统一转换成 BTCUSDT
+type Htx struct {
+	Unsupported
+	Http
+	name      string
+	account   string
+	apikey    string
+	secretkey string
+	debug     bool
+
+	spotAccountId    string // 现货账户id, 下单/查余额需要
+	spotAccountIdMtx sync.Mutex
+
+	// spot websocket
+	spotWsPublicConn      *websocket.Conn
+	spotWsPublicConnMtx   sync.Mutex
+	spotWsPublicClosed    bool
+	spotWsPublicClosedMtx sync.RWMutex
+
+	spotWsPrivateConn      *websocket.Conn
+	spotWsPrivateConnMtx   sync.Mutex
+	spotWsPrivateClosed    bool
+	spotWsPrivateClosedMtx sync.RWMutex
+
+	// futures websocket
+	futuresWsPublicConn      *websocket.Conn
+	futuresWsPublicConnMtx   sync.Mutex
+	futuresWsPublicClosed    bool
+	futuresWsPublicClosedMtx sync.RWMutex
+}
+
+var (
+	htxContractSizeMap    map[string]decimal.Decimal // 合约面值, 每张合约的标的数量
+	htxContractSizeMapMtx sync.Mutex
+
+	htxLeverageMap    map[string]int // FuturesSwitchTradeMode设置的杠杆, 下单时使用
+	htxLeverageMapMtx sync.Mutex
+
+	htWsPubMsgPool  sync.Pool
+	htWsPrivMsgPool sync.Pool
+)
+
+const htSpotEndpoint = "https://api.huobi.pro"
+const htSwapEndpoint = "https://api.hbdm.com"
+const htApiDeadline = 1500 * time.Millisecond
+
+func init() {
+	htxContractSizeMap = make(map[string]decimal.Decimal)
+	htxLeverageMap = make(map[string]int)
+	htWsPubMsgPool = sync.Pool{
+		New: func() any {
+			return &HtxWsPubMsg{}
+		},
+	}
+	htWsPrivMsgPool = sync.Pool{
+		New: func() any {
+			return &HtxWsPrivMsg{}
+		},
+	}
+}
+
+func NewHtx(account, apikey, secretkey string) *Htx {
+	cexObj := &Htx{
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("htx"),
+		},
+		Unsupported: Unsupported{name: "htx"},
+		name:        "htx",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+	}
+	return cexObj
+}
+func (ht *Htx) Name() string {
+	return ht.name
+}
+func (ht *Htx) Account() string {
+	return ht.account
+}
+func (ht *Htx) ApiKey() string {
+	return ht.apikey
+}
+func (ht *Htx) Debug(v bool) {
+	ht.debug = v
+}
+func (ht *Htx) withContext(ctx context.Context) Exchanger {
+	cexObj := &Htx{
+		Http:      ht.Http.withContext(ctx),
+		name:      ht.name,
+		account:   ht.account,
+		apikey:    ht.apikey,
+		secretkey: ht.secretkey,
+		debug:     ht.debug,
+	}
+	cexObj.spotAccountId = ht.getSpotAccountIdCache()
+	cexObj.Init()
+	return cexObj
+}
+func (ht *Htx) Init() error {
+	ht.spotWsPublicClosed = true
+	ht.spotWsPrivateClosed = true
+	ht.futuresWsPublicClosed = true
+	return nil
+}
+
+// 签名串: method\nhost\npath\n按key排序的query, base64(HMAC-SHA256)
+// 签名参数都放在query中, POST的body不参与签名
+// host/path 取Options替换后的实际请求地址, 否则服务端验签失败
+func (ht *Htx) signedUrl(method, endpoint, path string, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("AccessKeyId", ht.apikey)
+	params.Set("SignatureMethod", "HmacSHA256")
+	params.Set("SignatureVersion", "2")
+	params.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+	link := ht.rewriteUrl(endpoint + path)
+	host, path := htSplitUrl(link)
+	params.Set("Signature", ht.sign(method+"\n"+host+"\n"+path+"\n"+params.Encode()))
+	return link + "?" + params.Encode()
+}
+
+// https://api.huobi.pro/v1/order -> api.huobi.pro, /v1/order
+func htSplitUrl(link string) (string, string) {
+	_, rest, _ := strings.Cut(link, "://")
+	host, _, _ := strings.Cut(rest, "/")
+	return host, rest[len(host):]
+}
+func (ht *Htx) sign(params string) string {
+	h := hmac.New(sha256.New, []byte(ht.secretkey))
+	h.Write([]byte(params))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+func (ht *Htx) apiError(httpStatus int, code, msg string) error {
+	return newApiError(ht.Name(), httpStatus, code, msg)
+}
+
+// 行情推送为gzip压缩的二进制数据
+func (ht *Htx) gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// BTCUSDT => btcusdt
+func (ht *Htx) fromStdSpotSymbol(symbol string) string {
+	return strings.ToLower(symbol)
+}
+func (ht *Htx) toStdSpotSymbol(symbol string) string {
+	return strings.ToUpper(symbol)
+}
+
+// BTCUSDT => BTC-USDT
+func (ht *Htx) fromStdContractSymbol(symbol string) string {
+	if !strings.HasSuffix(symbol, "USDT") {
+		return symbol
+	}
+	return strings.TrimSuffix(symbol, "USDT") + "-USDT"
+}
+func (ht *Htx) toStdContractSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "-", "")
+}
+func (ht *Htx) getContractSize(symbol string) decimal.Decimal {
+	htxContractSizeMapMtx.Lock()
+	defer htxContractSizeMapMtx.Unlock()
+	return htxContractSizeMap[symbol]
+}
+func (ht *Htx) getLeverage(symbol string) int {
+	htxLeverageMapMtx.Lock()
+	defer htxLeverageMapMtx.Unlock()
+	return htxLeverageMap[symbol]
+}
+func (ht *Htx) setLeverage(symbol string, leverage int) {
+	htxLeverageMapMtx.Lock()
+	defer htxLeverageMapMtx.Unlock()
+	htxLeverageMap[symbol] = leverage
+}
+func (ht *Htx) getSpotAccountIdCache() string {
+	ht.spotAccountIdMtx.Lock()
+	defer ht.spotAccountIdMtx.Unlock()
+	return ht.spotAccountId
+}
+
+// type: buy-limit, sell-market, buy-ioc, buy-limit-maker, buy-limit-fok
+func (ht *Htx) fromStdSpotOrderType(side, orderType, timeInForce string, postOnly bool) string {
+	typ := strings.ToLower(side) + "-"
+	if orderType == "MARKET" {
+		return typ + "market"
+	} else if postOnly {
+		return typ + "limit-maker"
+	} else if timeInForce == "IOC" {
+		return typ + "ioc"
+	} else if timeInForce == "FOK" {
+		return typ + "limit-fok"
+	}
+	return typ + "limit"
+}
+
+// 返回 side, orderType, timeInForce
+func (ht *Htx) toStdSpotOrderType(typ string) (string, string, string) {
+	side, t, _ := strings.Cut(typ, "-")
+	side = strings.ToUpper(side)
+	if t == "market" {
+		return side, "MARKET", "GTC"
+	} else if t == "ioc" {
+		return side, "LIMIT", "IOC"
+	} else if t == "limit-fok" {
+		return side, "LIMIT", "FOK"
+	}
+	return side, "LIMIT", "GTC"
+}
+
+// state: created/submitted/partial-filled/filled/partial-canceled/canceling/canceled/rejected
+func (ht *Htx) toStdSpotOrderStatus(state string) string {
+	if state == "created" || state == "submitted" || state == "canceling" {
+		return "NEW"
+	} else if state == "partial-filled" {
+		return "PARTIALLY_FILLED"
+	} else if state == "filled" {
+		return "FILLED"
+	} else if state == "canceled" || state == "partial-canceled" {
+		return "CANCELED"
+	} else if state == "rejected" {
+		return "REJECTED"
+	}
+	return ""
+}
+
+// 1,2准备提交 3已提交 4部分成交 5部分成交已撤单 6全部成交 7已撤单 11撤单中
+func (ht *Htx) toStdFuturesOrderStatus(status int) string {
+	if status == 1 || status == 2 || status == 3 || status == 11 {
+		return "NEW"
+	} else if status == 4 {
+		return "PARTIALLY_FILLED"
+	} else if status == 6 {
+		return "FILLED"
+	} else if status == 5 || status == 7 {
+		return "CANCELED"
+	}
+	return ""
+}
+
+// 市价单使用对手价(opponent), timeInForce=GTX 为只做maker
+func (ht *Htx) fromStdFuturesOrderType(orderType, timeInForce string) string {
+	if orderType == "MARKET" {
+		return "opponent"
+	} else if timeInForce == "IOC" {
+		return "ioc"
+	} else if timeInForce == "FOK" {
+		return "fok"
+	} else if timeInForce == "GTX" {
+		return "post_only"
+	}
+	return "limit"
+}
+func (ht *Htx) toStdFuturesOrderType(typ string) string {
+	if typ == "limit" || typ == "post_only" || typ == "ioc" || typ == "fok" {
+		return "LIMIT"
+	}
+	return "MARKET"
+}
+func (ht *Htx) toStdWithdrawStatus(state string) string {
+	if state == "confirmed" {
+		return "COMPLETED"
+	} else if state == "failed" || state == "confirm-error" {
+		return "FAILED"
+	} else if state == "reject" || state == "wallet-reject" {
+		return "REJECTED"
+	} else if state == "canceled" || state == "repealed" {
+		return "CANCELED"
+	}
+	return "PENDING"
+}
@@ -0,0 +1,572 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 只支持U本位永续(linear-swap)全仓, 下单数量volume为张数, qty=volume*contract_size
+func (ht *Htx) FuturesSupported(typ string) bool {
+	return typ == "UM"
+}
+func (ht *Htx) FuturesServerTime(typ string) (int64, error) {
+	return ht.SpotServerTime()
+}
+
+// 需要先调用FuturesLoadAllPairRule, 取不到contract_size时返回0
+func (ht *Htx) FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal {
+	return size.Mul(ht.getContractSize(symbol))
+}
+func (ht *Htx) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal) decimal.Decimal {
+	ctSize := ht.getContractSize(symbol)
+	if ctSize.IsZero() {
+		return decimal.Zero
+	}
+	return qty.Div(ctSize).Floor()
+}
+func (ht *Htx) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
+	if !ht.FuturesSupported(typ) {
+		return nil, errors.New(ht.Name() + " not support futures " + typ)
+	}
+	url := htSwapEndpoint + "/linear-swap-api/v1/swap_contract_info?business_type=swap"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode int    `json:"err_code"`
+		ErrMsg  string `json:"err_msg"`
+		Data    []struct {
+			Symbol       string          `json:"contract_code"`
+			Base         string          `json:"symbol"`
+			Quote        string          `json:"trade_partition"`
+			ContractSize decimal.Decimal `json:"contract_size"`
+			PriceTick    decimal.Decimal `json:"price_tick"`
+			Status       int             `json:"contract_status"` // 1上市
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal fail! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	all := make(map[string]*FuturesExchangePairRule)
+	now := time.Now().Unix()
+	htxContractSizeMapMtx.Lock()
+	defer htxContractSizeMapMtx.Unlock()
+	for _, pair := range recv.Data {
+		if pair.Status != 1 || !pair.ContractSize.IsPositive() {
+			continue
+		}
+		symbol := ht.toStdContractSymbol(pair.Symbol)
+		htxContractSizeMap[symbol] = pair.ContractSize
+		ep := &FuturesExchangePairRule{
+			Typ:                typ,
+			Symbol:             symbol,
+			Base:               pair.Base,
+			Quote:              pair.Quote,
+			ContractSize:       pair.ContractSize,
+			ContractMultiplier: pair.ContractSize,
+			PriceTickSize:      pair.PriceTick,
+			MinPrice:           pair.PriceTick,
+			MaxPrice:           decimal.NewFromFloat(9999999999999999.99),
+			MinOrderQty:        pair.ContractSize,
+			MaxOrderQty:        decimal.NewFromFloat(9999999999999999.99),
+			QtyStep:            pair.ContractSize,
+			Time:               now,
+		}
+		all[ep.Symbol] = ep
+	}
+	return all, nil
+}
+
+// amount为标的数量, trade_turnover为成交额
+func (ht *Htx) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error) {
+	url := htSwapEndpoint + "/linear-swap-ex/market/detail/batch_merged?business_type=swap"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode int    `json:"err_code"`
+		ErrMsg  string `json:"err_msg"`
+		Ticks   []struct {
+			Symbol        string          `json:"contract_code"`
+			Close         decimal.Decimal `json:"close"`
+			Amount        decimal.Decimal `json:"amount"`
+			TradeTurnover decimal.Decimal `json:"trade_turnover"`
+		} `json:"ticks"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	allTk := make(map[string]Pub24hTicker, len(recv.Ticks))
+	for _, tk := range recv.Ticks {
+		symbol := ht.toStdContractSymbol(tk.Symbol)
+		allTk[symbol] = Pub24hTicker{
+			Symbol:      symbol,
+			LastPrice:   tk.Close,
+			Volume:      tk.Amount,
+			QuoteVolume: tk.TradeTurnover,
+		}
+	}
+	return allTk, nil
+}
+
+// 张数换算成标的数量, 需要先调用FuturesLoadAllPairRule
+func (ht *Htx) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
+	url := htSwapEndpoint + "/linear-swap-ex/market/bbo?contract_code=" + ht.fromStdContractSymbol(symbol)
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string       `json:"status"`
+		ErrCode int          `json:"err_code"`
+		ErrMsg  string       `json:"err_msg"`
+		Ticks   []HtxSwapBBO `json:"ticks"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return BestBidAsk{}, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return BestBidAsk{}, ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	if len(recv.Ticks) == 0 {
+		return BestBidAsk{}, errors.New(ht.Name() + " resp empty")
+	}
+	bbo := &(recv.Ticks[0])
+	return BestBidAsk{
+		Symbol:   symbol,
+		Time:     bbo.Ts,
+		BidPrice: bbo.Bid[0],
+		BidQty:   ht.FuturesSizeToQty(typ, symbol, bbo.Bid[1]),
+		AskPrice: bbo.Ask[0],
+		AskQty:   ht.FuturesSizeToQty(typ, symbol, bbo.Ask[1]),
+	}, nil
+}
+
+// funding_rate为当期资金费率, funding_time为当期结算时间
+func (ht *Htx) FuturesGetAllFundingRate(typ string) (map[string]FundingRate, error) {
+	url := htSwapEndpoint + "/linear-swap-api/v1/swap_batch_funding_rate"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode int    `json:"err_code"`
+		ErrMsg  string `json:"err_msg"`
+		Data    []struct {
+			Symbol      string          `json:"contract_code"`
+			FundingRate decimal.Decimal `json:"funding_rate"`
+			FundingTime decimal.Decimal `json:"funding_time"` // msec
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	all := make(map[string]FundingRate, len(recv.Data))
+	now := time.Now().Unix()
+	for _, v := range recv.Data {
+		symbol := ht.toStdContractSymbol(v.Symbol)
+		all[symbol] = FundingRate{
+			Symbol:   symbol,
+			Val:      v.FundingRate,
+			NextTime: v.FundingTime.IntPart() / 1000,
+			UTime:    now,
+		}
+	}
+	return all, nil
+}
+func (ht *Htx) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
+	var data []struct {
+		Symbol      string          `json:"margin_asset"`
+		Total       decimal.Decimal `json:"margin_balance"`
+		Avail       decimal.Decimal `json:"margin_available"`
+		MaxTransfer decimal.Decimal `json:"withdraw_available"`
+	}
+	err := ht.swapPost("/linear-swap-api/v1/swap_cross_account_info",
+		map[string]any{"margin_account": "USDT"}, &data)
+	if err != nil {
+		return nil, err
+	}
+	assetsMap := make(map[string]*FuturesAsset, len(data))
+	for _, v := range data {
+		assetsMap[v.Symbol] = &FuturesAsset{
+			Symbol:            v.Symbol,
+			Total:             v.Total,
+			Avail:             v.Avail,
+			MaxWithdrawAmount: v.MaxTransfer,
+		}
+	}
+	return assetsMap, nil
+}
+
+// interval 1m,5m,15m,30m,1h,4h,1d, from/to不能和size同时使用, 一次最多2000根
+// amount为标的数量, trade_turnover为成交额
+func (ht *Htx) FuturesGetKLine(typ, symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	periods := map[string]string{"1m": "1min", "5m": "5min", "15m": "15min", "30m": "30min",
+		"1h": "60min", "4h": "4hour", "1d": "1day"}
+	if periods[interval] == "" {
+		return nil, errors.New(ht.Name() + " not support interval " + interval)
+	}
+	query := url.Values{}
+	query.Set("contract_code", ht.fromStdContractSymbol(symbol))
+	query.Set("period", periods[interval])
+	if startTime > 0 {
+		end := klineEndTime(interval, startTime, endTime, limit)
+		if end == 0 {
+			end = time.Now().Unix()
+		}
+		query.Set("from", strconv.FormatInt(startTime, 10))
+		query.Set("to", strconv.FormatInt(end-1, 10))
+	} else if endTime > 0 {
+		query.Set("from", strconv.FormatInt(endTime-limit*klineIntervalSeconds(interval), 10))
+		query.Set("to", strconv.FormatInt(endTime-1, 10))
+	} else if limit > 0 {
+		query.Set("size", strconv.FormatInt(limit, 10))
+	}
+	link := htSwapEndpoint + "/linear-swap-ex/market/history/kline?" + query.Encode()
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode int    `json:"err_code"`
+		ErrMsg  string `json:"err_msg"`
+		Data    []struct {
+			OpenTime      int64           `json:"id"` // sec
+			Open          decimal.Decimal `json:"open"`
+			High          decimal.Decimal `json:"high"`
+			Low           decimal.Decimal `json:"low"`
+			Close         decimal.Decimal `json:"close"`
+			Amount        decimal.Decimal `json:"amount"`
+			TradeTurnover decimal.Decimal `json:"trade_turnover"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	all := make([]KLine, 0, len(recv.Data))
+	for _, v := range recv.Data {
+		all = append(all, KLine{
+			OpenTime:    v.OpenTime,
+			OpenPrice:   v.Open,
+			HighPrice:   v.High,
+			LowPrice:    v.Low,
+			ClosePrice:  v.Close,
+			Volume:      v.Amount,
+			QuoteVolume: v.TradeTurnover,
+		})
+	}
+	return all, nil
+}
+
+// 接口不返回强平价
+func (ht *Htx) futuresGetPositions(typ string) ([]*FuturesPosition, error) {
+	var data []struct {
+		Symbol        string          `json:"contract_code"`
+		Volume        decimal.Decimal `json:"volume"`
+		CostOpen      decimal.Decimal `json:"cost_open"`
+		UnrealisedPnl decimal.Decimal `json:"profit_unreal"`
+		Leverage      decimal.Decimal `json:"lever_rate"`
+		Direction     string          `json:"direction"`     // buy/sell
+		PositionMode  string          `json:"position_mode"` // single_side/dual_side
+	}
+	err := ht.swapPost("/linear-swap-api/v1/swap_cross_position_info",
+		map[string]any{"margin_account": "USDT"}, &data)
+	if err != nil {
+		return nil, err
+	}
+	all := make([]*FuturesPosition, 0, len(data))
+	now := time.Now().UnixMilli()
+	for _, v := range data {
+		symbol := ht.toStdContractSymbol(v.Symbol)
+		cp := &FuturesPosition{
+			Symbol:           symbol,
+			Side:             ht.toStdSide(v.Direction),
+			PositionQty:      ht.FuturesSizeToQty(typ, symbol, v.Volume),
+			EntryPrice:       v.CostOpen,
+			UnRealizedProfit: v.UnrealisedPnl,
+			Leverage:         v.Leverage,
+			UTime:            now,
+		}
+		if v.PositionMode == "dual_side" {
+			cp.Mode = 1
+		}
+		all = append(all, cp)
+	}
+	return all, nil
+}
+func (ht *Htx) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosition, error) {
+	all, err := ht.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPosition)
+	for _, v := range all {
+		positionM[v.Symbol] = v
+	}
+	return positionM, nil
+}
+func (ht *Htx) FuturesGetAllPositions(typ string) (map[string]*FuturesPositions, error) {
+	all, err := ht.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPositions)
+	for _, v := range all {
+		fp := positionM[v.Symbol]
+		if fp == nil {
+			fp = &FuturesPositions{}
+			positionM[v.Symbol] = fp
+		}
+		fp.Val(v)
+	}
+	return positionM, nil
+}
+func (ht *Htx) toStdSide(v string) string {
+	if v == "buy" {
+		return "BUY"
+	} else if v == "sell" {
+		return "SELL"
+	}
+	return ""
+}
+
+// 双仓时用offset区分开平: LONG+BUY开多 LONG+SELL平多, SHORT+SELL开空 SHORT+BUY平空
+// 单仓时offset=both, reduce_only生效
+// client_order_id 必须是数字, lever_rate 使用FuturesSwitchTradeMode设置的杠杆
+func (ht *Htx) FuturesPlaceOrder(typ, symbol, cltId string, /*BTCUSDT*/
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	if tradeMode != 0 {
+		return "", errors.New(ht.Name() + " only support cross margin")
+	}
+	volume := ht.FuturesQtyToSize(typ, symbol, qty)
+	if !volume.IsPositive() {
+		return "", errors.New(ht.Name() + " order volume is 0, load pair rule first")
+	}
+	params := map[string]any{
+		"contract_code":    ht.fromStdContractSymbol(symbol),
+		"volume":           volume.IntPart(),
+		"direction":        "buy",
+		"order_price_type": ht.fromStdFuturesOrderType(orderType, timeInForce),
+	}
+	if side == "SELL" {
+		params["direction"] = "sell"
+	}
+	if cltId != "" {
+		id, err := strconv.ParseInt(cltId, 10, 64)
+		if err != nil {
+			return "", errors.New(ht.Name() + " client id must be number")
+		}
+		params["client_order_id"] = id
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+	}
+	if leverage := ht.getLeverage(symbol); leverage > 0 {
+		params["lever_rate"] = leverage
+	}
+	if positionMode == "LONG" {
+		params["offset"] = "open"
+		if side == "SELL" {
+			params["offset"] = "close"
+		}
+	} else if positionMode == "SHORT" {
+		params["offset"] = "open"
+		if side == "BUY" {
+			params["offset"] = "close"
+		}
+	} else {
+		params["offset"] = "both"
+		params["reduce_only"] = reduceOnly
+	}
+	ret := struct {
+		OrderId string `json:"order_id_str"`
+	}{}
+	if err := ht.swapPost("/linear-swap-api/v1/swap_cross_order", params, &ret); err != nil {
+		return "", err
+	}
+	return ret.OrderId, nil
+}
+func (ht *Htx) toStdFuturesOrder(order *HtxFuturesOrder) *FuturesOrder {
+	symbol := ht.toStdContractSymbol(order.Symbol)
+	fo := &FuturesOrder{
+		Symbol:    symbol,
+		OrderId:   order.OrderId,
+		Status:    ht.toStdFuturesOrderStatus(order.Status),
+		Type:      ht.toStdFuturesOrderType(order.Type),
+		Side:      ht.toStdSide(order.Direction),
+		Price:     order.Price,
+		Qty:       ht.FuturesSizeToQty("UM", symbol, order.Volume),
+		FilledQty: ht.FuturesSizeToQty("UM", symbol, order.TradeVolume),
+		FilledAmt: order.TradeTurnover,
+		AvgPrice:  order.AvgPrice,
+		FeeAsset:  order.FeeAsset,
+		FeeQty:    order.Fee.Abs(),
+		CTime:     order.CreatedAt,
+		UTime:     order.UpdateTime,
+	}
+	if order.ClientId > 0 {
+		fo.ClientId = strconv.FormatInt(order.ClientId, 10)
+	}
+	if fo.UTime == 0 {
+		fo.UTime = fo.CTime
+	}
+	return fo
+}
+func (ht *Htx) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOrder, error) {
+	params := map[string]any{
+		"contract_code": ht.fromStdContractSymbol(symbol),
+	}
+	if orderId != "" {
+		params["order_id"] = orderId
+	} else {
+		params["client_order_id"] = cltId
+	}
+	var data []HtxFuturesOrder
+	if err := ht.swapPost("/linear-swap-api/v1/swap_cross_order_info", params, &data); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New(ht.Name() + " order not found")
+	}
+	return ht.toStdFuturesOrder(&data[0]), nil
+}
+func (ht *Htx) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error) {
+	params := map[string]any{
+		"page_size": 50,
+	}
+	if symbol != "" {
+		params["contract_code"] = ht.fromStdContractSymbol(symbol)
+	}
+	ret := struct {
+		Orders []HtxFuturesOrder `json:"orders"`
+	}{}
+	if err := ht.swapPost("/linear-swap-api/v1/swap_cross_openorders", params, &ret); err != nil {
+		return nil, err
+	}
+	orders := make([]*FuturesOrder, 0, len(ret.Orders))
+	for i := range ret.Orders {
+		orders = append(orders, ht.toStdFuturesOrder(&ret.Orders[i]))
+	}
+	return orders, nil
+}
+
+// 撤单失败的在errors中返回
+func (ht *Htx) swapCancel(path string, params map[string]any) error {
+	ret := struct {
+		Errors []struct {
+			ErrCode int    `json:"err_code"`
+			ErrMsg  string `json:"err_msg"`
+		} `json:"errors"`
+	}{}
+	if err := ht.swapPost(path, params, &ret); err != nil {
+		return err
+	}
+	if len(ret.Errors) > 0 {
+		return ht.apiError(200, strconv.Itoa(ret.Errors[0].ErrCode), ret.Errors[0].ErrMsg) // 请求成功, 单个订单撤销失败
+	}
+	return nil
+}
+func (ht *Htx) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
+	params := map[string]any{
+		"contract_code": ht.fromStdContractSymbol(symbol),
+	}
+	if orderId != "" {
+		params["order_id"] = orderId
+	} else {
+		params["client_order_id"] = cltId
+	}
+	return ht.swapCancel("/linear-swap-api/v1/swap_cross_cancel", params)
+}
+func (ht *Htx) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	return futuresPlaceOrdersConcurrently(ht, typ, orders), nil
+}
+func (ht *Htx) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return futuresCancelOrdersConcurrently(ht, typ, orders), nil
+}
+func (ht *Htx) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(ht.Name() + " symbol is empty")
+	}
+	return ht.swapCancel("/linear-swap-api/v1/swap_cross_cancelall",
+		map[string]any{"contract_code": ht.fromStdContractSymbol(symbol)})
+}
+func (ht *Htx) FuturesSwitchPositionMode(typ string, mode int) error {
+	params := map[string]any{
+		"margin_account": "USDT",
+		"position_mode":  "single_side",
+	}
+	if mode == 1 {
+		params["position_mode"] = "dual_side"
+	}
+	return ht.swapPost("/linear-swap-api/v1/swap_cross_switch_position_mode", params, nil)
+}
+
+// 只支持全仓, 设置的杠杆会在下单时使用
+func (ht *Htx) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int) error {
+	if mode != 0 {
+		return errors.New(ht.Name() + " only support cross margin")
+	}
+	params := map[string]any{
+		"contract_code": ht.fromStdContractSymbol(symbol),
+		"lever_rate":    leverage,
+	}
+	if err := ht.swapPost("/linear-swap-api/v1/swap_cross_switch_lever_rate", params, nil); err != nil {
+		return err
+	}
+	ht.setLeverage(symbol, leverage)
+	return nil
+}
+
+// status=ok 成功, data 解析到 ret (可为nil)
+func (ht *Htx) swapPost(path string, params map[string]any, ret any) error {
+	body, _ := json.Marshal(params)
+	link := ht.signedUrl("POST", htSwapEndpoint, path, nil)
+	httpCode, resp, err := ht.Post(link, body, htApiDeadline,
+		map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string          `json:"status"`
+		ErrCode int             `json:"err_code"`
+		ErrMsg  string          `json:"err_msg"`
+		Data    json.RawMessage `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return ht.apiError(httpCode, strconv.Itoa(recv.ErrCode), recv.ErrMsg)
+	}
+	if ret != nil && len(recv.Data) > 0 {
+		if err = json.Unmarshal(recv.Data, ret); err != nil {
+			return errors.New(ht.Name() + " unmarshal error! " + err.Error())
+		}
+	}
+	return nil
+}
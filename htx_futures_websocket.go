@@ -0,0 +1,142 @@
+package cex
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+// 永续行情, 格式同现货行情频道, 数量为张数(需要先调用FuturesLoadAllPairRule)
+const htSwapWsPublicUrl = "wss://api.hbdm.com/linear-swap-ws"
+
+func (ht *Htx) FuturesWsPublicOpen(typ string) error {
+	if !ht.FuturesSupported(typ) {
+		return errors.New(ht.Name() + " not support futures " + typ)
+	}
+	var err error
+	ht.futuresWsPublicConn, err = ht.wsDial(htSwapWsPublicUrl)
+	if err != nil {
+		return errors.New(ht.Name() + " futures.ws.public con failed! " + err.Error())
+	}
+	ht.futuresWsPublicClosedMtx.Lock()
+	ht.futuresWsPublicClosed = false
+	ht.futuresWsPublicClosedMtx.Unlock()
+	return nil
+}
+
+// orderbook5 使用step6(20档不合并), 推送时截取5档
+func (ht *Htx) FuturesWsPublicSubscribe(channels []string) {
+	ht.wsPublicSend(ht.futuresWsPublicConn, &ht.futuresWsPublicConnMtx, "sub",
+		ht.wsPublicTopics(channels, ht.fromStdContractSymbol, "depth.step6", "detail"))
+}
+func (ht *Htx) FuturesWsPublicUnsubscribe(channels []string) {
+	ht.wsPublicSend(ht.futuresWsPublicConn, &ht.futuresWsPublicConnMtx, "unsub",
+		ht.wsPublicTopics(channels, ht.fromStdContractSymbol, "depth.step6", "detail"))
+}
+func (ht *Htx) FuturesWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (ht *Htx) FuturesWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (ht *Htx) FuturesWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (ht *Htx) FuturesWsPublicLoop(ch chan<- any) {
+	defer ht.FuturesWsPublicClose()
+	defer close(ch)
+
+	pingWait := 30 * time.Second // 服务端5秒ping一次
+	ht.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pingWait))
+	for {
+		_, recv, err := ht.futuresWsPublicConn.ReadMessage()
+		if err != nil {
+			if !ht.FuturesWsPublicIsClosed() {
+				ilog.Warning("%s", ht.Name()+" futures.ws.public channel read: "+err.Error())
+			}
+			break
+		}
+		ht.futuresWsPublicConn.SetReadDeadline(time.Now().Add(pingWait))
+		msg := htWsPubMsgPool.Get().(*HtxWsPubMsg)
+		if ht.wsPublicRecv("futures.ws.public", ht.futuresWsPublicConn, &ht.futuresWsPublicConnMtx, recv, msg) {
+			ht.futuresWsHandlePublicMsg(msg, ch)
+		}
+		htWsPubMsgPool.Put(msg)
+	}
+}
+func (ht *Htx) FuturesWsPublicIsClosed() bool {
+	ht.futuresWsPublicClosedMtx.RLock()
+	defer ht.futuresWsPublicClosedMtx.RUnlock()
+	return ht.futuresWsPublicClosed
+}
+func (ht *Htx) FuturesWsPublicClose() {
+	ht.futuresWsPublicClosedMtx.Lock()
+	defer ht.futuresWsPublicClosedMtx.Unlock()
+	if ht.futuresWsPublicClosed {
+		return
+	}
+	ht.futuresWsPublicClosed = true
+	ht.futuresWsPublicConn.Close()
+}
+
+// ch: market.BTC-USDT.depth.step6 / market.BTC-USDT.bbo / market.BTC-USDT.detail
+func (ht *Htx) futuresWsHandlePublicMsg(msg *HtxWsPubMsg, ch chan<- any) {
+	arr := strings.Split(msg.Ch, ".")
+	if len(arr) < 3 {
+		return
+	}
+	symbol := ht.toStdContractSymbol(arr[1])
+	if arr[2] == "depth" {
+		var depth HtxOrderBook
+		if err := easyjson.Unmarshal(msg.Tick, &depth); err != nil {
+			ilog.Error("%s", ht.Name()+" futures.ws.public "+symbol+" orderbook5 exception")
+			return
+		}
+		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+		obd.Symbol = symbol
+		obd.Level = 5
+		obd.Time = depth.Ts
+		obd.Bids = obd.Bids[:0]
+		obd.Asks = obd.Asks[:0]
+		for _, v := range depth.Bids[:min(5, len(depth.Bids))] {
+			obd.Bids = append(obd.Bids, Ticker{Price: v[0],
+				Quantity: ht.FuturesSizeToQty("UM", symbol, v[1])})
+		}
+		for _, v := range depth.Asks[:min(5, len(depth.Asks))] {
+			obd.Asks = append(obd.Asks, Ticker{Price: v[0],
+				Quantity: ht.FuturesSizeToQty("UM", symbol, v[1])})
+		}
+		ch <- obd
+	} else if arr[2] == "bbo" {
+		var tick HtxSwapBBO
+		if err := easyjson.Unmarshal(msg.Tick, &tick); err != nil {
+			ilog.Error("%s", ht.Name()+" futures.ws.public "+symbol+" bbo exception")
+			return
+		}
+		bbo := wsPublicBBOPool.Get().(*BestBidAsk)
+		bbo.Symbol = symbol
+		bbo.Time = tick.Ts
+		bbo.BidPrice = tick.Bid[0]
+		bbo.BidQty = ht.FuturesSizeToQty("UM", symbol, tick.Bid[1])
+		bbo.AskPrice = tick.Ask[0]
+		bbo.AskQty = ht.FuturesSizeToQty("UM", symbol, tick.Ask[1])
+		ch <- bbo
+	} else if arr[2] == "detail" {
+		var tick HtxTicker
+		if err := easyjson.Unmarshal(msg.Tick, &tick); err != nil {
+			ilog.Error("%s", ht.Name()+" futures.ws.public "+symbol+" ticker exception")
+			return
+		}
+		tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+		tk.Symbol = symbol
+		tk.LastPrice = tick.Close
+		tk.Volume = tick.Amount
+		tk.QuoteVolume = tick.TradeTurnover
+		tk.BaseVolume = decimal.Zero
+		ch <- tk
+	}
+}
@@ -0,0 +1,504 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (ht *Htx) SpotSupported() bool {
+	return true
+}
+func (ht *Htx) SpotServerTime() (int64, error) {
+	url := htSpotEndpoint + "/v1/common/timestamp"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return 0, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    int64  `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return 0, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return 0, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	return recv.Data, nil
+}
+func (ht *Htx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
+	url := htSpotEndpoint + "/v1/common/symbols"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    []struct {
+			Symbol         string          `json:"symbol"`
+			Base           string          `json:"base-currency"`
+			Quote          string          `json:"quote-currency"`
+			State          string          `json:"state"`
+			PricePrecision int32           `json:"price-precision"`
+			QtyPrecision   int32           `json:"amount-precision"`
+			MinQty         decimal.Decimal `json:"limit-order-min-order-amt"`
+			MaxQty         decimal.Decimal `json:"limit-order-max-order-amt"`
+			MinNotional    decimal.Decimal `json:"min-order-value"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal fail! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	all := make(map[string]*SpotExchangePairRule)
+	now := time.Now().Unix()
+	for _, pair := range recv.Data {
+		if pair.State != "online" {
+			continue
+		}
+		ep := &SpotExchangePairRule{
+			Symbol:        ht.toStdSpotSymbol(pair.Symbol),
+			Base:          strings.ToUpper(pair.Base),
+			Quote:         strings.ToUpper(pair.Quote),
+			Status:        "online",
+			PriceTickSize: decimal.New(1, -pair.PricePrecision),
+			QtyStep:       decimal.New(1, -pair.QtyPrecision),
+			MinOrderQty:   pair.MinQty,
+			MaxOrderQty:   pair.MaxQty,
+			MaxPrice:      decimal.NewFromFloat(9999999999999999.99),
+			MinNotional:   pair.MinNotional,
+			Time:          now,
+		}
+		ep.MinPrice = ep.PriceTickSize
+		if !ep.MinOrderQty.IsPositive() {
+			ep.MinOrderQty = ep.QtyStep
+		}
+		all[ep.Symbol] = ep
+	}
+	return all, nil
+}
+
+// amount为标的成交量, vol为计价币成交额
+func (ht *Htx) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
+	url := htSpotEndpoint + "/market/tickers"
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    []struct {
+			Symbol string          `json:"symbol"`
+			Close  decimal.Decimal `json:"close"`
+			Amount decimal.Decimal `json:"amount"`
+			Vol    decimal.Decimal `json:"vol"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	allTk := make(map[string]Pub24hTicker, len(recv.Data))
+	for _, tk := range recv.Data {
+		symbol := ht.toStdSpotSymbol(tk.Symbol)
+		allTk[symbol] = Pub24hTicker{
+			Symbol:      symbol,
+			LastPrice:   tk.Close,
+			Volume:      tk.Amount,
+			QuoteVolume: tk.Vol,
+		}
+	}
+	return allTk, nil
+}
+func (ht *Htx) SpotGetBBO(symbol string) (BestBidAsk, error) {
+	url := htSpotEndpoint + "/market/detail/merged?symbol=" + ht.fromStdSpotSymbol(symbol)
+	httpCode, resp, err := ht.Get(url, htApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Ts      int64  `json:"ts"`
+		Tick    struct {
+			Bid [2]decimal.Decimal `json:"bid"`
+			Ask [2]decimal.Decimal `json:"ask"`
+		} `json:"tick"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return BestBidAsk{}, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return BestBidAsk{}, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	return BestBidAsk{
+		Symbol:   symbol,
+		Time:     recv.Ts,
+		BidPrice: recv.Tick.Bid[0],
+		BidQty:   recv.Tick.Bid[1],
+		AskPrice: recv.Tick.Ask[0],
+		AskQty:   recv.Tick.Ask[1],
+	}, nil
+}
+
+// 现货(spot)账户id, 取到后缓存
+func (ht *Htx) getSpotAccountId() (string, error) {
+	if id := ht.getSpotAccountIdCache(); id != "" {
+		return id, nil
+	}
+	path := "/v1/account/accounts"
+	httpCode, resp, err := ht.Get(ht.signedUrl("GET", htSpotEndpoint, path, nil), htApiDeadline, nil)
+	if err != nil {
+		return "", newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    []struct {
+			Id    int64  `json:"id"`
+			Type  string `json:"type"`
+			State string `json:"state"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return "", ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	for _, v := range recv.Data {
+		if v.Type == "spot" {
+			id := strconv.FormatInt(v.Id, 10)
+			ht.spotAccountIdMtx.Lock()
+			ht.spotAccountId = id
+			ht.spotAccountIdMtx.Unlock()
+			return id, nil
+		}
+	}
+	return "", errors.New(ht.Name() + " spot account not found")
+}
+func (ht *Htx) SpotGetAllAssets() (map[string]*SpotAsset, error) {
+	accountId, err := ht.getSpotAccountId()
+	if err != nil {
+		return nil, err
+	}
+	path := "/v1/account/accounts/" + accountId + "/balance"
+	httpCode, resp, err := ht.Get(ht.signedUrl("GET", htSpotEndpoint, path, nil), htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    struct {
+			List []struct {
+				Currency string          `json:"currency"`
+				Type     string          `json:"type"` // trade/frozen
+				Balance  decimal.Decimal `json:"balance"`
+			} `json:"list"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	assetsMap := make(map[string]*SpotAsset)
+	for _, v := range recv.Data.List {
+		if v.Balance.IsZero() {
+			continue
+		}
+		symbol := strings.ToUpper(v.Currency)
+		asset, ok := assetsMap[symbol]
+		if !ok {
+			asset = &SpotAsset{Symbol: symbol}
+			assetsMap[symbol] = asset
+		}
+		if v.Type == "trade" {
+			asset.Avail = asset.Avail.Add(v.Balance)
+		} else {
+			asset.Locked = asset.Locked.Add(v.Balance)
+		}
+		asset.Total = asset.Avail.Add(asset.Locked)
+	}
+	return assetsMap, nil
+}
+
+// 市价买单amount为计价币数量(amt), 市价卖单为标的数量(qty)
+func (ht *Htx) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
+	price, amt, qty decimal.Decimal,
+	side, timeInForce, orderType string, postOnly bool) (string, error) {
+	accountId, err := ht.getSpotAccountId()
+	if err != nil {
+		return "", err
+	}
+	params := map[string]any{
+		"account-id": accountId,
+		"symbol":     ht.fromStdSpotSymbol(symbol),
+		"type":       ht.fromStdSpotOrderType(side, orderType, timeInForce, postOnly),
+		"amount":     qty.String(),
+		"source":     "spot-api",
+	}
+	if orderType == "MARKET" && side == "BUY" {
+		if !amt.IsPositive() {
+			return "", errors.New(ht.Name() + " market buy order need amt")
+		}
+		params["amount"] = amt.String()
+	}
+	if orderType != "MARKET" {
+		params["price"] = price.String()
+	}
+	if cltId != "" {
+		params["client-order-id"] = cltId
+	}
+	var orderId string
+	if err = ht.spotPost("/v1/order/orders/place", params, &orderId); err != nil {
+		return "", err
+	}
+	return orderId, nil
+}
+func (ht *Htx) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
+	if orderId != "" {
+		return ht.spotPost("/v1/order/orders/"+orderId+"/submitcancel",
+			map[string]any{"symbol": ht.fromStdSpotSymbol(symbol)}, nil)
+	}
+	return ht.spotPost("/v1/order/orders/submitCancelClientOrder",
+		map[string]any{"client-order-id": cltId}, nil)
+}
+func (ht *Htx) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(ht, orders), nil
+}
+func (ht *Htx) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(ht, orders), nil
+}
+
+// symbol为空撤销所有symbol, 每次最多撤100个, next-id=-1表示没有更多
+func (ht *Htx) SpotCancelAllOrders(symbol string) error {
+	accountId, err := ht.getSpotAccountId()
+	if err != nil {
+		return err
+	}
+	params := map[string]any{
+		"account-id": accountId,
+	}
+	if symbol != "" {
+		params["symbol"] = ht.fromStdSpotSymbol(symbol)
+	}
+	for range 10 {
+		ret := struct {
+			SuccessCount int   `json:"success-count"`
+			NextId       int64 `json:"next-id"`
+		}{}
+		if err = ht.spotPost("/v1/order/orders/batchCancelOpenOrders", params, &ret); err != nil {
+			return err
+		}
+		if ret.NextId == -1 || ret.SuccessCount == 0 {
+			break
+		}
+	}
+	return nil
+}
+func (ht *Htx) toStdSpotOrder(order *HtxSpotOrder) *SpotOrder {
+	side, typ, tif := ht.toStdSpotOrderType(order.Type)
+	so := &SpotOrder{
+		Symbol:      ht.toStdSpotSymbol(order.Symbol),
+		OrderId:     strconv.FormatInt(order.Id, 10),
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Amount,
+		FilledQty:   order.FieldAmount,
+		FilledAmt:   order.FieldCashAmount,
+		FeeQty:      order.FieldFees.Neg(),
+		Status:      ht.toStdSpotOrderStatus(order.State),
+		Type:        typ,
+		TimeInForce: tif,
+		Side:        side,
+		CTime:       order.CreatedAt,
+		UTime:       max(order.FinishedAt, order.CanceledAt),
+	}
+	if !order.FilledAmount.IsZero() {
+		so.FilledQty = order.FilledAmount
+		so.FilledAmt = order.FilledCash
+		so.FeeQty = order.FilledFees.Neg()
+	}
+	if so.FilledQty.IsPositive() {
+		so.AvgPrice = so.FilledAmt.Div(so.FilledQty)
+	}
+	if so.UTime == 0 {
+		so.UTime = so.CTime
+	}
+	return so
+}
+func (ht *Htx) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
+	var link string
+	if orderId != "" {
+		link = ht.signedUrl("GET", htSpotEndpoint, "/v1/order/orders/"+orderId, nil)
+	} else {
+		query := url.Values{}
+		query.Set("clientOrderId", cltId)
+		link = ht.signedUrl("GET", htSpotEndpoint, "/v1/order/orders/getClientOrder", query)
+	}
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string       `json:"status"`
+		ErrCode string       `json:"err-code"`
+		ErrMsg  string       `json:"err-msg"`
+		Data    HtxSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	return ht.toStdSpotOrder(&recv.Data), nil
+}
+func (ht *Htx) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	accountId, err := ht.getSpotAccountId()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("account-id", accountId)
+	query.Set("size", "500")
+	if symbol != "" {
+		query.Set("symbol", ht.fromStdSpotSymbol(symbol))
+	}
+	link := ht.signedUrl("GET", htSpotEndpoint, "/v1/order/openOrders", query)
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string         `json:"status"`
+		ErrCode string         `json:"err-code"`
+		ErrMsg  string         `json:"err-msg"`
+		Data    []HtxSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	orders := make([]*SpotOrder, 0, len(recv.Data))
+	for i := range recv.Data {
+		orders = append(orders, ht.toStdSpotOrder(&recv.Data[i]))
+	}
+	return orders, nil
+}
+
+// 最近48小时内已完全成交的订单, 最多100个, symbol必填
+func (ht *Htx) SpotGetFilledOrders(symbol string) ([]*SpotOrder, error) {
+	if symbol == "" {
+		return nil, errors.New(ht.Name() + " symbol is empty")
+	}
+	query := url.Values{}
+	query.Set("symbol", ht.fromStdSpotSymbol(symbol))
+	query.Set("states", "filled")
+	query.Set("size", "100")
+	link := ht.signedUrl("GET", htSpotEndpoint, "/v1/order/orders", query)
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string         `json:"status"`
+		ErrCode string         `json:"err-code"`
+		ErrMsg  string         `json:"err-msg"`
+		Data    []HtxSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	orders := make([]*SpotOrder, 0, len(recv.Data))
+	for i := range recv.Data {
+		orders = append(orders, ht.toStdSpotOrder(&recv.Data[i]))
+	}
+	return orders, nil
+}
+func (ht *Htx) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
+	query := url.Values{}
+	query.Set("symbols", ht.fromStdSpotSymbol(symbol))
+	link := ht.signedUrl("GET", htSpotEndpoint, "/v2/reference/transact-fee-rate", query)
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return SpotTradeFee{}, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    []struct {
+			Maker decimal.Decimal `json:"actualMakerRate"`
+			Taker decimal.Decimal `json:"actualTakerRate"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return SpotTradeFee{}, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 200 {
+		return SpotTradeFee{}, ht.apiError(httpCode, strconv.Itoa(recv.Code), recv.Message)
+	}
+	if len(recv.Data) == 0 {
+		return SpotTradeFee{}, errors.New(ht.Name() + " resp empty")
+	}
+	return SpotTradeFee{
+		Maker: recv.Data[0].Maker,
+		Taker: recv.Data[0].Taker,
+	}, nil
+}
+
+// v1接口: status=ok 成功, data 解析到 ret (可为nil)
+func (ht *Htx) spotPost(path string, params map[string]any, ret any) error {
+	body, _ := json.Marshal(params)
+	link := ht.signedUrl("POST", htSpotEndpoint, path, nil)
+	httpCode, resp, err := ht.Post(link, body, htApiDeadline,
+		map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string          `json:"status"`
+		ErrCode string          `json:"err-code"`
+		ErrMsg  string          `json:"err-msg"`
+		Data    json.RawMessage `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	if ret != nil && len(recv.Data) > 0 {
+		if err = json.Unmarshal(recv.Data, ret); err != nil {
+			return errors.New(ht.Name() + " unmarshal error! " + err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,404 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+// 行情频道数据为gzip压缩, 服务端发送 {"ping":ts}, 客户端需回复 {"pong":ts}
+// v2私有频道不压缩, 服务端发送 {"action":"ping"}, 客户端回复 {"action":"pong"}
+const htSpotWsPublicUrl = "wss://api.huobi.pro/ws"
+const htSpotWsPrivateUrl = "wss://api.huobi.pro/ws/v2"
+
+func (ht *Htx) wsDial(link string) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 2 * time.Second,
+	}
+	conn, _, err := dialer.Dial(ht.wsUrl(link), nil)
+	return conn, err
+}
+
+// channels: orderbook5@symbolA,symbolB bbo@symbolA ticker@symbolA
+// toSymbol 把BTCUSDT转换成交易所格式, orderbook5/ticker 为对应的频道名
+func (ht *Htx) wsPublicTopics(channels []string, toSymbol func(string) string,
+	orderbook5, ticker string) []string {
+	topics := make([]string, 0, 4)
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		topic := ""
+		if arr[0] == "orderbook5" {
+			topic = orderbook5
+		} else if arr[0] == "bbo" {
+			topic = "bbo"
+		} else if arr[0] == "ticker" {
+			topic = ticker
+		} else {
+			continue
+		}
+		for sym := range strings.SplitSeq(arr[1], ",") {
+			topics = append(topics, "market."+toSymbol(sym)+"."+topic)
+		}
+	}
+	return topics
+}
+
+// op: sub/unsub
+func (ht *Htx) wsPublicSend(conn *websocket.Conn, mtx *sync.Mutex, op string, topics []string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	for _, topic := range topics {
+		req, _ := json.Marshal(map[string]string{op: topic, "id": topic})
+		if err := conn.WriteMessage(websocket.TextMessage, req); err != nil {
+			ilog.Warning("%s", ht.Name()+" ws.public "+op+" net error! "+err.Error())
+			return
+		}
+	}
+}
+
+// 解压并解析到msg, 是行情推送时返回true, ping在这里回复
+func (ht *Htx) wsPublicRecv(tag string, conn *websocket.Conn, mtx *sync.Mutex,
+	recv []byte, msg *HtxWsPubMsg) bool {
+	data, err := ht.gunzip(recv)
+	if err != nil {
+		ilog.Error("%s", ht.Name()+" "+tag+" gunzip fail! "+err.Error())
+		return false
+	}
+	msg.reset()
+	if err = easyjson.Unmarshal(data, msg); err != nil {
+		ilog.Error("%s", ht.Name()+" "+tag+" recv invalid msg:"+string(data))
+		return false
+	}
+	if msg.Ping != 0 {
+		mtx.Lock()
+		conn.WriteMessage(websocket.TextMessage,
+			[]byte(`{"pong":`+strconv.FormatInt(msg.Ping, 10)+`}`))
+		mtx.Unlock()
+		return false
+	}
+	if msg.Status == "error" {
+		ilog.Error("%s", ht.Name()+" "+tag+" recv err: "+string(data))
+		return false
+	}
+	return msg.Ch != "" && len(msg.Tick) > 0
+}
+func (ht *Htx) SpotWsPublicOpen() error {
+	var err error
+	ht.spotWsPublicConn, err = ht.wsDial(htSpotWsPublicUrl)
+	if err != nil {
+		return errors.New(ht.Name() + " spot.ws.public con failed! " + err.Error())
+	}
+	ht.spotWsPublicClosedMtx.Lock()
+	ht.spotWsPublicClosed = false
+	ht.spotWsPublicClosedMtx.Unlock()
+	return nil
+}
+func (ht *Htx) SpotWsPublicSubscribe(channels []string) {
+	ht.wsPublicSend(ht.spotWsPublicConn, &ht.spotWsPublicConnMtx, "sub",
+		ht.wsPublicTopics(channels, ht.fromStdSpotSymbol, "mbp.refresh.5", "ticker"))
+}
+func (ht *Htx) SpotWsPublicUnsubscribe(channels []string) {
+	ht.wsPublicSend(ht.spotWsPublicConn, &ht.spotWsPublicConnMtx, "unsub",
+		ht.wsPublicTopics(channels, ht.fromStdSpotSymbol, "mbp.refresh.5", "ticker"))
+}
+func (ht *Htx) SpotWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (ht *Htx) SpotWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (ht *Htx) SpotWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (ht *Htx) SpotWsPublicLoop(ch chan<- any) {
+	defer ht.SpotWsPublicClose()
+	defer close(ch)
+
+	pingWait := 30 * time.Second // 服务端5秒ping一次
+	ht.spotWsPublicConn.SetReadDeadline(time.Now().Add(pingWait))
+	for {
+		_, recv, err := ht.spotWsPublicConn.ReadMessage()
+		if err != nil {
+			if !ht.SpotWsPublicIsClosed() {
+				ilog.Warning("%s", ht.Name()+" spot.ws.public channel read: "+err.Error())
+			}
+			break
+		}
+		ht.spotWsPublicConn.SetReadDeadline(time.Now().Add(pingWait))
+		msg := htWsPubMsgPool.Get().(*HtxWsPubMsg)
+		if ht.wsPublicRecv("spot.ws.public", ht.spotWsPublicConn, &ht.spotWsPublicConnMtx, recv, msg) {
+			ht.spotWsHandlePublicMsg(msg, ch)
+		}
+		htWsPubMsgPool.Put(msg)
+	}
+}
+func (ht *Htx) SpotWsPublicIsClosed() bool {
+	ht.spotWsPublicClosedMtx.RLock()
+	defer ht.spotWsPublicClosedMtx.RUnlock()
+	return ht.spotWsPublicClosed
+}
+func (ht *Htx) SpotWsPublicClose() {
+	ht.spotWsPublicClosedMtx.Lock()
+	defer ht.spotWsPublicClosedMtx.Unlock()
+	if ht.spotWsPublicClosed {
+		return
+	}
+	ht.spotWsPublicClosed = true
+	ht.spotWsPublicConn.Close()
+}
+
+// ch: market.btcusdt.mbp.refresh.5 / market.btcusdt.bbo / market.btcusdt.ticker
+func (ht *Htx) spotWsHandlePublicMsg(msg *HtxWsPubMsg, ch chan<- any) {
+	arr := strings.Split(msg.Ch, ".")
+	if len(arr) < 3 {
+		return
+	}
+	symbol := ht.toStdSpotSymbol(arr[1])
+	if arr[2] == "mbp" {
+		var depth HtxOrderBook
+		if err := easyjson.Unmarshal(msg.Tick, &depth); err != nil {
+			ilog.Error("%s", ht.Name()+" spot.ws.public "+symbol+" orderbook5 exception")
+			return
+		}
+		obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+		obd.Symbol = symbol
+		obd.Level = 5
+		obd.Time = msg.Ts
+		obd.Bids = obd.Bids[:0]
+		obd.Asks = obd.Asks[:0]
+		for _, v := range depth.Bids {
+			obd.Bids = append(obd.Bids, Ticker{Price: v[0], Quantity: v[1]})
+		}
+		for _, v := range depth.Asks {
+			obd.Asks = append(obd.Asks, Ticker{Price: v[0], Quantity: v[1]})
+		}
+		ch <- obd
+	} else if arr[2] == "bbo" {
+		var tick HtxSpotBBO
+		if err := easyjson.Unmarshal(msg.Tick, &tick); err != nil {
+			ilog.Error("%s", ht.Name()+" spot.ws.public "+symbol+" bbo exception")
+			return
+		}
+		bbo := wsPublicBBOPool.Get().(*BestBidAsk)
+		bbo.Symbol = symbol
+		bbo.Time = tick.QuoteTime
+		bbo.BidPrice = tick.Bid
+		bbo.BidQty = tick.BidSize
+		bbo.AskPrice = tick.Ask
+		bbo.AskQty = tick.AskSize
+		ch <- bbo
+	} else if arr[2] == "ticker" {
+		var tick HtxTicker
+		if err := easyjson.Unmarshal(msg.Tick, &tick); err != nil {
+			ilog.Error("%s", ht.Name()+" spot.ws.public "+symbol+" ticker exception")
+			return
+		}
+		tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+		tk.Symbol = symbol
+		tk.LastPrice = tick.Close
+		tk.Volume = tick.Amount
+		tk.QuoteVolume = tick.Vol
+		tk.BaseVolume = decimal.Zero
+		ch <- tk
+	}
+}
+
+// = priv channel
+func (ht *Htx) SpotWsPrivateSupported() bool {
+	return true
+}
+
+// 签名串: GET\nhost\n/ws/v2\n按key排序的参数, signatureVersion=2.1, host/path取替换后的地址
+func (ht *Htx) SpotWsPrivateOpen() error {
+	conn, err := ht.wsDial(htSpotWsPrivateUrl)
+	if err != nil {
+		return errors.New(ht.Name() + " spot.ws.priv con failed! " + err.Error())
+	}
+	ts := time.Now().UTC().Format("2006-01-02T15:04:05")
+	params := url.Values{}
+	params.Set("accessKey", ht.apikey)
+	params.Set("signatureMethod", "HmacSHA256")
+	params.Set("signatureVersion", "2.1")
+	params.Set("timestamp", ts)
+	host, path := htSplitUrl(ht.wsUrl(htSpotWsPrivateUrl))
+	auth := map[string]any{
+		"action": "req",
+		"ch":     "auth",
+		"params": map[string]string{
+			"authType":         "api",
+			"accessKey":        ht.apikey,
+			"signatureMethod":  "HmacSHA256",
+			"signatureVersion": "2.1",
+			"timestamp":        ts,
+			"signature":        ht.sign("GET\n" + host + "\n" + path + "\n" + params.Encode()),
+		},
+	}
+	req, _ := json.Marshal(auth)
+	if err = conn.WriteMessage(websocket.TextMessage, req); err != nil {
+		conn.Close()
+		return errors.New(ht.Name() + " spot.ws.priv auth failed! " + err.Error())
+	}
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	_, recv, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return errors.New(ht.Name() + " spot.ws.priv auth failed! " + err.Error())
+	}
+	msg := HtxWsPrivMsg{}
+	if err = easyjson.Unmarshal(recv, &msg); err != nil || msg.Ch != "auth" || msg.Code != 200 {
+		conn.Close()
+		return errors.New(ht.Name() + " spot.ws.priv auth failed! " + string(recv))
+	}
+	ht.spotWsPrivateConn = conn
+	ht.spotWsPrivateClosedMtx.Lock()
+	ht.spotWsPrivateClosed = false
+	ht.spotWsPrivateClosedMtx.Unlock()
+	return nil
+}
+
+// channels: orders, balance
+func (ht *Htx) SpotWsPrivateSubscribe(channels []string) {
+	ht.spotWsPrivateConnMtx.Lock()
+	defer ht.spotWsPrivateConnMtx.Unlock()
+	for _, c := range channels {
+		topic := ""
+		if c == "orders" {
+			topic = "orders#*"
+		} else if c == "balance" {
+			topic = "accounts.update#1" // 可用和总余额变化都推送
+		} else {
+			continue
+		}
+		req := `{"action":"sub","ch":"` + topic + `"}`
+		if err := ht.spotWsPrivateConn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+			ilog.Warning("%s", ht.Name()+" spot.ws.priv subscribe net error! "+err.Error())
+			return
+		}
+	}
+}
+func (ht *Htx) SpotWsPrivateIsClosed() bool {
+	ht.spotWsPrivateClosedMtx.RLock()
+	defer ht.spotWsPrivateClosedMtx.RUnlock()
+	return ht.spotWsPrivateClosed
+}
+func (ht *Htx) SpotWsPrivateClose() {
+	ht.spotWsPrivateClosedMtx.Lock()
+	defer ht.spotWsPrivateClosedMtx.Unlock()
+	if ht.spotWsPrivateClosed {
+		return
+	}
+	ht.spotWsPrivateClosed = true
+	ht.spotWsPrivateConn.Close()
+}
+func (ht *Htx) SpotWsPrivateLoop(ch chan<- any) {
+	defer ht.SpotWsPrivateClose()
+	defer close(ch)
+
+	pingWait := 45 * time.Second // 服务端20秒ping一次
+	ht.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pingWait))
+	for {
+		_, recv, err := ht.spotWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !ht.SpotWsPrivateIsClosed() {
+				ilog.Warning("%s", ht.Name()+" spot.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		ht.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pingWait))
+		if ht.debug {
+			ilog.Rinfo("%s", ht.Name()+" spot priv ws: "+string(recv))
+		}
+		msg := htWsPrivMsgPool.Get().(*HtxWsPrivMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", ht.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Action == "ping" {
+			ht.spotWsPrivateConnMtx.Lock()
+			ht.spotWsPrivateConn.WriteMessage(websocket.TextMessage,
+				[]byte(`{"action":"pong","data":`+string(msg.Data)+`}`))
+			ht.spotWsPrivateConnMtx.Unlock()
+		} else if msg.Action == "push" {
+			if strings.HasPrefix(msg.Ch, "orders#") {
+				ht.spotWsHandleOrder(msg.Data, ch)
+			} else if strings.HasPrefix(msg.Ch, "accounts.update#") {
+				ht.spotWsHandleBalance(msg.Data, ch)
+			}
+		} else if msg.Code != 0 && msg.Code != 200 {
+			ilog.Error("%s", ht.Name()+" spot.ws.priv recv err: "+string(recv))
+		}
+	END:
+		htWsPrivMsgPool.Put(msg)
+	}
+}
+
+// eventType: creation/trade/cancellation, execAmt为累计成交数量
+func (ht *Htx) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	order := struct {
+		Symbol      string          `json:"symbol"`
+		OrderId     int64           `json:"orderId"`
+		ClientId    string          `json:"clientOrderId"`
+		Price       decimal.Decimal `json:"orderPrice"`
+		Size        decimal.Decimal `json:"orderSize"`
+		Type        string          `json:"type"`
+		Status      string          `json:"orderStatus"`
+		ExecAmt     decimal.Decimal `json:"execAmt"`
+		CreateTime  int64           `json:"orderCreateTime"`
+		TradeTime   int64           `json:"tradeTime"`
+		LastActTime int64           `json:"lastActTime"`
+	}{}
+	if err := json.Unmarshal(data, &order); err != nil {
+		ilog.Error("%s", ht.Name()+" spot.ws.priv handle order: "+err.Error())
+		return
+	}
+	side, typ, tif := ht.toStdSpotOrderType(order.Type)
+	o := &SpotOrder{
+		Symbol:      ht.toStdSpotSymbol(order.Symbol),
+		OrderId:     strconv.FormatInt(order.OrderId, 10),
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Size,
+		FilledQty:   order.ExecAmt,
+		Status:      ht.toStdSpotOrderStatus(order.Status),
+		Type:        typ,
+		TimeInForce: tif,
+		Side:        side,
+		CTime:       order.CreateTime,
+		UTime:       max(order.CreateTime, order.TradeTime, order.LastActTime),
+	}
+	ch <- o
+}
+func (ht *Htx) spotWsHandleBalance(data json.RawMessage, ch chan<- any) {
+	bl := struct {
+		Currency    string          `json:"currency"`
+		AccountType string          `json:"accountType"`
+		Balance     decimal.Decimal `json:"balance"`
+		Available   decimal.Decimal `json:"available"`
+	}{}
+	if err := json.Unmarshal(data, &bl); err != nil {
+		ilog.Error("%s", ht.Name()+" spot.ws.priv handle balance: "+err.Error())
+		return
+	}
+	if bl.AccountType != "trade" {
+		return
+	}
+	ch <- &SpotAsset{
+		Symbol: strings.ToUpper(bl.Currency),
+		Total:  bl.Balance,
+		Avail:  bl.Available,
+		Locked: bl.Balance.Sub(bl.Available),
+	}
+}
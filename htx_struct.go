@@ -0,0 +1,113 @@
+package cex
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// 行情推送(现货/永续格式相同), ping/订阅回复也是这个结构
+type HtxWsPubMsg struct {
+	Ping    int64           `json:"ping,omitempty"`
+	Ch      string          `json:"ch,omitempty"`
+	Ts      int64           `json:"ts,omitempty"`
+	Status  string          `json:"status,omitempty"`
+	ErrCode string          `json:"err-code,omitempty"`
+	ErrMsg  string          `json:"err-msg,omitempty"`
+	Tick    json.RawMessage `json:"tick,omitempty"`
+}
+
+func (v *HtxWsPubMsg) reset() {
+	v.Ping = 0
+	v.Ch = ""
+	v.Ts = 0
+	v.Status = ""
+	v.ErrCode = ""
+	v.ErrMsg = ""
+	v.Tick = nil
+}
+
+// v2 私有频道
+type HtxWsPrivMsg struct {
+	Action  string          `json:"action,omitempty"` // ping/req/sub/push
+	Ch      string          `json:"ch,omitempty"`
+	Code    int             `json:"code,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (v *HtxWsPrivMsg) reset() {
+	v.Action = ""
+	v.Ch = ""
+	v.Code = 0
+	v.Message = ""
+	v.Data = nil
+}
+
+type HtxOrderBook struct {
+	Bids [][2]decimal.Decimal `json:"bids,omitempty"`
+	Asks [][2]decimal.Decimal `json:"asks,omitempty"`
+	Ts   int64                `json:"ts,omitempty"` // msec 只有永续有
+}
+type HtxSpotBBO struct {
+	Bid       decimal.Decimal `json:"bid"`
+	BidSize   decimal.Decimal `json:"bidSize"`
+	Ask       decimal.Decimal `json:"ask"`
+	AskSize   decimal.Decimal `json:"askSize"`
+	QuoteTime int64           `json:"quoteTime"` // msec
+}
+
+// 数量为张数
+type HtxSwapBBO struct {
+	Bid [2]decimal.Decimal `json:"bid"`
+	Ask [2]decimal.Decimal `json:"ask"`
+	Ts  int64              `json:"ts"` // msec
+}
+
+// amount为标的数量, 现货vol为计价币成交额, 永续vol为张数 trade_turnover为成交额
+type HtxTicker struct {
+	Close         decimal.Decimal `json:"close"`
+	Amount        decimal.Decimal `json:"amount"`
+	Vol           decimal.Decimal `json:"vol"`
+	TradeTurnover decimal.Decimal `json:"trade_turnover"`
+}
+
+// 当前委托中成交字段为filled-*, 订单详情中为field-*
+type HtxSpotOrder struct {
+	Id              int64           `json:"id"`
+	Symbol          string          `json:"symbol"`
+	ClientId        string          `json:"client-order-id"`
+	Price           decimal.Decimal `json:"price"`
+	Amount          decimal.Decimal `json:"amount"` // 市价买单为计价币数量
+	Type            string          `json:"type"`
+	State           string          `json:"state"`
+	FieldAmount     decimal.Decimal `json:"field-amount"`
+	FieldCashAmount decimal.Decimal `json:"field-cash-amount"`
+	FieldFees       decimal.Decimal `json:"field-fees"`
+	FilledAmount    decimal.Decimal `json:"filled-amount"`
+	FilledCash      decimal.Decimal `json:"filled-cash-amount"`
+	FilledFees      decimal.Decimal `json:"filled-fees"`
+	CreatedAt       int64           `json:"created-at"`  // msec
+	FinishedAt      int64           `json:"finished-at"` // msec
+	CanceledAt      int64           `json:"canceled-at"` // msec
+}
+
+// volume/trade_volume 为张数
+type HtxFuturesOrder struct {
+	Symbol        string          `json:"contract_code"`
+	OrderId       string          `json:"order_id_str"`
+	ClientId      int64           `json:"client_order_id"`
+	Price         decimal.Decimal `json:"price"`
+	Volume        decimal.Decimal `json:"volume"`
+	Type          string          `json:"order_price_type"`
+	Direction     string          `json:"direction"`
+	Offset        string          `json:"offset"`
+	Status        int             `json:"status"`
+	TradeVolume   decimal.Decimal `json:"trade_volume"`
+	TradeTurnover decimal.Decimal `json:"trade_turnover"`
+	AvgPrice      decimal.Decimal `json:"trade_avg_price"`
+	Fee           decimal.Decimal `json:"fee"`
+	FeeAsset      string          `json:"fee_asset"`
+	CreatedAt     int64           `json:"created_at"`  // msec
+	UpdateTime    int64           `json:"update_time"` // msec
+}
@@ -0,0 +1,1368 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package cex
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	decimal "github.com/shopspring/decimal"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonF759caf8DecodeGithubComShaovieCex(in *jlexer.Lexer, out *HtxWsPubMsg) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "ping":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ping = int64(in.Int64())
+			}
+		case "ch":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ch = string(in.String())
+			}
+		case "ts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ts = int64(in.Int64())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "err-code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ErrCode = string(in.String())
+			}
+		case "err-msg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ErrMsg = string(in.String())
+			}
+		case "tick":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Tick).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex(out *jwriter.Writer, in HtxWsPubMsg) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Ping != 0 {
+		const prefix string = ",\"ping\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Ping))
+	}
+	if in.Ch != "" {
+		const prefix string = ",\"ch\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Ch))
+	}
+	if in.Ts != 0 {
+		const prefix string = ",\"ts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Ts))
+	}
+	if in.Status != "" {
+		const prefix string = ",\"status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Status))
+	}
+	if in.ErrCode != "" {
+		const prefix string = ",\"err-code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ErrCode))
+	}
+	if in.ErrMsg != "" {
+		const prefix string = ",\"err-msg\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ErrMsg))
+	}
+	if len(in.Tick) != 0 {
+		const prefix string = ",\"tick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Tick).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxWsPubMsg) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxWsPubMsg) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxWsPubMsg) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxWsPubMsg) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *HtxWsPrivMsg) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "action":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Action = string(in.String())
+			}
+		case "ch":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ch = string(in.String())
+			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = int(in.Int())
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		case "data":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Data).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex1(out *jwriter.Writer, in HtxWsPrivMsg) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Action != "" {
+		const prefix string = ",\"action\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Action))
+	}
+	if in.Ch != "" {
+		const prefix string = ",\"ch\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Ch))
+	}
+	if in.Code != 0 {
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Code))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	if len(in.Data) != 0 {
+		const prefix string = ",\"data\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Data).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxWsPrivMsg) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxWsPrivMsg) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxWsPrivMsg) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxWsPrivMsg) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex1(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *HtxTicker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "close":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Close).UnmarshalJSON(data))
+				}
+			}
+		case "amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Amount).UnmarshalJSON(data))
+				}
+			}
+		case "vol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Vol).UnmarshalJSON(data))
+				}
+			}
+		case "trade_turnover":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.TradeTurnover).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex2(out *jwriter.Writer, in HtxTicker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"close\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Close).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"amount\":"
+		out.RawString(prefix)
+		out.Raw((in.Amount).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"vol\":"
+		out.RawString(prefix)
+		out.Raw((in.Vol).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"trade_turnover\":"
+		out.RawString(prefix)
+		out.Raw((in.TradeTurnover).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxTicker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxTicker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxTicker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex2(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *HtxSwapBBO) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('[')
+				v1 := 0
+				for !in.IsDelim(']') {
+					if v1 < 2 {
+						if in.IsNull() {
+							in.Skip()
+						} else {
+							if data := in.Raw(); in.Ok() {
+								in.AddError(((out.Bid)[v1]).UnmarshalJSON(data))
+							}
+						}
+						v1++
+					} else {
+						in.SkipRecursive()
+					}
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ask":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('[')
+				v2 := 0
+				for !in.IsDelim(']') {
+					if v2 < 2 {
+						if in.IsNull() {
+							in.Skip()
+						} else {
+							if data := in.Raw(); in.Ok() {
+								in.AddError(((out.Ask)[v2]).UnmarshalJSON(data))
+							}
+						}
+						v2++
+					} else {
+						in.SkipRecursive()
+					}
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ts = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex3(out *jwriter.Writer, in HtxSwapBBO) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bid\":"
+		out.RawString(prefix[1:])
+		out.RawByte('[')
+		for v3 := range in.Bid {
+			if v3 > 0 {
+				out.RawByte(',')
+			}
+			out.Raw(((in.Bid)[v3]).MarshalJSON())
+		}
+		out.RawByte(']')
+	}
+	{
+		const prefix string = ",\"ask\":"
+		out.RawString(prefix)
+		out.RawByte('[')
+		for v4 := range in.Ask {
+			if v4 > 0 {
+				out.RawByte(',')
+			}
+			out.Raw(((in.Ask)[v4]).MarshalJSON())
+		}
+		out.RawByte(']')
+	}
+	{
+		const prefix string = ",\"ts\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Ts))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxSwapBBO) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxSwapBBO) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxSwapBBO) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxSwapBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex3(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex4(in *jlexer.Lexer, out *HtxSpotOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Id = int64(in.Int64())
+			}
+		case "symbol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "client-order-id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Price).UnmarshalJSON(data))
+				}
+			}
+		case "amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Amount).UnmarshalJSON(data))
+				}
+			}
+		case "type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "state":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.State = string(in.String())
+			}
+		case "field-amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FieldAmount).UnmarshalJSON(data))
+				}
+			}
+		case "field-cash-amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FieldCashAmount).UnmarshalJSON(data))
+				}
+			}
+		case "field-fees":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FieldFees).UnmarshalJSON(data))
+				}
+			}
+		case "filled-amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FilledAmount).UnmarshalJSON(data))
+				}
+			}
+		case "filled-cash-amount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FilledCash).UnmarshalJSON(data))
+				}
+			}
+		case "filled-fees":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FilledFees).UnmarshalJSON(data))
+				}
+			}
+		case "created-at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CreatedAt = int64(in.Int64())
+			}
+		case "finished-at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FinishedAt = int64(in.Int64())
+			}
+		case "canceled-at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CanceledAt = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex4(out *jwriter.Writer, in HtxSpotOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Id))
+	}
+	{
+		const prefix string = ",\"symbol\":"
+		out.RawString(prefix)
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"client-order-id\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.Raw((in.Price).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"amount\":"
+		out.RawString(prefix)
+		out.Raw((in.Amount).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"field-amount\":"
+		out.RawString(prefix)
+		out.Raw((in.FieldAmount).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"field-cash-amount\":"
+		out.RawString(prefix)
+		out.Raw((in.FieldCashAmount).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"field-fees\":"
+		out.RawString(prefix)
+		out.Raw((in.FieldFees).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"filled-amount\":"
+		out.RawString(prefix)
+		out.Raw((in.FilledAmount).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"filled-cash-amount\":"
+		out.RawString(prefix)
+		out.Raw((in.FilledCash).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"filled-fees\":"
+		out.RawString(prefix)
+		out.Raw((in.FilledFees).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created-at\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CreatedAt))
+	}
+	{
+		const prefix string = ",\"finished-at\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.FinishedAt))
+	}
+	{
+		const prefix string = ",\"canceled-at\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CanceledAt))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxSpotOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxSpotOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxSpotOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxSpotOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex4(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex5(in *jlexer.Lexer, out *HtxSpotBBO) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Bid).UnmarshalJSON(data))
+				}
+			}
+		case "bidSize":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.BidSize).UnmarshalJSON(data))
+				}
+			}
+		case "ask":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Ask).UnmarshalJSON(data))
+				}
+			}
+		case "askSize":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AskSize).UnmarshalJSON(data))
+				}
+			}
+		case "quoteTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.QuoteTime = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex5(out *jwriter.Writer, in HtxSpotBBO) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bid\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Bid).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"bidSize\":"
+		out.RawString(prefix)
+		out.Raw((in.BidSize).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"ask\":"
+		out.RawString(prefix)
+		out.Raw((in.Ask).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"askSize\":"
+		out.RawString(prefix)
+		out.Raw((in.AskSize).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"quoteTime\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.QuoteTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxSpotBBO) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxSpotBBO) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxSpotBBO) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxSpotBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex5(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex6(in *jlexer.Lexer, out *HtxOrderBook) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bids":
+			if in.IsNull() {
+				in.Skip()
+				out.Bids = nil
+			} else {
+				in.Delim('[')
+				if out.Bids == nil {
+					if !in.IsDelim(']') {
+						out.Bids = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Bids = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Bids = (out.Bids)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v6 := 0
+						for !in.IsDelim(']') {
+							if v6 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v5)[v6]).UnmarshalJSON(data))
+									}
+								}
+								v6++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Bids = append(out.Bids, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "asks":
+			if in.IsNull() {
+				in.Skip()
+				out.Asks = nil
+			} else {
+				in.Delim('[')
+				if out.Asks == nil {
+					if !in.IsDelim(']') {
+						out.Asks = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Asks = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Asks = (out.Asks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v8 := 0
+						for !in.IsDelim(']') {
+							if v8 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v7)[v8]).UnmarshalJSON(data))
+									}
+								}
+								v8++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Asks = append(out.Asks, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ts = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex6(out *jwriter.Writer, in HtxOrderBook) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Bids) != 0 {
+		const prefix string = ",\"bids\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v9, v10 := range in.Bids {
+				if v9 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v11 := range v10 {
+					if v11 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v10)[v11]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Asks) != 0 {
+		const prefix string = ",\"asks\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v12, v13 := range in.Asks {
+				if v12 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v14 := range v13 {
+					if v14 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v13)[v14]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Ts != 0 {
+		const prefix string = ",\"ts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Ts))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxOrderBook) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxOrderBook) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxOrderBook) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxOrderBook) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex6(l, v)
+}
+func easyjsonF759caf8DecodeGithubComShaovieCex7(in *jlexer.Lexer, out *HtxFuturesOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "contract_code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "order_id_str":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = string(in.String())
+			}
+		case "client_order_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = int64(in.Int64())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Price).UnmarshalJSON(data))
+				}
+			}
+		case "volume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "order_price_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "direction":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Direction = string(in.String())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = int(in.Int())
+			}
+		case "trade_volume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.TradeVolume).UnmarshalJSON(data))
+				}
+			}
+		case "trade_turnover":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.TradeTurnover).UnmarshalJSON(data))
+				}
+			}
+		case "trade_avg_price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AvgPrice).UnmarshalJSON(data))
+				}
+			}
+		case "fee":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Fee).UnmarshalJSON(data))
+				}
+			}
+		case "fee_asset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FeeAsset = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CreatedAt = int64(in.Int64())
+			}
+		case "update_time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UpdateTime = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF759caf8EncodeGithubComShaovieCex7(out *jwriter.Writer, in HtxFuturesOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"contract_code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"order_id_str\":"
+		out.RawString(prefix)
+		out.String(string(in.OrderId))
+	}
+	{
+		const prefix string = ",\"client_order_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ClientId))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.Raw((in.Price).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"volume\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"order_price_type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"direction\":"
+		out.RawString(prefix)
+		out.String(string(in.Direction))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.String(string(in.Offset))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.Int(int(in.Status))
+	}
+	{
+		const prefix string = ",\"trade_volume\":"
+		out.RawString(prefix)
+		out.Raw((in.TradeVolume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"trade_turnover\":"
+		out.RawString(prefix)
+		out.Raw((in.TradeTurnover).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"trade_avg_price\":"
+		out.RawString(prefix)
+		out.Raw((in.AvgPrice).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"fee\":"
+		out.RawString(prefix)
+		out.Raw((in.Fee).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"fee_asset\":"
+		out.RawString(prefix)
+		out.String(string(in.FeeAsset))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CreatedAt))
+	}
+	{
+		const prefix string = ",\"update_time\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.UpdateTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HtxFuturesOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF759caf8EncodeGithubComShaovieCex7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HtxFuturesOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF759caf8EncodeGithubComShaovieCex7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HtxFuturesOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF759caf8DecodeGithubComShaovieCex7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HtxFuturesOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF759caf8DecodeGithubComShaovieCex7(l, v)
+}
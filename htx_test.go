@@ -0,0 +1,77 @@
+package cex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newHtxTestServer(t *testing.T, fn http.HandlerFunc) *Htx {
+	srv := httptest.NewServer(fn)
+	t.Cleanup(srv.Close)
+	ex, err := New("htx", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ex.(*Htx)
+}
+func TestHtxFuturesGetKLine(t *testing.T) {
+	var query map[string][]string
+	ht := newHtxTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/linear-swap-ex/market/history/kline" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"status":"ok","data":[` +
+			`{"id":1700000000,"open":100,"high":110,"low":90,"close":105,"amount":2,"vol":20,"trade_turnover":210},` +
+			`{"id":1700000060,"open":105,"high":106,"low":104,"close":106,"amount":1,"vol":10,"trade_turnover":105}]}`))
+	})
+	l, err := ht.FuturesGetKLine("UM", "BTCUSDT", "1m", 1700000000, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query["contract_code"][0] != "BTC-USDT" || query["period"][0] != "1min" ||
+		query["from"][0] != "1700000000" || query["to"][0] != "1700000119" || query["size"] != nil {
+		t.Fatalf("unexpected query %v", query)
+	}
+	if len(l) != 2 || l[1].OpenTime != 1700000060 || !l[0].Volume.Equal(decimal.NewFromInt(2)) ||
+		!l[0].QuoteVolume.Equal(decimal.NewFromInt(210)) || !l[1].ClosePrice.Equal(decimal.NewFromInt(106)) {
+		t.Fatalf("unexpected klines %+v", l)
+	}
+
+	if _, err = ht.FuturesGetKLine("UM", "BTCUSDT", "1m", 0, 0, 100); err != nil {
+		t.Fatal(err)
+	}
+	if query["size"][0] != "100" || query["from"] != nil {
+		t.Fatalf("unexpected query %v", query)
+	}
+	if _, err = ht.FuturesGetKLine("UM", "BTCUSDT", "6h", 0, 0, 100); err == nil {
+		t.Fatal("want interval not support error")
+	}
+}
+func TestHtxFuturesGetAllPositions(t *testing.T) {
+	ht := newHtxTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","data":[` +
+			`{"contract_code":"BTC-USDT","volume":3,"cost_open":100,"profit_unreal":1,"lever_rate":5,"direction":"buy","position_mode":"dual_side"},` +
+			`{"contract_code":"BTC-USDT","volume":2,"cost_open":110,"profit_unreal":-1,"lever_rate":5,"direction":"sell","position_mode":"dual_side"},` +
+			`{"contract_code":"ETH-USDT","volume":4,"cost_open":10,"profit_unreal":0,"lever_rate":3,"direction":"sell","position_mode":"single_side"}]}`))
+	})
+	htxContractSizeMapMtx.Lock()
+	htxContractSizeMap["BTCUSDT"] = decimal.NewFromFloat(0.001)
+	htxContractSizeMap["ETHUSDT"] = decimal.NewFromFloat(0.01)
+	htxContractSizeMapMtx.Unlock()
+	m, err := ht.FuturesGetAllPositions("UM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc, eth := m["BTCUSDT"], m["ETHUSDT"]
+	if btc == nil || btc.Mode != 1 || !btc.Buy.Qty.Equal(decimal.NewFromFloat(0.003)) ||
+		!btc.Sell.Qty.Equal(decimal.NewFromFloat(0.002)) || !btc.Sell.EntryPrice.Equal(decimal.NewFromInt(110)) {
+		t.Fatalf("unexpected BTCUSDT positions %+v", btc)
+	}
+	if eth == nil || eth.Mode != 0 || eth.Both.Side != "SELL" || !eth.Both.Qty.Equal(decimal.NewFromFloat(0.04)) {
+		t.Fatalf("unexpected ETHUSDT positions %+v", eth)
+	}
+}
@@ -0,0 +1,205 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// chain 为HTX的链名, 如 trc20usdt, 为空使用币种默认链
+func (ht *Htx) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal) (*WithdrawReturn, error) {
+	params := map[string]any{
+		"address":  addr,
+		"currency": strings.ToLower(symbol),
+		"amount":   qty.String(),
+	}
+	if chain != "" {
+		params["chain"] = chain
+	}
+	if memo != "" {
+		params["addr-tag"] = memo
+	}
+	var wid int64
+	if err := ht.spotPost("/v1/dw/withdraw/api/create", params, &wid); err != nil {
+		return nil, err
+	}
+	return &WithdrawReturn{
+		Symbol: symbol,
+		WId:    strconv.FormatInt(wid, 10),
+	}, nil
+}
+func (ht *Htx) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
+	query := url.Values{}
+	query.Set("currency", strings.ToLower(symbol))
+	query.Set("type", "withdraw")
+	query.Set("size", "100")
+	link := ht.signedUrl("GET", htSpotEndpoint, "/v1/query/deposit-withdraw", query)
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Status  string `json:"status"`
+		ErrCode string `json:"err-code"`
+		ErrMsg  string `json:"err-msg"`
+		Data    []struct {
+			Id        int64           `json:"id"`
+			Currency  string          `json:"currency"`
+			Txid      string          `json:"tx-hash"`
+			Qty       decimal.Decimal `json:"amount"`
+			Fee       decimal.Decimal `json:"fee"`
+			State     string          `json:"state"`
+			Info      string          `json:"error-msg"`
+			UpdatedAt int64           `json:"updated-at"` // msec
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Status != "ok" {
+		return nil, ht.apiError(httpCode, recv.ErrCode, recv.ErrMsg)
+	}
+	res := make([]WithdrawResult, 0, len(recv.Data))
+	for _, v := range recv.Data {
+		res = append(res, WithdrawResult{
+			WId:      strconv.FormatInt(v.Id, 10),
+			Symbol:   strings.ToUpper(v.Currency),
+			Status:   ht.toStdWithdrawStatus(v.State),
+			Txid:     v.Txid,
+			Info:     v.Info,
+			Qty:      v.Qty,
+			Fee:      v.Fee,
+			DoneTime: v.UpdatedAt / 1000,
+		})
+	}
+	return res, nil
+}
+
+// NORMAL 只支持 SPOT <-> UM_FUTURE(全仓USDT)
+// MASTER_TO_SUB/SUB_TO_MASTER 为母账户与子账户现货之间划转, subAccount 为子账户UID
+func (ht *Htx) Transfer(symbol, from, to, typ, subAccount string, qty decimal.Decimal) error {
+	if !qty.IsPositive() {
+		return errors.New(ht.Name() + " transfer qty <= 0. =" + qty.String())
+	}
+	if typ == "NORMAL" {
+		accType := map[string]string{"SPOT": "spot", "UM_FUTURE": "linear-swap"}
+		if accType[from] == "" || accType[to] == "" {
+			return errors.New(ht.Name() + " not support account type " + from + " -> " + to)
+		}
+		return ht.v2Post("/v2/account/transfer", map[string]any{
+			"from":           accType[from],
+			"to":             accType[to],
+			"currency":       strings.ToLower(symbol),
+			"amount":         qty.String(),
+			"margin-account": "USDT",
+		})
+	}
+	if subAccount == "" {
+		return errors.New(ht.Name() + " sub account is empty")
+	}
+	params := map[string]any{
+		"sub-uid":  subAccount,
+		"currency": strings.ToLower(symbol),
+		"amount":   qty.String(),
+	}
+	if typ == "MASTER_TO_SUB" {
+		params["type"] = "master-transfer-out"
+	} else if typ == "SUB_TO_MASTER" {
+		params["type"] = "master-transfer-in"
+	} else {
+		return errors.New(ht.Name() + " not support transfer type " + typ)
+	}
+	return ht.spotPost("/v1/subuser/transfer", params, nil)
+}
+
+// v2接口: code=200 成功
+func (ht *Htx) v2Post(path string, params map[string]any) error {
+	body, _ := json.Marshal(params)
+	link := ht.signedUrl("POST", htSpotEndpoint, path, nil)
+	httpCode, resp, err := ht.Post(link, body, htApiDeadline,
+		map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 200 {
+		return ht.apiError(httpCode, strconv.Itoa(recv.Code), recv.Message)
+	}
+	return nil
+}
+
+// htx没有独立的资金账户, 充提都走现货账户, 返回现货账户资产
+func (ht *Htx) FundingGetAllAssets() (map[string]*FundingAsset, error) {
+	spotAssets, err := ht.SpotGetAllAssets()
+	if err != nil {
+		return nil, err
+	}
+	assetsMap := make(map[string]*FundingAsset, len(spotAssets))
+	for symbol, v := range spotAssets {
+		assetsMap[symbol] = &FundingAsset{
+			Symbol: v.Symbol,
+			Total:  v.Total,
+			Avail:  v.Avail,
+			Locked: v.Locked,
+		}
+	}
+	return assetsMap, nil
+}
+func (ht *Htx) FundingGetAsset(symbol string) (FundingAsset, error) {
+	assetsMap, err := ht.FundingGetAllAssets()
+	if err != nil {
+		return FundingAsset{}, err
+	}
+	if v, ok := assetsMap[symbol]; ok {
+		return *v, nil
+	}
+	return FundingAsset{Symbol: symbol}, nil
+}
+
+// network 为空返回所有链的地址
+func (ht *Htx) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
+	query := url.Values{}
+	query.Set("currency", strings.ToLower(symbol))
+	link := ht.signedUrl("GET", htSpotEndpoint, "/v2/account/deposit/address", query)
+	httpCode, resp, err := ht.Get(link, htApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ht.Name(), err)
+	}
+	recv := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    []struct {
+			Addr    string `json:"address"`
+			Memo    string `json:"addressTag"`
+			Network string `json:"chain"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ht.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 200 {
+		return nil, ht.apiError(httpCode, strconv.Itoa(recv.Code), recv.Message)
+	}
+	daL := make([]DepositAddress, 0, len(recv.Data))
+	for _, v := range recv.Data {
+		if network != "" && !strings.EqualFold(v.Network, network) {
+			continue
+		}
+		daL = append(daL, DepositAddress{
+			Network: v.Network,
+			Addr:    v.Addr,
+			Memo:    v.Memo,
+		})
+	}
+	return daL, nil
+}
@@ -1,12 +1,14 @@
 package cex
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/base64"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +20,7 @@ import (
 
 type Kraken struct {
 	Unsupported
+	Http
 	name               string
 	account            string
 	apikey             string
@@ -34,6 +37,8 @@ type Kraken struct {
 	spotWsOrderBookAsks          map[string]*treemap.Map[decimal.Decimal, decimal.Decimal]
 	spotWsOrderBookSeqId         map[string]int64
 	spotWsOrderBookBid1Ask1Cache BestBidAsk
+	spotWsOrderBooks             localOrderBooks
+	spotWsKLines                 map[string]*KLine // symbol:interval 最近一根未结束的k线
 	spotWsOrderCachedInfo        map[string]*KrakenCachedOrder
 
 	spotWsPrivateConn             *websocket.Conn
@@ -56,6 +61,9 @@ var (
 	kkXStocksSymbolMap    map[string]string
 	kkXStocksSymbolMapMtx sync.RWMutex
 
+	kkSpotAltNameMap    map[string]string // altname(订单中的pair) -> BTCUSDT
+	kkSpotAltNameMapMtx sync.RWMutex
+
 	kkNonceSeq int64 // 单个apikey下的nonce必须是自增的
 )
 
@@ -66,13 +74,19 @@ func init() {
 	kkSpotSymbolMap = make(map[string]string)
 	kkSpotWssSymbolMap = make(map[string]string)
 	kkXStocksSymbolMap = make(map[string]string)
+	kkSpotAltNameMap = make(map[string]string)
 }
 func NewKraken(account, apikey, secretkey string) *Kraken {
 	cexObj := &Kraken{
-		name:      "kraken",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("kraken"),
+		},
+		Unsupported: Unsupported{name: "kraken"},
+		name:        "kraken",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
 	}
 	return cexObj
 }
@@ -91,6 +105,19 @@ func (kk *Kraken) Debug(v bool) {
 func (kk *Kraken) IsXStock(v string) bool {
 	return kk.isXStocksSymbol(v)
 }
+func (kk *Kraken) withContext(ctx context.Context) Exchanger {
+	cexObj := &Kraken{
+		Http:               kk.Http.withContext(ctx),
+		name:               kk.name,
+		account:            kk.account,
+		apikey:             kk.apikey,
+		secretkey:          kk.secretkey,
+		secretkeyHadDecode: kk.secretkeyHadDecode,
+		debug:              kk.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (kk *Kraken) Init() error {
 	kk.spotWsPublicClosed = true
 	kk.spotWsPrivateClosed = true
@@ -100,6 +127,7 @@ func (kk *Kraken) Init() error {
 	kk.spotWsOrderBookBids = make(map[string]*treemap.Map[decimal.Decimal, decimal.Decimal], 512)
 	kk.spotWsOrderBookAsks = make(map[string]*treemap.Map[decimal.Decimal, decimal.Decimal], 512)
 	kk.spotWsOrderBookSeqId = make(map[string]int64, 16)
+	kk.spotWsKLines = make(map[string]*KLine, 16)
 	kk.spotWsOrderCachedInfo = make(map[string]*KrakenCachedOrder, 16)
 
 	if kk.secretkeyHadDecode == false {
@@ -130,6 +158,9 @@ func (kk *Kraken) sign(nonce, path, params string) string {
 	h.Write(append([]byte(path), shaSum...))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
+func (kk *Kraken) apiError(httpStatus int, errs []string) error {
+	return newApiError(kk.Name(), httpStatus, errs[0], strings.Join(errs, ";"))
+}
 func (kk *Kraken) getSpotSymbol(symbol string) string {
 	kkSpotSymbolMapMtx.RLock()
 	defer kkSpotSymbolMapMtx.RUnlock()
@@ -140,6 +171,11 @@ func (kk *Kraken) getSpotWssSymbol(symbol string) string {
 	defer kkSpotWssSymbolMapMtx.RUnlock()
 	return kkSpotWssSymbolMap[symbol]
 }
+func (kk *Kraken) getStdSpotSymbol(altName string) string {
+	kkSpotAltNameMapMtx.RLock()
+	defer kkSpotAltNameMapMtx.RUnlock()
+	return kkSpotAltNameMap[altName]
+}
 func (kk *Kraken) isXStocksSymbol(symbol string) bool {
 	kkXStocksSymbolMapMtx.RLock()
 	defer kkXStocksSymbolMapMtx.RUnlock()
@@ -229,3 +265,11 @@ func (kk *Kraken) toStdWithdrawStatus(c string) string {
 	}
 	return ""
 }
+func (kk *Kraken) toStdDepositStatus(c string) string {
+	if c == "Success" {
+		return "CREDITED"
+	} else if c == "Failure" {
+		return "FAILED"
+	}
+	return "PENDING"
+}
@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
-
-	"github.com/shaovie/gutils/ihttp"
 )
 
 // = assets
@@ -18,9 +17,9 @@ func (kk *Kraken) SpotSupported() bool {
 }
 func (kk *Kraken) SpotServerTime() (int64, error) {
 	url := kkSpotEndpoint + "/0/public/Time"
-	_, resp, err := ihttp.Get(url, kkApiDeadline, nil)
+	_, resp, err := kk.Get(url, kkApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(kk.Name() + " net error! " + err.Error())
+		return 0, newNetError(kk.Name(), err)
 	}
 
 	recv := struct {
@@ -41,12 +40,13 @@ func (kk *Kraken) SpotServerTime() (int64, error) {
 }
 func (kk *Kraken) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	link := kkSpotEndpoint + "/0/public/AssetPairs"
-	_, resp, err := ihttp.Get(link, kkApiDeadline, nil)
+	_, resp, err := kk.Get(link, kkApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 
 	type Rule struct {
+		AltName     string          `json:"altname"`
 		WSName      string          `json:"wsname"`
 		Base        string          `json:"base"`
 		Quote       string          `json:"quote"`
@@ -69,6 +69,7 @@ func (kk *Kraken) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 	tkkSpotSymbolMap := make(map[string]string)
 	tkkSpotWssSymbolMap := make(map[string]string)
 	tkkXStocksSymbolMap := make(map[string]string)
+	tkkSpotAltNameMap := make(map[string]string)
 	for id, pair := range ret.Result {
 		if pair.Status != "online" && pair.Status != "post_only" { // open for trading
 			continue
@@ -94,13 +95,14 @@ func (kk *Kraken) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 		all[ep.Symbol] = ep
 		tkkSpotSymbolMap[ep.Symbol] = id
 		tkkSpotWssSymbolMap[ep.Symbol] = base + "/" + quote
+		tkkSpotAltNameMap[pair.AltName] = ep.Symbol
 	}
 
 	// get xstocks
 	link = kkSpotEndpoint + "/0/public/AssetPairs?aclass_base=tokenized_asset"
-	_, resp, err = ihttp.Get(link, kkApiDeadline, nil)
+	_, resp, err = kk.Get(link, kkApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	ret2 := struct {
 		Error  []string         `json:"error"`
@@ -139,6 +141,7 @@ func (kk *Kraken) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 		tkkSpotSymbolMap[ep.Symbol] = id
 		tkkSpotWssSymbolMap[ep.Symbol] = ep.Base + "/" + ep.Quote
 		tkkXStocksSymbolMap[ep.Symbol] = id
+		tkkSpotAltNameMap[pair.AltName] = ep.Symbol
 	}
 
 	kkSpotSymbolMapMtx.Lock()
@@ -152,6 +155,10 @@ func (kk *Kraken) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 	kkXStocksSymbolMapMtx.Lock()
 	kkXStocksSymbolMap = tkkXStocksSymbolMap
 	kkXStocksSymbolMapMtx.Unlock()
+
+	kkSpotAltNameMapMtx.Lock()
+	kkSpotAltNameMap = tkkSpotAltNameMap
+	kkSpotAltNameMapMtx.Unlock()
 	return all, nil
 }
 func (kk *Kraken) SpotGetAllAssets() (map[string]*SpotAsset, error) {
@@ -159,9 +166,9 @@ func (kk *Kraken) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	link := kkSpotEndpoint + path
 	values := url.Values{}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string          `json:"error,omitempty"`
@@ -172,7 +179,7 @@ func (kk *Kraken) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return nil, errors.New(kk.Name() + " spot get assets fail! " + recv.Error[0])
+		return nil, kk.apiError(httpCode, recv.Error)
 	}
 	if len(recv.Result) == 0 {
 		return nil, errors.New(kk.Name() + " spot get assets fail!")
@@ -197,9 +204,9 @@ func (kk *Kraken) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	if kk.isXStocksSymbol(symbol) {
 		url += "&asset_class=tokenized_asset"
 	}
-	_, resp, err := ihttp.Get(url, kkApiDeadline, nil)
+	_, resp, err := kk.Get(url, kkApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(kk.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(kk.Name(), err)
 	}
 
 	recv := struct {
@@ -263,9 +270,9 @@ func (kk *Kraken) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 		values.Set("asset_class", "tokenized_asset")
 	}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return "", errors.New(kk.Name() + " net error! " + err.Error())
+		return "", newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error,omitempty"`
@@ -278,7 +285,7 @@ func (kk *Kraken) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 		return "", errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return "", errors.New("spot order fail! " + recv.Error[0])
+		return "", kk.apiError(httpCode, recv.Error)
 	}
 
 	if len(recv.Result.OrderIds) == 0 {
@@ -287,6 +294,12 @@ func (kk *Kraken) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 
 	return recv.Result.OrderIds[0], nil
 }
+func (kk *Kraken) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(kk, orders), nil
+}
+func (kk *Kraken) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(kk, orders), nil
+}
 func (kk *Kraken) SpotCancelOrder(symbol string, orderId, cltId string) error {
 	path := "/0/private/CancelOrder"
 	link := kkSpotEndpoint + path
@@ -297,9 +310,9 @@ func (kk *Kraken) SpotCancelOrder(symbol string, orderId, cltId string) error {
 		values.Set("txid", orderId)
 	}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return errors.New(kk.Name() + " net error! " + err.Error())
+		return newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error,omitempty"`
@@ -312,10 +325,101 @@ func (kk *Kraken) SpotCancelOrder(symbol string, orderId, cltId string) error {
 		return errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return errors.New(kk.Name() + " spot cancel order fail! " + recv.Error[0])
+		return kk.apiError(httpCode, recv.Error)
+	}
+	return nil
+}
+func (kk *Kraken) SpotCancelAllOrders(symbol string) error {
+	if symbol != "" {
+		return errors.New(kk.Name() + " only support cancel all symbols")
+	}
+	path := "/0/private/CancelAll"
+	headers, params := kk.buildHeaders(path, url.Values{})
+	httpCode, resp, err := kk.Post(kkSpotEndpoint+path, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Error []string `json:"error,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(recv.Error) > 0 {
+		return kk.apiError(httpCode, recv.Error)
 	}
 	return nil
 }
+func (kk *Kraken) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	if typ != "SPOT" {
+		return newNotSupportError(kk.Name())
+	}
+	path := "/0/private/CancelAllOrdersAfter"
+	values := url.Values{}
+	values.Set("timeout", strconv.Itoa(timeout))
+	headers, params := kk.buildHeaders(path, values)
+	httpCode, resp, err := kk.Post(kkSpotEndpoint+path, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Error []string `json:"error,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(recv.Error) > 0 {
+		return kk.apiError(httpCode, recv.Error)
+	}
+	return nil
+}
+func (kk *Kraken) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	if orderId == "" { // EditOrder 不支持cl_ord_id
+		return nil, errors.New(kk.Name() + " orderId empty!")
+	}
+	path := "/0/private/EditOrder"
+	link := kkSpotEndpoint + path
+	values := url.Values{}
+	values.Set("txid", orderId)
+	values.Set("pair", kk.getSpotSymbol(symbol))
+	values.Set("price", price.String())
+	values.Set("volume", qty.String())
+	headers, params := kk.buildHeaders(path, values)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Error  []string `json:"error,omitempty"`
+		Result struct {
+			OrderId string          `json:"txid,omitempty"` // 新订单id
+			Status  string          `json:"status,omitempty"`
+			Price   decimal.Decimal `json:"price"`
+			Qty     decimal.Decimal `json:"volume"`
+			ErrMsg  string          `json:"error_message,omitempty"`
+		}
+	}{}
+	err = json.Unmarshal(resp, &recv)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(recv.Error) > 0 {
+		return nil, kk.apiError(httpCode, recv.Error)
+	}
+	if recv.Result.Status != "ok" || recv.Result.OrderId == "" {
+		return nil, errors.New(kk.Name() + " edit order fail! " + recv.Result.ErrMsg)
+	}
+	return &SpotOrder{
+		Symbol:  symbol,
+		OrderId: recv.Result.OrderId,
+		Price:   recv.Result.Price,
+		Qty:     recv.Result.Qty,
+		Status:  "NEW",
+		Type:    "LIMIT",
+		Side:    side,
+	}, nil
+}
 func (kk *Kraken) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	path := "/0/private/QueryOrders"
 	link := kkSpotEndpoint + path
@@ -323,9 +427,9 @@ func (kk *Kraken) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 	values.Set("consolidate_taker", "true")
 	values.Set("txid", orderId)
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	type orderDesc struct {
 		Symbol    string          `json:"pair"`
@@ -354,7 +458,7 @@ func (kk *Kraken) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(ret.Error) > 0 {
-		return nil, errors.New(kk.Name() + " spot get order fail! " + ret.Error[0])
+		return nil, kk.apiError(httpCode, ret.Error)
 	}
 
 	ord := ret.Result[orderId]
@@ -362,7 +466,7 @@ func (kk *Kraken) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 		return nil, errors.New(kk.Name() + " spot get order fail! orderId:" + orderId)
 	}
 	feeAsset := SpotSymbolQuote(kk.Name(), symbol) // Kraken手续费扣的全是报价币
-	return &SpotOrder{
+	so := &SpotOrder{
 		Symbol:    symbol,
 		OrderId:   orderId,
 		ClientId:  ord.ClientId,
@@ -378,5 +482,151 @@ func (kk *Kraken) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error
 		FeeAsset:  feeAsset,
 		CTime:     int64(ord.CTime * 1000),
 		UTime:     int64(ord.DoneTime * 1000),
-	}, nil
+	}
+	if so.Status == "NEW" && so.FilledQty.IsPositive() { // 部分成交的状态也是open
+		so.Status = "PARTIALLY_FILLED"
+	}
+	return so, nil
+}
+
+// kraken 不支持按pair查询挂单, 在本地过滤
+func (kk *Kraken) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	path := "/0/private/OpenOrders"
+	link := kkSpotEndpoint + path
+	headers, params := kk.buildHeaders(path, url.Values{})
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	type orderDesc struct {
+		Symbol    string          `json:"pair"`      // altname
+		OrderType string          `json:"ordertype"` // market/limit
+		Side      string          `json:"type"`      // buy/sell
+		Price     decimal.Decimal `json:"price"`
+	}
+	type tradeInfo struct {
+		ClientId     string          `json:"cl_ord_id"`
+		Status       string          `json:"status"` // pending,open
+		Desc         orderDesc       `json:"descr"`
+		Qty          decimal.Decimal `json:"vol"`
+		ExecutedQty  decimal.Decimal `json:"vol_exec"`
+		CummQuoteQty decimal.Decimal `json:"cost"`
+		AvgPrice     decimal.Decimal `json:"price"`
+		Fee          decimal.Decimal `json:"fee"`
+		CTime        float64         `json:"opentm"`
+	}
+	ret := struct {
+		Error  []string `json:"error"`
+		Result struct {
+			Open map[string]*tradeInfo `json:"open"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(ret.Error) > 0 {
+		return nil, kk.apiError(httpCode, ret.Error)
+	}
+	orders := make([]*SpotOrder, 0, len(ret.Result.Open))
+	for txid, ord := range ret.Result.Open {
+		stdSymbol := kk.getStdSpotSymbol(ord.Desc.Symbol)
+		if symbol != "" && stdSymbol != symbol {
+			continue
+		}
+		so := &SpotOrder{
+			Symbol:    stdSymbol,
+			OrderId:   txid,
+			ClientId:  ord.ClientId,
+			Price:     ord.Desc.Price,
+			Qty:       ord.Qty,
+			FilledQty: ord.ExecutedQty,
+			FilledAmt: ord.CummQuoteQty,
+			AvgPrice:  ord.AvgPrice,
+			Status:    kk.toStdOrderStatus(ord.Status),
+			Type:      kk.toStdOrderType(ord.Desc.OrderType),
+			Side:      kk.toStdSide(ord.Desc.Side),
+			FeeQty:    ord.Fee.Neg(),
+			FeeAsset:  SpotSymbolQuote(kk.Name(), stdSymbol), // Kraken手续费扣的全是报价币
+			CTime:     int64(ord.CTime * 1000),
+		}
+		if so.Status == "NEW" && so.FilledQty.IsPositive() {
+			so.Status = "PARTIALLY_FILLED"
+		}
+		orders = append(orders, so)
+	}
+	return orders, nil
+}
+
+// kraken 不支持6h,12h, 最多返回since之后的720根, endTime/limit 在本地过滤
+// 1m..1d 对应kraken的interval(分钟), 不支持6h,12h
+var kkKLineIntervals = map[string]string{"1m": "1", "5m": "5", "15m": "15", "30m": "30",
+	"1h": "60", "4h": "240", "1d": "1440"}
+
+func (kk *Kraken) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	itv, ok := kkKLineIntervals[interval]
+	if !ok {
+		return nil, errors.New(kk.Name() + " not support interval " + interval)
+	}
+	url := kkSpotEndpoint + "/0/public/OHLC?pair=" + kk.getSpotSymbol(symbol) + "&interval=" + itv
+	if startTime > 0 {
+		url += "&since=" + strconv.FormatInt(startTime-1, 10)
+	}
+	if kk.isXStocksSymbol(symbol) {
+		url += "&asset_class=tokenized_asset"
+	}
+	httpCode, resp, err := kk.Get(url, kkApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Err    []string                   `json:"error"`
+		Result map[string]json.RawMessage `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal error! " + err.Error())
+	}
+	if len(recv.Err) != 0 {
+		return nil, kk.apiError(httpCode, recv.Err)
+	}
+	// [time, open, high, low, close, vwap, volume, count]
+	var klines [][]any
+	for k, v := range recv.Result {
+		if k != "last" {
+			if err = json.Unmarshal(v, &klines); err != nil {
+				return nil, errors.New(kk.Name() + " unmarshal error! " + err.Error())
+			}
+			break
+		}
+	}
+	all := make([]KLine, 0, len(klines))
+	for _, v := range klines {
+		if len(v) < 7 {
+			continue
+		}
+		kl := KLine{}
+		ts, _ := v[0].(float64)
+		kl.OpenTime = int64(ts)
+		if kl.OpenTime < startTime || (endTime > 0 && kl.OpenTime >= endTime) {
+			continue
+		}
+		kl.OpenPrice, _ = decimal.NewFromString(v[1].(string))
+		kl.HighPrice, _ = decimal.NewFromString(v[2].(string))
+		kl.LowPrice, _ = decimal.NewFromString(v[3].(string))
+		kl.ClosePrice, _ = decimal.NewFromString(v[4].(string))
+		vwap, _ := decimal.NewFromString(v[5].(string))
+		kl.Volume, _ = decimal.NewFromString(v[6].(string))
+		kl.QuoteVolume = vwap.Mul(kl.Volume)
+
+		all = append(all, kl)
+	}
+	if limit > 0 && int64(len(all)) > limit {
+		if startTime > 0 {
+			all = all[:limit]
+		} else {
+			all = all[int64(len(all))-limit:]
+		}
+	}
+	return all, nil
 }
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,7 +15,6 @@ import (
 	"github.com/emirpasic/gods/v2/maps/treemap"
 	"github.com/gorilla/websocket"
 	"github.com/mailru/easyjson"
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shaovie/gutils/ilog"
 	"github.com/shopspring/decimal"
 )
@@ -43,7 +44,7 @@ func init() {
 }
 
 func (kk *Kraken) SpotWsPublicOpen() error {
-	url := "wss://ws.kraken.com/v2"
+	url := kk.wsUrl("wss://ws.kraken.com/v2")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -80,6 +81,28 @@ func (kk *Kraken) SpotWsPublicSubscribe(channels []string) {
 					ob5Symbols = append(ob5Symbols, sym)
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			for v := range strings.SplitSeq(arr[1], ",") {
+				sym, level := parseOrderBookSymbol(v)
+				if wsSym := kk.getSpotWssSymbol(sym); wsSym != "" {
+					kk.spotWsOrderBooks.add(sym, level)
+					kk.spotWsOrderBookRequest("subscribe", wsSym, level)
+				}
+			}
+		} else if arr[0] == "kline" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			for v := range strings.SplitSeq(arr[1], ",") {
+				sym, interval := parseKLineSymbol(v)
+				itv, ok := kkKLineIntervals[interval]
+				if wsSym := kk.getSpotWssSymbol(sym); wsSym != "" && ok {
+					kk.spotWsKLineRequest("subscribe", wsSym, itv)
+				}
+			}
 		} else if arr[0] == "bbo" { // 用ticker实现
 			var symbolArr []string
 			if len(arr) > 1 && len(arr[1]) > 0 {
@@ -132,6 +155,29 @@ func (kk *Kraken) SpotWsPublicUnsubscribe(channels []string) {
 					ob5Symbols = append(ob5Symbols, sym)
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			for v := range strings.SplitSeq(arr[1], ",") {
+				sym, _ := parseOrderBookSymbol(v)
+				ob := kk.spotWsOrderBooks.get(sym)
+				if wsSym := kk.getSpotWssSymbol(sym); wsSym != "" && ob != nil {
+					kk.spotWsOrderBooks.remove(sym)
+					kk.spotWsOrderBookRequest("unsubscribe", wsSym, ob.level)
+				}
+			}
+		} else if arr[0] == "kline" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			for v := range strings.SplitSeq(arr[1], ",") {
+				sym, interval := parseKLineSymbol(v)
+				itv, ok := kkKLineIntervals[interval]
+				if wsSym := kk.getSpotWssSymbol(sym); wsSym != "" && ok {
+					kk.spotWsKLineRequest("unsubscribe", wsSym, itv)
+				}
+			}
 		} else if arr[0] == "bbo" {
 			var symbolArr []string
 			if len(arr) > 1 && len(arr[1]) > 0 {
@@ -176,6 +222,9 @@ func (kk *Kraken) SpotWsPublicBBOPoolPut(v any) {
 func (kk *Kraken) SpotWsPublicTradePoolPut(v any) {
 	wsPublicTradePool.Put(v)
 }
+func (kk *Kraken) SpotWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (kk *Kraken) SpotWsPublicLoop(ch chan<- any) {
 	defer kk.SpotWsPublicClose()
 	defer close(ch)
@@ -208,19 +257,20 @@ func (kk *Kraken) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := kk.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !kk.SpotWsPublicIsClosed() {
-				ilog.Warning(kk.Name() + " spot.ws.public channel read: " + err.Error())
+				ilog.Warning("%s", kk.Name()+" spot.ws.public channel read: "+err.Error())
 			}
 			break
 		}
 		msg := kkWsMsgPool.Get().(*KrakenWsMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(kk.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", kk.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
 
 		if msg.Channel == "book" {
-			if msg.Type == "snapshot" {
+			if kk.spotWsHandleOrderBook(msg.Type, msg.Data, ch) {
+			} else if msg.Type == "snapshot" {
 				if symbol, ok := kk.spotWsHandleOrderBookSnap(msg.Data); ok {
 					kk.spotWsHandleOrderBook1(symbol, ch)
 				}
@@ -231,13 +281,15 @@ func (kk *Kraken) SpotWsPublicLoop(ch chan<- any) {
 			}
 		} else if msg.Channel == "ticker" {
 			kk.spotWsHandleBBO(msg.Data, ch) // snap or update are same
+		} else if msg.Channel == "ohlc" {
+			kk.spotWsHandleKLine(msg.Data, ch)
 		} else if msg.Channel == "heartbeat" || msg.Channel == "status" {
 		} else if msg.Method == "pong" {
 			kk.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
 		} else if msg.Method == "subscribe" {
 		} else if msg.Method == "unsubscribe" {
 		} else {
-			ilog.Error(kk.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+			ilog.Error("%s", kk.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 		}
 	END:
 		kkWsMsgPool.Put(msg)
@@ -258,6 +310,82 @@ func (kk *Kraken) SpotWsPublicClose() {
 	kk.spotWsPublicConn.Close()
 }
 
+type KrakenWsKLine struct {
+	Symbol        string          `json:"symbol"` // BTC/USD
+	Open          decimal.Decimal `json:"open"`
+	High          decimal.Decimal `json:"high"`
+	Low           decimal.Decimal `json:"low"`
+	Close         decimal.Decimal `json:"close"`
+	Volume        decimal.Decimal `json:"volume"`
+	Vwap          decimal.Decimal `json:"vwap"`
+	IntervalBegin string          `json:"interval_begin"` // RFC3339
+	Interval      int             `json:"interval"`       // minute
+}
+
+func (kk *Kraken) spotWsKLineRequest(method, wsSymbol, interval string) {
+	req := fmt.Sprintf(`{"method":"%s","params":{"channel":"ohlc","interval":%s,"symbol":["%s"]}}`,
+		method, interval, wsSymbol)
+	kk.spotWsPublicConnMtx.Lock()
+	kk.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
+	kk.spotWsPublicConnMtx.Unlock()
+}
+
+// kraken不推送k线是否结束, 收到下一周期的数据时把缓存的上一根以IsClosed=true推送出去
+// 同一条消息里同一个symbol的多根k线(snapshot), 只有最后一根以未结束状态推送
+func (kk *Kraken) spotWsHandleKLine(data json.RawMessage, ch chan<- any) {
+	var list []KrakenWsKLine
+	if err := json.Unmarshal(data, &list); err != nil {
+		return
+	}
+	for i := range list {
+		kl := &list[i]
+		before, after, ok := strings.Cut(kl.Symbol, "/")
+		if !ok {
+			continue
+		}
+		begin, err := time.Parse(time.RFC3339Nano, kl.IntervalBegin)
+		if err != nil {
+			continue
+		}
+		interval := ""
+		for k, v := range kkKLineIntervals {
+			if v == strconv.Itoa(kl.Interval) {
+				interval = k
+				break
+			}
+		}
+		symbol := before + after
+		key := symbol + ":" + interval
+		last := kk.spotWsKLines[key]
+		if last != nil && begin.Unix() < last.OpenTime { // 旧数据
+			continue
+		}
+		if last != nil && begin.Unix() > last.OpenTime {
+			v := wsPublicKLinePool.Get().(*KLine)
+			*v = *last
+			v.IsClosed = true
+			ch <- v
+		}
+		if last == nil {
+			last = &KLine{Symbol: symbol, Interval: interval}
+			kk.spotWsKLines[key] = last
+		}
+		last.OpenTime = begin.Unix()
+		last.OpenPrice = kl.Open
+		last.HighPrice = kl.High
+		last.LowPrice = kl.Low
+		last.ClosePrice = kl.Close
+		last.Volume = kl.Volume
+		last.QuoteVolume = kl.Vwap.Mul(kl.Volume)
+		if i+1 < len(list) && list[i+1].Symbol == kl.Symbol && list[i+1].Interval == kl.Interval {
+			continue
+		}
+		v := wsPublicKLinePool.Get().(*KLine)
+		*v = *last
+		ch <- v
+	}
+}
+
 type KrakenSpotOrderBook struct {
 	Symbol string `json:"symbol"`
 	Bids   []struct {
@@ -354,6 +482,93 @@ func (kk *Kraken) spotWsHandleOrderBookUpdate(data json.RawMessage) (string, boo
 	return "", false
 }
 
+// book 频道只支持 10,25,100,500,1000 档, level=0 取1000档
+func (kk *Kraken) spotOrderBookDepth(level int) int {
+	for _, depth := range []int{10, 25, 100, 500} {
+		if level > 0 && level <= depth {
+			return depth
+		}
+	}
+	return 1000
+}
+func (kk *Kraken) spotWsOrderBookRequest(method, wsSymbol string, level int) {
+	req := fmt.Sprintf(`{"method":"%s","params":{"channel":"book","depth":%d,"symbol":["%s"]}}`,
+		method, kk.spotOrderBookDepth(level), wsSymbol)
+	kk.spotWsPublicConnMtx.Lock()
+	kk.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
+	kk.spotWsPublicConnMtx.Unlock()
+}
+
+// orderbook@ 订阅的交易对返回true
+// kraken只推送订阅档位内的变化, 每次更新后要截掉多余的档位, 再校验checksum
+// 校验失败时重新订阅, 交易所会重新推快照
+func (kk *Kraken) spotWsHandleOrderBook(typ string, data json.RawMessage, ch chan<- any) bool {
+	type Level struct {
+		Price json.Number `json:"price"`
+		Qty   json.Number `json:"qty"`
+	}
+	obs := []struct {
+		Symbol    string  `json:"symbol"`
+		Bids      []Level `json:"bids"`
+		Asks      []Level `json:"asks"`
+		Checksum  uint32  `json:"checksum"`
+		Timestamp string  `json:"timestamp"`
+	}{}
+	if err := json.Unmarshal(data, &obs); err != nil || len(obs) == 0 {
+		return false
+	}
+	handled := false
+	for i := range obs {
+		wsSymbol := obs[i].Symbol
+		ob := kk.spotWsOrderBooks.get(strings.ReplaceAll(wsSymbol, "/", ""))
+		if ob == nil {
+			continue
+		}
+		handled = true
+		if typ == "snapshot" {
+			ob.reset()
+			ob.synced = true
+		} else if !ob.synced { // 等待重新订阅后的快照
+			continue
+		}
+		for _, v := range obs[i].Bids {
+			ob.set(true, v.Price.String(), v.Qty.String())
+		}
+		for _, v := range obs[i].Asks {
+			ob.set(false, v.Price.String(), v.Qty.String())
+		}
+		ob.truncate(kk.spotOrderBookDepth(ob.level))
+		ob.seqId = int64(obs[i].Checksum)
+		if ts, err := time.Parse(time.RFC3339Nano, obs[i].Timestamp); err == nil {
+			ob.time = ts.UnixMilli()
+		}
+		if kk.orderBookChecksum(ob) != obs[i].Checksum || ob.crossed() {
+			ilog.Warning("%s", kk.Name()+" spot.ws.public "+wsSymbol+" orderbook checksum error, resync")
+			ob.reset()
+			kk.spotWsOrderBookRequest("unsubscribe", wsSymbol, ob.level)
+			kk.spotWsOrderBookRequest("subscribe", wsSymbol, ob.level)
+			continue
+		}
+		ch <- ob.snapshot()
+	}
+	return handled
+}
+
+// crc32(前10档卖盘 + 前10档买盘), 每档为 去掉小数点和前导0的价格 + 去掉小数点和前导0的数量
+func (kk *Kraken) orderBookChecksum(ob *localOrderBook) uint32 {
+	trim := func(v string) string {
+		return strings.TrimLeft(strings.ReplaceAll(v, ".", ""), "0")
+	}
+	var buf strings.Builder
+	for _, l := range ob.top(false, 10) {
+		buf.WriteString(trim(l.px) + trim(l.sz))
+	}
+	for _, l := range ob.top(true, 10) {
+		buf.WriteString(trim(l.px) + trim(l.sz))
+	}
+	return crc32.ChecksumIEEE([]byte(buf.String()))
+}
+
 // 旧代码，本来是用它实现BBO，没用了
 func (kk *Kraken) spotWsHandleOrderBook1(symbol string, ch chan<- any) {
 	bids := kk.spotWsOrderBookBids[symbol]
@@ -427,9 +642,9 @@ func (kk *Kraken) getWsToken() (string, error) {
 	link := kkSpotEndpoint + path
 	values := url.Values{}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return "", errors.New(kk.Name() + " net error! " + err.Error())
+		return "", newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error,omitempty"`
@@ -442,7 +657,7 @@ func (kk *Kraken) getWsToken() (string, error) {
 		return "", errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return "", errors.New(kk.Name() + " get wss token fail! " + recv.Error[0])
+		return "", kk.apiError(httpCode, recv.Error)
 	}
 	if recv.Result.Token == "" {
 		return "", errors.New(kk.Name() + " get wss token fail!")
@@ -450,7 +665,7 @@ func (kk *Kraken) getWsToken() (string, error) {
 	return recv.Result.Token, nil
 }
 func (kk *Kraken) SpotWsPrivateOpen() error {
-	url := "wss://ws-auth.kraken.com/v2"
+	url := kk.wsUrl("wss://ws-auth.kraken.com/v2")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -555,17 +770,17 @@ func (kk *Kraken) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := kk.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !kk.SpotWsPrivateIsClosed() {
-				ilog.Warning(kk.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", kk.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if kk.debug {
-			ilog.Rinfo(kk.Name() + " spot priv ws: " + string(recv))
+			ilog.Rinfo("%s", kk.Name()+" spot priv ws: "+string(recv))
 		}
 		msg := kkWsMsgPool.Get().(*KrakenWsMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(kk.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", kk.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Channel == "balances" {
@@ -585,7 +800,7 @@ func (kk *Kraken) SpotWsPrivateLoop(ch chan<- any) {
 		} else if msg.Method == "subscribe" {
 		} else if msg.Method == "unsubscribe" {
 		} else {
-			ilog.Error(kk.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+			ilog.Error("%s", kk.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 		}
 	END:
 		kkWsMsgPool.Put(msg)
@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -26,9 +26,9 @@ func (kk *Kraken) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 	values.Set("amount", qty.String())
 
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error"`
@@ -41,7 +41,7 @@ func (kk *Kraken) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return nil, errors.New(kk.Name() + " resp error! " + recv.Error[0])
+		return nil, kk.apiError(httpCode, recv.Error)
 	}
 
 	wr := &WithdrawReturn{
@@ -50,6 +50,33 @@ func (kk *Kraken) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decim
 	}
 	return wr, nil
 }
+func (kk *Kraken) CancelWithdrawal(symbol, wid string) error {
+	path := "/0/private/WithdrawCancel"
+	link := kkSpotEndpoint + path
+	values := url.Values{}
+	values.Set("asset", symbol)
+	values.Set("refid", wid)
+	headers, params := kk.buildHeaders(path, values)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Error  []string `json:"error"`
+		Result bool     `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &recv)
+	if err != nil {
+		return errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(recv.Error) > 0 {
+		return kk.apiError(httpCode, recv.Error)
+	}
+	if !recv.Result {
+		return errors.New(kk.Name() + " cancel withdrawal fail!")
+	}
+	return nil
+}
 func (kk *Kraken) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
 	path := "/0/private/WithdrawStatus"
 	link := kkSpotEndpoint + path
@@ -59,9 +86,9 @@ func (kk *Kraken) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
 		values.Set("aclass", "tokenized_asset")
 	}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	_, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 
 	ret := struct {
@@ -95,6 +122,64 @@ func (kk *Kraken) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error)
 	}
 	return res, nil
 }
+
+// method 为充值方式(链), kraken 不返回确认数
+func (kk *Kraken) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	path := "/0/private/DepositStatus"
+	link := kkSpotEndpoint + path
+	values := url.Values{}
+	values.Set("asset", symbol)
+	if kk.isXStocksSymbol(symbol + "USD") {
+		values.Set("aclass", "tokenized_asset")
+	}
+	if startTime > 0 {
+		values.Set("start", strconv.FormatInt(startTime, 10))
+	}
+	if endTime > 0 {
+		values.Set("end", strconv.FormatInt(endTime, 10))
+	}
+	headers, params := kk.buildHeaders(path, values)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+
+	ret := struct {
+		Error  []string `json:"error"`
+		Result []struct {
+			Id     string          `json:"refid"`
+			Symbol string          `json:"asset"`
+			Method string          `json:"method"`
+			Addr   string          `json:"info"`
+			Txid   string          `json:"txid"`
+			Status string          `json:"status"`
+			Qty    decimal.Decimal `json:"amount"`
+			Time   int64           `json:"time"`
+		}
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(ret.Error) > 0 {
+		return nil, kk.apiError(httpCode, ret.Error)
+	}
+	res := make([]DepositResult, 0, len(ret.Result))
+	for i := range ret.Result {
+		a := DepositResult{
+			Id:      ret.Result[i].Id,
+			Symbol:  ret.Result[i].Symbol,
+			Network: ret.Result[i].Method,
+			Addr:    ret.Result[i].Addr,
+			Txid:    ret.Result[i].Txid,
+			Qty:     ret.Result[i].Qty,
+			Status:  kk.toStdDepositStatus(ret.Result[i].Status),
+			Time:    ret.Result[i].Time,
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
 func (kk *Kraken) getDepositMethods(symbol string) ([]string, error) {
 	path := "/0/private/DepositMethods"
 	link := kkSpotEndpoint + path
@@ -104,9 +189,9 @@ func (kk *Kraken) getDepositMethods(symbol string) ([]string, error) {
 		values.Set("aclass", "tokenized_asset")
 	}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error"`
@@ -119,7 +204,7 @@ func (kk *Kraken) getDepositMethods(symbol string) ([]string, error) {
 		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return nil, errors.New(kk.Name() + " resp error! " + recv.Error[0])
+		return nil, kk.apiError(httpCode, recv.Error)
 	}
 
 	if len(recv.Result) == 0 {
@@ -154,9 +239,9 @@ func (kk *Kraken) GetDepositAddress(symbol, network string) ([]DepositAddress, e
 		values.Set("aclass", "tokenized_asset")
 	}
 	headers, params := kk.buildHeaders(path, values)
-	_, resp, err := ihttp.Post(link, []byte(params), kkApiDeadline, headers)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(kk.Name() + " net error! " + err.Error())
+		return nil, newNetError(kk.Name(), err)
 	}
 	recv := struct {
 		Error  []string `json:"error"`
@@ -170,7 +255,7 @@ func (kk *Kraken) GetDepositAddress(symbol, network string) ([]DepositAddress, e
 		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
 	}
 	if len(recv.Error) > 0 {
-		return nil, errors.New(kk.Name() + " resp error! " + recv.Error[0])
+		return nil, kk.apiError(httpCode, recv.Error)
 	}
 
 	if len(recv.Result) == 0 {
@@ -187,3 +272,135 @@ func (kk *Kraken) GetDepositAddress(symbol, network string) ([]DepositAddress, e
 
 	return daL, nil
 }
+
+// Memo 为提现key, Withdrawal 时作为memo传入
+func (kk *Kraken) GetWithdrawAddressBook(symbol string) ([]WithdrawAddress, error) {
+	path := "/0/private/WithdrawAddresses"
+	link := kkSpotEndpoint + path
+	values := url.Values{}
+	if symbol != "" {
+		values.Set("asset", symbol)
+		if kk.isXStocksSymbol(symbol + "USD") {
+			values.Set("aclass", "tokenized_asset")
+		}
+	}
+	headers, params := kk.buildHeaders(path, values)
+	httpCode, resp, err := kk.Post(link, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	recv := struct {
+		Error  []string `json:"error"`
+		Result []struct {
+			Symbol   string `json:"asset"`
+			Method   string `json:"method"`
+			Addr     string `json:"address"`
+			Key      string `json:"key"` // 地址簿中的名称
+			Verified bool   `json:"verified"`
+		}
+	}{}
+	err = json.Unmarshal(resp, &recv)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(recv.Error) > 0 {
+		return nil, kk.apiError(httpCode, recv.Error)
+	}
+	waL := make([]WithdrawAddress, 0, len(recv.Result))
+	for i := range recv.Result {
+		if !recv.Result[i].Verified { // 未验证的地址不能提现
+			continue
+		}
+		waL = append(waL, WithdrawAddress{
+			Symbol:  strings.ReplaceAll(kk.toStdSymbol(recv.Result[i].Symbol), "x.T", "x"),
+			Network: kk.methodToNetwork(recv.Result[i].Method),
+			Addr:    recv.Result[i].Addr,
+			Memo:    recv.Result[i].Key,
+			Name:    recv.Result[i].Key,
+		})
+	}
+	return waL, nil
+}
+
+// BindNetworks 的key为提现方式(method), kraken 不提供充值最小额
+func (kk *Kraken) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
+	httpCode, resp, err := kk.Get(kkSpotEndpoint+"/0/public/Assets", kkApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	assets := struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			Decimals int32  `json:"decimals"`
+			Status   string `json:"status"` // enabled/deposit_only/withdrawal_only/funding_temporarily_disabled
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &assets)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(assets.Error) > 0 {
+		return nil, kk.apiError(httpCode, assets.Error)
+	}
+
+	path := "/0/private/WithdrawMethods"
+	headers, params := kk.buildHeaders(path, url.Values{})
+	httpCode, resp, err = kk.Post(kkSpotEndpoint+path, []byte(params), kkApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(kk.Name(), err)
+	}
+	methods := struct {
+		Error  []string `json:"error"`
+		Result []struct {
+			Asset   string          `json:"asset"`
+			Method  string          `json:"method"`
+			Minimum decimal.Decimal `json:"minimum"`
+			Fee     struct {
+				Fee decimal.Decimal `json:"fee"`
+			} `json:"fee"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(resp, &methods)
+	if err != nil {
+		return nil, errors.New(kk.Name() + " unmarshal fail! " + err.Error())
+	}
+	if len(methods.Error) > 0 {
+		return nil, kk.apiError(httpCode, methods.Error)
+	}
+
+	waiMap := make(map[string]*WalletAssetInfo)
+	for _, m := range methods.Result {
+		as, ok := assets.Result[m.Asset]
+		if !ok {
+			continue
+		}
+		symbol := strings.ReplaceAll(kk.toStdSymbol(m.Asset), "x.T", "x")
+		wai, ok := waiMap[symbol]
+		if !ok {
+			wai = &WalletAssetInfo{
+				Symbol:       symbol,
+				BindNetworks: make(map[string]*WalletAssetBindNetworkInfo),
+			}
+			waiMap[symbol] = wai
+		}
+		wai.BindNetworks[m.Method] = &WalletAssetBindNetworkInfo{
+			IsWithdrawalEnabled: as.Status == "enabled" || as.Status == "withdrawal_only",
+			IsDepositEnabled:    as.Status == "enabled" || as.Status == "deposit_only",
+			WithdrawScale:       as.Decimals,
+			WithdrawFee:         m.Fee.Fee,
+			MinWithdrawalAmount: m.Minimum,
+		}
+	}
+	return waiMap, nil
+}
+
+// kraken 提现由key决定提现方法, 这里只取方法中括号内的网络
+// Tether USD (TRC20) -> TRC20, 没有括号时返回空(不比较网络)
+func (kk *Kraken) methodToNetwork(method string) string {
+	l := strings.LastIndexByte(method, '(')
+	r := strings.LastIndexByte(method, ')')
+	if l < 0 || r < l {
+		return ""
+	}
+	return strings.TrimSpace(method[l+1 : r])
+}
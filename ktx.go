@@ -1,6 +1,12 @@
 package cex
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -9,13 +15,27 @@ import (
 
 type Ktx struct {
 	Unsupported
-	name string
+	Http
+	name      string
+	account   string
+	apikey    string
+	secretkey string
+	debug     bool
 
 	// spot websocket
 	spotWsPublicConn      *websocket.Conn
 	spotWsPublicConnMtx   sync.Mutex
 	spotWsPublicClosed    bool
 	spotWsPublicClosedMtx sync.RWMutex
+	// bbo 和 ticker 共用 ticker 流, 记录各自订阅的symbol
+	spotWsPublicBBOSubs    map[string]bool
+	spotWsPublicTickerSubs map[string]bool
+	spotWsPublicSubsMtx    sync.RWMutex
+
+	spotWsPrivateConn      *websocket.Conn
+	spotWsPrivateConnMtx   sync.Mutex
+	spotWsPrivateClosed    bool
+	spotWsPrivateClosedMtx sync.RWMutex
 }
 
 var (
@@ -30,9 +50,17 @@ func init() {
 	ktxSpotSymbolMap = make(map[string]string)
 }
 
-func NewKtx() *Ktx {
+func NewKtx(account, apikey, secretkey string) *Ktx {
 	cexObj := &Ktx{
-		name: "ktx",
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("ktx"),
+		},
+		Unsupported: Unsupported{name: "ktx"},
+		name:        "ktx",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
 	}
 	return cexObj
 }
@@ -40,15 +68,31 @@ func (ktx *Ktx) Name() string {
 	return ktx.name
 }
 func (ktx *Ktx) Account() string {
-	return ""
+	return ktx.account
 }
 func (ktx *Ktx) ApiKey() string {
-	return ""
+	return ktx.apikey
 }
 func (ktx *Ktx) Debug(v bool) {
+	ktx.debug = v
+}
+func (ktx *Ktx) withContext(ctx context.Context) Exchanger {
+	cexObj := &Ktx{
+		Http:      ktx.Http.withContext(ctx),
+		name:      ktx.name,
+		account:   ktx.account,
+		apikey:    ktx.apikey,
+		secretkey: ktx.secretkey,
+		debug:     ktx.debug,
+	}
+	cexObj.Init()
+	return cexObj
 }
 func (ktx *Ktx) Init() error {
 	ktx.spotWsPublicClosed = true
+	ktx.spotWsPrivateClosed = true
+	ktx.spotWsPublicBBOSubs = make(map[string]bool)
+	ktx.spotWsPublicTickerSubs = make(map[string]bool)
 	return nil
 }
 func (ktx *Ktx) getSpotSymbol(symbol string) string {
@@ -56,3 +100,36 @@ func (ktx *Ktx) getSpotSymbol(symbol string) string {
 	defer ktxSpotSymbolMapMtx.RUnlock()
 	return ktxSpotSymbolMap[symbol]
 }
+
+// BTC_USDT -> BTCUSDT
+func (ktx *Ktx) toStdSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "_", "")
+}
+
+// 签名串: timestamp + method + /api/v1/xx?query + body, hex(HMAC-SHA256)
+// path 不含 /api 前缀(与 ktxSpotEndpoint 拼接)
+func (ktx *Ktx) buildHeaders(method, path, body string) map[string]string {
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return map[string]string{
+		"Content-Type":    "application/json",
+		"X-API-KEY":       ktx.apikey,
+		"X-API-TIMESTAMP": ts,
+		"X-API-SIGNATURE": ktx.sign(ts + method + "/api" + path + body),
+	}
+}
+func (ktx *Ktx) sign(params string) string {
+	h := hmac.New(sha256.New, []byte(ktx.secretkey))
+	h.Write([]byte(params))
+	return hex.EncodeToString(h.Sum(nil))
+}
+func (ktx *Ktx) apiError(httpStatus, code int, msg string) error {
+	return newApiError(ktx.Name(), httpStatus, strconv.Itoa(code), msg)
+}
+
+// 订单状态 NEW/PARTIALLY_FILLED/FILLED/CANCELLED/REJECTED
+func (ktx *Ktx) toStdOrderStatus(status string) string {
+	if status == "CANCELLED" || status == "PARTIALLY_CANCELLED" {
+		return "CANCELED"
+	}
+	return status
+}
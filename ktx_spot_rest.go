@@ -6,15 +6,17 @@ import (
 	"strings"
 	"time"
 
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
+func (ktx *Ktx) SpotSupported() bool {
+	return true
+}
 func (ktx *Ktx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	link := ktxSpotEndpoint + "/v1/products?market=spot"
-	_, resp, err := ihttp.Get(link, ktxApiDeadline, nil)
+	_, resp, err := ktx.Get(link, ktxApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(ktx.Name() + " net error! " + err.Error())
+		return nil, newNetError(ktx.Name(), err)
 	}
 
 	ret := struct {
@@ -68,9 +70,9 @@ func (ktx *Ktx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 func (ktx *Ktx) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	symbolS := ktx.getSpotSymbol(symbol)
 	url := ktxSpotEndpoint + "/v1/order_book?market=spot&level=1&symbol=" + symbolS
-	_, resp, err := ihttp.Get(url, ktxApiDeadline, nil)
+	_, resp, err := ktx.Get(url, ktxApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(ktx.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(ktx.Name(), err)
 	}
 
 	recv := struct {
@@ -95,3 +97,219 @@ func (ktx *Ktx) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	}
 	return BestBidAsk{}, errors.New(ktx.Name() + " resp empty!")
 }
+func (ktx *Ktx) SpotGetAllAssets() (map[string]*SpotAsset, error) {
+	path := "/v1/balances?market=spot"
+	httpCode, resp, err := ktx.Get(ktxSpotEndpoint+path, ktxApiDeadline, ktx.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code   int    `json:"code"`
+		Msg    string `json:"message"`
+		Result []struct {
+			Symbol string          `json:"asset"`
+			Avail  decimal.Decimal `json:"available"`
+			Locked decimal.Decimal `json:"locked"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	assetsMap := make(map[string]*SpotAsset, len(recv.Result))
+	for _, v := range recv.Result {
+		total := v.Avail.Add(v.Locked)
+		if total.IsZero() {
+			continue
+		}
+		assetsMap[v.Symbol] = &SpotAsset{
+			Symbol: v.Symbol,
+			Total:  total,
+			Avail:  v.Avail,
+			Locked: v.Locked,
+		}
+	}
+	return assetsMap, nil
+}
+
+// 市价买单用 amt(计价币数量), 市价卖单用 qty
+func (ktx *Ktx) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
+	price, amt, qty decimal.Decimal,
+	side, timeInForce, orderType string, postOnly bool) (string, error) {
+	symbolS := ktx.getSpotSymbol(symbol)
+	if symbolS == "" {
+		return "", errors.New(ktx.Name() + " unknown symbol " + symbol)
+	}
+	params := map[string]any{
+		"market": "spot",
+		"symbol": symbolS,
+		"side":   side,
+		"type":   orderType,
+	}
+	if cltId != "" {
+		params["clientOrderId"] = cltId
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+		params["quantity"] = qty.String()
+		if timeInForce != "" {
+			params["timeInForce"] = timeInForce
+		}
+		if postOnly {
+			params["postOnly"] = true
+		}
+	} else if orderType == "MARKET" {
+		if side == "BUY" && amt.IsPositive() {
+			params["quoteQuantity"] = amt.String()
+		} else {
+			params["quantity"] = qty.String()
+		}
+	} else {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	body, _ := json.Marshal(params)
+	path := "/v1/order"
+	httpCode, resp, err := ktx.Post(ktxSpotEndpoint+path, body, ktxApiDeadline,
+		ktx.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return "", newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code   int    `json:"code"`
+		Msg    string `json:"message"`
+		Result struct {
+			OrderId string `json:"orderId"`
+		} `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return "", ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Result.OrderId, nil
+}
+func (ktx *Ktx) orderIdQuery(orderId, cltId string) string {
+	if orderId != "" {
+		return "orderId=" + orderId
+	}
+	return "clientOrderId=" + cltId
+}
+func (ktx *Ktx) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
+	if orderId == "" && cltId == "" {
+		return errors.New(ktx.Name() + " orderId or cltId empty!")
+	}
+	path := "/v1/order?market=spot&" + ktx.orderIdQuery(orderId, cltId)
+	httpCode, resp, err := ktx.Delete(ktxSpotEndpoint+path, ktxApiDeadline, ktx.buildHeaders("DELETE", path, ""))
+	if err != nil {
+		return newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+func (ktx *Ktx) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(ktx, orders), nil
+}
+func (ktx *Ktx) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(ktx, orders), nil
+}
+func (ktx *Ktx) SpotCancelAllOrders(symbol string) error {
+	path := "/v1/orders?market=spot"
+	if symbol != "" {
+		path += "&symbol=" + ktx.getSpotSymbol(symbol)
+	}
+	httpCode, resp, err := ktx.Delete(ktxSpotEndpoint+path, ktxApiDeadline, ktx.buildHeaders("DELETE", path, ""))
+	if err != nil {
+		return newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+func (ktx *Ktx) toStdSpotOrder(order *KtxSpotOrder) *SpotOrder {
+	return &SpotOrder{
+		Symbol:      ktx.toStdSymbol(order.Symbol),
+		OrderId:     order.OrderId,
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Qty,
+		FilledQty:   order.FilledQty,
+		FilledAmt:   order.FilledAmt,
+		AvgPrice:    order.AvgPrice,
+		Status:      ktx.toStdOrderStatus(order.Status),
+		Type:        order.Type,
+		TimeInForce: order.TimeInForce,
+		Side:        order.Side,
+		FeeAsset:    order.FeeAsset,
+		FeeQty:      order.FeeQty.Abs().Neg(),
+		CTime:       order.CTime,
+		UTime:       order.UTime,
+	}
+}
+func (ktx *Ktx) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
+	if orderId == "" && cltId == "" {
+		return nil, errors.New(ktx.Name() + " orderId or cltId empty!")
+	}
+	path := "/v1/order?market=spot&" + ktx.orderIdQuery(orderId, cltId)
+	httpCode, resp, err := ktx.Get(ktxSpotEndpoint+path, ktxApiDeadline, ktx.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code   int          `json:"code"`
+		Msg    string       `json:"message"`
+		Result KtxSpotOrder `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return ktx.toStdSpotOrder(&recv.Result), nil
+}
+func (ktx *Ktx) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	path := "/v1/open_orders?market=spot"
+	if symbol != "" {
+		path += "&symbol=" + ktx.getSpotSymbol(symbol)
+	}
+	httpCode, resp, err := ktx.Get(ktxSpotEndpoint+path, ktxApiDeadline, ktx.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(ktx.Name(), err)
+	}
+	recv := struct {
+		Code   int            `json:"code"`
+		Msg    string         `json:"message"`
+		Result []KtxSpotOrder `json:"result"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(ktx.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, ktx.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	dl := make([]*SpotOrder, 0, len(recv.Result))
+	for i := range recv.Result {
+		dl = append(dl, ktx.toStdSpotOrder(&recv.Result[i]))
+	}
+	return dl, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/mailru/easyjson"
 	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
 )
 
 var (
@@ -26,7 +28,7 @@ func init() {
 }
 
 func (ktx *Ktx) SpotWsPublicOpen() error {
-	url := "wss://m-stream.ktx.com"
+	url := ktx.wsUrl("wss://m-stream.ktx.com")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -43,63 +45,66 @@ func (ktx *Ktx) SpotWsPublicOpen() error {
 	return nil
 }
 func (ktx *Ktx) SpotWsPublicSubscribe(channels []string) {
+	ktx.spotWsPublicSubscribe("SUBSCRIBE", channels)
+}
+func (ktx *Ktx) SpotWsPublicUnsubscribe(channels []string) {
+	ktx.spotWsPublicSubscribe("UNSUBSCRIBE", channels)
+}
+
+// bbo 和 ticker 都用 ticker 流实现, 另一个还在订阅时不发送退订
+func (ktx *Ktx) spotWsPublicSubscribe(method string, channels []string) {
 	if len(channels) == 0 {
 		return
 	}
+	sub := method == "SUBSCRIBE"
 	streams := make([]string, 0, 4)
+	ktx.spotWsPublicSubsMtx.Lock()
 	for _, c := range channels {
 		arr := strings.Split(c, "@")
-		if arr[0] == "bbo" { // 用ticker实现
-			var symbolArr []string
-			if len(arr) > 1 && len(arr[1]) > 0 {
-				symbolArr = strings.Split(arr[1], ",")
-			} else {
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		if arr[0] != "orderbook5" && arr[0] != "bbo" && arr[0] != "ticker" {
+			continue
+		}
+		for v := range strings.SplitSeq(arr[1], ",") {
+			sym := ktx.getSpotSymbol(v)
+			if sym == "" {
 				continue
 			}
-			for _, v := range symbolArr {
-				if sym := ktx.getSpotSymbol(v); sym != "" {
-					streams = append(streams, "spot."+sym+".ticker")
-				}
+			if arr[0] == "orderbook5" {
+				streams = append(streams, "spot."+sym+".depth5")
+				continue
 			}
-		}
-	}
-	if len(streams) > 0 {
-		jv, _ := json.Marshal(streams)
-		req := fmt.Sprintf(`{"method":"SUBSCRIBE","params":%s}`, string(jv))
-		ktx.spotWsPublicConnMtx.Lock()
-		ktx.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
-		ktx.spotWsPublicConnMtx.Unlock()
-	}
-}
-func (ktx *Ktx) SpotWsPublicUnsubscribe(channels []string) {
-	if len(channels) == 0 {
-		return
-	}
-	streams := make([]string, 0, 4)
-	for _, c := range channels {
-		arr := strings.Split(c, "@")
-		if arr[0] == "bbo" { // 用ticker实现
-			var symbolArr []string
-			if len(arr) > 1 && len(arr[1]) > 0 {
-				symbolArr = strings.Split(arr[1], ",")
+			subs, other := ktx.spotWsPublicBBOSubs, ktx.spotWsPublicTickerSubs
+			if arr[0] == "ticker" {
+				subs, other = other, subs
+			}
+			if sub {
+				subs[v] = true
 			} else {
-				continue
+				delete(subs, v)
 			}
-			for _, v := range symbolArr {
-				if sym := ktx.getSpotSymbol(v); sym != "" {
-					streams = append(streams, "spot."+sym+".ticker")
-				}
+			if !other[v] {
+				streams = append(streams, "spot."+sym+".ticker")
 			}
 		}
 	}
+	ktx.spotWsPublicSubsMtx.Unlock()
 	if len(streams) > 0 {
 		jv, _ := json.Marshal(streams)
-		req := fmt.Sprintf(`{"method":"UNSUBSCRIBE","params":%s}`, string(jv))
+		req := fmt.Sprintf(`{"method":"%s","params":%s}`, method, string(jv))
 		ktx.spotWsPublicConnMtx.Lock()
 		ktx.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
 		ktx.spotWsPublicConnMtx.Unlock()
 	}
 }
+func (ktx *Ktx) SpotWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (ktx *Ktx) SpotWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
 func (ktx *Ktx) SpotWsPublicBBOPoolPut(v any) {
 	wsPublicBBOPool.Put(v)
 }
@@ -135,25 +140,27 @@ func (ktx *Ktx) SpotWsPublicLoop(ch chan<- any) {
 		_, recv, err := ktx.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !ktx.SpotWsPublicIsClosed() {
-				ilog.Warning(ktx.Name() + " spot.ws.public channel read: " + err.Error())
+				ilog.Warning("%s", ktx.Name()+" spot.ws.public channel read: "+err.Error())
 			}
 			break
 		}
 		msg := ktxWsPubMsgPool.Get().(*KtxWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(ktx.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", ktx.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Stream != "" {
 			if strings.HasSuffix(msg.Stream, ".ticker") {
-				ktx.spotWsHandleBBO(msg.Data, ch)
+				ktx.spotWsHandleTicker(msg.Data, ch)
+			} else if strings.HasSuffix(msg.Stream, ".depth5") {
+				ktx.spotWsHandleOrderBook5(msg.Data, ch)
 			}
 		} else if msg.Pong > 0 {
 			ktx.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
 		} else if msg.Op != "" {
 		} else {
-			ilog.Error(ktx.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+			ilog.Error("%s", ktx.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 		}
 	END:
 		ktxWsPubMsgPool.Put(msg)
@@ -173,15 +180,212 @@ func (ktx *Ktx) SpotWsPublicClose() {
 	ktx.spotWsPublicClosed = true
 	ktx.spotWsPublicConn.Close()
 }
-func (ktx *Ktx) spotWsHandleBBO(data json.RawMessage, ch chan<- any) {
-	bbo := KtxTicker{}
-	if err := easyjson.Unmarshal(data, &bbo); err == nil {
+func (ktx *Ktx) spotWsHandleTicker(data json.RawMessage, ch chan<- any) {
+	tk := KtxTicker{}
+	if err := easyjson.Unmarshal(data, &tk); err != nil {
+		ilog.Error("%s", ktx.Name()+" spot.ws.public ticker exception: "+err.Error())
+		return
+	}
+	symbol := ktx.toStdSymbol(tk.Symbol)
+	ktx.spotWsPublicSubsMtx.RLock()
+	bbo, ticker := ktx.spotWsPublicBBOSubs[symbol], ktx.spotWsPublicTickerSubs[symbol]
+	ktx.spotWsPublicSubsMtx.RUnlock()
+	if bbo {
 		obd := wsPublicBBOPool.Get().(*BestBidAsk)
-		obd.Symbol = strings.ReplaceAll(bbo.Symbol, "_", "")
-		obd.BidPrice = bbo.BidPrice
-		obd.BidQty = bbo.BidQty
-		obd.AskPrice = bbo.AskPrice
-		obd.AskQty = bbo.AskQty
+		obd.Symbol = symbol
+		obd.BidPrice = tk.BidPrice
+		obd.BidQty = tk.BidQty
+		obd.AskPrice = tk.AskPrice
+		obd.AskQty = tk.AskQty
 		ch <- obd
 	}
+	if ticker {
+		t := wsPublicTickerPool.Get().(*Pub24hTicker)
+		t.Symbol = symbol
+		t.LastPrice = tk.Last
+		t.Volume = tk.Volume
+		t.QuoteVolume = tk.QuoteVolume
+		ch <- t
+	}
+}
+func (ktx *Ktx) spotWsHandleOrderBook5(data json.RawMessage, ch chan<- any) {
+	depth := KtxOrderBook5{}
+	if err := easyjson.Unmarshal(data, &depth); err != nil {
+		ilog.Error("%s", ktx.Name()+" spot.ws.public orderbook5 exception: "+err.Error())
+		return
+	}
+	obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+	obd.Symbol = ktx.toStdSymbol(depth.Symbol)
+	obd.Level = 5
+	obd.Time = depth.Time
+	obd.Bids = obd.Bids[:0]
+	obd.Asks = obd.Asks[:0]
+	for _, v := range depth.Bids {
+		obd.Bids = append(obd.Bids, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	for _, v := range depth.Asks {
+		obd.Asks = append(obd.Asks, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	ch <- obd
+}
+
+// = priv channel
+func (ktx *Ktx) SpotWsPrivateSupported() bool {
+	return true
+}
+
+// 连接后先登录, 签名串为 timestamp + "GET/ws/login"
+func (ktx *Ktx) SpotWsPrivateOpen() error {
+	url := ktx.wsUrl("wss://u-stream.ktx.com")
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	ktx.spotWsPrivateConn, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(ktx.Name() + " spot.ws.priv con failed! " + err.Error())
+	}
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req := fmt.Sprintf(`{"method":"LOGIN","params":{"apiKey":"%s","timestamp":%s,"signature":"%s"}}`,
+		ktx.apikey, ts, ktx.sign(ts+"GET/ws/login"))
+	if err = ktx.spotWsPrivateConn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		ktx.spotWsPrivateConn.Close()
+		return errors.New(ktx.Name() + " spot.ws.priv login failed! " + err.Error())
+	}
+	ktx.spotWsPrivateConn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	_, recv, err := ktx.spotWsPrivateConn.ReadMessage()
+	if err != nil {
+		ktx.spotWsPrivateConn.Close()
+		return errors.New(ktx.Name() + " spot.ws.priv login failed! " + err.Error())
+	}
+	msg := KtxWsPubMsg{}
+	if err = easyjson.Unmarshal(recv, &msg); err != nil || msg.Code != 0 {
+		ktx.spotWsPrivateConn.Close()
+		return errors.New(ktx.Name() + " spot.ws.priv login failed! " + string(recv))
+	}
+
+	ktx.spotWsPrivateClosedMtx.Lock()
+	ktx.spotWsPrivateClosed = false
+	ktx.spotWsPrivateClosedMtx.Unlock()
+	return nil
+}
+
+// channels: orders, balance
+func (ktx *Ktx) SpotWsPrivateSubscribe(channels []string) {
+	streams := make([]string, 0, 2)
+	for _, c := range channels {
+		if c == "orders" {
+			streams = append(streams, "spot.order")
+		} else if c == "balance" {
+			streams = append(streams, "spot.balance")
+		}
+	}
+	if len(streams) > 0 {
+		jv, _ := json.Marshal(streams)
+		req := fmt.Sprintf(`{"method":"SUBSCRIBE","params":%s}`, string(jv))
+		ktx.spotWsPrivateConnMtx.Lock()
+		err := ktx.spotWsPrivateConn.WriteMessage(websocket.TextMessage, []byte(req))
+		ktx.spotWsPrivateConnMtx.Unlock()
+		if err != nil {
+			ilog.Warning("%s", ktx.Name()+" spot.ws.priv subscribe net error! "+err.Error())
+		}
+	}
+}
+func (ktx *Ktx) SpotWsPrivateIsClosed() bool {
+	ktx.spotWsPrivateClosedMtx.RLock()
+	defer ktx.spotWsPrivateClosedMtx.RUnlock()
+	return ktx.spotWsPrivateClosed
+}
+func (ktx *Ktx) SpotWsPrivateClose() {
+	ktx.spotWsPrivateClosedMtx.Lock()
+	defer ktx.spotWsPrivateClosedMtx.Unlock()
+	if ktx.spotWsPrivateClosed {
+		return
+	}
+	ktx.spotWsPrivateClosed = true
+	ktx.spotWsPrivateConn.Close()
+}
+func (ktx *Ktx) SpotWsPrivateLoop(ch chan<- any) {
+	defer ktx.SpotWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 30 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	ktx.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if ktx.SpotWsPrivateIsClosed() {
+					break
+				}
+				s := fmt.Sprintf(`{"ping":%d}`, time.Now().UnixMilli())
+				ktx.spotWsPrivateConnMtx.Lock()
+				ktx.spotWsPrivateConn.WriteMessage(websocket.TextMessage, []byte(s))
+				ktx.spotWsPrivateConnMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	for {
+		_, recv, err := ktx.spotWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !ktx.SpotWsPrivateIsClosed() {
+				ilog.Warning("%s", ktx.Name()+" spot.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if ktx.debug {
+			ilog.Rinfo("%s", ktx.Name()+" spot priv ws: "+string(recv))
+		}
+		msg := ktxWsPubMsgPool.Get().(*KtxWsPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", ktx.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Stream == "spot.order" {
+			ktx.spotWsHandleOrder(msg.Data, ch)
+		} else if msg.Stream == "spot.balance" {
+			ktx.spotWsHandleBalance(msg.Data, ch)
+		} else if msg.Pong > 0 {
+			ktx.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+		} else if msg.Code != 0 {
+			ilog.Error("%s", ktx.Name()+" spot.ws.priv recv err: "+string(recv))
+		}
+	END:
+		ktxWsPubMsgPool.Put(msg)
+	}
+}
+func (ktx *Ktx) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	order := KtxSpotOrder{}
+	if err := easyjson.Unmarshal(data, &order); err != nil {
+		ilog.Error("%s", ktx.Name()+" spot.ws.priv handle order: "+err.Error())
+		return
+	}
+	ch <- ktx.toStdSpotOrder(&order)
+}
+func (ktx *Ktx) spotWsHandleBalance(data json.RawMessage, ch chan<- any) {
+	bl := struct {
+		Symbol string          `json:"asset"`
+		Avail  decimal.Decimal `json:"available"`
+		Locked decimal.Decimal `json:"locked"`
+	}{}
+	if err := json.Unmarshal(data, &bl); err != nil {
+		ilog.Error("%s", ktx.Name()+" spot.ws.priv handle balance: "+err.Error())
+		return
+	}
+	ch <- &SpotAsset{
+		Symbol: bl.Symbol,
+		Total:  bl.Avail.Add(bl.Locked),
+		Avail:  bl.Avail,
+		Locked: bl.Locked,
+	}
 }
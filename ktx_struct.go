@@ -9,6 +9,8 @@ import (
 type KtxWsPubMsg struct {
 	Pong   float64         `json:"pong"`
 	Op     string          `json:"op"`
+	Code   int             `json:"code"`
+	Msg    string          `json:"message"`
 	Stream string          `json:"stream"`
 	Data   json.RawMessage `json:"data,omitempty"`
 }
@@ -16,6 +18,8 @@ type KtxWsPubMsg struct {
 func (v *KtxWsPubMsg) reset() {
 	v.Pong = 0.0
 	v.Op = ""
+	v.Code = 0
+	v.Msg = ""
 	v.Stream = ""
 	v.Data = nil
 }
@@ -26,4 +30,34 @@ type KtxTicker struct {
 	BidQty   decimal.Decimal `json:"bidQty"`
 	AskPrice decimal.Decimal `json:"askPrice"`
 	AskQty   decimal.Decimal `json:"askQty"`
+
+	Last        decimal.Decimal `json:"lastPrice"`
+	Volume      decimal.Decimal `json:"volume"`
+	QuoteVolume decimal.Decimal `json:"quoteVolume"`
+}
+
+type KtxOrderBook5 struct {
+	Symbol string               `json:"product"`
+	Asks   [][2]decimal.Decimal `json:"a"`
+	Bids   [][2]decimal.Decimal `json:"b"`
+	Time   int64                `json:"time"` // msec
+}
+
+type KtxSpotOrder struct {
+	Symbol      string          `json:"symbol"` // BTC_USDT
+	OrderId     string          `json:"orderId"`
+	ClientId    string          `json:"clientOrderId"`
+	Side        string          `json:"side"` // BUY/SELL
+	Type        string          `json:"type"` // LIMIT/MARKET
+	TimeInForce string          `json:"timeInForce"`
+	Price       decimal.Decimal `json:"price"`
+	Qty         decimal.Decimal `json:"quantity"`
+	FilledQty   decimal.Decimal `json:"filledQuantity"`
+	FilledAmt   decimal.Decimal `json:"filledAmount"`
+	AvgPrice    decimal.Decimal `json:"avgPrice"`
+	Status      string          `json:"status"`
+	FeeAsset    string          `json:"feeAsset"`
+	FeeQty      decimal.Decimal `json:"fee"`
+	CTime       int64           `json:"createdAt"` // msec
+	UTime       int64           `json:"updatedAt"` // msec
 }
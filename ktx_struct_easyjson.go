@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	decimal "github.com/shopspring/decimal"
 )
 
 // suppress unused package warning
@@ -43,6 +44,18 @@ func easyjsonF72c1e69DecodeGithubComShaovieCex(in *jlexer.Lexer, out *KtxWsPubMs
 			} else {
 				out.Op = string(in.String())
 			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = int(in.Int())
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Msg = string(in.String())
+			}
 		case "stream":
 			if in.IsNull() {
 				in.Skip()
@@ -81,6 +94,16 @@ func easyjsonF72c1e69EncodeGithubComShaovieCex(out *jwriter.Writer, in KtxWsPubM
 		out.RawString(prefix)
 		out.String(string(in.Op))
 	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.Int(int(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Msg))
+	}
 	{
 		const prefix string = ",\"stream\":"
 		out.RawString(prefix)
@@ -169,6 +192,30 @@ func easyjsonF72c1e69DecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KtxTicker
 					in.AddError((out.AskQty).UnmarshalJSON(data))
 				}
 			}
+		case "lastPrice":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Last).UnmarshalJSON(data))
+				}
+			}
+		case "volume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "quoteVolume":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -208,6 +255,21 @@ func easyjsonF72c1e69EncodeGithubComShaovieCex1(out *jwriter.Writer, in KtxTicke
 		out.RawString(prefix)
 		out.Raw((in.AskQty).MarshalJSON())
 	}
+	{
+		const prefix string = ",\"lastPrice\":"
+		out.RawString(prefix)
+		out.Raw((in.Last).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"volume\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"quoteVolume\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
 	out.RawByte('}')
 }
 
@@ -234,3 +296,455 @@ func (v *KtxTicker) UnmarshalJSON(data []byte) error {
 func (v *KtxTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonF72c1e69DecodeGithubComShaovieCex1(l, v)
 }
+func easyjsonF72c1e69DecodeGithubComShaovieCex2(in *jlexer.Lexer, out *KtxSpotOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "symbol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "orderId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = string(in.String())
+			}
+		case "clientOrderId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "side":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Side = string(in.String())
+			}
+		case "type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "timeInForce":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TimeInForce = string(in.String())
+			}
+		case "price":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Price).UnmarshalJSON(data))
+				}
+			}
+		case "quantity":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Qty).UnmarshalJSON(data))
+				}
+			}
+		case "filledQuantity":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FilledQty).UnmarshalJSON(data))
+				}
+			}
+		case "filledAmount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FilledAmt).UnmarshalJSON(data))
+				}
+			}
+		case "avgPrice":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AvgPrice).UnmarshalJSON(data))
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "feeAsset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FeeAsset = string(in.String())
+			}
+		case "fee":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FeeQty).UnmarshalJSON(data))
+				}
+			}
+		case "createdAt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CTime = int64(in.Int64())
+			}
+		case "updatedAt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UTime = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF72c1e69EncodeGithubComShaovieCex2(out *jwriter.Writer, in KtxSpotOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"symbol\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"orderId\":"
+		out.RawString(prefix)
+		out.String(string(in.OrderId))
+	}
+	{
+		const prefix string = ",\"clientOrderId\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	{
+		const prefix string = ",\"side\":"
+		out.RawString(prefix)
+		out.String(string(in.Side))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"timeInForce\":"
+		out.RawString(prefix)
+		out.String(string(in.TimeInForce))
+	}
+	{
+		const prefix string = ",\"price\":"
+		out.RawString(prefix)
+		out.Raw((in.Price).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"quantity\":"
+		out.RawString(prefix)
+		out.Raw((in.Qty).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"filledQuantity\":"
+		out.RawString(prefix)
+		out.Raw((in.FilledQty).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"filledAmount\":"
+		out.RawString(prefix)
+		out.Raw((in.FilledAmt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"avgPrice\":"
+		out.RawString(prefix)
+		out.Raw((in.AvgPrice).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"feeAsset\":"
+		out.RawString(prefix)
+		out.String(string(in.FeeAsset))
+	}
+	{
+		const prefix string = ",\"fee\":"
+		out.RawString(prefix)
+		out.Raw((in.FeeQty).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CTime))
+	}
+	{
+		const prefix string = ",\"updatedAt\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.UTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KtxSpotOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF72c1e69EncodeGithubComShaovieCex2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KtxSpotOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF72c1e69EncodeGithubComShaovieCex2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KtxSpotOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF72c1e69DecodeGithubComShaovieCex2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KtxSpotOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF72c1e69DecodeGithubComShaovieCex2(l, v)
+}
+func easyjsonF72c1e69DecodeGithubComShaovieCex3(in *jlexer.Lexer, out *KtxOrderBook5) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "product":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "a":
+			if in.IsNull() {
+				in.Skip()
+				out.Asks = nil
+			} else {
+				in.Delim('[')
+				if out.Asks == nil {
+					if !in.IsDelim(']') {
+						out.Asks = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Asks = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Asks = (out.Asks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v2 := 0
+						for !in.IsDelim(']') {
+							if v2 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v1)[v2]).UnmarshalJSON(data))
+									}
+								}
+								v2++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Asks = append(out.Asks, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "b":
+			if in.IsNull() {
+				in.Skip()
+				out.Bids = nil
+			} else {
+				in.Delim('[')
+				if out.Bids == nil {
+					if !in.IsDelim(']') {
+						out.Bids = make([][2]decimal.Decimal, 0, 2)
+					} else {
+						out.Bids = [][2]decimal.Decimal{}
+					}
+				} else {
+					out.Bids = (out.Bids)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 [2]decimal.Decimal
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('[')
+						v4 := 0
+						for !in.IsDelim(']') {
+							if v4 < 2 {
+								if in.IsNull() {
+									in.Skip()
+								} else {
+									if data := in.Raw(); in.Ok() {
+										in.AddError(((v3)[v4]).UnmarshalJSON(data))
+									}
+								}
+								v4++
+							} else {
+								in.SkipRecursive()
+							}
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Bids = append(out.Bids, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF72c1e69EncodeGithubComShaovieCex3(out *jwriter.Writer, in KtxOrderBook5) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"product\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"a\":"
+		out.RawString(prefix)
+		if in.Asks == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Asks {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v7 := range v6 {
+					if v7 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v6)[v7]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"b\":"
+		out.RawString(prefix)
+		if in.Bids == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Bids {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				out.RawByte('[')
+				for v10 := range v9 {
+					if v10 > 0 {
+						out.RawByte(',')
+					}
+					out.Raw(((v9)[v10]).MarshalJSON())
+				}
+				out.RawByte(']')
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KtxOrderBook5) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF72c1e69EncodeGithubComShaovieCex3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KtxOrderBook5) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF72c1e69EncodeGithubComShaovieCex3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KtxOrderBook5) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF72c1e69DecodeGithubComShaovieCex3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KtxOrderBook5) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF72c1e69DecodeGithubComShaovieCex3(l, v)
+}
@@ -1,21 +1,44 @@
 package cex
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/shaovie/gutils/gutils"
+	"github.com/shopspring/decimal"
 )
 
 type Kucoin struct {
 	Unsupported
-	name string
+	Http
+	name      string
+	account   string
+	apikey    string
+	secretkey string
+	passwd    string
+	debug     bool
 
 	// spot websocket
 	spotWsPublicConn      *websocket.Conn
 	spotWsPublicConnMtx   sync.Mutex
 	spotWsPublicClosed    bool
 	spotWsPublicClosedMtx sync.RWMutex
+	spotWsPublicPingItv   time.Duration
+
+	spotWsPrivateConn      *websocket.Conn
+	spotWsPrivateConnMtx   sync.Mutex
+	spotWsPrivateClosed    bool
+	spotWsPrivateClosedMtx sync.RWMutex
+	spotWsPrivatePingItv   time.Duration
 }
 
 var (
@@ -30,9 +53,18 @@ func init() {
 	kcSpotSymbolMap = make(map[string]string)
 }
 
-func NewKucoin() *Kucoin {
+func NewKucoin(account, apikey, secretkey, passwd string) *Kucoin {
 	cexObj := &Kucoin{
-		name: "kucoin",
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("kucoin"),
+		},
+		Unsupported: Unsupported{name: "kucoin"},
+		name:        "kucoin",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+		passwd:      passwd,
 	}
 	return cexObj
 }
@@ -40,15 +72,30 @@ func (kc *Kucoin) Name() string {
 	return kc.name
 }
 func (kc *Kucoin) Account() string {
-	return ""
+	return kc.account
 }
 func (kc *Kucoin) ApiKey() string {
-	return ""
+	return kc.apikey
 }
 func (kc *Kucoin) Debug(v bool) {
+	kc.debug = v
+}
+func (kc *Kucoin) withContext(ctx context.Context) Exchanger {
+	cexObj := &Kucoin{
+		Http:      kc.Http.withContext(ctx),
+		name:      kc.name,
+		account:   kc.account,
+		apikey:    kc.apikey,
+		secretkey: kc.secretkey,
+		passwd:    kc.passwd,
+		debug:     kc.debug,
+	}
+	cexObj.Init()
+	return cexObj
 }
 func (kc *Kucoin) Init() error {
 	kc.spotWsPublicClosed = true
+	kc.spotWsPrivateClosed = true
 	return nil
 }
 func (kc *Kucoin) getSpotSymbol(symbol string) string {
@@ -56,3 +103,146 @@ func (kc *Kucoin) getSpotSymbol(symbol string) string {
 	defer kcSpotSymbolMapMtx.RUnlock()
 	return kcSpotSymbolMap[symbol]
 }
+
+// BTC-USDT -> BTCUSDT
+func (kc *Kucoin) toStdSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "-", "")
+}
+
+// api key v2: passphrase 也需要签名
+func (kc *Kucoin) buildHeaders(method, path, body string) map[string]string {
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return map[string]string{
+		"Content-Type":       "application/json",
+		"KC-API-KEY":         kc.apikey,
+		"KC-API-SIGN":        kc.sign(ts + method + path + body),
+		"KC-API-TIMESTAMP":   ts,
+		"KC-API-PASSPHRASE":  kc.sign(kc.passwd),
+		"KC-API-KEY-VERSION": "2",
+	}
+}
+func (kc *Kucoin) sign(params string) string {
+	h := hmac.New(sha256.New, []byte(kc.secretkey))
+	h.Write([]byte(params))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+func (kc *Kucoin) apiError(httpStatus int, code, msg string) error {
+	return newApiError(kc.Name(), httpStatus, code, msg)
+}
+func (kc *Kucoin) toStdSide(side string) string {
+	if side == "buy" {
+		return "BUY"
+	} else if side == "sell" {
+		return "SELL"
+	}
+	return ""
+}
+func (kc *Kucoin) fromStdSide(side string) string {
+	if side == "BUY" {
+		return "buy"
+	} else if side == "SELL" {
+		return "sell"
+	}
+	return ""
+}
+func (kc *Kucoin) toStdOrderType(orderType string) string {
+	if orderType == "limit" {
+		return "LIMIT"
+	} else if orderType == "market" {
+		return "MARKET"
+	}
+	return ""
+}
+func (kc *Kucoin) fromStdOrderType(orderType string) string {
+	if orderType == "LIMIT" {
+		return "limit"
+	} else if orderType == "MARKET" {
+		return "market"
+	}
+	return ""
+}
+
+// 查询订单没有status字段, 根据isActive/cancelExist/dealSize推断
+func (kc *Kucoin) toStdOrderStatus(isActive, cancelExist bool, dealSize decimal.Decimal) string {
+	if isActive {
+		if dealSize.IsPositive() {
+			return "PARTIALLY_FILLED"
+		}
+		return "NEW"
+	}
+	if cancelExist {
+		return "CANCELED"
+	}
+	return "FILLED"
+}
+
+// ws 连接需要先取token, 返回 endpoint?token=xx&connectId=xx, ping间隔
+func (kc *Kucoin) getBulletUrl(private bool) (string, time.Duration, error) {
+	path := "/api/v1/bullet-public"
+	var headers map[string]string
+	if private {
+		path = "/api/v1/bullet-private"
+		headers = kc.buildHeaders("POST", path, "")
+	}
+	httpCode, resp, err := kc.Post(kcSpotEndpoint+path, nil, kcApiDeadline, headers)
+	if err != nil {
+		return "", 0, newNetError(kc.Name(), err)
+	}
+	ret := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			Token   string `json:"token"`
+			Servers []struct {
+				Endpoint     string `json:"endpoint"`
+				PingInterval int64  `json:"pingInterval"` // msec
+			} `json:"instanceServers"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return "", 0, errors.New(kc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "200000" {
+		return "", 0, kc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data.Servers) == 0 {
+		return "", 0, errors.New(kc.Name() + " bullet resp empty")
+	}
+	srv := ret.Data.Servers[0]
+	url := kc.wsUrl(strings.TrimSuffix(srv.Endpoint, "/")) + "?token=" + ret.Data.Token +
+		"&connectId=" + gutils.RandomStr(16)
+	return url, time.Duration(srv.PingInterval) * time.Millisecond, nil
+}
+
+// 连接成功后服务端先推 {"id":"xx","type":"welcome"}
+func (kc *Kucoin) wsDial(private bool) (*websocket.Conn, time.Duration, error) {
+	url, pingItv, err := kc.getBulletUrl(private)
+	if err != nil {
+		return nil, 0, err
+	}
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	_, recv, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, 0, err
+	}
+	welcome := struct {
+		Type string `json:"type"`
+	}{}
+	if json.Unmarshal(recv, &welcome); welcome.Type != "welcome" {
+		conn.Close()
+		return nil, 0, errors.New("recv unexpected msg: " + string(recv))
+	}
+	if pingItv <= 0 {
+		pingItv = 18 * time.Second
+	}
+	return conn, pingItv, nil
+}
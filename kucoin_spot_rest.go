@@ -5,15 +5,37 @@ import (
 	"errors"
 	"time"
 
-	"github.com/shaovie/gutils/ihttp"
+	"github.com/shaovie/gutils/gutils"
 	"github.com/shopspring/decimal"
 )
 
+func (kc *Kucoin) SpotSupported() bool {
+	return true
+}
+func (kc *Kucoin) SpotServerTime() (int64, error) {
+	url := kcSpotEndpoint + "/api/v1/timestamp"
+	httpCode, resp, err := kc.Get(url, kcApiDeadline, nil)
+	if err != nil {
+		return 0, newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Time int64  `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return 0, errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return 0, kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Time, nil
+}
 func (kc *Kucoin) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	link := kcSpotEndpoint + "/api/ua/v1/market/instrument?tradeType=SPOT"
-	_, resp, err := ihttp.Get(link, kcApiDeadline, nil)
+	_, resp, err := kc.Get(link, kcApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(kc.Name() + " net error! " + err.Error())
+		return nil, newNetError(kc.Name(), err)
 	}
 
 	ret := struct {
@@ -70,9 +92,9 @@ func (kc *Kucoin) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error
 func (kc *Kucoin) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	symbolS := kc.getSpotSymbol(symbol)
 	url := kcSpotEndpoint + "/api/ua/v1/market/ticker?tradeType=SPOT&symbol=" + symbolS
-	_, resp, err := ihttp.Get(url, kcApiDeadline, nil)
+	_, resp, err := kc.Get(url, kcApiDeadline, nil)
 	if err != nil {
-		return BestBidAsk{}, errors.New(kc.Name() + " net error! " + err.Error())
+		return BestBidAsk{}, newNetError(kc.Name(), err)
 	}
 
 	recv := struct {
@@ -103,3 +125,271 @@ func (kc *Kucoin) SpotGetBBO(symbol string) (BestBidAsk, error) {
 	}
 	return BestBidAsk{}, errors.New(kc.Name() + " resp empty!")
 }
+func (kc *Kucoin) SpotGetAllAssets() (map[string]*SpotAsset, error) {
+	path := "/api/v1/accounts?type=trade"
+	httpCode, resp, err := kc.Get(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Symbol string          `json:"currency"`
+			Total  decimal.Decimal `json:"balance"`
+			Avail  decimal.Decimal `json:"available"`
+			Locked decimal.Decimal `json:"holds"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return nil, kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	assetsMap := make(map[string]*SpotAsset, len(recv.Data))
+	for _, v := range recv.Data {
+		if v.Total.IsZero() {
+			continue
+		}
+		assetsMap[v.Symbol] = &SpotAsset{
+			Symbol: v.Symbol,
+			Total:  v.Total,
+			Avail:  v.Avail,
+			Locked: v.Locked,
+		}
+	}
+	return assetsMap, nil
+}
+
+// kucoin 的 clientOid 必填, cltId为空时自动生成
+func (kc *Kucoin) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
+	price, amt, qty decimal.Decimal,
+	side, timeInForce, orderType string, postOnly bool) (string, error) {
+	symbolS := kc.getSpotSymbol(symbol)
+	if symbolS == "" {
+		return "", errors.New(kc.Name() + " unknown symbol " + symbol)
+	}
+	if cltId == "" {
+		cltId = gutils.RandomStr(24)
+	}
+	params := map[string]any{
+		"clientOid": cltId,
+		"symbol":    symbolS,
+		"side":      kc.fromStdSide(side),
+		"type":      kc.fromStdOrderType(orderType),
+	}
+	if orderType == "LIMIT" {
+		params["price"] = price.String()
+		params["size"] = qty.String()
+		if timeInForce != "" {
+			params["timeInForce"] = timeInForce
+		}
+		if postOnly {
+			params["postOnly"] = true
+		}
+	} else if orderType == "MARKET" {
+		if amt.IsPositive() {
+			params["funds"] = amt.String()
+		} else if qty.IsPositive() {
+			params["size"] = qty.String()
+		}
+	} else {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	body, _ := json.Marshal(params)
+	path := "/api/v1/orders"
+	httpCode, resp, err := kc.Post(kcSpotEndpoint+path, body, kcApiDeadline,
+		kc.buildHeaders("POST", path, string(body)))
+	if err != nil {
+		return "", newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			OrderId string `json:"orderId"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return "", errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return "", kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return recv.Data.OrderId, nil
+}
+func (kc *Kucoin) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
+	path := ""
+	if orderId != "" {
+		path = "/api/v1/orders/" + orderId
+	} else if cltId != "" {
+		path = "/api/v1/order/client-order/" + cltId
+	} else {
+		return errors.New(kc.Name() + " orderId or cltId empty!")
+	}
+	httpCode, resp, err := kc.Delete(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("DELETE", path, ""))
+	if err != nil {
+		return newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+func (kc *Kucoin) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(kc, orders), nil
+}
+func (kc *Kucoin) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(kc, orders), nil
+}
+func (kc *Kucoin) SpotCancelAllOrders(symbol string) error {
+	path := "/api/v1/orders?tradeType=TRADE"
+	if symbol != "" {
+		path += "&symbol=" + kc.getSpotSymbol(symbol)
+	}
+	httpCode, resp, err := kc.Delete(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("DELETE", path, ""))
+	if err != nil {
+		return newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return nil
+}
+
+type kcSpotOrder struct {
+	Symbol      string          `json:"symbol"` // BTC-USDT
+	OrderId     string          `json:"id"`
+	ClientId    string          `json:"clientOid"`
+	Type        string          `json:"type"`
+	Side        string          `json:"side"`
+	Price       decimal.Decimal `json:"price"`
+	Size        decimal.Decimal `json:"size"`
+	DealSize    decimal.Decimal `json:"dealSize"`
+	DealFunds   decimal.Decimal `json:"dealFunds"`
+	Fee         decimal.Decimal `json:"fee"`
+	FeeCurrency string          `json:"feeCurrency"`
+	TimeInForce string          `json:"timeInForce"`
+	IsActive    bool            `json:"isActive"`
+	CancelExist bool            `json:"cancelExist"`
+	CreatedAt   int64           `json:"createdAt"` // msec
+}
+
+func (kc *Kucoin) toStdSpotOrder(order *kcSpotOrder) *SpotOrder {
+	return &SpotOrder{
+		Symbol:      kc.toStdSymbol(order.Symbol),
+		OrderId:     order.OrderId,
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Size,
+		FilledQty:   order.DealSize,
+		FilledAmt:   order.DealFunds,
+		Status:      kc.toStdOrderStatus(order.IsActive, order.CancelExist, order.DealSize),
+		Type:        kc.toStdOrderType(order.Type),
+		TimeInForce: order.TimeInForce,
+		Side:        kc.toStdSide(order.Side),
+		FeeAsset:    order.FeeCurrency,
+		FeeQty:      order.Fee.Neg(),
+		CTime:       order.CreatedAt,
+	}
+}
+func (kc *Kucoin) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
+	path := ""
+	if orderId != "" {
+		path = "/api/v1/orders/" + orderId
+	} else if cltId != "" {
+		path = "/api/v1/order/client-order/" + cltId
+	} else {
+		return nil, errors.New(kc.Name() + " orderId or cltId empty!")
+	}
+	httpCode, resp, err := kc.Get(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string      `json:"code"`
+		Msg  string      `json:"msg"`
+		Data kcSpotOrder `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return nil, kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	return kc.toStdSpotOrder(&recv.Data), nil
+}
+
+// 只取第一页(最多500)
+func (kc *Kucoin) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	path := "/api/v1/orders?status=active&tradeType=TRADE&pageSize=500"
+	if symbol != "" {
+		path += "&symbol=" + kc.getSpotSymbol(symbol)
+	}
+	httpCode, resp, err := kc.Get(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("GET", path, ""))
+	if err != nil {
+		return nil, newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			Items []kcSpotOrder `json:"items"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return nil, kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	dl := make([]*SpotOrder, 0, len(recv.Data.Items))
+	for i := range recv.Data.Items {
+		dl = append(dl, kc.toStdSpotOrder(&recv.Data.Items[i]))
+	}
+	return dl, nil
+}
+func (kc *Kucoin) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
+	var f SpotTradeFee
+	path := "/api/v1/trade-fees?symbols=" + kc.getSpotSymbol(symbol)
+	httpCode, resp, err := kc.Get(kcSpotEndpoint+path, kcApiDeadline, kc.buildHeaders("GET", path, ""))
+	if err != nil {
+		return f, newNetError(kc.Name(), err)
+	}
+	recv := struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Maker decimal.Decimal `json:"makerFeeRate"`
+			Taker decimal.Decimal `json:"takerFeeRate"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return f, errors.New(kc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != "200000" {
+		return f, kc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+	if len(recv.Data) == 0 {
+		return f, errors.New(kc.Name() + " resp empty")
+	}
+	return SpotTradeFee{
+		Maker: recv.Data[0].Maker,
+		Taker: recv.Data[0].Taker,
+	}, nil
+}
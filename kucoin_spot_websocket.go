@@ -3,18 +3,21 @@ package cex
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/mailru/easyjson"
+	"github.com/shaovie/gutils/gutils"
 	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
 )
 
 var (
-	kcWsPubMsgPool sync.Pool
+	kcWsPubMsgPool                   sync.Pool
+	kcSpotWsPublicOrderBookInnerPool sync.Pool
 )
 
 func init() {
@@ -23,128 +26,147 @@ func init() {
 			return &KucoinWsPubMsg{}
 		},
 	}
+	kcSpotWsPublicOrderBookInnerPool = sync.Pool{
+		New: func() any {
+			return &KucoinOrderBook5{
+				Bids: make([][2]decimal.Decimal, 0, 5),
+				Asks: make([][2]decimal.Decimal, 0, 5),
+			}
+		},
+	}
 }
 
+// 公共和私有连接都要先通过 bullet 接口取token
 func (kc *Kucoin) SpotWsPublicOpen() error {
-	url := "wss://x-push-spot.kucoin.com"
-	var err error
-	dialer := websocket.Dialer{
-		EnableCompression: true, // 启用压缩扩展
-		HandshakeTimeout:  2 * time.Second,
-	}
-	kc.spotWsPublicConn, _, err = dialer.Dial(url, nil)
+	conn, pingItv, err := kc.wsDial(false)
 	if err != nil {
 		return errors.New(kc.Name() + " spot.ws.public con failed! " + err.Error())
 	}
-
+	kc.spotWsPublicConn = conn
+	kc.spotWsPublicPingItv = pingItv
 	kc.spotWsPublicClosedMtx.Lock()
 	kc.spotWsPublicClosed = false
 	kc.spotWsPublicClosedMtx.Unlock()
 	return nil
 }
+
+// 一个topic最多100个symbol
 func (kc *Kucoin) SpotWsPublicSubscribe(channels []string) {
-	if len(channels) == 0 {
-		return
-	}
+	kc.spotWsPublicSubscribe("subscribe", channels)
+}
+func (kc *Kucoin) SpotWsPublicUnsubscribe(channels []string) {
+	kc.spotWsPublicSubscribe("unsubscribe", channels)
+}
+func (kc *Kucoin) spotWsPublicSubscribe(typ string, channels []string) {
 	for _, c := range channels {
 		arr := strings.Split(c, "@")
-		if arr[0] == "bbo" { // 用ticker实现
-			var symbolArr []string
-			if len(arr) > 1 && len(arr[1]) > 0 {
-				symbolArr = strings.Split(arr[1], ",")
-			} else {
-				continue
-			}
-			for _, v := range symbolArr {
-				if sym := kc.getSpotSymbol(v); sym != "" {
-					req := fmt.Sprintf(`{"action":"subscribe","channel":"obu",`+
-						`"tradeType":"SPOT","depth":"1","symbol":"%s"}`, sym)
-					kc.spotWsPublicConnMtx.Lock()
-					kc.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
-					kc.spotWsPublicConnMtx.Unlock()
-				}
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		topic := ""
+		if arr[0] == "bbo" {
+			topic = "/spotMarket/level1:"
+		} else if arr[0] == "orderbook5" {
+			topic = "/spotMarket/level2Depth5:"
+		} else if arr[0] == "ticker" {
+			topic = "/market/snapshot:"
+		} else {
+			continue
+		}
+		symbols := make([]string, 0, 4)
+		for v := range strings.SplitSeq(arr[1], ",") {
+			if sym := kc.getSpotSymbol(strings.ToUpper(v)); sym != "" {
+				symbols = append(symbols, sym)
 			}
 		}
+		if len(symbols) == 0 {
+			continue
+		}
+		kc.wsSend(kc.spotWsPublicConn, &kc.spotWsPublicConnMtx, typ,
+			topic+strings.Join(symbols, ","), false)
 	}
 }
-func (kc *Kucoin) SpotWsPublicUnsubscribe(channels []string) {
-	if len(channels) == 0 {
-		return
+func (kc *Kucoin) wsSend(conn *websocket.Conn, mtx *sync.Mutex, typ, topic string, private bool) error {
+	arg := map[string]any{
+		"id":       typ[:3] + "-" + gutils.RandomStr(8),
+		"type":     typ,
+		"topic":    topic,
+		"response": true,
 	}
-	for _, c := range channels {
-		arr := strings.Split(c, "@")
-		if arr[0] == "bbo" { // 用ticker实现
-			var symbolArr []string
-			if len(arr) > 1 && len(arr[1]) > 0 {
-				symbolArr = strings.Split(arr[1], ",")
-			} else {
-				continue
-			}
-			for _, v := range symbolArr {
-				if sym := kc.getSpotSymbol(v); sym != "" {
-					req := fmt.Sprintf(`{"action":"unsubscribe","channel":"obu",`+
-						`"tradeType":"SPOT","depth":"1","symbol":"%s"}`, sym)
-					kc.spotWsPublicConnMtx.Lock()
-					kc.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
-					kc.spotWsPublicConnMtx.Unlock()
-				}
-			}
-		}
+	if private {
+		arg["privateChannel"] = true
 	}
+	req, _ := json.Marshal(arg)
+	mtx.Lock()
+	defer mtx.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, req)
+}
+func (kc *Kucoin) SpotWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (kc *Kucoin) SpotWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
 }
 func (kc *Kucoin) SpotWsPublicBBOPoolPut(v any) {
 	wsPublicBBOPool.Put(v)
 }
+func (kc *Kucoin) wsPing(conn *websocket.Conn, mtx *sync.Mutex,
+	isClosed func() bool, interval time.Duration, exitChan <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exitChan:
+			return
+		case <-ticker.C:
+			if isClosed() {
+				break
+			}
+			ping := `{"id":"` + strconv.FormatInt(time.Now().UnixMilli(), 10) + `","type":"ping"}`
+			mtx.Lock()
+			conn.WriteMessage(websocket.TextMessage, []byte(ping))
+			mtx.Unlock()
+		}
+	}
+}
 func (kc *Kucoin) SpotWsPublicLoop(ch chan<- any) {
 	defer kc.SpotWsPublicClose()
 	defer close(ch)
 
-	pingInterval := 30 * time.Second
-	pongWait := pingInterval + 2*time.Second
+	pingInterval := kc.spotWsPublicPingItv
+	pongWait := pingInterval + 3*time.Second
 	kc.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
 	pingExit := make(chan struct{})
 	defer close(pingExit)
-	go func(exitChan <-chan struct{}) {
-		ticker := time.NewTicker(pingInterval)
-		defer ticker.Stop()
-		var pingMsg = []byte(`{"type":"ping"}`)
-		for {
-			select {
-			case <-exitChan:
-				return
-			case <-ticker.C:
-				if kc.SpotWsPublicIsClosed() {
-					break
-				}
-				kc.spotWsPublicConnMtx.Lock()
-				kc.spotWsPublicConn.WriteMessage(websocket.TextMessage, pingMsg)
-				kc.spotWsPublicConnMtx.Unlock()
-			}
-		}
-	}(pingExit)
+	go kc.wsPing(kc.spotWsPublicConn, &kc.spotWsPublicConnMtx,
+		kc.SpotWsPublicIsClosed, pingInterval, pingExit)
 
 	for {
 		_, recv, err := kc.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !kc.SpotWsPublicIsClosed() {
-				ilog.Warning(kc.Name() + " spot.ws.public channel read: " + err.Error())
+				ilog.Warning("%s", kc.Name()+" spot.ws.public channel read: "+err.Error())
 			}
 			break
 		}
 		msg := kcWsPubMsgPool.Get().(*KucoinWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(kc.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", kc.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
-		if msg.Channel == "obu.SPOT" {
-			if msg.T == "snapshot" {
-				kc.spotWsHandleBBO(msg.Data, ch)
+		if msg.Type == "message" {
+			if msg.Subject == "level1" {
+				kc.spotWsHandleBBO(msg, ch)
+			} else if msg.Subject == "level2" {
+				kc.spotWsHandleOrderBook5(msg, ch)
+			} else if msg.Subject == "trade.snapshot" {
+				kc.spotWsHandle24hTickers(msg, ch)
 			}
 		} else if msg.Type == "pong" {
 			kc.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
-		} else {
-			//ilog.Error(kc.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+		} else if msg.Type == "error" {
+			ilog.Error("%s", kc.Name()+" spot.ws.public recv err: "+string(recv))
 		}
 	END:
 		kcWsPubMsgPool.Put(msg)
@@ -164,15 +186,221 @@ func (kc *Kucoin) SpotWsPublicClose() {
 	kc.spotWsPublicClosed = true
 	kc.spotWsPublicConn.Close()
 }
-func (kc *Kucoin) spotWsHandleBBO(data json.RawMessage, ch chan<- any) {
-	bbo := KucoinTicker{}
-	if err := easyjson.Unmarshal(data, &bbo); err == nil && len(bbo.Bids) > 0 && len(bbo.Asks) > 0 {
-		obd := wsPublicBBOPool.Get().(*BestBidAsk)
-		obd.Symbol = strings.ReplaceAll(bbo.Symbol, "-", "")
-		obd.BidPrice = bbo.Bids[0][0]
-		obd.BidQty = bbo.Bids[0][1]
-		obd.AskPrice = bbo.Asks[0][0]
-		obd.AskQty = bbo.Asks[0][1]
-		ch <- obd
+
+// topic: /spotMarket/level1:BTC-USDT
+func (kc *Kucoin) topicSymbol(topic string) string {
+	if i := strings.LastIndexByte(topic, ':'); i != -1 {
+		return kc.toStdSymbol(topic[i+1:])
+	}
+	return ""
+}
+func (kc *Kucoin) spotWsHandleBBO(msg *KucoinWsPubMsg, ch chan<- any) {
+	bbo := KucoinBBO{}
+	if err := easyjson.Unmarshal(msg.Data, &bbo); err != nil {
+		ilog.Error("%s", kc.Name()+" spot.ws.public "+msg.Topic+" bbo exception")
+		return
+	}
+	obd := wsPublicBBOPool.Get().(*BestBidAsk)
+	obd.Symbol = kc.topicSymbol(msg.Topic)
+	obd.Time = bbo.Time
+	obd.BidPrice = bbo.Bids[0]
+	obd.BidQty = bbo.Bids[1]
+	obd.AskPrice = bbo.Asks[0]
+	obd.AskQty = bbo.Asks[1]
+	ch <- obd
+}
+func (kc *Kucoin) spotWsHandleOrderBook5(msg *KucoinWsPubMsg, ch chan<- any) {
+	depth := kcSpotWsPublicOrderBookInnerPool.Get().(*KucoinOrderBook5)
+	defer kcSpotWsPublicOrderBookInnerPool.Put(depth)
+	depth.Bids = depth.Bids[:0]
+	depth.Asks = depth.Asks[:0]
+	if err := easyjson.Unmarshal(msg.Data, depth); err != nil {
+		ilog.Error("%s", kc.Name()+" spot.ws.public "+msg.Topic+" orderbook5 exception")
+		return
+	}
+	obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+	obd.Symbol = kc.topicSymbol(msg.Topic)
+	obd.Level = 5
+	obd.Time = depth.Time
+	obd.Bids = obd.Bids[:0]
+	obd.Asks = obd.Asks[:0]
+	for _, v := range depth.Bids {
+		obd.Bids = append(obd.Bids, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	for _, v := range depth.Asks {
+		obd.Asks = append(obd.Asks, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	ch <- obd
+}
+func (kc *Kucoin) spotWsHandle24hTickers(msg *KucoinWsPubMsg, ch chan<- any) {
+	ticker := KucoinSnapshot{}
+	if err := easyjson.Unmarshal(msg.Data, &ticker); err != nil {
+		ilog.Error("%s", kc.Name()+" spot.ws.public "+msg.Topic+" ticker exception")
+		return
+	}
+	tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+	tk.Symbol = kc.toStdSymbol(ticker.Data.Symbol)
+	tk.LastPrice = ticker.Data.Last
+	tk.Volume = ticker.Data.Volume
+	tk.QuoteVolume = ticker.Data.QuoteVolume
+	ch <- tk
+}
+
+// = priv channel
+func (kc *Kucoin) SpotWsPrivateSupported() bool {
+	return true
+}
+func (kc *Kucoin) SpotWsPrivateOpen() error {
+	conn, pingItv, err := kc.wsDial(true)
+	if err != nil {
+		return errors.New(kc.Name() + " spot.ws.priv connect failed! " + err.Error())
+	}
+	kc.spotWsPrivateConn = conn
+	kc.spotWsPrivatePingItv = pingItv
+	kc.spotWsPrivateClosedMtx.Lock()
+	kc.spotWsPrivateClosed = false
+	kc.spotWsPrivateClosedMtx.Unlock()
+	return nil
+}
+func (kc *Kucoin) SpotWsPrivateSubscribe(channels []string) {
+	for _, c := range channels {
+		topic := ""
+		if c == "orders" {
+			topic = "/spotMarket/tradeOrders"
+		} else if c == "balance" {
+			topic = "/account/balance"
+		} else {
+			continue
+		}
+		err := kc.wsSend(kc.spotWsPrivateConn, &kc.spotWsPrivateConnMtx, "subscribe", topic, true)
+		if err != nil {
+			ilog.Warning("%s", kc.Name()+" spot.ws.priv subscribe net error! "+err.Error())
+		}
+	}
+}
+func (kc *Kucoin) SpotWsPrivateIsClosed() bool {
+	kc.spotWsPrivateClosedMtx.RLock()
+	defer kc.spotWsPrivateClosedMtx.RUnlock()
+	return kc.spotWsPrivateClosed
+}
+func (kc *Kucoin) SpotWsPrivateClose() {
+	kc.spotWsPrivateClosedMtx.Lock()
+	defer kc.spotWsPrivateClosedMtx.Unlock()
+	if kc.spotWsPrivateClosed {
+		return
+	}
+	kc.spotWsPrivateClosed = true
+	kc.spotWsPrivateConn.Close()
+}
+func (kc *Kucoin) SpotWsPrivateLoop(ch chan<- any) {
+	defer kc.SpotWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := kc.spotWsPrivatePingItv
+	pongWait := pingInterval + 3*time.Second
+	kc.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go kc.wsPing(kc.spotWsPrivateConn, &kc.spotWsPrivateConnMtx,
+		kc.SpotWsPrivateIsClosed, pingInterval, pingExit)
+
+	for {
+		_, recv, err := kc.spotWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !kc.SpotWsPrivateIsClosed() {
+				ilog.Warning("%s", kc.Name()+" spot.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if kc.debug {
+			ilog.Rinfo("%s", kc.Name()+" spot priv ws: "+string(recv))
+		}
+		msg := kcWsPubMsgPool.Get().(*KucoinWsPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", kc.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Type == "message" {
+			if msg.Subject == "orderChange" {
+				kc.spotWsHandleOrder(msg.Data, ch)
+			} else if msg.Subject == "account.balance" {
+				kc.spotWsHandleBalanceUpdate(msg.Data, ch)
+			}
+		} else if msg.Type == "pong" {
+			kc.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+		} else if msg.Type == "error" {
+			ilog.Error("%s", kc.Name()+" spot.ws.priv recv err: "+string(recv))
+		}
+	END:
+		kcWsPubMsgPool.Put(msg)
+	}
+}
+
+// type: open/match/filled/canceled/update, 时间为纳秒
+func (kc *Kucoin) spotWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	order := struct {
+		Symbol     string          `json:"symbol"` // BTC-USDT
+		OrderId    string          `json:"orderId"`
+		ClientId   string          `json:"clientOid"`
+		Type       string          `json:"type"`
+		OrderType  string          `json:"orderType"`
+		Side       string          `json:"side"`
+		Price      decimal.Decimal `json:"price"`
+		Size       decimal.Decimal `json:"size"`
+		FilledSize decimal.Decimal `json:"filledSize"`
+		RemainSize decimal.Decimal `json:"remainSize"`
+		OrderTime  int64           `json:"orderTime"` // nsec
+		Ts         int64           `json:"ts"`        // nsec
+	}{}
+	if err := json.Unmarshal(data, &order); err != nil {
+		ilog.Error("%s", kc.Name()+" spot.ws.priv handle order: "+err.Error())
+		return
+	}
+	o := &SpotOrder{
+		Symbol:    kc.toStdSymbol(order.Symbol),
+		OrderId:   order.OrderId,
+		ClientId:  order.ClientId,
+		Price:     order.Price,
+		Qty:       order.Size,
+		FilledQty: order.FilledSize,
+		Type:      kc.toStdOrderType(order.OrderType),
+		Side:      kc.toStdSide(order.Side),
+		CTime:     order.OrderTime / 1000000,
+		UTime:     order.Ts / 1000000,
+	}
+	if order.Type == "filled" {
+		o.Status = "FILLED"
+	} else if order.Type == "canceled" {
+		o.Status = "CANCELED"
+	} else if order.FilledSize.IsPositive() {
+		o.Status = "PARTIALLY_FILLED"
+	} else {
+		o.Status = "NEW"
+	}
+	ch <- o
+}
+
+// relationEvent 以 main./trade./margin. 开头区分账户, 只推送交易账户
+func (kc *Kucoin) spotWsHandleBalanceUpdate(data json.RawMessage, ch chan<- any) {
+	bl := struct {
+		Event  string          `json:"relationEvent"`
+		Symbol string          `json:"currency"`
+		Total  decimal.Decimal `json:"total"`
+		Avail  decimal.Decimal `json:"available"`
+		Locked decimal.Decimal `json:"hold"`
+	}{}
+	if err := json.Unmarshal(data, &bl); err != nil {
+		ilog.Error("%s", kc.Name()+" spot.ws.priv handle balance: "+err.Error())
+		return
+	}
+	if !strings.HasPrefix(bl.Event, "trade.") {
+		return
+	}
+	ch <- &SpotAsset{
+		Symbol: bl.Symbol,
+		Total:  bl.Total,
+		Avail:  bl.Avail,
+		Locked: bl.Locked,
 	}
 }
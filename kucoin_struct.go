@@ -7,23 +7,42 @@ import (
 )
 
 type KucoinWsPubMsg struct {
-	Type    string          `json:"type"`
-	Channel string          `json:"T"`
-	T       string          `json:"t"`
-	Depth   string          `json:"dp"`
-	Data    json.RawMessage `json:"d"`
+	Id      string          `json:"id"`
+	Type    string          `json:"type"` // message/pong/ack/error
+	Topic   string          `json:"topic"`
+	Subject string          `json:"subject"`
+	Data    json.RawMessage `json:"data"`
 }
 
 func (v *KucoinWsPubMsg) reset() {
+	v.Id = ""
 	v.Type = ""
-	v.T = ""
-	v.Channel = ""
-	v.Depth = ""
+	v.Topic = ""
+	v.Subject = ""
 	v.Data = nil
 }
 
-type KucoinTicker struct {
-	Symbol string               `json:"s"`
-	Bids   [][2]decimal.Decimal `json:"b"`
-	Asks   [][2]decimal.Decimal `json:"a"`
+// /spotMarket/level1:BTC-USDT
+type KucoinBBO struct {
+	Bids [2]decimal.Decimal `json:"bids"`
+	Asks [2]decimal.Decimal `json:"asks"`
+	Time int64              `json:"timestamp"` // msec
+}
+
+// /spotMarket/level2Depth5:BTC-USDT
+type KucoinOrderBook5 struct {
+	Bids [][2]decimal.Decimal `json:"bids"`
+	Asks [][2]decimal.Decimal `json:"asks"`
+	Time int64                `json:"timestamp"` // msec
+}
+
+// /market/snapshot:BTC-USDT
+type KucoinSnapshot struct {
+	Data Kucoin24hTicker `json:"data"`
+}
+type Kucoin24hTicker struct {
+	Symbol      string          `json:"symbol"` // BTC-USDT
+	Last        decimal.Decimal `json:"lastTradedPrice"`
+	Volume      decimal.Decimal `json:"vol"`
+	QuoteVolume decimal.Decimal `json:"volValue"`
 }
@@ -32,31 +32,31 @@ func easyjsonCfff304bDecodeGithubComShaovieCex(in *jlexer.Lexer, out *KucoinWsPu
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "type":
+		case "id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Type = string(in.String())
+				out.Id = string(in.String())
 			}
-		case "T":
+		case "type":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Channel = string(in.String())
+				out.Type = string(in.String())
 			}
-		case "t":
+		case "topic":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.T = string(in.String())
+				out.Topic = string(in.String())
 			}
-		case "dp":
+		case "subject":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Depth = string(in.String())
+				out.Subject = string(in.String())
 			}
-		case "d":
+		case "data":
 			if in.IsNull() {
 				in.Skip()
 			} else {
@@ -79,27 +79,27 @@ func easyjsonCfff304bEncodeGithubComShaovieCex(out *jwriter.Writer, in KucoinWsP
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"type\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Type))
+		out.String(string(in.Id))
 	}
 	{
-		const prefix string = ",\"T\":"
+		const prefix string = ",\"type\":"
 		out.RawString(prefix)
-		out.String(string(in.Channel))
+		out.String(string(in.Type))
 	}
 	{
-		const prefix string = ",\"t\":"
+		const prefix string = ",\"topic\":"
 		out.RawString(prefix)
-		out.String(string(in.T))
+		out.String(string(in.Topic))
 	}
 	{
-		const prefix string = ",\"dp\":"
+		const prefix string = ",\"subject\":"
 		out.RawString(prefix)
-		out.String(string(in.Depth))
+		out.String(string(in.Subject))
 	}
 	{
-		const prefix string = ",\"d\":"
+		const prefix string = ",\"data\":"
 		out.RawString(prefix)
 		out.Raw((in.Data).MarshalJSON())
 	}
@@ -129,7 +129,7 @@ func (v *KucoinWsPubMsg) UnmarshalJSON(data []byte) error {
 func (v *KucoinWsPubMsg) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonCfff304bDecodeGithubComShaovieCex(l, v)
 }
-func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinTicker) {
+func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinSnapshot) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -143,13 +143,72 @@ func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinTic
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "s":
+		case "data":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Symbol = string(in.String())
+				(out.Data).UnmarshalEasyJSON(in)
 			}
-		case "b":
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonCfff304bEncodeGithubComShaovieCex1(out *jwriter.Writer, in KucoinSnapshot) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix[1:])
+		(in.Data).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KucoinSnapshot) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonCfff304bEncodeGithubComShaovieCex1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KucoinSnapshot) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonCfff304bEncodeGithubComShaovieCex1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KucoinSnapshot) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonCfff304bDecodeGithubComShaovieCex1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KucoinSnapshot) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonCfff304bDecodeGithubComShaovieCex1(l, v)
+}
+func easyjsonCfff304bDecodeGithubComShaovieCex2(in *jlexer.Lexer, out *KucoinOrderBook5) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bids":
 			if in.IsNull() {
 				in.Skip()
 				out.Bids = nil
@@ -193,7 +252,7 @@ func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinTic
 				}
 				in.Delim(']')
 			}
-		case "a":
+		case "asks":
 			if in.IsNull() {
 				in.Skip()
 				out.Asks = nil
@@ -237,6 +296,12 @@ func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinTic
 				}
 				in.Delim(']')
 			}
+		case "timestamp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = int64(in.Int64())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -247,18 +312,13 @@ func easyjsonCfff304bDecodeGithubComShaovieCex1(in *jlexer.Lexer, out *KucoinTic
 		in.Consumed()
 	}
 }
-func easyjsonCfff304bEncodeGithubComShaovieCex1(out *jwriter.Writer, in KucoinTicker) {
+func easyjsonCfff304bEncodeGithubComShaovieCex2(out *jwriter.Writer, in KucoinOrderBook5) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"s\":"
+		const prefix string = ",\"bids\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Symbol))
-	}
-	{
-		const prefix string = ",\"b\":"
-		out.RawString(prefix)
 		if in.Bids == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
@@ -280,7 +340,7 @@ func easyjsonCfff304bEncodeGithubComShaovieCex1(out *jwriter.Writer, in KucoinTi
 		}
 	}
 	{
-		const prefix string = ",\"a\":"
+		const prefix string = ",\"asks\":"
 		out.RawString(prefix)
 		if in.Asks == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
@@ -302,29 +362,273 @@ func easyjsonCfff304bEncodeGithubComShaovieCex1(out *jwriter.Writer, in KucoinTi
 			out.RawByte(']')
 		}
 	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Time))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v KucoinTicker) MarshalJSON() ([]byte, error) {
+func (v KucoinOrderBook5) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonCfff304bEncodeGithubComShaovieCex1(&w, v)
+	easyjsonCfff304bEncodeGithubComShaovieCex2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v KucoinTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonCfff304bEncodeGithubComShaovieCex1(w, v)
+func (v KucoinOrderBook5) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonCfff304bEncodeGithubComShaovieCex2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *KucoinTicker) UnmarshalJSON(data []byte) error {
+func (v *KucoinOrderBook5) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonCfff304bDecodeGithubComShaovieCex1(&r, v)
+	easyjsonCfff304bDecodeGithubComShaovieCex2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *KucoinTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonCfff304bDecodeGithubComShaovieCex1(l, v)
+func (v *KucoinOrderBook5) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonCfff304bDecodeGithubComShaovieCex2(l, v)
+}
+func easyjsonCfff304bDecodeGithubComShaovieCex3(in *jlexer.Lexer, out *KucoinBBO) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "bids":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('[')
+				v11 := 0
+				for !in.IsDelim(']') {
+					if v11 < 2 {
+						if in.IsNull() {
+							in.Skip()
+						} else {
+							if data := in.Raw(); in.Ok() {
+								in.AddError(((out.Bids)[v11]).UnmarshalJSON(data))
+							}
+						}
+						v11++
+					} else {
+						in.SkipRecursive()
+					}
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "asks":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('[')
+				v12 := 0
+				for !in.IsDelim(']') {
+					if v12 < 2 {
+						if in.IsNull() {
+							in.Skip()
+						} else {
+							if data := in.Raw(); in.Ok() {
+								in.AddError(((out.Asks)[v12]).UnmarshalJSON(data))
+							}
+						}
+						v12++
+					} else {
+						in.SkipRecursive()
+					}
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "timestamp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonCfff304bEncodeGithubComShaovieCex3(out *jwriter.Writer, in KucoinBBO) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bids\":"
+		out.RawString(prefix[1:])
+		out.RawByte('[')
+		for v13 := range in.Bids {
+			if v13 > 0 {
+				out.RawByte(',')
+			}
+			out.Raw(((in.Bids)[v13]).MarshalJSON())
+		}
+		out.RawByte(']')
+	}
+	{
+		const prefix string = ",\"asks\":"
+		out.RawString(prefix)
+		out.RawByte('[')
+		for v14 := range in.Asks {
+			if v14 > 0 {
+				out.RawByte(',')
+			}
+			out.Raw(((in.Asks)[v14]).MarshalJSON())
+		}
+		out.RawByte(']')
+	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KucoinBBO) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonCfff304bEncodeGithubComShaovieCex3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KucoinBBO) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonCfff304bEncodeGithubComShaovieCex3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KucoinBBO) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonCfff304bDecodeGithubComShaovieCex3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KucoinBBO) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonCfff304bDecodeGithubComShaovieCex3(l, v)
+}
+func easyjsonCfff304bDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *Kucoin24hTicker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "symbol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "lastTradedPrice":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Last).UnmarshalJSON(data))
+				}
+			}
+		case "vol":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.Volume).UnmarshalJSON(data))
+				}
+			}
+		case "volValue":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.QuoteVolume).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonCfff304bEncodeGithubComShaovieCex4(out *jwriter.Writer, in Kucoin24hTicker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"symbol\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"lastTradedPrice\":"
+		out.RawString(prefix)
+		out.Raw((in.Last).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"vol\":"
+		out.RawString(prefix)
+		out.Raw((in.Volume).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"volValue\":"
+		out.RawString(prefix)
+		out.Raw((in.QuoteVolume).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Kucoin24hTicker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonCfff304bEncodeGithubComShaovieCex4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Kucoin24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonCfff304bEncodeGithubComShaovieCex4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Kucoin24hTicker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonCfff304bDecodeGithubComShaovieCex4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Kucoin24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonCfff304bDecodeGithubComShaovieCex4(l, v)
 }
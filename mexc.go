@@ -1,8 +1,12 @@
 package cex
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,10 +16,12 @@ import (
 
 type Mexc struct {
 	Unsupported
+	Http
 	name      string
 	account   string
 	apikey    string
 	secretkey string
+	debug     bool
 
 	// spot websocket
 	spotWsPublicConn               *websocket.Conn
@@ -24,9 +30,15 @@ type Mexc struct {
 	spotWsPublicClosedMtx          sync.RWMutex
 	spotWsPublicTickerInnerPool    *sync.Pool
 	spotWsPublicOrderBookInnerPool *sync.Pool
+
+	spotWsPrivateConn      *websocket.Conn
+	spotWsPrivateConnMtx   sync.Mutex
+	spotWsPrivateClosed    bool
+	spotWsPrivateClosedMtx sync.RWMutex
+	spotWsPrivateListenKey string
 }
 type McSubscribeArg struct {
-	Id     string   `json:"id"`
+	Id     string   `json:"id,omitempty"`
 	Method string   `json:"method"`
 	Params []string `json:"params,omitempty"`
 }
@@ -39,6 +51,20 @@ type McWsApiArg struct {
 const mcUniEndpoint = "https://api.mexc.com"
 const mcApiDeadline = 1500 * time.Millisecond
 
+func NewMexc(account, apikey, secretkey string) *Mexc {
+	cexObj := &Mexc{
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("mexc"),
+		},
+		Unsupported: Unsupported{name: "mexc"},
+		name:        "mexc",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+	}
+	return cexObj
+}
 func (mc *Mexc) Name() string {
 	return mc.name
 }
@@ -48,8 +74,24 @@ func (mc *Mexc) Account() string {
 func (mc *Mexc) ApiKey() string {
 	return mc.apikey
 }
+func (mc *Mexc) Debug(v bool) {
+	mc.debug = v
+}
+func (mc *Mexc) withContext(ctx context.Context) Exchanger {
+	cexObj := &Mexc{
+		Http:      mc.Http.withContext(ctx),
+		name:      mc.name,
+		account:   mc.account,
+		apikey:    mc.apikey,
+		secretkey: mc.secretkey,
+		debug:     mc.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (mc *Mexc) Init() error {
 	mc.spotWsPublicClosed = true
+	mc.spotWsPrivateClosed = true
 	if mc.spotWsPublicTickerInnerPool == nil {
 		mc.spotWsPublicTickerInnerPool = &sync.Pool{
 			New: func() any {
@@ -69,13 +111,87 @@ func (mc *Mexc) Init() error {
 	}
 	return nil
 }
-func (mc *Mexc) handleExceptionResp(api string, resp []byte) error {
+func (mc *Mexc) apiError(httpStatus, code int, msg string) error {
+	return newApiError(mc.Name(), httpStatus, strconv.Itoa(code), msg)
+}
+func (mc *Mexc) handleExceptionResp(httpStatus int, api string, resp []byte) error {
 	ret := struct {
 		Code int    `json:"code,omitempty"`
 		Msg  string `json:"msg,omitempty"`
 	}{}
 	if err := json.Unmarshal(resp, &ret); err != nil {
-		return errors.New(mc.Name() + " " + api + " " + err.Error() + " " + string(resp))
+		// 非json, 如WAF/网关返回的html页面, 按http状态码分类
+		return newApiError(mc.Name(), httpStatus, "", api+" "+string(resp))
+	}
+	return mc.apiError(httpStatus, ret.Code, ret.Msg)
+}
+func (mc *Mexc) httpQuerySign(query string) string {
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	params := "recvWindow=3000&timestamp=" + ts + query
+	return params + "&signature=" + mc.sign(params)
+}
+func (mc *Mexc) sign(params string) string {
+	h := hmac.New(sha256.New, []byte(mc.secretkey))
+	h.Write([]byte(params))
+	return hex.EncodeToString(h.Sum(nil))
+}
+func (mc *Mexc) headers() map[string]string {
+	return map[string]string{
+		"X-MEXC-APIKEY": mc.apikey,
+		"Content-Type":  "application/json",
+	}
+}
+
+// mexc 的 IOC/FOK/LIMIT_MAKER 都是独立的订单类型
+func (mc *Mexc) fromStdOrderType(orderType, timeInForce string, postOnly bool) string {
+	if orderType == "MARKET" {
+		return "MARKET"
+	}
+	if postOnly {
+		return "LIMIT_MAKER"
+	} else if timeInForce == "IOC" {
+		return "IMMEDIATE_OR_CANCEL"
+	} else if timeInForce == "FOK" {
+		return "FILL_OR_KILL"
+	}
+	return "LIMIT"
+}
+func (mc *Mexc) toStdOrderType(orderType string) string {
+	if orderType == "MARKET" {
+		return "MARKET"
+	}
+	return "LIMIT"
+}
+func (mc *Mexc) toStdTimeInForce(orderType string) string {
+	if orderType == "IMMEDIATE_OR_CANCEL" {
+		return "IOC"
+	} else if orderType == "FILL_OR_KILL" {
+		return "FOK"
+	} else if orderType == "MARKET" {
+		return ""
+	}
+	return "GTC"
+}
+func (mc *Mexc) toStdOrderStatus(status string) string {
+	if status == "PARTIALLY_CANCELED" {
+		return "CANCELED"
+	}
+	return status
+}
+func (mc *Mexc) toStdSpotOrder(order *MexcSpotOrder) *SpotOrder {
+	return &SpotOrder{
+		Symbol:      order.Symbol,
+		OrderId:     order.OrderId,
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Quantity,
+		FilledQty:   order.ExecutedQty,
+		FilledAmt:   order.CummQuoteQty,
+		Status:      mc.toStdOrderStatus(order.Status),
+		Type:        mc.toStdOrderType(order.Type),
+		TimeInForce: mc.toStdTimeInForce(order.Type),
+		Side:        order.Side,
+		CTime:       order.Time,
+		UTime:       order.UTime,
 	}
-	return errors.New(ret.Msg)
 }
@@ -0,0 +1,232 @@
+package cex
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// mexc v3 ws 推送为protobuf, 这里只解码用到的消息和字段
+// 字段编号参考 https://github.com/mexcdevelop/websocket-proto
+
+// PushDataV3ApiWrapper 中 body 的字段编号
+const (
+	mcPbPublicLimitDepths     = 303
+	mcPbPrivateOrders         = 304
+	mcPbPrivateAccount        = 307
+	mcPbPublicMiniTicker      = 309
+	mcPbPublicAggreDeals      = 314
+	mcPbPublicAggreBookTicker = 315
+)
+
+var errMcPbInvalid = errors.New("mexc invalid protobuf data")
+
+// 遍历一层消息的所有字段, varint/fixed 放在v, length-delimited 放在data
+func mcPbRange(b []byte, fn func(field int, v uint64, data []byte)) error {
+	for len(b) > 0 {
+		key, n := mcPbVarint(b)
+		if n <= 0 {
+			return errMcPbInvalid
+		}
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0: // varint
+			v, n := mcPbVarint(b)
+			if n <= 0 {
+				return errMcPbInvalid
+			}
+			b = b[n:]
+			fn(field, v, nil)
+		case 1: // 64-bit
+			if len(b) < 8 {
+				return errMcPbInvalid
+			}
+			v := uint64(0)
+			for i := 7; i >= 0; i-- {
+				v = v<<8 | uint64(b[i])
+			}
+			b = b[8:]
+			fn(field, v, nil)
+		case 2: // length-delimited
+			l, n := mcPbVarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errMcPbInvalid
+			}
+			data := b[n : n+int(l)]
+			b = b[n+int(l):]
+			fn(field, 0, data)
+		case 5: // 32-bit
+			if len(b) < 4 {
+				return errMcPbInvalid
+			}
+			v := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24
+			b = b[4:]
+			fn(field, v, nil)
+		default:
+			return errMcPbInvalid
+		}
+	}
+	return nil
+}
+func mcPbVarint(b []byte) (uint64, int) {
+	v := uint64(0)
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+func mcPbDecimal(data []byte) decimal.Decimal {
+	v, _ := decimal.NewFromString(string(data))
+	return v
+}
+
+// PushDataV3ApiWrapper: channel=1, body=301~315, symbol=3, sendTime=6
+type MexcPbWrapper struct {
+	Channel  string
+	Symbol   string
+	SendTime int64 // msec
+	BodyType int
+	Body     []byte
+}
+
+func (v *MexcPbWrapper) Unmarshal(b []byte) error {
+	v.Channel, v.Symbol, v.SendTime, v.BodyType, v.Body = "", "", 0, 0, nil
+	return mcPbRange(b, func(field int, val uint64, data []byte) {
+		if field == 1 {
+			v.Channel = string(data)
+		} else if field == 3 {
+			v.Symbol = string(data)
+		} else if field == 6 {
+			v.SendTime = int64(val)
+		} else if field > 300 {
+			v.BodyType = field
+			v.Body = data
+		}
+	})
+}
+
+// PublicLimitDepthsV3Api: asks=1, bids=2; item: price=1, quantity=2
+func (v *MexcSpotOrderBook) UnmarshalPb(b []byte) error {
+	v.Bids = v.Bids[:0]
+	v.Asks = v.Asks[:0]
+	return mcPbRange(b, func(field int, _ uint64, data []byte) {
+		if field != 1 && field != 2 {
+			return
+		}
+		var item [2]decimal.Decimal
+		mcPbRange(data, func(f int, _ uint64, d []byte) {
+			if f == 1 {
+				item[0] = mcPbDecimal(d)
+			} else if f == 2 {
+				item[1] = mcPbDecimal(d)
+			}
+		})
+		if field == 1 {
+			v.Asks = append(v.Asks, item)
+		} else {
+			v.Bids = append(v.Bids, item)
+		}
+	})
+}
+
+// PublicAggreBookTickerV3Api: bidPrice=1, bidQuantity=2, askPrice=3, askQuantity=4
+func (v *MexcSpotBBO) UnmarshalPb(b []byte) error {
+	return mcPbRange(b, func(field int, _ uint64, data []byte) {
+		if field == 1 {
+			v.BidPrice = mcPbDecimal(data)
+		} else if field == 2 {
+			v.BidQty = mcPbDecimal(data)
+		} else if field == 3 {
+			v.AskPrice = mcPbDecimal(data)
+		} else if field == 4 {
+			v.AskQty = mcPbDecimal(data)
+		}
+	})
+}
+
+// PublicMiniTickerV3Api: symbol=1, price=2, volume=7(成交额), quantity=8(成交量)
+func (v *MexcSpot24hTicker) UnmarshalPb(b []byte) error {
+	return mcPbRange(b, func(field int, _ uint64, data []byte) {
+		if field == 1 {
+			v.Symbol = string(data)
+		} else if field == 2 {
+			v.Last = mcPbDecimal(data)
+		} else if field == 7 {
+			v.QuoteVolume = mcPbDecimal(data)
+		} else if field == 8 {
+			v.Volume = mcPbDecimal(data)
+		}
+	})
+}
+
+// PublicAggreDealsV3Api: deals=1; item: price=1, quantity=2, tradeType=3, time=4
+func (v *MexcSpotPublicTrades) UnmarshalPb(b []byte) error {
+	v.Trades = v.Trades[:0]
+	return mcPbRange(b, func(field int, _ uint64, data []byte) {
+		if field != 1 {
+			return
+		}
+		tr := MexcSpotPublicTrade{}
+		mcPbRange(data, func(f int, val uint64, d []byte) {
+			if f == 1 {
+				tr.Price = mcPbDecimal(d)
+			} else if f == 2 {
+				tr.Qty = mcPbDecimal(d)
+			} else if f == 3 {
+				tr.Side = int(val)
+			} else if f == 4 {
+				tr.Time = int64(val)
+			}
+		})
+		v.Trades = append(v.Trades, tr)
+	})
+}
+
+// PrivateOrdersV3Api
+func (v *MexcWsSpotOrder) UnmarshalPb(b []byte) error {
+	return mcPbRange(b, func(field int, val uint64, data []byte) {
+		switch field {
+		case 1:
+			v.OrderId = string(data)
+		case 2:
+			v.ClientId = string(data)
+		case 3:
+			v.Price = mcPbDecimal(data)
+		case 4:
+			v.Qty = mcPbDecimal(data)
+		case 6:
+			v.AvgPrice = mcPbDecimal(data)
+		case 7:
+			v.OrderType = int(val)
+		case 8:
+			v.Side = int(val)
+		case 13:
+			v.FilledQty = mcPbDecimal(data)
+		case 14:
+			v.FilledAmt = mcPbDecimal(data)
+		case 15:
+			v.Status = int(val)
+		case 16:
+			v.CTime = int64(val)
+		}
+	})
+}
+
+// PrivateAccountV3Api: vcoinName=1, balanceAmount=3, frozenAmount=5, time=8
+func (v *MexcWsSpotAccount) UnmarshalPb(b []byte) error {
+	return mcPbRange(b, func(field int, val uint64, data []byte) {
+		if field == 1 {
+			v.Symbol = string(data)
+		} else if field == 3 {
+			v.Avail = mcPbDecimal(data)
+		} else if field == 5 {
+			v.Locked = mcPbDecimal(data)
+		} else if field == 8 {
+			v.Time = int64(val)
+		}
+	})
+}
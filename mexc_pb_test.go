@@ -0,0 +1,152 @@
+package cex
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// 按mexc websocket-proto定义编码的推送帧, 包含未解析的字段(symbolId/createTime/eventType等)
+var mcPbFrames = map[string]string{
+	"deals": "0a2f73706f74407075626c69632e61676772652e6465616c732e76332e6170692e7062403130306d7340425443555344" +
+		"541a07425443555344542220633363366635643762306234346165316136636265356132626364376635616228dc8095" +
+		"d4c43230bca9d9d0c432d213670a1f0a0839333232302e3030120a302e3034343338323433180220bba9d9d0c4320a1b" +
+		"0a0839333232302e30311206302e30303131180120bca9d9d0c432122773706f74407075626c69632e61676772652e64" +
+		"65616c732e76332e6170692e7062403130306d73",
+	"depth": "0a2b73706f74407075626c69632e6c696d69742e64657074682e76332e6170692e7062404254435553445440351a0742" +
+		"5443555344542220633363366635643762306234346165316136636265356132626364376635616228dc8095d4c43230" +
+		"8af2d7d1c432fa127f0a160a0839333138302e3138120a302e32313937363432340a0f0a0839333138322e3232120330" +
+		"2e35120f0a0839333138302e31371203312e3212130a0839333138302e30301207302e30303132381a2173706f744070" +
+		"75626c69632e6c696d69742e64657074682e76332e6170692e7062220b3336393133323933353131",
+	"bbo": "0a3473706f74407075626c69632e61676772652e626f6f6b5469636b65722e76332e6170692e7062403130306d734042" +
+		"5443555344541a0742544355534454222063336336663564376230623434616531613663626535613262636437663561" +
+		"6228dc8095d4c4323091b0e7d1c432da13270a0839333338372e32381207332e37333438351a0839333338372e323922" +
+		"08372e363639383735",
+	"ticker": "0a2e73706f74407075626c69632e6d696e695469636b65722e76332e6170692e7062405554432b384042544355534454" +
+		"1a07425443555344542220633363366635643762306234346165316136636265356132626364376635616228dc8095d4" +
+		"c4323091b0e7d1c432aa13490a0742544355534454120839333338372e32381a06302e303137332206302e303137332a" +
+		"053934303030320539313030303a0d313132333435363738392e3132420731323032302e35",
+	"order": "0a1d73706f7440707269766174652e6f72646572732e76332e6170692e70621a074254435553445430dc8095d4c43282" +
+		"13750a1a4330325f5f34313333323132333833353436373737363030343312086d796369643030311a05393330303022" +
+		"04302e30312a03393330320739323939392e3538014001480152033436355a05302e3030356205302e3030356a05302e" +
+		"30303572083436342e3939373578038001a88095d4c432",
+	"account": "0a1e73706f7440707269766174652e6163636f756e742e76332e6170692e706230dc8095d4c4329a135a0a0455534454" +
+		"122031323866353839323731636234393531623033653731653633323365623762651a0732312e3637303722092d3436" +
+		"342e393937352a033436353201303a0d454e54525553545f504c41434540c18095d4c432",
+}
+
+func mcPbFrame(t *testing.T, name string, bodyType int) *MexcPbWrapper {
+	b, err := hex.DecodeString(mcPbFrames[name])
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &MexcPbWrapper{}
+	if err = w.Unmarshal(b); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if w.BodyType != bodyType || len(w.Body) == 0 {
+		t.Fatalf("%s: want body %d got %d", name, bodyType, w.BodyType)
+	}
+	return w
+}
+func TestMexcPbDeals(t *testing.T) {
+	w := mcPbFrame(t, "deals", mcPbPublicAggreDeals)
+	if w.Channel != "spot@public.aggre.deals.v3.api.pb@100ms@BTCUSDT" || w.Symbol != "BTCUSDT" || w.SendTime != 1736409765052 {
+		t.Fatalf("unexpected wrapper %s %s %d", w.Channel, w.Symbol, w.SendTime)
+	}
+	v := &MexcSpotPublicTrades{}
+	if err := v.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Trades) != 2 {
+		t.Fatalf("want 2 trades got %d", len(v.Trades))
+	}
+	tr := v.Trades[0]
+	if !tr.Price.Equal(dec("93220")) || !tr.Qty.Equal(dec("0.04438243")) || tr.Side != 2 || tr.Time != 1736409765051 {
+		t.Errorf("unexpected trade %+v", tr)
+	}
+	tr = v.Trades[1]
+	if !tr.Price.Equal(dec("93220.01")) || !tr.Qty.Equal(dec("0.0011")) || tr.Side != 1 || tr.Time != 1736409765052 {
+		t.Errorf("unexpected trade %+v", tr)
+	}
+}
+func TestMexcPbDepth(t *testing.T) {
+	w := mcPbFrame(t, "depth", mcPbPublicLimitDepths)
+	v := &MexcSpotOrderBook{}
+	if err := v.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Asks) != 2 || len(v.Bids) != 2 {
+		t.Fatalf("want 2 asks 2 bids got %d %d", len(v.Asks), len(v.Bids))
+	}
+	if !v.Asks[0][0].Equal(dec("93180.18")) || !v.Asks[0][1].Equal(dec("0.21976424")) ||
+		!v.Asks[1][0].Equal(dec("93182.22")) || !v.Asks[1][1].Equal(dec("0.5")) {
+		t.Errorf("unexpected asks %v", v.Asks)
+	}
+	if !v.Bids[0][0].Equal(dec("93180.17")) || !v.Bids[0][1].Equal(dec("1.2")) ||
+		!v.Bids[1][0].Equal(dec("93180")) || !v.Bids[1][1].Equal(dec("0.00128")) {
+		t.Errorf("unexpected bids %v", v.Bids)
+	}
+}
+func TestMexcPbBBOTicker(t *testing.T) {
+	w := mcPbFrame(t, "bbo", mcPbPublicAggreBookTicker)
+	bbo := &MexcSpotBBO{}
+	if err := bbo.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if !bbo.BidPrice.Equal(dec("93387.28")) || !bbo.BidQty.Equal(dec("3.73485")) ||
+		!bbo.AskPrice.Equal(dec("93387.29")) || !bbo.AskQty.Equal(dec("7.669875")) {
+		t.Errorf("unexpected bbo %+v", *bbo)
+	}
+	w = mcPbFrame(t, "ticker", mcPbPublicMiniTicker)
+	tk := &MexcSpot24hTicker{}
+	if err := tk.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if tk.Symbol != "BTCUSDT" || !tk.Last.Equal(dec("93387.28")) ||
+		!tk.QuoteVolume.Equal(dec("1123456789.12")) || !tk.Volume.Equal(dec("12020.5")) {
+		t.Errorf("unexpected ticker %+v", *tk)
+	}
+}
+func TestMexcPbPrivateOrder(t *testing.T) {
+	w := mcPbFrame(t, "order", mcPbPrivateOrders)
+	if w.Channel != "spot@private.orders.v3.api.pb" || w.Symbol != "BTCUSDT" {
+		t.Fatalf("unexpected wrapper %s %s", w.Channel, w.Symbol)
+	}
+	v := &MexcWsSpotOrder{}
+	if err := v.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if v.OrderId != "C02__413321238354677760043" || v.ClientId != "mycid001" ||
+		!v.Price.Equal(dec("93000")) || !v.Qty.Equal(dec("0.01")) || !v.AvgPrice.Equal(dec("92999.5")) ||
+		!v.FilledQty.Equal(dec("0.005")) || !v.FilledAmt.Equal(dec("464.9975")) ||
+		v.OrderType != 1 || v.Side != 1 || v.Status != 3 || v.CTime != 1736417034280 {
+		t.Errorf("unexpected order %+v", *v)
+	}
+}
+func TestMexcPbPrivateAccount(t *testing.T) {
+	w := mcPbFrame(t, "account", mcPbPrivateAccount)
+	v := &MexcWsSpotAccount{}
+	if err := v.UnmarshalPb(w.Body); err != nil {
+		t.Fatal(err)
+	}
+	if v.Symbol != "USDT" || !v.Avail.Equal(dec("21.6707")) || !v.Locked.Equal(dec("465")) || v.Time != 1736417034305 {
+		t.Errorf("unexpected account %+v", *v)
+	}
+}
+func TestMexcPbInvalid(t *testing.T) {
+	b, _ := hex.DecodeString(mcPbFrames["deals"])
+	w := &MexcPbWrapper{}
+	if err := w.Unmarshal(b[:len(b)-1]); err != errMcPbInvalid {
+		t.Errorf("truncated frame: want errMcPbInvalid got %v", err)
+	}
+	if err := w.Unmarshal([]byte{0x0b, 0x00}); err != errMcPbInvalid { // wire type 3(group)不支持
+		t.Errorf("group wire type: want errMcPbInvalid got %v", err)
+	}
+	if err := w.Unmarshal([]byte{0x30, 0xff, 0xff}); err != errMcPbInvalid { // varint未结束
+		t.Errorf("bad varint: want errMcPbInvalid got %v", err)
+	}
+	v := &MexcSpotPublicTrades{}
+	if err := v.UnmarshalPb([]byte{0x0a, 0x05, 0x0a}); err != errMcPbInvalid {
+		t.Errorf("truncated deal: want errMcPbInvalid got %v", err)
+	}
+}
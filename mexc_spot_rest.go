@@ -3,9 +3,9 @@ package cex
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/shaovie/gutils/ihttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -14,9 +14,9 @@ func (mc *Mexc) SpotSupported() bool {
 }
 func (mc *Mexc) SpotServerTime() (int64, error) {
 	url := mcUniEndpoint + "/api/v3/time"
-	_, resp, err := ihttp.Get(url, mcApiDeadline, nil)
+	_, resp, err := mc.Get(url, mcApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(mc.Name() + " net error! " + err.Error())
+		return 0, newNetError(mc.Name(), err)
 	}
 	recv := struct {
 		Time int64 `json:"serverTime,omitempty"`
@@ -29,9 +29,9 @@ func (mc *Mexc) SpotServerTime() (int64, error) {
 }
 func (mc *Mexc) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	url := mcUniEndpoint + "/api/v3/exchangeInfo"
-	_, resp, err := ihttp.Get(url, mcApiDeadline, nil)
+	_, resp, err := mc.Get(url, mcApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(mc.Name() + " net error! " + err.Error())
+		return nil, newNetError(mc.Name(), err)
 	}
 
 	recv := struct {
@@ -41,8 +41,9 @@ func (mc *Mexc) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 			Quote  string `json:"quoteAsset,omitempty"`
 			Status string `json:"status"` // 1 - 开放， 2 - 暂停， 3 - 下线
 
-			StepSize decimal.Decimal `json:"quoteAmountPrecision"`
-			TickSize decimal.Decimal `json:"baseSizePrecision"`
+			QuotePrecision int32           `json:"quotePrecision"`
+			MinNotional    decimal.Decimal `json:"quoteAmountPrecision"` // 最小下单金额
+			StepSize       decimal.Decimal `json:"baseSizePrecision"`
 		} `json:"symbols,omitempty"`
 	}{}
 	err = json.Unmarshal(resp, &recv)
@@ -65,22 +66,23 @@ func (mc *Mexc) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error)
 		}
 		ep.MinPrice = decimal.NewFromFloat(0.000000001)
 		ep.MaxPrice = decimal.NewFromFloat(999999999)
-		ep.MinOrderQty = pair.TickSize
+		ep.MinOrderQty = pair.StepSize
 		ep.MaxOrderQty = decimal.NewFromFloat(999999999)
-		ep.QtyStep = pair.TickSize
-		// ep.PriceTickSize =
+		ep.QtyStep = pair.StepSize
+		ep.PriceTickSize = decimal.New(1, -pair.QuotePrecision)
+		ep.MinNotional = pair.MinNotional
 		all[ep.Symbol] = ep
 	}
 	return all, nil
 }
 func (mc *Mexc) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
 	url := mcUniEndpoint + "/api/v3/ticker/24hr"
-	_, resp, err := ihttp.Get(url, mcApiDeadline, nil)
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(mc.Name() + " net error! " + err.Error())
+		return nil, newNetError(mc.Name(), err)
 	}
 	if resp[0] != '[' {
-		return nil, mc.handleExceptionResp("SpotGetAll24hTicker", resp)
+		return nil, mc.handleExceptionResp(httpCode, "SpotGetAll24hTicker", resp)
 	}
 	tickers := []struct {
 		Symbol      string          `json:"symbol"`
@@ -106,3 +108,305 @@ func (mc *Mexc) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
 	}
 	return allTk, nil
 }
+
+// mexc 不支持6h,12h, 1h要转成60m
+func (mc *Mexc) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	intervals := map[string]string{"1m": "1m", "5m": "5m", "15m": "15m", "30m": "30m",
+		"1h": "60m", "4h": "4h", "1d": "1d"}
+	itv, ok := intervals[interval]
+	if !ok {
+		return nil, errors.New(mc.Name() + " not support interval " + interval)
+	}
+	params := fmt.Sprintf("symbol=%s&interval=%s&limit=%d", symbol, itv, limit)
+	if startTime > 0 {
+		params += fmt.Sprintf("&startTime=%d", startTime*1000)
+	}
+	if endTime > 0 {
+		params += fmt.Sprintf("&endTime=%d", endTime*1000-1)
+	}
+	url := mcUniEndpoint + "/api/v3/klines?" + params
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(mc.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, mc.handleExceptionResp(httpCode, "SpotGetKLine", resp)
+	}
+
+	var klines [][]any
+	if err = json.Unmarshal(resp, &klines); err != nil {
+		return nil, errors.New(mc.Name() + " unmarshal error! " + err.Error())
+	}
+	all := make([]KLine, 0, len(klines))
+	for _, v := range klines {
+		if len(v) < 8 {
+			continue
+		}
+		kl := KLine{}
+		kl.OpenTime = int64(v[0].(float64)) / 1000
+		kl.OpenPrice, _ = decimal.NewFromString(v[1].(string))
+		kl.HighPrice, _ = decimal.NewFromString(v[2].(string))
+		kl.LowPrice, _ = decimal.NewFromString(v[3].(string))
+		kl.ClosePrice, _ = decimal.NewFromString(v[4].(string))
+		kl.Volume, _ = decimal.NewFromString(v[5].(string))
+		kl.QuoteVolume, _ = decimal.NewFromString(v[7].(string))
+
+		all = append(all, kl)
+	}
+	return all, nil
+}
+func (mc *Mexc) SpotGetBBO(symbol string) (BestBidAsk, error) {
+	url := mcUniEndpoint + "/api/v3/ticker/bookTicker?symbol=" + symbol
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(mc.Name(), err)
+	}
+	bbo := struct {
+		Code     int             `json:"code,omitempty"`
+		Msg      string          `json:"msg,omitempty"`
+		Symbol   string          `json:"symbol,omitempty"`
+		BidPrice decimal.Decimal `json:"bidPrice"`
+		BidQty   decimal.Decimal `json:"bidQty"`
+		AskPrice decimal.Decimal `json:"askPrice"`
+		AskQty   decimal.Decimal `json:"askQty"`
+	}{}
+	if err = json.Unmarshal(resp, &bbo); err != nil {
+		return BestBidAsk{}, errors.New(mc.Name() + " Unmarshal err! " + err.Error())
+	}
+	if bbo.Code != 0 {
+		return BestBidAsk{}, mc.apiError(httpCode, bbo.Code, bbo.Msg)
+	}
+	return BestBidAsk{
+		Symbol:   bbo.Symbol,
+		BidPrice: bbo.BidPrice,
+		BidQty:   bbo.BidQty,
+		AskPrice: bbo.AskPrice,
+		AskQty:   bbo.AskQty,
+	}, nil
+}
+func (mc *Mexc) SpotGetAllAssets() (map[string]*SpotAsset, error) {
+	url := mcUniEndpoint + "/api/v3/account?" + mc.httpQuerySign("")
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return nil, newNetError(mc.Name(), err)
+	}
+	recv := struct {
+		Code     int    `json:"code,omitempty"`
+		Msg      string `json:"msg,omitempty"`
+		Balances []struct {
+			Symbol string          `json:"asset,omitempty"`
+			Free   decimal.Decimal `json:"free"`
+			Locked decimal.Decimal `json:"locked"`
+		} `json:"balances,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &recv); err != nil {
+		return nil, errors.New(mc.Name() + " unmarshal error! " + err.Error())
+	}
+	if recv.Code != 0 {
+		return nil, mc.apiError(httpCode, recv.Code, recv.Msg)
+	}
+
+	assetsMap := make(map[string]*SpotAsset, len(recv.Balances))
+	for _, v := range recv.Balances {
+		if v.Free.IsZero() && v.Locked.IsZero() {
+			continue
+		}
+		assetsMap[v.Symbol] = &SpotAsset{
+			Symbol: v.Symbol,
+			Avail:  v.Free,
+			Locked: v.Locked,
+			Total:  v.Free.Add(v.Locked),
+		}
+	}
+	return assetsMap, nil
+}
+
+// postOnly 对应 LIMIT_MAKER, IOC/FOK 对应 IMMEDIATE_OR_CANCEL/FILL_OR_KILL
+func (mc *Mexc) SpotPlaceOrder(symbol, cltId string, /*BTCUSDT*/
+	price, amt, qty decimal.Decimal,
+	side, timeInForce, orderType string, postOnly bool) (string, error) {
+	params := fmt.Sprintf("&symbol=%s&side=%s&type=%s",
+		symbol, side, mc.fromStdOrderType(orderType, timeInForce, postOnly))
+	if cltId != "" {
+		params += "&newClientOrderId=" + cltId
+	}
+	if orderType == "LIMIT" {
+		params += "&price=" + price.String() + "&quantity=" + qty.String()
+	} else if orderType == "MARKET" {
+		if amt.IsPositive() {
+			params += "&quoteOrderQty=" + amt.String()
+		} else if qty.IsPositive() {
+			params += "&quantity=" + qty.String()
+		}
+	} else {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	url := mcUniEndpoint + "/api/v3/order?" + mc.httpQuerySign(params)
+	httpCode, resp, err := mc.Post(url, nil, mcApiDeadline, mc.headers())
+	if err != nil {
+		return "", newNetError(mc.Name(), err)
+	}
+	ret := struct {
+		Code    int    `json:"code,omitempty"`
+		Msg     string `json:"msg,omitempty"`
+		OrderId string `json:"orderId,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return "", errors.New(mc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return "", mc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return ret.OrderId, nil
+}
+func (mc *Mexc) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
+	params := "&symbol=" + symbol
+	if orderId != "" {
+		params += "&orderId=" + orderId
+	} else if cltId != "" {
+		params += "&origClientOrderId=" + cltId
+	} else {
+		return errors.New(mc.Name() + " orderId or cltId empty!")
+	}
+	url := mcUniEndpoint + "/api/v3/order?" + mc.httpQuerySign(params)
+	httpCode, resp, err := mc.Delete(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return newNetError(mc.Name(), err)
+	}
+	ret := struct {
+		Code   int    `json:"code,omitempty"`
+		Msg    string `json:"msg,omitempty"`
+		Status string `json:"status"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(mc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return mc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	if ret.Status != "CANCELED" {
+		return errors.New(mc.Name() + " cancel failed! status now: " + ret.Status)
+	}
+	return nil
+}
+func (mc *Mexc) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return spotPlaceOrdersConcurrently(mc, orders), nil
+}
+func (mc *Mexc) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return spotCancelOrdersConcurrently(mc, orders), nil
+}
+
+// mexc 必须指定symbol
+func (mc *Mexc) SpotCancelAllOrders(symbol string) error {
+	if symbol == "" {
+		return errors.New(mc.Name() + " symbol empty!")
+	}
+	url := mcUniEndpoint + "/api/v3/openOrders?" + mc.httpQuerySign("&symbol="+symbol)
+	httpCode, resp, err := mc.Delete(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return newNetError(mc.Name(), err)
+	}
+	if resp[0] != '[' {
+		return mc.handleExceptionResp(httpCode, "SpotCancelAllOrders", resp)
+	}
+	return nil
+}
+func (mc *Mexc) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
+	params := "&symbol=" + symbol
+	if orderId != "" {
+		params += "&orderId=" + orderId
+	} else if cltId != "" {
+		params += "&origClientOrderId=" + cltId
+	} else {
+		return nil, errors.New(mc.Name() + " orderId or cltId empty!")
+	}
+	url := mcUniEndpoint + "/api/v3/order?" + mc.httpQuerySign(params)
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return nil, newNetError(mc.Name(), err)
+	}
+	order := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		MexcSpotOrder
+	}{}
+	if err = json.Unmarshal(resp, &order); err != nil {
+		return nil, errors.New(mc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if order.Code != 0 {
+		return nil, mc.apiError(httpCode, order.Code, order.Msg)
+	}
+	return mc.toStdSpotOrder(&order.MexcSpotOrder), nil
+}
+
+// mexc 必须指定symbol
+func (mc *Mexc) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
+	url := mcUniEndpoint + "/api/v3/openOrders?" + mc.httpQuerySign("&symbol="+symbol)
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return nil, newNetError(mc.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, mc.handleExceptionResp(httpCode, "SpotGetOpenOrders", resp)
+	}
+	orders := []MexcSpotOrder{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(mc.Name() + " Unmarshal err! " + err.Error())
+	}
+	dl := make([]*SpotOrder, 0, len(orders))
+	for i := range orders {
+		dl = append(dl, mc.toStdSpotOrder(&orders[i]))
+	}
+	return dl, nil
+}
+
+// 最近24小时内的订单中已完全成交的
+func (mc *Mexc) SpotGetFilledOrders(symbol string) ([]*SpotOrder, error) {
+	url := mcUniEndpoint + "/api/v3/allOrders?" + mc.httpQuerySign("&limit=200&symbol="+symbol)
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return nil, newNetError(mc.Name(), err)
+	}
+	if resp[0] != '[' {
+		return nil, mc.handleExceptionResp(httpCode, "SpotGetFilledOrders", resp)
+	}
+	orders := []MexcSpotOrder{}
+	if err = json.Unmarshal(resp, &orders); err != nil {
+		return nil, errors.New(mc.Name() + " Unmarshal err! " + err.Error())
+	}
+	dl := make([]*SpotOrder, 0, len(orders))
+	for i := range orders {
+		if orders[i].Status != "FILLED" {
+			continue
+		}
+		dl = append(dl, mc.toStdSpotOrder(&orders[i]))
+	}
+	return dl, nil
+}
+func (mc *Mexc) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
+	var f SpotTradeFee
+	url := mcUniEndpoint + "/api/v3/tradeFee?" + mc.httpQuerySign("&symbol="+symbol)
+	httpCode, resp, err := mc.Get(url, mcApiDeadline, mc.headers())
+	if err != nil {
+		return f, newNetError(mc.Name(), err)
+	}
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Maker decimal.Decimal `json:"makerCommission"`
+			Taker decimal.Decimal `json:"takerCommission"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return f, errors.New(mc.Name() + " Unmarshal err! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return f, mc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return SpotTradeFee{
+		Maker: ret.Data.Maker,
+		Taker: ret.Data.Taker,
+	}, nil
+}
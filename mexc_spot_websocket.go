@@ -0,0 +1,466 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shaovie/gutils/ilog"
+)
+
+// 公共/私有推送均为protobuf(BinaryMessage), 订阅响应和pong为json(TextMessage)
+func (mc *Mexc) SpotWsPublicOpen() error {
+	url := mc.wsUrl("wss://wbs-api.mexc.com/ws")
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	mc.spotWsPublicConn, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(mc.Name() + " spot.ws.public con failed! " + err.Error())
+	}
+	mc.spotWsPublicClosedMtx.Lock()
+	mc.spotWsPublicClosed = false
+	mc.spotWsPublicClosedMtx.Unlock()
+	return nil
+}
+
+// 每个连接最多30个订阅
+func (mc *Mexc) SpotWsPublicSubscribe(channels []string) {
+	mc.spotWsPublicSubscribe("SUBSCRIPTION", channels)
+}
+func (mc *Mexc) SpotWsPublicUnsubscribe(channels []string) {
+	mc.spotWsPublicSubscribe("UNSUBSCRIPTION", channels)
+}
+func (mc *Mexc) spotWsPublicSubscribe(method string, channels []string) {
+	if len(channels) == 0 {
+		return
+	}
+	arg := McSubscribeArg{Method: method}
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		symbolArr := strings.SplitSeq(arr[1], ",")
+		if arr[0] == "orderbook5" {
+			for sym := range symbolArr {
+				arg.Params = append(arg.Params, "spot@public.limit.depth.v3.api.pb@"+strings.ToUpper(sym)+"@5")
+			}
+		} else if arr[0] == "bbo" {
+			for sym := range symbolArr {
+				arg.Params = append(arg.Params, "spot@public.aggre.bookTicker.v3.api.pb@100ms@"+strings.ToUpper(sym))
+			}
+		} else if arr[0] == "trades" {
+			for sym := range symbolArr {
+				arg.Params = append(arg.Params, "spot@public.aggre.deals.v3.api.pb@100ms@"+strings.ToUpper(sym))
+			}
+		} else if arr[0] == "ticker" {
+			for sym := range symbolArr {
+				arg.Params = append(arg.Params, "spot@public.miniTicker.v3.api.pb@"+strings.ToUpper(sym)+"@UTC+0")
+			}
+		}
+	}
+	if len(arg.Params) > 0 {
+		req, _ := json.Marshal(&arg)
+		mc.spotWsPublicConnMtx.Lock()
+		mc.spotWsPublicConn.WriteMessage(websocket.TextMessage, req)
+		mc.spotWsPublicConnMtx.Unlock()
+	}
+}
+func (mc *Mexc) SpotWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (mc *Mexc) SpotWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (mc *Mexc) SpotWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (mc *Mexc) SpotWsPublicTradePoolPut(v any) {
+	wsPublicTradePool.Put(v)
+}
+
+// pong: {"id":0,"code":0,"msg":"PONG"}
+func (mc *Mexc) wsHandleTextMsg(recv []byte, conn *websocket.Conn, pongWait time.Duration) {
+	ret := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err := json.Unmarshal(recv, &ret); err != nil {
+		ilog.Error("%s", mc.Name()+" ws recv invalid msg:"+string(recv))
+		return
+	}
+	if ret.Msg == "PONG" {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+	} else if ret.Code != 0 || strings.Index(ret.Msg, "Not Subscribed") != -1 ||
+		strings.Index(ret.Msg, "Blocked") != -1 {
+		ilog.Error("%s", mc.Name()+" ws recv subscribe err:"+string(recv))
+	}
+}
+func (mc *Mexc) SpotWsPublicLoop(ch chan<- any) {
+	defer mc.SpotWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 20 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	mc.spotWsPublicConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		ping := `{"method":"PING"}`
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if mc.SpotWsPublicIsClosed() {
+					break
+				}
+				mc.spotWsPublicConnMtx.Lock()
+				mc.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(ping))
+				mc.spotWsPublicConnMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	var msg MexcPbWrapper
+	for {
+		typ, recv, err := mc.spotWsPublicConn.ReadMessage()
+		if err != nil {
+			if !mc.SpotWsPublicIsClosed() { // 并非主动断开
+				ilog.Warning("%s", mc.Name()+" spot.ws.public read: "+err.Error())
+			}
+			break
+		}
+		if typ == websocket.TextMessage {
+			mc.wsHandleTextMsg(recv, mc.spotWsPublicConn, pongWait)
+			continue
+		}
+		if err = msg.Unmarshal(recv); err != nil {
+			ilog.Error("%s", mc.Name()+" spot.ws.public invalid msg: "+err.Error())
+			continue
+		}
+		if msg.BodyType == mcPbPublicLimitDepths {
+			mc.spotWsHandleOrderBook5(&msg, ch)
+		} else if msg.BodyType == mcPbPublicAggreBookTicker {
+			mc.spotWsHandleBBO(&msg, ch)
+		} else if msg.BodyType == mcPbPublicAggreDeals {
+			mc.spotWsHandlePublicTrade(&msg, ch)
+		} else if msg.BodyType == mcPbPublicMiniTicker {
+			mc.spotWsHandle24hTickers(&msg, ch)
+		}
+	}
+}
+func (mc *Mexc) SpotWsPublicIsClosed() bool {
+	mc.spotWsPublicClosedMtx.RLock()
+	defer mc.spotWsPublicClosedMtx.RUnlock()
+	return mc.spotWsPublicClosed
+}
+func (mc *Mexc) SpotWsPublicClose() {
+	mc.spotWsPublicClosedMtx.Lock()
+	defer mc.spotWsPublicClosedMtx.Unlock()
+	if mc.spotWsPublicClosed {
+		return
+	}
+	mc.spotWsPublicClosed = true
+	mc.spotWsPublicConn.Close()
+}
+func (mc *Mexc) spotWsHandleOrderBook5(msg *MexcPbWrapper, ch chan<- any) {
+	depth := mc.spotWsPublicOrderBookInnerPool.Get().(*MexcSpotOrderBook)
+	defer mc.spotWsPublicOrderBookInnerPool.Put(depth)
+	if err := depth.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.public "+msg.Channel+" orderbook5 exception")
+		return
+	}
+	obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+	obd.Symbol = msg.Symbol
+	obd.Level = 5
+	obd.Time = msg.SendTime
+	obd.Bids = obd.Bids[:0]
+	obd.Asks = obd.Asks[:0]
+	for _, v := range depth.Bids {
+		obd.Bids = append(obd.Bids, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	for _, v := range depth.Asks {
+		obd.Asks = append(obd.Asks, Ticker{Price: v[0], Quantity: v[1]})
+	}
+	ch <- obd
+}
+func (mc *Mexc) spotWsHandleBBO(msg *MexcPbWrapper, ch chan<- any) {
+	bbo := MexcSpotBBO{}
+	if err := bbo.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.public "+msg.Channel+" bbo exception")
+		return
+	}
+	obd := wsPublicBBOPool.Get().(*BestBidAsk)
+	obd.Symbol = msg.Symbol
+	obd.Time = msg.SendTime
+	obd.BidPrice = bbo.BidPrice
+	obd.BidQty = bbo.BidQty
+	obd.AskPrice = bbo.AskPrice
+	obd.AskQty = bbo.AskQty
+	ch <- obd
+}
+func (mc *Mexc) spotWsHandlePublicTrade(msg *MexcPbWrapper, ch chan<- any) {
+	trades := MexcSpotPublicTrades{}
+	if err := trades.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.public "+msg.Channel+" trades exception")
+		return
+	}
+	for _, v := range trades.Trades {
+		pt := wsPublicTradePool.Get().(*PublicTrade)
+		pt.Symbol = msg.Symbol
+		pt.Time = v.Time
+		pt.Price = v.Price
+		pt.Qty = v.Qty
+		ch <- pt
+	}
+}
+func (mc *Mexc) spotWsHandle24hTickers(msg *MexcPbWrapper, ch chan<- any) {
+	ticker := mc.spotWsPublicTickerInnerPool.Get().(*MexcSpot24hTicker)
+	defer mc.spotWsPublicTickerInnerPool.Put(ticker)
+	if err := ticker.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.public "+msg.Channel+" ticker exception")
+		return
+	}
+	tk := wsPublicTickerPool.Get().(*Pub24hTicker)
+	tk.Symbol = ticker.Symbol
+	tk.LastPrice = ticker.Last
+	tk.Volume = ticker.Volume
+	tk.QuoteVolume = ticker.QuoteVolume
+	ch <- tk
+}
+
+// = priv channel
+func (mc *Mexc) SpotWsPrivateSupported() bool {
+	return true
+}
+
+// listenKey 有效期60分钟, 在loop中每30分钟延期一次
+func (mc *Mexc) spotGetListenKey() (string, error) {
+	url := mcUniEndpoint + "/api/v3/userDataStream?" + mc.httpQuerySign("")
+	httpCode, resp, err := mc.Post(url, nil, mcApiDeadline, mc.headers())
+	if err != nil {
+		return "", newNetError(mc.Name(), err)
+	}
+	ret := struct {
+		Code      int    `json:"code,omitempty"`
+		Msg       string `json:"msg,omitempty"`
+		ListenKey string `json:"listenKey"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return "", errors.New(mc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 || ret.ListenKey == "" {
+		return "", mc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return ret.ListenKey, nil
+}
+func (mc *Mexc) spotKeepaliveListenKey(listenKey string) error {
+	url := mcUniEndpoint + "/api/v3/userDataStream?" + mc.httpQuerySign("&listenKey="+listenKey)
+	httpCode, resp, err := mc.Put(url, nil, mcApiDeadline, mc.headers())
+	if err != nil {
+		return newNetError(mc.Name(), err)
+	}
+	ret := struct {
+		Code int    `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(mc.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != 0 {
+		return mc.apiError(httpCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
+func (mc *Mexc) SpotWsPrivateOpen() error {
+	listenKey, err := mc.spotGetListenKey()
+	if err != nil {
+		return errors.New(mc.Name() + " get listenkey fail! " + err.Error())
+	}
+	url := mc.wsUrl("wss://wbs-api.mexc.com/ws") + "?listenKey=" + listenKey
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	mc.spotWsPrivateConn, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(mc.Name() + " spot.ws.priv connect failed! " + err.Error())
+	}
+	mc.spotWsPrivateListenKey = listenKey
+	mc.spotWsPrivateClosedMtx.Lock()
+	mc.spotWsPrivateClosed = false
+	mc.spotWsPrivateClosedMtx.Unlock()
+	return nil
+}
+func (mc *Mexc) SpotWsPrivateSubscribe(channels []string) {
+	arg := McSubscribeArg{Method: "SUBSCRIPTION"}
+	for _, c := range channels {
+		if c == "orders" {
+			arg.Params = append(arg.Params, "spot@private.orders.v3.api.pb")
+		} else if c == "balance" {
+			arg.Params = append(arg.Params, "spot@private.account.v3.api.pb")
+		}
+	}
+	if len(arg.Params) > 0 {
+		req, _ := json.Marshal(&arg)
+		mc.spotWsPrivateConnMtx.Lock()
+		if err := mc.spotWsPrivateConn.WriteMessage(websocket.TextMessage, req); err != nil {
+			ilog.Warning("%s", mc.Name()+" spot.ws.priv subscribe net error! "+err.Error())
+		}
+		mc.spotWsPrivateConnMtx.Unlock()
+	}
+}
+func (mc *Mexc) SpotWsPrivateIsClosed() bool {
+	mc.spotWsPrivateClosedMtx.RLock()
+	defer mc.spotWsPrivateClosedMtx.RUnlock()
+	return mc.spotWsPrivateClosed
+}
+func (mc *Mexc) SpotWsPrivateClose() {
+	mc.spotWsPrivateClosedMtx.Lock()
+	defer mc.spotWsPrivateClosedMtx.Unlock()
+	if mc.spotWsPrivateClosed {
+		return
+	}
+	mc.spotWsPrivateClosed = true
+	mc.spotWsPrivateConn.Close()
+}
+func (mc *Mexc) SpotWsPrivateLoop(ch chan<- any) {
+	defer mc.SpotWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 20 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	mc.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		ping := `{"method":"PING"}`
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if mc.SpotWsPrivateIsClosed() {
+					break
+				}
+				mc.spotWsPrivateConnMtx.Lock()
+				mc.spotWsPrivateConn.WriteMessage(websocket.TextMessage, []byte(ping))
+				mc.spotWsPrivateConnMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	keepaliveExit := make(chan struct{})
+	defer close(keepaliveExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(30 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if mc.SpotWsPrivateIsClosed() {
+					break
+				}
+				if err := mc.spotKeepaliveListenKey(mc.spotWsPrivateListenKey); err != nil {
+					ilog.Warning("%s", mc.Name()+" spot.ws.priv keepalive listenkey fail! "+err.Error())
+				}
+			}
+		}
+	}(keepaliveExit)
+
+	var msg MexcPbWrapper
+	for {
+		typ, recv, err := mc.spotWsPrivateConn.ReadMessage()
+		if err != nil {
+			if !mc.SpotWsPrivateIsClosed() {
+				ilog.Warning("%s", mc.Name()+" spot.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if typ == websocket.TextMessage {
+			mc.wsHandleTextMsg(recv, mc.spotWsPrivateConn, pongWait)
+			continue
+		}
+		if err = msg.Unmarshal(recv); err != nil {
+			ilog.Error("%s", mc.Name()+" spot.ws.priv invalid msg: "+err.Error())
+			continue
+		}
+		if msg.BodyType == mcPbPrivateOrders {
+			mc.spotWsHandleOrder(&msg, ch)
+		} else if msg.BodyType == mcPbPrivateAccount {
+			mc.spotWsHandleBalanceUpdate(&msg, ch)
+		}
+	}
+}
+
+// orderType 1:限价 2:只挂单 3:IOC 4:FOK 5:市价
+// status 1:未成交 2:已成交 3:部分成交 4:已撤单 5:部分撤单
+func (mc *Mexc) spotWsHandleOrder(msg *MexcPbWrapper, ch chan<- any) {
+	order := MexcWsSpotOrder{}
+	if err := order.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.priv handle order: "+err.Error())
+		return
+	}
+	o := &SpotOrder{
+		Symbol:      msg.Symbol,
+		OrderId:     order.OrderId,
+		ClientId:    order.ClientId,
+		Price:       order.Price,
+		Qty:         order.Qty,
+		FilledQty:   order.FilledQty,
+		FilledAmt:   order.FilledAmt,
+		AvgPrice:    order.AvgPrice,
+		Type:        "LIMIT",
+		TimeInForce: "GTC",
+		CTime:       order.CTime,
+		UTime:       msg.SendTime,
+	}
+	if order.OrderType == 3 {
+		o.TimeInForce = "IOC"
+	} else if order.OrderType == 4 {
+		o.TimeInForce = "FOK"
+	} else if order.OrderType == 5 {
+		o.Type = "MARKET"
+		o.TimeInForce = ""
+	}
+	if order.Side == 1 {
+		o.Side = "BUY"
+	} else if order.Side == 2 {
+		o.Side = "SELL"
+	}
+	if order.Status == 1 {
+		o.Status = "NEW"
+	} else if order.Status == 2 {
+		o.Status = "FILLED"
+	} else if order.Status == 3 {
+		o.Status = "PARTIALLY_FILLED"
+	} else if order.Status == 4 || order.Status == 5 {
+		o.Status = "CANCELED"
+	}
+	ch <- o
+}
+func (mc *Mexc) spotWsHandleBalanceUpdate(msg *MexcPbWrapper, ch chan<- any) {
+	bl := MexcWsSpotAccount{}
+	if err := bl.UnmarshalPb(msg.Body); err != nil {
+		ilog.Error("%s", mc.Name()+" spot.ws.priv handle balance: "+err.Error())
+		return
+	}
+	ch <- &SpotAsset{
+		Symbol: bl.Symbol,
+		Avail:  bl.Avail,
+		Locked: bl.Locked,
+		Total:  bl.Avail.Add(bl.Locked),
+	}
+}
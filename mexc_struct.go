@@ -28,3 +28,55 @@ type MexcSpotOrderBook struct {
 	Bids [][2]decimal.Decimal `json:"bids,omitempty"`
 	Asks [][2]decimal.Decimal `json:"asks,omitempty"`
 }
+type MexcSpotBBO struct {
+	BidPrice decimal.Decimal
+	BidQty   decimal.Decimal
+	AskPrice decimal.Decimal
+	AskQty   decimal.Decimal
+}
+type MexcSpotPublicTrade struct {
+	Price decimal.Decimal
+	Qty   decimal.Decimal
+	Side  int   // 1:buy 2:sell
+	Time  int64 // msec
+}
+type MexcSpotPublicTrades struct {
+	Trades []MexcSpotPublicTrade
+}
+type MexcSpotOrder struct {
+	Symbol       string          `json:"symbol,omitempty"` // BTCUSDT
+	OrderId      string          `json:"orderId,omitempty"`
+	ClientId     string          `json:"clientOrderId,omitempty"`
+	Price        decimal.Decimal `json:"price"`
+	Quantity     decimal.Decimal `json:"origQty"`             // 用户设置的原始订单数量
+	ExecutedQty  decimal.Decimal `json:"executedQty"`         // 交易的订单数量
+	CummQuoteQty decimal.Decimal `json:"cummulativeQuoteQty"` // 累计交易的金额
+	Status       string          `json:"status,omitempty"`
+	Type         string          `json:"type,omitempty"` // LIMIT/MARKET/LIMIT_MAKER/IMMEDIATE_OR_CANCEL/FILL_OR_KILL
+	Side         string          `json:"side,omitempty"`
+	Time         int64           `json:"time,omitempty"`
+	UTime        int64           `json:"updateTime,omitempty"`
+}
+
+// 私有推送 spot@private.orders.v3.api.pb
+type MexcWsSpotOrder struct {
+	OrderId   string
+	ClientId  string
+	Price     decimal.Decimal
+	Qty       decimal.Decimal
+	AvgPrice  decimal.Decimal
+	FilledQty decimal.Decimal
+	FilledAmt decimal.Decimal
+	OrderType int   // 1:限价 2:只挂单 3:IOC 4:FOK 5:市价
+	Side      int   // 1:buy 2:sell
+	Status    int   // 1:未成交 2:已成交 3:部分成交 4:已撤单 5:部分撤单
+	CTime     int64 // msec
+}
+
+// 私有推送 spot@private.account.v3.api.pb
+type MexcWsSpotAccount struct {
+	Symbol string
+	Avail  decimal.Decimal
+	Locked decimal.Decimal
+	Time   int64 // msec
+}
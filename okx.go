@@ -1,17 +1,21 @@
 package cex
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 )
 
 type Okx struct {
 	Unsupported
+	Http
 	name      string
 	account   string
 	apikey    string
@@ -25,6 +29,8 @@ type Okx struct {
 	spotWsPublicClosed          bool
 	spotWsPublicClosedMtx       sync.RWMutex
 	spotWsPublicTickerInnerPool *sync.Pool
+	spotWsOrderBooks            localOrderBooks
+	spotWsBusiness              okxWsBusiness // kline
 
 	spotWsPrivateConn      *websocket.Conn
 	spotWsPrivateConnMtx   sync.Mutex
@@ -39,6 +45,7 @@ type Okx struct {
 	wsContractPubChannelClosedMtx sync.RWMutex
 	wsContractPubTickerPool       *sync.Pool
 	wsContractPubOrderBookPool    *sync.Pool
+	wsContractBusiness            okxWsBusiness // kline
 
 	wsContractPrivCon              *websocket.Conn
 	wsContractPrivConPongTime      int64
@@ -58,6 +65,7 @@ var (
 	okxSpotSymbolMapMtx sync.Mutex
 
 	okxContractSymbolMap    map[string]string
+	okxContractValMap       map[string]decimal.Decimal // 合约面值ctVal, UM为标的数量
 	okxContractSymbolMapMtx sync.Mutex
 )
 
@@ -67,14 +75,20 @@ const okApiDeadline = 1500 * time.Millisecond
 func init() {
 	okxSpotSymbolMap = make(map[string]string)
 	okxContractSymbolMap = make(map[string]string)
+	okxContractValMap = make(map[string]decimal.Decimal)
 }
 func NewOkx(account, apikey, secretkey, passwd string) *Okx {
 	cexObj := &Okx{
-		name:      "okx",
-		account:   account,
-		apikey:    apikey,
-		secretkey: secretkey,
-		passwd:    passwd,
+		Http: Http{
+			client:  sharedClient,
+			limiter: getRateLimiter("okx"),
+		},
+		Unsupported: Unsupported{name: "okx"},
+		name:        "okx",
+		account:     account,
+		apikey:      apikey,
+		secretkey:   secretkey,
+		passwd:      passwd,
 	}
 	return cexObj
 }
@@ -90,6 +104,19 @@ func (ok *Okx) ApiKey() string {
 func (ok *Okx) Debug(v bool) {
 	ok.debug = v
 }
+func (ok *Okx) withContext(ctx context.Context) Exchanger {
+	cexObj := &Okx{
+		Http:      ok.Http.withContext(ctx),
+		name:      ok.name,
+		account:   ok.account,
+		apikey:    ok.apikey,
+		secretkey: ok.secretkey,
+		passwd:    ok.passwd,
+		debug:     ok.debug,
+	}
+	cexObj.Init()
+	return cexObj
+}
 func (ok *Okx) Init() error {
 	ok.spotWsPublicClosed = true
 	ok.spotWsPrivateClosed = true
@@ -105,6 +132,13 @@ func (ok *Okx) Init() error {
 			},
 		}
 	}
+	if ok.wsContractPubTickerPool == nil {
+		ok.wsContractPubTickerPool = &sync.Pool{
+			New: func() any {
+				return make([]Okx24hTicker, 0, 2)
+			},
+		}
+	}
 	return nil
 }
 func (ok *Okx) getSpotSymbol(symbol string) string {
@@ -117,6 +151,23 @@ func (ok *Okx) getContractSymbol(symbol string) string {
 	defer okxContractSymbolMapMtx.Unlock()
 	return okxContractSymbolMap[symbol]
 }
+func (ok *Okx) getContractVal(symbol string) decimal.Decimal {
+	okxContractSymbolMapMtx.Lock()
+	defer okxContractSymbolMapMtx.Unlock()
+	return okxContractValMap[symbol]
+}
+
+// BTC-USDT-SWAP => BTCUSDT,UM  BTC-USD-SWAP => BTCUSD,CM
+func (ok *Okx) toStdContractSymbol(instId string) (string, string) {
+	arr := strings.Split(instId, "-")
+	if len(arr) != 3 || arr[2] != "SWAP" {
+		return "", ""
+	}
+	if arr[1] == "USD" {
+		return arr[0] + arr[1], "CM"
+	}
+	return arr[0] + arr[1], "UM"
+}
 func (ok *Okx) buildHeaders(method, path, body string) map[string]string {
 	ts := time.Now().UTC().Format("2006-01-02T15:04:05.999Z")
 	return map[string]string{
@@ -132,6 +183,9 @@ func (ok *Okx) sign(params string) string {
 	h.Write([]byte(params))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
+func (ok *Okx) apiError(httpStatus int, code, msg string) error {
+	return newApiError(ok.Name(), httpStatus, code, msg)
+}
 func (ok *Okx) toStdSide(side string) string {
 	if side == "buy" {
 		return "BUY"
@@ -161,6 +215,8 @@ func (ok *Okx) toStdOrderType(orderType string) string {
 		return "IOC"
 	} else if orderType == "fok" {
 		return "FOK"
+	} else if orderType == "post_only" {
+		return "LIMIT"
 	}
 	return ""
 }
@@ -176,11 +232,39 @@ func (ok *Okx) fromStdOrderType(orderType string) string {
 	}
 	return ""
 }
+func (ok *Okx) fromStdFuturesOrderType(orderType, timeInForce string) string {
+	if orderType == "LIMIT" {
+		if timeInForce == "IOC" {
+			return "ioc"
+		} else if timeInForce == "FOK" {
+			return "fok"
+		} else if timeInForce == "GTX" {
+			return "post_only"
+		}
+	}
+	return ok.fromStdOrderType(orderType)
+}
+func (ok *Okx) fromStdPositionMode(positionMode string) string {
+	if positionMode == "LONG" {
+		return "long"
+	} else if positionMode == "SHORT" {
+		return "short"
+	}
+	return "" // 单仓模式不传
+}
+func (ok *Okx) fromStdTradeMode(tradeMode int) string {
+	if tradeMode == 1 {
+		return "isolated"
+	}
+	return "cross"
+}
 func (ok *Okx) fromStdTriggerBy(triggerBy string) string {
-	if triggerBy == "MARKET_PRICE" {
+	if triggerBy == "MARKET_PRICE" || triggerBy == "" {
 		return "last"
 	} else if triggerBy == "MARK_PRICE" {
 		return "mark"
+	} else if triggerBy == "INDEX_PRICE" {
+		return "index"
 	}
 	return ""
 }
@@ -189,6 +273,20 @@ func (ok *Okx) toStdTriggerBy(triggerBy string) string {
 		return "MARKET_PRICE"
 	} else if triggerBy == "mark" {
 		return "MARK_PRICE"
+	} else if triggerBy == "index" {
+		return "INDEX_PRICE"
+	}
+	return ""
+}
+func (ok *Okx) toStdAlgoOrderStatus(status string) string {
+	if status == "live" || status == "pause" {
+		return "NEW"
+	} else if status == "effective" || status == "partially_effective" {
+		return "TRIGGERED"
+	} else if status == "canceled" {
+		return "CANCELED"
+	} else if status == "order_failed" {
+		return "REJECTED"
 	}
 	return ""
 }
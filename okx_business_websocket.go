@@ -0,0 +1,187 @@
+package cex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shopspring/decimal"
+
+	"github.com/shaovie/gutils/ilog"
+)
+
+// okx 的candle频道只在business连接上, kline@ 订阅时才建立连接
+// 由public loop启动的goroutine读取, 推送到public的ch, business断开时同时关闭public连接, 由使用方重连
+type okxWsBusiness struct {
+	name   string // spot.ws.business / futures.ws.business 用于日志
+	conn   *websocket.Conn
+	mtx    sync.Mutex
+	ready  chan struct{} // conn建立后close
+	closed bool
+}
+
+// public连接Open时调用, 重置状态
+func (b *okxWsBusiness) open(name string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.name = name
+	b.conn = nil
+	b.ready = make(chan struct{})
+	b.closed = false
+}
+
+// dial: 未连接时是否建立连接, 退订时不需要
+func (b *okxWsBusiness) write(ok *Okx, data []byte, dial bool) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.closed || b.ready == nil {
+		return errors.New(ok.Name() + " " + b.name + " closed")
+	}
+	if b.conn == nil {
+		if !dial {
+			return nil
+		}
+		dialer := websocket.Dialer{
+			EnableCompression: true, // 启用压缩扩展
+			HandshakeTimeout:  2 * time.Second,
+		}
+		conn, _, err := dialer.Dial(ok.wsUrl("wss://ws.okx.com:8443/ws/v5/business"), nil)
+		if err != nil {
+			return errors.New(ok.Name() + " " + b.name + " con failed! " + err.Error())
+		}
+		b.conn = conn
+		close(b.ready)
+	}
+	return b.conn.WriteMessage(websocket.TextMessage, data)
+}
+func (b *okxWsBusiness) isClosed() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.closed
+}
+func (b *okxWsBusiness) close() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	if b.conn != nil {
+		b.conn.Close()
+	}
+}
+
+// 等待连接建立后读取, exit关闭或连接断开时返回
+// 非主动断开时调用onBroken(关闭public连接)
+func (b *okxWsBusiness) loop(ok *Okx, exit <-chan struct{}, onBroken func(),
+	handle func(msg *OkxWsPubMsg)) {
+	select {
+	case <-b.ready:
+	case <-exit:
+		return
+	}
+	b.mtx.Lock()
+	conn := b.conn
+	b.mtx.Unlock()
+
+	pingInterval := 28 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-exit:
+				return
+			case <-ticker.C:
+				if b.write(ok, []byte("ping"), false) != nil {
+					return
+				}
+			}
+		}
+	}()
+	for {
+		_, recv, err := conn.ReadMessage()
+		if err != nil {
+			if !b.isClosed() {
+				ilog.Warning("%s", ok.Name()+" "+b.name+" read: "+err.Error())
+				onBroken()
+			}
+			return
+		}
+		if len(recv) == 4 && bytes.Equal(recv, []byte("pong")) {
+			conn.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		msg := okxWsPubMsgPool.Get().(*OkxWsPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", ok.Name()+" "+b.name+" recv invalid msg:"+string(recv))
+		} else if len(msg.Event) == 0 {
+			handle(msg)
+		} else if msg.Event == "error" {
+			ilog.Error("%s", ok.Name()+" "+b.name+" recv error event: "+string(recv))
+		}
+		okxWsPubMsgPool.Put(msg)
+	}
+}
+
+// kline@ 订阅参数, interval不支持时跳过
+func (ok *Okx) wsKLineArgs(symbols string, getSymbol func(string) string) []map[string]string {
+	args := make([]map[string]string, 0, 2)
+	for v := range strings.SplitSeq(symbols, ",") {
+		sym, interval := parseKLineSymbol(v)
+		bar, ok0 := okxKLineBars[interval]
+		if !ok0 {
+			ilog.Error("%s", ok.Name()+" not support kline interval "+interval)
+			continue
+		}
+		if instId := getSymbol(sym); instId != "" {
+			args = append(args, map[string]string{"channel": "candle" + bar, "instId": instId})
+		}
+	}
+	return args
+}
+
+// [ts,o,h,l,c,vol,volCcy,volCcyQuote,confirm], volIdx同getKLine
+func (ok *Okx) wsHandleKLine(symbol, channel string, data json.RawMessage, volIdx int,
+	ch chan<- any) {
+	bar := strings.TrimPrefix(channel, "candle")
+	interval := ""
+	for k, v := range okxKLineBars {
+		if v == bar {
+			interval = k
+			break
+		}
+	}
+	var list [][]string
+	if err := json.Unmarshal(data, &list); err != nil || interval == "" {
+		ilog.Error("%s", ok.Name()+" ws kline exception: "+string(data))
+		return
+	}
+	for _, v := range list {
+		if len(v) < 9 {
+			continue
+		}
+		kl := wsPublicKLinePool.Get().(*KLine)
+		kl.Symbol = symbol
+		kl.Interval = interval
+		ts, _ := strconv.ParseInt(v[0], 10, 64)
+		kl.OpenTime = ts / 1000
+		kl.OpenPrice, _ = decimal.NewFromString(v[1])
+		kl.HighPrice, _ = decimal.NewFromString(v[2])
+		kl.LowPrice, _ = decimal.NewFromString(v[3])
+		kl.ClosePrice, _ = decimal.NewFromString(v[4])
+		kl.Volume, _ = decimal.NewFromString(v[volIdx])
+		kl.QuoteVolume, _ = decimal.NewFromString(v[7])
+		kl.IsClosed = v[8] == "1"
+		ch <- kl
+	}
+}
@@ -0,0 +1,1173 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/shopspring/decimal"
+)
+
+// okx 永续合约 UM:BTC-USDT-SWAP CM:BTC-USD-SWAP
+// 下单数量sz为张数, UM中 qty=sz*ctVal, CM中 qty就是张数
+func (ok *Okx) FuturesSupported(typ string) bool {
+	if typ == "UM" || typ == "CM" {
+		return true
+	}
+	return false
+}
+func (ok *Okx) FuturesServerTime(typ string) (int64, error) {
+	return ok.SpotServerTime()
+}
+
+// 需要先调用FuturesLoadAllPairRule, 取不到ctVal时返回0
+func (ok *Okx) FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal {
+	if typ == "CM" {
+		return size
+	}
+	return size.Mul(ok.getContractVal(symbol))
+}
+func (ok *Okx) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal) decimal.Decimal {
+	if typ == "CM" {
+		return qty
+	}
+	ctVal := ok.getContractVal(symbol)
+	if ctVal.IsZero() {
+		return decimal.Zero
+	}
+	return qty.Div(ctVal)
+}
+func (ok *Okx) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
+	url := okUniEndpoint + "/api/v5/public/instruments?instType=SWAP"
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			InstId      string `json:"instId"`
+			CtType      string `json:"ctType"` // linear/inverse
+			CtVal       string `json:"ctVal"`
+			State       string `json:"state"`
+			PriceTickSz string `json:"tickSz"`
+			QtyStep     string `json:"lotSz"`
+			MinOrderQty string `json:"minSz"`
+			MaxOrderQty string `json:"maxLmtSz"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	ctType := "linear"
+	if typ == "CM" {
+		ctType = "inverse"
+	}
+	all := make(map[string]*FuturesExchangePairRule)
+	now := time.Now().Unix()
+	tokxContractSymbolMap := make(map[string]string)
+	tokxContractValMap := make(map[string]decimal.Decimal)
+	for _, pair := range ret.Data {
+		if pair.State != "live" || pair.CtType != ctType {
+			continue
+		}
+		arr := strings.Split(pair.InstId, "-")
+		if len(arr) != 3 {
+			continue
+		}
+		ctVal, _ := decimal.NewFromString(pair.CtVal)
+		if !ctVal.IsPositive() {
+			continue
+		}
+		ep := &FuturesExchangePairRule{
+			Typ:      typ,
+			Symbol:   arr[0] + arr[1],
+			Base:     arr[0],
+			Quote:    arr[1],
+			MaxPrice: decimal.NewFromFloat(999999999.99),
+			Time:     now,
+		}
+		ep.PriceTickSize, _ = decimal.NewFromString(pair.PriceTickSz)
+		ep.QtyStep, _ = decimal.NewFromString(pair.QtyStep)
+		ep.MinOrderQty, _ = decimal.NewFromString(pair.MinOrderQty)
+		ep.MaxOrderQty, _ = decimal.NewFromString(pair.MaxOrderQty)
+		ep.MinPrice = ep.PriceTickSize
+		if typ == "CM" { // 张数
+			ep.ContractSize = ctVal
+		} else { // 张数换算成标的数量
+			ep.ContractMultiplier = ctVal
+			ep.QtyStep = ep.QtyStep.Mul(ctVal)
+			ep.MinOrderQty = ep.MinOrderQty.Mul(ctVal)
+			ep.MaxOrderQty = ep.MaxOrderQty.Mul(ctVal)
+		}
+		all[ep.Symbol] = ep
+		tokxContractSymbolMap[ep.Symbol] = pair.InstId
+		tokxContractValMap[ep.Symbol] = ctVal
+	}
+
+	okxContractSymbolMapMtx.Lock() // UM/CM 共用, 合并
+	for k, v := range tokxContractSymbolMap {
+		okxContractSymbolMap[k] = v
+		okxContractValMap[k] = tokxContractValMap[k]
+	}
+	okxContractSymbolMapMtx.Unlock()
+	return all, nil
+}
+func (ok *Okx) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error) {
+	path := "/api/v5/market/tickers"
+	url := okUniEndpoint + path + "?instType=SWAP"
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	tickers := Okx24hTickers{}
+	err = easyjson.Unmarshal(resp, &tickers)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if len(tickers.Data) == 0 {
+		return nil, errors.New(ok.Name() + " resp empty")
+	}
+	allTk := make(map[string]Pub24hTicker, len(tickers.Data))
+	for _, tk := range tickers.Data {
+		sym, t := ok.toStdContractSymbol(tk.Symbol)
+		if t != typ {
+			continue
+		}
+		// 合约中vol24h为张数, volCcy24h为标的数量
+		v := Pub24hTicker{
+			Symbol:    sym,
+			LastPrice: tk.Last,
+		}
+		if typ == "CM" {
+			v.Volume = tk.Volume
+			v.BaseVolume = tk.QuoteVolume
+		} else {
+			v.Volume = tk.QuoteVolume
+			v.QuoteVolume = tk.QuoteVolume.Mul(tk.Last)
+		}
+		allTk[v.Symbol] = v
+	}
+	return allTk, nil
+}
+func (ok *Okx) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
+	url := okUniEndpoint + "/api/v5/market/ticker?instId=" + ok.getContractSymbol(symbol)
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return BestBidAsk{}, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return BestBidAsk{}, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			BidPrice string `json:"bidPx"`
+			BidQty   string `json:"bidSz"`
+			AskPrice string `json:"askPx"`
+			AskQty   string `json:"askSz"`
+			Time     string `json:"ts"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return BestBidAsk{}, errors.New(ok.Name() + " Unmarshal err! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return BestBidAsk{}, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return BestBidAsk{}, errors.New(ok.Name() + " resp empty")
+	}
+	bbo := &(ret.Data[0])
+	v := BestBidAsk{Symbol: symbol}
+	v.Time, _ = strconv.ParseInt(bbo.Time, 10, 64)
+	v.BidPrice, _ = decimal.NewFromString(bbo.BidPrice)
+	v.AskPrice, _ = decimal.NewFromString(bbo.AskPrice)
+	bidSz, _ := decimal.NewFromString(bbo.BidQty)
+	askSz, _ := decimal.NewFromString(bbo.AskQty)
+	v.BidQty = ok.FuturesSizeToQty(typ, symbol, bidSz)
+	v.AskQty = ok.FuturesSizeToQty(typ, symbol, askSz)
+	return v, nil
+}
+func (ok *Okx) FuturesGetAllFundingRate(typ string) (map[string]FundingRate, error) {
+	url := okUniEndpoint + "/api/v5/public/funding-rate?instId=ANY"
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	frs := OkxFundingRates{}
+	if err = easyjson.Unmarshal(resp, &frs); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if frs.Code != "0" {
+		return nil, ok.apiError(retCode, frs.Code, frs.Msg)
+	}
+	all := make(map[string]FundingRate, len(frs.Data))
+	now := time.Now().Unix()
+	for _, fr := range frs.Data {
+		sym, t := ok.toStdContractSymbol(fr.Symbol)
+		if t != typ {
+			continue
+		}
+		nextTime, _ := strconv.ParseInt(fr.Time, 10, 64)
+		v := FundingRate{
+			Symbol:   sym,
+			Val:      fr.Fr,
+			UTime:    now,
+			NextTime: nextTime / 1000,
+		}
+		all[v.Symbol] = v
+	}
+	return all, nil
+}
+
+// startTime/endTime msec, 一次最多400条
+func (ok *Okx) FuturesGetFundingRateHistory(typ, symbol string,
+	startTime, endTime int64) ([]FundingRateHistory, error) {
+	// before: 晚于该时间的数据, after: 早于该时间的数据
+	query := "?instId=" + ok.getContractSymbol(symbol) + "&limit=400"
+	if startTime > 0 {
+		query += "&before=" + strconv.FormatInt(startTime-1, 10)
+	}
+	if endTime > 0 {
+		query += "&after=" + strconv.FormatInt(endTime+1, 10)
+	}
+	url := okUniEndpoint + "/api/v5/public/funding-rate-history" + query
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			FundingRate decimal.Decimal `json:"realizedRate"` // 实际结算的资金费率
+			Time        string          `json:"fundingTime"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	frh := make([]FundingRateHistory, 0, len(ret.Data))
+	for i := len(ret.Data) - 1; i >= 0; i-- { // okx 是倒序
+		v := ret.Data[i]
+		t, _ := strconv.ParseInt(v.Time, 10, 64)
+		frh = append(frh, FundingRateHistory{
+			FundingRate: v.FundingRate,
+			Time:        t,
+		})
+	}
+	return frh, nil
+}
+func (ok *Okx) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRateMarkPrice, error) {
+	symbolS := ok.getContractSymbol(symbol)
+	url := okUniEndpoint + "/api/v5/public/funding-rate?instId=" + symbolS
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return FundingRateMarkPrice{}, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return FundingRateMarkPrice{}, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	frs := OkxFundingRates{}
+	if err = easyjson.Unmarshal(resp, &frs); err != nil {
+		return FundingRateMarkPrice{}, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if frs.Code != "0" {
+		return FundingRateMarkPrice{}, ok.apiError(retCode, frs.Code, frs.Msg)
+	}
+	if len(frs.Data) == 0 {
+		return FundingRateMarkPrice{}, errors.New(ok.Name() + " resp empty")
+	}
+	v := FundingRateMarkPrice{FundingRate: frs.Data[0].Fr}
+	v.NextTime, _ = strconv.ParseInt(frs.Data[0].Time, 10, 64)
+
+	url = okUniEndpoint + "/api/v5/public/mark-price?instType=SWAP&instId=" + symbolS
+	retCode, resp, err = ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return FundingRateMarkPrice{}, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return FundingRateMarkPrice{}, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			MarkPrice decimal.Decimal `json:"markPx"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return FundingRateMarkPrice{}, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return FundingRateMarkPrice{}, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return FundingRateMarkPrice{}, errors.New(ok.Name() + " resp empty")
+	}
+	v.MarkPrice = ret.Data[0].MarkPrice
+	return v, nil
+}
+
+// 统一账户, UM/CM 返回相同的资产
+func (ok *Okx) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
+	path := "/api/v5/account/balance"
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Detail []struct {
+				Symbol   string `json:"ccy"`
+				Total    string `json:"eq"`
+				AvailEq  string `json:"availEq"` // 可用保证金, 现货模式下为空
+				AvailBal string `json:"availBal"`
+			} `json:"details,omitempty"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+
+	assetsMap := make(map[string]*FuturesAsset)
+	for _, dt := range ret.Data {
+		for _, asset := range dt.Detail {
+			fa := &FuturesAsset{Symbol: asset.Symbol}
+			fa.Total, _ = decimal.NewFromString(asset.Total)
+			fa.MaxWithdrawAmount, _ = decimal.NewFromString(asset.AvailBal)
+			fa.Avail = fa.MaxWithdrawAmount
+			if asset.AvailEq != "" {
+				fa.Avail, _ = decimal.NewFromString(asset.AvailEq)
+			}
+			if fa.Total.IsZero() && fa.Avail.IsZero() {
+				continue
+			}
+			assetsMap[fa.Symbol] = fa
+		}
+	}
+	return assetsMap, nil
+}
+
+// 参数同SpotGetKLine, Volume为标的数量
+func (ok *Okx) FuturesGetKLine(typ, symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	return ok.getKLine(ok.getContractSymbol(symbol), interval, startTime, endTime, limit, 6)
+}
+func (ok *Okx) futuresGetPositions(typ string) ([]*FuturesPosition, error) {
+	path := "/api/v5/account/positions?instType=SWAP"
+	headers := ok.buildHeaders("GET", path, "")
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol        string `json:"instId"`
+			PosSide       string `json:"posSide"` // net/long/short
+			Pos           string `json:"pos"`     // 张数, 单仓模式下符号代表多空方向
+			EntryPrice    string `json:"avgPx"`
+			LiqPrice      string `json:"liqPx"`
+			Leverage      string `json:"lever"`
+			UnrealisedPnl string `json:"upl"`
+			UTime         string `json:"uTime"` // msec
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	all := make([]*FuturesPosition, 0, len(ret.Data))
+	for _, v := range ret.Data {
+		sym, t := ok.toStdContractSymbol(v.Symbol)
+		if t != typ {
+			continue
+		}
+		pos, _ := decimal.NewFromString(v.Pos)
+		cp := &FuturesPosition{
+			Symbol: sym,
+			Side:   ok.toStdSide(v.PosSide),
+		}
+		if v.PosSide == "net" { // 单仓模式
+			cp.Side = "SELL"
+			if pos.IsPositive() {
+				cp.Side = "BUY"
+			}
+		} else {
+			cp.Mode = 1
+		}
+		cp.PositionQty = ok.FuturesSizeToQty(typ, sym, pos.Abs())
+		cp.EntryPrice, _ = decimal.NewFromString(v.EntryPrice)
+		cp.LiqPrice, _ = decimal.NewFromString(v.LiqPrice)
+		cp.Leverage, _ = decimal.NewFromString(v.Leverage)
+		cp.UnRealizedProfit, _ = decimal.NewFromString(v.UnrealisedPnl)
+		cp.UTime, _ = strconv.ParseInt(v.UTime, 10, 64)
+		all = append(all, cp)
+	}
+	return all, nil
+}
+func (ok *Okx) FuturesGetAllPositions(typ string) (map[string]*FuturesPositions, error) {
+	all, err := ok.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPositions)
+	for _, v := range all {
+		fp := positionM[v.Symbol]
+		if fp == nil {
+			fp = &FuturesPositions{}
+			positionM[v.Symbol] = fp
+		}
+		fp.Val(v)
+	}
+	return positionM, nil
+}
+func (ok *Okx) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosition, error) {
+	all, err := ok.futuresGetPositions(typ)
+	if err != nil {
+		return nil, err
+	}
+	positionM := make(map[string]*FuturesPosition)
+	for _, v := range all {
+		if v.Mode != 0 {
+			continue // 只支持单仓模式
+		}
+		positionM[v.Symbol] = v
+	}
+	return positionM, nil
+}
+func (ok *Okx) futuresOrderPayload(typ, symbol, clientId string,
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode, reduceOnly int) string {
+	payload := `{"instId":"` + ok.getContractSymbol(symbol) + `"` +
+		`,"sz":"` + ok.FuturesQtyToSize(typ, symbol, qty).String() + `"`
+	if clientId != "" {
+		payload += `,"clOrdId":"` + clientId + `"`
+	}
+	if orderType != "MARKET" {
+		payload += `,"px":"` + price.String() + `"`
+	}
+	if posSide := ok.fromStdPositionMode(positionMode); posSide != "" {
+		payload += `,"posSide":"` + posSide + `"`
+	}
+	if reduceOnly == 1 {
+		payload += `,"reduceOnly":true`
+	}
+	payload += "" +
+		`,"side":"` + ok.fromStdSide(side) + `"` +
+		`,"ordType":"` + ok.fromStdFuturesOrderType(orderType, timeInForce) + `"` +
+		`,"tdMode":"` + ok.fromStdTradeMode(tradeMode) + `"` +
+		`}`
+	return payload
+}
+
+// timeInForce=GTX 为只做maker
+func (ok *Okx) FuturesPlaceOrder(typ, symbol, clientId string, /*BTCUSDT*/
+	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	payload := ok.futuresOrderPayload(typ, symbol, clientId, price, qty,
+		side, orderType, timeInForce, positionMode, tradeMode, reduceOnly)
+	path := "/api/v5/trade/order"
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return "", newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			OrderId string `json:"ordId"`
+			SCode   string `json:"sCode,omitempty"`
+			SMsg    string `json:"sMsg,omitempty"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		if len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return "", ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return "", errors.New(ok.Name() + " fail! response emtpy")
+	}
+	return ret.Data[0].OrderId, nil
+}
+func (ok *Okx) toStdFuturesOrder(order *OkxFuturesOrder) *FuturesOrder {
+	sym, typ := ok.toStdContractSymbol(order.Symbol)
+	fo := &FuturesOrder{
+		Symbol:   sym,
+		OrderId:  order.OrderId,
+		ClientId: order.ClientId,
+		Status:   ok.toStdOrderStatus(order.Status),
+		Type:     ok.toStdOrderType(order.Type),
+		Side:     ok.toStdSide(order.Side),
+		FeeAsset: order.FeeCoin,
+	}
+	size, _ := decimal.NewFromString(order.Size)
+	filledSize, _ := decimal.NewFromString(order.ExecutedQty)
+	avgPrice, _ := decimal.NewFromString(order.AvgPrice)
+	fo.Price, _ = decimal.NewFromString(order.Price)
+	fo.Qty = ok.FuturesSizeToQty(typ, sym, size)
+	fo.FilledQty = ok.FuturesSizeToQty(typ, sym, filledSize)
+	if typ == "CM" { // 成交金额换算成标的数量
+		fo.AvgPrice = avgPrice
+		if avgPrice.IsPositive() {
+			fo.FilledAmt = filledSize.Mul(ok.getContractVal(sym)).Div(avgPrice)
+		}
+	} else {
+		fo.FilledAmt = fo.FilledQty.Mul(avgPrice)
+	}
+	fo.FeeQty, _ = decimal.NewFromString(order.FeeQty)
+	fo.CTime, _ = strconv.ParseInt(order.Time, 10, 64)
+	fo.UTime, _ = strconv.ParseInt(order.UTime, 10, 64)
+	return fo
+}
+func (ok *Okx) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOrder, error) {
+	symbolS := ok.getContractSymbol(symbol)
+	path := "/api/v5/trade/order?instId=" + symbolS + "&ordId=" + orderId
+	if orderId == "" && cltId != "" {
+		path = "/api/v5/trade/order?instId=" + symbolS + "&clOrdId=" + cltId
+	}
+	headers := ok.buildHeaders("GET", path, "")
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string            `json:"code,omitempty"`
+		Msg  string            `json:"msg,omitempty"`
+		Data []OkxFuturesOrder `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return nil, errors.New(ok.Name() + " resp empty")
+	}
+	return ok.toStdFuturesOrder(&ret.Data[0]), nil
+}
+func (ok *Okx) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error) {
+	path := "/api/v5/trade/orders-pending?instType=SWAP"
+	if symbol != "" {
+		path += "&instId=" + ok.getContractSymbol(symbol)
+	}
+	headers := ok.buildHeaders("GET", path, "")
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string            `json:"code,omitempty"`
+		Msg  string            `json:"msg,omitempty"`
+		Data []OkxFuturesOrder `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	orders := make([]*FuturesOrder, 0, len(ret.Data))
+	for i := range ret.Data {
+		if _, t := ok.toStdContractSymbol(ret.Data[i].Symbol); t != typ {
+			continue
+		}
+		orders = append(orders, ok.toStdFuturesOrder(&ret.Data[i]))
+	}
+	return orders, nil
+}
+func (ok *Okx) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
+	symbolS := ok.getContractSymbol(symbol)
+	path := "/api/v5/trade/cancel-order"
+	payload := `{"instId":"` + symbolS + `","ordId":"` + orderId + `"}`
+	if orderId == "" && cltId != "" {
+		payload = `{"instId":"` + symbolS + `","clOrdId":"` + cltId + `"}`
+	}
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			OrderId string `json:"ordId"`
+			SCode   string `json:"sCode"`
+			SMsg    string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		if ret.Code != "0" && len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if dt := ret.Data[0]; dt.SCode != "0" {
+		return ok.apiError(retCode, dt.SCode, dt.SMsg)
+	}
+	return nil
+}
+func (ok *Okx) FuturesPlaceOrders(typ string, orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	path := "/api/v5/trade/batch-orders"
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			o := orders[i]
+			results[i].ClientId = o.ClientId
+			if i > start {
+				payload += ","
+			}
+			payload += ok.futuresOrderPayload(typ, o.Symbol, o.ClientId, o.Price, o.Qty,
+				o.Side, o.Type, o.TimeInForce, o.PositionMode, o.TradeMode, o.ReduceOnly)
+		}
+		payload += "]"
+		headers := ok.buildHeaders("POST", path, payload)
+		retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+		ok.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (ok *Okx) FuturesCancelOrders(typ string, orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	path := "/api/v5/trade/cancel-batch-orders"
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			results[i].OrderId = orders[i].OrderId
+			results[i].ClientId = orders[i].ClientId
+			if i > start {
+				payload += ","
+			}
+			symbolS := ok.getContractSymbol(orders[i].Symbol)
+			if orders[i].OrderId == "" && orders[i].ClientId != "" {
+				payload += `{"instId":"` + symbolS + `","clOrdId":"` + orders[i].ClientId + `"}`
+			} else {
+				payload += `{"instId":"` + symbolS + `","ordId":"` + orders[i].OrderId + `"}`
+			}
+		}
+		payload += "]"
+		headers := ok.buildHeaders("POST", path, payload)
+		retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+		ok.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (ok *Okx) FuturesCancelAllOrders(typ, symbol string) error {
+	if symbol == "" {
+		return errors.New(ok.Name() + " symbol empty!")
+	}
+	// 没有撤销全部的接口, 查询挂单后批量撤销
+	// 挂单每次最多查到100个, 循环直到没有未尝试过的挂单
+	var errs []error
+	tried := make(map[string]bool) // 撤销过的不再重试, 避免撤销失败时死循环
+	for {
+		orders, err := ok.FuturesGetOpenOrders(typ, symbol)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		args := make([]CancelOrderArg, 0, len(orders))
+		for _, o := range orders {
+			if !tried[o.OrderId] {
+				tried[o.OrderId] = true
+				args = append(args, CancelOrderArg{Symbol: symbol, OrderId: o.OrderId})
+			}
+		}
+		if len(args) == 0 {
+			break
+		}
+		results, _ := ok.FuturesCancelOrders(typ, args)
+		for i := range results {
+			if results[i].Err != nil {
+				errs = append(errs, results[i].Err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+func (ok *Okx) FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*FuturesOrder, error) {
+	symbolS := ok.getContractSymbol(symbol)
+	path := "/api/v5/trade/amend-order"
+	payload := `{"instId":"` + symbolS + `","ordId":"` + orderId + `"`
+	if orderId == "" && cltId != "" {
+		payload = `{"instId":"` + symbolS + `","clOrdId":"` + cltId + `"`
+	}
+	payload += `,"newSz":"` + ok.FuturesQtyToSize(typ, symbol, qty).String() + `"` +
+		`,"newPx":"` + price.String() + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			OrderId  string `json:"ordId"`
+			ClientId string `json:"clOrdId"`
+			SCode    string `json:"sCode"`
+			SMsg     string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		if ret.Code != "0" && len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+
+	dt := ret.Data[0]
+	if dt.SCode != "0" {
+		return nil, ok.apiError(retCode, dt.SCode, dt.SMsg)
+	}
+	return &FuturesOrder{ // 改单为异步, 以订单推送为准
+		Symbol:   symbol,
+		OrderId:  dt.OrderId,
+		ClientId: dt.ClientId,
+		Price:    price,
+		Qty:      qty,
+		Type:     "LIMIT",
+		Side:     side,
+	}, nil
+}
+
+// 同SpotPlaceConditionalOrder, 以全仓模式下单
+func (ok *Okx) FuturesPlaceConditionalOrder(typ, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy, positionMode string,
+	reduceOnly int) (string, error) {
+	isStop, isLimit, valid := parseConditionalOrderType(orderType)
+	if !valid {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	pxType := ok.fromStdTriggerBy(triggerBy)
+	if pxType == "" {
+		return "", errors.New(ok.Name() + " not support trigger by " + triggerBy)
+	}
+	ordPx := "-1" // 市价
+	if isLimit {
+		ordPx = price.String()
+	}
+	prefix := "tp"
+	if isStop {
+		prefix = "sl"
+	}
+	payload := `{"instId":"` + ok.getContractSymbol(symbol) + `"` +
+		`,"tdMode":"cross"` +
+		`,"side":"` + ok.fromStdSide(side) + `"` +
+		`,"ordType":"conditional"` +
+		`,"sz":"` + ok.FuturesQtyToSize(typ, symbol, qty).String() + `"` +
+		`,"` + prefix + `TriggerPx":"` + triggerPrice.String() + `"` +
+		`,"` + prefix + `OrdPx":"` + ordPx + `"` +
+		`,"` + prefix + `TriggerPxType":"` + pxType + `"`
+	if posSide := ok.fromStdPositionMode(positionMode); posSide != "" {
+		payload += `,"posSide":"` + posSide + `"`
+	}
+	if reduceOnly == 1 {
+		payload += `,"reduceOnly":true`
+	}
+	if cltId != "" {
+		payload += `,"algoClOrdId":"` + cltId + `"`
+	}
+	payload += `}`
+	path := "/api/v5/trade/order-algo"
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return "", newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			AlgoId string `json:"algoId"`
+			SCode  string `json:"sCode,omitempty"`
+			SMsg   string `json:"sMsg,omitempty"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		if len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return "", ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return "", errors.New(ok.Name() + " fail! response emtpy")
+	}
+	return ret.Data[0].AlgoId, nil
+}
+func (ok *Okx) FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error) {
+	path := "/api/v5/trade/orders-algo-pending?ordType=conditional&instType=SWAP"
+	if symbol != "" {
+		path += "&instId=" + ok.getContractSymbol(symbol)
+	}
+	headers := ok.buildHeaders("GET", path, "")
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol        string          `json:"instId"`
+			AlgoId        string          `json:"algoId"`
+			ClientId      string          `json:"algoClOrdId"`
+			Side          string          `json:"side"`
+			Size          decimal.Decimal `json:"sz"`
+			SlTriggerPx   string          `json:"slTriggerPx"`
+			SlOrdPx       string          `json:"slOrdPx"`
+			SlTriggerType string          `json:"slTriggerPxType"`
+			TpTriggerPx   string          `json:"tpTriggerPx"`
+			TpOrdPx       string          `json:"tpOrdPx"`
+			TpTriggerType string          `json:"tpTriggerPxType"`
+			Status        string          `json:"state"`
+			Time          string          `json:"cTime"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	orders := make([]*ConditionalOrder, 0, len(ret.Data))
+	for _, order := range ret.Data {
+		sym, t := ok.toStdContractSymbol(order.Symbol)
+		if t != typ {
+			continue
+		}
+		ctime, _ := strconv.ParseInt(order.Time, 10, 64)
+		co := &ConditionalOrder{
+			Symbol:   sym,
+			OrderId:  order.AlgoId,
+			ClientId: order.ClientId,
+			Side:     ok.toStdSide(order.Side),
+			Qty:      ok.FuturesSizeToQty(typ, sym, order.Size),
+			Status:   ok.toStdAlgoOrderStatus(order.Status),
+			CTime:    ctime,
+		}
+		triggerPx, ordPx, triggerType := order.TpTriggerPx, order.TpOrdPx, order.TpTriggerType
+		co.Type = "TAKE_PROFIT"
+		if order.SlTriggerPx != "" {
+			triggerPx, ordPx, triggerType = order.SlTriggerPx, order.SlOrdPx, order.SlTriggerType
+			co.Type = "STOP"
+		}
+		co.TriggerPrice, _ = decimal.NewFromString(triggerPx)
+		co.TriggerBy = ok.toStdTriggerBy(triggerType)
+		if ordPx == "-1" {
+			co.Type += "_MARKET"
+		} else {
+			co.Type += "_LIMIT"
+			co.Price, _ = decimal.NewFromString(ordPx)
+		}
+		orders = append(orders, co)
+	}
+	return orders, nil
+}
+func (ok *Okx) FuturesCancelConditionalOrder(typ, symbol, orderId string) error {
+	path := "/api/v5/trade/cancel-algos"
+	payload := `[{"instId":"` + ok.getContractSymbol(symbol) + `","algoId":"` + orderId + `"}]`
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			AlgoId string `json:"algoId"`
+			SCode  string `json:"sCode"`
+			SMsg   string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		if ret.Code != "0" && len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if dt := ret.Data[0]; dt.SCode != "0" {
+		return ok.apiError(retCode, dt.SCode, dt.SMsg)
+	}
+	return nil
+}
+
+// 整个账户生效, 有持仓或挂单时不能切换
+func (ok *Okx) FuturesSwitchPositionMode(typ string, mode int) error {
+	m := ""
+	if mode == 1 {
+		m = "long_short_mode"
+	} else if mode == 0 {
+		m = "net_mode"
+	}
+	if m == "" {
+		return errors.New("params error")
+	}
+	path := "/api/v5/account/set-position-mode"
+	payload := `{"posMode":"` + m + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
+
+// okx 全仓/逐仓在下单时通过tdMode指定, 这里只设置对应保证金模式下的杠杆
+func (ok *Okx) FuturesSwitchTradeMode(typ, symbol string, mode, leverage int) error {
+	if leverage < 1 || leverage > 125 {
+		return errors.New("leverage error 1~125")
+	}
+	path := "/api/v5/account/set-leverage"
+	payload := `{"instId":"` + ok.getContractSymbol(symbol) + `"` +
+		`,"lever":"` + strconv.Itoa(leverage) + `"` +
+		`,"mgnMode":"` + ok.fromStdTradeMode(mode) + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
+
+// okx 按数量分层, 只返回QtyCap/QtyFloor(UM为标的数量, CM为张数), 没有速算数
+func (ok *Okx) FuturesMaintMargin(typ, symbol string) ([]*FuturesLeverageBracket, error) {
+	instFamily := strings.TrimSuffix(ok.getContractSymbol(symbol), "-SWAP")
+	url := okUniEndpoint + "/api/v5/public/position-tiers?instType=SWAP&tdMode=cross&instFamily=" +
+		instFamily
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Tier     string          `json:"tier"`
+			MaxLever decimal.Decimal `json:"maxLever"`
+			MinSz    decimal.Decimal `json:"minSz"`
+			MaxSz    decimal.Decimal `json:"maxSz"`
+			Mmr      decimal.Decimal `json:"mmr"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return nil, errors.New(ok.Name() + " resp empty")
+	}
+	lbs := make([]*FuturesLeverageBracket, 0, len(ret.Data))
+	for _, v := range ret.Data {
+		tier, _ := strconv.ParseInt(v.Tier, 10, 64)
+		lbs = append(lbs, &FuturesLeverageBracket{
+			Bracket:          tier,
+			InitialLeverage:  v.MaxLever.IntPart(),
+			QtyCap:           ok.FuturesSizeToQty(typ, symbol, v.MaxSz),
+			QtyFloor:         ok.FuturesSizeToQty(typ, symbol, v.MinSz),
+			MaintMarginRatio: v.Mmr,
+		})
+	}
+	return lbs, nil
+}
+
+// 只支持FUNDING_FEE, startTime/endTime msec, 一次最多100条
+func (ok *Okx) FuturesGetProfitLossHistory(typ, symbol, plType string,
+	startTime, endTime int64) ([]FuturesProfitLossHistory, error) {
+	if plType != "FUNDING_FEE" {
+		return nil, errors.New(ok.Name() + " not support " + plType)
+	}
+	path := "/api/v5/account/bills-archive?instType=SWAP&type=8&limit=100" // 8:资金费
+	if symbol != "" {
+		path += "&instId=" + ok.getContractSymbol(symbol)
+	}
+	if startTime > 0 {
+		path += "&begin=" + strconv.FormatInt(startTime, 10)
+	}
+	if endTime > 0 {
+		path += "&end=" + strconv.FormatInt(endTime, 10)
+	}
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(okUniEndpoint+path, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol string          `json:"instId"`
+			Asset  string          `json:"ccy"`
+			Income decimal.Decimal `json:"balChg"`
+			Time   string          `json:"ts"`
+		} `json:"data,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	plh := make([]FuturesProfitLossHistory, 0, len(ret.Data))
+	for _, v := range ret.Data {
+		if _, t := ok.toStdContractSymbol(v.Symbol); t != typ {
+			continue
+		}
+		ts, _ := strconv.ParseInt(v.Time, 10, 64)
+		plh = append(plh, FuturesProfitLossHistory{
+			Income: v.Income,
+			Asset:  v.Asset,
+			Typ:    plType,
+			Time:   ts,
+		})
+	}
+	return plh, nil
+}
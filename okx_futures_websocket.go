@@ -0,0 +1,674 @@
+package cex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mailru/easyjson"
+	"github.com/shopspring/decimal"
+
+	"github.com/shaovie/gutils/gutils"
+	"github.com/shaovie/gutils/ilog"
+)
+
+// UM/CM 共用一个连接, 推送的数量已由张数换算成qty
+func (ok *Okx) FuturesWsPublicOpen(typ string) error {
+	url := ok.wsUrl("wss://ws.okx.com:8443/ws/v5/public")
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	ok.wsContractPubCon, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(ok.Name() + " futures.ws.public con failed! " + err.Error())
+	}
+	ok.wsContractBusiness.open("futures.ws.business")
+
+	ok.wsContractPubChannelClosedMtx.Lock()
+	ok.wsContractPubChannelClosed = false
+	ok.wsContractPubChannelClosedMtx.Unlock()
+	return nil
+}
+func (ok *Okx) FuturesWsPublicSubscribe(channels []string) {
+	ok.futuresWsPublicSubscribe("subscribe", channels)
+}
+func (ok *Okx) FuturesWsPublicUnsubscribe(channels []string) {
+	ok.futuresWsPublicSubscribe("unsubscribe", channels)
+}
+func (ok *Okx) futuresWsPublicSubscribe(op string, channels []string) {
+	if len(channels) == 0 {
+		return
+	}
+	type Arg struct {
+		Channel string `json:"channel"`
+		InstId  string `json:"instId"`
+	}
+	req := struct {
+		Id   string `json:"id"`
+		Op   string `json:"op"`
+		Args []*Arg `json:"args"`
+	}{Id: gutils.RandomStr(8), Op: op}
+	req.Args = make([]*Arg, 0, 2)
+	var klineArgs []map[string]string
+	for _, c := range channels {
+		arr := strings.Split(c, "@")
+		if len(arr) < 2 || len(arr[1]) == 0 {
+			continue
+		}
+		channel := ""
+		if arr[0] == "orderbook5" {
+			channel = "books5"
+		} else if arr[0] == "bbo" {
+			channel = "bbo-tbt"
+		} else if arr[0] == "ticker" {
+			channel = "tickers"
+		} else if arr[0] == "kline" {
+			klineArgs = append(klineArgs, ok.wsKLineArgs(arr[1], ok.getContractSymbol)...)
+			continue
+		} else {
+			continue
+		}
+		symbolArr := strings.SplitSeq(arr[1], ",")
+		for sym := range symbolArr {
+			if symbolS := ok.getContractSymbol(sym); symbolS != "" {
+				arg := Arg{Channel: channel, InstId: symbolS}
+				req.Args = append(req.Args, &arg)
+			}
+		}
+	}
+	if len(req.Args) > 0 {
+		subData, _ := json.Marshal(&req)
+		ok.wsContractPubConMtx.Lock()
+		ok.wsContractPubCon.WriteMessage(websocket.TextMessage, subData)
+		ok.wsContractPubConMtx.Unlock()
+	}
+	if len(klineArgs) > 0 {
+		subData, _ := json.Marshal(map[string]any{"id": gutils.RandomStr(8), "op": op, "args": klineArgs})
+		if err := ok.wsContractBusiness.write(ok, subData, op == "subscribe"); err != nil {
+			ilog.Error("%s", ok.Name()+" futures.ws.business "+op+": "+err.Error())
+		}
+	}
+}
+func (ok *Okx) FuturesWsPublicTickerPoolPut(v any) {
+	wsPublicTickerPool.Put(v)
+}
+func (ok *Okx) FuturesWsPublicOrderBook5PoolPut(v any) {
+	wsPublicOrderBook5Pool.Put(v)
+}
+func (ok *Okx) FuturesWsPublicBBOPoolPut(v any) {
+	wsPublicBBOPool.Put(v)
+}
+func (ok *Okx) FuturesWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
+func (ok *Okx) FuturesWsPublicLoop(ch chan<- any) {
+	defer ok.FuturesWsPublicClose()
+	defer close(ch)
+
+	pingInterval := 28 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	ok.wsContractPubCon.SetReadDeadline(time.Now().Add(pongWait))
+	ok.wsContractPubCon.SetPongHandler(func(string) error {
+		ok.wsContractPubCon.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		var pingMsg = []byte("ping")
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if ok.FuturesWsPublicIsClosed() {
+					break
+				}
+				ok.wsContractPubConMtx.Lock()
+				ok.wsContractPubCon.WriteMessage(websocket.TextMessage, pingMsg)
+				ok.wsContractPubConMtx.Unlock()
+			}
+		}
+	}(pingExit)
+	// kline推送, 退出时先关闭business并等它结束, 再close(ch)
+	businessExit := make(chan struct{})
+	businessDone := make(chan struct{})
+	defer func() {
+		close(businessExit)
+		ok.wsContractBusiness.close()
+		<-businessDone
+	}()
+	go func() {
+		defer close(businessDone)
+		ok.wsContractBusiness.loop(ok, businessExit, ok.FuturesWsPublicClose, func(msg *OkxWsPubMsg) {
+			if strings.HasPrefix(msg.Arg.Channel, "candle") {
+				if sym, _ := ok.toStdContractSymbol(msg.Arg.Symbol); sym != "" {
+					ok.wsHandleKLine(sym, msg.Arg.Channel, msg.Data, 6, ch)
+				}
+			}
+		})
+	}()
+
+	for {
+		_, recv, err := ok.wsContractPubCon.ReadMessage()
+		if err != nil {
+			if !ok.FuturesWsPublicIsClosed() {
+				ilog.Warning("%s", ok.Name()+" futures.ws.public read: "+err.Error())
+			}
+			break
+		}
+		if len(recv) == 4 && bytes.Equal(recv, []byte("pong")) {
+			ok.wsContractPubCon.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		msg := okxWsPubMsgPool.Get().(*OkxWsPubMsg)
+		msg.reset()
+		if err = easyjson.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", ok.Name()+" futures.ws.public recv invalid msg:"+string(recv))
+			goto END
+		}
+		if len(msg.Event) == 0 {
+			if msg.Arg.Channel == "books5" {
+				ok.futuresWsHandleOrderBook5(msg.Arg.Symbol, msg.Data, ch)
+			} else if msg.Arg.Channel == "bbo-tbt" {
+				ok.futuresWsHandleBBO(msg.Arg.Symbol, msg.Data, ch)
+			} else if msg.Arg.Channel == "tickers" {
+				ok.futuresWsHandle24hTickers(msg.Data, ch)
+			}
+		} else if msg.Event == "error" {
+			ilog.Error("%s", ok.Name()+" futures.ws.public recv error event: "+string(recv))
+		} else if msg.Event == "subscribe" {
+		} else if msg.Event == "unsubscribe" {
+		} else if msg.Event == "notice" {
+		} else if msg.Event == "channel-conn-count" {
+		} else if msg.Event == "channel-conn-count-error" {
+			ilog.Error("%s", ok.Name()+" futures.ws.public recv err: "+string(recv))
+			goto END
+		} else {
+			ilog.Error("%s", ok.Name()+" futures.ws.public recv unknown msg: "+string(recv))
+		}
+	END:
+		okxWsPubMsgPool.Put(msg)
+	}
+}
+func (ok *Okx) FuturesWsPublicIsClosed() bool {
+	ok.wsContractPubChannelClosedMtx.RLock()
+	defer ok.wsContractPubChannelClosedMtx.RUnlock()
+	return ok.wsContractPubChannelClosed
+}
+func (ok *Okx) FuturesWsPublicClose() {
+	ok.wsContractPubChannelClosedMtx.Lock()
+	defer ok.wsContractPubChannelClosedMtx.Unlock()
+	if ok.wsContractPubChannelClosed {
+		return
+	}
+	ok.wsContractPubChannelClosed = true
+	ok.wsContractPubCon.Close()
+	ok.wsContractBusiness.close()
+}
+func (ok *Okx) futuresWsHandleOrderBook5(symbolS string, data json.RawMessage, ch chan<- any) {
+	sym, typ := ok.toStdContractSymbol(symbolS)
+	if sym == "" {
+		return
+	}
+	var orderBookList []OkxOrderBook
+	if err := json.Unmarshal(data, &orderBookList); err == nil && len(orderBookList) > 0 {
+		for _, depth := range orderBookList {
+			if len(depth.Bids) != len(depth.Asks) {
+				ilog.Error("%s", ok.Name()+" futures.ws.public "+symbolS+" exception")
+				continue
+			}
+			obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
+			obd.Symbol = sym
+			obd.Level = len(depth.Bids)
+			obd.Time, _ = strconv.ParseInt(depth.Time, 10, 64)
+			obd.Bids = obd.Bids[:0]
+			obd.Asks = obd.Asks[:0]
+			for i, v := range depth.Bids {
+				bTk := Ticker{Price: v[0], Quantity: ok.FuturesSizeToQty(typ, sym, v[1])}
+				obd.Bids = append(obd.Bids, bTk)
+
+				v2 := depth.Asks[i]
+				aTk := Ticker{Price: v2[0], Quantity: ok.FuturesSizeToQty(typ, sym, v2[1])}
+				obd.Asks = append(obd.Asks, aTk)
+			}
+			ch <- obd
+		}
+	}
+}
+func (ok *Okx) futuresWsHandleBBO(symbolS string, data json.RawMessage, ch chan<- any) {
+	sym, typ := ok.toStdContractSymbol(symbolS)
+	if sym == "" {
+		return
+	}
+	var orderBookList []OkxOrderBook
+	if err := json.Unmarshal(data, &orderBookList); err == nil && len(orderBookList) == 1 {
+		depth := orderBookList[0]
+		if len(depth.Bids) != 1 || len(depth.Asks) != 1 {
+			ilog.Error("%s", ok.Name()+" futures.ws.public "+symbolS+" bbo exception")
+			return
+		}
+		obd := wsPublicBBOPool.Get().(*BestBidAsk)
+		obd.Symbol = sym
+		obd.Time, _ = strconv.ParseInt(depth.Time, 10, 64)
+		obd.BidPrice = depth.Bids[0][0]
+		obd.BidQty = ok.FuturesSizeToQty(typ, sym, depth.Bids[0][1])
+		obd.AskPrice = depth.Asks[0][0]
+		obd.AskQty = ok.FuturesSizeToQty(typ, sym, depth.Asks[0][1])
+		ch <- obd
+	}
+}
+func (ok *Okx) futuresWsHandle24hTickers(data json.RawMessage, ch chan<- any) {
+	tickers := ok.wsContractPubTickerPool.Get().([]Okx24hTicker)
+	defer ok.wsContractPubTickerPool.Put(tickers)
+	if err := json.Unmarshal(data, &tickers); err == nil {
+		for _, tk := range tickers {
+			sym, typ := ok.toStdContractSymbol(tk.Symbol)
+			if sym == "" {
+				continue
+			}
+			t := wsPublicTickerPool.Get().(*Pub24hTicker)
+			t.Symbol = sym
+			t.LastPrice = tk.Last
+			if typ == "CM" {
+				t.Volume = tk.Volume
+				t.BaseVolume = tk.QuoteVolume
+				t.QuoteVolume = decimal.Zero
+			} else {
+				t.Volume = tk.QuoteVolume
+				t.BaseVolume = decimal.Zero
+				t.QuoteVolume = tk.QuoteVolume.Mul(tk.Last)
+			}
+			ch <- t
+		}
+	}
+}
+
+// priv
+func (ok *Okx) FuturesWsPrivateSupported(typ string) bool {
+	return ok.FuturesSupported(typ)
+}
+func (ok *Okx) FuturesWsPrivateOpen(typ string) error {
+	url := ok.wsUrl("wss://ws.okx.com:8443/ws/v5/private")
+	var err error
+	dialer := websocket.Dialer{
+		EnableCompression: true, // 启用压缩扩展
+		HandshakeTimeout:  2 * time.Second,
+	}
+	ok.wsContractPrivCon, _, err = dialer.Dial(url, nil)
+	if err != nil {
+		return errors.New(ok.Name() + " connect failed! " + err.Error())
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	loginData := fmt.Sprintf("{"+
+		"\"op\": \"login\",\"args\":[{"+
+		"\"apiKey\":\"%s\","+
+		"\"passphrase\":\"%s\","+
+		"\"timestamp\":\"%s\","+
+		"\"sign\":\"%s\"}]}",
+		ok.apikey, ok.passwd, ts, ok.sign(ts+"GET"+"/users/self/verify"))
+	if err := ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, []byte(loginData)); err != nil {
+		ok.wsContractPrivCon.Close()
+		return errors.New(ok.Name() + " futures.ws.priv send login err:" + err.Error())
+	}
+	_, msg, err := ok.wsContractPrivCon.ReadMessage()
+	if err != nil {
+		ok.wsContractPrivCon.Close()
+		return errors.New(ok.Name() + " futures.ws.priv recv login resp err:" + err.Error())
+	}
+
+	recv := struct {
+		Event string `json:"event,omitempty"`
+		Code  string `json:"code,omitempty"`
+		Msg   string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(msg, &recv); err != nil {
+		ok.wsContractPrivCon.Close()
+		return errors.New(ok.Name() + " futures.ws.priv login resp err:" + err.Error())
+	}
+	if recv.Event != "login" {
+		ok.wsContractPrivCon.Close()
+		return errors.New(ok.Name() + " futures.ws.priv login failed! err event:" + string(msg))
+	}
+	if recv.Code != "0" {
+		ok.wsContractPrivCon.Close()
+		return errors.New(ok.Name() + " futures.ws.priv login error! " + string(recv.Msg))
+	}
+
+	ok.wsContractPrivChannelClosedMtx.Lock()
+	ok.wsContractPrivChannelClosed = false
+	ok.wsContractPrivChannelClosedMtx.Unlock()
+	return nil
+}
+func (ok *Okx) FuturesWsPrivateSubscribe(channels []string) {
+	if len(channels) == 0 {
+		return
+	}
+	type Arg struct {
+		Channel  string `json:"channel"`
+		InstType string `json:"instType,omitempty"`
+	}
+	for _, c := range channels {
+		var arg *Arg
+		if c == "orders" {
+			arg = &Arg{Channel: "orders", InstType: "SWAP"}
+		} else if c == "positions" {
+			arg = &Arg{Channel: "positions", InstType: "SWAP"}
+		} else {
+			continue
+		}
+		subscribe := struct {
+			Op   string `json:"op"`
+			Args []*Arg `json:"args"`
+		}{Op: "subscribe", Args: []*Arg{arg}}
+		subData, _ := json.Marshal(&subscribe)
+		ok.wsContractPrivConMtx.Lock()
+		ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, subData)
+		ok.wsContractPrivConMtx.Unlock()
+	}
+}
+func (ok *Okx) FuturesWsPrivateLoop(ch chan<- any) {
+	defer ok.FuturesWsPrivateClose()
+	defer close(ch)
+
+	pingInterval := 25 * time.Second
+	pongWait := pingInterval + 2*time.Second
+	ok.wsContractPrivCon.SetReadDeadline(time.Now().Add(pongWait))
+	ok.wsContractPrivCon.SetPongHandler(func(string) error {
+		ok.wsContractPrivCon.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	pingExit := make(chan struct{})
+	defer close(pingExit)
+	go func(exitChan <-chan struct{}) {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		var pingMsg = []byte("ping")
+		for {
+			select {
+			case <-exitChan:
+				return
+			case <-ticker.C:
+				if ok.FuturesWsPrivateIsClosed() {
+					break
+				}
+				ok.wsContractPrivConMtx.Lock()
+				ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, pingMsg)
+				ok.wsContractPrivConMtx.Unlock()
+			}
+		}
+	}(pingExit)
+
+	for {
+		_, recv, err := ok.wsContractPrivCon.ReadMessage()
+		if err != nil {
+			if !ok.FuturesWsPrivateIsClosed() {
+				ilog.Warning("%s", ok.Name()+" futures.ws.priv channel read: "+err.Error())
+			}
+			break
+		}
+		if ok.debug {
+			ilog.Rinfo("%s", ok.Name()+" futures priv ws: "+string(recv))
+		}
+		if len(recv) == 4 && bytes.Equal(recv, []byte("pong")) {
+			ok.wsContractPrivCon.SetReadDeadline(time.Now().Add(pongWait))
+			continue
+		}
+		msg := okxWsPrivMsgPool.Get().(*OkxWsPrivMsg)
+		msg.reset()
+		if err = json.Unmarshal(recv, msg); err != nil {
+			ilog.Error("%s", ok.Name()+" futures.ws.priv recv invalid msg:"+string(recv))
+			goto END
+		}
+		if msg.Event == "" && msg.RequestId == "" {
+			if msg.Arg.Channel == "orders" {
+				ok.futuresWsHandleOrder(msg.Data, ch)
+			} else if msg.Arg.Channel == "positions" {
+				ok.futuresWsHandlePosition(msg.Data, ch)
+			}
+		} else if msg.RequestId != "" {
+			if msg.Op == "order" || msg.Op == "amend-order" {
+				ok.futuresWsHandlePlaceOrderResp(msg.RequestId, msg.Data, ch)
+			} else if msg.Op == "cancel-order" {
+				ok.futuresWsHandleCancelOrderResp(msg.Data)
+			}
+		} else if msg.Event == "error" {
+			ilog.Error("%s", ok.Name()+" futures.ws.priv recv error event: "+string(recv))
+		} else if msg.Event == "login" {
+		} else if msg.Event == "subscribe" {
+		} else if msg.Event == "notice" {
+		} else if msg.Event == "channel-conn-count" {
+		} else if msg.Event == "channel-conn-count-error" {
+			ilog.Error("%s", ok.Name()+" futures.ws.priv recv err: "+string(recv))
+			okxWsPrivMsgPool.Put(msg)
+			break
+		} else {
+			ilog.Error("%s", ok.Name()+" futures.ws.priv recv unknown msg: "+string(recv))
+		}
+	END:
+		okxWsPrivMsgPool.Put(msg)
+	}
+}
+func (ok *Okx) FuturesWsPrivateIsClosed() bool {
+	ok.wsContractPrivChannelClosedMtx.RLock()
+	defer ok.wsContractPrivChannelClosedMtx.RUnlock()
+	return ok.wsContractPrivChannelClosed
+}
+func (ok *Okx) FuturesWsPrivateClose() {
+	ok.wsContractPrivChannelClosedMtx.Lock()
+	defer ok.wsContractPrivChannelClosedMtx.Unlock()
+	if ok.wsContractPrivChannelClosed {
+		return
+	}
+	ok.wsContractPrivChannelClosed = true
+	ok.wsContractPrivCon.Close()
+}
+func (ok *Okx) futuresWsHandleOrder(data json.RawMessage, ch chan<- any) {
+	orders := []OkxFuturesOrder{}
+	if err := json.Unmarshal(data, &orders); err == nil {
+		for i := range orders {
+			ch <- ok.toStdFuturesOrder(&orders[i])
+		}
+	}
+}
+func (ok *Okx) futuresWsHandlePosition(data json.RawMessage, ch chan<- any) {
+	pl := []struct {
+		Symbol        string `json:"instId"`
+		PosSide       string `json:"posSide"` // net/long/short
+		Pos           string `json:"pos"`
+		EntryPrice    string `json:"avgPx"`
+		LiqPrice      string `json:"liqPx"`
+		Leverage      string `json:"lever"`
+		UnrealisedPnl string `json:"upl"`
+		UTime         string `json:"uTime"`
+	}{}
+	if err := json.Unmarshal(data, &pl); err == nil {
+		for _, p := range pl {
+			sym, typ := ok.toStdContractSymbol(p.Symbol)
+			if sym == "" {
+				continue
+			}
+			pos, _ := decimal.NewFromString(p.Pos)
+			cp := &FuturesPosition{
+				Symbol: sym,
+				Side:   ok.toStdSide(p.PosSide),
+			}
+			if p.PosSide == "net" { // 单仓模式
+				cp.Side = "SELL"
+				if pos.IsPositive() {
+					cp.Side = "BUY"
+				}
+			} else {
+				cp.Mode = 1
+			}
+			cp.PositionQty = ok.FuturesSizeToQty(typ, sym, pos.Abs())
+			cp.EntryPrice, _ = decimal.NewFromString(p.EntryPrice)
+			cp.UnRealizedProfit, _ = decimal.NewFromString(p.UnrealisedPnl)
+			cp.Leverage, _ = decimal.NewFromString(p.Leverage)
+			cp.LiqPrice = decimal.NewFromFloat(-1)
+			if p.LiqPrice != "" {
+				cp.LiqPrice, _ = decimal.NewFromString(p.LiqPrice)
+			}
+			cp.UTime, _ = strconv.ParseInt(p.UTime, 10, 64)
+			ch <- cp
+		}
+	}
+}
+func (ok *Okx) futuresWsHandlePlaceOrderResp(reqId string, data json.RawMessage, ch chan<- any) {
+	ret := []struct {
+		OrderId  string `json:"ordId,omitempty"`
+		ClientId string `json:"clOrdId,omitempty"`
+		Code     string `json:"sCode,omitempty"`
+		Msg      string `json:"sMsg,omitempty"`
+	}{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		ilog.Error("%s", ok.Name()+" futures.ws.priv handle place order resp: "+err.Error())
+		return
+	}
+	for _, ord := range ret {
+		if ord.Code != "0" {
+			ch <- &FuturesOrder{
+				RequestId: reqId,
+				Err:       ord.Msg,
+			}
+			continue
+		}
+		ch <- &FuturesOrder{
+			RequestId: reqId,
+			OrderId:   ord.OrderId,
+			ClientId:  ord.ClientId,
+		}
+	}
+}
+func (ok *Okx) futuresWsHandleCancelOrderResp(data json.RawMessage) {
+	ret := []struct {
+		OrderId string `json:"ordId,omitempty"`
+		Code    string `json:"sCode,omitempty"`
+		Msg     string `json:"sMsg,omitempty"`
+	}{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		ilog.Error("%s", ok.Name()+" futures.ws.priv handle cancel order resp: "+err.Error())
+		return
+	}
+	for _, ord := range ret {
+		if ord.Code != "0" {
+			ilog.Error("%s", ok.Name()+" futures.ws.priv cancel order fail! "+string(data))
+		}
+	}
+}
+func (ok *Okx) FuturesWsPlaceOrder(symbol, cltId string, price, qty decimal.Decimal,
+	side, orderType, timeInForce, positionMode string,
+	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
+	if ok.FuturesWsPrivateIsClosed() {
+		return "", errors.New(ok.Name() + " futures priv ws closed")
+	}
+	type Arg struct {
+		Symbol     string `json:"instId,omitempty"`
+		TradeMode  string `json:"tdMode,omitempty"`
+		Type       string `json:"ordType,omitempty"`
+		Size       string `json:"sz,omitempty"`
+		Side       string `json:"side,omitempty"`
+		PosSide    string `json:"posSide,omitempty"`
+		ClientId   string `json:"clOrdId,omitempty"`
+		Price      string `json:"px,omitempty"`
+		ReduceOnly bool   `json:"reduceOnly,omitempty"`
+	}
+	_, typ := ok.toStdContractSymbol(ok.getContractSymbol(symbol))
+	arg := Arg{
+		Symbol:     ok.getContractSymbol(symbol),
+		TradeMode:  ok.fromStdTradeMode(tradeMode),
+		Type:       ok.fromStdFuturesOrderType(orderType, timeInForce),
+		Size:       ok.FuturesQtyToSize(typ, symbol, qty).String(),
+		Side:       ok.fromStdSide(side),
+		PosSide:    ok.fromStdPositionMode(positionMode),
+		ClientId:   cltId,
+		ReduceOnly: reduceOnly == 1,
+	}
+	if orderType != "MARKET" {
+		arg.Price = price.String()
+	}
+	type Req struct {
+		Id   string `json:"id"`
+		Op   string `json:"op"`
+		Args []Arg  `json:"args"`
+	}
+	req := Req{Id: gutils.RandomStr(16), Op: "order", Args: []Arg{arg}}
+	reqJson, _ := json.Marshal(req)
+	ok.wsContractPrivConMtx.Lock()
+	defer ok.wsContractPrivConMtx.Unlock()
+	if err := ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(ok.Name() + " send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
+func (ok *Okx) FuturesWsCancelOrder(symbol, orderId, cltId string) (string, error) {
+	if ok.FuturesWsPrivateIsClosed() {
+		return "", errors.New(ok.Name() + " futures.ws.priv ws closed")
+	}
+	type Arg struct {
+		Symbol  string `json:"instId"`
+		OrderId string `json:"ordId,omitempty"`
+		CltId   string `json:"clOrdId,omitempty"`
+	}
+	arg := Arg{
+		Symbol:  ok.getContractSymbol(symbol),
+		OrderId: orderId,
+		CltId:   cltId, // ordId和clOrdId必须传一个, 若传两个，以 ordId 为主
+	}
+	type Req struct {
+		Id   string `json:"id"`
+		Op   string `json:"op"`
+		Args []Arg  `json:"args"`
+	}
+	req := Req{Id: gutils.RandomStr(16), Op: "cancel-order", Args: []Arg{arg}}
+	reqJson, _ := json.Marshal(req)
+	ok.wsContractPrivConMtx.Lock()
+	defer ok.wsContractPrivConMtx.Unlock()
+	if err := ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(ok.Name() + " send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
+func (ok *Okx) FuturesWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	if ok.FuturesWsPrivateIsClosed() {
+		return "", errors.New(ok.Name() + " futures.ws.priv ws closed")
+	}
+	type Arg struct {
+		Symbol  string `json:"instId"`
+		OrderId string `json:"ordId,omitempty"`
+		CltId   string `json:"clOrdId,omitempty"`
+		Qty     string `json:"newSz"`
+		Price   string `json:"newPx"`
+	}
+	_, typ := ok.toStdContractSymbol(ok.getContractSymbol(symbol))
+	arg := Arg{
+		Symbol:  ok.getContractSymbol(symbol),
+		OrderId: orderId,
+		CltId:   cltId,
+		Qty:     ok.FuturesQtyToSize(typ, symbol, qty).String(),
+		Price:   price.String(),
+	}
+	type Req struct {
+		Id   string `json:"id"`
+		Op   string `json:"op"`
+		Args []Arg  `json:"args"`
+	}
+	req := Req{Id: gutils.RandomStr(16), Op: "amend-order", Args: []Arg{arg}}
+	reqJson, _ := json.Marshal(req)
+	ok.wsContractPrivConMtx.Lock()
+	defer ok.wsContractPrivConMtx.Unlock()
+	if err := ok.wsContractPrivCon.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(ok.Name() + " send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
@@ -3,15 +3,12 @@ package cex
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mailru/easyjson"
 	"github.com/shopspring/decimal"
-
-	"github.com/shaovie/gutils/ihttp"
 )
 
 func (ok *Okx) SpotSupported() bool {
@@ -19,9 +16,9 @@ func (ok *Okx) SpotSupported() bool {
 }
 func (ok *Okx) SpotServerTime() (int64, error) {
 	url := okUniEndpoint + "/api/v5/public/time"
-	_, resp, err := ihttp.Get(url, okApiDeadline, nil)
+	httpCode, resp, err := ok.Get(url, okApiDeadline, nil)
 	if err != nil {
-		return 0, errors.New(ok.Name() + " net error! " + err.Error())
+		return 0, newNetError(ok.Name(), err)
 	}
 	recv := struct {
 		Code string `json:"code,omitempty"`
@@ -35,7 +32,7 @@ func (ok *Okx) SpotServerTime() (int64, error) {
 		return 0, errors.New(ok.Name() + " unmarshal error! " + err.Error())
 	}
 	if recv.Code != "0" {
-		return 0, errors.New("resp error: " + recv.Msg)
+		return 0, ok.apiError(httpCode, recv.Code, recv.Msg)
 	}
 	if len(recv.Data) == 0 {
 		return 0, errors.New("resp empty")
@@ -45,12 +42,12 @@ func (ok *Okx) SpotServerTime() (int64, error) {
 }
 func (ok *Okx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 	url := okUniEndpoint + "/api/v5/public/instruments?instType=SPOT"
-	retCode, resp, err := ihttp.Get(url, okApiDeadline, nil)
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(ok.Name() + " net error! " + err.Error())
+		return nil, newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return nil, errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
 	}
 
 	ret := struct {
@@ -72,7 +69,7 @@ func (ok *Okx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
 	}
 	if ret.Code != "0" {
-		return nil, errors.New("resp error: " + ret.Msg)
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 	all := make(map[string]*SpotExchangePairRule)
 	now := time.Now().Unix()
@@ -106,12 +103,12 @@ func (ok *Okx) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
 func (ok *Okx) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
 	path := "/api/v5/market/tickers"
 	url := okUniEndpoint + path + "?instType=SPOT"
-	retCode, resp, err := ihttp.Get(url, okApiDeadline, nil)
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
 	if err != nil {
-		return nil, errors.New(ok.Name() + " net error! " + err.Error())
+		return nil, newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return nil, errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	tickers := Okx24hTickers{}
 	err = easyjson.Unmarshal(resp, &tickers)
@@ -167,12 +164,12 @@ func (ok *Okx) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 	path := "/api/v5/trade/order"
 	headers := ok.buildHeaders("POST", path, payload)
 	url := okUniEndpoint + path
-	retCode, resp, err := ihttp.Post(url, []byte(payload), okApiDeadline, headers)
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
 	if err != nil {
-		return "", errors.New(ok.Name() + " net error! " + err.Error())
+		return "", newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return "", errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return "", newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	ret := struct {
 		Code string `json:"code,omitempty"`
@@ -192,7 +189,7 @@ func (ok *Okx) SpotPlaceOrder(symbol, clientId string, /*BTCUSDT*/
 			ret.Code = ret.Data[0].SCode
 			ret.Msg = ret.Data[0].SMsg
 		}
-		return "", errors.New(ok.Name() + " fail! code=" + ret.Code + " msg=" + ret.Msg)
+		return "", ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 
 	if len(ret.Data) == 0 {
@@ -205,12 +202,12 @@ func (ok *Okx) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 	path := "/api/v5/account/balance"
 	url := okUniEndpoint + path
 	headers := ok.buildHeaders("GET", path, "")
-	retCode, resp, err := ihttp.Get(url, okApiDeadline, headers)
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(ok.Name() + " spot get assets net error! " + err.Error())
+		return nil, newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return nil, errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	ret := struct {
 		Code string `json:"code,omitempty"`
@@ -235,7 +232,7 @@ func (ok *Okx) SpotGetAllAssets() (map[string]*SpotAsset, error) {
 			ret.Code = ret.Data[0].SCode
 			ret.Msg = ret.Data[0].SMsg
 		}
-		return nil, errors.New(ok.Name() + " resp err! " + ret.Msg)
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 
 	spotAssets := make(map[string]*SpotAsset)
@@ -261,12 +258,12 @@ func (ok *Okx) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	}
 	headers := ok.buildHeaders("GET", path, "")
 	url := okUniEndpoint + path
-	retCode, resp, err := ihttp.Get(url, okApiDeadline, headers)
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(ok.Name() + " net error! " + err.Error())
+		return nil, newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return nil, errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	ret := struct {
 		Code string `json:"code,omitempty"`
@@ -299,7 +296,7 @@ func (ok *Okx) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 			ret.Code = ret.Data[0].SCode
 			ret.Msg = ret.Data[0].SMsg
 		}
-		return nil, errors.New(ok.Name() + " resp err! " + ret.Msg)
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 
 	dt := ret.Data[0]
@@ -324,6 +321,169 @@ func (ok *Okx) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
 	so.FeeQty, _ = decimal.NewFromString(dt.FeeQty)
 	return so, nil
 }
+func (ok *Okx) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	path := "/api/v5/trade/batch-orders"
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			o := orders[i]
+			results[i].ClientId = o.ClientId
+			orderType := o.Type
+			if o.TimeInForce == "IOC" || o.TimeInForce == "FOK" {
+				orderType = o.TimeInForce
+			}
+			if o.Type == "MARKET" && o.Side == "BUY" {
+				o.Qty = o.Amt
+			}
+			ordType := ok.fromStdOrderType(orderType)
+			if o.Type == "LIMIT" && o.PostOnly {
+				ordType = "post_only"
+			}
+			if i > start {
+				payload += ","
+			}
+			payload += `{"instId":"` + ok.getSpotSymbol(o.Symbol) + `"` +
+				`,"sz":"` + o.Qty.String() + `"`
+			if o.ClientId != "" {
+				payload += `,"clOrdId":"` + o.ClientId + `"`
+			}
+			payload += "" +
+				`,"px":"` + o.Price.String() + `"` +
+				`,"side":"` + ok.fromStdSide(o.Side) + `"` +
+				`,"ordType":"` + ordType + `"` +
+				`,"tdMode":"cash"` +
+				`}`
+		}
+		payload += "]"
+		headers := ok.buildHeaders("POST", path, payload)
+		retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+		ok.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+func (ok *Okx) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	path := "/api/v5/trade/cancel-batch-orders"
+	results := make([]BatchOrderResult, len(orders))
+	for start := 0; start < len(orders); start += 20 { // 每批最多20个
+		end := min(start+20, len(orders))
+		payload := "["
+		for i := start; i < end; i++ {
+			results[i].OrderId = orders[i].OrderId
+			results[i].ClientId = orders[i].ClientId
+			if i > start {
+				payload += ","
+			}
+			symbolS := ok.getSpotSymbol(orders[i].Symbol)
+			if orders[i].OrderId == "" && orders[i].ClientId != "" {
+				payload += `{"instId":"` + symbolS + `","clOrdId":"` + orders[i].ClientId + `"}`
+			} else {
+				payload += `{"instId":"` + symbolS + `","ordId":"` + orders[i].OrderId + `"}`
+			}
+		}
+		payload += "]"
+		headers := ok.buildHeaders("POST", path, payload)
+		retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+		ok.handleBatchResp(results[start:end], retCode, resp, err)
+	}
+	return results, nil
+}
+
+// code=1全部失败, code=2部分成功, 以data中的sCode为准
+func (ok *Okx) handleBatchResp(results []BatchOrderResult, retCode int, resp []byte, err error) {
+	if err != nil {
+		err = newNetError(ok.Name(), err)
+	} else if retCode != 200 {
+		err = newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			OrderId  string `json:"ordId"`
+			ClientId string `json:"clOrdId"`
+			SCode    string `json:"sCode"`
+			SMsg     string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	if err == nil {
+		if e := json.Unmarshal(resp, &ret); e != nil {
+			err = errors.New(ok.Name() + " unmarshal fail! " + e.Error())
+		} else if len(ret.Data) != len(results) {
+			err = ok.apiError(retCode, ret.Code, ret.Msg)
+		}
+	}
+	for i := range results {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		dt := ret.Data[i]
+		if dt.SCode != "0" {
+			results[i].Err = ok.apiError(retCode, dt.SCode, dt.SMsg)
+			continue
+		}
+		results[i].OrderId = dt.OrderId
+		results[i].ClientId = dt.ClientId
+	}
+}
+func (ok *Okx) SpotCancelAllOrders(symbol string) error {
+	if symbol == "" {
+		return errors.New(ok.Name() + " symbol empty!")
+	}
+	// 现货没有撤销全部的接口, 查询挂单后批量撤销
+	// 挂单每次最多查到100个, 循环直到没有未尝试过的挂单
+	var errs []error
+	tried := make(map[string]bool) // 撤销过的不再重试, 避免撤销失败时死循环
+	for {
+		orders, err := ok.SpotGetOpenOrders(symbol)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		args := make([]CancelOrderArg, 0, len(orders))
+		for _, o := range orders {
+			if !tried[o.OrderId] {
+				tried[o.OrderId] = true
+				args = append(args, CancelOrderArg{Symbol: symbol, OrderId: o.OrderId})
+			}
+		}
+		if len(args) == 0 {
+			break
+		}
+		results, _ := ok.SpotCancelOrders(args)
+		for i := range results {
+			if results[i].Err != nil {
+				errs = append(errs, results[i].Err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+func (ok *Okx) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	path := "/api/v5/trade/cancel-all-after"
+	payload := `{"timeOut":"` + strconv.Itoa(timeout) + `"}` // 0 或 10~120秒
+	headers := ok.buildHeaders("POST", path, payload)
+	retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	if err = json.Unmarshal(resp, &ret); err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
 func (ok *Okx) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string) error {
 	symbolS := ok.getSpotSymbol(symbol)
 	path := "/api/v5/trade/cancel-order"
@@ -333,12 +493,12 @@ func (ok *Okx) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string)
 	}
 	headers := ok.buildHeaders("POST", path, payload)
 	url := okUniEndpoint + path
-	retCode, resp, err := ihttp.Post(url, []byte(payload), okApiDeadline, headers)
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
 	if err != nil {
-		return errors.New(ok.Name() + " net error! " + err.Error())
+		return newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	ret := struct {
 		Code string `json:"code,omitempty"`
@@ -358,26 +518,80 @@ func (ok *Okx) SpotCancelOrder(symbol string /*BTCUSDT*/, orderId, cltId string)
 			ret.Code = ret.Data[0].SCode
 			ret.Msg = ret.Data[0].SMsg
 		}
-		return errors.New(ok.Name() + " resp error! " + ret.Msg)
+		return ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 
 	dt := ret.Data[0]
 	if dt.SCode != "0" {
-		return errors.New(ok.Name() + " resp error! " + dt.SMsg)
+		return ok.apiError(retCode, dt.SCode, dt.SMsg)
 	}
 	return nil
 }
+func (ok *Okx) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	symbolS := ok.getSpotSymbol(symbol)
+	path := "/api/v5/trade/amend-order"
+	payload := `{"instId":"` + symbolS + `","ordId":"` + orderId + `"`
+	if orderId == "" && cltId != "" {
+		payload = `{"instId":"` + symbolS + `","clOrdId":"` + cltId + `"`
+	}
+	payload += `,"newSz":"` + qty.String() + `","newPx":"` + price.String() + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			OrderId  string `json:"ordId"`
+			ClientId string `json:"clOrdId"`
+			SCode    string `json:"sCode"`
+			SMsg     string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		if ret.Code != "0" && len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+
+	dt := ret.Data[0]
+	if dt.SCode != "0" {
+		return nil, ok.apiError(retCode, dt.SCode, dt.SMsg)
+	}
+	return &SpotOrder{ // 改单为异步, 以订单推送为准
+		Symbol:   symbol,
+		OrderId:  dt.OrderId,
+		ClientId: dt.ClientId,
+		Price:    price,
+		Qty:      qty,
+		Type:     "LIMIT",
+		Side:     side,
+	}, nil
+}
 func (ok *Okx) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	symbolS := ok.getSpotSymbol(symbol)
 	path := "/api/v5/trade/orders-pending?instType=SPOT&instId=" + symbolS
 	headers := ok.buildHeaders("GET", path, "")
 	url := okUniEndpoint + path
-	retCode, resp, err := ihttp.Get(url, okApiDeadline, headers)
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
 	if err != nil {
-		return nil, errors.New(ok.Name() + " net error! " + err.Error())
+		return nil, newNetError(ok.Name(), err)
 	}
 	if retCode != 200 {
-		return nil, errors.New(ok.Name() + " http code " + fmt.Sprintf("%d", retCode))
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
 	}
 	ret := struct {
 		Code string `json:"code,omitempty"`
@@ -405,12 +619,12 @@ func (ok *Okx) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	if err != nil {
 		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
 	}
-	if ret.Code != "0" || len(ret.Data) == 0 {
-		if ret.Code != "0" && len(ret.Data) > 0 {
+	if ret.Code != "0" {
+		if len(ret.Data) > 0 {
 			ret.Code = ret.Data[0].SCode
 			ret.Msg = ret.Data[0].SMsg
 		}
-		return nil, errors.New(ok.Name() + " resp fail! " + ret.Msg)
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
 	}
 
 	orders := make([]*SpotOrder, 0, len(ret.Data))
@@ -438,3 +652,239 @@ func (ok *Okx) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
 	}
 	return orders, nil
 }
+
+// 用algo单(ordType=conditional)实现, 止损填sl*, 止盈填tp*
+func (ok *Okx) SpotPlaceConditionalOrder(symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string) (string, error) {
+	isStop, isLimit, valid := parseConditionalOrderType(orderType)
+	if !valid {
+		return "", errors.New("not support order type:" + orderType)
+	}
+	pxType := ok.fromStdTriggerBy(triggerBy)
+	if pxType == "" {
+		return "", errors.New(ok.Name() + " not support trigger by " + triggerBy)
+	}
+	ordPx := "-1" // 市价
+	if isLimit {
+		ordPx = price.String()
+	}
+	prefix := "tp"
+	if isStop {
+		prefix = "sl"
+	}
+	payload := `{"instId":"` + ok.getSpotSymbol(symbol) + `"` +
+		`,"tdMode":"cash"` +
+		`,"side":"` + ok.fromStdSide(side) + `"` +
+		`,"ordType":"conditional"` +
+		`,"sz":"` + qty.String() + `"` +
+		`,"` + prefix + `TriggerPx":"` + triggerPrice.String() + `"` +
+		`,"` + prefix + `OrdPx":"` + ordPx + `"` +
+		`,"` + prefix + `TriggerPxType":"` + pxType + `"`
+	if !isLimit {
+		payload += `,"tgtCcy":"base_ccy"` // 市价单sz按base计算
+	}
+	if cltId != "" {
+		payload += `,"algoClOrdId":"` + cltId + `"`
+	}
+	payload += `}`
+	path := "/api/v5/trade/order-algo"
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return "", newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return "", newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			AlgoId string `json:"algoId"`
+			SCode  string `json:"sCode,omitempty"`
+			SMsg   string `json:"sMsg,omitempty"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return "", errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		if len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return "", ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if len(ret.Data) == 0 {
+		return "", errors.New(ok.Name() + " fail! response emtpy")
+	}
+	return ret.Data[0].AlgoId, nil
+}
+func (ok *Okx) SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error) {
+	path := "/api/v5/trade/orders-algo-pending?ordType=conditional&instType=SPOT"
+	if symbol != "" {
+		path += "&instId=" + ok.getSpotSymbol(symbol)
+	}
+	headers := ok.buildHeaders("GET", path, "")
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol        string          `json:"instId"`
+			AlgoId        string          `json:"algoId"`
+			ClientId      string          `json:"algoClOrdId"`
+			Side          string          `json:"side"`
+			Qty           decimal.Decimal `json:"sz"`
+			SlTriggerPx   string          `json:"slTriggerPx"`
+			SlOrdPx       string          `json:"slOrdPx"`
+			SlTriggerType string          `json:"slTriggerPxType"`
+			TpTriggerPx   string          `json:"tpTriggerPx"`
+			TpOrdPx       string          `json:"tpOrdPx"`
+			TpTriggerType string          `json:"tpTriggerPxType"`
+			Status        string          `json:"state"`
+			Time          string          `json:"cTime"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	orders := make([]*ConditionalOrder, 0, len(ret.Data))
+	for _, order := range ret.Data {
+		ctime, _ := strconv.ParseInt(order.Time, 10, 64)
+		co := &ConditionalOrder{
+			Symbol:   strings.ReplaceAll(order.Symbol, "-", ""),
+			OrderId:  order.AlgoId,
+			ClientId: order.ClientId,
+			Side:     ok.toStdSide(order.Side),
+			Qty:      order.Qty,
+			Status:   ok.toStdAlgoOrderStatus(order.Status),
+			CTime:    ctime,
+		}
+		triggerPx, ordPx, triggerType := order.TpTriggerPx, order.TpOrdPx, order.TpTriggerType
+		co.Type = "TAKE_PROFIT"
+		if order.SlTriggerPx != "" {
+			triggerPx, ordPx, triggerType = order.SlTriggerPx, order.SlOrdPx, order.SlTriggerType
+			co.Type = "STOP"
+		}
+		co.TriggerPrice, _ = decimal.NewFromString(triggerPx)
+		co.TriggerBy = ok.toStdTriggerBy(triggerType)
+		if ordPx == "-1" {
+			co.Type += "_MARKET"
+		} else {
+			co.Type += "_LIMIT"
+			co.Price, _ = decimal.NewFromString(ordPx)
+		}
+		orders = append(orders, co)
+	}
+	return orders, nil
+}
+func (ok *Okx) SpotCancelConditionalOrder(symbol, orderId string) error {
+	path := "/api/v5/trade/cancel-algos"
+	payload := `[{"instId":"` + ok.getSpotSymbol(symbol) + `","algoId":"` + orderId + `"}]`
+	headers := ok.buildHeaders("POST", path, payload)
+	url := okUniEndpoint + path
+	retCode, resp, err := ok.Post(url, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			AlgoId string `json:"algoId"`
+			SCode  string `json:"sCode"`
+			SMsg   string `json:"sMsg"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		if ret.Code != "0" && len(ret.Data) > 0 {
+			ret.Code = ret.Data[0].SCode
+			ret.Msg = ret.Data[0].SMsg
+		}
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	if dt := ret.Data[0]; dt.SCode != "0" {
+		return ok.apiError(retCode, dt.SCode, dt.SMsg)
+	}
+	return nil
+}
+
+// 6h,12h,1d 按utc时间对齐, 一次最多300根
+func (ok *Okx) SpotGetKLine(symbol, interval string,
+	startTime, endTime, limit int64) ([]KLine, error) {
+	return ok.getKLine(ok.getSpotSymbol(symbol), interval, startTime, endTime, limit, 5)
+}
+
+// interval => okx bar, ws的频道为 candle+bar
+var okxKLineBars = map[string]string{"1m": "1m", "5m": "5m", "15m": "15m", "30m": "30m",
+	"1h": "1H", "4h": "4H", "6h": "6Hutc", "12h": "12Hutc", "1d": "1Dutc"}
+
+// volIdx: 成交量所在列, 现货为5(vol), 合约为6(volCcy 标的数量)
+func (ok *Okx) getKLine(instId, interval string,
+	startTime, endTime, limit int64, volIdx int) ([]KLine, error) {
+	bar, ok0 := okxKLineBars[interval]
+	if !ok0 {
+		return nil, errors.New(ok.Name() + " not support interval " + interval)
+	}
+	// after: 早于该时间的数据, before: 晚于该时间的数据 (msec)
+	query := "?instId=" + instId + "&bar=" + bar +
+		"&limit=" + strconv.FormatInt(limit, 10)
+	if end := klineEndTime(interval, startTime, endTime, limit); end > 0 {
+		query += "&after=" + strconv.FormatInt(end*1000, 10)
+	}
+	if startTime > 0 {
+		query += "&before=" + strconv.FormatInt(startTime*1000-1, 10)
+	}
+	url := okUniEndpoint + "/api/v5/market/candles" + query
+	retCode, resp, err := ok.Get(url, okApiDeadline, nil)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := OkxKLine{}
+	if err = easyjson.Unmarshal(resp, &ret); err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal error! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	all := make([]KLine, 0, len(ret.Data))
+	for i := len(ret.Data) - 1; i >= 0; i-- { // okx 是倒序
+		v := ret.Data[i]
+		kl := KLine{}
+		ts, _ := strconv.ParseInt(v[0], 10, 64)
+		kl.OpenTime = ts / 1000
+		kl.OpenPrice, _ = decimal.NewFromString(v[1])
+		kl.HighPrice, _ = decimal.NewFromString(v[2])
+		kl.LowPrice, _ = decimal.NewFromString(v[3])
+		kl.ClosePrice, _ = decimal.NewFromString(v[4])
+		kl.Volume, _ = decimal.NewFromString(v[volIdx])
+		kl.QuoteVolume, _ = decimal.NewFromString(v[7])
+
+		all = append(all, kl)
+	}
+	return all, nil
+}
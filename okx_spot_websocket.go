@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,7 +37,7 @@ func init() {
 	}
 }
 func (ok *Okx) SpotWsPublicOpen() error {
-	url := "wss://ws.okx.com:8443/ws/v5/public"
+	url := ok.wsUrl("wss://ws.okx.com:8443/ws/v5/public")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -46,6 +47,7 @@ func (ok *Okx) SpotWsPublicOpen() error {
 	if err != nil {
 		return errors.New(ok.Name() + " spot.ws.public con failed! " + err.Error())
 	}
+	ok.spotWsBusiness.open("spot.ws.business")
 
 	ok.spotWsPublicConnMtx.Lock()
 	ok.spotWsPublicClosed = false
@@ -66,6 +68,7 @@ func (ok *Okx) SpotWsPublicSubscribe(channels []string) {
 		Args []*Arg `json:"args"`
 	}{Id: gutils.RandomStr(8), Op: "subscribe"}
 	req.Args = make([]*Arg, 0, 2)
+	var klineArgs []map[string]string
 	for _, c := range channels {
 		arr := strings.Split(c, "@")
 		if arr[0] == "orderbook5" {
@@ -79,6 +82,19 @@ func (ok *Okx) SpotWsPublicSubscribe(channels []string) {
 					req.Args = append(req.Args, &arg)
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, level := parseOrderBookSymbol(v)
+				if symbolS := ok.getSpotSymbol(sym); symbolS != "" {
+					ok.spotWsOrderBooks.add(sym, level)
+					arg := Arg{Channel: "books", InstId: symbolS}
+					req.Args = append(req.Args, &arg)
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) < 2 || len(arr[1]) == 0 {
 				continue
@@ -100,6 +116,10 @@ func (ok *Okx) SpotWsPublicSubscribe(channels []string) {
 					}
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				klineArgs = append(klineArgs, ok.wsKLineArgs(arr[1], ok.getSpotSymbol)...)
+			}
 		}
 	}
 	if len(req.Args) > 0 {
@@ -108,6 +128,12 @@ func (ok *Okx) SpotWsPublicSubscribe(channels []string) {
 		ok.spotWsPublicConn.WriteMessage(websocket.TextMessage, subData)
 		ok.spotWsPublicConnMtx.Unlock()
 	}
+	if len(klineArgs) > 0 {
+		subData, _ := json.Marshal(map[string]any{"id": gutils.RandomStr(8), "op": req.Op, "args": klineArgs})
+		if err := ok.spotWsBusiness.write(ok, subData, true); err != nil {
+			ilog.Error("%s", ok.Name()+" spot.ws.business "+req.Op+": "+err.Error())
+		}
+	}
 }
 func (ok *Okx) SpotWsPublicUnsubscribe(channels []string) {
 	if len(channels) == 0 {
@@ -123,6 +149,7 @@ func (ok *Okx) SpotWsPublicUnsubscribe(channels []string) {
 		Args []*Arg `json:"args"`
 	}{Id: gutils.RandomStr(8), Op: "unsubscribe"}
 	req.Args = make([]*Arg, 0, 2)
+	var klineArgs []map[string]string
 	for _, c := range channels {
 		arr := strings.Split(c, "@")
 		if arr[0] == "orderbook5" {
@@ -136,6 +163,19 @@ func (ok *Okx) SpotWsPublicUnsubscribe(channels []string) {
 					req.Args = append(req.Args, &arg)
 				}
 			}
+		} else if arr[0] == "orderbook" {
+			if len(arr) < 2 || len(arr[1]) == 0 {
+				continue
+			}
+			symbolArr := strings.SplitSeq(arr[1], ",")
+			for v := range symbolArr {
+				sym, _ := parseOrderBookSymbol(v)
+				if symbolS := ok.getSpotSymbol(sym); symbolS != "" {
+					ok.spotWsOrderBooks.remove(sym)
+					arg := Arg{Channel: "books", InstId: symbolS}
+					req.Args = append(req.Args, &arg)
+				}
+			}
 		} else if arr[0] == "bbo" {
 			if len(arr) < 2 || len(arr[1]) == 0 {
 				continue
@@ -157,6 +197,10 @@ func (ok *Okx) SpotWsPublicUnsubscribe(channels []string) {
 					}
 				}
 			}
+		} else if arr[0] == "kline" {
+			if len(arr) > 1 && len(arr[1]) > 0 {
+				klineArgs = append(klineArgs, ok.wsKLineArgs(arr[1], ok.getSpotSymbol)...)
+			}
 		}
 	}
 	if len(req.Args) > 0 {
@@ -165,6 +209,12 @@ func (ok *Okx) SpotWsPublicUnsubscribe(channels []string) {
 		ok.spotWsPublicConn.WriteMessage(websocket.TextMessage, subData)
 		ok.spotWsPublicConnMtx.Unlock()
 	}
+	if len(klineArgs) > 0 {
+		subData, _ := json.Marshal(map[string]any{"id": gutils.RandomStr(8), "op": req.Op, "args": klineArgs})
+		if err := ok.spotWsBusiness.write(ok, subData, false); err != nil {
+			ilog.Error("%s", ok.Name()+" spot.ws.business "+req.Op+": "+err.Error())
+		}
+	}
 }
 func (ok *Okx) SpotWsPublicTickerPoolPut(v any) {
 	wsPublicTickerPool.Put(v)
@@ -172,6 +222,9 @@ func (ok *Okx) SpotWsPublicTickerPoolPut(v any) {
 func (ok *Okx) SpotWsPublicOrderBook5PoolPut(v any) {
 	wsPublicOrderBook5Pool.Put(v)
 }
+func (ok *Okx) SpotWsPublicKLinePoolPut(v any) {
+	wsPublicKLinePool.Put(v)
+}
 func (ok *Okx) SpotWsPublicLoop(ch chan<- any) {
 	defer ok.SpotWsPublicClose()
 	defer close(ch)
@@ -203,12 +256,28 @@ func (ok *Okx) SpotWsPublicLoop(ch chan<- any) {
 			}
 		}
 	}(pingExit)
+	// kline推送, 退出时先关闭business并等它结束, 再close(ch)
+	businessExit := make(chan struct{})
+	businessDone := make(chan struct{})
+	defer func() {
+		close(businessExit)
+		ok.spotWsBusiness.close()
+		<-businessDone
+	}()
+	go func() {
+		defer close(businessDone)
+		ok.spotWsBusiness.loop(ok, businessExit, ok.SpotWsPublicClose, func(msg *OkxWsPubMsg) {
+			if strings.HasPrefix(msg.Arg.Channel, "candle") {
+				ok.wsHandleKLine(strings.ReplaceAll(msg.Arg.Symbol, "-", ""), msg.Arg.Channel, msg.Data, 5, ch)
+			}
+		})
+	}()
 
 	for {
 		_, recv, err := ok.spotWsPublicConn.ReadMessage()
 		if err != nil {
 			if !ok.SpotWsPublicIsClosed() {
-				ilog.Warning(ok.Name() + " spot.ws.public read: " + err.Error())
+				ilog.Warning("%s", ok.Name()+" spot.ws.public read: "+err.Error())
 			}
 			break
 		}
@@ -220,28 +289,30 @@ func (ok *Okx) SpotWsPublicLoop(ch chan<- any) {
 		msg := okxWsPubMsgPool.Get().(*OkxWsPubMsg)
 		msg.reset()
 		if err = easyjson.Unmarshal(recv, msg); err != nil {
-			ilog.Error(ok.Name() + " spot.ws.public recv invalid msg:" + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.public recv invalid msg:"+string(recv))
 			goto END
 		}
 		if len(msg.Event) == 0 {
 			if msg.Arg.Channel == "books5" {
 				ok.spotWsHandleOrderBook5(msg.Arg.Symbol, msg.Data, ch)
+			} else if msg.Arg.Channel == "books" {
+				ok.spotWsHandleOrderBook(msg.Arg.Symbol, recv, ch)
 			} else if msg.Arg.Channel == "bbo-tbt" {
 				ok.spotWsHandleBBO(msg.Arg.Symbol, msg.Data, ch)
 			} else if msg.Arg.Channel == "tickers" {
 				ok.spotWsHandle24hTickers(msg.Data, ch)
 			}
 		} else if msg.Event == "error" {
-			ilog.Error(ok.Name() + " spot.ws.public recv error event: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.public recv error event: "+string(recv))
 		} else if msg.Event == "subscribe" {
 		} else if msg.Event == "unsubscribe" {
 		} else if msg.Event == "notice" {
 		} else if msg.Event == "channel-conn-count" {
 		} else if msg.Event == "channel-conn-count-error" {
-			ilog.Error(ok.Name() + " spot.ws.public recv err: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.public recv err: "+string(recv))
 			goto END
 		} else {
-			ilog.Error(ok.Name() + " spot.ws.public recv unknown msg: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.public recv unknown msg: "+string(recv))
 		}
 	END:
 		okxWsPubMsgPool.Put(msg)
@@ -260,6 +331,7 @@ func (ok *Okx) SpotWsPublicClose() {
 	}
 	ok.spotWsPublicClosed = true
 	ok.spotWsPublicConn.Close()
+	ok.spotWsBusiness.close()
 }
 func (ok *Okx) spotWsHandleOrderBook5(symbol string, data json.RawMessage, ch chan<- any) {
 	before, after, ok0 := strings.Cut(symbol, "-")
@@ -271,7 +343,7 @@ func (ok *Okx) spotWsHandleOrderBook5(symbol string, data json.RawMessage, ch ch
 	if err := json.Unmarshal(data, &orderBookList); err == nil && len(orderBookList) > 0 {
 		for _, depth := range orderBookList {
 			if len(depth.Bids) != len(depth.Asks) {
-				ilog.Error(ok.Name() + " spot.ws.public " + symbol + " exception")
+				ilog.Error("%s", ok.Name()+" spot.ws.public "+symbol+" exception")
 				continue
 			}
 			obd := wsPublicOrderBook5Pool.Get().(*OrderBookDepth)
@@ -292,6 +364,87 @@ func (ok *Okx) spotWsHandleOrderBook5(symbol string, data json.RawMessage, ch ch
 		}
 	}
 }
+
+// books 频道: 先推快照再推增量, prevSeqId 必须等于上次的 seqId, 并校验checksum
+// 丢包或校验失败时重新订阅, 交易所会重新推快照
+func (ok *Okx) spotWsHandleOrderBook(symbolS string, recv []byte, ch chan<- any) {
+	symbol := strings.ReplaceAll(symbolS, "-", "")
+	ob := ok.spotWsOrderBooks.get(symbol)
+	if ob == nil {
+		return
+	}
+	msg := struct {
+		Action string `json:"action"`
+		Data   []struct {
+			Time      string     `json:"ts"`
+			Bids      [][]string `json:"bids"`
+			Asks      [][]string `json:"asks"`
+			Checksum  int64      `json:"checksum"`
+			SeqId     int64      `json:"seqId"`
+			PrevSeqId int64      `json:"prevSeqId"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(recv, &msg); err != nil || len(msg.Data) == 0 {
+		ilog.Error("%s", ok.Name()+" spot.ws.public "+symbolS+" orderbook exception")
+		return
+	}
+	levels := func(l [][]string) [][2]string {
+		v := make([][2]string, 0, len(l))
+		for _, item := range l {
+			if len(item) >= 2 {
+				v = append(v, [2]string{item[0], item[1]})
+			}
+		}
+		return v
+	}
+	depth := msg.Data[0]
+	if msg.Action == "snapshot" {
+		ob.reset()
+		ob.synced = true
+	} else if !ob.synced { // 等待重新订阅后的快照
+		return
+	} else if depth.PrevSeqId != ob.seqId {
+		ilog.Warning("%s", ok.Name()+" spot.ws.public "+symbolS+" orderbook seq gap, resync")
+		ok.spotWsResyncOrderBook(ob, symbolS)
+		return
+	}
+	ob.setLevels(levels(depth.Bids), levels(depth.Asks))
+	ob.seqId = depth.SeqId
+	ob.time, _ = strconv.ParseInt(depth.Time, 10, 64)
+	if ok.orderBookChecksum(ob) != int32(depth.Checksum) || ob.crossed() {
+		ilog.Warning("%s", ok.Name()+" spot.ws.public "+symbolS+" orderbook checksum error, resync")
+		ok.spotWsResyncOrderBook(ob, symbolS)
+		return
+	}
+	ch <- ob.snapshot()
+}
+
+// crc32(bid1px:bid1sz:ask1px:ask1sz:bid2px...) 前25档, 一边不足时只拼另一边
+func (ok *Okx) orderBookChecksum(ob *localOrderBook) int32 {
+	bids := ob.top(true, 25)
+	asks := ob.top(false, 25)
+	var buf strings.Builder
+	for i := 0; i < 25; i++ {
+		if i < len(bids) {
+			buf.WriteString(bids[i].px + ":" + bids[i].sz + ":")
+		}
+		if i < len(asks) {
+			buf.WriteString(asks[i].px + ":" + asks[i].sz + ":")
+		}
+	}
+	str := strings.TrimSuffix(buf.String(), ":")
+	return int32(crc32.ChecksumIEEE([]byte(str)))
+}
+func (ok *Okx) spotWsResyncOrderBook(ob *localOrderBook, symbolS string) {
+	ob.reset()
+	for _, op := range []string{"unsubscribe", "subscribe"} {
+		req := fmt.Sprintf(`{"id":"%s","op":"%s","args":[{"channel":"books","instId":"%s"}]}`,
+			gutils.RandomStr(8), op, symbolS)
+		ok.spotWsPublicConnMtx.Lock()
+		ok.spotWsPublicConn.WriteMessage(websocket.TextMessage, []byte(req))
+		ok.spotWsPublicConnMtx.Unlock()
+	}
+}
 func (ok *Okx) spotWsHandleBBO(symbol string, data json.RawMessage, ch chan<- any) {
 	before, after, ok0 := strings.Cut(symbol, "-")
 	if !ok0 {
@@ -303,7 +456,7 @@ func (ok *Okx) spotWsHandleBBO(symbol string, data json.RawMessage, ch chan<- an
 	if err := json.Unmarshal(data, &orderBookList); err == nil && len(orderBookList) == 1 {
 		for _, depth := range orderBookList {
 			if len(depth.Bids) != len(depth.Asks) && len(depth.Bids) != 1 {
-				ilog.Error(ok.Name() + " spot.ws.public " + symbol + " bbo exception")
+				ilog.Error("%s", ok.Name()+" spot.ws.public "+symbol+" bbo exception")
 				continue
 			}
 			ts, _ := strconv.ParseInt(depth.Time, 10, 64)
@@ -341,7 +494,7 @@ func (ok *Okx) spotWsHandle24hTickers(data json.RawMessage, ch chan<- any) {
 
 // priv
 func (ok *Okx) SpotWsPrivateOpen() error {
-	url := "wss://ws.okx.com:8443/ws/v5/private"
+	url := ok.wsUrl("wss://ws.okx.com:8443/ws/v5/private")
 	var err error
 	dialer := websocket.Dialer{
 		EnableCompression: true, // 启用压缩扩展
@@ -482,12 +635,12 @@ func (ok *Okx) SpotWsPrivateLoop(ch chan<- any) {
 		_, recv, err := ok.spotWsPrivateConn.ReadMessage()
 		if err != nil {
 			if !ok.SpotWsPrivateIsClosed() {
-				ilog.Warning(ok.Name() + " spot.ws.priv channel read: " + err.Error())
+				ilog.Warning("%s", ok.Name()+" spot.ws.priv channel read: "+err.Error())
 			}
 			break
 		}
 		if ok.debug {
-			ilog.Rinfo(ok.Name() + " spot priv ws: " + string(recv))
+			ilog.Rinfo("%s", ok.Name()+" spot priv ws: "+string(recv))
 		}
 		if len(recv) == 4 && bytes.Equal(recv, []byte("pong")) {
 			ok.spotWsPrivateConn.SetReadDeadline(time.Now().Add(pongWait))
@@ -496,7 +649,7 @@ func (ok *Okx) SpotWsPrivateLoop(ch chan<- any) {
 		msg := okxWsPrivMsgPool.Get().(*OkxWsPrivMsg)
 		msg.reset()
 		if err = json.Unmarshal(recv, msg); err != nil {
-			ilog.Error(ok.Name() + " spot.ws.priv recv invalid msg:" + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.priv recv invalid msg:"+string(recv))
 			goto END
 		}
 		if msg.Event == "" && msg.RequestId == "" {
@@ -506,24 +659,24 @@ func (ok *Okx) SpotWsPrivateLoop(ch chan<- any) {
 				ok.spotWsHandleBalanceUpdate(msg.Data, ch)
 			}
 		} else if msg.RequestId != "" {
-			if msg.Op == "order" {
+			if msg.Op == "order" || msg.Op == "amend-order" {
 				ok.spotWsHandlePlaceOrderResp(msg.RequestId, msg.Data, ch)
 			} else if msg.Op == "cancel-order" {
 				ok.spotWsHandleCancelOrderResp(msg.RequestId, msg.Data, ch)
 			}
 		} else if msg.Event == "error" {
-			ilog.Error(ok.Name() + " spot.ws.priv recv error event: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.priv recv error event: "+string(recv))
 		} else if msg.Event == "login" {
 		} else if msg.Event == "subscribe" {
 		} else if msg.Event == "notice" {
 		} else if msg.Event == "channel-conn-count" {
 			// {"event":"channel-conn-count","channel":"orders","connCount":"1"
 		} else if msg.Event == "channel-conn-count-error" {
-			ilog.Error(ok.Name() + " spot.ws.priv recv err: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.priv recv err: "+string(recv))
 			okxWsPrivMsgPool.Put(msg)
 			break
 		} else {
-			ilog.Error(ok.Name() + " spot.ws.priv recv unknown msg: " + string(recv))
+			ilog.Error("%s", ok.Name()+" spot.ws.priv recv unknown msg: "+string(recv))
 		}
 	END:
 		okxWsPrivMsgPool.Put(msg)
@@ -621,7 +774,7 @@ func (ok *Okx) spotWsHandlePlaceOrderResp(reqId string, data json.RawMessage, ch
 		Msg      string `json:"sMsg,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &ret); err != nil {
-		ilog.Error(ok.Name() + " spot.ws.priv handle place order resp: " + err.Error())
+		ilog.Error("%s", ok.Name()+" spot.ws.priv handle place order resp: "+err.Error())
 		return
 	}
 	for _, ord := range ret {
@@ -647,12 +800,12 @@ func (ok *Okx) spotWsHandleCancelOrderResp(reqId string, data json.RawMessage, c
 		Msg     string `json:"sMsg,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &ret); err != nil {
-		ilog.Error(ok.Name() + " spot.ws.priv handle cancel order resp: " + err.Error())
+		ilog.Error("%s", ok.Name()+" spot.ws.priv handle cancel order resp: "+err.Error())
 		return
 	}
 	for _, ord := range ret {
 		if ord.Code != "0" {
-			ilog.Error(ok.Name() + " spot.ws.priv cancel order fail! " + string(data))
+			ilog.Error("%s", ok.Name()+" spot.ws.priv cancel order fail! "+string(data))
 		}
 	}
 }
@@ -735,3 +888,36 @@ func (ok *Okx) SpotWsCancelOrder(symbol, orderId, cltId string) (string, error)
 	}
 	return req.Id, nil
 }
+func (ok *Okx) SpotWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	if ok.SpotWsPrivateIsClosed() {
+		return "", errors.New(ok.Name() + " spot.ws.priv ws closed")
+	}
+	type Arg struct {
+		Symbol  string `json:"instId"`
+		OrderId string `json:"ordId,omitempty"`
+		CltId   string `json:"clOrdId,omitempty"`
+		Qty     string `json:"newSz"`
+		Price   string `json:"newPx"`
+	}
+	arg := Arg{
+		Symbol:  ok.getSpotSymbol(symbol),
+		OrderId: orderId,
+		CltId:   cltId,
+		Qty:     qty.String(),
+		Price:   price.String(),
+	}
+	type Req struct {
+		Id   string `json:"id"`
+		Op   string `json:"op"`
+		Args []Arg  `json:"args"`
+	}
+	req := Req{Id: gutils.RandomStr(16), Op: "amend-order", Args: []Arg{arg}}
+	reqJson, _ := json.Marshal(req)
+	ok.spotWsPrivateConnMtx.Lock()
+	defer ok.spotWsPrivateConnMtx.Unlock()
+	if err := ok.spotWsPrivateConn.WriteMessage(websocket.TextMessage, reqJson); err != nil {
+		return "", errors.New(ok.Name() + " send fail: " + err.Error())
+	}
+	return req.Id, nil
+}
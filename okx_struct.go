@@ -47,6 +47,7 @@ type OkxFundingRates struct {
 	Data []struct {
 		Symbol   string          `json:"instId"`
 		InstType string          `json:"instType"`
+		Time     string          `json:"fundingTime,omitempty"`     // 本期结算时间 msec
 		NextTime string          `json:"nextFundingTime,omitempty"` // msec
 		Fr       decimal.Decimal `json:"fundingRate"`
 	} `json:"data,omitempty"`
@@ -56,3 +57,19 @@ type OkxKLine struct {
 	Msg  string      `json:"msg,omitempty"`
 	Data [][9]string `json:"data,omitempty"`
 }
+type OkxFuturesOrder struct {
+	Symbol      string `json:"instId"`
+	OrderId     string `json:"ordId"`
+	ClientId    string `json:"clOrdId,omitempty"`
+	Price       string `json:"px,omitempty"`
+	Size        string `json:"sz"`        // 张数
+	ExecutedQty string `json:"accFillSz"` // 张数
+	AvgPrice    string `json:"avgPx,omitempty"`
+	Status      string `json:"state"`
+	Type        string `json:"ordType"`
+	Side        string `json:"side"`
+	FeeCoin     string `json:"feeCcy,omitempty"`
+	FeeQty      string `json:"fee,omitempty"`
+	Time        string `json:"cTime"`
+	UTime       string `json:"uTime,omitempty"`
+}
@@ -619,7 +619,215 @@ func (v *OkxKLine) UnmarshalJSON(data []byte) error {
 func (v *OkxKLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonBb14c8deDecodeGithubComShaovieCex3(l, v)
 }
-func easyjsonBb14c8deDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *OkxFundingRates) {
+func easyjsonBb14c8deDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *OkxFuturesOrder) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "instId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Symbol = string(in.String())
+			}
+		case "ordId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OrderId = string(in.String())
+			}
+		case "clOrdId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientId = string(in.String())
+			}
+		case "px":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Price = string(in.String())
+			}
+		case "sz":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Size = string(in.String())
+			}
+		case "accFillSz":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExecutedQty = string(in.String())
+			}
+		case "avgPx":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvgPrice = string(in.String())
+			}
+		case "state":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "ordType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = string(in.String())
+			}
+		case "side":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Side = string(in.String())
+			}
+		case "feeCcy":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FeeCoin = string(in.String())
+			}
+		case "fee":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FeeQty = string(in.String())
+			}
+		case "cTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
+		case "uTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UTime = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBb14c8deEncodeGithubComShaovieCex4(out *jwriter.Writer, in OkxFuturesOrder) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"instId\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Symbol))
+	}
+	{
+		const prefix string = ",\"ordId\":"
+		out.RawString(prefix)
+		out.String(string(in.OrderId))
+	}
+	if in.ClientId != "" {
+		const prefix string = ",\"clOrdId\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientId))
+	}
+	if in.Price != "" {
+		const prefix string = ",\"px\":"
+		out.RawString(prefix)
+		out.String(string(in.Price))
+	}
+	{
+		const prefix string = ",\"sz\":"
+		out.RawString(prefix)
+		out.String(string(in.Size))
+	}
+	{
+		const prefix string = ",\"accFillSz\":"
+		out.RawString(prefix)
+		out.String(string(in.ExecutedQty))
+	}
+	if in.AvgPrice != "" {
+		const prefix string = ",\"avgPx\":"
+		out.RawString(prefix)
+		out.String(string(in.AvgPrice))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"ordType\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"side\":"
+		out.RawString(prefix)
+		out.String(string(in.Side))
+	}
+	if in.FeeCoin != "" {
+		const prefix string = ",\"feeCcy\":"
+		out.RawString(prefix)
+		out.String(string(in.FeeCoin))
+	}
+	if in.FeeQty != "" {
+		const prefix string = ",\"fee\":"
+		out.RawString(prefix)
+		out.String(string(in.FeeQty))
+	}
+	{
+		const prefix string = ",\"cTime\":"
+		out.RawString(prefix)
+		out.String(string(in.Time))
+	}
+	if in.UTime != "" {
+		const prefix string = ",\"uTime\":"
+		out.RawString(prefix)
+		out.String(string(in.UTime))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OkxFuturesOrder) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBb14c8deEncodeGithubComShaovieCex4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OkxFuturesOrder) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBb14c8deEncodeGithubComShaovieCex4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OkxFuturesOrder) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBb14c8deDecodeGithubComShaovieCex4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OkxFuturesOrder) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBb14c8deDecodeGithubComShaovieCex4(l, v)
+}
+func easyjsonBb14c8deDecodeGithubComShaovieCex5(in *jlexer.Lexer, out *OkxFundingRates) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -656,13 +864,15 @@ func easyjsonBb14c8deDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *OkxFundin
 						out.Data = make([]struct {
 							Symbol   string          `json:"instId"`
 							InstType string          `json:"instType"`
+							Time     string          `json:"fundingTime,omitempty"`
 							NextTime string          `json:"nextFundingTime,omitempty"`
 							Fr       decimal.Decimal `json:"fundingRate"`
-						}, 0, 1)
+						}, 0, 0)
 					} else {
 						out.Data = []struct {
 							Symbol   string          `json:"instId"`
 							InstType string          `json:"instType"`
+							Time     string          `json:"fundingTime,omitempty"`
 							NextTime string          `json:"nextFundingTime,omitempty"`
 							Fr       decimal.Decimal `json:"fundingRate"`
 						}{}
@@ -674,6 +884,7 @@ func easyjsonBb14c8deDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *OkxFundin
 					var v19 struct {
 						Symbol   string          `json:"instId"`
 						InstType string          `json:"instType"`
+						Time     string          `json:"fundingTime,omitempty"`
 						NextTime string          `json:"nextFundingTime,omitempty"`
 						Fr       decimal.Decimal `json:"fundingRate"`
 					}
@@ -693,7 +904,7 @@ func easyjsonBb14c8deDecodeGithubComShaovieCex4(in *jlexer.Lexer, out *OkxFundin
 		in.Consumed()
 	}
 }
-func easyjsonBb14c8deEncodeGithubComShaovieCex4(out *jwriter.Writer, in OkxFundingRates) {
+func easyjsonBb14c8deEncodeGithubComShaovieCex5(out *jwriter.Writer, in OkxFundingRates) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -738,29 +949,30 @@ func easyjsonBb14c8deEncodeGithubComShaovieCex4(out *jwriter.Writer, in OkxFundi
 // MarshalJSON supports json.Marshaler interface
 func (v OkxFundingRates) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBb14c8deEncodeGithubComShaovieCex4(&w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v OkxFundingRates) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBb14c8deEncodeGithubComShaovieCex4(w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *OkxFundingRates) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBb14c8deDecodeGithubComShaovieCex4(&r, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *OkxFundingRates) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBb14c8deDecodeGithubComShaovieCex4(l, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex5(l, v)
 }
 func easyjsonBb14c8deDecode1(in *jlexer.Lexer, out *struct {
 	Symbol   string          `json:"instId"`
 	InstType string          `json:"instType"`
+	Time     string          `json:"fundingTime,omitempty"`
 	NextTime string          `json:"nextFundingTime,omitempty"`
 	Fr       decimal.Decimal `json:"fundingRate"`
 }) {
@@ -789,6 +1001,12 @@ func easyjsonBb14c8deDecode1(in *jlexer.Lexer, out *struct {
 			} else {
 				out.InstType = string(in.String())
 			}
+		case "fundingTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Time = string(in.String())
+			}
 		case "nextFundingTime":
 			if in.IsNull() {
 				in.Skip()
@@ -816,6 +1034,7 @@ func easyjsonBb14c8deDecode1(in *jlexer.Lexer, out *struct {
 func easyjsonBb14c8deEncode1(out *jwriter.Writer, in struct {
 	Symbol   string          `json:"instId"`
 	InstType string          `json:"instType"`
+	Time     string          `json:"fundingTime,omitempty"`
 	NextTime string          `json:"nextFundingTime,omitempty"`
 	Fr       decimal.Decimal `json:"fundingRate"`
 }) {
@@ -832,6 +1051,11 @@ func easyjsonBb14c8deEncode1(out *jwriter.Writer, in struct {
 		out.RawString(prefix)
 		out.String(string(in.InstType))
 	}
+	if in.Time != "" {
+		const prefix string = ",\"fundingTime\":"
+		out.RawString(prefix)
+		out.String(string(in.Time))
+	}
 	if in.NextTime != "" {
 		const prefix string = ",\"nextFundingTime\":"
 		out.RawString(prefix)
@@ -844,7 +1068,7 @@ func easyjsonBb14c8deEncode1(out *jwriter.Writer, in struct {
 	}
 	out.RawByte('}')
 }
-func easyjsonBb14c8deDecodeGithubComShaovieCex5(in *jlexer.Lexer, out *Okx24hTickers) {
+func easyjsonBb14c8deDecodeGithubComShaovieCex6(in *jlexer.Lexer, out *Okx24hTickers) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -907,7 +1131,7 @@ func easyjsonBb14c8deDecodeGithubComShaovieCex5(in *jlexer.Lexer, out *Okx24hTic
 		in.Consumed()
 	}
 }
-func easyjsonBb14c8deEncodeGithubComShaovieCex5(out *jwriter.Writer, in Okx24hTickers) {
+func easyjsonBb14c8deEncodeGithubComShaovieCex6(out *jwriter.Writer, in Okx24hTickers) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -952,27 +1176,27 @@ func easyjsonBb14c8deEncodeGithubComShaovieCex5(out *jwriter.Writer, in Okx24hTi
 // MarshalJSON supports json.Marshaler interface
 func (v Okx24hTickers) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBb14c8deEncodeGithubComShaovieCex5(&w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Okx24hTickers) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBb14c8deEncodeGithubComShaovieCex5(w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Okx24hTickers) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBb14c8deDecodeGithubComShaovieCex5(&r, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Okx24hTickers) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBb14c8deDecodeGithubComShaovieCex5(l, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex6(l, v)
 }
-func easyjsonBb14c8deDecodeGithubComShaovieCex6(in *jlexer.Lexer, out *Okx24hTicker) {
+func easyjsonBb14c8deDecodeGithubComShaovieCex7(in *jlexer.Lexer, out *Okx24hTicker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1026,7 +1250,7 @@ func easyjsonBb14c8deDecodeGithubComShaovieCex6(in *jlexer.Lexer, out *Okx24hTic
 		in.Consumed()
 	}
 }
-func easyjsonBb14c8deEncodeGithubComShaovieCex6(out *jwriter.Writer, in Okx24hTicker) {
+func easyjsonBb14c8deEncodeGithubComShaovieCex7(out *jwriter.Writer, in Okx24hTicker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1056,23 +1280,23 @@ func easyjsonBb14c8deEncodeGithubComShaovieCex6(out *jwriter.Writer, in Okx24hTi
 // MarshalJSON supports json.Marshaler interface
 func (v Okx24hTicker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBb14c8deEncodeGithubComShaovieCex6(&w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Okx24hTicker) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBb14c8deEncodeGithubComShaovieCex6(w, v)
+	easyjsonBb14c8deEncodeGithubComShaovieCex7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Okx24hTicker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBb14c8deDecodeGithubComShaovieCex6(&r, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Okx24hTicker) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBb14c8deDecodeGithubComShaovieCex6(l, v)
+	easyjsonBb14c8deDecodeGithubComShaovieCex7(l, v)
 }
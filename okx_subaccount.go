@@ -0,0 +1,294 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+func (ok *Okx) SubAccountList() ([]SubAccount, error) {
+	path := "/api/v5/users/subaccount/list"
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			SubAcct string `json:"subAcct"`
+			Label   string `json:"label"`
+			Enable  bool   `json:"enable"`
+			CTime   string `json:"ts"` // msec
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	res := make([]SubAccount, 0, len(ret.Data))
+	for _, v := range ret.Data {
+		ctime, _ := strconv.ParseInt(v.CTime, 10, 64)
+		sa := SubAccount{
+			Id:     v.SubAcct,
+			Name:   v.SubAcct,
+			Remark: v.Label,
+			Status: "NORMAL",
+			CTime:  ctime / 1000,
+		}
+		if !v.Enable {
+			sa.Status = "FROZEN"
+		}
+		res = append(res, sa)
+	}
+	return res, nil
+}
+func (ok *Okx) SubAccountCreate(name, remark string) (*SubAccount, error) {
+	path := "/api/v5/users/subaccount/create-subaccount"
+	payload := `{"subAcct":"` + name + `","type":"1","label":"` + remark + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			SubAcct string `json:"subAcct"`
+			Label   string `json:"label"`
+			CTime   string `json:"ts"` // msec
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	ctime, _ := strconv.ParseInt(ret.Data[0].CTime, 10, 64)
+	return &SubAccount{
+		Id:     ret.Data[0].SubAcct,
+		Name:   ret.Data[0].SubAcct,
+		Remark: ret.Data[0].Label,
+		Status: "NORMAL",
+		CTime:  ctime / 1000,
+	}, nil
+}
+
+// 统一账户, SPOT/UM_FUTURE 返回交易账户资产
+func (ok *Okx) SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error) {
+	if typ == "FUNDING" {
+		return ok.subAccountGetFundingAssets(subAccount)
+	} else if typ != "SPOT" && typ != "UM_FUTURE" {
+		return nil, newNotSupportError(ok.Name())
+	}
+	path := "/api/v5/account/subaccount/balances?subAcct=" + subAccount
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Detail []struct {
+				Symbol string          `json:"ccy"`
+				Total  decimal.Decimal `json:"eq"`
+				Avail  decimal.Decimal `json:"availBal"`
+				Locked decimal.Decimal `json:"frozenBal"`
+			} `json:"details,omitempty"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	assets := make(map[string]*SubAccountAsset)
+	for _, dt := range ret.Data {
+		for _, v := range dt.Detail {
+			assets[v.Symbol] = &SubAccountAsset{
+				Symbol: v.Symbol,
+				Total:  v.Total,
+				Avail:  v.Avail,
+				Locked: v.Locked,
+			}
+		}
+	}
+	return assets, nil
+}
+func (ok *Okx) subAccountGetFundingAssets(subAccount string) (map[string]*SubAccountAsset, error) {
+	path := "/api/v5/asset/subaccount/balances?subAcct=" + subAccount
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol string          `json:"ccy"`
+			Total  decimal.Decimal `json:"bal"`
+			Avail  decimal.Decimal `json:"availBal"`
+			Locked decimal.Decimal `json:"frozenBal"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	assets := make(map[string]*SubAccountAsset, len(ret.Data))
+	for _, v := range ret.Data {
+		assets[v.Symbol] = &SubAccountAsset{
+			Symbol: v.Symbol,
+			Total:  v.Total,
+			Avail:  v.Avail,
+			Locked: v.Locked,
+		}
+	}
+	return assets, nil
+}
+
+// 最近3个月, 最多100条
+func (ok *Okx) SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error) {
+	path := "/api/v5/asset/subaccount/bills?limit=100"
+	if subAccount != "" {
+		path += "&subAcct=" + subAccount
+	}
+	if startTime > 0 {
+		path += "&begin=" + strconv.FormatInt(startTime*1000, 10)
+	}
+	if endTime > 0 {
+		path += "&end=" + strconv.FormatInt(endTime*1000, 10)
+	}
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Id      string          `json:"billId"`
+			Symbol  string          `json:"ccy"`
+			Qty     decimal.Decimal `json:"amt"`
+			Type    string          `json:"type"` // 0:母转子 1:子转母
+			SubAcct string          `json:"subAcct"`
+			CTime   string          `json:"ts"` // msec
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	res := make([]SubAccountTransfer, 0, len(ret.Data))
+	for _, v := range ret.Data {
+		ctime, _ := strconv.ParseInt(v.CTime, 10, 64)
+		t := SubAccountTransfer{
+			Id:     v.Id,
+			Symbol: v.Symbol,
+			Qty:    v.Qty,
+			Status: "SUCCESS", // 只返回成功的记录
+			Time:   ctime / 1000,
+		}
+		if v.Type == "0" {
+			t.To = v.SubAcct
+		} else {
+			t.From = v.SubAcct
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// perm READ:read_only TRADE:read_only,trade, ips 最多20个
+func (ok *Okx) SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error) {
+	params := map[string]any{
+		"subAcct":    subAccount,
+		"label":      label,
+		"passphrase": passphrase,
+		"perm":       "read_only",
+	}
+	if perm == "TRADE" {
+		params["perm"] = "read_only,trade"
+	} else if perm != "READ" {
+		return nil, errors.New(ok.Name() + " not support perm " + perm)
+	}
+	if len(ips) > 0 {
+		params["ip"] = strings.Join(ips, ",")
+	}
+	body, _ := json.Marshal(params)
+	path := "/api/v5/users/subaccount/apikey"
+	headers := ok.buildHeaders("POST", path, string(body))
+	retCode, resp, err := ok.Post(okUniEndpoint+path, body, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Label      string `json:"label"`
+			ApiKey     string `json:"apiKey"`
+			SecretKey  string `json:"secretKey"`
+			Passphrase string `json:"passphrase"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" || len(ret.Data) == 0 {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	return &SubAccountApiKey{
+		ApiKey:     ret.Data[0].ApiKey,
+		SecretKey:  ret.Data[0].SecretKey,
+		Passphrase: ret.Data[0].Passphrase,
+		Label:      ret.Data[0].Label,
+		Perm:       perm,
+		Ips:        ips,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// BindNetworks 的key为okx的链名, 如 USDT-TRC20
+func (ok *Okx) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
+	path := "/api/v5/asset/currencies"
+	url := okUniEndpoint + path
+	headers := ok.buildHeaders("GET", path, "")
+	retCode, resp, err := ok.Get(url, okApiDeadline, headers)
+	if err != nil {
+		return nil, newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return nil, newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data []struct {
+			Symbol              string          `json:"ccy"`
+			Network             string          `json:"chain"`
+			CanDep              bool            `json:"canDep"`
+			CanWd               bool            `json:"canWd"`
+			WithdrawScale       string          `json:"wdTickSz"`
+			WithdrawFee         decimal.Decimal `json:"fee"`
+			MinFee              decimal.Decimal `json:"minFee"`
+			MinWithdrawalAmount decimal.Decimal `json:"minWd"`
+			MinDepositAmount    decimal.Decimal `json:"minDep"`
+		} `json:"data,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return nil, ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+
+	waiMap := make(map[string]*WalletAssetInfo)
+	for _, v := range ret.Data {
+		wai, exist := waiMap[v.Symbol]
+		if !exist {
+			wai = &WalletAssetInfo{
+				Symbol:       v.Symbol,
+				BindNetworks: make(map[string]*WalletAssetBindNetworkInfo),
+			}
+			waiMap[v.Symbol] = wai
+		}
+		scale, _ := strconv.ParseInt(v.WithdrawScale, 10, 32)
+		fee := v.WithdrawFee
+		if fee.IsZero() {
+			fee = v.MinFee // 旧版本接口
+		}
+		wai.BindNetworks[v.Network] = &WalletAssetBindNetworkInfo{
+			IsWithdrawalEnabled: v.CanWd,
+			IsDepositEnabled:    v.CanDep,
+			WithdrawScale:       int32(scale),
+			WithdrawFee:         fee,
+			MinWithdrawalAmount: v.MinWithdrawalAmount,
+			MinDepositAmount:    v.MinDepositAmount,
+		}
+	}
+	return waiMap, nil
+}
+func (ok *Okx) CancelWithdrawal(symbol, wid string) error {
+	path := "/api/v5/asset/cancel-withdrawal"
+	payload := `{"wdId":"` + wid + `"}`
+	headers := ok.buildHeaders("POST", path, payload)
+	retCode, resp, err := ok.Post(okUniEndpoint+path, []byte(payload), okApiDeadline, headers)
+	if err != nil {
+		return newNetError(ok.Name(), err)
+	}
+	if retCode != 200 {
+		return newApiError(ok.Name(), retCode, "", string(resp))
+	}
+	ret := struct {
+		Code string `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}{}
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return errors.New(ok.Name() + " unmarshal fail! " + err.Error())
+	}
+	if ret.Code != "0" {
+		return ok.apiError(retCode, ret.Code, ret.Msg)
+	}
+	return nil
+}
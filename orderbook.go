@@ -0,0 +1,405 @@
+package cex
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emirpasic/gods/v2/maps/treemap"
+	"github.com/shopspring/decimal"
+)
+
+// orderbook@SYMBOL:DEPTH 频道推送的本地订单簿快照
+// 每次推送都是新的对象(不走pool), 调用方可以长期持有
+type OrderBook struct {
+	Symbol   string   // BTCUSDT
+	Level    int      // 订阅的档位, 0表示交易所能提供的全量
+	UpdateId int64    // 最后一次更新的序号
+	Time     int64    // msec  0  表示交易所不提供
+	Bids     []Ticker // 价格从高到低
+	Asks     []Ticker // 价格从低到高
+}
+
+func (ob *OrderBook) BestBid() Ticker {
+	if len(ob.Bids) == 0 {
+		return Ticker{}
+	}
+	return ob.Bids[0]
+}
+func (ob *OrderBook) BestAsk() Ticker {
+	if len(ob.Asks) == 0 {
+		return Ticker{}
+	}
+	return ob.Asks[0]
+}
+func (ob *OrderBook) MidPrice() decimal.Decimal {
+	if len(ob.Bids) == 0 || len(ob.Asks) == 0 {
+		return decimal.Zero
+	}
+	return ob.Bids[0].Price.Add(ob.Asks[0].Price).Div(decimal.NewFromInt(2))
+}
+func (ob *OrderBook) Spread() decimal.Decimal {
+	if len(ob.Bids) == 0 || len(ob.Asks) == 0 {
+		return decimal.Zero
+	}
+	return ob.Asks[0].Price.Sub(ob.Bids[0].Price)
+}
+
+// 某个价格档位上的挂单量, 没有该档位返回0
+func (ob *OrderBook) QtyAt(price decimal.Decimal) decimal.Decimal {
+	for _, v := range ob.Bids {
+		if v.Price.Equal(price) {
+			return v.Quantity
+		} else if v.Price.LessThan(price) {
+			break
+		}
+	}
+	for _, v := range ob.Asks {
+		if v.Price.Equal(price) {
+			return v.Quantity
+		} else if v.Price.GreaterThan(price) {
+			break
+		}
+	}
+	return decimal.Zero
+}
+
+// 从盘口到price(含)的累计挂单量
+// price <= 买1 统计买盘, price >= 卖1 统计卖盘, 在盘口中间返回0
+func (ob *OrderBook) DepthAt(price decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	if len(ob.Bids) > 0 && price.LessThanOrEqual(ob.Bids[0].Price) {
+		for _, v := range ob.Bids {
+			if v.Price.LessThan(price) {
+				break
+			}
+			total = total.Add(v.Quantity)
+		}
+	} else if len(ob.Asks) > 0 && price.GreaterThanOrEqual(ob.Asks[0].Price) {
+		for _, v := range ob.Asks {
+			if v.Price.GreaterThan(price) {
+				break
+			}
+			total = total.Add(v.Quantity)
+		}
+	}
+	return total
+}
+
+// 以市价吃掉qty数量的成交均价, side=BUY 吃卖盘, side=SELL 吃买盘
+// 本地深度不够qty时返回false
+func (ob *OrderBook) VWAP(side string, qty decimal.Decimal) (decimal.Decimal, bool) {
+	levels := ob.Asks
+	if side == "SELL" {
+		levels = ob.Bids
+	}
+	if !qty.IsPositive() {
+		return decimal.Zero, false
+	}
+	left, amt := qty, decimal.Zero
+	for _, v := range levels {
+		fill := decimal.Min(left, v.Quantity)
+		amt = amt.Add(fill.Mul(v.Price))
+		left = left.Sub(fill)
+		if !left.IsPositive() {
+			return amt.Div(qty), true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// 前levels档的买卖量失衡 (bidQty-askQty)/(bidQty+askQty), 范围[-1,1]
+// levels <= 0 表示统计本地全部档位
+func (ob *OrderBook) Imbalance(levels int) decimal.Decimal {
+	bidQty, askQty := decimal.Zero, decimal.Zero
+	for i, v := range ob.Bids {
+		if levels > 0 && i >= levels {
+			break
+		}
+		bidQty = bidQty.Add(v.Quantity)
+	}
+	for i, v := range ob.Asks {
+		if levels > 0 && i >= levels {
+			break
+		}
+		askQty = askQty.Add(v.Quantity)
+	}
+	total := bidQty.Add(askQty)
+	if total.IsZero() {
+		return decimal.Zero
+	}
+	return bidQty.Sub(askQty).Div(total)
+}
+
+// 解析 orderbook@BTCUSDT:100,ETHUSDT 中的单个 SYMBOL:DEPTH, DEPTH缺省为0
+func parseOrderBookSymbol(v string) (string, int) {
+	symbol, depth, _ := strings.Cut(v, ":")
+	level, _ := strconv.Atoi(depth)
+	if level < 0 {
+		level = 0
+	}
+	return strings.ToUpper(symbol), level
+}
+
+// 本地维护的价格档位, 保留交易所的原始字符串用于checksum
+type obLevel struct {
+	price decimal.Decimal
+	qty   decimal.Decimal
+	px    string
+	sz    string
+}
+
+// 快照+增量同步的增量数据 (binance/gate)
+type obDiff struct {
+	firstId int64 // U
+	lastId  int64 // u
+	time    int64
+	bids    [][2]string
+	asks    [][2]string
+}
+
+// rest快照, 由拉取快照的goroutine交给ws loop
+type obSnapshot struct {
+	lastId int64
+	bids   [][2]string
+	asks   [][2]string
+	err    error
+}
+
+const obMaxPendingDiff = 1024
+
+// 只在ws loop所在的goroutine中读写 (snapCh除外)
+type localOrderBook struct {
+	symbol   string
+	level    int
+	seqId    int64
+	time     int64
+	synced   bool
+	snapTime int64 // 上次拉取快照的时间 msec
+	fetching bool  // 正在拉取快照
+	snapCh   chan *obSnapshot
+	pending  []*obDiff
+	bids     *treemap.Map[decimal.Decimal, obLevel]
+	asks     *treemap.Map[decimal.Decimal, obLevel]
+}
+
+func newLocalOrderBook(symbol string, level int) *localOrderBook {
+	return &localOrderBook{
+		symbol: symbol,
+		level:  level,
+		snapCh: make(chan *obSnapshot, 1),
+		bids: treemap.NewWith[decimal.Decimal, obLevel](func(a, b decimal.Decimal) int {
+			return b.Compare(a) // desc
+		}),
+		asks: treemap.NewWith[decimal.Decimal, obLevel](func(a, b decimal.Decimal) int {
+			return a.Compare(b) // asc
+		}),
+	}
+}
+func (ob *localOrderBook) reset() {
+	ob.bids.Clear()
+	ob.asks.Clear()
+	ob.seqId = 0
+	ob.time = 0
+	ob.synced = false
+	ob.pending = ob.pending[:0]
+}
+
+// qty为0表示删除该档位
+func (ob *localOrderBook) set(isBid bool, px, sz string) {
+	price, err := decimal.NewFromString(px)
+	if err != nil {
+		return
+	}
+	qty, err := decimal.NewFromString(sz)
+	if err != nil {
+		return
+	}
+	side := ob.asks
+	if isBid {
+		side = ob.bids
+	}
+	if qty.IsZero() {
+		side.Remove(price)
+	} else {
+		side.Put(price, obLevel{price: price, qty: qty, px: px, sz: sz})
+	}
+}
+func (ob *localOrderBook) setLevels(bids, asks [][2]string) {
+	for _, v := range bids {
+		ob.set(true, v[0], v[1])
+	}
+	for _, v := range asks {
+		ob.set(false, v[0], v[1])
+	}
+}
+
+// 只保留前n档 (kraken这类只推送订阅档位内变化的交易所需要)
+func (ob *localOrderBook) truncate(n int) {
+	for _, side := range []*treemap.Map[decimal.Decimal, obLevel]{ob.bids, ob.asks} {
+		for side.Size() > n {
+			k, _, _ := side.Max()
+			side.Remove(k)
+		}
+	}
+}
+
+// 买1>=卖1 说明本地数据已经错乱
+func (ob *localOrderBook) crossed() bool {
+	bid, _, ok0 := ob.bids.Min()
+	ask, _, ok1 := ob.asks.Min()
+	return ok0 && ok1 && bid.GreaterThanOrEqual(ask)
+}
+
+// 前n档的原始数据, n<=0 返回全部
+func (ob *localOrderBook) top(isBid bool, n int) []obLevel {
+	side := ob.asks
+	if isBid {
+		side = ob.bids
+	}
+	if n <= 0 || n > side.Size() {
+		n = side.Size()
+	}
+	l := make([]obLevel, 0, n)
+	it := side.Iterator()
+	for i := 0; i < n && it.Next(); i++ {
+		l = append(l, it.Value())
+	}
+	return l
+}
+
+// 快照+增量同步: 增量先缓存, 返回false表示没有更新本地数据
+// 调用方检查 ob.synced == false 时需要拉取快照
+func (ob *localOrderBook) applyDiff(d *obDiff) bool {
+	if !ob.synced {
+		if len(ob.pending) >= obMaxPendingDiff {
+			ob.pending = ob.pending[1:]
+		}
+		ob.pending = append(ob.pending, d)
+		return false
+	}
+	if d.lastId <= ob.seqId { // 旧数据
+		return false
+	}
+	if d.firstId > ob.seqId+1 { // 丢包了, 重新同步
+		ob.reset()
+		ob.pending = append(ob.pending, d)
+		return false
+	}
+	ob.setLevels(d.bids, d.asks)
+	ob.seqId = d.lastId
+	ob.time = d.time
+	if ob.crossed() {
+		ob.reset()
+		return false
+	}
+	return true
+}
+
+// 用rest快照重建本地数据, 再回放缓存的增量
+// 快照比缓存的增量还旧时返回false, 等待下次重新拉取
+func (ob *localOrderBook) applySnapshot(lastId int64, bids, asks [][2]string) bool {
+	pending := ob.pending
+	ob.reset()
+	ob.setLevels(bids, asks)
+	ob.seqId = lastId
+	for i, d := range pending {
+		if d.lastId <= ob.seqId {
+			continue
+		}
+		if d.firstId > ob.seqId+1 {
+			ob.reset()
+			ob.pending = append(ob.pending, pending[i:]...)
+			return false
+		}
+		ob.setLevels(d.bids, d.asks)
+		ob.seqId = d.lastId
+		ob.time = d.time
+	}
+	if ob.crossed() {
+		ob.reset()
+		return false
+	}
+	ob.synced = true
+	return true
+}
+
+// 未同步时在新的goroutine中拉取rest快照, 不阻塞ws loop, 期间的增量继续缓存
+// 同一时间只有一个拉取, 两次拉取至少间隔1秒
+func (ob *localOrderBook) fetchSnapshot(fetch func() (int64, [][2]string, [][2]string, error)) {
+	if ob.synced || ob.fetching {
+		return
+	}
+	now := time.Now().UnixMilli()
+	if now-ob.snapTime < 1000 {
+		return
+	}
+	ob.snapTime = now
+	ob.fetching = true
+	snapCh := ob.snapCh
+	go func() {
+		lastId, bids, asks, err := fetch()
+		snapCh <- &obSnapshot{lastId: lastId, bids: bids, asks: asks, err: err}
+	}()
+}
+
+// 取出已拉取完成的快照并回放缓存的增量, 返回true表示本地数据已同步
+func (ob *localOrderBook) pollSnapshot() (bool, error) {
+	select {
+	case snap := <-ob.snapCh:
+		ob.fetching = false
+		if snap.err != nil {
+			return false, snap.err
+		}
+		return ob.applySnapshot(snap.lastId, snap.bids, snap.asks), nil
+	default:
+	}
+	return false, nil
+}
+
+// 生成推送给调用方的快照, 按订阅档位截取
+func (ob *localOrderBook) snapshot() *OrderBook {
+	bids := ob.top(true, ob.level)
+	asks := ob.top(false, ob.level)
+	v := &OrderBook{
+		Symbol:   ob.symbol,
+		Level:    ob.level,
+		UpdateId: ob.seqId,
+		Time:     ob.time,
+		Bids:     make([]Ticker, 0, len(bids)),
+		Asks:     make([]Ticker, 0, len(asks)),
+	}
+	for _, l := range bids {
+		v.Bids = append(v.Bids, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	for _, l := range asks {
+		v.Asks = append(v.Asks, Ticker{Price: l.price, Quantity: l.qty})
+	}
+	return v
+}
+
+// 订阅时写, ws loop中读
+type localOrderBooks struct {
+	mtx   sync.Mutex
+	books map[string]*localOrderBook
+}
+
+func (lb *localOrderBooks) add(symbol string, level int) {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+	if lb.books == nil {
+		lb.books = make(map[string]*localOrderBook, 8)
+	}
+	lb.books[symbol] = newLocalOrderBook(symbol, level)
+}
+func (lb *localOrderBooks) remove(symbol string) {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+	delete(lb.books, symbol)
+}
+func (lb *localOrderBooks) get(symbol string) *localOrderBook {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+	return lb.books[symbol]
+}
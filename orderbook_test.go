@@ -0,0 +1,282 @@
+package cex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+func obLevels(ob *localOrderBook, isBid bool) []string {
+	l := make([]string, 0, 8)
+	for _, v := range ob.top(isBid, 0) {
+		l = append(l, v.px+":"+v.sz)
+	}
+	return l
+}
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+func TestLocalOrderBookSync(t *testing.T) {
+	cases := []struct {
+		name     string
+		diffs    []*obDiff // 快照前缓存的增量
+		lastId   int64
+		bids     [][2]string
+		asks     [][2]string
+		synced   bool
+		seqId    int64
+		wantBids []string
+		wantAsks []string
+	}{
+		{
+			name:     "no pending",
+			lastId:   100,
+			bids:     [][2]string{{"10", "1"}, {"9", "2"}},
+			asks:     [][2]string{{"11", "1"}, {"12", "2"}},
+			synced:   true,
+			seqId:    100,
+			wantBids: []string{"10:1", "9:2"},
+			wantAsks: []string{"11:1", "12:2"},
+		},
+		{
+			name: "replay pending, skip older",
+			diffs: []*obDiff{
+				{firstId: 90, lastId: 95, bids: [][2]string{{"8", "5"}}},                                   // 比快照旧
+				{firstId: 96, lastId: 102, bids: [][2]string{{"10", "0"}}, asks: [][2]string{{"11", "3"}}}, // 跨过快照
+				{firstId: 103, lastId: 104, bids: [][2]string{{"9.5", "4"}}},
+			},
+			lastId:   100,
+			bids:     [][2]string{{"10", "1"}, {"9", "2"}},
+			asks:     [][2]string{{"11", "1"}, {"12", "2"}},
+			synced:   true,
+			seqId:    104,
+			wantBids: []string{"9.5:4", "9:2"},
+			wantAsks: []string{"11:3", "12:2"},
+		},
+		{
+			name: "snapshot older than pending",
+			diffs: []*obDiff{
+				{firstId: 105, lastId: 110, bids: [][2]string{{"10", "3"}}},
+			},
+			lastId: 100,
+			bids:   [][2]string{{"10", "1"}},
+			asks:   [][2]string{{"11", "1"}},
+			synced: false,
+		},
+		{
+			name:   "crossed snapshot",
+			lastId: 100,
+			bids:   [][2]string{{"11", "1"}},
+			asks:   [][2]string{{"10", "1"}},
+			synced: false,
+		},
+	}
+	for _, c := range cases {
+		ob := newLocalOrderBook("BTCUSDT", 0)
+		for _, d := range c.diffs {
+			if ob.applyDiff(d) {
+				t.Fatalf("%s: diff applied before snapshot", c.name)
+			}
+		}
+		if got := ob.applySnapshot(c.lastId, c.bids, c.asks); got != c.synced || ob.synced != c.synced {
+			t.Fatalf("%s: want synced %v got %v", c.name, c.synced, got)
+		}
+		if !c.synced {
+			if c.diffs != nil && len(ob.pending) == 0 {
+				t.Errorf("%s: pending diffs dropped", c.name)
+			}
+			continue
+		}
+		if ob.seqId != c.seqId {
+			t.Errorf("%s: want seqId %d got %d", c.name, c.seqId, ob.seqId)
+		}
+		if got := obLevels(ob, true); !equalStrings(got, c.wantBids) {
+			t.Errorf("%s: want bids %v got %v", c.name, c.wantBids, got)
+		}
+		if got := obLevels(ob, false); !equalStrings(got, c.wantAsks) {
+			t.Errorf("%s: want asks %v got %v", c.name, c.wantAsks, got)
+		}
+	}
+}
+func TestLocalOrderBookSeqGap(t *testing.T) {
+	ob := newLocalOrderBook("BTCUSDT", 1)
+	if !ob.applySnapshot(100, [][2]string{{"10", "1"}, {"9", "1"}}, [][2]string{{"11", "1"}}) {
+		t.Fatal("snapshot not applied")
+	}
+	if ob.applyDiff(&obDiff{firstId: 90, lastId: 100}) {
+		t.Fatal("old diff should be ignored")
+	}
+	if !ob.applyDiff(&obDiff{firstId: 101, lastId: 101, bids: [][2]string{{"10", "2"}}}) {
+		t.Fatal("continuous diff not applied")
+	}
+	if s := ob.snapshot(); len(s.Bids) != 1 || !s.Bids[0].Quantity.Equal(dec("2")) || s.UpdateId != 101 {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
+	// 丢了102
+	if ob.applyDiff(&obDiff{firstId: 103, lastId: 104}) {
+		t.Fatal("gap diff should not be applied")
+	}
+	if ob.synced || ob.bids.Size() != 0 || len(ob.pending) != 1 {
+		t.Fatalf("want reset with 1 pending, synced=%v bids=%d pending=%d", ob.synced, ob.bids.Size(), len(ob.pending))
+	}
+	// 增量造成买1>=卖1也需要重新同步
+	ob.applySnapshot(104, [][2]string{{"10", "1"}}, [][2]string{{"11", "1"}})
+	if ob.applyDiff(&obDiff{firstId: 105, lastId: 105, bids: [][2]string{{"12", "1"}}}) || ob.synced {
+		t.Fatal("crossed book should resync")
+	}
+}
+func TestLocalOrderBookAsyncSnapshot(t *testing.T) {
+	ob := newLocalOrderBook("BTCUSDT", 0)
+	release := make(chan struct{})
+	calls := 0
+	fetch := func() (int64, [][2]string, [][2]string, error) {
+		calls++
+		<-release
+		return 100, [][2]string{{"10", "1"}}, [][2]string{{"11", "1"}}, nil
+	}
+	ob.applyDiff(&obDiff{firstId: 99, lastId: 101, bids: [][2]string{{"10", "5"}}})
+	ob.fetchSnapshot(fetch)
+	ob.fetchSnapshot(fetch) // 正在拉取, 不重复
+	// 拉取期间增量继续缓存, 不阻塞
+	ob.applyDiff(&obDiff{firstId: 102, lastId: 102, asks: [][2]string{{"11", "2"}}})
+	if synced, _ := ob.pollSnapshot(); synced {
+		t.Fatal("snapshot not fetched yet")
+	}
+	close(release)
+	var synced bool
+	var err error
+	for range 100 {
+		if synced, err = ob.pollSnapshot(); synced || err != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !synced || err != nil {
+		t.Fatalf("want synced, got %v %v", synced, err)
+	}
+	if calls != 1 {
+		t.Fatalf("want 1 fetch got %d", calls)
+	}
+	if ob.seqId != 102 || !equalStrings(obLevels(ob, true), []string{"10:5"}) ||
+		!equalStrings(obLevels(ob, false), []string{"11:2"}) {
+		t.Fatalf("pending diffs not replayed: seq=%d bids=%v asks=%v", ob.seqId, obLevels(ob, true), obLevels(ob, false))
+	}
+
+	// 拉取失败后间隔1秒才重试
+	ob.reset()
+	ob.snapTime = 0
+	ob.fetchSnapshot(func() (int64, [][2]string, [][2]string, error) {
+		return 0, nil, nil, errors.New("timeout")
+	})
+	for range 100 {
+		if _, err = ob.pollSnapshot(); err != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err == nil || ob.fetching {
+		t.Fatalf("want fetch error, got %v fetching=%v", err, ob.fetching)
+	}
+	ob.fetchSnapshot(fetch)
+	if ob.fetching {
+		t.Fatal("refetch should be rate limited")
+	}
+}
+func TestOkxOrderBookChecksum(t *testing.T) {
+	ok := &Okx{}
+	ob := newLocalOrderBook("BTCUSDT", 0)
+	// okx文档中的例子: 3366.1:7:3366.8:9:3366:6:3368:8
+	ob.setLevels([][2]string{{"3366.1", "7"}, {"3366", "6"}}, [][2]string{{"3366.8", "9"}, {"3368", "8"}})
+	if got := ok.orderBookChecksum(ob); got != -1881014294 {
+		t.Errorf("want -1881014294 got %d", got)
+	}
+	// 卖盘不足时只拼买盘: 3366.1:7:3366.8:9:3366:6
+	ob.set(false, "3368", "0")
+	if got := ok.orderBookChecksum(ob); got != 1164732920 {
+		t.Errorf("want 1164732920 got %d", got)
+	}
+}
+func TestBinanceOrderBookLimit(t *testing.T) {
+	cases := map[int]string{0: "5000", 5: "100", 50: "100", 51: "500", 250: "500", 400: "1000", 500: "1000", 501: "5000", 2000: "5000"}
+	for level, want := range cases {
+		if got := bnSpotOrderBookLimit(level); got != want {
+			t.Errorf("level %d: want %s got %s", level, want, got)
+		}
+	}
+}
+func TestOrderBookAnalytics(t *testing.T) {
+	ob := &OrderBook{
+		Bids: []Ticker{{Price: dec("100"), Quantity: dec("1")}, {Price: dec("99"), Quantity: dec("2")}, {Price: dec("98"), Quantity: dec("3")}},
+		Asks: []Ticker{{Price: dec("101"), Quantity: dec("1")}, {Price: dec("102"), Quantity: dec("1")}, {Price: dec("103"), Quantity: dec("4")}},
+	}
+	vwap := []struct {
+		side string
+		qty  string
+		want string
+		ok   bool
+	}{
+		{"BUY", "1", "101", true},
+		{"BUY", "2", "101.5", true},
+		{"BUY", "4", "102.25", true}, // 101+102+103*2
+		{"SELL", "3", "99.3333333333333333", true},
+		{"BUY", "7", "0", false},
+		{"SELL", "0", "0", false},
+	}
+	for _, c := range vwap {
+		got, ok := ob.VWAP(c.side, dec(c.qty))
+		if ok != c.ok || !got.Equal(dec(c.want)) {
+			t.Errorf("VWAP %s %s: want %s,%v got %s,%v", c.side, c.qty, c.want, c.ok, got, ok)
+		}
+	}
+	imbalance := []struct {
+		levels int
+		want   string
+	}{
+		{1, "0"},
+		{2, "0.2"},  // (3-2)/5
+		{0, "0.0"},  // (6-6)/12
+		{10, "0.0"}, // 超过档位数
+	}
+	for _, c := range imbalance {
+		if got := ob.Imbalance(c.levels); !got.Equal(dec(c.want)) {
+			t.Errorf("Imbalance %d: want %s got %s", c.levels, c.want, got)
+		}
+	}
+	depth := []struct {
+		price string
+		want  string
+	}{
+		{"100", "1"},
+		{"99", "3"},
+		{"98.5", "3"},
+		{"90", "6"},
+		{"100.5", "0"}, // 盘口中间
+		{"101", "1"},
+		{"102.5", "2"},
+		{"200", "6"},
+	}
+	for _, c := range depth {
+		if got := ob.DepthAt(dec(c.price)); !got.Equal(dec(c.want)) {
+			t.Errorf("DepthAt %s: want %s got %s", c.price, c.want, got)
+		}
+	}
+	if !ob.MidPrice().Equal(dec("100.5")) || !ob.Spread().Equal(dec("1")) {
+		t.Errorf("mid %s spread %s", ob.MidPrice(), ob.Spread())
+	}
+	if !ob.QtyAt(dec("99")).Equal(dec("2")) || !ob.QtyAt(dec("103")).Equal(dec("4")) || !ob.QtyAt(dec("100.5")).IsZero() {
+		t.Error("QtyAt")
+	}
+}
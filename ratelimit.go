@@ -0,0 +1,433 @@
+package cex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 请求分类(endpoint family)
+const (
+	RateLimitPublic = "public" // 无签名的行情接口
+	RateLimitQuery  = "query"  // 签名的查询接口
+	RateLimitOrder  = "order"  // 下单/撤单等写接口
+)
+
+type RateLimitRule struct {
+	Limit  int           // 窗口内允许的最大权重
+	Window time.Duration // 窗口长度
+}
+
+type RateLimitConfig struct {
+	// key: RateLimitPublic/RateLimitQuery/RateLimitOrder 或Classify返回的自定义分类(如okx按接口), 没有配置的分类不限频
+	Rules map[string]RateLimitRule
+	// true: 超限直接返回 ErrRateLimited 分类的错误; false: 阻塞等待(可被ctx取消)
+	FailFast bool
+
+	// 交易所响应头中的已用权重(如binance的X-MBX-USED-WEIGHT-1M), 按host统计
+	// 达到UsedWeightLimit后本窗口内的请求都会被拦截
+	UsedWeightHeader string
+	UsedWeightLimit  int
+	UsedWeightWindow time.Duration
+
+	// 返回请求的分类和权重. nil时: 非GET为order, 带签名头的GET为query, 其它为public, 权重都为1
+	Classify func(method string, u *url.URL, signed bool) (family string, weight int)
+}
+
+type rateBucket struct {
+	capacity float64
+	tokens   float64
+	rate     float64 // 每秒恢复的权重
+	last     time.Time
+}
+
+// 以服务端返回的已用权重为准
+type rateUsedWeight struct {
+	used        int
+	windowStart time.Time
+}
+
+type rateLimiter struct {
+	name string
+
+	mtx         sync.Mutex
+	cfg         *RateLimitConfig
+	buckets     map[string]*rateBucket
+	usedWeight  map[string]*rateUsedWeight // host -> used
+	pausedUntil time.Time                  // 收到429/418后暂停所有请求
+}
+
+var (
+	rateLimiters    map[string]*rateLimiter
+	rateLimitersMtx sync.Mutex
+)
+
+func init() {
+	rateLimiters = make(map[string]*rateLimiter)
+
+	SetRateLimit("binance", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitOrder: {Limit: 50, Window: 10 * time.Second},
+		},
+		UsedWeightHeader: "X-Mbx-Used-Weight-1m",
+		UsedWeightLimit:  1200,
+		UsedWeightWindow: time.Minute,
+		Classify:         bnRateLimitClassify,
+	})
+	okRules := map[string]RateLimitRule{
+		RateLimitPublic: {Limit: 20, Window: 2 * time.Second},
+		RateLimitQuery:  {Limit: 20, Window: 2 * time.Second},
+		RateLimitOrder:  {Limit: 60, Window: 2 * time.Second},
+	}
+	for k, v := range okRateLimitRules {
+		okRules[k] = v
+	}
+	SetRateLimit("okx", &RateLimitConfig{
+		Rules:    okRules,
+		Classify: okRateLimitClassify,
+	})
+	SetRateLimit("gate", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitPublic: {Limit: 200, Window: 10 * time.Second},
+			RateLimitQuery:  {Limit: 200, Window: 10 * time.Second},
+			RateLimitOrder:  {Limit: 10, Window: time.Second},
+		},
+	})
+	SetRateLimit("bybit", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitPublic: {Limit: 600, Window: 5 * time.Second},
+			RateLimitQuery:  {Limit: 10, Window: time.Second},
+			RateLimitOrder:  {Limit: 10, Window: time.Second},
+		},
+	})
+	SetRateLimit("bitget", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitPublic: {Limit: 20, Window: time.Second},
+			RateLimitQuery:  {Limit: 10, Window: time.Second},
+			RateLimitOrder:  {Limit: 10, Window: time.Second},
+		},
+	})
+	SetRateLimit("htx", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitPublic: {Limit: 100, Window: time.Second},
+			RateLimitQuery:  {Limit: 100, Window: 10 * time.Second}, // 按UID
+			RateLimitOrder:  {Limit: 100, Window: 2 * time.Second},
+		},
+	})
+	SetRateLimit("kraken", &RateLimitConfig{
+		Rules: map[string]RateLimitRule{
+			RateLimitPublic: {Limit: 1, Window: time.Second},
+			RateLimitQuery:  {Limit: 15, Window: 45 * time.Second}, // 计数器每秒衰减0.33
+			RateLimitOrder:  {Limit: 60, Window: 10 * time.Second},
+		},
+		Classify: kkRateLimitClassify,
+	})
+}
+
+// 设置交易所的限频规则, 对已创建的对象同样生效. cfg=nil 表示不限频
+// 同一交易所的所有对象共享一份计数(交易所一般按IP/UID限频)
+func SetRateLimit(cexName string, cfg *RateLimitConfig) {
+	rl := getRateLimiter(cexName)
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	rl.cfg = cfg
+	rl.buckets = make(map[string]*rateBucket)
+	rl.usedWeight = make(map[string]*rateUsedWeight)
+	if cfg == nil {
+		return
+	}
+	now := time.Now()
+	for family, rule := range cfg.Rules {
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			continue
+		}
+		rl.buckets[family] = &rateBucket{
+			capacity: float64(rule.Limit),
+			tokens:   float64(rule.Limit),
+			rate:     float64(rule.Limit) / rule.Window.Seconds(),
+			last:     now,
+		}
+	}
+}
+func getRateLimiter(cexName string) *rateLimiter {
+	rateLimitersMtx.Lock()
+	defer rateLimitersMtx.Unlock()
+	rl := rateLimiters[cexName]
+	if rl == nil {
+		rl = &rateLimiter{
+			name:       cexName,
+			buckets:    make(map[string]*rateBucket),
+			usedWeight: make(map[string]*rateUsedWeight),
+		}
+		rateLimiters[cexName] = rl
+	}
+	return rl
+}
+
+// 请求前调用, 需要等待时阻塞(FailFast时直接返回错误)
+func (rl *rateLimiter) wait(ctx context.Context, method string, u *url.URL, signed bool) error {
+	for {
+		delay, err := rl.reserve(method, u, signed)
+		if err != nil || delay <= 0 {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &Error{Exchange: rl.name, Category: CategoryRateLimited,
+				Msg: "rate limit wait canceled: " + ctx.Err().Error()}
+		case <-timer.C:
+		}
+	}
+}
+
+// 返回需要等待的时间, 为0时已扣除本次权重
+func (rl *rateLimiter) reserve(method string, u *url.URL, signed bool) (time.Duration, error) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	cfg := rl.cfg
+	if cfg == nil {
+		return 0, nil
+	}
+	family, weight := rateLimitDefaultClassify(method, u, signed)
+	if cfg.Classify != nil {
+		family, weight = cfg.Classify(method, u, signed)
+	}
+	now := time.Now()
+
+	var delay time.Duration
+	if now.Before(rl.pausedUntil) {
+		delay = rl.pausedUntil.Sub(now)
+	}
+	if cfg.UsedWeightHeader != "" && cfg.UsedWeightLimit > 0 {
+		if uw := rl.usedWeight[u.Host]; uw != nil {
+			windowEnd := uw.windowStart.Add(cfg.UsedWeightWindow)
+			if now.Before(windowEnd) && uw.used+weight > cfg.UsedWeightLimit {
+				delay = max(delay, windowEnd.Sub(now))
+			}
+		}
+	}
+	b := rl.buckets[family]
+	if b != nil {
+		b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if w := float64(weight); b.tokens < w && w <= b.capacity {
+			delay = max(delay, time.Duration((w-b.tokens)/b.rate*float64(time.Second)))
+		}
+	}
+	if delay > 0 {
+		if cfg.FailFast {
+			return 0, &Error{Exchange: rl.name, Category: CategoryRateLimited,
+				Msg: "local rate limit exceeded: " + family + ", retry after " + delay.String()}
+		}
+		return delay, nil
+	}
+	if b != nil {
+		b.tokens -= float64(weight)
+	}
+	if uw := rl.usedWeight[u.Host]; uw != nil {
+		uw.used += weight // 在收到响应头之前先本地累计
+	}
+	return 0, nil
+}
+
+// 响应后调用, 同步服务端的已用权重和封禁时间
+func (rl *rateLimiter) update(u *url.URL, status int, header http.Header) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	cfg := rl.cfg
+	if cfg == nil {
+		return
+	}
+	now := time.Now()
+	if cfg.UsedWeightHeader != "" && cfg.UsedWeightWindow > 0 {
+		if v := header.Get(cfg.UsedWeightHeader); v != "" {
+			if used, err := strconv.Atoi(v); err == nil {
+				rl.usedWeight[u.Host] = &rateUsedWeight{
+					used:        used,
+					windowStart: now.Truncate(cfg.UsedWeightWindow),
+				}
+			}
+		}
+	}
+	if status == http.StatusTooManyRequests || status == 418 { // binance 418 为IP被封
+		pause := time.Minute
+		if cfg.UsedWeightWindow > 0 {
+			pause = cfg.UsedWeightWindow
+		}
+		if ra, err := strconv.Atoi(header.Get("Retry-After")); err == nil && ra > 0 {
+			pause = time.Duration(ra) * time.Second
+		}
+		if until := now.Add(pause); until.After(rl.pausedUntil) {
+			rl.pausedUntil = until
+		}
+	}
+}
+
+func rateLimitDefaultClassify(method string, u *url.URL, signed bool) (string, int) {
+	if method != http.MethodGet {
+		return RateLimitOrder, 1
+	}
+	if signed {
+		return RateLimitQuery, 1
+	}
+	return RateLimitPublic, 1
+}
+
+// okx 按接口限频, key为 "METHOD path", 未列出的接口按分类限频
+// 批量接口按订单数计入对应限额, 这里按请求计
+var okRateLimitRules = map[string]RateLimitRule{
+	"POST /api/v5/trade/order":                        {Limit: 60, Window: 2 * time.Second},
+	"POST /api/v5/trade/batch-orders":                 {Limit: 300, Window: 2 * time.Second},
+	"POST /api/v5/trade/cancel-order":                 {Limit: 60, Window: 2 * time.Second},
+	"POST /api/v5/trade/cancel-batch-orders":          {Limit: 300, Window: 2 * time.Second},
+	"POST /api/v5/trade/amend-order":                  {Limit: 60, Window: 2 * time.Second},
+	"POST /api/v5/trade/amend-batch-orders":           {Limit: 300, Window: 2 * time.Second},
+	"POST /api/v5/trade/order-algo":                   {Limit: 20, Window: 2 * time.Second},
+	"POST /api/v5/trade/cancel-algos":                 {Limit: 20, Window: 2 * time.Second},
+	"POST /api/v5/trade/cancel-all-after":             {Limit: 1, Window: time.Second},
+	"GET /api/v5/trade/order":                         {Limit: 60, Window: 2 * time.Second},
+	"GET /api/v5/trade/orders-pending":                {Limit: 60, Window: 2 * time.Second},
+	"GET /api/v5/trade/orders-history":                {Limit: 40, Window: 2 * time.Second},
+	"GET /api/v5/trade/orders-algo-pending":           {Limit: 20, Window: 2 * time.Second},
+	"GET /api/v5/trade/fills":                         {Limit: 60, Window: 2 * time.Second},
+	"GET /api/v5/account/balance":                     {Limit: 10, Window: 2 * time.Second},
+	"GET /api/v5/account/positions":                   {Limit: 10, Window: 2 * time.Second},
+	"GET /api/v5/account/config":                      {Limit: 5, Window: 2 * time.Second},
+	"POST /api/v5/account/set-leverage":               {Limit: 20, Window: 2 * time.Second},
+	"POST /api/v5/account/set-position-mode":          {Limit: 5, Window: 2 * time.Second},
+	"GET /api/v5/market/tickers":                      {Limit: 20, Window: 2 * time.Second},
+	"GET /api/v5/market/ticker":                       {Limit: 20, Window: 2 * time.Second},
+	"GET /api/v5/market/books":                        {Limit: 40, Window: 2 * time.Second},
+	"GET /api/v5/market/candles":                      {Limit: 40, Window: 2 * time.Second},
+	"GET /api/v5/public/instruments":                  {Limit: 20, Window: 2 * time.Second},
+	"GET /api/v5/public/time":                         {Limit: 10, Window: 2 * time.Second},
+	"GET /api/v5/public/funding-rate":                 {Limit: 20, Window: 2 * time.Second},
+	"GET /api/v5/public/funding-rate-history":         {Limit: 10, Window: 2 * time.Second},
+	"GET /api/v5/public/mark-price":                   {Limit: 10, Window: 2 * time.Second},
+	"GET /api/v5/asset/balances":                      {Limit: 6, Window: time.Second},
+	"GET /api/v5/asset/currencies":                    {Limit: 6, Window: time.Second},
+	"GET /api/v5/asset/deposit-address":               {Limit: 6, Window: time.Second},
+	"GET /api/v5/asset/deposit-history":               {Limit: 6, Window: time.Second},
+	"GET /api/v5/asset/withdrawal-history":            {Limit: 6, Window: time.Second},
+	"POST /api/v5/asset/withdrawal":                   {Limit: 6, Window: time.Second},
+	"POST /api/v5/asset/cancel-withdrawal":            {Limit: 6, Window: time.Second},
+	"POST /api/v5/asset/transfer":                     {Limit: 2, Window: time.Second},
+	"GET /api/v5/users/subaccount/list":               {Limit: 2, Window: 2 * time.Second},
+	"POST /api/v5/users/subaccount/create-subaccount": {Limit: 1, Window: 2 * time.Second},
+}
+
+func okRateLimitClassify(method string, u *url.URL, signed bool) (string, int) {
+	if k := method + " " + u.Path; okRateLimitRules[k].Limit > 0 {
+		return k, 1
+	}
+	return rateLimitDefaultClassify(method, u, signed)
+}
+
+// kraken 私有接口都是POST, 只有下单/撤单计入order, 其它计入查询计数器
+// 账本/成交历史每次计2
+func kkRateLimitClassify(method string, u *url.URL, signed bool) (string, int) {
+	name, ok := strings.CutPrefix(u.Path, "/0/private/")
+	if !ok {
+		return RateLimitPublic, 1
+	}
+	switch name {
+	case "AddOrder", "AddOrderBatch", "EditOrder", "AmendOrder",
+		"CancelOrder", "CancelOrderBatch", "CancelAll", "CancelAllOrdersAfter":
+		return RateLimitOrder, 1
+	case "Ledgers", "QueryLedgers", "TradesHistory", "QueryTrades":
+		return RateLimitQuery, 2
+	}
+	return RateLimitQuery, 1
+}
+
+// binance 各接口权重, 参考官方文档 REQUEST_WEIGHT
+var bnRequestWeight = map[string]int{
+	"/api/v3/exchangeInfo":       20,
+	"/api/v3/account":            20,
+	"/api/v3/account/commission": 20,
+	"/api/v3/order":              4,
+	"/api/v3/ticker/bookTicker":  2,
+	"/fapi/v1/ticker/24hr":       1,
+	"/fapi/v2/account":           5,
+	"/fapi/v3/account":           5,
+	"/fapi/v2/positionRisk":      5,
+	"/fapi/v3/positionRisk":      5,
+	"/fapi/v1/premiumIndex":      1,
+	"/fapi/v1/klines":            5,
+	"/dapi/v1/klines":            5,
+}
+
+func bnRateLimitClassify(method string, u *url.URL, signed bool) (string, int) {
+	family, _ := rateLimitDefaultClassify(method, u, signed)
+	path := u.Path
+	if family == RateLimitOrder && !strings.HasSuffix(path, "/order") &&
+		!strings.HasSuffix(path, "/batchOrders") && !strings.HasSuffix(path, "/openOrders") {
+		family = RateLimitQuery // 如 get-funding-asset 这类POST查询
+	}
+	if method != http.MethodGet {
+		return family, 1
+	}
+	noSymbol := u.Query().Get("symbol") == ""
+	switch path {
+	case "/api/v3/ticker/24hr":
+		if noSymbol {
+			return family, 80
+		}
+		return family, 2
+	case "/api/v3/openOrders":
+		if noSymbol {
+			return family, 80
+		}
+		return family, 6
+	case "/fapi/v1/openOrders", "/dapi/v1/openOrders":
+		if noSymbol {
+			return family, 40
+		}
+		return family, 1
+	case "/fapi/v1/ticker/24hr", "/dapi/v1/ticker/24hr":
+		if noSymbol {
+			return family, 40
+		}
+		return family, 1
+	case "/api/v3/depth": // 缺省100档
+		limit, _ := strconv.Atoi(u.Query().Get("limit"))
+		switch {
+		case limit > 1000:
+			return family, 250
+		case limit > 500:
+			return family, 50
+		case limit > 100:
+			return family, 25
+		}
+		return family, 5
+	case "/fapi/v1/depth", "/dapi/v1/depth":
+		limit, _ := strconv.Atoi(u.Query().Get("limit"))
+		if limit == 0 {
+			limit = 500 // 缺省500档
+		}
+		switch {
+		case limit > 500:
+			return family, 20
+		case limit > 100:
+			return family, 10
+		case limit > 50:
+			return family, 5
+		}
+		return family, 2
+	case "/fapi/v1/premiumIndex", "/dapi/v1/premiumIndex":
+		if noSymbol {
+			return family, 10
+		}
+		return family, 1
+	}
+	if w, ok := bnRequestWeight[path]; ok {
+		return family, w
+	}
+	return family, 1
+}
@@ -0,0 +1,129 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitBucket(t *testing.T) {
+	name := "test-bucket"
+	SetRateLimit(name, &RateLimitConfig{
+		Rules:    map[string]RateLimitRule{RateLimitOrder: {Limit: 2, Window: 200 * time.Millisecond}},
+		FailFast: true,
+	})
+	rl := getRateLimiter(name)
+	u, _ := url.Parse("https://api.test.com/order")
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := rl.wait(ctx, "POST", u, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rl.wait(ctx, "POST", u, true); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited got %v", err)
+	}
+	// 其它分类没有配置, 不受影响
+	if err := rl.wait(ctx, "GET", u, false); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(120 * time.Millisecond) // 恢复1个令牌
+	if err := rl.wait(ctx, "POST", u, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// 阻塞模式
+	SetRateLimit(name, &RateLimitConfig{
+		Rules: map[string]RateLimitRule{RateLimitOrder: {Limit: 2, Window: 200 * time.Millisecond}},
+	})
+	rl.wait(ctx, "POST", u, true)
+	rl.wait(ctx, "POST", u, true)
+	start := time.Now()
+	if err := rl.wait(ctx, "POST", u, true); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Fatalf("want wait about 100ms got %s", d)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := rl.wait(ctx, "POST", u, true); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited after ctx done got %v", err)
+	}
+}
+func TestRateLimitUsedWeight(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "1195")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	old := getRateLimiter("binance").cfg
+	defer SetRateLimit("binance", old)
+	SetRateLimit("binance", &RateLimitConfig{
+		FailFast:         true,
+		UsedWeightHeader: "X-Mbx-Used-Weight-1m",
+		UsedWeightLimit:  1200,
+		UsedWeightWindow: time.Minute,
+		Classify:         bnRateLimitClassify,
+	})
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.SpotGetOpenOrders("BTCUSDT"); err != nil { // 权重6
+		t.Fatal(err)
+	}
+	// 服务端已用1195, 再请求6会超过1200
+	if _, err = ex.SpotGetOpenOrders("BTCUSDT"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited got %v", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("want 1 request sent got %d", n)
+	}
+}
+func TestRateLimitClassify(t *testing.T) {
+	cases := []struct {
+		classify func(string, *url.URL, bool) (string, int)
+		method   string
+		link     string
+		family   string
+		weight   int
+	}{
+		{kkRateLimitClassify, "POST", "/0/private/AddOrder", RateLimitOrder, 1},
+		{kkRateLimitClassify, "POST", "/0/private/CancelAll", RateLimitOrder, 1},
+		{kkRateLimitClassify, "POST", "/0/private/Balance", RateLimitQuery, 1},
+		{kkRateLimitClassify, "POST", "/0/private/WithdrawStatus", RateLimitQuery, 1},
+		{kkRateLimitClassify, "POST", "/0/private/Ledgers", RateLimitQuery, 2},
+		{kkRateLimitClassify, "GET", "/0/public/Depth", RateLimitPublic, 1},
+		{okRateLimitClassify, "POST", "/api/v5/trade/order", "POST /api/v5/trade/order", 1},
+		{okRateLimitClassify, "GET", "/api/v5/trade/order", "GET /api/v5/trade/order", 1},
+		{okRateLimitClassify, "GET", "/api/v5/asset/balances", "GET /api/v5/asset/balances", 1},
+		{okRateLimitClassify, "POST", "/api/v5/asset/unknown", RateLimitOrder, 1},
+		{bnRateLimitClassify, "GET", "/api/v3/depth?symbol=BTCUSDT&limit=5000", RateLimitPublic, 250},
+		{bnRateLimitClassify, "GET", "/api/v3/depth?symbol=BTCUSDT&limit=1000", RateLimitPublic, 50},
+		{bnRateLimitClassify, "GET", "/api/v3/depth?symbol=BTCUSDT", RateLimitPublic, 5},
+		{bnRateLimitClassify, "GET", "/fapi/v1/depth?symbol=BTCUSDT&limit=1000", RateLimitPublic, 20},
+		{bnRateLimitClassify, "GET", "/fapi/v1/depth?symbol=BTCUSDT", RateLimitPublic, 10},
+	}
+	for _, c := range cases {
+		u, _ := url.Parse("https://api.test.com" + c.link)
+		family, weight := c.classify(c.method, u, false)
+		if family != c.family || weight != c.weight {
+			t.Errorf("%s %s: want %s/%d got %s/%d", c.method, c.link, c.family, c.weight, family, weight)
+		}
+	}
+	// okx 每个接口都有规则
+	for k := range okRateLimitRules {
+		if _, ok := getRateLimiter("okx").buckets[k]; !ok {
+			t.Errorf("okx bucket %s not created", k)
+		}
+	}
+}
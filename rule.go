@@ -91,7 +91,7 @@ func spotUpdateExPairRule() {
 					spotExchangePairRule[cexName] = ret
 					spotExchangePairRuleMtx.Unlock()
 				} else if err != nil {
-					ilog.Warning("cex.rule.spotUpdateExPairRule: " + err.Error())
+					ilog.Warning("%s", "cex.rule.spotUpdateExPairRule: "+err.Error())
 				}
 			}
 		}(k)
@@ -148,7 +148,7 @@ func futuresUpdateExPairRule() {
 						futuresExchangePairRule[cexName] = ol
 						futuresExchangePairRuleMtx.Unlock()
 					} else if err != nil {
-						ilog.Warning("cex.rule.FuturesUpdateExPairRule:UM " + err.Error())
+						ilog.Warning("%s", "cex.rule.FuturesUpdateExPairRule:UM "+err.Error())
 					}
 				}
 				if co.FuturesSupported("CM") {
@@ -163,7 +163,7 @@ func futuresUpdateExPairRule() {
 						futuresExchangePairRule[cexName] = ol
 						futuresExchangePairRuleMtx.Unlock()
 					} else if err != nil {
-						ilog.Warning("cex.rule.FuturesUpdateExPairRule:CM " + err.Error())
+						ilog.Warning("%s", "cex.rule.FuturesUpdateExPairRule:CM "+err.Error())
 					}
 				}
 			}
@@ -1,6 +1,7 @@
 package cex
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/shopspring/decimal"
@@ -11,6 +12,7 @@ var (
 	wsPublicOrderBook5Pool *sync.Pool
 	wsPublicBBOPool        *sync.Pool
 	wsPublicTradePool      *sync.Pool
+	wsPublicKLinePool      *sync.Pool
 )
 
 func init() {
@@ -35,6 +37,11 @@ func init() {
 			return &PublicTrade{}
 		},
 	}
+	wsPublicKLinePool = &sync.Pool{
+		New: func() any {
+			return &KLine{}
+		},
+	}
 }
 
 type SpotExchangePairRule struct {
@@ -199,6 +206,20 @@ type SpotPostOrder struct {
 	Qty         decimal.Decimal
 	Amt         decimal.Decimal
 }
+
+// 批量撤单参数, orderId, cltId 二选一
+type CancelOrderArg struct {
+	Symbol   string // BTCUSDT
+	OrderId  string
+	ClientId string
+}
+
+// 批量下单/撤单结果, 与请求顺序一一对应
+type BatchOrderResult struct {
+	OrderId  string
+	ClientId string
+	Err      error // 单个订单的错误
+}
 type SpotOrder struct {
 	RequestId string // for ws 不要超过28, 在SpotWsPlaceOrder成功后返回
 	Err       string // for ws
@@ -254,6 +275,18 @@ type Pub24hTicker struct {
 	QuoteVolume decimal.Decimal // futures-UM 24小时成交额
 }
 
+type FuturesPostOrder struct {
+	Symbol       string // BTCUSDT
+	ClientId     string
+	Side         string
+	Type         string
+	TimeInForce  string
+	PositionMode string // BOTH,LONG/SHORT
+	Price        decimal.Decimal
+	Qty          decimal.Decimal // CM中 qty为合约张数
+	TradeMode    int             // 全仓:0/逐仓:1
+	ReduceOnly   int
+}
 type FuturesOrder struct {
 	RequestId string // for ws
 	Err       string // for ws
@@ -275,6 +308,46 @@ type FuturesOrder struct {
 	CTime    int64
 	UTime    int64
 }
+
+// 条件单(止损/止盈), 触发后交易所会生成普通订单
+type ConditionalOrder struct {
+	Symbol       string // BTCUSDT
+	OrderId      string // 条件单id
+	ClientId     string
+	Side         string          // BUY/SELL
+	Type         string          // STOP_MARKET/STOP_LIMIT/TAKE_PROFIT_MARKET/TAKE_PROFIT_LIMIT
+	TriggerPrice decimal.Decimal // 触发价
+	TriggerBy    string          // MARKET_PRICE/MARK_PRICE/INDEX_PRICE
+	Price        decimal.Decimal // 触发后的委托价, 市价为0
+	Qty          decimal.Decimal
+	Status       string // NEW(等待触发)/TRIGGERED/CANCELED/REJECTED
+	CTime        int64  // msec
+}
+
+// 解析条件单类型, ok=false 表示不支持
+func parseConditionalOrderType(orderType string) (isStop, isLimit, ok bool) {
+	switch orderType {
+	case "STOP_MARKET":
+		return true, false, true
+	case "STOP_LIMIT":
+		return true, true, true
+	case "TAKE_PROFIT_MARKET":
+		return false, false, true
+	case "TAKE_PROFIT_LIMIT":
+		return false, true, true
+	}
+	return false, false, false
+}
+
+// 价格上涨到触发价时触发返回true, 下跌到触发价时触发返回false
+// 止损: 卖单下跌触发, 买单上涨触发; 止盈相反
+func conditionalTriggerRise(isStop bool, side string) bool {
+	if isStop {
+		return side == "BUY"
+	}
+	return side == "SELL"
+}
+
 type FuturesPosition struct {
 	Mode        int             // 0单仓,1双仓
 	Symbol      string          // BTCUSDT
@@ -394,13 +467,61 @@ type FundingRateMarkPrice struct {
 	NextTime    int64           // 下次结算时间 msec
 }
 type KLine struct {
-	OpenTime    int64 // sec
+	Symbol      string // 只有ws推送时有值
+	Interval    string // 只有ws推送时有值 1m,5m...
+	OpenTime    int64  // sec
 	OpenPrice   decimal.Decimal
 	HighPrice   decimal.Decimal
 	ClosePrice  decimal.Decimal
 	LowPrice    decimal.Decimal
 	Volume      decimal.Decimal // 成交量
 	QuoteVolume decimal.Decimal // 成交金额
+	IsClosed    bool            // 只有ws推送时有值 该周期是否已结束
+}
+
+// 1m,5m,15m,30m,1h,4h,6h,12h,1d 对应的秒数, 不支持返回0
+func klineIntervalSeconds(interval string) int64 {
+	switch interval {
+	case "1m":
+		return 60
+	case "5m":
+		return 300
+	case "15m":
+		return 900
+	case "30m":
+		return 1800
+	case "1h":
+		return 3600
+	case "4h":
+		return 4 * 3600
+	case "6h":
+		return 6 * 3600
+	case "12h":
+		return 12 * 3600
+	case "1d":
+		return 86400
+	}
+	return 0
+}
+
+// 有的交易所只支持 截止时间+条数 查询, 根据startTime和limit推算截止时间(sec)
+func klineEndTime(interval string, startTime, endTime, limit int64) int64 {
+	if startTime > 0 && limit > 0 {
+		end := startTime + limit*klineIntervalSeconds(interval)
+		if endTime == 0 || end < endTime {
+			return end
+		}
+	}
+	return endTime
+}
+
+// 解析 kline@BTCUSDT:1m,ETHUSDT:5m 中的单个 SYMBOL:INTERVAL, INTERVAL缺省为1m
+func parseKLineSymbol(v string) (string, string) {
+	symbol, interval, _ := strings.Cut(v, ":")
+	if interval == "" {
+		interval = "1m"
+	}
+	return strings.ToUpper(symbol), interval
 }
 
 type WithdrawReturn struct {
@@ -418,6 +539,18 @@ type WithdrawResult struct {
 	Fee      decimal.Decimal //
 	DoneTime int64           // second 完成时间
 }
+type DepositResult struct {
+	Id            string // 交易所充值记录ID
+	Symbol        string // USDT
+	Network       string
+	Addr          string
+	Memo          string
+	Txid          string
+	Qty           decimal.Decimal
+	Confirmations int64  // 当前确认数, 交易所不提供时为0
+	Status        string // PENDING/CREDITED/FAILED
+	Time          int64  // second 充值时间
+}
 type FuturesLeverageBracket struct {
 	Bracket          int64           // 层级
 	InitialLeverage  int64           // 该层允许的最高初始杠杆倍数
@@ -509,17 +642,57 @@ type DepositAddress struct {
 	Memo    string
 }
 
+// 交易所地址簿中保存的提现地址
+type WithdrawAddress struct {
+	Symbol  string // 为空表示通用地址
+	Network string
+	Addr    string
+	Memo    string // kraken 为提现key
+	Name    string // 备注
+}
+
 type WalletAssetInfo struct {
-	Symbol            string // BTC
-	IsTransferEnabled bool
-	TransferScale     int32 // 划转精度
-	BindNetworks      map[string]*WalletAssetBindNetworkInfo
+	Symbol            string                                 // BTC
+	IsTransferEnabled bool                                   // only bigone
+	TransferScale     int32                                  // 划转精度, only bigone
+	BindNetworks      map[string]*WalletAssetBindNetworkInfo // key: network
 }
 type WalletAssetBindNetworkInfo struct {
 	IsWithdrawalEnabled bool
 	IsDepositEnabled    bool
-	WithdrawScale       int32           // 提现精度
+	WithdrawScale       int32           // 提现精度, -1 表示交易所不提供
 	WithdrawFee         decimal.Decimal // 提现Fee
 	MinWithdrawalAmount decimal.Decimal
 	MinDepositAmount    decimal.Decimal
 }
+
+type SubAccount struct {
+	Id     string // 子账户标识, 其他子账户接口及Transfer使用: binance 邮箱, okx 子账户名, bybit/gate UID
+	Name   string
+	Remark string
+	Status string // NORMAL/FROZEN
+	CTime  int64  // second
+}
+type SubAccountAsset struct {
+	Symbol string
+	Total  decimal.Decimal
+	Avail  decimal.Decimal
+	Locked decimal.Decimal
+}
+type SubAccountApiKey struct {
+	ApiKey     string
+	SecretKey  string
+	Passphrase string // 只有okx
+	Label      string
+	Perm       string   // READ/TRADE
+	Ips        []string // 绑定的ip, 为空表示不限制
+}
+type SubAccountTransfer struct {
+	Id     string
+	Symbol string
+	Qty    decimal.Decimal
+	From   string // 子账户Id, 母账户为空(binance,bybit 为母账户邮箱/UID)
+	To     string
+	Status string // SUCCESS/PENDING/FAILED
+	Time   int64  // second
+}
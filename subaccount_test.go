@@ -0,0 +1,155 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBinanceSubAccountListPaging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		n := 200
+		if page == 2 {
+			n = 3
+		} else if page != 1 {
+			n = 0
+		}
+		l := make([]string, 0, n)
+		for i := range n {
+			l = append(l, `{"email":"s`+strconv.Itoa(page)+`_`+strconv.Itoa(i)+`@x.com","isFreeze":false,"createTime":1700000000000}`)
+		}
+		w.Write([]byte(`{"subAccounts":[` + strings.Join(l, ",") + `]}`))
+	}))
+	defer srv.Close()
+
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := ex.SubAccountList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 203 {
+		t.Fatalf("want 203 sub accounts got %d", len(l))
+	}
+	if l[202].Id != "s2_2@x.com" {
+		t.Fatalf("unexpected last sub account %s", l[202].Id)
+	}
+}
+func TestBinanceSubAccountEmailEscape(t *testing.T) {
+	var rawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"balances":[{"asset":"BTC","free":"1","locked":"0.5"}]}`))
+	}))
+	defer srv.Close()
+
+	ex, err := New("binance", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, err := ex.SubAccountGetAssets("a+b@x.com", "SPOT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rawQuery, "email=a%2Bb%40x.com") {
+		t.Fatalf("email not escaped: %s", rawQuery)
+	}
+	if !assets["BTC"].Total.Equal(assets["BTC"].Avail.Add(assets["BTC"].Locked)) || !assets["BTC"].Locked.IsPositive() {
+		t.Fatalf("unexpected asset %+v", *assets["BTC"])
+	}
+}
+func TestBybitSubAccountTransferHistoryPaging(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		next := ""
+		if r.URL.Query().Get("cursor") == "" {
+			next = "c1"
+		}
+		w.Write([]byte(`{"retCode":0,"result":{"list":[{"transferId":"t` + strconv.Itoa(len(queries)) +
+			`","coin":"USDT","amount":"1","fromMemberId":"1001","toMemberId":"2002","status":"SUCCESS",` +
+			`"timestamp":"1700000000000"}],"nextPageCursor":"` + next + `"}}`))
+	}))
+	defer srv.Close()
+
+	ex, err := New("bybit", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := ex.SubAccountTransferHistory("2002", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 || !strings.Contains(queries[0], "memberId=2002") || !strings.Contains(queries[1], "cursor=c1") {
+		t.Fatalf("unexpected queries %v", queries)
+	}
+	if len(l) != 2 || l[1].Id != "t2" || l[0].To != "2002" || l[0].Time != 1700000000 {
+		t.Fatalf("unexpected transfers %+v", l)
+	}
+}
+func TestBybitSubAccountCreateEscape(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"retCode":0,"result":{"uid":"1001","username":"sub1","remark":"a \"b\""}}`))
+	}))
+	defer srv.Close()
+
+	ex, err := New("bybit", "test", "k", "s", "", "", &Options{RestURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.SubAccountCreate("sub1", `a "b"`); err != nil {
+		t.Fatal(err)
+	}
+	if body["username"] != "sub1" || body["remark"] != `a "b"` || body["memberType"] != float64(1) {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+func TestSubAccountCreateApiKey(t *testing.T) {
+	cases := []struct {
+		cex  string
+		path string
+		resp string
+	}{
+		{"binance", "/sapi/v1/sub-account/subAccountApi", `{"apiKey":"ak","secretKey":"sk"}`},
+		{"okx", "/api/v5/users/subaccount/apikey",
+			`{"code":"0","data":[{"label":"l1","apiKey":"ak","secretKey":"sk","passphrase":"pp"}]}`},
+		{"bybit", "/v5/user/create-sub-api", `{"retCode":0,"result":{"note":"l1","apiKey":"ak","secret":"sk"}}`},
+		{"gate", "/api/v4/sub_accounts/1001/keys", `{"name":"l1","key":"ak","secret":"sk"}`},
+	}
+	for _, c := range cases {
+		var path string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.Write([]byte(c.resp))
+		}))
+		ex, err := New(c.cex, "test", "k", "s", "p", "", &Options{RestURL: srv.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ex.SubAccountCreateApiKey("1001", "l1", "pp", "TRADE", nil)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", c.cex, err)
+		}
+		if path != c.path || key.ApiKey != "ak" || key.SecretKey != "sk" || key.Perm != "TRADE" {
+			t.Fatalf("%s: unexpected path %s key %+v", c.cex, path, *key)
+		}
+	}
+
+	ex, err := New("kraken", "test", "k", "s", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.SubAccountCreateApiKey("1001", "l1", "", "READ", nil); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("want ErrNotSupported got %v", err)
+	}
+}
@@ -1,67 +1,96 @@
 package cex
 
 import (
-	"errors"
-
 	"github.com/shopspring/decimal"
 )
 
 type Unsupported struct {
+	name string // 交易所名, 用于错误信息
 }
 
 // = spot
 func (us *Unsupported) SpotSupported() bool            { return false }
-func (us *Unsupported) SpotServerTime() (int64, error) { return 0, errors.New("not support") }
+func (us *Unsupported) SpotServerTime() (int64, error) { return 0, newNotSupportError(us.name) }
 func (us *Unsupported) SpotLoadAllPairRule() (map[string]*SpotExchangePairRule, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetAll24hTicker() (map[string]Pub24hTicker, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetBBO(symbol string) (BestBidAsk, error) {
-	return BestBidAsk{}, errors.New("not support")
+	return BestBidAsk{}, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetAllAssets() (map[string]*SpotAsset, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotPlaceOrder(symbol, cltId string, price, amt, qty decimal.Decimal,
 	side, timeInForce, orderType string, postOnly bool) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotPlaceOrderMultiple([]SpotPostOrder) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotPlaceOrders(orders []SpotPostOrder) ([]BatchOrderResult, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotCancelOrders(orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotCancelAllOrders(symbol string) error {
+	return newNotSupportError(us.name)
+}
+func (us *Unsupported) CancelAllOrdersAfter(typ, symbol string, timeout int) error {
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotCancelOrder(symbol, orderId, cltId string) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*SpotOrder, error) {
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetOrder(symbol, orderId, cltId string) (*SpotOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetFilledOrders(symbol string) ([]*SpotOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetOpenOrders(symbol string) ([]*SpotOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotGetTradeFee(symbol string) (SpotTradeFee, error) {
-	return SpotTradeFee{}, errors.New("not support")
+	return SpotTradeFee{}, newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotGetKLine(symbol, interval string, startTime, endTime, lmt int64) ([]KLine, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotPlaceConditionalOrder(symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy string) (string, error) {
+	return "", newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotGetOpenConditionalOrders(symbol string) ([]*ConditionalOrder, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotCancelConditionalOrder(symbol, orderId string) error {
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) IsXStock(symbol string) bool {
 	return false
 }
 
-func (us *Unsupported) SpotWsPublicOpen() error                      { return errors.New("not support") }
+func (us *Unsupported) SpotWsPublicOpen() error                      { return newNotSupportError(us.name) }
 func (us *Unsupported) SpotWsPublicSubscribe(channels []string)      {}
 func (us *Unsupported) SpotWsPublicUnsubscribe(channels []string)    {}
 func (us *Unsupported) SpotWsPublicTickerPoolPut(v any)              {}
 func (us *Unsupported) SpotWsPublicOrderBook5PoolPut(v any)          {}
 func (us *Unsupported) SpotWsPublicBBOPoolPut(v any)                 {}
 func (us *Unsupported) SpotWsPublicTradePoolPut(v any)               {}
+func (us *Unsupported) SpotWsPublicKLinePoolPut(v any)               {}
 func (us *Unsupported) SpotWsPublicLoop(ch chan<- any)               {}
 func (us *Unsupported) SpotWsPublicClose()                           {}
 func (us *Unsupported) SpotWsPublicIsClosed() bool                   { return true }
 func (us *Unsupported) SpotWsPrivateSupported() bool                 { return false }
-func (us *Unsupported) SpotWsPrivateOpen() error                     { return errors.New("not support") }
+func (us *Unsupported) SpotWsPrivateOpen() error                     { return newNotSupportError(us.name) }
 func (us *Unsupported) SpotWsPrivateSubscribe(channels []string)     {}
 func (us *Unsupported) SpotWsPrivateLoop(ch chan<- any)              {}
 func (us *Unsupported) SpotWsPrivateLastPong() (int64, int64, int64) { return 0, 0, 0 }
@@ -69,73 +98,77 @@ func (us *Unsupported) SpotWsPrivateClose()                          {}
 func (us *Unsupported) SpotWsPrivateIsClosed() bool                  { return true }
 func (us *Unsupported) SpotWsPlaceOrder(symbol, cltId string, price, amt, qty decimal.Decimal,
 	side, timeInForce, orderType string, postOnly bool) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
 }
 func (us *Unsupported) SpotWsCancelOrder(s, o, c string) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
+}
+func (us *Unsupported) SpotWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	return "", newNotSupportError(us.name)
 }
 
 func (us *Unsupported) MarginSupported() bool { return false }
 func (us *Unsupported) MarginGetCrossAccountInfo() (*MarginCrossAccountInfo, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginGetMaxBorrowable(s string) (MarginMaxBorrowable, error) {
 	return MarginMaxBorrowable{}, nil
 }
 func (us *Unsupported) MarginPlaceOrder(symbol, cltId string, price, amt, qty decimal.Decimal,
 	side, timeInForce, orderType, sideEffectType string, isIsolated bool) (string, decimal.Decimal, string, error) {
-	return "", decimal.Zero, "", errors.New("not support")
+	return "", decimal.Zero, "", newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginCancelOrder(symbol, orderId, cltId string, isIsolated bool) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginGetOrder(symbol, orderId, cltId string, isIsolated bool) (*MarginOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginGetTrades(symbol, orderId string, isIsolated bool) ([]*MarginTrade, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginRepay(symbol string, amt decimal.Decimal, isIsolated bool) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) MarginGetAssetInfo(symbol string) (MarginAssetInfo, error) {
-	return MarginAssetInfo{}, errors.New("not support")
+	return MarginAssetInfo{}, newNotSupportError(us.name)
 }
 
 // = futrures
 func (us *Unsupported) FuturesSupported(typ string) bool { return false }
 func (us *Unsupported) FuturesServerTime(typ string) (int64, error) {
-	return 0, errors.New("not support")
+	return 0, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesLoadAllPairRule(typ string) (map[string]*FuturesExchangePairRule, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetAll24hTicker(typ string) (map[string]Pub24hTicker, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetBBO(typ, symbol string) (BestBidAsk, error) {
-	return BestBidAsk{}, errors.New("not support")
+	return BestBidAsk{}, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetAllFundingRate(typ string) (map[string]FundingRate, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetFundingRateHistory(typ, symbol string, startTime, endTime int64) ([]FundingRateHistory, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetFundingRateMarkPrice(typ, symbol string) (FundingRateMarkPrice, error) {
-	return FundingRateMarkPrice{}, errors.New("not support")
+	return FundingRateMarkPrice{}, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetAllAssets(typ string) (map[string]*FuturesAsset, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetKLine(typ, symbol, interval string, startTime, endTime, lmt int64) ([]KLine, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetAllPositionList(typ string) (map[string]*FuturesPosition, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetAllPositions(typ string) (map[string]*FuturesPositions, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesSizeToQty(typ, symbol string, size decimal.Decimal) decimal.Decimal {
 	return decimal.Zero
@@ -146,41 +179,68 @@ func (us *Unsupported) FuturesQtyToSize(typ, symbol string, qty decimal.Decimal)
 func (us *Unsupported) FuturesPlaceOrder(typ, symbol, clientId string,
 	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
 	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesPlaceOrders(typ string,
+	orders []FuturesPostOrder) ([]BatchOrderResult, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesCancelOrders(typ string,
+	orders []CancelOrderArg) ([]BatchOrderResult, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesCancelAllOrders(typ, symbol string) error {
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesCancelOrder(typ, symbol, orderId, cltId string) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesAmendOrder(typ, symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (*FuturesOrder, error) {
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetOrder(typ, symbol, orderId, cltId string) (*FuturesOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetOpenOrders(typ, symbol string) ([]*FuturesOrder, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesPlaceConditionalOrder(typ, symbol, cltId string,
+	triggerPrice, price, qty decimal.Decimal, side, orderType, triggerBy, positionMode string,
+	reduceOnly int) (string, error) {
+	return "", newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesGetOpenConditionalOrders(typ, symbol string) ([]*ConditionalOrder, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesCancelConditionalOrder(typ, symbol, orderId string) error {
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesSwitchPositionMode(typ string, mode int) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesSwitchTradeMode(typ, symbol string, mode, lver int) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesMaintMargin(typ, symbol string) ([]*FuturesLeverageBracket, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesGetProfitLossHistory(typ, symbol, plType string,
 	startTime, endTime int64) ([]FuturesProfitLossHistory, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
-func (us *Unsupported) FuturesWsPublicOpen(typ string) error         { return errors.New("not support") }
+func (us *Unsupported) FuturesWsPublicOpen(typ string) error         { return newNotSupportError(us.name) }
 func (us *Unsupported) FuturesWsPublicSubscribe(channels []string)   {}
 func (us *Unsupported) FuturesWsPublicUnsubscribe(channels []string) {}
 func (us *Unsupported) FuturesWsPublicTickerPoolPut(v any)           {}
 func (us *Unsupported) FuturesWsPublicOrderBook5PoolPut(v any)       {}
 func (us *Unsupported) FuturesWsPublicBBOPoolPut(v any)              {}
+func (us *Unsupported) FuturesWsPublicKLinePoolPut(v any)            {}
 func (us *Unsupported) FuturesWsPublicLoop(ch chan<- any)            {}
 func (us *Unsupported) FuturesWsPublicClose()                        {}
 func (us *Unsupported) FuturesWsPublicIsClosed() bool                { return true }
 func (us *Unsupported) FuturesWsPrivateSupported(typ string) bool    { return false }
-func (us *Unsupported) FuturesWsPrivateOpen(typ string) error        { return errors.New("not support") }
+func (us *Unsupported) FuturesWsPrivateOpen(typ string) error        { return newNotSupportError(us.name) }
 func (us *Unsupported) FuturesWsPrivateSubscribe(channels []string)  {}
 func (us *Unsupported) FuturesWsPrivateLoop(ch chan<- any)           {}
 func (us *Unsupported) FuturesWsPrivateClose()                       {}
@@ -188,18 +248,22 @@ func (us *Unsupported) FuturesWsPrivateIsClosed() bool               { return tr
 func (us *Unsupported) FuturesWsPlaceOrder(symbol, cltId string,
 	price, qty decimal.Decimal, side, orderType, timeInForce, positionMode string,
 	tradeMode /*全仓:0/逐仓:1*/, reduceOnly int) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
 }
 func (us *Unsupported) FuturesWsCancelOrder(symbol, orderId, cltId string) (string, error) {
-	return "", errors.New("not support")
+	return "", newNotSupportError(us.name)
+}
+func (us *Unsupported) FuturesWsAmendOrder(symbol, orderId, cltId, side string,
+	price, qty decimal.Decimal) (string, error) {
+	return "", newNotSupportError(us.name)
 }
 
 // unified
 func (us *Unsupported) UnifiedGetAssets() (map[string]*UnifiedAsset, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) UnifiedWsSupported() bool             { return false }
-func (us *Unsupported) UnifiedWsOpen() error                 { return errors.New("not support") }
+func (us *Unsupported) UnifiedWsOpen() error                 { return newNotSupportError(us.name) }
 func (us *Unsupported) UnifiedWsSubscribe(channels []string) {}
 func (us *Unsupported) UnifiedWsLoop(ch chan<- any)          {}
 func (us *Unsupported) UnifiedWsClose()                      {}
@@ -207,23 +271,49 @@ func (us *Unsupported) UnifiedWsIsClosed() bool              { return true }
 
 // wallet
 func (us *Unsupported) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal) (*WithdrawReturn, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) CancelWithdrawal(symbol, wid string) error {
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) GetWithdrawalHistory(symbol string) ([]WithdrawResult, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) GetDepositHistory(symbol string, startTime, endTime int64) ([]DepositResult, error) {
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) Transfer(symbol, from, to, typ, subAccount string, qty decimal.Decimal) error {
-	return errors.New("not support")
+	return newNotSupportError(us.name)
 }
 func (us *Unsupported) FundingGetAllAssets() (map[string]*FundingAsset, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) FundingGetAsset(symbol string) (FundingAsset, error) {
-	return FundingAsset{}, errors.New("not support")
+	return FundingAsset{}, newNotSupportError(us.name)
 }
 func (us *Unsupported) GetDepositAddress(symbol, network string) ([]DepositAddress, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) GetWithdrawAddressBook(symbol string) ([]WithdrawAddress, error) {
+	return nil, newNotSupportError(us.name)
 }
 func (us *Unsupported) GetWalletAllAssetInfo() (map[string]*WalletAssetInfo, error) {
-	return nil, errors.New("not support")
+	return nil, newNotSupportError(us.name)
+}
+
+// sub account
+func (us *Unsupported) SubAccountList() ([]SubAccount, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SubAccountCreate(name, remark string) (*SubAccount, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SubAccountGetAssets(subAccount, typ string) (map[string]*SubAccountAsset, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SubAccountTransferHistory(subAccount string, startTime, endTime int64) ([]SubAccountTransfer, error) {
+	return nil, newNotSupportError(us.name)
+}
+func (us *Unsupported) SubAccountCreateApiKey(subAccount, label, passphrase, perm string, ips []string) (*SubAccountApiKey, error) {
+	return nil, newNotSupportError(us.name)
 }
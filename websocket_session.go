@@ -0,0 +1,377 @@
+package cex
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WsSession 管理的连接类型
+const (
+	WsSpotPublic     = "spot.public"
+	WsSpotPrivate    = "spot.private"
+	WsFuturesPublic  = "futures.public"
+	WsFuturesPrivate = "futures.private"
+	WsUnified        = "unified"
+)
+
+// WsSession 通过Run的ch推送的连接事件
+const (
+	WsEventConnected    = "connected"
+	WsEventDisconnected = "disconnected"
+	WsEventResubscribed = "resubscribed"
+)
+
+type WsSessionEvent struct {
+	Kind     string   // WsSpotPublic ...
+	Event    string   // WsEventConnected ...
+	Attempt  int      // 连续失败次数, connected后归零
+	Err      error    // disconnected的原因
+	Channels []string // resubscribed时重放的订阅
+	Time     int64    // msec
+}
+
+type WsSessionConfig struct {
+	MinBackoff time.Duration // 重连退避起始值, 默认1s
+	MaxBackoff time.Duration // 重连退避上限, 默认60s
+	MaxRetries int           // 连续失败多少次后放弃(Run返回), 0表示不限
+	// >0 时连接存活到期后主动重连, 用于刷新listenKey/token(如binance 24h断开)
+	Lifetime time.Duration
+}
+
+// 托管的ws连接: 断线后按退避重连, 重放当前订阅, 并在同一个ch上推送 *WsSessionEvent
+// 每次重连都会调用newEx创建新的cex object(closed的obj不能复用),
+// listenKey/token 在新obj的Open中重新获取
+//
+//	s := cex.NewWsSession(func() (cex.Exchanger, error) {
+//		return cex.NewPrivate("binance", account, apikey, secretkey, "")
+//	}, cex.WsFuturesPrivate, "UM", nil)
+//	s.Subscribe([]string{"orders", "positions"})
+//	go s.Run(ch)
+type WsSession struct {
+	newEx func() (Exchanger, error)
+	kind  string
+	typ   string // futures UM/CM
+	cfg   WsSessionConfig
+
+	mtx      sync.Mutex
+	ex       Exchanger // 当前连接, 未连接时为nil
+	channels []string  // 当前订阅, 按订阅顺序
+
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewWsSession(newEx func() (Exchanger, error), kind, typ string, cfg *WsSessionConfig) *WsSession {
+	s := &WsSession{
+		newEx:     newEx,
+		kind:      kind,
+		typ:       typ,
+		closeChan: make(chan struct{}),
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.MinBackoff <= 0 {
+		s.cfg.MinBackoff = time.Second
+	}
+	if s.cfg.MaxBackoff < s.cfg.MinBackoff {
+		s.cfg.MaxBackoff = max(60*time.Second, s.cfg.MinBackoff)
+	}
+	return s
+}
+
+// 当前连接的cex object, 用于PoolPut/WsPlaceOrder等, 未连接时返回nil
+func (s *WsSession) Exchanger() Exchanger {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.ex
+}
+
+// 当前的订阅集合
+func (s *WsSession) Channels() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]string(nil), s.channels...)
+}
+
+// 记录订阅, 已连接时立即发送, 重连后自动重放
+func (s *WsSession) Subscribe(channels []string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.channels = append(s.channels, channels...)
+	if s.ex != nil {
+		s.subscribe(s.ex, channels)
+	}
+}
+
+// 从订阅集合中移除, channel格式与Subscribe相同(name@symbolA,symbolB)
+func (s *WsSession) Unsubscribe(channels []string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, c := range channels {
+		name, syms, _ := strings.Cut(c, "@")
+		left := s.channels[:0]
+		for _, sc := range s.channels {
+			scName, scSyms, _ := strings.Cut(sc, "@")
+			if scName != name {
+				left = append(left, sc)
+				continue
+			}
+			if syms == "" {
+				continue
+			}
+			var remain []string
+			for _, sym := range strings.Split(scSyms, ",") {
+				matched := false
+				for _, v := range strings.Split(syms, ",") {
+					if wsSessionSymbolEqual(name, sym, v) {
+						matched = true
+						break
+					}
+				}
+				if !matched {
+					remain = append(remain, sym)
+				}
+			}
+			if len(remain) > 0 {
+				left = append(left, scName+"@"+strings.Join(remain, ","))
+			}
+		}
+		s.channels = left
+	}
+	if s.ex != nil {
+		switch s.kind {
+		case WsSpotPublic:
+			s.ex.SpotWsPublicUnsubscribe(channels)
+		case WsFuturesPublic:
+			s.ex.FuturesWsPublicUnsubscribe(channels)
+		}
+	}
+}
+
+// 阻塞直到Close或者重连次数超过MaxRetries, 结束时会close(ch)
+func (s *WsSession) Run(ch chan<- any) {
+	defer close(ch)
+	attempt := 0
+	connected := false // 是否成功连接过, 用于判断是否需要发resubscribed
+	for !s.IsClosed() {
+		ex, err := s.open()
+		if err != nil {
+			attempt++
+			s.emit(ch, WsEventDisconnected, attempt, err, nil)
+			if s.cfg.MaxRetries > 0 && attempt >= s.cfg.MaxRetries {
+				return
+			}
+			if !s.sleep(s.backoff(attempt)) {
+				return
+			}
+			continue
+		}
+
+		s.mtx.Lock()
+		if s.closed {
+			s.mtx.Unlock()
+			s.closeEx(ex)
+			return
+		}
+		s.ex = ex
+		channels := append([]string(nil), s.channels...)
+		if len(channels) > 0 {
+			s.subscribe(ex, channels)
+		}
+		s.mtx.Unlock()
+
+		attempt = 0
+		s.emit(ch, WsEventConnected, 0, nil, nil)
+		if connected && len(channels) > 0 {
+			s.emit(ch, WsEventResubscribed, 0, nil, channels)
+		}
+		connected = true
+
+		err = s.loop(ex, ch)
+
+		s.mtx.Lock()
+		s.ex = nil
+		s.mtx.Unlock()
+		if s.IsClosed() {
+			return
+		}
+		attempt++
+		s.emit(ch, WsEventDisconnected, attempt, err, nil)
+		if !s.sleep(s.backoff(attempt)) {
+			return
+		}
+	}
+}
+
+func (s *WsSession) Close() {
+	s.mtx.Lock()
+	if s.closed {
+		s.mtx.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.closeChan)
+	ex := s.ex
+	s.mtx.Unlock()
+	if ex != nil {
+		s.closeEx(ex)
+	}
+}
+func (s *WsSession) IsClosed() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.closed
+}
+
+func (s *WsSession) open() (Exchanger, error) {
+	ex, err := s.newEx()
+	if err != nil {
+		return nil, err
+	}
+	switch s.kind {
+	case WsSpotPublic:
+		err = ex.SpotWsPublicOpen()
+	case WsSpotPrivate:
+		err = ex.SpotWsPrivateOpen()
+	case WsFuturesPublic:
+		err = ex.FuturesWsPublicOpen(s.typ)
+	case WsFuturesPrivate:
+		err = ex.FuturesWsPrivateOpen(s.typ)
+	case WsUnified:
+		err = ex.UnifiedWsOpen()
+	default:
+		err = errors.New("unknown ws session kind: " + s.kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ex, nil
+}
+func (s *WsSession) subscribe(ex Exchanger, channels []string) {
+	switch s.kind {
+	case WsSpotPublic:
+		ex.SpotWsPublicSubscribe(channels)
+	case WsSpotPrivate:
+		ex.SpotWsPrivateSubscribe(channels)
+	case WsFuturesPublic:
+		ex.FuturesWsPublicSubscribe(channels)
+	case WsFuturesPrivate:
+		ex.FuturesWsPrivateSubscribe(channels)
+	case WsUnified:
+		ex.UnifiedWsSubscribe(channels)
+	}
+}
+func (s *WsSession) closeEx(ex Exchanger) {
+	switch s.kind {
+	case WsSpotPublic:
+		ex.SpotWsPublicClose()
+	case WsSpotPrivate:
+		ex.SpotWsPrivateClose()
+	case WsFuturesPublic:
+		ex.FuturesWsPublicClose()
+	case WsFuturesPrivate:
+		ex.FuturesWsPrivateClose()
+	case WsUnified:
+		ex.UnifiedWsClose()
+	}
+}
+
+// 运行ex的Loop并把消息转发到ch, 连接断开后返回原因
+func (s *WsSession) loop(ex Exchanger, ch chan<- any) error {
+	inner := make(chan any, 64)
+	switch s.kind {
+	case WsSpotPublic:
+		go ex.SpotWsPublicLoop(inner)
+	case WsSpotPrivate:
+		go ex.SpotWsPrivateLoop(inner)
+	case WsFuturesPublic:
+		go ex.FuturesWsPublicLoop(inner)
+	case WsFuturesPrivate:
+		go ex.FuturesWsPrivateLoop(inner)
+	case WsUnified:
+		go ex.UnifiedWsLoop(inner)
+	}
+
+	var lifetimeC <-chan time.Time
+	if s.cfg.Lifetime > 0 {
+		timer := time.NewTimer(s.cfg.Lifetime)
+		defer timer.Stop()
+		lifetimeC = timer.C
+	}
+	reason := errors.New(ex.Name() + " " + s.kind + " connection lost")
+	for {
+		select {
+		case v, ok := <-inner:
+			if !ok {
+				return reason
+			}
+			select {
+			case ch <- v:
+			case <-s.closeChan: // 使用方阻塞时也要能退出
+				go func() {
+					for range inner { // 等Loop结束, 避免它阻塞在inner上
+					}
+				}()
+				return reason
+			}
+		case <-lifetimeC:
+			reason = errors.New(ex.Name() + " " + s.kind + " lifetime reached, reconnect")
+			s.closeEx(ex) // Loop 结束后会close(inner)
+		}
+	}
+}
+
+func (s *WsSession) emit(ch chan<- any, event string, attempt int, err error, channels []string) {
+	select {
+	case ch <- &WsSessionEvent{
+		Kind:     s.kind,
+		Event:    event,
+		Attempt:  attempt,
+		Err:      err,
+		Channels: channels,
+		Time:     time.Now().UnixMilli(),
+	}:
+	case <-s.closeChan:
+	}
+}
+
+// orderbook@ 只比较symbol(同一symbol只有一个本地订单簿), kline@ 比较symbol和周期(缺省1m)
+func wsSessionSymbolEqual(name, a, b string) bool {
+	switch name {
+	case "orderbook":
+		sa, _ := parseOrderBookSymbol(a)
+		sb, _ := parseOrderBookSymbol(b)
+		return sa == sb
+	case "kline":
+		sa, ia := parseKLineSymbol(a)
+		sb, ib := parseKLineSymbol(b)
+		return sa == sb && ia == ib
+	}
+	return a == b
+}
+
+// 指数退避 + 20%随机抖动
+func (s *WsSession) backoff(attempt int) time.Duration {
+	d := s.cfg.MinBackoff
+	for i := 1; i < attempt && d < s.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, s.cfg.MaxBackoff)
+	return d - time.Duration(rand.Int64N(int64(d)/5+1))
+}
+
+// 返回false表示期间session被关闭
+func (s *WsSession) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-s.closeChan:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
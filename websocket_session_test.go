@@ -0,0 +1,125 @@
+package cex_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shaovie/cex"
+	"github.com/shaovie/cex/cextest"
+	"github.com/shaovie/gutils/ilog"
+	"github.com/shopspring/decimal"
+)
+
+// 断线时adapter会写ilog, 需先初始化
+func TestMain(m *testing.M) {
+	dir, _ := os.MkdirTemp("", "cex-test-log")
+	ilog.Init(dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+func newWsSessionServer(t *testing.T) (*cextest.Server, func() (cex.Exchanger, error)) {
+	srv, err := cextest.NewServer("binance", cextest.Config{ApiKey: "k", SecretKey: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	srv.Engine.AddSymbol(cextest.Symbol{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"})
+	srv.Engine.SetBook("BTCUSDT",
+		[]cextest.Level{{Price: decimal.NewFromInt(99), Qty: decimal.NewFromInt(1)}},
+		[]cextest.Level{{Price: decimal.NewFromInt(101), Qty: decimal.NewFromInt(1)}})
+	return srv, func() (cex.Exchanger, error) {
+		return cex.New("binance", "test", "k", "s", "", "", srv.Options())
+	}
+}
+
+// 从ch中读取直到match返回true
+func waitSessionMsg(t *testing.T, ch <-chan any, what string, match func(v any) bool) {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				t.Fatal("session closed, waiting for " + what)
+			}
+			if match(v) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timeout, waiting for " + what)
+		}
+	}
+}
+func isSessionEvent(v any, event string) bool {
+	e, ok := v.(*cex.WsSessionEvent)
+	return ok && e.Event == event
+}
+func isBidPrice(v any, price int64) bool {
+	bbo, ok := v.(*cex.BestBidAsk)
+	return ok && bbo.Symbol == "BTCUSDT" && bbo.BidPrice.Equal(decimal.NewFromInt(price))
+}
+func TestWsSessionResubscribe(t *testing.T) {
+	srv, newEx := newWsSessionServer(t)
+	s := cex.NewWsSession(newEx, cex.WsSpotPublic, "", &cex.WsSessionConfig{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	})
+	ch := make(chan any, 64)
+	s.Subscribe([]string{"bbo@BTCUSDT"})
+	go s.Run(ch)
+	defer s.Close()
+
+	waitSessionMsg(t, ch, "connected", func(v any) bool { return isSessionEvent(v, cex.WsEventConnected) })
+	waitSessionMsg(t, ch, "bbo", func(v any) bool { return isBidPrice(v, 99) })
+
+	// 服务端断开, 重连后重放订阅
+	srv.DropWs("/")
+	waitSessionMsg(t, ch, "disconnected", func(v any) bool { return isSessionEvent(v, cex.WsEventDisconnected) })
+	waitSessionMsg(t, ch, "resubscribed", func(v any) bool {
+		e, ok := v.(*cex.WsSessionEvent)
+		return ok && e.Event == cex.WsEventResubscribed && reflect.DeepEqual(e.Channels, []string{"bbo@BTCUSDT"})
+	})
+	srv.Engine.SetBook("BTCUSDT",
+		[]cextest.Level{{Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1)}},
+		[]cextest.Level{{Price: decimal.NewFromInt(101), Qty: decimal.NewFromInt(1)}})
+	waitSessionMsg(t, ch, "bbo after reconnect", func(v any) bool { return isBidPrice(v, 100) })
+}
+func TestWsSessionUnsubscribe(t *testing.T) {
+	s := cex.NewWsSession(nil, cex.WsSpotPublic, "", nil)
+	s.Subscribe([]string{"orderbook@BTCUSDT:100,ETHUSDT", "kline@BTCUSDT:1m,BTCUSDT:5m", "bbo@BTCUSDT"})
+	s.Unsubscribe([]string{"orderbook@btcusdt", "kline@BTCUSDT"})
+	want := []string{"orderbook@ETHUSDT", "kline@BTCUSDT:5m", "bbo@BTCUSDT"}
+	if got := s.Channels(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+}
+func TestWsSessionCloseBlocked(t *testing.T) {
+	srv, newEx := newWsSessionServer(t)
+	s := cex.NewWsSession(newEx, cex.WsSpotPublic, "", nil)
+	ch := make(chan any) // 不带缓冲, 使用方不读时Run会阻塞在转发上
+	s.Subscribe([]string{"bbo@BTCUSDT"})
+	done := make(chan struct{})
+	go func() {
+		s.Run(ch)
+		close(done)
+	}()
+	waitSessionMsg(t, ch, "connected", func(v any) bool { return isSessionEvent(v, cex.WsEventConnected) })
+	for i := int64(0); i < 5; i++ {
+		srv.Engine.SetBook("BTCUSDT",
+			[]cextest.Level{{Price: decimal.NewFromInt(90 + i), Qty: decimal.NewFromInt(1)}},
+			[]cextest.Level{{Price: decimal.NewFromInt(101), Qty: decimal.NewFromInt(1)}})
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	s.Close()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run not return after Close")
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("want ch closed")
+	}
+}
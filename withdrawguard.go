@@ -0,0 +1,392 @@
+package cex
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"math/big"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 提现保护: 只允许提现到白名单地址, 校验地址格式, 限制每个币种每天(UTC)的提现总量
+// 白名单来自交易所地址簿(LoadAddressBook)或手动添加(AddAddress), 币种没有白名单地址时拒绝提现
+// 没有设置日限额的币种不限量
+//
+//	g := cex.NewWithdrawGuard(ex)
+//	if err := g.LoadAddressBook("USDT"); err != nil {
+//		return err
+//	}
+//	g.SetDailyCap("USDT", decimal.NewFromInt(100000))
+//	wr, err := g.Withdrawal("USDT", addr, "", "TRX", qty)
+type WithdrawGuard struct {
+	ex Exchanger
+
+	mtx       sync.Mutex
+	whitelist map[string][]WithdrawAddress // symbol -> 地址, 通用地址的key为""
+	dailyCap  map[string]decimal.Decimal
+	used      map[string]decimal.Decimal // 当天已提现(含处理中)
+	day       string                     // 2006-01-02
+}
+
+func NewWithdrawGuard(ex Exchanger) *WithdrawGuard {
+	return &WithdrawGuard{
+		ex:        ex,
+		whitelist: make(map[string][]WithdrawAddress),
+		dailyCap:  make(map[string]decimal.Decimal),
+		used:      make(map[string]decimal.Decimal),
+	}
+}
+
+// 从交易所地址簿加载白名单, 覆盖该币种(及返回的通用地址)之前的白名单
+func (g *WithdrawGuard) LoadAddressBook(symbol string) error {
+	waL, err := g.ex.GetWithdrawAddressBook(symbol)
+	if err != nil {
+		return err
+	}
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	l := make(map[string][]WithdrawAddress)
+	for _, wa := range waL {
+		l[wa.Symbol] = append(l[wa.Symbol], wa)
+	}
+	delete(g.whitelist, symbol)
+	maps.Copy(g.whitelist, l)
+	return nil
+}
+
+// 手动添加白名单地址, Symbol 为空表示所有币种通用
+func (g *WithdrawGuard) AddAddress(wa WithdrawAddress) error {
+	if wa.Addr != "" {
+		if err := ValidateWithdrawAddress(wa.Symbol, wa.Network, wa.Addr, wa.Memo); err != nil {
+			return err
+		}
+	}
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	g.whitelist[wa.Symbol] = append(g.whitelist[wa.Symbol], wa)
+	return nil
+}
+
+// qty <= 0 表示取消限额
+func (g *WithdrawGuard) SetDailyCap(symbol string, qty decimal.Decimal) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if qty.IsPositive() {
+		g.dailyCap[symbol] = qty
+	} else {
+		delete(g.dailyCap, symbol)
+	}
+}
+
+// 检查通过后调用Exchanger.Withdrawal, 参数同Withdrawal
+func (g *WithdrawGuard) Withdrawal(symbol, addr, memo, chain string, qty decimal.Decimal) (*WithdrawReturn, error) {
+	day, err := g.reserve(symbol, addr, memo, chain, qty)
+	if err != nil {
+		return nil, err
+	}
+	wr, err := g.ex.Withdrawal(symbol, addr, memo, chain, qty)
+	if err != nil {
+		g.release(symbol, qty, day)
+		return nil, err
+	}
+	return wr, nil
+}
+
+// 只检查不提现
+func (g *WithdrawGuard) Check(symbol, addr, memo, chain string, qty decimal.Decimal) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	return g.check(symbol, addr, memo, chain, qty)
+}
+
+// 返回占用额度所在的日期, 释放时用
+func (g *WithdrawGuard) reserve(symbol, addr, memo, chain string, qty decimal.Decimal) (string, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if err := g.check(symbol, addr, memo, chain, qty); err != nil {
+		return "", err
+	}
+	g.used[symbol] = g.used[symbol].Add(qty)
+	return g.day, nil
+}
+
+// 跨天后used已清零, 不再释放前一天的额度
+func (g *WithdrawGuard) release(symbol string, qty decimal.Decimal, day string) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if day != g.day {
+		return
+	}
+	used := g.used[symbol].Sub(qty)
+	if used.IsNegative() {
+		used = decimal.Zero
+	}
+	g.used[symbol] = used
+}
+
+// 调用时需持有mtx
+func (g *WithdrawGuard) check(symbol, addr, memo, chain string, qty decimal.Decimal) error {
+	if !qty.IsPositive() {
+		return errors.New("withdraw guard: qty <= 0. =" + qty.String())
+	}
+	if addr == "" && memo == "" {
+		return errors.New("withdraw guard: address is empty")
+	}
+	if addr != "" {
+		if err := ValidateWithdrawAddress(symbol, chain, addr, memo); err != nil {
+			return err
+		}
+	}
+	if !g.isWhitelisted(symbol, addr, memo, chain) {
+		return errors.New("withdraw guard: " + symbol + " " + chain + " " + addr + " not in whitelist")
+	}
+
+	day := time.Now().UTC().Format(time.DateOnly)
+	if day != g.day {
+		g.day = day
+		clear(g.used)
+	}
+	if limit, ok := g.dailyCap[symbol]; ok {
+		if g.used[symbol].Add(qty).GreaterThan(limit) {
+			return errors.New("withdraw guard: " + symbol + " exceeds daily cap " + limit.String() +
+				", used " + g.used[symbol].String())
+		}
+	}
+	return nil
+}
+
+// addr 为空时只匹配memo(kraken提现key)
+func (g *WithdrawGuard) isWhitelisted(symbol, addr, memo, chain string) bool {
+	for _, l := range [][]WithdrawAddress{g.whitelist[symbol], g.whitelist[""]} {
+		for _, wa := range l {
+			if chain != "" && wa.Network != "" && !withdrawNetworkEqual(chain, wa.Network) {
+				continue
+			}
+			if wa.Memo != memo {
+				continue
+			}
+			if addr == "" || wa.Addr == addr ||
+				(withdrawChainFamily(chain) == "EVM" && strings.EqualFold(wa.Addr, addr)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 需要memo的链
+var withdrawMemoRequiredChains = map[string]bool{
+	"XRP":  true,
+	"XLM":  true,
+	"EOS":  true,
+	"ATOM": true,
+	"HBAR": true,
+}
+
+// 校验提现地址格式: EVM链(0x+40位hex, 大小写混合时校验EIP-55),
+// TRON(base58check), 需要memo的链(XRP/XLM/EOS/ATOM/HBAR)
+// chain 为空时按symbol判断, 其他链只检查非空
+func ValidateWithdrawAddress(symbol, chain, addr, memo string) error {
+	if addr == "" {
+		return errors.New("withdraw guard: address is empty")
+	}
+	if strings.TrimSpace(addr) != addr {
+		return errors.New("withdraw guard: address has leading or trailing space")
+	}
+	c := chain
+	if c == "" {
+		c = symbol
+	}
+	c = strings.ToUpper(c)
+	if withdrawMemoRequiredChains[c] && memo == "" {
+		return errors.New("withdraw guard: " + c + " memo is required")
+	}
+	switch withdrawChainFamily(c) {
+	case "EVM":
+		if !isValidEvmAddress(addr) {
+			return errors.New("withdraw guard: invalid evm address " + addr)
+		}
+	case "TRON":
+		if !isValidTronAddress(addr) {
+			return errors.New("withdraw guard: invalid tron address " + addr)
+		}
+	}
+	return nil
+}
+
+// 链名的别名, 如 TRC20 -> TRX, ERC20 -> ETH
+var withdrawNetworkAlias = map[string]string{
+	"TRON":     "TRX",
+	"TRC20":    "TRX",
+	"ERC20":    "ETH",
+	"BEP20":    "BSC",
+	"ARB":      "ARBITRUM",
+	"ARBONE":   "ARBITRUM",
+	"ARBEVM":   "ARBITRUM",
+	"OP":       "OPTIMISM",
+	"MATIC":    "POLYGON",
+	"AVAX_C":   "AVAXC",
+	"AVAX-C":   "AVAXC",
+	"SOLANA":   "SOL",
+	"SPL":      "SOL",
+	"BITCOIN":  "BTC",
+	"ETHEREUM": "ETH",
+}
+
+// 比较两个交易所的链名, 别名视为同一条链
+func withdrawNetworkEqual(a, b string) bool {
+	a, b = strings.ToUpper(a), strings.ToUpper(b)
+	if a == b {
+		return true
+	}
+	if withdrawChainFamily(a) == "TRON" && withdrawChainFamily(b) == "TRON" {
+		return true
+	}
+	if v, ok := withdrawNetworkAlias[a]; ok {
+		a = v
+	}
+	if v, ok := withdrawNetworkAlias[b]; ok {
+		b = v
+	}
+	return a == b
+}
+
+// 各交易所的链名不统一, 如 TRX/TRC20/trc20usdt, ETH/ERC20/BSC/BEP20
+func withdrawChainFamily(chain string) string {
+	c := strings.ToUpper(chain)
+	if c == "TRX" || c == "TRON" || strings.Contains(c, "TRC20") {
+		return "TRON"
+	}
+	switch c {
+	case "ETH", "ERC20", "BSC", "BEP20", "ARBITRUM", "ARB", "ARBONE", "ARBEVM",
+		"OPTIMISM", "OP", "OPBNB", "MATIC", "POLYGON", "AVAXC", "AVAX_C", "AVAX-C",
+		"BASE", "LINEA", "SCROLL", "MANTLE", "ZKSYNCERA":
+		return "EVM"
+	}
+	if strings.Contains(c, "ERC20") || strings.Contains(c, "BEP20") {
+		return "EVM"
+	}
+	return ""
+}
+func isValidEvmAddress(addr string) bool {
+	if len(addr) != 42 || (addr[:2] != "0x" && addr[:2] != "0X") {
+		return false
+	}
+	hexPart := addr[2:]
+	if _, err := hex.DecodeString(hexPart); err != nil {
+		return false
+	}
+	lower := strings.ToLower(hexPart)
+	if hexPart == lower || hexPart == strings.ToUpper(hexPart) {
+		return true // 全小写或全大写不带校验
+	}
+	// EIP-55
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+	for i := range len(hexPart) {
+		ch := hexPart[i]
+		if ch >= '0' && ch <= '9' {
+			continue
+		}
+		upper := hash[i] >= '8'
+		if upper != (ch >= 'A' && ch <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// T开头, 解码后 0x41 + 20字节 + 4字节校验(双sha256)
+func isValidTronAddress(addr string) bool {
+	if len(addr) != 34 || addr[0] != 'T' {
+		return false
+	}
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := range len(addr) {
+		idx := strings.IndexByte(base58Alphabet, addr[i])
+		if idx < 0 {
+			return false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+	b := n.Bytes()
+	if len(b) != 25 || b[0] != 0x41 {
+		return false
+	}
+	h1 := sha256.Sum256(b[:21])
+	h2 := sha256.Sum256(h1[:])
+	return string(h2[:4]) == string(b[21:])
+}
+
+// 以太坊使用的Keccak-256(padding为0x01, 不同于标准SHA3-256)
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+var keccakRotc = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+var keccakPiln = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+
+func keccakF1600(st *[25]uint64) {
+	var bc [5]uint64
+	for r := range 24 {
+		for i := range 5 {
+			bc[i] = st[i] ^ st[i+5] ^ st[i+10] ^ st[i+15] ^ st[i+20]
+		}
+		for i := range 5 {
+			t := bc[(i+4)%5] ^ bits.RotateLeft64(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				st[j+i] ^= t
+			}
+		}
+		t := st[1]
+		for i := range 24 {
+			j := keccakPiln[i]
+			bc[0] = st[j]
+			st[j] = bits.RotateLeft64(t, keccakRotc[i])
+			t = bc[0]
+		}
+		for j := 0; j < 25; j += 5 {
+			for i := range 5 {
+				bc[i] = st[j+i]
+			}
+			for i := range 5 {
+				st[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+		st[0] ^= keccakRC[r]
+	}
+}
+func keccak256(data []byte) []byte {
+	const rate = 136
+	padLen := rate - len(data)%rate
+	buf := make([]byte, len(data)+padLen)
+	copy(buf, data)
+	buf[len(data)] = 0x01
+	buf[len(buf)-1] |= 0x80
+	var st [25]uint64
+	for off := 0; off < len(buf); off += rate {
+		for i := range rate / 8 {
+			st[i] ^= binary.LittleEndian.Uint64(buf[off+i*8:])
+		}
+		keccakF1600(&st)
+	}
+	out := make([]byte, 32)
+	for i := range 4 {
+		binary.LittleEndian.PutUint64(out[i*8:], st[i])
+	}
+	return out
+}
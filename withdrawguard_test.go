@@ -0,0 +1,145 @@
+package cex
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestKeccak256(t *testing.T) {
+	cases := []struct {
+		data []byte
+		want string
+	}{
+		{nil, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{[]byte("abc"), "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{make([]byte, 200), "e1bb54e1bc3af48d01e5dbfc81015c98152a574f6428c6948aa4837c9c0baad9"}, // 跨过136字节的rate
+	}
+	for _, c := range cases {
+		if got := hex.EncodeToString(keccak256(c.data)); got != c.want {
+			t.Errorf("keccak256(%q): want %s got %s", c.data, c.want, got)
+		}
+	}
+}
+func TestValidateWithdrawAddress(t *testing.T) {
+	cases := []struct {
+		chain string
+		addr  string
+		memo  string
+		ok    bool
+	}{
+		// EIP-55
+		{"ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "", true},
+		{"ERC20", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "", true},
+		{"BSC", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "", true}, // 全小写不校验
+		{"ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", "", false},
+		{"ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", "", false},
+		// TRON base58check
+		{"TRX", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", "", true},
+		{"TRC20", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6u", "", false},
+		{"TRX", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj60", "", false}, // 0不在base58中
+		// memo
+		{"XRP", "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", "", false},
+		{"XRP", "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", "123", true},
+		{"BTC", " bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", "", false},
+	}
+	for _, c := range cases {
+		err := ValidateWithdrawAddress("", c.chain, c.addr, c.memo)
+		if (err == nil) != c.ok {
+			t.Errorf("%s %s memo=%q: want ok=%v got %v", c.chain, c.addr, c.memo, c.ok, err)
+		}
+	}
+}
+func TestWithdrawGuardKrakenNetwork(t *testing.T) {
+	kk := &Kraken{}
+	network := kk.methodToNetwork("Tether USD (TRC20)")
+	if network != "TRC20" {
+		t.Fatalf("want TRC20 got %q", network)
+	}
+	if kk.methodToNetwork("Bitcoin") != "" {
+		t.Fatal("method without network should return empty")
+	}
+	g := NewWithdrawGuard(nil)
+	g.whitelist["USDT"] = []WithdrawAddress{
+		{Symbol: "USDT", Network: network, Addr: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", Memo: "my-tron"},
+	}
+	qty := decimal.NewFromInt(1)
+	for _, chain := range []string{"TRX", "TRC20", "tron", ""} {
+		if err := g.Check("USDT", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", "my-tron", chain, qty); err != nil {
+			t.Errorf("chain %q: %v", chain, err)
+		}
+	}
+	if err := g.Check("USDT", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "my-tron", "ETH", qty); err == nil {
+		t.Error("ETH should not match a TRC20 whitelist entry")
+	}
+}
+func TestWithdrawNetworkEqual(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"TRX", "TRC20", true},
+		{"ETH", "erc20", true},
+		{"BSC", "BEP20", true},
+		{"ARBITRUM", "ARBONE", true},
+		{"ETH", "BSC", false},
+		{"ERC20", "BEP20", false},
+		{"SOL", "TRX", false},
+	}
+	for _, c := range cases {
+		if got := withdrawNetworkEqual(c.a, c.b); got != c.want {
+			t.Errorf("%s %s: want %v got %v", c.a, c.b, c.want, got)
+		}
+	}
+}
+func TestWithdrawGuardDailyCap(t *testing.T) {
+	const addr = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	g := NewWithdrawGuard(nil)
+	if err := g.AddAddress(WithdrawAddress{Symbol: "USDT", Network: "TRX", Addr: addr}); err != nil {
+		t.Fatal(err)
+	}
+	g.SetDailyCap("USDT", decimal.NewFromInt(100))
+
+	day, err := g.reserve("USDT", addr, "", "TRX", decimal.NewFromInt(60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = g.reserve("USDT", addr, "", "TRX", decimal.NewFromInt(50)); err == nil {
+		t.Fatal("want daily cap error")
+	}
+	// 提现失败释放额度
+	g.release("USDT", decimal.NewFromInt(60), day)
+	if !g.used["USDT"].IsZero() {
+		t.Fatalf("want used 0 got %s", g.used["USDT"])
+	}
+	if _, err = g.reserve("USDT", addr, "", "TRX", decimal.NewFromInt(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 跨天后前一天的释放不影响当天
+	g.release("USDT", decimal.NewFromInt(100), "2000-01-01")
+	if !g.used["USDT"].Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("stale release changed used: %s", g.used["USDT"])
+	}
+	g.day = "2000-01-01" // 模拟上次提现在前一天
+	day, err = g.reserve("USDT", addr, "", "TRX", decimal.NewFromInt(30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.used["USDT"].Equal(decimal.NewFromInt(30)) {
+		t.Fatalf("want used reset to 30 got %s", g.used["USDT"])
+	}
+	g.release("USDT", decimal.NewFromInt(30), "2000-01-01")
+	if g.used["USDT"].IsNegative() || !g.used["USDT"].Equal(decimal.NewFromInt(30)) {
+		t.Fatalf("release after rollover: used %s", g.used["USDT"])
+	}
+	g.release("USDT", decimal.NewFromInt(30), day)
+	if !g.used["USDT"].IsZero() {
+		t.Fatalf("want used 0 got %s", g.used["USDT"])
+	}
+
+	if _, err = g.reserve("USDT", "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf", "", "TRX", decimal.NewFromInt(1)); err == nil {
+		t.Fatal("want whitelist error")
+	}
+}